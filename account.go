@@ -0,0 +1,178 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// AccountService handles communication with the account and usage related
+// methods of the Treasure Data API.
+type AccountService struct {
+	client *Client
+}
+
+// Account represents the Treasure Data account of the API key, including its
+// storage use and Hive compute quota
+type Account struct {
+	ID int64 `json:"id"`
+	// Plan is the numeric plan identifier
+	Plan int64 `json:"plan"`
+	// StorageSize is the account's total storage in bytes
+	StorageSize int64 `json:"storage_size"`
+	// GuaranteedCores and MaximumCores are the Hive compute quota
+	GuaranteedCores int64  `json:"guaranteed_cores"`
+	MaximumCores    int64  `json:"maximum_cores"`
+	CreatedAt       TDTime `json:"created_at"`
+}
+
+// UnmarshalJSON accepts numeric fields encoded as numbers or strings
+func (a *Account) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID              FlexibleInt64 `json:"id"`
+		Plan            FlexibleInt64 `json:"plan"`
+		StorageSize     FlexibleInt64 `json:"storage_size"`
+		GuaranteedCores FlexibleInt64 `json:"guaranteed_cores"`
+		MaximumCores    FlexibleInt64 `json:"maximum_cores"`
+		CreatedAt       TDTime        `json:"created_at"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	value := func(f FlexibleInt64) int64 {
+		if f.Value == nil {
+			return 0
+		}
+		return *f.Value
+	}
+	*a = Account{
+		ID:              value(raw.ID),
+		Plan:            value(raw.Plan),
+		StorageSize:     value(raw.StorageSize),
+		GuaranteedCores: value(raw.GuaranteedCores),
+		MaximumCores:    value(raw.MaximumCores),
+		CreatedAt:       raw.CreatedAt,
+	}
+	return nil
+}
+
+// accountShowResponse represents the response from the account show API
+type accountShowResponse struct {
+	Account Account `json:"account"`
+}
+
+// CoreUtilization is the Hive core usage history of the account
+type CoreUtilization struct {
+	From TDTime `json:"from"`
+	To   TDTime `json:"to"`
+	// Interval is the sampling interval of History in seconds
+	Interval int64 `json:"interval"`
+	// History holds one sample per interval as returned by the API
+	History []interface{} `json:"history"`
+}
+
+// DatabaseStorage is the storage used by one database
+type DatabaseStorage struct {
+	Database             string `json:"database"`
+	Tables               int    `json:"tables"`
+	Records              int64  `json:"records"`
+	EstimatedStorageSize int64  `json:"estimated_storage_size"`
+}
+
+// StorageUsage breaks the account's storage down by database
+type StorageUsage struct {
+	// StorageSize is the account total reported by the account API
+	StorageSize int64 `json:"storage_size"`
+	// Databases are sorted by estimated storage, largest first
+	Databases []DatabaseStorage `json:"databases"`
+}
+
+// Show returns the account of the API key
+func (s *AccountService) Show(ctx context.Context) (*Account, error) {
+	u := fmt.Sprintf("%s/account/show", apiVersion)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp accountShowResponse
+	_, err = s.client.Do(ctx, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp.Account, nil
+}
+
+// CoreUtilization returns the Hive core usage between from and to. Zero
+// times leave the range to the API's default.
+func (s *AccountService) CoreUtilization(ctx context.Context, from, to time.Time) (*CoreUtilization, error) {
+	params := url.Values{}
+	if !from.IsZero() {
+		params.Set("from", from.UTC().Format("2006-01-02 15:04:05 UTC"))
+	}
+	if !to.IsZero() {
+		params.Set("to", to.UTC().Format("2006-01-02 15:04:05 UTC"))
+	}
+	u := fmt.Sprintf("%s/account/core_utilization", apiVersion)
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var utilization CoreUtilization
+	_, err = s.client.Do(ctx, req, &utilization)
+	if err != nil {
+		return nil, err
+	}
+
+	return &utilization, nil
+}
+
+// storageUsageConcurrency bounds the table listings StorageUsage runs at once
+const storageUsageConcurrency = 4
+
+// StorageUsage sums the estimated storage and record counts of every table
+// per database. It lists the tables of each database, so it makes one request
+// per database, several at a time.
+func (s *AccountService) StorageUsage(ctx context.Context) (*StorageUsage, error) {
+	account, err := s.Show(ctx)
+	if err != nil {
+		return nil, err
+	}
+	databases, err := s.client.Databases.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := BatchMap(ctx, storageUsageConcurrency, databases, func(ctx context.Context, db Database) (DatabaseStorage, error) {
+		tables, err := s.client.Tables.List(ctx, db.Name)
+		if err != nil {
+			return DatabaseStorage{}, fmt.Errorf("listing tables of %s: %w", db.Name, err)
+		}
+		entry := DatabaseStorage{Database: db.Name, Tables: len(tables)}
+		for _, t := range tables {
+			entry.Records += t.Count
+			entry.EstimatedStorageSize += t.EstimatedStorageSize
+		}
+		return entry, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &StorageUsage{StorageSize: account.StorageSize, Databases: entries}
+	sort.SliceStable(usage.Databases, func(i, j int) bool {
+		return usage.Databases[i].EstimatedStorageSize > usage.Databases[j].EstimatedStorageSize
+	})
+	return usage, nil
+}
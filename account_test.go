@@ -0,0 +1,117 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestAccountService_Show(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/account/show", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{
+			"account": {
+				"id": 100,
+				"plan": "12",
+				"storage_size": 1073741824,
+				"guaranteed_cores": 4,
+				"maximum_cores": "8",
+				"created_at": "2020-06-11 10:25:10 UTC"
+			}
+		}`)
+	})
+
+	account, err := client.Account.Show(context.Background())
+	if err != nil {
+		t.Fatalf("Account.Show returned error: %v", err)
+	}
+
+	if account.ID != 100 || account.Plan != 12 || account.StorageSize != 1073741824 {
+		t.Errorf("Account.Show returned %+v", account)
+	}
+	if account.GuaranteedCores != 4 || account.MaximumCores != 8 {
+		t.Errorf("Account.Show cores = %d/%d, want 4/8", account.GuaranteedCores, account.MaximumCores)
+	}
+	want := time.Date(2020, 6, 11, 10, 25, 10, 0, time.UTC)
+	if !account.CreatedAt.Equal(want) {
+		t.Errorf("Account.Show CreatedAt = %v, want %v", account.CreatedAt, want)
+	}
+}
+
+func TestAccountService_CoreUtilization(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/account/core_utilization", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got := r.URL.Query().Get("from"); got != "2024-01-01 00:00:00 UTC" {
+			t.Errorf("from = %q", got)
+		}
+		if got := r.URL.Query().Get("to"); got != "2024-01-02 00:00:00 UTC" {
+			t.Errorf("to = %q", got)
+		}
+		fmt.Fprint(w, `{
+			"from": "2024-01-01 00:00:00 UTC",
+			"to": "2024-01-02 00:00:00 UTC",
+			"interval": 3600,
+			"history": [[1704067200, 2.5], [1704070800, 3.0]]
+		}`)
+	})
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	utilization, err := client.Account.CoreUtilization(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("Account.CoreUtilization returned error: %v", err)
+	}
+	if utilization.Interval != 3600 || len(utilization.History) != 2 {
+		t.Errorf("Account.CoreUtilization returned %+v", utilization)
+	}
+}
+
+func TestAccountService_StorageUsage(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/account/show", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"account": {"id": 1, "storage_size": 5000}}`)
+	})
+	mux.HandleFunc("/v3/database/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"databases": [{"name": "small"}, {"name": "large"}]}`)
+	})
+	mux.HandleFunc("/v3/table/list/small", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tables": [{"name": "t1", "count": 10, "estimated_storage_size": 100}]}`)
+	})
+	mux.HandleFunc("/v3/table/list/large", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tables": [
+			{"name": "t1", "count": 1000, "estimated_storage_size": 3000},
+			{"name": "t2", "count": 500, "estimated_storage_size": 1500}
+		]}`)
+	})
+
+	usage, err := client.Account.StorageUsage(context.Background())
+	if err != nil {
+		t.Fatalf("Account.StorageUsage returned error: %v", err)
+	}
+
+	if usage.StorageSize != 5000 {
+		t.Errorf("StorageSize = %d, want 5000", usage.StorageSize)
+	}
+	want := []DatabaseStorage{
+		{Database: "large", Tables: 2, Records: 1500, EstimatedStorageSize: 4500},
+		{Database: "small", Tables: 1, Records: 10, EstimatedStorageSize: 100},
+	}
+	if len(usage.Databases) != len(want) {
+		t.Fatalf("Databases = %+v, want %+v", usage.Databases, want)
+	}
+	for i := range want {
+		if usage.Databases[i] != want[i] {
+			t.Errorf("Databases[%d] = %+v, want %+v", i, usage.Databases[i], want[i])
+		}
+	}
+}
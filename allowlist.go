@@ -0,0 +1,246 @@
+package treasuredata
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ErrOperationNotAllowed is matched through errors.Is by requests refused
+// because they are not in the client's allowlist
+var ErrOperationNotAllowed = errors.New("operation not allowed")
+
+// OperationNotAllowedError reports a request refused by WithAllowlist before
+// it was sent
+type OperationNotAllowedError struct {
+	Service string
+	Method  string
+	Path    string
+}
+
+func (e *OperationNotAllowedError) Error() string {
+	return fmt.Sprintf("%s %s:%s is not allowed by the client's operation allowlist", e.Method, e.Service, e.Path)
+}
+
+// Is reports whether target is ErrOperationNotAllowed
+func (e *OperationNotAllowedError) Is(target error) bool {
+	return target == ErrOperationNotAllowed
+}
+
+// AllowRule permits the requests to a service whose method and path match
+type AllowRule struct {
+	// Service is MetricsServiceTD, MetricsServiceCDP or
+	// MetricsServiceWorkflow. Empty matches every service.
+	Service string
+	// Method is an HTTP method. Empty or "*" matches every method.
+	Method string
+	// Path is matched against the request path relative to the service base
+	// URL, e.g. "v3/job/show/12345". Each segment is a path.Match pattern,
+	// so "*" matches one segment, and a final "**" matches any remainder.
+	Path string
+}
+
+// String formats the rule as ParseAllowRule accepts it
+func (r AllowRule) String() string {
+	method := r.Method
+	if method == "" {
+		method = "*"
+	}
+	if r.Service == "" {
+		return method + " " + r.Path
+	}
+	return method + " " + r.Service + ":" + r.Path
+}
+
+// ParseAllowRule parses "[METHOD ][service:]path", e.g. "GET td:v3/job/show/*"
+// or "cdp:audiences/**"
+func ParseAllowRule(s string) (AllowRule, error) {
+	var rule AllowRule
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 1:
+		rule.Path = fields[0]
+	case 2:
+		rule.Method = strings.ToUpper(fields[0])
+		rule.Path = fields[1]
+	default:
+		return rule, fmt.Errorf("invalid allow rule %q: want \"[METHOD ][service:]path\"", s)
+	}
+
+	if service, p, ok := strings.Cut(rule.Path, ":"); ok {
+		switch service {
+		case MetricsServiceTD, MetricsServiceCDP, MetricsServiceWorkflow:
+		default:
+			return rule, fmt.Errorf("invalid allow rule %q: unknown service %q", s, service)
+		}
+		rule.Service, rule.Path = service, p
+	}
+	rule.Path = strings.TrimPrefix(rule.Path, "/")
+	if rule.Path == "" {
+		return rule, fmt.Errorf("invalid allow rule %q: empty path", s)
+	}
+	if _, err := path.Match(rule.Path, ""); err != nil {
+		return rule, fmt.Errorf("invalid allow rule %q: %v", s, err)
+	}
+	return rule, nil
+}
+
+// AllowlistPresets are named groups of rules for common restricted uses,
+// such as a CI job that only runs queries
+var AllowlistPresets = map[string][]AllowRule{
+	// Every GET request. A few read-only endpoints use POST and are excluded.
+	"read-only": {
+		{Method: http.MethodGet, Path: "**"},
+	},
+	// Issuing queries, following and killing the jobs, and reading results
+	"query": {
+		{Service: MetricsServiceTD, Method: http.MethodPost, Path: "v3/job/issue/**"},
+		{Service: MetricsServiceTD, Method: http.MethodPost, Path: "v3/job/kill/*"},
+		{Service: MetricsServiceTD, Method: http.MethodGet, Path: "v3/job/status/*"},
+		{Service: MetricsServiceTD, Method: http.MethodGet, Path: "v3/job/show/*"},
+		{Service: MetricsServiceTD, Method: http.MethodGet, Path: "v3/job/result/*"},
+	},
+	// Reading the status and results of existing jobs
+	"results": {
+		{Service: MetricsServiceTD, Method: http.MethodGet, Path: "v3/job/status/*"},
+		{Service: MetricsServiceTD, Method: http.MethodGet, Path: "v3/job/show/*"},
+		{Service: MetricsServiceTD, Method: http.MethodGet, Path: "v3/job/result/*"},
+	},
+	// Bulk import sessions and streaming imports, and the jobs they start
+	"import": {
+		{Service: MetricsServiceTD, Path: "v3/bulk_import/**"},
+		{Service: MetricsServiceTD, Method: http.MethodPut, Path: "v3/table/import/**"},
+		{Service: MetricsServiceTD, Method: http.MethodPut, Path: "v3/table/import_with_id/**"},
+		{Service: MetricsServiceTD, Method: http.MethodGet, Path: "v3/job/status/*"},
+		{Service: MetricsServiceTD, Method: http.MethodGet, Path: "v3/job/show/*"},
+	},
+	// Starting, retrying and killing workflow attempts, and reading workflows
+	"workflow-run": {
+		{Service: MetricsServiceWorkflow, Method: http.MethodGet, Path: "**"},
+		{Service: MetricsServiceWorkflow, Method: http.MethodPost, Path: "api/workflows/*/attempts"},
+		{Service: MetricsServiceWorkflow, Method: http.MethodPost, Path: "api/workflows/*/attempts/*/retry"},
+		{Service: MetricsServiceWorkflow, Method: http.MethodPost, Path: "api/workflows/*/attempts/*/kill"},
+	},
+}
+
+// ParseAllowlist builds rules from entries that are either preset names
+// (see AllowlistPresets) or rules in ParseAllowRule syntax
+func ParseAllowlist(entries []string) ([]AllowRule, error) {
+	var rules []AllowRule
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if preset, ok := AllowlistPresets[entry]; ok {
+			rules = append(rules, preset...)
+			continue
+		}
+		if !strings.ContainsAny(entry, " /:*") {
+			names := make([]string, 0, len(AllowlistPresets))
+			for name := range AllowlistPresets {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("unknown allowlist preset %q (presets: %s)", entry, strings.Join(names, ", "))
+		}
+		rule, err := ParseAllowRule(entry)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// WithAllowlist restricts the client to requests matching at least one rule.
+// Other requests fail with an *OperationNotAllowedError before they are sent,
+// which limits what leaked credentials used by the program can do. Calling
+// it without rules refuses every request. Passing it more than once only
+// allows requests that every allowlist allows. The Trino client is not
+// covered.
+func WithAllowlist(rules ...AllowRule) ClientOption {
+	return func(c *Client) error {
+		for _, rule := range rules {
+			if _, err := path.Match(rule.Path, ""); err != nil {
+				return fmt.Errorf("invalid allow rule %q: %v", rule, err)
+			}
+		}
+		c.allowlists = append(c.allowlists, append([]AllowRule{}, rules...))
+		return nil
+	}
+}
+
+// checkAllowlist returns an *OperationNotAllowedError if req matches none of
+// the rules of one of the client's allowlists
+func (c *Client) checkAllowlist(req *http.Request) error {
+	if len(c.allowlists) == 0 {
+		return nil
+	}
+
+	service, p := c.servicePath(req)
+	for _, rules := range c.allowlists {
+		if !allowedBy(rules, service, req.Method, p) {
+			return &OperationNotAllowedError{Service: service, Method: req.Method, Path: p}
+		}
+	}
+	return nil
+}
+
+// allowedBy reports whether a request matches at least one rule
+func allowedBy(rules []AllowRule, service, method, p string) bool {
+	for _, rule := range rules {
+		if rule.Service != "" && rule.Service != service {
+			continue
+		}
+		if rule.Method != "" && rule.Method != "*" && !strings.EqualFold(rule.Method, method) {
+			continue
+		}
+		if matchAllowPath(rule.Path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// servicePath returns the service of req and its path relative to the
+// service base URL
+func (c *Client) servicePath(req *http.Request) (string, string) {
+	service := c.requestInfo(req).Service
+	base := map[string]string{
+		MetricsServiceTD:       pathOf(c.BaseURL),
+		MetricsServiceCDP:      pathOf(c.CDPURL),
+		MetricsServiceWorkflow: pathOf(c.WorkflowURL),
+	}[service]
+	return service, strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, base), "/")
+}
+
+// pathOf returns the path of u, or "" for a nil URL
+func pathOf(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	return u.Path
+}
+
+// matchAllowPath matches p segment by segment against pattern
+func matchAllowPath(pattern, p string) bool {
+	patterns := strings.Split(pattern, "/")
+	segments := strings.Split(p, "/")
+	for i, seg := range patterns {
+		if seg == "**" && i == len(patterns)-1 {
+			return true
+		}
+		if i >= len(segments) {
+			return false
+		}
+		if ok, _ := path.Match(seg, segments[i]); !ok {
+			return false
+		}
+	}
+	return len(segments) == len(patterns)
+}
@@ -0,0 +1,105 @@
+package treasuredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestWithAllowlist(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/issue/trino/db", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"job_id": "123"}`)
+	})
+	mux.HandleFunc("/v3/table/delete/db/t", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("a request outside the allowlist was sent")
+	})
+
+	if err := WithAllowlist(AllowlistPresets["query"]...)(client); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Queries.Issue(context.Background(), QueryTypeTrino, "db", &IssueQueryOptions{Query: "SELECT 1"}); err != nil {
+		t.Errorf("allowed query failed: %v", err)
+	}
+
+	err := client.Tables.Delete(context.Background(), "db", "t")
+	if !errors.Is(err, ErrOperationNotAllowed) {
+		t.Fatalf("err = %v, want ErrOperationNotAllowed", err)
+	}
+	var denied *OperationNotAllowedError
+	if !errors.As(err, &denied) || denied.Method != "POST" || denied.Service != MetricsServiceTD || denied.Path != "v3/table/delete/db/t" {
+		t.Errorf("denied = %+v", denied)
+	}
+}
+
+func TestWithAllowlist_Intersects(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/show/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"job_id": "1"}`)
+	})
+
+	// The second list cannot widen the first one
+	query, _ := ParseAllowlist([]string{"GET td:v3/job/**"})
+	wide, _ := ParseAllowlist([]string{"td:**"})
+	for _, rules := range [][]AllowRule{query, wide} {
+		if err := WithAllowlist(rules...)(client); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := client.Jobs.Get(context.Background(), "1"); err != nil {
+		t.Errorf("request allowed by both lists failed: %v", err)
+	}
+	if err := client.Jobs.Kill(context.Background(), "1"); !errors.Is(err, ErrOperationNotAllowed) {
+		t.Errorf("err = %v, want ErrOperationNotAllowed for a request only the second list allows", err)
+	}
+}
+
+func TestParseAllowlist(t *testing.T) {
+	rules, err := ParseAllowlist([]string{"results", "get cdp:audiences/**", "workflow:api/projects"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != len(AllowlistPresets["results"])+2 {
+		t.Fatalf("rules = %v", rules)
+	}
+	if got := rules[len(rules)-2]; got != (AllowRule{Service: MetricsServiceCDP, Method: "GET", Path: "audiences/**"}) {
+		t.Errorf("rule = %+v", got)
+	}
+	if got := rules[len(rules)-1].String(); got != "* workflow:api/projects" {
+		t.Errorf("String() = %q", got)
+	}
+
+	for _, bad := range []string{"everything", "GET ftp:files/*", "GET td:v3/[", "GET POST v3/job"} {
+		if _, err := ParseAllowlist([]string{bad}); err == nil {
+			t.Errorf("ParseAllowlist(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestMatchAllowPath(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"v3/job/show/*", "v3/job/show/123", true},
+		{"v3/job/show/*", "v3/job/show/123/extra", false},
+		{"v3/job/show/*", "v3/job/show", false},
+		{"v3/job/issue/**", "v3/job/issue/trino/db", true},
+		{"v3/job/issue/**", "v3/job/issue", true},
+		{"**", "api/projects", true},
+		{"v3/database/list", "v3/database/list", true},
+	}
+	for _, tt := range tests {
+		if got := matchAllowPath(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchAllowPath(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
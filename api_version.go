@@ -0,0 +1,133 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// APIVersion selects between the legacy and entity (JSON:API) paths of a
+// service
+type APIVersion string
+
+const (
+	// APIVersionLegacy uses the original paths, e.g. "audiences/{id}/segments"
+	APIVersionLegacy APIVersion = "legacy"
+	// APIVersionEntity uses the JSON:API paths under "entities/"
+	APIVersionEntity APIVersion = "entity"
+)
+
+// APIVersionPolicy pins services to legacy or entity paths. Services that
+// are not listed keep the SDK's default mix.
+type APIVersionPolicy map[ServiceKind]APIVersion
+
+// WithAPIVersionPolicy pins services to legacy or entity endpoints for the
+// methods that can be served by either:
+//
+//   - CDP pinned to APIVersionEntity: GetSegment and DeleteSegment use
+//     entities/segments/{id}.
+//   - CDP pinned to APIVersionLegacy: ListParentSegments and
+//     GetParentSegment use audiences and audiences/{id}.
+//
+// Methods that exist in only one form, such as journeys (entity only) or
+// segment queries (legacy only), are not affected, and methods named
+// *Entity* always use entity paths. The Workflow API only has legacy paths,
+// so it can be pinned to APIVersionLegacy but not APIVersionEntity.
+func WithAPIVersionPolicy(policy APIVersionPolicy) ClientOption {
+	return func(c *Client) error {
+		for kind, version := range policy {
+			if version != APIVersionLegacy && version != APIVersionEntity {
+				return fmt.Errorf("invalid API version %q for %s", version, kind)
+			}
+			switch kind {
+			case ServiceCDP:
+			case ServiceWorkflow:
+				if version == APIVersionEntity {
+					return fmt.Errorf("%s has no entity endpoints", kind)
+				}
+			case ServiceAPI, ServiceTrino:
+				return fmt.Errorf("%s has a single API version", kind)
+			default:
+				return fmt.Errorf("unknown service kind %q", kind)
+			}
+
+			if c.apiVersions == nil {
+				c.apiVersions = APIVersionPolicy{}
+			}
+			c.apiVersions[kind] = version
+		}
+		return nil
+	}
+}
+
+// pinnedTo reports whether service was pinned to version with
+// WithAPIVersionPolicy
+func (c *Client) pinnedTo(service ServiceKind, version APIVersion) bool {
+	return c.apiVersions[service] == version
+}
+
+// getEntitySegmentAsSegment fetches a segment from entities/segments/{id} and
+// converts the JSON:API resource to a CDPSegment
+func (s *CDPService) getEntitySegmentAsSegment(ctx context.Context, segmentID string) (*CDPSegment, error) {
+	req, err := s.client.NewCDPJSONAPIRequest("GET", fmt.Sprintf("entities/segments/%s", segmentID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Data struct {
+			ID         string          `json:"id"`
+			Attributes json.RawMessage `json:"attributes"`
+		} `json:"data"`
+	}
+	if _, err := s.client.Do(ctx, req, &response); err != nil {
+		return nil, err
+	}
+
+	var segment CDPSegment
+	if len(response.Data.Attributes) > 0 {
+		if err := json.Unmarshal(response.Data.Attributes, &segment); err != nil {
+			return nil, fmt.Errorf("decoding segment %s attributes: %w", segmentID, err)
+		}
+	}
+	segment.ID = response.Data.ID
+	return &segment, nil
+}
+
+// audienceAsParentSegment converts a legacy audience to the entity form
+func audienceAsParentSegment(a CDPAudience) CDPParentSegment {
+	description := a.Description
+	return CDPParentSegment{
+		ID:   a.ID,
+		Type: "parent-segment",
+		Attributes: &CDPParentSegmentAttributes{
+			Name:        a.Name,
+			Description: &description,
+			CreatedAt:   a.CreatedAt.Time,
+			UpdatedAt:   a.UpdatedAt.Time,
+		},
+	}
+}
+
+// listParentSegmentsLegacy lists parent segments through the audiences path
+func (s *CDPService) listParentSegmentsLegacy(ctx context.Context) (*CDPParentSegmentListResponse, error) {
+	audiences, err := s.ListAudiences(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CDPParentSegmentListResponse{Data: []CDPParentSegment{}}
+	for _, a := range audiences.Audiences {
+		response.Data = append(response.Data, audienceAsParentSegment(a))
+	}
+	return response, nil
+}
+
+// getParentSegmentLegacy fetches a parent segment through audiences/{id}
+func (s *CDPService) getParentSegmentLegacy(ctx context.Context, parentSegmentID string) (*CDPParentSegmentResponse, error) {
+	audience, err := s.GetAudience(ctx, parentSegmentID)
+	if err != nil {
+		return nil, err
+	}
+	return &CDPParentSegmentResponse{Data: audienceAsParentSegment(*audience)}, nil
+}
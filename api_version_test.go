@@ -0,0 +1,101 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestWithAPIVersionPolicy_Validation(t *testing.T) {
+	tests := []struct {
+		policy  APIVersionPolicy
+		wantErr bool
+	}{
+		{APIVersionPolicy{ServiceCDP: APIVersionEntity}, false},
+		{APIVersionPolicy{ServiceCDP: APIVersionLegacy, ServiceWorkflow: APIVersionLegacy}, false},
+		{APIVersionPolicy{ServiceWorkflow: APIVersionEntity}, true},
+		{APIVersionPolicy{ServiceAPI: APIVersionLegacy}, true},
+		{APIVersionPolicy{ServiceCDP: "v5"}, true},
+		{APIVersionPolicy{"api-unknown": APIVersionLegacy}, true},
+	}
+	for _, tt := range tests {
+		_, err := NewClient("1/abc", WithAPIVersionPolicy(tt.policy))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("WithAPIVersionPolicy(%v) error = %v, wantErr %v", tt.policy, err, tt.wantErr)
+		}
+	}
+}
+
+func TestAPIVersionPolicy_EntitySegments(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	client.CDPURL = client.BaseURL
+	if err := WithAPIVersionPolicy(APIVersionPolicy{ServiceCDP: APIVersionEntity})(client); err != nil {
+		t.Fatal(err)
+	}
+
+	mux.HandleFunc("/entities/segments/42", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"data": {"id": "42", "type": "segment-batch", "attributes": {"audienceId": "7", "name": "VIP", "population": 1200}}}`)
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+	mux.HandleFunc("/audiences/7/segments/42", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("legacy path used with the CDP entity policy")
+	})
+
+	segment, err := client.CDP.GetSegment(context.Background(), "7", "42")
+	if err != nil {
+		t.Fatalf("GetSegment returned error: %v", err)
+	}
+	if segment.ID != "42" || segment.AudienceID != "7" || segment.Name != "VIP" || segment.Population != 1200 {
+		t.Errorf("segment = %+v", segment)
+	}
+
+	if err := client.CDP.DeleteSegment(context.Background(), "7", "42"); err != nil {
+		t.Fatalf("DeleteSegment returned error: %v", err)
+	}
+}
+
+func TestAPIVersionPolicy_LegacyParentSegments(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	client.CDPURL = client.BaseURL
+	if err := WithAPIVersionPolicy(APIVersionPolicy{ServiceCDP: APIVersionLegacy})(client); err != nil {
+		t.Fatal(err)
+	}
+
+	mux.HandleFunc("/audiences", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"id": "7", "name": "Customers", "description": "All customers"}]`)
+	})
+	mux.HandleFunc("/audiences/7", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id": "7", "name": "Customers"}`)
+	})
+	mux.HandleFunc("/entities/parent_segments", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("entity path used with the CDP legacy policy")
+	})
+
+	list, err := client.CDP.ListParentSegments(context.Background())
+	if err != nil {
+		t.Fatalf("ListParentSegments returned error: %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].ID != "7" || list.Data[0].Attributes.Name != "Customers" ||
+		*list.Data[0].Attributes.Description != "All customers" {
+		t.Errorf("list = %+v", list)
+	}
+
+	parent, err := client.CDP.GetParentSegment(context.Background(), "7")
+	if err != nil {
+		t.Fatalf("GetParentSegment returned error: %v", err)
+	}
+	if parent.Data.ID != "7" || parent.Data.Attributes.Name != "Customers" {
+		t.Errorf("parent = %+v", parent.Data)
+	}
+}
@@ -0,0 +1,48 @@
+package treasuredata
+
+import (
+	"net/http"
+	"strings"
+)
+
+// sourceHeader carries the application identity set with WithAppName on API
+// requests, so jobs can be attributed to the tool that issued them
+const sourceHeader = "X-TD-Source"
+
+// WithAppName identifies the application using the client. "name/version"
+// is appended to the User-Agent of every request and sent as the X-TD-Source
+// header, so API traffic and the jobs it issues can be attributed to the
+// tool. Pass the same values in TDTrinoClientConfig for Trino sessions.
+func WithAppName(name, version string) ClientOption {
+	return func(c *Client) error {
+		if strings.TrimSpace(name) == "" {
+			return NewValidationError("name", name, "application name cannot be empty")
+		}
+		if strings.ContainsAny(name+version, " /\t\r\n") {
+			return NewValidationError("name", name+"/"+version, "application name and version cannot contain spaces or slashes")
+		}
+		c.appName = appIdentity(name, version)
+		return nil
+	}
+}
+
+// appIdentity returns "name/version", or name without a version
+func appIdentity(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + "/" + version
+}
+
+// setAppHeaders adds the application identity to req
+func (c *Client) setAppHeaders(req *http.Request) {
+	if c.appName == "" {
+		return
+	}
+
+	req.Header = req.Header.Clone()
+	if ua := req.Header.Get("User-Agent"); !strings.HasSuffix(ua, c.appName) {
+		req.Header.Set("User-Agent", strings.TrimSpace(ua+" "+c.appName))
+	}
+	req.Header.Set(sourceHeader, c.appName)
+}
@@ -0,0 +1,63 @@
+package treasuredata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithAppName(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	if err := WithAppName("segment-sync", "2.1.0")(client); err != nil {
+		t.Fatal(err)
+	}
+	WithUserAgent("platform-sdk/1.0")(client)
+
+	var ua, source string
+	mux.HandleFunc("/v3/job/issue/trino/db", func(w http.ResponseWriter, r *http.Request) {
+		ua, source = r.Header.Get("User-Agent"), r.Header.Get(sourceHeader)
+		w.Write([]byte(`{"job_id": "1"}`))
+	})
+
+	if _, err := client.Queries.Issue(context.Background(), QueryTypeTrino, "db", &IssueQueryOptions{Query: "SELECT 1"}); err != nil {
+		t.Fatal(err)
+	}
+	if ua != "platform-sdk/1.0 segment-sync/2.1.0" {
+		t.Errorf("User-Agent = %q", ua)
+	}
+	if source != "segment-sync/2.1.0" {
+		t.Errorf("%s = %q", sourceHeader, source)
+	}
+}
+
+func TestWithAppName_Validation(t *testing.T) {
+	for _, tt := range []struct{ name, version string }{
+		{"", "1.0"},
+		{"my tool", "1.0"},
+		{"tool", "1.0/beta"},
+	} {
+		if _, err := NewClient("test-api-key", WithAppName(tt.name, tt.version)); err == nil {
+			t.Errorf("WithAppName(%q, %q) succeeded", tt.name, tt.version)
+		}
+	}
+}
+
+func TestTrinoTransport_ClientInfo(t *testing.T) {
+	var info string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info = r.Header.Get("X-Trino-Client-Info")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &trinoTransport{apiKey: "1/abc", clientInfo: appIdentity("segment-sync", "")}}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if info != "segment-sync" {
+		t.Errorf("X-Trino-Client-Info = %q", info)
+	}
+}
@@ -0,0 +1,116 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Task is a unit of work run by Batch
+type Task func(ctx context.Context) error
+
+// TaskError is the failure of one task in a batch
+type TaskError struct {
+	// Index is the position of the task or item in the batch
+	Index int
+	Err   error
+}
+
+func (e TaskError) Error() string {
+	return fmt.Sprintf("task %d: %v", e.Index, e.Err)
+}
+
+func (e TaskError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError collects the failures of a batch, ordered by task index
+type BatchError struct {
+	Errors []TaskError
+	// Total is the number of tasks in the batch
+	Total int
+}
+
+func (e *BatchError) Error() string {
+	if len(e.Errors) == 1 {
+		return fmt.Sprintf("1 of %d tasks failed: %v", e.Total, e.Errors[0])
+	}
+	msgs := make([]string, 0, 3)
+	for i, err := range e.Errors {
+		if i == 3 {
+			msgs = append(msgs, fmt.Sprintf("and %d more", len(e.Errors)-3))
+			break
+		}
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%d of %d tasks failed: %s", len(e.Errors), e.Total, strings.Join(msgs, "; "))
+}
+
+// Unwrap lets errors.Is and errors.As match any task's error
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Batch runs tasks with at most concurrency running at once and waits for
+// all of them. A failing task does not stop the others; the failures are
+// returned together as a *BatchError. Tasks not yet started when ctx is done
+// fail with ctx.Err(). concurrency below 1 runs the tasks one at a time.
+func Batch(ctx context.Context, concurrency int, tasks ...Task) error {
+	_, err := BatchMap(ctx, concurrency, tasks, func(ctx context.Context, task Task) (struct{}, error) {
+		return struct{}{}, task(ctx)
+	})
+	return err
+}
+
+// BatchMap calls fn for every item with bounded concurrency, as Batch does,
+// and returns the results in item order. Results of failed items are zero
+// values; the failures are reported in a *BatchError.
+func BatchMap[T, R any](ctx context.Context, concurrency int, items []T, fn func(ctx context.Context, item T) (R, error)) ([]R, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]R, len(items))
+	errs := make([]error, len(items))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i, item := range items {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			<-sem
+			errs[i] = err
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, item T) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i], errs[i] = fn(ctx, item)
+		}(i, item)
+	}
+	wg.Wait()
+
+	batchErr := &BatchError{Total: len(items)}
+	for i, err := range errs {
+		if err != nil {
+			batchErr.Errors = append(batchErr.Errors, TaskError{Index: i, Err: err})
+		}
+	}
+	if len(batchErr.Errors) > 0 {
+		return results, batchErr
+	}
+	return results, nil
+}
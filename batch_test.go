@@ -0,0 +1,92 @@
+package treasuredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBatch_BoundsConcurrency(t *testing.T) {
+	var running, peak int32
+	tasks := make([]Task, 20)
+	for i := range tasks {
+		tasks[i] = func(ctx context.Context) error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return nil
+		}
+	}
+
+	if err := Batch(context.Background(), 3, tasks...); err != nil {
+		t.Fatalf("Batch returned error: %v", err)
+	}
+	if peak > 3 {
+		t.Errorf("peak concurrency = %d, want at most 3", peak)
+	}
+}
+
+func TestBatchMap_AggregatesErrors(t *testing.T) {
+	errOdd := errors.New("odd")
+	items := []int{0, 1, 2, 3, 4}
+
+	results, err := BatchMap(context.Background(), 2, items, func(ctx context.Context, n int) (string, error) {
+		if n%2 == 1 {
+			return "", fmt.Errorf("item %d: %w", n, errOdd)
+		}
+		return fmt.Sprint(n * 10), nil
+	})
+
+	want := []string{"0", "", "20", "", "40"}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("results[%d] = %q, want %q", i, results[i], want[i])
+		}
+	}
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected *BatchError, got %v", err)
+	}
+	if batchErr.Total != 5 || len(batchErr.Errors) != 2 || batchErr.Errors[0].Index != 1 || batchErr.Errors[1].Index != 3 {
+		t.Errorf("BatchError = %+v", batchErr)
+	}
+	if !errors.Is(err, errOdd) {
+		t.Error("errors.Is should match a task's error")
+	}
+	if got := err.Error(); got != "2 of 5 tasks failed: task 1: item 1: odd; task 3: item 3: odd" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func TestBatch_CancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var started int32
+	tasks := make([]Task, 10)
+	for i := range tasks {
+		tasks[i] = func(ctx context.Context) error {
+			if atomic.AddInt32(&started, 1) == 1 {
+				cancel()
+			}
+			return nil
+		}
+	}
+
+	err := Batch(ctx, 1, tasks...)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if started != 1 {
+		t.Errorf("started %d tasks after cancellation, want 1", started)
+	}
+}
@@ -0,0 +1,193 @@
+package treasuredata
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SQLIdentifier is a BindParams value written as a quoted, possibly
+// qualified, name instead of a string literal
+type SQLIdentifier []string
+
+// Ident returns a BindParams value for a database, table or column name:
+// Ident("sales", "events") is bound as "sales"."events"
+func Ident(parts ...string) SQLIdentifier {
+	return SQLIdentifier(parts)
+}
+
+// BindParams replaces :name placeholders in sql with params, quoted for their
+// type:
+//
+//   - strings become string literals, with embedded quotes doubled
+//   - integers, finite floats and bools are written as is, with negative
+//     numbers parenthesized so that "10-:n" cannot become a -- comment
+//   - time.Time becomes Unix seconds, as TD_TIME_RANGE and the time column
+//     expect; the zero time becomes NULL, an open TD_TIME_RANGE bound
+//   - Ident values become quoted identifiers ("db"."table")
+//   - slices become comma-separated lists for IN (:ids)
+//   - nil becomes NULL
+//
+// Placeholders inside string literals, quoted identifiers and comments are
+// left alone, as is "::". A placeholder without a param, or a param of an
+// unsupported type, is an error.
+//
+//	sql, err := td.BindParams(
+//		`SELECT * FROM :table WHERE country IN (:countries) AND TD_TIME_RANGE(time, :since, :until)`,
+//		map[string]any{"table": td.Ident("sales", "events"), "countries": []string{"JP", "US"},
+//			"since": since, "until": time.Time{}})
+//
+// BindParams quotes for Trino; use BindParamsFor for Hive queries.
+func BindParams(sql string, params map[string]any) (string, error) {
+	return BindParamsFor(QueryTypeTrino, sql, params)
+}
+
+// BindParamsFor is BindParams quoting for the engine the query runs on. For
+// QueryTypeHive, strings are escaped with backslashes, identifiers are
+// quoted with backticks, and backslash escapes are honored when skipping
+// string literals in sql.
+func BindParamsFor(queryType QueryType, sql string, params map[string]any) (string, error) {
+	hive := queryType == QueryTypeHive
+	var sb strings.Builder
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := quotedEnd(sql, i, c, hive && c != '`')
+			sb.WriteString(sql[i:end])
+			i = end
+		case strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				end = len(sql) - i
+			}
+			sb.WriteString(sql[i : i+end])
+			i += end
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				end = len(sql) - i
+			} else {
+				end += 4
+			}
+			sb.WriteString(sql[i : i+end])
+			i += end
+		case strings.HasPrefix(sql[i:], "::"):
+			sb.WriteString("::")
+			i += 2
+		case c == ':' && i+1 < len(sql) && isParamStart(sql[i+1]):
+			end := i + 2
+			for end < len(sql) && isParamChar(sql[end]) {
+				end++
+			}
+			name := sql[i+1 : end]
+			value, ok := params[name]
+			if !ok {
+				return "", fmt.Errorf("no value for parameter :%s", name)
+			}
+			bound, err := bindValue(queryType, value)
+			if err != nil {
+				return "", fmt.Errorf("parameter :%s: %w", name, err)
+			}
+			sb.WriteString(bound)
+			i = end
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	return sb.String(), nil
+}
+
+// quotedEnd returns the index just past the quoted section starting at
+// start, where a doubled quote is an escaped one, as is any character after
+// a backslash when backslashEscapes is set
+func quotedEnd(sql string, start int, quote byte, backslashEscapes bool) int {
+	for i := start + 1; i < len(sql); i++ {
+		if backslashEscapes && sql[i] == '\\' {
+			i++
+			continue
+		}
+		if sql[i] != quote {
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(sql)
+}
+
+func isParamStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isParamChar(c byte) bool {
+	return isParamStart(c) || (c >= '0' && c <= '9')
+}
+
+func bindValue(queryType QueryType, value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "NULL", nil
+	case string:
+		return EscapeStringLiteralFor(queryType, v), nil
+	case bool:
+		if v {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case time.Time:
+		return TimeRangeBound(v), nil
+	case *time.Time:
+		if v == nil {
+			return "NULL", nil
+		}
+		return TimeRangeBound(*v), nil
+	case SQLIdentifier:
+		if len(v) == 0 {
+			return "", fmt.Errorf("empty identifier")
+		}
+		for _, part := range v {
+			if part == "" {
+				return "", fmt.Errorf("empty identifier part in %q", []string(v))
+			}
+		}
+		return QualifiedNameFor(queryType, v...), nil
+	case time.Duration:
+		return "", fmt.Errorf("unsupported type time.Duration; bind a number of seconds")
+	case []byte:
+		return "", fmt.Errorf("unsupported type []byte")
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return NumberLiteral(value)
+	case reflect.Slice, reflect.Array:
+		if rv.Len() == 0 {
+			return "", fmt.Errorf("empty list")
+		}
+		items := make([]string, rv.Len())
+		for i := range items {
+			item := rv.Index(i).Interface()
+			if _, ok := item.(SQLIdentifier); !ok {
+				if k := reflect.ValueOf(item).Kind(); k == reflect.Slice || k == reflect.Array {
+					return "", fmt.Errorf("nested list")
+				}
+			}
+			bound, err := bindValue(queryType, item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = bound
+		}
+		return strings.Join(items, ", "), nil
+	}
+	return "", fmt.Errorf("unsupported type %T", value)
+}
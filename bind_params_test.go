@@ -0,0 +1,122 @@
+package treasuredata
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBindParams(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sql, err := BindParams(`SELECT * FROM :table
+WHERE country IN (:countries) AND name = :name -- :ignored
+  AND TD_TIME_RANGE(time, :since, :until) AND score > :score
+  AND note <> ':literal' AND "col:umn" IS NOT NULL /* :comment */ AND flag = :flag
+  AND CAST(x AS varchar)::text IS NULL AND y = :y AND z = :z`, map[string]any{
+		"table":     Ident("sales", `ev"ents`),
+		"countries": []string{"JP", "US"},
+		"name":      "O'Brien",
+		"since":     since,
+		"until":     time.Time{},
+		"score":     1.5,
+		"flag":      true,
+		"y":         int64(7),
+		"z":         float32(0.1),
+	})
+	if err != nil {
+		t.Fatalf("BindParams returned error: %v", err)
+	}
+	want := `SELECT * FROM "sales"."ev""ents"
+WHERE country IN ('JP', 'US') AND name = 'O''Brien' -- :ignored
+  AND TD_TIME_RANGE(time, 1704067200, NULL) AND score > 1.5
+  AND note <> ':literal' AND "col:umn" IS NOT NULL /* :comment */ AND flag = TRUE
+  AND CAST(x AS varchar)::text IS NULL AND y = 7 AND z = 0.1`
+	if sql != want {
+		t.Errorf("BindParams =\n%s\nwant\n%s", sql, want)
+	}
+}
+
+func TestBindParams_NegativeNumbers(t *testing.T) {
+	sql, err := BindParams("SELECT 10-:n AS x, secret FROM t WHERE id = :id\nAND 1=1 AND y > :f",
+		map[string]any{"n": -5, "id": 1, "f": -1.5})
+	if err != nil {
+		t.Fatalf("BindParams returned error: %v", err)
+	}
+	want := "SELECT 10-(-5) AS x, secret FROM t WHERE id = 1\nAND 1=1 AND y > (-1.5)"
+	if sql != want {
+		t.Errorf("BindParams = %q, want %q", sql, want)
+	}
+	if sql, _ := BindParams("SELECT :t", map[string]any{"t": time.Unix(-60, 0)}); sql != "SELECT (-60)" {
+		t.Errorf("BindParams for a time before 1970 = %q", sql)
+	}
+}
+
+func TestBindParamsFor_Hive(t *testing.T) {
+	params := map[string]any{"a": `\`, "b": " OR 1=1 --", "table": Ident("sales", "events")}
+	query := `SELECT * FROM :table WHERE a = :a AND b = :b AND c = 'it\'s :a'`
+
+	sql, err := BindParamsFor(QueryTypeHive, query, params)
+	if err != nil {
+		t.Fatalf("BindParamsFor returned error: %v", err)
+	}
+	// The backslash is escaped so that the literal ends where it should, and
+	// the placeholder in the backslash-escaped literal is left alone
+	want := "SELECT * FROM `sales`.`events` WHERE a = '\\\\' AND b = ' OR 1=1 --' AND c = 'it\\'s :a'"
+	if sql != want {
+		t.Errorf("BindParamsFor(hive) =\n%s\nwant\n%s", sql, want)
+	}
+
+	// Trino has no backslash escapes
+	sql, err = BindParamsFor(QueryTypeTrino, "SELECT :a, :b", params)
+	if err != nil || sql != `SELECT '\', ' OR 1=1 --'` {
+		t.Errorf("BindParamsFor(trino) = %q, %v", sql, err)
+	}
+}
+
+func TestBindParams_Errors(t *testing.T) {
+	tests := map[string]map[string]any{
+		"SELECT :missing":  {},
+		"SELECT :nan":      {"nan": math.NaN()},
+		"SELECT :empty":    {"empty": []int{}},
+		"SELECT :ident":    {"ident": Ident("db", "")},
+		"SELECT :duration": {"duration": time.Minute},
+		"SELECT :map":      {"map": map[string]int{}},
+		"SELECT :nested":   {"nested": [][]int{{1}}},
+	}
+	for sql, params := range tests {
+		if got, err := BindParams(sql, params); err == nil {
+			t.Errorf("BindParams(%q) = %q, want error", sql, got)
+		}
+	}
+
+	// Lists of identifiers and nil are fine
+	sql, err := BindParams("SELECT :cols FROM t WHERE x = :x", map[string]any{"cols": []SQLIdentifier{Ident("a"), Ident("b")}, "x": nil})
+	if err != nil || sql != `SELECT "a", "b" FROM t WHERE x = NULL` {
+		t.Errorf("BindParams = %q, %v", sql, err)
+	}
+}
+
+func FuzzBindParams(f *testing.F) {
+	for _, seed := range []string{
+		"SELECT * FROM :table WHERE id IN (:ids)",
+		"SELECT ':literal', \"col:umn\", `x:y` -- :comment",
+		"SELECT 1 /* :comment */ WHERE x::text = :name",
+		"SELECT 'unterminated :name",
+		"/* unterminated :name",
+		"SELECT ''':name'''",
+	} {
+		f.Add(seed)
+	}
+	params := map[string]any{"table": Ident("sales", "events"), "ids": []int{1, 2}, "name": "it's"}
+	f.Fuzz(func(t *testing.T, sql string) {
+		// Without params, a query either has a placeholder outside quotes and
+		// comments, or binds to itself
+		if bound, err := BindParams(sql, nil); err == nil && bound != sql {
+			t.Fatalf("BindParams(%q, nil) = %q", sql, bound)
+		}
+		if _, err := BindParams(sql, params); err != nil && !strings.Contains(err.Error(), "no value for parameter") {
+			t.Fatalf("BindParams(%q) returned unexpected error: %v", sql, err)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package treasuredata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestNewRequest_MsgpackBody(t *testing.T) {
+	client, err := NewClient("test-key")
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	req, err := client.NewRequest("PUT", "v3/table/import/db/tbl/msgpack", &MsgpackBody{Value: map[string]interface{}{"a": 1}})
+	if err != nil {
+		t.Fatalf("NewRequest returned error: %v", err)
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "application/x-msgpack" {
+		t.Errorf("Content-Type = %s, want application/x-msgpack", ct)
+	}
+	body, _ := io.ReadAll(req.Body)
+	if want := []byte{0x81, 0xa1, 'a', 0x01}; !bytes.Equal(body, want) {
+		t.Errorf("body = %x, want %x", body, want)
+	}
+
+	if _, err := client.NewRequest("POST", "v3/job/issue/trino/db", MsgpackBody{Value: "x"}); err == nil {
+		t.Error("Expected error for an endpoint that does not accept MessagePack")
+	}
+
+	req, err = client.NewRequest("POST", "v3/job/issue/trino/db", map[string]string{"query": "a<b"})
+	if err != nil {
+		t.Fatalf("NewRequest returned error: %v", err)
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %s, want application/json", ct)
+	}
+	body, _ = io.ReadAll(req.Body)
+	if string(body) != "{\"query\":\"a<b\"}\n" {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestMsgpackBody_Records(t *testing.T) {
+	records := []map[string]interface{}{{"id": 1}, {"id": 2}}
+	data, err := MsgpackBody{Records: records, Gzip: true}.encode()
+	if err != nil {
+		t.Fatalf("encode returned error: %v", err)
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("body is not gzip data: %v", err)
+	}
+	dec := newMsgpackDecoder(gz)
+	for i := range records {
+		v, err := dec.decode()
+		if err != nil {
+			t.Fatalf("record %d: %v", i, err)
+		}
+		if m, ok := v.(map[string]interface{}); !ok || len(m) != 1 {
+			t.Errorf("record %d = %#v", i, v)
+		}
+	}
+	if _, err := dec.decode(); err != io.EOF {
+		t.Errorf("Expected io.EOF after the last record, got %v", err)
+	}
+
+	if _, err := (MsgpackBody{Records: []map[string]interface{}{{"c": make(chan int)}}}).encode(); err == nil {
+		t.Error("Expected error for an unsupported value")
+	}
+}
+
+func TestBulkImportService_UploadRecords(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/bulk_import/upload_part/session/part1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		// Records are sent as a part file, the same as UploadPart sends
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("FormFile returned error: %v", err)
+		}
+		defer file.Close()
+		if header.Filename != "part1" {
+			t.Errorf("Filename = %s, want part1", header.Filename)
+		}
+		body, _ := io.ReadAll(file)
+		want, _ := EncodeImportRecords([]map[string]interface{}{{"id": 1}})
+		if !bytes.Equal(body, want) {
+			t.Errorf("body = %x, want %x", body, want)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	err := client.BulkImport.UploadRecords(context.Background(), "session", "part1", []map[string]interface{}{{"id": 1}})
+	if err != nil {
+		t.Fatalf("BulkImport.UploadRecords returned error: %v", err)
+	}
+}
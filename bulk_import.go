@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net/http"
 )
 
 // BulkImportService handles communication with the bulk import related methods of the Treasure Data API.
@@ -47,7 +48,29 @@ func (s *BulkImportService) Create(ctx context.Context, name, database, table st
 }
 
 // UploadPart uploads a part to a bulk import session
-func (s *BulkImportService) UploadPart(ctx context.Context, name, partName string, data io.Reader) error {
+func (s *BulkImportService) UploadPart(ctx context.Context, name, partName string, data io.Reader, reqOpts ...RequestOption) error {
+	req, err := s.newUploadPartRequest(name, partName, data)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil, reqOpts...)
+	return err
+}
+
+// UploadRecords encodes records as gzip-compressed MessagePack and uploads
+// them as a part of a bulk import session, in the same form as UploadPart
+func (s *BulkImportService) UploadRecords(ctx context.Context, name, partName string, records []map[string]interface{}, reqOpts ...RequestOption) error {
+	data, err := EncodeImportRecords(records)
+	if err != nil {
+		return err
+	}
+	return s.UploadPart(ctx, name, partName, bytes.NewReader(data), reqOpts...)
+}
+
+// newUploadPartRequest builds the multipart/form-data PUT that every part
+// upload is sent as
+func (s *BulkImportService) newUploadPartRequest(name, partName string, data io.Reader) (*http.Request, error) {
 	u := fmt.Sprintf("%s/bulk_import/upload_part/%s/%s", apiVersion, name, partName)
 
 	// Create multipart writer
@@ -57,28 +80,20 @@ func (s *BulkImportService) UploadPart(ctx context.Context, name, partName strin
 	// Create form file field
 	part, err := writer.CreateFormFile("file", partName)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Copy data to form field
 	if _, err := io.Copy(part, data); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Close multipart writer
 	if err := writer.Close(); err != nil {
-		return err
-	}
-
-	req, err := s.client.NewRequest("PUT", u, &buf)
-	if err != nil {
-		return err
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	_, err = s.client.Do(ctx, req, nil)
-	return err
+	return s.client.NewBinaryRequest("PUT", u, buf.Bytes(), writer.FormDataContentType())
 }
 
 // Delete deletes a bulk import session
@@ -170,7 +185,7 @@ func (s *BulkImportService) Unfreeze(ctx context.Context, name string) error {
 }
 
 // Perform performs a bulk import job
-func (s *BulkImportService) Perform(ctx context.Context, name string) (*Job, error) {
+func (s *BulkImportService) Perform(ctx context.Context, name string, reqOpts ...RequestOption) (*Job, error) {
 	u := fmt.Sprintf("%s/bulk_import/perform/%s", apiVersion, name)
 
 	req, err := s.client.NewRequest("POST", u, nil)
@@ -179,7 +194,7 @@ func (s *BulkImportService) Perform(ctx context.Context, name string) (*Job, err
 	}
 
 	var job Job
-	_, err = s.client.Do(ctx, req, &job)
+	_, err = s.client.Do(ctx, req, &job, reqOpts...)
 	if err != nil {
 		return nil, err
 	}
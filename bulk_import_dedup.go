@@ -0,0 +1,150 @@
+package treasuredata
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// PartLedger records the content hash of parts uploaded to bulk import sessions.
+// Implementations must be safe for concurrent use.
+type PartLedger interface {
+	// PartHash returns the hash recorded for a part, if any
+	PartHash(session, part string) (hash string, ok bool, err error)
+	// RecordPart stores the hash of a successfully uploaded part
+	RecordPart(session, part, hash string) error
+}
+
+// MemoryPartLedger is a PartLedger that keeps hashes in memory for the life of the process
+type MemoryPartLedger struct {
+	mu    sync.Mutex
+	parts map[string]map[string]string
+}
+
+// NewMemoryPartLedger creates an empty in-memory ledger
+func NewMemoryPartLedger() *MemoryPartLedger {
+	return &MemoryPartLedger{parts: map[string]map[string]string{}}
+}
+
+// PartHash returns the hash recorded for a part
+func (l *MemoryPartLedger) PartHash(session, part string) (string, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	hash, ok := l.parts[session][part]
+	return hash, ok, nil
+}
+
+// RecordPart stores the hash of an uploaded part
+func (l *MemoryPartLedger) RecordPart(session, part, hash string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.parts[session] == nil {
+		l.parts[session] = map[string]string{}
+	}
+	l.parts[session][part] = hash
+	return nil
+}
+
+// PartState describes how a part compares with what the ledger has recorded
+type PartState int
+
+const (
+	// PartNew means no part with this name has been uploaded
+	PartNew PartState = iota
+	// PartUnchanged means an identical part has already been uploaded
+	PartUnchanged
+	// PartConflict means a part with this name but different content has been uploaded
+	PartConflict
+)
+
+// PartConflictError is returned when a part name was already used for different content
+type PartConflictError struct {
+	Session      string
+	Part         string
+	RecordedHash string
+	Hash         string
+}
+
+func (e *PartConflictError) Error() string {
+	return fmt.Sprintf("part %s in session %s was already uploaded with different content (recorded %s, now %s)",
+		e.Part, e.Session, shortHash(e.RecordedHash), shortHash(e.Hash))
+}
+
+// PartUploadResult describes the outcome of UploadPartIfChanged
+type PartUploadResult struct {
+	Part    string
+	Hash    string
+	Skipped bool
+}
+
+// HashPart returns the hex-encoded SHA-256 of a part's content
+func HashPart(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// CheckPart compares a part's hash with the ledger
+func CheckPart(ledger PartLedger, session, part, hash string) (PartState, error) {
+	recorded, ok, err := ledger.PartHash(session, part)
+	if err != nil {
+		return PartNew, err
+	}
+	switch {
+	case !ok:
+		return PartNew, nil
+	case recorded == hash:
+		return PartUnchanged, nil
+	default:
+		return PartConflict, &PartConflictError{Session: session, Part: part, RecordedHash: recorded, Hash: hash}
+	}
+}
+
+// UploadPartIfChanged uploads a part unless the ledger shows an identical part
+// was already uploaded, which makes retried uploads idempotent. A part whose
+// name was already used for different content is not uploaded and a
+// *PartConflictError is returned.
+func (s *BulkImportService) UploadPartIfChanged(ctx context.Context, ledger PartLedger, name, partName string, data io.Reader) (*PartUploadResult, error) {
+	content, err := io.ReadAll(data)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := HashPart(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PartUploadResult{Part: partName, Hash: hash}
+	state, err := CheckPart(ledger, name, partName, hash)
+	if err != nil {
+		return nil, err
+	}
+	if state == PartUnchanged {
+		result.Skipped = true
+		return result, nil
+	}
+
+	if err := s.UploadPart(ctx, name, partName, bytes.NewReader(content)); err != nil {
+		return nil, err
+	}
+
+	if err := ledger.RecordPart(name, partName, hash); err != nil {
+		return nil, fmt.Errorf("part %s uploaded but not recorded: %w", partName, err)
+	}
+
+	return result, nil
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
@@ -0,0 +1,305 @@
+package treasuredata
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TableColumn is a column from a table's schema
+type TableColumn struct {
+	Name  string
+	Type  string
+	Alias string
+}
+
+// Columns parses the table's schema. The schema is a JSON array of
+// [name, type] or [name, type, alias] entries.
+func (t *Table) Columns() ([]TableColumn, error) {
+	return ParseTableSchema(t.Schema)
+}
+
+// ParseTableSchema parses a table schema string as returned by the API
+func ParseTableSchema(schema string) ([]TableColumn, error) {
+	if strings.TrimSpace(schema) == "" {
+		return nil, nil
+	}
+
+	var entries [][]string
+	if err := json.Unmarshal([]byte(schema), &entries); err != nil {
+		return nil, fmt.Errorf("invalid table schema: %w", err)
+	}
+
+	columns := make([]TableColumn, 0, len(entries))
+	for _, entry := range entries {
+		if len(entry) < 2 {
+			return nil, fmt.Errorf("invalid table schema entry %v", entry)
+		}
+		column := TableColumn{Name: entry[0], Type: entry[1]}
+		if len(entry) > 2 {
+			column.Alias = entry[2]
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// ErrorRecords downloads the records rejected when a bulk import session was
+// performed. The session must have been performed.
+func (s *BulkImportService) ErrorRecords(ctx context.Context, name string, reqOpts ...RequestOption) ([]map[string]interface{}, error) {
+	u := fmt.Sprintf("%s/bulk_import/error_records/%s", apiVersion, name)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := requestContext(ctx, reqOpts)
+	defer cancel()
+
+	resp, err := s.client.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckResponse(resp); err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read error records: %w", err)
+	}
+	defer gz.Close()
+
+	var records []map[string]interface{}
+	dec := newMsgpackDecoder(gz)
+	for {
+		v, err := dec.decode()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode error records: %w", err)
+		}
+		if record, ok := v.(map[string]interface{}); ok {
+			records = append(records, record)
+		}
+	}
+}
+
+// ColumnCoercionErrors summarizes the values of one column that cannot be
+// stored as the column's type
+type ColumnCoercionErrors struct {
+	Column string
+	Type   string
+	// Invalid counts values that cannot be coerced to Type
+	Invalid int64
+	// Missing counts records without the column. Only the time column is
+	// required, so Missing is only reported for it.
+	Missing int64
+	// Samples holds up to CoercionReportSamples offending values
+	Samples []string
+}
+
+// CoercionReportSamples is the number of example values kept per column
+const CoercionReportSamples = 3
+
+// CoercionReport describes why records of a performed bulk import session
+// were rejected, column by column
+type CoercionReport struct {
+	Session      string
+	Database     string
+	Table        string
+	JobID        string
+	ValidRecords int64
+	ErrorRecords int64
+	ErrorParts   int
+	// RecordsAnalyzed is the number of error records downloaded and checked
+	RecordsAnalyzed int
+	// Columns lists columns with at least one failure, most failures first
+	Columns []ColumnCoercionErrors
+	// UnknownColumns lists fields of error records that are not in the table
+	// schema with the number of records containing them
+	UnknownColumns map[string]int64
+}
+
+// CoercionReport downloads a performed session's error records and checks
+// them against the destination table's schema
+func (s *BulkImportService) CoercionReport(ctx context.Context, name string) (*CoercionReport, error) {
+	session, err := s.Show(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := s.client.Tables.Get(ctx, session.Database, session.Table)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := table.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CoercionReport{
+		Session:      session.Name,
+		Database:     session.Database,
+		Table:        session.Table,
+		JobID:        session.JobID,
+		ValidRecords: session.ValidRecords,
+		ErrorRecords: session.ErrorRecords,
+		ErrorParts:   session.ErrorParts,
+	}
+	if session.ErrorRecords == 0 {
+		return report, nil
+	}
+
+	records, err := s.ErrorRecords(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := AnalyzeCoercion(columns, records)
+	report.RecordsAnalyzed = len(records)
+	report.Columns = analysis.Columns
+	report.UnknownColumns = analysis.UnknownColumns
+	return report, nil
+}
+
+// AnalyzeCoercion checks records against table columns and returns the
+// per-column failures in a report with only the analysis fields set
+func AnalyzeCoercion(columns []TableColumn, records []map[string]interface{}) *CoercionReport {
+	// Every table has an implicit integer time column
+	columns = append([]TableColumn{{Name: "time", Type: "long"}}, columns...)
+
+	byName := make(map[string]*ColumnCoercionErrors, len(columns))
+	order := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if _, ok := byName[column.Name]; ok {
+			continue
+		}
+		byName[column.Name] = &ColumnCoercionErrors{Column: column.Name, Type: column.Type}
+		order = append(order, column.Name)
+	}
+
+	report := &CoercionReport{RecordsAnalyzed: len(records), UnknownColumns: map[string]int64{}}
+	for _, record := range records {
+		if _, ok := record["time"]; !ok {
+			byName["time"].Missing++
+		}
+		for field, value := range record {
+			stats, ok := byName[field]
+			if !ok {
+				report.UnknownColumns[field]++
+				continue
+			}
+			if value == nil || coercible(value, stats.Type) {
+				continue
+			}
+			stats.Invalid++
+			if len(stats.Samples) < CoercionReportSamples {
+				stats.Samples = append(stats.Samples, sampleValue(value))
+			}
+		}
+	}
+
+	for _, name := range order {
+		if stats := byName[name]; stats.Invalid > 0 || stats.Missing > 0 {
+			report.Columns = append(report.Columns, *stats)
+		}
+	}
+	sort.SliceStable(report.Columns, func(i, j int) bool {
+		return report.Columns[i].Invalid+report.Columns[i].Missing > report.Columns[j].Invalid+report.Columns[j].Missing
+	})
+	return report
+}
+
+// coercible reports whether the import pipeline can store v in a column of
+// the given type
+func coercible(v interface{}, columnType string) bool {
+	t := strings.ToLower(strings.TrimSpace(columnType))
+	switch {
+	case t == "int", t == "long", t == "bigint":
+		return coercibleInt(v)
+	case t == "float", t == "double":
+		return coercibleFloat(v)
+	case t == "boolean":
+		switch val := v.(type) {
+		case bool:
+			return true
+		case string:
+			_, err := strconv.ParseBool(val)
+			return err == nil
+		}
+		return false
+	case strings.HasPrefix(t, "array"):
+		_, ok := v.([]interface{})
+		return ok
+	case strings.HasPrefix(t, "map"):
+		_, ok := v.(map[string]interface{})
+		return ok
+	default:
+		// Strings and unknown types accept any scalar
+		switch v.(type) {
+		case []interface{}, map[string]interface{}:
+			return false
+		}
+		return true
+	}
+}
+
+func coercibleInt(v interface{}) bool {
+	switch val := v.(type) {
+	case int64:
+		return true
+	case uint64:
+		return false
+	case float64:
+		return val == math.Trunc(val) && val >= -(1<<63) && val < (1<<63)
+	case string:
+		_, err := strconv.ParseInt(strings.TrimSpace(val), 10, 64)
+		return err == nil
+	}
+	return false
+}
+
+func coercibleFloat(v interface{}) bool {
+	switch val := v.(type) {
+	case int64, uint64, float64:
+		return true
+	case string:
+		_, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		return err == nil
+	}
+	return false
+}
+
+func sampleValue(v interface{}) string {
+	var s string
+	switch val := v.(type) {
+	case string:
+		s = strconv.Quote(val)
+	case []byte:
+		s = fmt.Sprintf("<%d bytes>", len(val))
+	default:
+		if b, err := json.Marshal(val); err == nil {
+			s = string(b)
+		} else {
+			s = fmt.Sprint(val)
+		}
+	}
+	if len(s) > 64 {
+		s = s[:61] + "..."
+	}
+	return s
+}
@@ -0,0 +1,151 @@
+package treasuredata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestMsgpackDecoder_RoundTrip(t *testing.T) {
+	values := []interface{}{
+		nil, true, false,
+		int64(0), int64(127), int64(200), int64(70000), int64(math.MaxInt64),
+		int64(-1), int64(-100), int64(-40000), int64(math.MinInt64),
+		1.5, "short", string(bytes.Repeat([]byte("x"), 300)), []byte{1, 2, 3},
+		[]interface{}{int64(1), "a", nil},
+		map[string]interface{}{"k": map[string]interface{}{"n": 2.25}},
+	}
+
+	var buf bytes.Buffer
+	for _, v := range values {
+		b, err := encodeMsgpack(v)
+		if err != nil {
+			t.Fatalf("encodeMsgpack(%v) returned error: %v", v, err)
+		}
+		buf.Write(b)
+	}
+
+	dec := newMsgpackDecoder(&buf)
+	for _, want := range values {
+		got, err := dec.decode()
+		if err != nil {
+			t.Fatalf("decode returned error: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("decode = %#v, want %#v", got, want)
+		}
+	}
+
+	ts, _ := newMsgpackDecoder(bytes.NewReader([]byte{0xd6, 0xff, 0, 0, 0, 10})).decode()
+	if !reflect.DeepEqual(ts, time.Unix(10, 0).UTC()) {
+		t.Errorf("Timestamp extension decoded to %v", ts)
+	}
+
+	if _, err := newMsgpackDecoder(bytes.NewReader([]byte{0x92, 0x01})).decode(); err == nil {
+		t.Error("Expected error for truncated array")
+	}
+}
+
+func TestMsgpackDecoder_HugeLengthHeaders(t *testing.T) {
+	// Each header claims about 4 GiB or 4 billion items but the data ends
+	// right after it, which must fail without allocating the claimed size
+	for _, data := range [][]byte{
+		{0xdb, 0xff, 0xff, 0xff, 0xf0, 'a'},
+		{0xc6, 0xff, 0xff, 0xff, 0xf0, 1, 2},
+		{0xdd, 0xff, 0xff, 0xff, 0xf0, 0x01},
+		{0xdf, 0xff, 0xff, 0xff, 0xf0, 0xa1, 'k', 0x01},
+	} {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		_, err := newMsgpackDecoder(bytes.NewReader(data)).decode()
+		runtime.ReadMemStats(&after)
+		if err != io.ErrUnexpectedEOF {
+			t.Errorf("decode(%x) error = %v, want io.ErrUnexpectedEOF", data, err)
+		}
+		if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+			t.Errorf("decode(%x) allocated %d bytes", data, allocated)
+		}
+	}
+
+	// Values larger than the preallocation cap still decode
+	big := string(bytes.Repeat([]byte("y"), msgpackMaxPreallocBytes*3+1))
+	b, _ := encodeMsgpack(big)
+	if got, err := newMsgpackDecoder(bytes.NewReader(b)).decode(); err != nil || got != big {
+		t.Errorf("decode of a %d byte string = %d bytes, %v", len(big), len(fmt.Sprint(got)), err)
+	}
+}
+
+func TestAnalyzeCoercion(t *testing.T) {
+	columns := []TableColumn{
+		{Name: "user_id", Type: "long"},
+		{Name: "amount", Type: "double"},
+		{Name: "name", Type: "string"},
+		{Name: "tags", Type: "array<string>"},
+	}
+	records := []map[string]interface{}{
+		{"time": int64(1), "user_id": "abc", "amount": "1.5", "name": "a"},
+		{"user_id": "42", "amount": "n/a", "tags": "x"},
+		{"time": "yesterday", "user_id": 2.5, "name": []interface{}{"a"}, "extra": true},
+	}
+
+	report := AnalyzeCoercion(columns, records)
+
+	got := map[string]string{}
+	for _, c := range report.Columns {
+		got[c.Column] = fmt.Sprintf("%d/%d %v", c.Invalid, c.Missing, c.Samples)
+	}
+	want := map[string]string{
+		"user_id": `2/0 ["abc" 2.5]`,
+		"time":    `1/1 ["yesterday"]`,
+		"amount":  `1/0 ["n/a"]`,
+		"name":    `1/0 [["a"]]`,
+		"tags":    `1/0 ["x"]`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Columns = %v, want %v", got, want)
+	}
+	if report.Columns[0].Column != "time" && report.Columns[0].Column != "user_id" {
+		t.Errorf("First column = %s, want one with the most failures", report.Columns[0].Column)
+	}
+	if report.UnknownColumns["extra"] != 1 {
+		t.Errorf("UnknownColumns = %v, want extra=1", report.UnknownColumns)
+	}
+}
+
+func TestBulkImportService_CoercionReport(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/bulk_import/show/s1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "s1", "database": "db", "table": "events", "job_id": "9", "valid_records": 10, "error_records": 1}`)
+	})
+	mux.HandleFunc("/v3/table/show/db/events", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "events", "schema": "[[\"user_id\",\"long\"]]"}`)
+	})
+	mux.HandleFunc("/v3/bulk_import/error_records/s1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		record, _ := encodeMsgpack(map[string]interface{}{"time": int64(1), "user_id": "bad"})
+		gz := gzip.NewWriter(w)
+		gz.Write(record)
+		gz.Close()
+	})
+
+	report, err := client.BulkImport.CoercionReport(context.Background(), "s1")
+	if err != nil {
+		t.Fatalf("CoercionReport returned error: %v", err)
+	}
+	if report.JobID != "9" || report.ErrorRecords != 1 || report.RecordsAnalyzed != 1 {
+		t.Errorf("Report = %+v, want job 9 with 1 analyzed error record", report)
+	}
+	if len(report.Columns) != 1 || report.Columns[0].Column != "user_id" || report.Columns[0].Invalid != 1 {
+		t.Errorf("Columns = %+v, want user_id with 1 invalid value", report.Columns)
+	}
+}
@@ -2,6 +2,7 @@ package treasuredata
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -204,6 +205,51 @@ func TestBulkImportService_UploadPart(t *testing.T) {
 	}
 }
 
+func TestBulkImportService_UploadPartIfChanged(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	uploads := 0
+	mux.HandleFunc("/v3/bulk_import/upload_part/test_session/part001.csv", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		uploads++
+		fmt.Fprint(w, `{"message": "Part uploaded successfully"}`)
+	})
+
+	ctx := context.Background()
+	ledger := NewMemoryPartLedger()
+
+	result, err := client.BulkImport.UploadPartIfChanged(ctx, ledger, "test_session", "part001.csv", strings.NewReader("a,b\n1,2"))
+	if err != nil {
+		t.Fatalf("UploadPartIfChanged returned error: %v", err)
+	}
+	if result.Skipped || uploads != 1 {
+		t.Errorf("First upload skipped=%v uploads=%d, want uploaded once", result.Skipped, uploads)
+	}
+
+	// Retrying identical content is a no-op
+	result, err = client.BulkImport.UploadPartIfChanged(ctx, ledger, "test_session", "part001.csv", strings.NewReader("a,b\n1,2"))
+	if err != nil {
+		t.Fatalf("UploadPartIfChanged retry returned error: %v", err)
+	}
+	if !result.Skipped || uploads != 1 {
+		t.Errorf("Retry skipped=%v uploads=%d, want skipped", result.Skipped, uploads)
+	}
+
+	// Same name with different content is reported and not uploaded
+	_, err = client.BulkImport.UploadPartIfChanged(ctx, ledger, "test_session", "part001.csv", strings.NewReader("a,b\n3,4"))
+	var conflict *PartConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("Expected *PartConflictError, got %v", err)
+	}
+	if conflict.Part != "part001.csv" || conflict.RecordedHash != result.Hash {
+		t.Errorf("Conflict = %+v, want part001.csv recorded as %s", conflict, result.Hash)
+	}
+	if uploads != 1 {
+		t.Errorf("Conflicting part was uploaded")
+	}
+}
+
 func TestBulkImportService_Commit(t *testing.T) {
 	client, mux, teardown := setup()
 	defer teardown()
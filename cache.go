@@ -0,0 +1,217 @@
+package treasuredata
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// cacheHeader marks responses served from the cache
+const cacheHeader = "X-Td-Cache"
+
+// CachedResponse is a response kept by a CacheStore
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// CacheStore keeps cached GET responses. Implementations must be safe for
+// concurrent use; NewMemoryCache returns an in-process store, and shared
+// stores such as Redis can be plugged in by implementing this interface.
+type CacheStore interface {
+	// Get returns the response stored under key if it has not expired
+	Get(key string) (*CachedResponse, bool)
+	// Set stores resp under key for ttl
+	Set(key string, resp *CachedResponse, ttl time.Duration)
+	// Clear removes every entry
+	Clear()
+}
+
+// cachePaths match the slow-changing read endpoints that WithCache caches:
+// databases, tables, access control and CDP audiences, folders and their
+// definitions. Job status, results and executions are never cached.
+var cachePaths = []*regexp.Regexp{
+	regexp.MustCompile(`/v3/database/(list|show/[^/]+)$`),
+	regexp.MustCompile(`/v3/table/(list|show)/`),
+	regexp.MustCompile(`/v3/access_control/`),
+	regexp.MustCompile(`/audiences(/[^/]+)?$`),
+	regexp.MustCompile(`/audiences/[^/]+/(attributes|behaviors|folders|segments)(/[^/]*)?$`),
+	regexp.MustCompile(`/entities/(folders|by-folder)/`),
+}
+
+// responseCache is the cache configured by WithCache
+type responseCache struct {
+	store CacheStore
+	ttl   time.Duration
+}
+
+// WithCache caches successful GET responses of slow-changing resources
+// (databases, tables, access control policies, CDP audiences and folders)
+// in store for ttl. Any successful POST, PUT, PATCH or DELETE made through the
+// client clears the cache, so the client sees its own changes. Pass a
+// context from WithoutCache to any call to force a fresh read.
+func WithCache(store CacheStore, ttl time.Duration) ClientOption {
+	return func(c *Client) error {
+		if store == nil || ttl <= 0 {
+			return nil
+		}
+		c.cache = &responseCache{store: store, ttl: ttl}
+		return nil
+	}
+}
+
+type noCacheKey struct{}
+
+// WithoutCache returns a context whose requests skip the cache configured
+// with WithCache. It is the per-call way to bypass the cache:
+//
+//	dbs, err := client.Databases.List(td.WithoutCache(ctx))
+//
+// Fresh responses are still stored.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+// IsCached reports whether a response returned by Client.Do was served from
+// the cache
+func IsCached(resp *http.Response) bool {
+	return resp != nil && resp.Header.Get(cacheHeader) != ""
+}
+
+// cacheKey identifies a request by URL and by a hash of its credentials, so
+// that a store shared between clients never serves one account's data to
+// another
+func cacheKey(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return hex.EncodeToString(sum[:8]) + " " + req.URL.String()
+}
+
+func (c *Client) cacheable(req *http.Request) bool {
+	if c.cache == nil || req.Method != http.MethodGet {
+		return false
+	}
+	for _, re := range cachePaths {
+		if re.MatchString(req.URL.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// cachedResponse returns a stored response for req, or nil
+func (c *Client) cachedResponse(ctx context.Context, req *http.Request) *http.Response {
+	if !c.cacheable(req) {
+		return nil
+	}
+	if skip, _ := ctx.Value(noCacheKey{}).(bool); skip {
+		return nil
+	}
+	cached, ok := c.cache.store.Get(cacheKey(req))
+	if !ok {
+		return nil
+	}
+
+	header := cached.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set(cacheHeader, "hit")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", cached.StatusCode, http.StatusText(cached.StatusCode)),
+		StatusCode:    cached.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       req,
+	}
+}
+
+// updateCache stores successful cacheable responses and clears the cache
+// after successful changes. It returns resp with its body intact.
+func (c *Client) updateCache(req *http.Request, resp *http.Response) (*http.Response, error) {
+	if c.cache == nil || resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, nil
+	}
+	if isMutating(req.Method) {
+		c.cache.store.Clear()
+		return resp, nil
+	}
+	if !c.cacheable(req) || resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	c.cache.store.Set(cacheKey(req), &CachedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+	}, c.cache.ttl)
+	return resp, nil
+}
+
+// MemoryCache is an in-process CacheStore
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+}
+
+type memoryCacheEntry struct {
+	resp    *CachedResponse
+	expires time.Time
+}
+
+// NewMemoryCache returns an empty in-process cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]memoryCacheEntry)}
+}
+
+// Get returns the unexpired response stored under key
+func (m *MemoryCache) Get(key string) (*CachedResponse, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return entry.resp, true
+}
+
+// Set stores resp under key for ttl, dropping expired entries
+func (m *MemoryCache) Set(key string, resp *CachedResponse, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for k, entry := range m.entries {
+		if now.After(entry.expires) {
+			delete(m.entries, k)
+		}
+	}
+	m.entries[key] = memoryCacheEntry{resp: resp, expires: now.Add(ttl)}
+}
+
+// Clear removes every entry
+func (m *MemoryCache) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[string]memoryCacheEntry)
+}
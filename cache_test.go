@@ -0,0 +1,117 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithCache(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	WithCache(NewMemoryCache(), time.Minute)(client)
+
+	calls := 0
+	mux.HandleFunc("/v3/database/list", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprintf(w, `{"databases": [{"name": "db%d"}]}`, calls)
+	})
+	mux.HandleFunc("/v3/database/create/new_db", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"database": "new_db"}`)
+	})
+
+	ctx := context.Background()
+	first, err := client.Databases.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := client.Databases.List(ctx)
+	if calls != 1 || second[0].Name != first[0].Name {
+		t.Errorf("expected the second list to be cached: calls=%d, %v", calls, second)
+	}
+
+	// A bypass reads fresh data and refreshes the entry
+	fresh, _ := client.Databases.List(WithoutCache(ctx))
+	if calls != 2 || fresh[0].Name != "db2" {
+		t.Errorf("WithoutCache: calls=%d, %v", calls, fresh)
+	}
+	cached, _ := client.Databases.List(ctx)
+	if calls != 2 || cached[0].Name != "db2" {
+		t.Errorf("expected the refreshed entry: calls=%d, %v", calls, cached)
+	}
+
+	// Changes clear the cache
+	if _, err := client.Databases.Create(ctx, "new_db"); err != nil {
+		t.Fatal(err)
+	}
+	client.Databases.List(ctx)
+	if calls != 3 {
+		t.Errorf("expected a fresh list after a change, calls=%d", calls)
+	}
+}
+
+func TestWithCache_OnlySlowChangingResources(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	WithCache(NewMemoryCache(), time.Minute)(client)
+
+	calls := 0
+	mux.HandleFunc("/v3/job/show/1", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"job_id": "1", "status": "running"}`)
+	})
+
+	client.Jobs.Get(context.Background(), "1")
+	client.Jobs.Get(context.Background(), "1")
+	if calls != 2 {
+		t.Errorf("job status must not be cached, calls=%d", calls)
+	}
+}
+
+func TestWithCache_IsCached(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	WithCache(NewMemoryCache(), time.Minute)(client)
+
+	calls := 0
+	mux.HandleFunc("/v3/access_control/policies", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `[]`)
+	})
+
+	for i := 0; i < 2; i++ {
+		req, _ := client.NewRequest("GET", "v3/access_control/policies", nil)
+		resp, err := client.Do(WithoutCache(context.Background()), req, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if IsCached(resp) {
+			t.Error("WithoutCache returned a cached response")
+		}
+	}
+	req, _ := client.NewRequest("GET", "v3/access_control/policies", nil)
+	resp, _ := client.Do(context.Background(), req, nil)
+	if calls != 2 || !IsCached(resp) {
+		t.Errorf("calls=%d cached=%t, want 2 and a cached response", calls, IsCached(resp))
+	}
+}
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	cache := NewMemoryCache()
+	cache.Set("a", &CachedResponse{StatusCode: 200}, time.Millisecond)
+	cache.Set("b", &CachedResponse{StatusCode: 200}, time.Hour)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := cache.Get("a"); ok {
+		t.Error("expired entry was returned")
+	}
+	if _, ok := cache.Get("b"); !ok {
+		t.Error("live entry was not returned")
+	}
+	cache.Clear()
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Clear left an entry")
+	}
+}
@@ -221,8 +221,13 @@ type CDPAudienceExecution struct {
 // CDPAudienceStatisticsPoint represents a single data point in audience statistics
 type CDPAudienceStatisticsPoint []interface{} // [timestamp, population, hasData]
 
-// CDPAudienceSampleValue represents a sample value with its frequency
-type CDPAudienceSampleValue []interface{} // [value, frequency]
+// CDPAudienceSampleValue is a sample value of a column with the number of
+// profiles or events that have it. The API returns each one as a
+// [value, frequency] array.
+type CDPAudienceSampleValue struct {
+	Value     interface{} `json:"value"`
+	Frequency int64       `json:"frequency"`
+}
 
 // CDPBehaviorSchemaField represents a field in a behavior schema with visibility
 type CDPBehaviorSchemaField struct {
@@ -297,8 +302,11 @@ type CDPActivationExecution struct {
 	FinishedAt        *TDTime `json:"finishedAt"`
 	Status            string  `json:"status"`
 	// Legacy fields for compatibility
-	ActivationID    string `json:"activation_id,omitempty"`
+	ActivationID string `json:"activation_id,omitempty"`
+	// RecordsExported and RecordsSkipped count the records delivered to and
+	// rejected by the destination; both are zero until the run reports them
 	RecordsExported int64  `json:"records_exported,omitempty"`
+	RecordsSkipped  int64  `json:"records_skipped,omitempty"`
 	ErrorMessage    string `json:"error_message,omitempty"`
 }
 
@@ -515,6 +523,18 @@ type CDPAudienceUpdateRequest struct {
 	EnrichmentTdJsSdkEnabled     *bool                  `json:"enrichmentTdJsSdkEnabled,omitempty"`
 	Master                       *CDPAudienceMaster     `json:"master,omitempty"`
 	Attributes                   []CDPAudienceAttribute `json:"attributes,omitempty"`
+	Behaviors                    []CDPAudienceBehavior  `json:"behaviors,omitempty"`
+}
+
+// CDPSegmentCreateRequest represents a request to create a rule-based segment
+type CDPSegmentCreateRequest struct {
+	Name            string      `json:"name"`
+	Description     string      `json:"description,omitempty"`
+	Realtime        bool        `json:"realtime,omitempty"`
+	IsVisible       bool        `json:"isVisible"`
+	Kind            int         `json:"kind,omitempty"`
+	SegmentFolderID string      `json:"segmentFolderId,omitempty"`
+	Rule            interface{} `json:"rule,omitempty"`
 }
 
 // CDPActivationCreateRequest represents a request to create an activation
@@ -0,0 +1,190 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultActivationHistoryConcurrency bounds the run listings in flight
+const defaultActivationHistoryConcurrency = 8
+
+// CDPActivationRun is one execution of an activation, with the activation it
+// belongs to and a link to the workflow attempt that delivered it
+type CDPActivationRun struct {
+	AudienceID        string     `json:"audience_id"`
+	SegmentID         string     `json:"segment_id"`
+	ActivationID      string     `json:"activation_id"`
+	ActivationName    string     `json:"activation_name"`
+	ExecutionID       string     `json:"execution_id"`
+	Status            string     `json:"status"`
+	CreatedAt         time.Time  `json:"created_at"`
+	FinishedAt        *time.Time `json:"finished_at,omitempty"`
+	RecordsExported   int64      `json:"records_exported"`
+	RecordsSkipped    int64      `json:"records_skipped"`
+	ErrorMessage      string     `json:"error_message,omitempty"`
+	WorkflowID        string     `json:"workflow_id,omitempty"`
+	WorkflowSessionID string     `json:"workflow_session_id,omitempty"`
+	WorkflowAttemptID string     `json:"workflow_attempt_id,omitempty"`
+	// WorkflowAttemptURL is the workflow API URL of the attempt
+	WorkflowAttemptURL string `json:"workflow_attempt_url,omitempty"`
+}
+
+// Duration returns how long the run took, or zero if it has not finished
+func (r CDPActivationRun) Duration() time.Duration {
+	if r.FinishedAt == nil {
+		return 0
+	}
+	return r.FinishedAt.Sub(r.CreatedAt)
+}
+
+// CDPActivationHistoryOptions specifies optional parameters to
+// ActivationHistory
+type CDPActivationHistoryOptions struct {
+	// Since drops runs created before it; zero keeps every run
+	Since time.Time
+	// Concurrency is the number of activations read at once (default 8)
+	Concurrency int
+}
+
+// ActivationHistory collects the runs of every activation of an audience,
+// newest first. Activations are read in parallel; if some fail, the runs of
+// the others are returned along with a *BatchError.
+func (s *CDPService) ActivationHistory(ctx context.Context, audienceID string, opts *CDPActivationHistoryOptions) ([]CDPActivationRun, error) {
+	if opts == nil {
+		opts = &CDPActivationHistoryOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultActivationHistoryConcurrency
+	}
+
+	activations, err := s.GetAudienceActivations(ctx, audienceID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	results, batchErr := BatchMap(ctx, concurrency, activations.Activations, func(ctx context.Context, activation CDPActivation) ([]CDPActivationRun, error) {
+		executions, err := s.GetActivationExecutions(ctx, audienceID, activation.SegmentID, activation.ID)
+		if err != nil {
+			return nil, fmt.Errorf("activation %s: %w", activation.ID, err)
+		}
+		var runs []CDPActivationRun
+		for _, execution := range executions {
+			if !opts.Since.IsZero() && execution.CreatedAt.Before(opts.Since) {
+				continue
+			}
+			runs = append(runs, s.activationRun(audienceID, activation, execution))
+		}
+		return runs, nil
+	})
+
+	var runs []CDPActivationRun
+	for _, result := range results {
+		runs = append(runs, result...)
+	}
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].CreatedAt.After(runs[j].CreatedAt)
+	})
+	return runs, batchErr
+}
+
+func (s *CDPService) activationRun(audienceID string, activation CDPActivation, execution CDPActivationExecution) CDPActivationRun {
+	run := CDPActivationRun{
+		AudienceID:        audienceID,
+		SegmentID:         activation.SegmentID,
+		ActivationID:      activation.ID,
+		ActivationName:    activation.Name,
+		ExecutionID:       execution.ID,
+		Status:            execution.Status,
+		CreatedAt:         execution.CreatedAt.Time,
+		RecordsExported:   execution.RecordsExported,
+		RecordsSkipped:    execution.RecordsSkipped,
+		ErrorMessage:      execution.ErrorMessage,
+		WorkflowID:        execution.WorkflowID,
+		WorkflowSessionID: execution.WorkflowSessionID,
+		WorkflowAttemptID: execution.WorkflowAttemptID,
+	}
+	if execution.FinishedAt != nil && !execution.FinishedAt.IsZero() {
+		finished := execution.FinishedAt.Time
+		run.FinishedAt = &finished
+	}
+	if run.WorkflowAttemptID != "" && s.client.WorkflowURL != nil {
+		if u, err := s.client.WorkflowURL.Parse("api/attempts/" + run.WorkflowAttemptID); err == nil {
+			run.WorkflowAttemptURL = u.String()
+		}
+	}
+	return run
+}
+
+// Failed reports whether the run ended in failure
+func (r CDPActivationRun) Failed() bool {
+	switch strings.ToLower(r.Status) {
+	case "failed", "failure", "error", "errored":
+		return true
+	}
+	return false
+}
+
+// FailedActivationRuns returns, for each activation of an audience whose
+// latest run since opts.Since failed, that run. Activations that succeeded
+// after a failure are left out, as is anything ActivationHistory could not
+// read, which is reported in a *BatchError.
+func (s *CDPService) FailedActivationRuns(ctx context.Context, audienceID string, opts *CDPActivationHistoryOptions) ([]CDPActivationRun, error) {
+	runs, err := s.ActivationHistory(ctx, audienceID, opts)
+	if runs == nil {
+		return nil, err
+	}
+
+	// Runs are newest first, so the first run seen is the latest
+	seen := map[string]bool{}
+	var failed []CDPActivationRun
+	for _, run := range runs {
+		if seen[run.ActivationID] {
+			continue
+		}
+		seen[run.ActivationID] = true
+		if run.Failed() {
+			failed = append(failed, run)
+		}
+	}
+	return failed, err
+}
+
+// CDPActivationRetry is the outcome of re-running the activation of a
+// failed run
+type CDPActivationRetry struct {
+	Failed CDPActivationRun `json:"failed"`
+	// Execution is the new run, nil if it could not be started
+	Execution *CDPActivationExecution `json:"execution,omitempty"`
+	Err       error                   `json:"-"`
+}
+
+// RetryActivations runs the activations of failed runs again, concurrency at
+// a time (default 8). Every run gets a CDPActivationRetry in order; if any
+// could not be started, a *BatchError is also returned.
+func (s *CDPService) RetryActivations(ctx context.Context, failed []CDPActivationRun, concurrency int) ([]CDPActivationRetry, error) {
+	if concurrency <= 0 {
+		concurrency = defaultActivationHistoryConcurrency
+	}
+	executions, err := BatchMap(ctx, concurrency, failed, func(ctx context.Context, run CDPActivationRun) (*CDPActivationExecution, error) {
+		execution, err := s.ExecuteActivation(ctx, run.AudienceID, run.SegmentID, run.ActivationID)
+		if err != nil {
+			return nil, fmt.Errorf("activation %s: %w", run.ActivationID, err)
+		}
+		return execution, nil
+	})
+
+	retries := make([]CDPActivationRetry, len(failed))
+	for i, run := range failed {
+		retries[i] = CDPActivationRetry{Failed: run, Execution: executions[i]}
+	}
+	if batchErr, ok := err.(*BatchError); ok {
+		for _, taskErr := range batchErr.Errors {
+			retries[taskErr.Index].Err = taskErr.Err
+		}
+	}
+	return retries, err
+}
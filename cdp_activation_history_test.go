@@ -0,0 +1,136 @@
+package treasuredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCDPService_ActivationHistory(t *testing.T) {
+	client, mux, teardown := setupCDP()
+	defer teardown()
+
+	mux.HandleFunc("/audiences/1/syndications", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[
+			{"id": "10", "name": "to-s3", "segmentId": "100"},
+			{"id": "11", "name": "to-ads", "segmentId": "101"},
+			{"id": "12", "name": "broken", "segmentId": "102"}
+		]`)
+	})
+	mux.HandleFunc("/audiences/1/segments/100/syndications/10/runs", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"id": "a", "status": "success", "createdAt": "2026-10-10T00:00:00Z", "finishedAt": "2026-10-10T00:05:00Z", "workflowAttemptId": "900", "recordsExported": 1200, "recordsSkipped": "3"},
+			{"id": "old", "status": "success", "createdAt": "2026-01-01T00:00:00Z"}
+		]`)
+	})
+	mux.HandleFunc("/audiences/1/segments/101/syndications/11/runs", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "b", "status": "running", "createdAt": "2026-10-12T00:00:00Z"}]`)
+	})
+	mux.HandleFunc("/audiences/1/segments/102/syndications/12/runs", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	since := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	runs, err := client.CDP.ActivationHistory(context.Background(), "1", &CDPActivationHistoryOptions{Since: since, Concurrency: 2})
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Errors) != 1 {
+		t.Fatalf("err = %v, want one failed activation", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("got %d runs, want 2: %+v", len(runs), runs)
+	}
+	if runs[0].ExecutionID != "b" || runs[0].ActivationName != "to-ads" || runs[0].FinishedAt != nil || runs[0].Duration() != 0 {
+		t.Errorf("runs[0] = %+v", runs[0])
+	}
+	first := runs[1]
+	if first.ExecutionID != "a" || first.SegmentID != "100" || first.RecordsExported != 1200 || first.RecordsSkipped != 3 || first.Duration() != 5*time.Minute {
+		t.Errorf("runs[1] = %+v", first)
+	}
+	if want := "https://api-workflow.us01.treasuredata.com/api/attempts/900"; first.WorkflowAttemptURL != want {
+		t.Errorf("WorkflowAttemptURL = %q, want %q", first.WorkflowAttemptURL, want)
+	}
+}
+
+func TestCDPService_GetActivationExecution(t *testing.T) {
+	client, mux, teardown := setupCDP()
+	defer teardown()
+
+	mux.HandleFunc("/audiences/1/segments/100/syndications/10/runs/a", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id": "a", "status": "success", "createdAt": "2026-10-10T00:00:00Z", "records_exported": 10, "records_skipped": 2}`)
+	})
+	// Older deployments only list runs
+	mux.HandleFunc("/audiences/1/segments/100/syndications/10/runs/b", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/audiences/1/segments/100/syndications/10/runs", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "b", "status": "success", "createdAt": "2026-10-11T00:00:00Z", "recordsExported": 5}]`)
+	})
+
+	execution, err := client.CDP.GetActivationExecution(context.Background(), "1", "100", "10", "a")
+	if err != nil || execution.RecordsExported != 10 || execution.RecordsSkipped != 2 {
+		t.Errorf("execution a = %+v, %v", execution, err)
+	}
+	execution, err = client.CDP.GetActivationExecution(context.Background(), "1", "100", "10", "b")
+	if err != nil || execution.ID != "b" || execution.RecordsExported != 5 {
+		t.Errorf("execution b = %+v, %v", execution, err)
+	}
+	if _, err := client.CDP.GetActivationExecution(context.Background(), "1", "100", "10", "c"); !IsNotFound(err) {
+		t.Errorf("missing execution err = %v", err)
+	}
+}
+
+func TestCDPService_RetryFailedActivations(t *testing.T) {
+	client, mux, teardown := setupCDP()
+	defer teardown()
+
+	mux.HandleFunc("/audiences/1/syndications", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "10", "segmentId": "100"}, {"id": "11", "segmentId": "101"}, {"id": "12", "segmentId": "102"}]`)
+	})
+	// 10 failed last, 11 failed and then succeeded, 12 failed and cannot be re-run
+	mux.HandleFunc("/audiences/1/segments/100/syndications/10/runs", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			fmt.Fprint(w, `{"id": "new", "status": "running"}`)
+			return
+		}
+		fmt.Fprint(w, `[{"id": "a", "status": "success", "createdAt": "2026-10-10T00:00:00Z"}, {"id": "b", "status": "failed", "createdAt": "2026-10-11T00:00:00Z"}]`)
+	})
+	mux.HandleFunc("/audiences/1/segments/101/syndications/11/runs", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			t.Error("activation 11 was re-run")
+		}
+		fmt.Fprint(w, `[{"id": "c", "status": "failed", "createdAt": "2026-10-10T00:00:00Z"}, {"id": "d", "status": "success", "createdAt": "2026-10-11T00:00:00Z"}]`)
+	})
+	mux.HandleFunc("/audiences/1/segments/102/syndications/12/runs", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		fmt.Fprint(w, `[{"id": "e", "status": "Error", "createdAt": "2026-10-12T00:00:00Z"}]`)
+	})
+
+	failed, err := client.CDP.FailedActivationRuns(context.Background(), "1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 2 || failed[0].ExecutionID != "e" || failed[1].ExecutionID != "b" {
+		t.Fatalf("failed = %+v", failed)
+	}
+
+	retries, err := client.CDP.RetryActivations(context.Background(), failed, 2)
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Errors) != 1 {
+		t.Fatalf("err = %v, want one failed retry", err)
+	}
+	if retries[0].Err == nil || retries[0].Execution != nil {
+		t.Errorf("retries[0] = %+v", retries[0])
+	}
+	if retries[1].Err != nil || retries[1].Execution == nil || retries[1].Execution.ID != "new" {
+		t.Errorf("retries[1] = %+v", retries[1])
+	}
+}
@@ -2,6 +2,7 @@ package treasuredata
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 )
@@ -147,6 +148,26 @@ func (s *CDPService) GetActivation(ctx context.Context, audienceID, segmentID, a
 	return &activation, nil
 }
 
+// CreateSegmentActivation creates an activation (syndication) on an audience
+// segment from a full activation definition; its ID and ownership fields are
+// assigned by the server
+func (s *CDPService) CreateSegmentActivation(ctx context.Context, audienceID, segmentID string, activation *CDPActivation) (*CDPActivation, error) {
+	u := fmt.Sprintf("audiences/%s/segments/%s/syndications", audienceID, segmentID)
+
+	req, err := s.client.NewCDPRequest("POST", u, activation)
+	if err != nil {
+		return nil, err
+	}
+
+	var created CDPActivation
+	_, err = s.client.Do(ctx, req, &created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
 // UpdateActivationStatus updates the status of an activation (syndication)
 func (s *CDPService) UpdateActivationStatus(ctx context.Context, audienceID, segmentID, activationID, status string) (*CDPActivation, error) {
 	u := fmt.Sprintf("audiences/%s/segments/%s/syndications/%s", audienceID, segmentID, activationID)
@@ -244,6 +265,67 @@ func (s *CDPService) GetActivationExecutions(ctx context.Context, audienceID, se
 	return executions, nil
 }
 
+// GetActivationExecution retrieves a single execution of an activation with
+// its record counts. Where the API has no endpoint for a single run, the
+// execution is looked up in the activation's run history.
+func (s *CDPService) GetActivationExecution(ctx context.Context, audienceID, segmentID, activationID, executionID string) (*CDPActivationExecution, error) {
+	u := fmt.Sprintf("audiences/%s/segments/%s/syndications/%s/runs/%s", audienceID, segmentID, activationID, executionID)
+
+	req, err := s.client.NewCDPRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var execution CDPActivationExecution
+	_, err = s.client.Do(ctx, req, &execution)
+	if err == nil {
+		return &execution, nil
+	}
+	if !IsNotFound(err) {
+		return nil, err
+	}
+
+	executions, listErr := s.GetActivationExecutions(ctx, audienceID, segmentID, activationID)
+	if listErr != nil {
+		return nil, listErr
+	}
+	for i := range executions {
+		if executions[i].ID == executionID {
+			return &executions[i], nil
+		}
+	}
+	return nil, err
+}
+
+// UnmarshalJSON accepts record counts under their current camelCase names
+// as well as the legacy snake_case ones, as numbers or strings
+func (e *CDPActivationExecution) UnmarshalJSON(data []byte) error {
+	type plain CDPActivationExecution
+	var p struct {
+		plain
+		RecordsExported      FlexibleInt64 `json:"records_exported"`
+		RecordsSkipped       FlexibleInt64 `json:"records_skipped"`
+		RecordsExportedCamel FlexibleInt64 `json:"recordsExported"`
+		RecordsSkippedCamel  FlexibleInt64 `json:"recordsSkipped"`
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+
+	count := func(values ...FlexibleInt64) int64 {
+		for _, v := range values {
+			if v.Value != nil {
+				return *v.Value
+			}
+		}
+		return 0
+	}
+	*e = CDPActivationExecution(p.plain)
+	e.RecordsExported = count(p.RecordsExportedCamel, p.RecordsExported)
+	e.RecordsSkipped = count(p.RecordsSkippedCamel, p.RecordsSkipped)
+	return nil
+}
+
 // GetAudienceActivations retrieves activations for a specific audience
 func (s *CDPService) GetAudienceActivations(ctx context.Context, audienceID string, opts *CDPActivationListOptions) (*CDPActivationListResponse, error) {
 	u := fmt.Sprintf("audiences/%s/syndications", audienceID)
@@ -0,0 +1,154 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// CDPAttributeUsage lists the segments whose rules reference an audience
+// attribute or behavior
+type CDPAttributeUsage struct {
+	Name string `json:"name"`
+	// Kind is "attribute" or "behavior"
+	Kind string `json:"kind"`
+	// Segments holds the names of referencing segments
+	Segments []string `json:"segments"`
+}
+
+// Used reports whether any segment references the attribute or behavior
+func (u CDPAttributeUsage) Used() bool {
+	return len(u.Segments) > 0
+}
+
+// CDPAttributeUsageReport summarizes which attributes and behaviors of an
+// audience are referenced by its segment rules
+type CDPAttributeUsageReport struct {
+	AudienceID      string              `json:"audience_id"`
+	SegmentsScanned int                 `json:"segments_scanned"`
+	Attributes      []CDPAttributeUsage `json:"attributes"`
+	Behaviors       []CDPAttributeUsage `json:"behaviors"`
+	// Unknown lists references to columns or behaviors the audience does not
+	// define, e.g. after an attribute was removed
+	Unknown []CDPAttributeUsage `json:"unknown,omitempty"`
+}
+
+// Unused returns attributes and behaviors no segment references; these are
+// candidates for removal from the audience
+func (r *CDPAttributeUsageReport) Unused() []CDPAttributeUsage {
+	var unused []CDPAttributeUsage
+	for _, usage := range append(append([]CDPAttributeUsage{}, r.Attributes...), r.Behaviors...) {
+		if !usage.Used() {
+			unused = append(unused, usage)
+		}
+	}
+	return unused
+}
+
+// AnalyzeAttributeUsage scans the rules of every segment in an audience and
+// reports which attributes and behaviors they reference
+func (s *CDPService) AnalyzeAttributeUsage(ctx context.Context, audienceID string) (*CDPAttributeUsageReport, error) {
+	rawAttributes, err := s.GetAudienceAttributes(ctx, audienceID)
+	if err != nil {
+		return nil, err
+	}
+	var attributes []CDPAudienceAttribute
+	data, err := json.Marshal(rawAttributes)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return nil, fmt.Errorf("failed to decode audience attributes: %w", err)
+	}
+
+	behaviors, err := s.GetAudienceBehaviors(ctx, audienceID)
+	if err != nil {
+		return nil, err
+	}
+
+	segments, err := s.ListSegments(ctx, audienceID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CDPAttributeUsageReport{AudienceID: audienceID}
+	attributeIndex := make(map[string]int, len(attributes))
+	for _, attribute := range attributes {
+		attributeIndex[attribute.Name] = len(report.Attributes)
+		report.Attributes = append(report.Attributes, CDPAttributeUsage{Name: attribute.Name, Kind: "attribute"})
+	}
+	behaviorIndex := make(map[string]int, 2*len(behaviors))
+	for _, behavior := range behaviors {
+		behaviorIndex[behavior.Name] = len(report.Behaviors)
+		behaviorIndex[behavior.ID] = len(report.Behaviors)
+		report.Behaviors = append(report.Behaviors, CDPAttributeUsage{Name: behavior.Name, Kind: "behavior"})
+	}
+	unknownIndex := make(map[CDPRuleReference]int)
+
+	for _, segment := range segments.Segments {
+		// Segment lists may omit rules; fetch the full segment when needed
+		if segment.Rule == nil {
+			full, err := s.GetSegment(ctx, audienceID, segment.ID)
+			if err != nil {
+				return nil, err
+			}
+			segment = *full
+		}
+
+		rule, err := ParseSegmentRule(segment.Rule)
+		if err != nil {
+			return nil, fmt.Errorf("segment %s: %w", segment.ID, err)
+		}
+		report.SegmentsScanned++
+
+		for _, ref := range rule.References() {
+			var usage *CDPAttributeUsage
+			if ref.Behavior != "" {
+				if i, ok := behaviorIndex[ref.Behavior]; ok {
+					usage = &report.Behaviors[i]
+				}
+			} else if i, ok := attributeIndex[ref.Name]; ok {
+				usage = &report.Attributes[i]
+			}
+
+			if usage == nil {
+				i, ok := unknownIndex[ref]
+				if !ok {
+					i = len(report.Unknown)
+					unknownIndex[ref] = i
+					unknown := CDPAttributeUsage{Name: ref.Name, Kind: "attribute"}
+					if ref.Behavior != "" {
+						unknown = CDPAttributeUsage{Name: ref.Behavior + "." + ref.Name, Kind: "behavior"}
+					}
+					report.Unknown = append(report.Unknown, unknown)
+				}
+				usage = &report.Unknown[i]
+			}
+			usage.Segments = appendUnique(usage.Segments, segment.Name)
+		}
+	}
+
+	sortUsage(report.Attributes)
+	sortUsage(report.Behaviors)
+	return report, nil
+}
+
+// sortUsage orders unused entries first, then by name
+func sortUsage(usage []CDPAttributeUsage) {
+	sort.SliceStable(usage, func(i, j int) bool {
+		if usage[i].Used() != usage[j].Used() {
+			return !usage[i].Used()
+		}
+		return usage[i].Name < usage[j].Name
+	})
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,161 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSegmentRule(t *testing.T) {
+	raw := map[string]interface{}{
+		"type": "And",
+		"conditions": []interface{}{
+			map[string]interface{}{
+				"type":      "Value",
+				"leftValue": map[string]interface{}{"name": "gender", "visibility": "clear"},
+				"operator":  map[string]interface{}{"type": "Equal", "rightValue": "F"},
+			},
+			map[string]interface{}{
+				"type": "Or",
+				"conditions": []interface{}{
+					map[string]interface{}{
+						"type":      "Value",
+						"leftValue": map[string]interface{}{"name": "amount", "source": map[string]interface{}{"name": "purchases"}},
+						"operator":  map[string]interface{}{"type": "Greater", "rightValue": 100},
+					},
+					map[string]interface{}{
+						"type":      "Value",
+						"leftValue": map[string]interface{}{"name": "gender"},
+						"operator":  map[string]interface{}{"type": "IsNull", "not": true},
+					},
+				},
+			},
+		},
+	}
+
+	rule, err := ParseSegmentRule(raw)
+	if err != nil {
+		t.Fatalf("ParseSegmentRule returned error: %v", err)
+	}
+	if rule.Type != "And" || len(rule.Conditions) != 2 || !rule.Conditions[1].Conditions[1].Operator.Not {
+		t.Errorf("Unexpected rule %+v", rule)
+	}
+
+	want := []CDPRuleReference{{Name: "gender"}, {Name: "amount", Behavior: "purchases"}}
+	if got := rule.References(); !reflect.DeepEqual(got, want) {
+		t.Errorf("References = %+v, want %+v", got, want)
+	}
+
+	if rule, err := ParseSegmentRule(nil); rule != nil || err != nil {
+		t.Errorf("ParseSegmentRule(nil) = %v, %v", rule, err)
+	}
+}
+
+func TestCDPService_AnalyzeAttributeUsage(t *testing.T) {
+	client, mux, teardown := setupCDP()
+	defer teardown()
+
+	mux.HandleFunc("/audiences/1/attributes", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"name": "gender"}, {"name": "age"}, {"name": "country"}]`)
+	})
+	mux.HandleFunc("/audiences/1/behaviors", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "10", "name": "purchases"}, {"id": "11", "name": "pageviews"}]`)
+	})
+	mux.HandleFunc("/audiences/1/segments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"id": "100", "name": "Women", "rule": {"type": "And", "conditions": [
+				{"type": "Value", "leftValue": {"name": "gender"}, "operator": {"type": "Equal", "rightValue": "F"}},
+				{"type": "Value", "leftValue": {"name": "legacy_score"}, "operator": {"type": "Greater", "rightValue": 1}}
+			]}},
+			{"id": "101", "name": "Buyers"}
+		]`)
+	})
+	mux.HandleFunc("/audiences/1/segments/101", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "101", "name": "Buyers", "rule": {"type": "And", "conditions": [
+			{"type": "Value", "leftValue": {"name": "amount", "source": "10"}, "operator": {"type": "Greater", "rightValue": 0}},
+			{"type": "Value", "leftValue": {"name": "gender"}, "operator": {"type": "Equal", "rightValue": "M"}}
+		]}}`)
+	})
+
+	report, err := client.CDP.AnalyzeAttributeUsage(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("AnalyzeAttributeUsage returned error: %v", err)
+	}
+
+	if report.SegmentsScanned != 2 {
+		t.Errorf("SegmentsScanned = %d, want 2", report.SegmentsScanned)
+	}
+	wantAttributes := []CDPAttributeUsage{
+		{Name: "age", Kind: "attribute"},
+		{Name: "country", Kind: "attribute"},
+		{Name: "gender", Kind: "attribute", Segments: []string{"Women", "Buyers"}},
+	}
+	if !reflect.DeepEqual(report.Attributes, wantAttributes) {
+		t.Errorf("Attributes = %+v, want %+v", report.Attributes, wantAttributes)
+	}
+	if len(report.Behaviors) != 2 || report.Behaviors[0].Name != "pageviews" || report.Behaviors[1].Segments[0] != "Buyers" {
+		t.Errorf("Behaviors = %+v", report.Behaviors)
+	}
+	if len(report.Unknown) != 1 || report.Unknown[0].Name != "legacy_score" {
+		t.Errorf("Unknown = %+v", report.Unknown)
+	}
+
+	var unused []string
+	for _, u := range report.Unused() {
+		unused = append(unused, u.Name)
+	}
+	if want := []string{"age", "country", "pageviews"}; !reflect.DeepEqual(unused, want) {
+		t.Errorf("Unused = %v, want %v", unused, want)
+	}
+}
+
+func BenchmarkCDPService_AnalyzeAttributeUsage(b *testing.B) {
+	client, mux, teardown := setupCDP()
+	defer teardown()
+
+	var attributes, segments strings.Builder
+	attributes.WriteString("[")
+	for i := 0; i < 200; i++ {
+		if i > 0 {
+			attributes.WriteString(",")
+		}
+		fmt.Fprintf(&attributes, `{"name": "attr_%d"}`, i)
+	}
+	attributes.WriteString("]")
+	segments.WriteString("[")
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			segments.WriteString(",")
+		}
+		fmt.Fprintf(&segments, `{"id": "%d", "name": "Segment %d", "rule": {"type": "And", "conditions": [
+			{"type": "Value", "leftValue": {"name": "attr_%d"}, "operator": {"type": "Equal", "rightValue": "x"}},
+			{"type": "Or", "conditions": [
+				{"type": "Value", "leftValue": {"name": "amount", "source": "10"}, "operator": {"type": "Greater", "rightValue": 100}},
+				{"type": "Value", "leftValue": {"name": "attr_%d"}, "operator": {"type": "IsNull", "not": true}}
+			]}
+		]}}`, i, i, i%200, (i*7)%250)
+	}
+	segments.WriteString("]")
+
+	mux.HandleFunc("/audiences/1/attributes", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, attributes.String())
+	})
+	mux.HandleFunc("/audiences/1/behaviors", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "10", "name": "purchases"}]`)
+	})
+	mux.HandleFunc("/audiences/1/segments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, segments.String())
+	})
+
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.CDP.AnalyzeAttributeUsage(ctx, "1"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
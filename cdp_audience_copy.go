@@ -0,0 +1,399 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CDPAudienceExportVersion is the format version written by ExportAudience
+const CDPAudienceExportVersion = 1
+
+// CDPAudienceExport is the portable definition of an audience, used to
+// recreate it in another account or region. Server-assigned IDs are kept so
+// references between folders, segments and activations can be remapped on
+// import; credentials in activation settings are removed.
+type CDPAudienceExport struct {
+	Version     int                    `json:"version"`
+	ExportedAt  time.Time              `json:"exported_at"`
+	Audience    CDPAudienceDefinition  `json:"audience"`
+	Attributes  []CDPAudienceAttribute `json:"attributes"`
+	Behaviors   []CDPAudienceBehavior  `json:"behaviors"`
+	Folders     []CDPAudienceFolder    `json:"folders"`
+	Segments    []CDPSegment           `json:"segments"`
+	Activations []CDPActivation        `json:"activations"`
+}
+
+// CDPAudienceDefinition holds the audience settings carried by an export
+type CDPAudienceDefinition struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	Description    string            `json:"description"`
+	ScheduleType   string            `json:"scheduleType"`
+	ScheduleOption *string           `json:"scheduleOption"`
+	Timezone       string            `json:"timezone"`
+	Master         CDPAudienceMaster `json:"master"`
+}
+
+// CDPAudienceImportOptions controls how an export is recreated
+type CDPAudienceImportOptions struct {
+	// Name overrides the audience name; empty keeps the exported name
+	Name string
+	// ConnectionIDs maps source connection IDs to connections in the target
+	// account. Activations whose connection is not mapped are skipped.
+	ConnectionIDs map[string]string
+	// SkipActivations imports the audience without activations
+	SkipActivations bool
+}
+
+// CDPAudienceImportResult describes the audience created by ImportAudience
+type CDPAudienceImportResult struct {
+	Audience *CDPAudience `json:"audience"`
+	// FolderIDs and SegmentIDs map exported IDs to the created ones
+	FolderIDs  map[string]string `json:"folder_ids"`
+	SegmentIDs map[string]string `json:"segment_ids"`
+	// Activations counts created activations
+	Activations int `json:"activations"`
+	// SkippedActivations names activations that were not recreated and why
+	SkippedActivations []string `json:"skipped_activations,omitempty"`
+}
+
+// credentialKeys are substrings of activation setting names that hold
+// secrets and are dropped on export
+var credentialKeys = []string{"password", "secret", "token", "credential", "api_key", "apikey", "private_key", "access_key"}
+
+// ExportAudience collects the definition of an audience: its settings,
+// attributes, behaviors, folders, segment rules and activations
+func (s *CDPService) ExportAudience(ctx context.Context, audienceID string) (*CDPAudienceExport, error) {
+	audience, err := s.GetAudience(ctx, audienceID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &CDPAudienceExport{
+		Version:    CDPAudienceExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Audience: CDPAudienceDefinition{
+			ID:             audience.ID,
+			Name:           audience.Name,
+			Description:    audience.Description,
+			ScheduleType:   audience.ScheduleType,
+			ScheduleOption: audience.ScheduleOption,
+			Timezone:       audience.Timezone,
+		},
+	}
+	if audience.Master != nil {
+		export.Audience.Master = *audience.Master
+	}
+
+	rawAttributes, err := s.GetAudienceAttributes(ctx, audienceID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(rawAttributes)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &export.Attributes); err != nil {
+		return nil, fmt.Errorf("failed to decode audience attributes: %w", err)
+	}
+
+	if export.Behaviors, err = s.GetAudienceBehaviors(ctx, audienceID); err != nil {
+		return nil, err
+	}
+
+	folders, err := s.ListFolders(ctx, audienceID)
+	if err != nil {
+		return nil, err
+	}
+	export.Folders = folders.Folders
+
+	segments, err := s.ListSegments(ctx, audienceID, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, segment := range segments.Segments {
+		if segment.Rule == nil {
+			full, err := s.GetSegment(ctx, audienceID, segment.ID)
+			if err != nil {
+				return nil, err
+			}
+			segment = *full
+		}
+		export.Segments = append(export.Segments, segment)
+	}
+
+	activations, err := s.ListActivations(ctx, audienceID, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, activation := range activations.Activations {
+		activation.ConnectorConfig = removeCredentials(activation.ConnectorConfig)
+		activation.Configuration = removeCredentials(activation.Configuration)
+		activation.Executions = nil
+		activation.CreatedBy = nil
+		activation.UpdatedBy = nil
+		// Recipients are user IDs of the source account
+		activation.EmailRecipients = nil
+		export.Activations = append(export.Activations, activation)
+	}
+
+	return export, nil
+}
+
+// removeCredentials returns a copy of settings without secret values
+func removeCredentials(settings map[string]interface{}) map[string]interface{} {
+	if settings == nil {
+		return nil
+	}
+	clean := make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		if isCredentialKey(key) {
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			value = removeCredentials(nested)
+		}
+		clean[key] = value
+	}
+	return clean
+}
+
+func isCredentialKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range credentialKeys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// ImportAudience recreates an exported audience through this client, e.g. a
+// client for the production account. The master table must exist in the
+// target account. Folders and segments are created parents and referenced
+// segments first. On error the partial result is returned with the error so
+// the created audience can be inspected or deleted.
+func (s *CDPService) ImportAudience(ctx context.Context, export *CDPAudienceExport, opts *CDPAudienceImportOptions) (*CDPAudienceImportResult, error) {
+	if export.Version != CDPAudienceExportVersion {
+		return nil, fmt.Errorf("unsupported audience export version %d", export.Version)
+	}
+	if opts == nil {
+		opts = &CDPAudienceImportOptions{}
+	}
+
+	name := export.Audience.Name
+	if opts.Name != "" {
+		name = opts.Name
+	}
+
+	audience, err := s.CreateAudience(ctx, name, export.Audience.Description,
+		export.Audience.Master.ParentDatabaseName, export.Audience.Master.ParentTableName)
+	if err != nil {
+		return nil, err
+	}
+	result := &CDPAudienceImportResult{
+		Audience:   audience,
+		FolderIDs:  make(map[string]string),
+		SegmentIDs: make(map[string]string),
+	}
+
+	update := &CDPAudienceUpdateRequest{
+		ScheduleType:   export.Audience.ScheduleType,
+		ScheduleOption: export.Audience.ScheduleOption,
+		Timezone:       export.Audience.Timezone,
+	}
+	for _, attribute := range export.Attributes {
+		attribute.ID, attribute.AudienceID, attribute.MatrixColumnName = "", "", ""
+		update.Attributes = append(update.Attributes, attribute)
+	}
+	for _, behavior := range export.Behaviors {
+		behavior.ID, behavior.AudienceID, behavior.MatrixDatabaseName, behavior.MatrixTableName = "", "", "", ""
+		update.Behaviors = append(update.Behaviors, behavior)
+	}
+	if audience, err = s.UpdateAudience(ctx, audience.ID, update); err != nil {
+		return result, fmt.Errorf("failed to set audience attributes: %w", err)
+	}
+	result.Audience = audience
+
+	if err := s.importFolders(ctx, export, result); err != nil {
+		return result, err
+	}
+	if err := s.importSegments(ctx, export, result); err != nil {
+		return result, err
+	}
+	if opts.SkipActivations {
+		return result, nil
+	}
+
+	for _, activation := range export.Activations {
+		segmentID, ok := result.SegmentIDs[activation.SegmentID]
+		if !ok {
+			result.SkippedActivations = append(result.SkippedActivations, fmt.Sprintf("%s: segment %s was not exported", activation.Name, activation.SegmentID))
+			continue
+		}
+		if activation.ConnectionID != "" {
+			connectionID, ok := opts.ConnectionIDs[activation.ConnectionID]
+			if !ok {
+				result.SkippedActivations = append(result.SkippedActivations, fmt.Sprintf("%s: no target connection for %s", activation.Name, activation.ConnectionID))
+				continue
+			}
+			activation.ConnectionID = connectionID
+		}
+
+		activation.ID = ""
+		activation.AudienceID, activation.Audience_ID = audience.ID, ""
+		activation.SegmentID = segmentID
+		if _, err := s.CreateSegmentActivation(ctx, audience.ID, segmentID, &activation); err != nil {
+			return result, fmt.Errorf("failed to create activation %s: %w", activation.Name, err)
+		}
+		result.Activations++
+	}
+
+	return result, nil
+}
+
+// importFolders recreates exported folders below the target audience's root
+// folder, which stands in for the exported root
+func (s *CDPService) importFolders(ctx context.Context, export *CDPAudienceExport, result *CDPAudienceImportResult) error {
+	existing, err := s.ListFolders(ctx, result.Audience.ID)
+	if err != nil {
+		return err
+	}
+	var targetRoot string
+	for _, folder := range existing.Folders {
+		if folder.ParentFolderID == nil {
+			targetRoot = folder.ID
+		}
+	}
+
+	pending := export.Folders
+	for len(pending) > 0 {
+		var next []CDPAudienceFolder
+		for _, folder := range pending {
+			if folder.ParentFolderID == nil && targetRoot != "" {
+				result.FolderIDs[folder.ID] = targetRoot
+				continue
+			}
+
+			req := &CDPAudienceFolderCreateRequest{Name: folder.Name}
+			if folder.Description != nil {
+				req.Description = *folder.Description
+			}
+			if folder.ParentFolderID != nil {
+				parentID, ok := result.FolderIDs[*folder.ParentFolderID]
+				if !ok {
+					next = append(next, folder)
+					continue
+				}
+				req.ParentID = &parentID
+			}
+
+			created, err := s.CreateAudienceFolder(ctx, result.Audience.ID, req)
+			if err != nil {
+				return fmt.Errorf("failed to create folder %s: %w", folder.Name, err)
+			}
+			result.FolderIDs[folder.ID] = created.ID
+		}
+
+		if len(next) == len(pending) {
+			return fmt.Errorf("folder %s has a parent that was not exported", next[0].Name)
+		}
+		pending = next
+	}
+	return nil
+}
+
+// importSegments creates exported segments, rewriting folder IDs and
+// references to other segments
+func (s *CDPService) importSegments(ctx context.Context, export *CDPAudienceExport, result *CDPAudienceImportResult) error {
+	pending := export.Segments
+	for len(pending) > 0 {
+		var next []CDPSegment
+		for _, segment := range pending {
+			rule, ok, err := remapSegmentReferences(segment.Rule, result.SegmentIDs)
+			if err != nil {
+				return fmt.Errorf("segment %s: %w", segment.Name, err)
+			}
+			if !ok {
+				next = append(next, segment)
+				continue
+			}
+
+			req := &CDPSegmentCreateRequest{
+				Name:            segment.Name,
+				Description:     segment.Description,
+				Realtime:        segment.Realtime,
+				IsVisible:       segment.IsVisible,
+				Kind:            segment.Kind,
+				SegmentFolderID: result.FolderIDs[segment.SegmentFolderID],
+				Rule:            rule,
+			}
+			created, err := s.CreateSegmentWithRequest(ctx, result.Audience.ID, req)
+			if err != nil {
+				return fmt.Errorf("failed to create segment %s: %w", segment.Name, err)
+			}
+			result.SegmentIDs[segment.ID] = created.ID
+		}
+
+		if len(next) == len(pending) {
+			return fmt.Errorf("segment %s references a segment that was not exported", next[0].Name)
+		}
+		pending = next
+	}
+	return nil
+}
+
+// remapSegmentReferences rewrites the IDs of "Reference" conditions using
+// ids. It reports false if a referenced segment has not been created yet.
+func remapSegmentReferences(rule interface{}, ids map[string]string) (interface{}, bool, error) {
+	if rule == nil {
+		return nil, true, nil
+	}
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return nil, false, err
+	}
+	var copied interface{}
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, false, err
+	}
+
+	resolved := true
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			if n["type"] == "Reference" {
+				if id, ok := n["id"].(string); ok {
+					if newID, ok := ids[id]; ok {
+						n["id"] = newID
+					} else {
+						resolved = false
+					}
+				}
+			}
+			for _, child := range n {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range n {
+				walk(child)
+			}
+		}
+	}
+	walk(copied)
+	return copied, resolved, nil
+}
+
+// CopyAudience exports an audience through source and imports it through
+// target, which may use a different account or region
+func CopyAudience(ctx context.Context, source, target *Client, audienceID string, opts *CDPAudienceImportOptions) (*CDPAudienceImportResult, error) {
+	export, err := source.CDP.ExportAudience(ctx, audienceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export audience %s: %w", audienceID, err)
+	}
+	return target.CDP.ImportAudience(ctx, export, opts)
+}
@@ -0,0 +1,134 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCopyAudience(t *testing.T) {
+	source, sourceMux, sourceTeardown := setupCDP()
+	defer sourceTeardown()
+	target, targetMux, targetTeardown := setupCDP()
+	defer targetTeardown()
+
+	sourceMux.HandleFunc("/audiences/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "1", "name": "Customers", "timezone": "Asia/Tokyo", "master": {"parentDatabaseName": "cdp", "parentTableName": "customers"}}`)
+	})
+	sourceMux.HandleFunc("/audiences/1/attributes", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "a1", "audienceId": "1", "name": "gender", "matrixColumnName": "a_gender"}]`)
+	})
+	sourceMux.HandleFunc("/audiences/1/behaviors", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "b1", "name": "purchases", "matrixTableName": "behavior_purchases"}]`)
+	})
+	sourceMux.HandleFunc("/audiences/1/folders/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "f2", "name": "Campaigns", "parentFolderId": "f1"}, {"id": "f1", "name": "Customers", "parentFolderId": null}]`)
+	})
+	sourceMux.HandleFunc("/audiences/1/segments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"id": "s2", "name": "Women buyers", "segmentFolderId": "f2", "rule": {"type": "And", "conditions": [{"type": "Reference", "id": "s1"}]}},
+			{"id": "s1", "name": "Women", "segmentFolderId": "f1", "rule": {"type": "And", "conditions": []}}
+		]`)
+	})
+	sourceMux.HandleFunc("/audiences/1/syndications", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"id": "x1", "name": "To S3", "segmentId": "s2", "connectionId": "c1", "connectorConfig": {"bucket": "b", "aws_secret_access_key": "hidden", "auth": {"token": "hidden"}}},
+			{"id": "x2", "name": "To Ads", "segmentId": "s1", "connectionId": "c2"}
+		]`)
+	})
+
+	targetMux.HandleFunc("/audiences", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["name"] != "Customers (prod)" {
+			t.Errorf("Audience name = %v", body["name"])
+		}
+		fmt.Fprint(w, `{"id": "9", "name": "Customers (prod)"}`)
+	})
+	targetMux.HandleFunc("/audiences/9", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		var update CDPAudienceUpdateRequest
+		json.NewDecoder(r.Body).Decode(&update)
+		if len(update.Attributes) != 1 || update.Attributes[0].ID != "" || update.Attributes[0].MatrixColumnName != "" {
+			t.Errorf("Attributes = %+v, want IDs cleared", update.Attributes)
+		}
+		if len(update.Behaviors) != 1 || update.Behaviors[0].Name != "purchases" || update.Behaviors[0].MatrixTableName != "" {
+			t.Errorf("Behaviors = %+v", update.Behaviors)
+		}
+		fmt.Fprint(w, `{"id": "9", "name": "Customers (prod)"}`)
+	})
+	targetMux.HandleFunc("/audiences/9/folders/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "root", "name": "Customers (prod)", "parentFolderId": null}]`)
+	})
+	targetMux.HandleFunc("/audiences/9/folders", func(w http.ResponseWriter, r *http.Request) {
+		var req CDPAudienceFolderCreateRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.ParentID == nil || *req.ParentID != "root" {
+			t.Errorf("Folder parent = %v, want root", req.ParentID)
+		}
+		fmt.Fprint(w, `{"id": "new-f2"}`)
+	})
+	var created []string
+	targetMux.HandleFunc("/audiences/9/segments", func(w http.ResponseWriter, r *http.Request) {
+		var req CDPSegmentCreateRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		created = append(created, req.Name)
+		switch req.Name {
+		case "Women":
+			if req.SegmentFolderID != "root" {
+				t.Errorf("Folder = %q, want root", req.SegmentFolderID)
+			}
+			fmt.Fprint(w, `{"id": "new-s1"}`)
+		case "Women buyers":
+			if !strings.Contains(mustJSON(t, req.Rule), `"id":"new-s1"`) || req.SegmentFolderID != "new-f2" {
+				t.Errorf("Segment %+v was not remapped", req)
+			}
+			fmt.Fprint(w, `{"id": "new-s2"}`)
+		}
+	})
+	targetMux.HandleFunc("/audiences/9/segments/new-s2/syndications", func(w http.ResponseWriter, r *http.Request) {
+		var activation CDPActivation
+		json.NewDecoder(r.Body).Decode(&activation)
+		if activation.ConnectionID != "prod-c1" || activation.ID != "" {
+			t.Errorf("Activation = %+v", activation)
+		}
+		if _, ok := activation.ConnectorConfig["aws_secret_access_key"]; ok {
+			t.Error("Credentials should not be exported")
+		}
+		if auth := activation.ConnectorConfig["auth"].(map[string]interface{}); len(auth) != 0 {
+			t.Errorf("Nested credentials = %v", auth)
+		}
+		fmt.Fprint(w, `{"id": "new-x1"}`)
+	})
+
+	result, err := CopyAudience(context.Background(), source, target, "1", &CDPAudienceImportOptions{
+		Name:          "Customers (prod)",
+		ConnectionIDs: map[string]string{"c1": "prod-c1"},
+	})
+	if err != nil {
+		t.Fatalf("CopyAudience returned error: %v", err)
+	}
+
+	if strings.Join(created, ",") != "Women,Women buyers" {
+		t.Errorf("Segments created in order %v, want referenced segment first", created)
+	}
+	if result.SegmentIDs["s2"] != "new-s2" || result.FolderIDs["f1"] != "root" {
+		t.Errorf("Result mappings = %+v", result)
+	}
+	if result.Activations != 1 || len(result.SkippedActivations) != 1 || !strings.Contains(result.SkippedActivations[0], "c2") {
+		t.Errorf("Activations = %d, skipped %v", result.Activations, result.SkippedActivations)
+	}
+}
+
+func TestImportAudience_UnsupportedVersion(t *testing.T) {
+	client, _, teardown := setupCDP()
+	defer teardown()
+
+	if _, err := client.CDP.ImportAudience(context.Background(), &CDPAudienceExport{Version: 99}, nil); err == nil {
+		t.Error("Expected error for an unknown export version")
+	}
+}
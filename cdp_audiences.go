@@ -4,10 +4,15 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 )
 
 // CreateAudience creates a new audience
 func (s *CDPService) CreateAudience(ctx context.Context, name, description, parentDatabaseName, parentTableName string) (*CDPAudience, error) {
+	if err := ValidateName(NameKindAudience, name); err != nil {
+		return nil, err
+	}
+
 	u := "audiences"
 
 	body := map[string]interface{}{
@@ -203,7 +208,7 @@ func (s *CDPService) GetAudienceStatistics(ctx context.Context, audienceID strin
 
 // GetAudienceSampleValues retrieves sample values for a specific audience attribute column
 func (s *CDPService) GetAudienceSampleValues(ctx context.Context, audienceID, column string) ([]CDPAudienceSampleValue, error) {
-	u := fmt.Sprintf("audiences/%s/sample_values?column=%s", audienceID, column)
+	u := fmt.Sprintf("audiences/%s/sample_values?column=%s", audienceID, url.QueryEscape(column))
 
 	req, err := s.client.NewCDPRequest("GET", u, nil)
 	if err != nil {
@@ -221,7 +226,7 @@ func (s *CDPService) GetAudienceSampleValues(ctx context.Context, audienceID, co
 
 // GetAudienceBehaviorSampleValues retrieves sample values for a specific audience behavior column
 func (s *CDPService) GetAudienceBehaviorSampleValues(ctx context.Context, audienceID, behaviorID, column string) ([]CDPAudienceSampleValue, error) {
-	u := fmt.Sprintf("audiences/%s/behaviors/%s/sample_values?column=%s", audienceID, behaviorID, column)
+	u := fmt.Sprintf("audiences/%s/behaviors/%s/sample_values?column=%s", audienceID, behaviorID, url.QueryEscape(column))
 
 	req, err := s.client.NewCDPRequest("GET", u, nil)
 	if err != nil {
@@ -241,6 +246,10 @@ func (s *CDPService) GetAudienceBehaviorSampleValues(ctx context.Context, audien
 
 // CreateAudienceFolder creates a new folder for a specific audience
 func (s *CDPService) CreateAudienceFolder(ctx context.Context, audienceID string, req *CDPAudienceFolderCreateRequest) (*CDPAudienceFolder, error) {
+	if err := ValidateName(NameKindFolder, req.Name); err != nil {
+		return nil, err
+	}
+
 	u := fmt.Sprintf("audiences/%s/folders", audienceID)
 
 	request, err := s.client.NewCDPRequest("POST", u, req)
@@ -0,0 +1,106 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BehaviorQueryOptions controls the query generated for a behavior's matrix table
+type BehaviorQueryOptions struct {
+	// Since and Until bound the event time column; zero values leave the range open
+	Since time.Time
+	Until time.Time
+	// Columns lists behavior schema field names to select; empty selects all
+	Columns []string
+	// Limit caps the number of rows; 0 means no limit
+	Limit int
+}
+
+// FindAudienceBehavior returns the audience behavior with the given ID or name
+func (s *CDPService) FindAudienceBehavior(ctx context.Context, audienceID, behavior string) (*CDPAudienceBehavior, error) {
+	behaviors, err := s.GetAudienceBehaviors(ctx, audienceID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range behaviors {
+		if behaviors[i].ID == behavior || behaviors[i].Name == behavior {
+			return &behaviors[i], nil
+		}
+	}
+	return nil, fmt.Errorf("behavior %q not found in audience %s", behavior, audienceID)
+}
+
+// BuildBehaviorQuery returns a Trino query over the behavior's matrix table
+// (MatrixDatabaseName.MatrixTableName), newest events first. Columns are
+// selected by matrix column and labelled with their behavior schema names.
+func BuildBehaviorQuery(behavior *CDPAudienceBehavior, opts BehaviorQueryOptions) (string, error) {
+	if behavior.MatrixDatabaseName == "" || behavior.MatrixTableName == "" {
+		return "", fmt.Errorf("behavior %s has no matrix table; run the audience first", behavior.Name)
+	}
+	if opts.Limit < 0 {
+		return "", fmt.Errorf("limit must not be negative")
+	}
+	if !opts.Since.IsZero() && !opts.Until.IsZero() && !opts.Since.Before(opts.Until) {
+		return "", fmt.Errorf("since must be before until")
+	}
+
+	fields := make(map[string]CDPBehaviorSchemaField, len(behavior.Schema))
+	for _, field := range behavior.Schema {
+		fields[field.Name] = field
+	}
+
+	selectList := "*"
+	if len(opts.Columns) > 0 {
+		var columns []string
+		for _, name := range opts.Columns {
+			field, ok := fields[name]
+			if !ok {
+				return "", fmt.Errorf("behavior %s has no column %q", behavior.Name, name)
+			}
+			column := field.MatrixColumnName
+			if column == "" {
+				column = field.Name
+			}
+			columns = append(columns, fmt.Sprintf("%s AS %s", EscapeIdentifier(column), EscapeIdentifier(field.Name)))
+		}
+		selectList = strings.Join(columns, ", ")
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "SELECT %s\nFROM %s", selectList, QualifiedName(behavior.MatrixDatabaseName, behavior.MatrixTableName))
+
+	if !opts.Since.IsZero() || !opts.Until.IsZero() {
+		fmt.Fprintf(&sb, "\nWHERE TD_TIME_RANGE(time, %s, %s)", TimeRangeBound(opts.Since), TimeRangeBound(opts.Until))
+	}
+	sb.WriteString("\nORDER BY time DESC")
+	if opts.Limit > 0 {
+		fmt.Fprintf(&sb, "\nLIMIT %d", opts.Limit)
+	}
+	return sb.String(), nil
+}
+
+// QueryBehavior issues a Trino job that reads an audience behavior's events.
+// behavior may be the behavior's ID or name. queryOpts may set priority or
+// pool; its Query is replaced by the generated query.
+func (s *CDPService) QueryBehavior(ctx context.Context, audienceID, behavior string, opts BehaviorQueryOptions, queryOpts *IssueQueryOptions) (*IssueQueryResponse, error) {
+	b, err := s.FindAudienceBehavior(ctx, audienceID, behavior)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := BuildBehaviorQuery(b, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	issueOpts := IssueQueryOptions{}
+	if queryOpts != nil {
+		issueOpts = *queryOpts
+	}
+	issueOpts.Query = query
+
+	return s.client.Queries.Issue(ctx, QueryTypeTrino, b.MatrixDatabaseName, &issueOpts)
+}
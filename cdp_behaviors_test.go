@@ -0,0 +1,89 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var testBehavior = CDPAudienceBehavior{
+	ID:                 "42",
+	Name:               "purchases",
+	MatrixDatabaseName: "cdp_audience_123",
+	MatrixTableName:    "behavior_purchases",
+	Schema: []CDPBehaviorSchemaField{
+		{Name: "amount", MatrixColumnName: "amount"},
+		{Name: "Product Name", MatrixColumnName: "product_name"},
+	},
+}
+
+func TestBuildBehaviorQuery(t *testing.T) {
+	query, err := BuildBehaviorQuery(&testBehavior, BehaviorQueryOptions{
+		Since:   time.Unix(1700000000, 0),
+		Columns: []string{"Product Name", "amount"},
+		Limit:   100,
+	})
+	if err != nil {
+		t.Fatalf("BuildBehaviorQuery returned error: %v", err)
+	}
+
+	want := `SELECT "product_name" AS "Product Name", "amount" AS "amount"
+FROM "cdp_audience_123"."behavior_purchases"
+WHERE TD_TIME_RANGE(time, 1700000000, NULL)
+ORDER BY time DESC
+LIMIT 100`
+	if query != want {
+		t.Errorf("BuildBehaviorQuery =\n%s\nwant\n%s", query, want)
+	}
+}
+
+func TestBuildBehaviorQuery_Errors(t *testing.T) {
+	if _, err := BuildBehaviorQuery(&testBehavior, BehaviorQueryOptions{Columns: []string{"missing"}}); err == nil {
+		t.Error("Expected error for an unknown column")
+	}
+	if _, err := BuildBehaviorQuery(&CDPAudienceBehavior{Name: "new"}, BehaviorQueryOptions{}); err == nil {
+		t.Error("Expected error for a behavior without a matrix table")
+	}
+	now := time.Now()
+	if _, err := BuildBehaviorQuery(&testBehavior, BehaviorQueryOptions{Since: now, Until: now.Add(-time.Hour)}); err == nil {
+		t.Error("Expected error when since is after until")
+	}
+}
+
+func TestCDPService_QueryBehavior(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	client.CDPURL = client.BaseURL
+
+	mux.HandleFunc("/audiences/123/behaviors", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		json.NewEncoder(w).Encode([]CDPAudienceBehavior{testBehavior})
+	})
+	mux.HandleFunc("/v3/job/issue/trino/cdp_audience_123", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		var opts IssueQueryOptions
+		json.NewDecoder(r.Body).Decode(&opts)
+		if opts.Query != "SELECT *\nFROM \"cdp_audience_123\".\"behavior_purchases\"\nORDER BY time DESC\nLIMIT 10" {
+			t.Errorf("Query = %q", opts.Query)
+		}
+		if opts.Priority != 1 {
+			t.Errorf("Priority = %d, want 1", opts.Priority)
+		}
+		fmt.Fprint(w, `{"job_id": "789", "database": "cdp_audience_123"}`)
+	})
+
+	resp, err := client.CDP.QueryBehavior(context.Background(), "123", "purchases", BehaviorQueryOptions{Limit: 10}, &IssueQueryOptions{Priority: 1})
+	if err != nil {
+		t.Fatalf("QueryBehavior returned error: %v", err)
+	}
+	if resp.JobID != "789" {
+		t.Errorf("JobID = %q, want 789", resp.JobID)
+	}
+
+	if _, err := client.CDP.QueryBehavior(context.Background(), "123", "unknown", BehaviorQueryOptions{}, nil); err == nil {
+		t.Error("Expected error for an unknown behavior")
+	}
+}
@@ -60,6 +60,10 @@ func (s *CDPService) CreateEntityFolderWithParams(ctx context.Context, name, des
 
 // CreateEntityFolder creates a new entity folder using JSON API format
 func (s *CDPService) CreateEntityFolder(ctx context.Context, req *CDPFolderCreateRequest) (*CDPFolder, error) {
+	if err := ValidateName(NameKindFolder, req.Name); err != nil {
+		return nil, err
+	}
+
 	u := "entities/folders"
 
 	// Convert to JSON API format
@@ -0,0 +1,243 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SegmentReferences returns the activations, funnels and journeys that refer
+// to a segment. They refer to it by ID, but activation connector settings and
+// funnel or journey stage names may repeat its name.
+func (s *CDPService) SegmentReferences(ctx context.Context, audienceID, segmentID string) ([]RenameReference, error) {
+	segment, err := s.GetSegment(ctx, audienceID, segmentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.segmentReferences(ctx, audienceID, segment, "")
+}
+
+// RenameSegment renames a segment and reports what refers to it. With
+// opts.Cascade, funnel stages named after the segment are renamed too.
+func (s *CDPService) RenameSegment(ctx context.Context, audienceID, segmentID, newName string, opts *RenameOptions) (*RenameReport, error) {
+	if err := ValidateName(NameKindSegment, newName); err != nil {
+		return nil, err
+	}
+	segment, err := s.GetSegment(ctx, audienceID, segmentID)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := s.segmentReferences(ctx, audienceID, segment, newName)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RenameReport{OldName: segment.Name, NewName: newName, References: refs}
+	return applyRename(report, opts, func() error {
+		_, err := s.UpdateSegment(ctx, audienceID, segmentID, map[string]string{"name": newName})
+		return err
+	})
+}
+
+func (s *CDPService) segmentReferences(ctx context.Context, audienceID string, segment *CDPSegment, newName string) ([]RenameReference, error) {
+	refs := []RenameReference{}
+
+	activations, err := s.ListSegmentActivations(ctx, segment.ID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list activations: %w", err)
+	}
+	for _, activation := range activations.Activations {
+		ref := RenameReference{Kind: "activation", ID: activation.ID, Name: activation.Name, Detail: "refers to the segment by ID"}
+		keys := settingsContaining(activation.ConnectorConfig, segment.Name)
+		keys = append(keys, settingsContaining(activation.Configuration, segment.Name)...)
+		if len(keys) > 0 {
+			// Destination settings such as an ad audience name are not
+			// rewritten: changing them usually creates a new destination
+			ref.Breaks = true
+			ref.Detail = fmt.Sprintf("settings %s contain the segment name", strings.Join(keys, ", "))
+		}
+		refs = append(refs, ref)
+	}
+
+	funnels, err := s.ListFunnels(ctx, audienceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list funnels: %w", err)
+	}
+	for _, funnel := range funnels {
+		for i, stage := range funnel.Stages {
+			if stage.SegmentID != segment.ID {
+				continue
+			}
+			ref := RenameReference{Kind: "funnel", ID: funnel.ID, Name: funnel.Name, Detail: fmt.Sprintf("stage %q uses the segment", stage.Name)}
+			if stage.Name == segment.Name {
+				ref.Breaks = true
+				ref.Detail = fmt.Sprintf("stage %d is named after the segment", i+1)
+				funnel, i := funnel, i
+				ref.cascade = func() error {
+					return s.renameFunnelStage(ctx, audienceID, funnel, i, newName)
+				}
+			}
+			refs = append(refs, ref)
+		}
+	}
+
+	if segment.SegmentFolderID != "" {
+		journeys, err := s.ListJourneys(ctx, segment.SegmentFolderID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list journeys: %w", err)
+		}
+		for _, journey := range journeys.Data {
+			if journey.Attributes == nil {
+				continue
+			}
+			for _, stage := range journey.Attributes.JourneyStages {
+				if !ruleReferencesSegment(stage.Rule, segment.ID) {
+					continue
+				}
+				ref := RenameReference{Kind: "journey", ID: journey.ID, Name: journey.Attributes.Name, Detail: fmt.Sprintf("stage %q uses the segment", stage.Name)}
+				if stage.Name == segment.Name {
+					ref.Breaks = true
+					ref.Detail = fmt.Sprintf("stage %q is named after the segment", stage.Name)
+				}
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs, nil
+}
+
+// renameFunnelStage renames one stage of a funnel, keeping the rest
+func (s *CDPService) renameFunnelStage(ctx context.Context, audienceID string, funnel CDPFunnel, stage int, newName string) error {
+	folderID, err := strconv.ParseInt(funnel.SegmentFolderID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("funnel %s: invalid segment folder ID %q", funnel.ID, funnel.SegmentFolderID)
+	}
+	stages := make([]CDPFunnelStage, len(funnel.Stages))
+	copy(stages, funnel.Stages)
+	stages[stage].Name = newName
+
+	_, err = s.UpdateFunnel(ctx, audienceID, funnel.ID, CDPFunnelCreateRequest{
+		Name:            funnel.Name,
+		Description:     funnel.Description,
+		SegmentFolderID: folderID,
+		Stages:          stages,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update funnel %s: %w", funnel.ID, err)
+	}
+	return nil
+}
+
+// FolderReferences returns the funnels and journeys built on a segment
+// folder. Both refer to the folder by ID.
+func (s *CDPService) FolderReferences(ctx context.Context, audienceID, folderID string) ([]RenameReference, error) {
+	refs := []RenameReference{}
+
+	funnels, err := s.ListFunnels(ctx, audienceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list funnels: %w", err)
+	}
+	for _, funnel := range funnels {
+		if funnel.SegmentFolderID == folderID {
+			refs = append(refs, RenameReference{Kind: "funnel", ID: funnel.ID, Name: funnel.Name, Detail: "refers to the folder by ID"})
+		}
+	}
+
+	journeys, err := s.ListJourneys(ctx, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journeys: %w", err)
+	}
+	for _, journey := range journeys.Data {
+		name := ""
+		if journey.Attributes != nil {
+			name = journey.Attributes.Name
+		}
+		refs = append(refs, RenameReference{Kind: "journey", ID: journey.ID, Name: name, Detail: "refers to the folder by ID"})
+	}
+	return refs, nil
+}
+
+// RenameAudienceFolder renames a segment folder of an audience and reports
+// what refers to it
+func (s *CDPService) RenameAudienceFolder(ctx context.Context, audienceID, folderID, newName string, opts *RenameOptions) (*RenameReport, error) {
+	if err := ValidateName(NameKindFolder, newName); err != nil {
+		return nil, err
+	}
+	folder, err := s.GetAudienceFolder(ctx, audienceID, folderID)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := s.FolderReferences(ctx, audienceID, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RenameReport{OldName: folder.Name, NewName: newName, References: refs}
+	return applyRename(report, opts, func() error {
+		_, err := s.UpdateAudienceFolder(ctx, audienceID, folderID, &CDPAudienceFolderUpdateRequest{Name: newName})
+		return err
+	})
+}
+
+// settingsContaining returns the sorted keys of settings whose string values,
+// at any depth, contain name
+func settingsContaining(settings map[string]interface{}, name string) []string {
+	var keys []string
+	for key, value := range settings {
+		if valueContains(value, name) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func valueContains(value interface{}, name string) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.Contains(v, name)
+	case map[string]interface{}:
+		for _, child := range v {
+			if valueContains(child, name) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if valueContains(child, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ruleReferencesSegment reports whether a raw journey or segment rule has a
+// segmentId (or segment_id) equal to segmentID
+func ruleReferencesSegment(rule interface{}, segmentID string) bool {
+	switch r := rule.(type) {
+	case map[string]interface{}:
+		for key, value := range r {
+			if key == "segmentId" || key == "segment_id" {
+				if fmt.Sprint(value) == segmentID {
+					return true
+				}
+				if f, ok := value.(float64); ok && strconv.FormatFloat(f, 'f', -1, 64) == segmentID {
+					return true
+				}
+			}
+			if ruleReferencesSegment(value, segmentID) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, child := range r {
+			if ruleReferencesSegment(child, segmentID) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCDPService_RenameSegment(t *testing.T) {
+	client, mux, teardown := setupCDP()
+	defer teardown()
+
+	var renamed string
+	mux.HandleFunc("/audiences/1/segments/10", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			renamed = body["name"]
+		}
+		fmt.Fprint(w, `{"id": "10", "audienceId": "1", "name": "Buyers", "segmentFolderId": "5"}`)
+	})
+	mux.HandleFunc("/entities/segments/10/syndications", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": [
+			{"id": "100", "name": "Export", "connectorConfig": {"table": "buyers"}},
+			{"id": "101", "name": "Ads", "connectorConfig": {"audience": {"name": "Buyers"}}}
+		]}`)
+	})
+	var funnelUpdate CDPFunnelCreateRequest
+	mux.HandleFunc("/audiences/1/funnels", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "20", "name": "Path", "segmentFolderId": "5", "stages": [
+			{"name": "Visitors", "segmentId": "9"}, {"name": "Buyers", "segmentId": "10"}, {"name": "Repeat", "segmentId": "11"}
+		]}]`)
+	})
+	mux.HandleFunc("/audiences/1/funnels/20", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		json.NewDecoder(r.Body).Decode(&funnelUpdate)
+		fmt.Fprint(w, `{"id": "20"}`)
+	})
+	mux.HandleFunc("/entities/journeys", func(w http.ResponseWriter, r *http.Request) {
+		testURL(t, r, "/entities/journeys?folder_id=5")
+		fmt.Fprint(w, `{"data": [{"id": "30", "attributes": {"name": "Onboarding", "journeyStages": [
+			{"name": "Buyers", "rule": {"type": "And", "conditions": [{"type": "Reference", "segmentId": 10}]}},
+			{"name": "Other", "rule": {"segmentId": 99}}
+		]}}]}`)
+	})
+
+	ctx := context.Background()
+	report, err := client.CDP.RenameSegment(ctx, "1", "10", "Buyers 2025", &RenameOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("CDP.RenameSegment returned error: %v", err)
+	}
+	if report.Renamed || renamed != "" {
+		t.Error("dry run renamed the segment")
+	}
+	if len(report.References) != 4 {
+		t.Fatalf("References = %+v, want 4", report.References)
+	}
+	var breaking []string
+	for _, ref := range report.Breaking() {
+		breaking = append(breaking, ref.Kind+":"+ref.ID)
+	}
+	if fmt.Sprint(breaking) != "[activation:101 funnel:20 journey:30]" {
+		t.Errorf("Breaking() = %v", breaking)
+	}
+
+	report, err = client.CDP.RenameSegment(ctx, "1", "10", "Buyers 2025", &RenameOptions{Cascade: true})
+	if err != nil {
+		t.Fatalf("CDP.RenameSegment returned error: %v", err)
+	}
+	if !report.Renamed || renamed != "Buyers 2025" {
+		t.Errorf("renamed = %q, Renamed = %v", renamed, report.Renamed)
+	}
+	if funnelUpdate.SegmentFolderID != 5 || funnelUpdate.Stages[1].Name != "Buyers 2025" || funnelUpdate.Stages[0].Name != "Visitors" {
+		t.Errorf("funnel update = %+v", funnelUpdate)
+	}
+	if got := len(report.Breaking()); got != 2 {
+		t.Errorf("Breaking() after cascade has %d references, want 2", got)
+	}
+
+	if _, err := client.CDP.RenameSegment(ctx, "1", "10", "", nil); err == nil {
+		t.Error("CDP.RenameSegment expected error for an empty name")
+	}
+}
+
+func TestCDPService_RenameAudienceFolder(t *testing.T) {
+	client, mux, teardown := setupCDP()
+	defer teardown()
+
+	mux.HandleFunc("/audiences/1/folders/5", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			fmt.Fprint(w, `{"id": "5", "name": "Campaigns"}`)
+			return
+		}
+		fmt.Fprint(w, `{"id": "5", "name": "Marketing"}`)
+	})
+	mux.HandleFunc("/audiences/1/funnels", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "20", "name": "Path", "segmentFolderId": "5"}, {"id": "21", "name": "Elsewhere", "segmentFolderId": "6"}]`)
+	})
+	mux.HandleFunc("/entities/journeys", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": [{"id": "30", "attributes": {"name": "Onboarding"}}]}`)
+	})
+
+	report, err := client.CDP.RenameAudienceFolder(context.Background(), "1", "5", "Campaigns", nil)
+	if err != nil {
+		t.Fatalf("CDP.RenameAudienceFolder returned error: %v", err)
+	}
+	if report.OldName != "Marketing" || !report.Renamed {
+		t.Errorf("report = %+v", report)
+	}
+	if len(report.References) != 2 || len(report.Breaking()) != 0 {
+		t.Errorf("References = %+v, want funnel 20 and journey 30 by ID", report.References)
+	}
+}
@@ -0,0 +1,130 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// UnmarshalJSON accepts the API's [value, frequency] array as well as the
+// {"value": ..., "frequency": ...} form produced by MarshalJSON
+func (v *CDPAudienceSampleValue) UnmarshalJSON(data []byte) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(data, &pair); err != nil {
+		type plain CDPAudienceSampleValue
+		var p plain
+		if err := json.Unmarshal(data, &p); err != nil {
+			return fmt.Errorf("invalid sample value %s: %w", data, err)
+		}
+		*v = CDPAudienceSampleValue(p)
+		return nil
+	}
+
+	if len(pair) != 2 {
+		return fmt.Errorf("invalid sample value %s: want [value, frequency]", data)
+	}
+	var value interface{}
+	if err := json.Unmarshal(pair[0], &value); err != nil {
+		return err
+	}
+	var frequency float64
+	if err := json.Unmarshal(pair[1], &frequency); err != nil {
+		return fmt.Errorf("invalid sample value frequency %s: %w", pair[1], err)
+	}
+	v.Value = value
+	v.Frequency = int64(frequency)
+	return nil
+}
+
+// String formats the value for display; null values are empty
+func (v CDPAudienceSampleValue) String() string {
+	switch value := v.Value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// CDPSampleValueListOptions selects a page of sample values
+type CDPSampleValueListOptions struct {
+	Limit  int
+	Offset int
+}
+
+// CDPSampleValuePage is one page of sample values. The API returns all
+// samples for a column at once, so pages are cut from that list.
+type CDPSampleValuePage struct {
+	Values []CDPAudienceSampleValue `json:"values"`
+	Total  int                      `json:"total"`
+	Offset int                      `json:"offset"`
+	// HasMore reports whether values remain after this page
+	HasMore bool `json:"has_more"`
+}
+
+// ListAudienceSampleValues returns a page of sample values for an audience
+// attribute column
+func (s *CDPService) ListAudienceSampleValues(ctx context.Context, audienceID, column string, opts *CDPSampleValueListOptions) (*CDPSampleValuePage, error) {
+	values, err := s.GetAudienceSampleValues(ctx, audienceID, column)
+	if err != nil {
+		return nil, err
+	}
+	return pageSampleValues(values, opts)
+}
+
+// ListAudienceBehaviorSampleValues returns a page of sample values for an
+// audience behavior column
+func (s *CDPService) ListAudienceBehaviorSampleValues(ctx context.Context, audienceID, behaviorID, column string, opts *CDPSampleValueListOptions) (*CDPSampleValuePage, error) {
+	values, err := s.GetAudienceBehaviorSampleValues(ctx, audienceID, behaviorID, column)
+	if err != nil {
+		return nil, err
+	}
+	return pageSampleValues(values, opts)
+}
+
+func pageSampleValues(values []CDPAudienceSampleValue, opts *CDPSampleValueListOptions) (*CDPSampleValuePage, error) {
+	page := &CDPSampleValuePage{Total: len(values)}
+	if opts == nil {
+		page.Values = values
+		return page, nil
+	}
+	if opts.Limit < 0 || opts.Offset < 0 {
+		return nil, fmt.Errorf("limit and offset must not be negative")
+	}
+
+	start := opts.Offset
+	if start > len(values) {
+		start = len(values)
+	}
+	end := len(values)
+	if opts.Limit > 0 && start+opts.Limit < end {
+		end = start + opts.Limit
+	}
+
+	page.Values = values[start:end]
+	page.Offset = start
+	page.HasMore = end < len(values)
+	return page, nil
+}
+
+// WriteSampleValuesCSV writes sample values as CSV with a value,frequency header
+func WriteSampleValuesCSV(w io.Writer, values []CDPAudienceSampleValue) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"value", "frequency"}); err != nil {
+		return err
+	}
+	for _, v := range values {
+		if err := cw.Write([]string{v.String(), strconv.FormatInt(v.Frequency, 10)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,71 @@
+package treasuredata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCDPAudienceSampleValue_UnmarshalJSON(t *testing.T) {
+	var values []CDPAudienceSampleValue
+	if err := json.Unmarshal([]byte(`[["Tokyo", 120], [null, 7], [3.5, 2], {"value": "Osaka", "frequency": 1}]`), &values); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	want := []struct {
+		str       string
+		frequency int64
+	}{{"Tokyo", 120}, {"", 7}, {"3.5", 2}, {"Osaka", 1}}
+	for i, w := range want {
+		if values[i].String() != w.str || values[i].Frequency != w.frequency {
+			t.Errorf("values[%d] = %q/%d, want %q/%d", i, values[i].String(), values[i].Frequency, w.str, w.frequency)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`[["only value"]]`), &values); err == nil {
+		t.Error("Expected error for a malformed pair")
+	}
+}
+
+func TestCDPService_ListAudienceSampleValues(t *testing.T) {
+	client, mux, teardown := setupCDP()
+	defer teardown()
+
+	mux.HandleFunc("/audiences/1/sample_values", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got := r.URL.Query().Get("column"); got != "home city" {
+			t.Errorf("column = %q, want %q", got, "home city")
+		}
+		fmt.Fprint(w, `[["a", 5], ["b", 4], ["c", 3], ["d", 2], ["e", 1]]`)
+	})
+
+	page, err := client.CDP.ListAudienceSampleValues(context.Background(), "1", "home city", &CDPSampleValueListOptions{Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatalf("ListAudienceSampleValues returned error: %v", err)
+	}
+	if page.Total != 5 || page.Offset != 2 || !page.HasMore || len(page.Values) != 2 || page.Values[0].String() != "c" {
+		t.Errorf("Page = %+v, want values c,d of 5 with more", page)
+	}
+
+	page, err = client.CDP.ListAudienceSampleValues(context.Background(), "1", "home city", &CDPSampleValueListOptions{Offset: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.HasMore || len(page.Values) != 1 {
+		t.Errorf("Last page = %+v, want one value and no more", page)
+	}
+}
+
+func TestWriteSampleValuesCSV(t *testing.T) {
+	var buf bytes.Buffer
+	values := []CDPAudienceSampleValue{{Value: "Tokyo, JP", Frequency: 3}, {Value: nil, Frequency: 1}}
+	if err := WriteSampleValuesCSV(&buf, values); err != nil {
+		t.Fatal(err)
+	}
+	if want := "value,frequency\n\"Tokyo, JP\",3\n,1\n"; buf.String() != want {
+		t.Errorf("CSV = %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,64 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// defaultSegmentDeleteConcurrency is the number of segments DeleteSegments
+// deletes at once by default
+const defaultSegmentDeleteConcurrency = 4
+
+// ListSegmentsByPrefix returns every segment of an audience whose name starts
+// with prefix. The prefix is case sensitive and cannot be empty, so that a
+// bulk delete never selects a whole audience by accident.
+func (s *CDPService) ListSegmentsByPrefix(ctx context.Context, audienceID, prefix string) ([]CDPSegment, error) {
+	if prefix == "" {
+		return nil, NewValidationError("prefix", prefix, "cannot be empty")
+	}
+
+	var matched []CDPSegment
+	it := s.ListAllSegments(ctx, audienceID, nil)
+	for it.Next(ctx) {
+		if segment := it.Value(); strings.HasPrefix(segment.Name, prefix) {
+			matched = append(matched, segment)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return matched, nil
+}
+
+// CDPSegmentDeletion is the outcome of deleting one segment
+type CDPSegmentDeletion struct {
+	Segment CDPSegment `json:"segment"`
+	Err     error      `json:"-"`
+}
+
+// DeleteSegments deletes segments of an audience, concurrency at a time
+// (default 4). Every segment gets a CDPSegmentDeletion in order; if any
+// could not be deleted, a *BatchError is also returned.
+func (s *CDPService) DeleteSegments(ctx context.Context, audienceID string, segments []CDPSegment, concurrency int) ([]CDPSegmentDeletion, error) {
+	if concurrency <= 0 {
+		concurrency = defaultSegmentDeleteConcurrency
+	}
+	_, err := BatchMap(ctx, concurrency, segments, func(ctx context.Context, segment CDPSegment) (struct{}, error) {
+		if err := s.DeleteSegment(ctx, audienceID, segment.ID); err != nil {
+			return struct{}{}, fmt.Errorf("segment %s (%s): %w", segment.ID, segment.Name, err)
+		}
+		return struct{}{}, nil
+	})
+
+	deletions := make([]CDPSegmentDeletion, len(segments))
+	for i, segment := range segments {
+		deletions[i] = CDPSegmentDeletion{Segment: segment}
+	}
+	if batchErr, ok := err.(*BatchError); ok {
+		for _, taskErr := range batchErr.Errors {
+			deletions[taskErr.Index].Err = taskErr.Err
+		}
+	}
+	return deletions, err
+}
@@ -0,0 +1,77 @@
+package treasuredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestCDPService_ListSegmentsByPrefix(t *testing.T) {
+	client, mux, teardown := setupCDP()
+	defer teardown()
+
+	mux.HandleFunc("/audiences/1/segments", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if r.URL.Query().Get("offset") != "" {
+			fmt.Fprint(w, `[]`)
+			return
+		}
+		fmt.Fprint(w, `[{"id": "10", "name": "tmp_a"}, {"id": "11", "name": "Daily buyers"}, {"id": "12", "name": "tmp_b"}, {"id": "13", "name": "TMP_c"}]`)
+	})
+
+	segments, err := client.CDP.ListSegmentsByPrefix(context.Background(), "1", "tmp_")
+	if err != nil {
+		t.Fatalf("CDP.ListSegmentsByPrefix returned error: %v", err)
+	}
+	var ids []string
+	for _, segment := range segments {
+		ids = append(ids, segment.ID)
+	}
+	if fmt.Sprint(ids) != "[10 12]" {
+		t.Errorf("CDP.ListSegmentsByPrefix returned %v, want [10 12]", ids)
+	}
+
+	if _, err := client.CDP.ListSegmentsByPrefix(context.Background(), "1", ""); err == nil {
+		t.Error("CDP.ListSegmentsByPrefix expected error for an empty prefix")
+	}
+}
+
+func TestCDPService_DeleteSegments(t *testing.T) {
+	client, mux, teardown := setupCDP()
+	defer teardown()
+
+	var mu sync.Mutex
+	var deleted []string
+	mux.HandleFunc("/audiences/1/segments/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		id := r.URL.Path[len("/audiences/1/segments/"):]
+		if id == "12" {
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"error": "segment has activations"}`)
+			return
+		}
+		mu.Lock()
+		deleted = append(deleted, id)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	segments := []CDPSegment{{ID: "10", Name: "tmp_a"}, {ID: "12", Name: "tmp_b"}, {ID: "14", Name: "tmp_c"}}
+	deletions, err := client.CDP.DeleteSegments(context.Background(), "1", segments, 2)
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Errors) != 1 || batchErr.Errors[0].Index != 1 {
+		t.Fatalf("CDP.DeleteSegments returned error %v, want a BatchError for segment 12", err)
+	}
+	if len(deletions) != 3 || deletions[0].Err != nil || deletions[1].Err == nil || deletions[2].Err != nil {
+		t.Errorf("CDP.DeleteSegments returned %+v", deletions)
+	}
+	sort.Strings(deleted)
+	if fmt.Sprint(deleted) != "[10 14]" {
+		t.Errorf("deleted %v, want [10 14]", deleted)
+	}
+}
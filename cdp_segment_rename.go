@@ -0,0 +1,176 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CDPAttributeRename describes a column rename applied to segment rules
+type CDPAttributeRename struct {
+	From string
+	To   string
+	// Behavior limits the rename to columns of this behavior, matched by the
+	// source name used in rules. Empty renames audience attributes.
+	Behavior string
+}
+
+// CDPSegmentRuleChange is a segment whose rule references a renamed column
+type CDPSegmentRuleChange struct {
+	SegmentID    string      `json:"segment_id"`
+	SegmentName  string      `json:"segment_name"`
+	Replacements int         `json:"replacements"`
+	Before       interface{} `json:"before"`
+	After        interface{} `json:"after"`
+}
+
+// Diff returns the changed lines of the indented rule JSON, prefixed with
+// "-" for the current rule and "+" for the rewritten one
+func (c *CDPSegmentRuleChange) Diff() string {
+	before, _ := json.MarshalIndent(c.Before, "", "  ")
+	after, _ := json.MarshalIndent(c.After, "", "  ")
+	beforeLines := strings.Split(string(before), "\n")
+	afterLines := strings.Split(string(after), "\n")
+
+	// A rename never changes the rule's shape, so lines correspond one to one
+	var sb strings.Builder
+	for i := 0; i < len(beforeLines) && i < len(afterLines); i++ {
+		if beforeLines[i] != afterLines[i] {
+			fmt.Fprintf(&sb, "-%s\n+%s\n", beforeLines[i], afterLines[i])
+		}
+	}
+	return sb.String()
+}
+
+// RenameRuleAttribute returns a copy of a raw segment rule with every
+// condition on rename.From rewritten to test rename.To, and the number of
+// conditions changed. Fields the SDK does not model are preserved.
+func RenameRuleAttribute(rule interface{}, rename CDPAttributeRename) (interface{}, int, error) {
+	if rename.From == "" || rename.To == "" {
+		return nil, 0, fmt.Errorf("rename requires both the old and the new column name")
+	}
+	if rename.From == rename.To {
+		return nil, 0, fmt.Errorf("old and new column names are the same")
+	}
+
+	// Round-trip through JSON to get a deep copy made of maps and slices
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to encode segment rule: %w", err)
+	}
+	var copied interface{}
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode segment rule: %w", err)
+	}
+
+	count := renameInRule(copied, rename)
+	return copied, count, nil
+}
+
+func renameInRule(node interface{}, rename CDPAttributeRename) int {
+	count := 0
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if left, ok := n["leftValue"].(map[string]interface{}); ok {
+			if left["name"] == rename.From && ruleValueSource(left["source"]) == rename.Behavior {
+				left["name"] = rename.To
+				count++
+			}
+		}
+		for _, child := range n {
+			count += renameInRule(child, rename)
+		}
+	case []interface{}:
+		for _, child := range n {
+			count += renameInRule(child, rename)
+		}
+	}
+	return count
+}
+
+// ruleValueSource returns the behavior name of a raw leftValue source, which
+// rules store either as a string or as {"name": ...}
+func ruleValueSource(source interface{}) string {
+	switch s := source.(type) {
+	case string:
+		return s
+	case map[string]interface{}:
+		name, _ := s["name"].(string)
+		return name
+	}
+	return ""
+}
+
+// UpdateSegmentRule replaces the rule of a segment
+func (s *CDPService) UpdateSegmentRule(ctx context.Context, audienceID, segmentID string, rule interface{}) (*CDPSegment, error) {
+	u := fmt.Sprintf("audiences/%s/segments/%s", audienceID, segmentID)
+
+	req, err := s.client.NewCDPRequest("PUT", u, map[string]interface{}{"rule": rule})
+	if err != nil {
+		return nil, err
+	}
+
+	var segment CDPSegment
+	_, err = s.client.Do(ctx, req, &segment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &segment, nil
+}
+
+// RenameSegmentAttribute rewrites the rules of every segment in an audience
+// that reference rename.From, e.g. after a master segment column was renamed.
+// With dryRun the affected segments are returned without being updated.
+// Segments are updated one at a time; on error the changes applied so far
+// are returned along with the error.
+func (s *CDPService) RenameSegmentAttribute(ctx context.Context, audienceID string, rename CDPAttributeRename, dryRun bool) ([]CDPSegmentRuleChange, error) {
+	if rename.From == "" || rename.To == "" {
+		return nil, fmt.Errorf("rename requires both the old and the new column name")
+	}
+
+	segments, err := s.ListSegments(ctx, audienceID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []CDPSegmentRuleChange
+	for _, segment := range segments.Segments {
+		if segment.Rule == nil {
+			full, err := s.GetSegment(ctx, audienceID, segment.ID)
+			if err != nil {
+				return nil, err
+			}
+			segment = *full
+		}
+		if segment.Rule == nil {
+			continue
+		}
+
+		after, count, err := RenameRuleAttribute(segment.Rule, rename)
+		if err != nil {
+			return nil, fmt.Errorf("segment %s: %w", segment.ID, err)
+		}
+		if count > 0 {
+			changes = append(changes, CDPSegmentRuleChange{
+				SegmentID:    segment.ID,
+				SegmentName:  segment.Name,
+				Replacements: count,
+				Before:       segment.Rule,
+				After:        after,
+			})
+		}
+	}
+
+	if dryRun {
+		return changes, nil
+	}
+
+	for i, change := range changes {
+		if _, err := s.UpdateSegmentRule(ctx, audienceID, change.SegmentID, change.After); err != nil {
+			return changes[:i], fmt.Errorf("failed to update segment %s: %w", change.SegmentID, err)
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,105 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const testRenameRule = `{"type": "And", "conditions": [
+	{"type": "Value", "leftValue": {"name": "zip", "visibility": "clear"}, "operator": {"type": "Equal", "rightValue": "100"}},
+	{"type": "Or", "conditions": [
+		{"type": "Value", "leftValue": {"name": "zip", "source": "purchases"}, "operator": {"type": "IsNull"}},
+		{"type": "Value", "leftValue": {"name": "zip"}, "operator": {"type": "Equal", "rightValue": "zip"}}
+	]}
+]}`
+
+func TestRenameRuleAttribute(t *testing.T) {
+	var rule interface{}
+	json.Unmarshal([]byte(testRenameRule), &rule)
+
+	renamed, count, err := RenameRuleAttribute(rule, CDPAttributeRename{From: "zip", To: "postal_code"})
+	if err != nil {
+		t.Fatalf("RenameRuleAttribute returned error: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("count = %d, want 2 (behavior column and values untouched)", count)
+	}
+
+	parsed, _ := ParseSegmentRule(renamed)
+	refs := parsed.References()
+	if len(refs) != 2 || refs[0].Name != "postal_code" || refs[1].Name != "zip" || refs[1].Behavior != "purchases" {
+		t.Errorf("References after rename = %+v", refs)
+	}
+	if !strings.Contains(mustJSON(t, renamed), `"visibility":"clear"`) {
+		t.Error("Unmodelled fields should be preserved")
+	}
+	if strings.Contains(mustJSON(t, rule), "postal_code") {
+		t.Error("Original rule should not be modified")
+	}
+
+	_, count, _ = RenameRuleAttribute(rule, CDPAttributeRename{From: "zip", To: "postal_code", Behavior: "purchases"})
+	if count != 1 {
+		t.Errorf("Behavior rename count = %d, want 1", count)
+	}
+
+	if _, _, err := RenameRuleAttribute(rule, CDPAttributeRename{From: "zip", To: "zip"}); err == nil {
+		t.Error("Expected error for an identical name")
+	}
+}
+
+func TestCDPService_RenameSegmentAttribute(t *testing.T) {
+	client, mux, teardown := setupCDP()
+	defer teardown()
+
+	mux.HandleFunc("/audiences/1/segments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[{"id": "10", "name": "Tokyo", "rule": %s}, {"id": "11", "name": "All", "rule": {"type": "And", "conditions": []}}]`, testRenameRule)
+	})
+	var updates int
+	mux.HandleFunc("/audiences/1/segments/10", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		updates++
+		var body struct {
+			Rule CDPSegmentRule `json:"rule"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Rule.Conditions[0].LeftValue.Name != "postal_code" {
+			t.Errorf("Updated rule = %+v", body.Rule)
+		}
+		fmt.Fprint(w, `{"id": "10", "name": "Tokyo"}`)
+	})
+	mux.HandleFunc("/audiences/1/segments/11", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Unaffected segment should not be updated")
+	})
+
+	rename := CDPAttributeRename{From: "zip", To: "postal_code"}
+	changes, err := client.CDP.RenameSegmentAttribute(context.Background(), "1", rename, true)
+	if err != nil {
+		t.Fatalf("RenameSegmentAttribute returned error: %v", err)
+	}
+	if len(changes) != 1 || changes[0].SegmentName != "Tokyo" || updates != 0 {
+		t.Fatalf("Dry run changes = %+v, updates = %d", changes, updates)
+	}
+	if diff := changes[0].Diff(); strings.Count(diff, "+") != 2 || !strings.Contains(diff, `+        "name": "postal_code"`) {
+		t.Errorf("Diff =\n%s", diff)
+	}
+
+	if _, err := client.CDP.RenameSegmentAttribute(context.Background(), "1", rename, false); err != nil {
+		t.Fatalf("RenameSegmentAttribute returned error: %v", err)
+	}
+	if updates != 1 {
+		t.Errorf("updates = %d, want 1", updates)
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,121 @@
+package treasuredata
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CDPSegmentRule is a typed view of a segment rule tree. Group nodes ("And",
+// "Or") hold Conditions; leaf nodes ("Value") compare LeftValue using Operator.
+type CDPSegmentRule struct {
+	Type       string           `json:"type"`
+	Conditions []CDPSegmentRule `json:"conditions,omitempty"`
+	LeftValue  *CDPRuleValue    `json:"leftValue,omitempty"`
+	Operator   *CDPRuleOperator `json:"operator,omitempty"`
+	Exclude    bool             `json:"exclude,omitempty"`
+	// ID is the referenced segment for "Reference" conditions
+	ID string `json:"id,omitempty"`
+}
+
+// CDPRuleValue is the column a rule condition tests. Source names the
+// behavior for behavior columns and is empty for audience attributes.
+type CDPRuleValue struct {
+	Name       string `json:"name"`
+	Source     string `json:"source,omitempty"`
+	Visibility string `json:"visibility,omitempty"`
+}
+
+// UnmarshalJSON accepts source as a behavior name or as {"name": ...}
+func (v *CDPRuleValue) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Name       string          `json:"name"`
+		Source     json.RawMessage `json:"source"`
+		Visibility string          `json:"visibility"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	v.Name = raw.Name
+	v.Visibility = raw.Visibility
+	v.Source = ""
+
+	if len(raw.Source) == 0 || string(raw.Source) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(raw.Source, &v.Source); err == nil {
+		return nil
+	}
+	var source struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(raw.Source, &source); err != nil {
+		return fmt.Errorf("unexpected rule value source %s", raw.Source)
+	}
+	v.Source = source.Name
+	return nil
+}
+
+// CDPRuleOperator is the comparison applied by a rule condition
+type CDPRuleOperator struct {
+	Type       string      `json:"type"`
+	Not        bool        `json:"not,omitempty"`
+	RightValue interface{} `json:"rightValue,omitempty"`
+}
+
+// CDPRuleReference is a column referenced by a segment rule
+type CDPRuleReference struct {
+	// Name is the attribute or behavior column name
+	Name string `json:"name"`
+	// Behavior is the behavior the column belongs to; empty for attributes
+	Behavior string `json:"behavior,omitempty"`
+}
+
+// ParseSegmentRule converts a raw segment rule, such as CDPSegment.Rule, into
+// its typed form. A nil rule returns nil.
+func ParseSegmentRule(rule interface{}) (*CDPSegmentRule, error) {
+	if rule == nil {
+		return nil, nil
+	}
+
+	data, ok := rule.(json.RawMessage)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(rule); err != nil {
+			return nil, fmt.Errorf("failed to encode segment rule: %w", err)
+		}
+	}
+
+	var parsed CDPSegmentRule
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse segment rule: %w", err)
+	}
+	return &parsed, nil
+}
+
+// Walk calls fn for the rule and every nested condition, depth first
+func (r *CDPSegmentRule) Walk(fn func(*CDPSegmentRule)) {
+	if r == nil {
+		return
+	}
+	fn(r)
+	for i := range r.Conditions {
+		r.Conditions[i].Walk(fn)
+	}
+}
+
+// References returns the distinct columns the rule tests, in rule order
+func (r *CDPSegmentRule) References() []CDPRuleReference {
+	var refs []CDPRuleReference
+	seen := make(map[CDPRuleReference]bool)
+	r.Walk(func(node *CDPSegmentRule) {
+		if node.LeftValue == nil || node.LeftValue.Name == "" {
+			return
+		}
+		ref := CDPRuleReference{Name: node.LeftValue.Name, Behavior: node.LeftValue.Source}
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	})
+	return refs
+}
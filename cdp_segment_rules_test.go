@@ -0,0 +1,45 @@
+package treasuredata
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func FuzzParseSegmentRule(f *testing.F) {
+	for _, seed := range []string{
+		`{"type": "And", "conditions": [{"type": "Value", "leftValue": {"name": "age"}, "operator": {"type": "Greater", "rightValue": 20}}]}`,
+		`{"type": "Value", "leftValue": {"name": "order_total", "source": {"name": "orders"}}, "operator": {"type": "Equal", "not": true, "rightValue": ["a", "b"]}}`,
+		`{"type": "Reference", "id": "123", "exclude": true}`,
+		`{"type": "Or", "conditions": []}`,
+		`{"leftValue": {"source": 1}}`,
+		`null`,
+		`[]`,
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		rule, err := ParseSegmentRule(json.RawMessage(data))
+		if err != nil || rule == nil {
+			return
+		}
+		rule.References()
+
+		// Re-encoding a parsed rule must be stable
+		encoded, err := json.Marshal(rule)
+		if err != nil {
+			t.Fatalf("Marshal of rule from %s returned error: %v", data, err)
+		}
+		again, err := ParseSegmentRule(json.RawMessage(encoded))
+		if err != nil {
+			t.Fatalf("ParseSegmentRule(%s) returned error: %v", encoded, err)
+		}
+		reencoded, err := json.Marshal(again)
+		if err != nil {
+			t.Fatalf("Marshal of rule from %s returned error: %v", encoded, err)
+		}
+		if !bytes.Equal(encoded, reencoded) {
+			t.Fatalf("round trip of %s: %s != %s", data, reencoded, encoded)
+		}
+	})
+}
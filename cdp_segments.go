@@ -8,6 +8,10 @@ import (
 
 // CreateSegment creates a new customer segment within an audience
 func (s *CDPService) CreateSegment(ctx context.Context, audienceID, name, description, query string) (*CDPSegment, error) {
+	if err := ValidateName(NameKindSegment, name); err != nil {
+		return nil, err
+	}
+
 	u := fmt.Sprintf("audiences/%s/segments", audienceID)
 
 	body := map[string]string{
@@ -30,6 +34,28 @@ func (s *CDPService) CreateSegment(ctx context.Context, audienceID, name, descri
 	return &segment, nil
 }
 
+// CreateSegmentWithRequest creates a segment from a rule, optionally in a folder
+func (s *CDPService) CreateSegmentWithRequest(ctx context.Context, audienceID string, req *CDPSegmentCreateRequest) (*CDPSegment, error) {
+	if err := ValidateName(NameKindSegment, req.Name); err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("audiences/%s/segments", audienceID)
+
+	request, err := s.client.NewCDPRequest("POST", u, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var segment CDPSegment
+	_, err = s.client.Do(ctx, request, &segment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &segment, nil
+}
+
 // ListSegments returns a list of customer segments for an audience
 func (s *CDPService) ListSegments(ctx context.Context, audienceID string, opts *CDPSegmentListOptions) (*CDPSegmentListResponse, error) {
 	u := fmt.Sprintf("audiences/%s/segments", audienceID)
@@ -82,6 +108,10 @@ func (s *CDPService) ListSegmentsInFolder(ctx context.Context, audienceID, folde
 
 // GetSegment retrieves a specific segment by ID from an audience
 func (s *CDPService) GetSegment(ctx context.Context, audienceID, segmentID string) (*CDPSegment, error) {
+	if s.client.pinnedTo(ServiceCDP, APIVersionEntity) {
+		return s.getEntitySegmentAsSegment(ctx, segmentID)
+	}
+
 	u := fmt.Sprintf("audiences/%s/segments/%s", audienceID, segmentID)
 
 	req, err := s.client.NewCDPRequest("GET", u, nil)
@@ -118,6 +148,10 @@ func (s *CDPService) UpdateSegment(ctx context.Context, audienceID, segmentID st
 
 // DeleteSegment deletes a customer segment from an audience
 func (s *CDPService) DeleteSegment(ctx context.Context, audienceID, segmentID string) error {
+	if s.client.pinnedTo(ServiceCDP, APIVersionEntity) {
+		return s.DeleteEntitySegment(ctx, segmentID)
+	}
+
 	u := fmt.Sprintf("audiences/%s/segments/%s", audienceID, segmentID)
 
 	req, err := s.client.NewCDPRequest("DELETE", u, nil)
@@ -284,6 +318,10 @@ func (s *CDPService) GetSegmentStatistics(ctx context.Context, audienceID, segme
 
 // CreateEntitySegment creates a new entity segment using JSON:API format
 func (s *CDPService) CreateEntitySegment(ctx context.Context, name, description, segmentType string, parentFolderID string, attributes map[string]interface{}) (*CDPJSONAPIResponse, error) {
+	if err := ValidateName(NameKindSegment, name); err != nil {
+		return nil, err
+	}
+
 	u := "entities/segments"
 
 	// Build JSON:API request
@@ -409,6 +447,10 @@ func (s *CDPService) DeleteEntitySegment(ctx context.Context, segmentID string)
 
 // ListParentSegments lists all parent segments
 func (c *CDPService) ListParentSegments(ctx context.Context) (*CDPParentSegmentListResponse, error) {
+	if c.client.pinnedTo(ServiceCDP, APIVersionLegacy) {
+		return c.listParentSegmentsLegacy(ctx)
+	}
+
 	path := "entities/parent_segments"
 
 	req, err := c.client.NewCDPRequest("GET", path, nil)
@@ -427,6 +469,10 @@ func (c *CDPService) ListParentSegments(ctx context.Context) (*CDPParentSegmentL
 
 // GetParentSegment retrieves a specific parent segment by ID
 func (c *CDPService) GetParentSegment(ctx context.Context, parentSegmentID string) (*CDPParentSegmentResponse, error) {
+	if c.client.pinnedTo(ServiceCDP, APIVersionLegacy) {
+		return c.getParentSegmentLegacy(ctx, parentSegmentID)
+	}
+
 	path := fmt.Sprintf("entities/parent_segments/%s", parentSegmentID)
 
 	req, err := c.client.NewCDPRequest("GET", path, nil)
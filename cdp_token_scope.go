@@ -0,0 +1,108 @@
+package treasuredata
+
+import (
+	"context"
+	"net/url"
+	"sort"
+)
+
+// CDPProfile is a profile returned by the Profiles API. It holds only the
+// attributes and segments that the token used for the lookup exposes.
+type CDPProfile struct {
+	AudienceID string                 `json:"audienceId"`
+	Key        map[string]interface{} `json:"key"`
+	Attributes map[string]interface{} `json:"attributes"`
+	// SegmentIDs are the token's segments that the profile belongs to
+	SegmentIDs []string `json:"values"`
+}
+
+// CDPTokenScope is what a profiles API token gives access to, as seen by a
+// lookup of one profile
+type CDPTokenScope struct {
+	KeyColumn string `json:"key_column"`
+	KeyValue  string `json:"key_value"`
+	// Profiles is the number of profiles found for the key
+	Profiles    int      `json:"profiles"`
+	AudienceIDs []string `json:"audience_ids"`
+	// Attributes are the names of the attributes returned, sorted
+	Attributes []string `json:"attributes"`
+	// SegmentIDs are the segments the profiles belong to, sorted. Segments of
+	// the token that the profiles are not in cannot be seen by a lookup.
+	SegmentIDs []string `json:"segment_ids"`
+}
+
+// LookupProfiles looks up the profiles whose keyColumn is keyValue through
+// the Profiles API, authenticating with a profiles API token instead of the
+// client's API key
+func (s *CDPService) LookupProfiles(ctx context.Context, token, keyColumn, keyValue string) ([]CDPProfile, error) {
+	if token == "" {
+		return nil, NewValidationError("token", token, "cannot be empty")
+	}
+	if keyColumn == "" {
+		return nil, NewValidationError("keyColumn", keyColumn, "cannot be empty")
+	}
+
+	query := url.Values{}
+	query.Set("version", "2")
+	query.Set("token", token)
+	query.Set("key."+keyColumn, keyValue)
+
+	req, err := s.client.NewProfilesRequest("GET", "cdp/lookup/collect/segments?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []CDPProfile
+	_, err = s.client.Do(ctx, req, &profiles)
+	if err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+// CheckTokenScope verifies a profiles API token end to end by looking up
+// one profile, and reports which attributes and segments the token returned.
+// An invalid token fails with the API's error; a valid token and a key with
+// no profile give a scope with Profiles set to 0.
+func (s *CDPService) CheckTokenScope(ctx context.Context, token, keyColumn, keyValue string) (*CDPTokenScope, error) {
+	profiles, err := s.LookupProfiles(ctx, token, keyColumn, keyValue)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := &CDPTokenScope{
+		KeyColumn:   keyColumn,
+		KeyValue:    keyValue,
+		Profiles:    len(profiles),
+		AudienceIDs: []string{},
+		Attributes:  []string{},
+		SegmentIDs:  []string{},
+	}
+	audiences := map[string]bool{}
+	attributes := map[string]bool{}
+	segments := map[string]bool{}
+	for _, profile := range profiles {
+		if profile.AudienceID != "" && !audiences[profile.AudienceID] {
+			audiences[profile.AudienceID] = true
+			scope.AudienceIDs = append(scope.AudienceIDs, profile.AudienceID)
+		}
+		for name := range profile.Attributes {
+			if !attributes[name] {
+				attributes[name] = true
+				scope.Attributes = append(scope.Attributes, name)
+			}
+		}
+		for _, id := range profile.SegmentIDs {
+			if !segments[id] {
+				segments[id] = true
+				scope.SegmentIDs = append(scope.SegmentIDs, id)
+			}
+		}
+	}
+	sort.Strings(scope.AudienceIDs)
+	sort.Strings(scope.Attributes)
+	sort.Strings(scope.SegmentIDs)
+
+	return scope, nil
+}
@@ -0,0 +1,81 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestCDPService_CheckTokenScope(t *testing.T) {
+	client, mux, teardown := setupCDP()
+	defer teardown()
+
+	mux.HandleFunc("/cdp/lookup/collect/segments", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("Authorization = %q, want no API key", got)
+		}
+		q := r.URL.Query()
+		if q.Get("version") != "2" || q.Get("token") != "tok-1" {
+			t.Errorf("query = %v", q)
+		}
+		switch q.Get("key.td_client_id") {
+		case "abc":
+			fmt.Fprint(w, `[
+				{"audienceId": "12", "key": {"td_client_id": "abc"}, "attributes": {"email": "a@example.com", "country": null}, "values": ["300", "200"]},
+				{"audienceId": "12", "key": {"td_client_id": "abc"}, "attributes": {"email": "b@example.com"}, "values": ["200"]}
+			]`)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	})
+
+	ctx := context.Background()
+	scope, err := client.CDP.CheckTokenScope(ctx, "tok-1", "td_client_id", "abc")
+	if err != nil {
+		t.Fatalf("CDP.CheckTokenScope returned error: %v", err)
+	}
+	want := &CDPTokenScope{
+		KeyColumn:   "td_client_id",
+		KeyValue:    "abc",
+		Profiles:    2,
+		AudienceIDs: []string{"12"},
+		Attributes:  []string{"country", "email"},
+		SegmentIDs:  []string{"200", "300"},
+	}
+	if !reflect.DeepEqual(scope, want) {
+		t.Errorf("CDP.CheckTokenScope returned %+v, want %+v", scope, want)
+	}
+
+	scope, err = client.CDP.CheckTokenScope(ctx, "tok-1", "td_client_id", "unknown")
+	if err != nil {
+		t.Fatalf("CDP.CheckTokenScope returned error: %v", err)
+	}
+	if scope.Profiles != 0 || len(scope.Attributes) != 0 {
+		t.Errorf("CDP.CheckTokenScope for an unknown key returned %+v", scope)
+	}
+
+	if _, err := client.CDP.CheckTokenScope(ctx, "", "td_client_id", "abc"); err == nil {
+		t.Error("CDP.CheckTokenScope expected error for an empty token")
+	}
+}
+
+func TestCDPService_CheckTokenScope_invalidToken(t *testing.T) {
+	client, mux, teardown := setupCDP()
+	defer teardown()
+
+	mux.HandleFunc("/cdp/lookup/collect/segments", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error": "invalid token"}`)
+	})
+
+	_, err := client.CDP.CheckTokenScope(context.Background(), "bad", "td_client_id", "abc")
+	if err == nil {
+		t.Fatal("CDP.CheckTokenScope expected error for an invalid token")
+	}
+	if !IsUnauthorized(err) {
+		t.Errorf("CDP.CheckTokenScope returned %v, want an unauthorized error", err)
+	}
+}
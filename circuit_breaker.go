@@ -0,0 +1,184 @@
+package treasuredata
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned, wrapped in a *CircuitOpenError, when a request
+// is rejected because the circuit breaker for its service is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitOpenError reports which service's circuit rejected a request
+type CircuitOpenError struct {
+	// Service is the endpoint group: MetricsServiceTD, MetricsServiceCDP,
+	// MetricsServiceWorkflow, or the host for other URLs
+	Service string
+	// RetryAt is when the circuit will let a probe request through
+	RetryAt time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s: %v until %s", e.Service, ErrCircuitOpen, e.RetryAt.Format(time.RFC3339))
+}
+
+// Unwrap lets errors.Is(err, ErrCircuitOpen) match
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// CircuitState is the state of a service's circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed lets requests through and counts consecutive failures
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects requests until the open timeout elapses
+	CircuitOpen
+	// CircuitHalfOpen lets a single probe request through to test recovery
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreakerOptions configures WithCircuitBreaker
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failures that opens the
+	// circuit. Defaults to 5.
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before a probe request is
+	// allowed. Defaults to 30 seconds.
+	OpenTimeout time.Duration
+	// IsFailure decides whether a request attempt counts as a failure. By
+	// default transport errors and 5xx responses do.
+	IsFailure func(resp *http.Response, err error) bool
+}
+
+// WithCircuitBreaker enables a circuit breaker per service (TD API, CDP and
+// Workflow). After FailureThreshold consecutive failures requests to that
+// service fail fast with a *CircuitOpenError; once OpenTimeout has elapsed one
+// probe request is let through, and its outcome closes or re-opens the circuit.
+func WithCircuitBreaker(opts CircuitBreakerOptions) ClientOption {
+	return func(c *Client) error {
+		if opts.FailureThreshold < 0 || opts.OpenTimeout < 0 {
+			return fmt.Errorf("circuit breaker options must not be negative")
+		}
+		if opts.FailureThreshold == 0 {
+			opts.FailureThreshold = 5
+		}
+		if opts.OpenTimeout == 0 {
+			opts.OpenTimeout = 30 * time.Second
+		}
+		if opts.IsFailure == nil {
+			opts.IsFailure = defaultIsFailure
+		}
+		c.breaker = &circuitBreaker{opts: opts, circuits: map[string]*circuit{}, now: time.Now}
+		return nil
+	}
+}
+
+// CircuitState returns the circuit breaker state for a service. It reports
+// CircuitClosed when no circuit breaker is configured.
+func (c *Client) CircuitState(service string) CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	return c.breaker.state(service)
+}
+
+func defaultIsFailure(resp *http.Response, err error) bool {
+	return err != nil || resp.StatusCode >= 500
+}
+
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// circuitBreaker tracks one circuit per service
+type circuitBreaker struct {
+	opts     CircuitBreakerOptions
+	mu       sync.Mutex
+	circuits map[string]*circuit
+	now      func() time.Time
+}
+
+func (b *circuitBreaker) circuit(service string) *circuit {
+	c, ok := b.circuits[service]
+	if !ok {
+		c = &circuit{}
+		b.circuits[service] = c
+	}
+	return c
+}
+
+func (b *circuitBreaker) state(service string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(service)
+	if c.state == CircuitOpen && !b.now().Before(c.openedAt.Add(b.opts.OpenTimeout)) {
+		return CircuitHalfOpen
+	}
+	return c.state
+}
+
+// allow reports whether a request to service may be sent
+func (b *circuitBreaker) allow(service string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(service)
+	now := b.now()
+	switch c.state {
+	case CircuitOpen:
+		retryAt := c.openedAt.Add(b.opts.OpenTimeout)
+		if now.Before(retryAt) {
+			return &CircuitOpenError{Service: service, RetryAt: retryAt}
+		}
+		c.state = CircuitHalfOpen
+		c.probing = true
+	case CircuitHalfOpen:
+		if c.probing {
+			return &CircuitOpenError{Service: service, RetryAt: now}
+		}
+		c.probing = true
+	}
+	return nil
+}
+
+// record updates the circuit with the outcome of an allowed request. Requests
+// abandoned by the caller are neither successes nor failures.
+func (b *circuitBreaker) record(service string, resp *http.Response, err error, abandoned bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(service)
+	switch {
+	case abandoned:
+		c.probing = false
+	case b.opts.IsFailure(resp, err):
+		c.failures++
+		if c.state == CircuitHalfOpen || c.failures >= b.opts.FailureThreshold {
+			c.state = CircuitOpen
+			c.openedAt = b.now()
+			c.probing = false
+		}
+	default:
+		*c = circuit{}
+	}
+}
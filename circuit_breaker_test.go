@@ -0,0 +1,100 @@
+package treasuredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithCircuitBreaker(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	if err := WithCircuitBreaker(CircuitBreakerOptions{FailureThreshold: 2, OpenTimeout: time.Minute})(client); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	client.breaker.now = func() time.Time { return now }
+
+	healthy := false
+	calls := 0
+	mux.HandleFunc("/v3/database/list", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"databases": []}`)
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := client.Databases.List(ctx); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("Request %d error = %v, want server error", i, err)
+		}
+	}
+	if got := client.CircuitState(MetricsServiceTD); got != CircuitOpen {
+		t.Fatalf("CircuitState = %s, want open", got)
+	}
+
+	// Open circuits fail fast without reaching the server
+	_, err := client.Databases.List(ctx)
+	var openErr *CircuitOpenError
+	if !errors.Is(err, ErrCircuitOpen) || !errors.As(err, &openErr) || openErr.Service != MetricsServiceTD {
+		t.Fatalf("Error = %v, want *CircuitOpenError for td", err)
+	}
+	if calls != 2 {
+		t.Errorf("Server calls = %d, want 2", calls)
+	}
+	if got := client.CircuitState(MetricsServiceCDP); got != CircuitClosed {
+		t.Errorf("CDP CircuitState = %s, want closed", got)
+	}
+
+	// After the timeout a failed probe re-opens the circuit
+	now = now.Add(time.Minute)
+	if got := client.CircuitState(MetricsServiceTD); got != CircuitHalfOpen {
+		t.Errorf("CircuitState = %s, want half-open", got)
+	}
+	if _, err := client.Databases.List(ctx); errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Probe was rejected: %v", err)
+	}
+	if got := client.CircuitState(MetricsServiceTD); got != CircuitOpen {
+		t.Fatalf("CircuitState after failed probe = %s, want open", got)
+	}
+
+	// A successful probe closes it
+	now = now.Add(time.Minute)
+	healthy = true
+	if _, err := client.Databases.List(ctx); err != nil {
+		t.Fatalf("Probe returned error: %v", err)
+	}
+	if got := client.CircuitState(MetricsServiceTD); got != CircuitClosed {
+		t.Errorf("CircuitState after successful probe = %s, want closed", got)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenAllowsOneProbe(t *testing.T) {
+	b := &circuitBreaker{
+		opts:     CircuitBreakerOptions{FailureThreshold: 1, OpenTimeout: time.Second, IsFailure: defaultIsFailure},
+		circuits: map[string]*circuit{},
+		now:      time.Now,
+	}
+	b.record("cdp", nil, errors.New("boom"), false)
+	b.circuits["cdp"].openedAt = time.Now().Add(-time.Hour)
+
+	if err := b.allow("cdp"); err != nil {
+		t.Fatalf("First probe rejected: %v", err)
+	}
+	if err := b.allow("cdp"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Second concurrent request error = %v, want ErrCircuitOpen", err)
+	}
+
+	// An abandoned probe frees the slot without changing the state
+	b.record("cdp", nil, context.Canceled, true)
+	if err := b.allow("cdp"); err != nil {
+		t.Errorf("Probe after abandoned probe rejected: %v", err)
+	}
+}
@@ -9,9 +9,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -45,6 +47,14 @@ var CDPRegionalEndpoints = map[string]string{
 	"ap02":  "https://api-cdp.ap02.treasuredata.com",
 }
 
+// Profiles API (CDP lookup) regional endpoints
+var ProfilesRegionalEndpoints = map[string]string{
+	"us":    "https://cdp-lookup.in.treasuredata.com",
+	"eu":    "https://cdp-lookup.in.eu01.treasuredata.com",
+	"tokyo": "https://cdp-lookup.in.treasuredata.co.jp",
+	"ap02":  "https://cdp-lookup.in.ap02.treasuredata.com",
+}
+
 // Workflow Regional endpoints
 var WorkflowRegionalEndpoints = map[string]string{
 	"us":    "https://api-workflow.us01.treasuredata.com",
@@ -60,7 +70,14 @@ type TDTime struct {
 
 // UnmarshalJSON implements the json.Unmarshaler interface for TDTime
 func (t *TDTime) UnmarshalJSON(data []byte) error {
-	// First, try to unmarshal as a number (Unix timestamp)
+	// Handle null values first: unmarshaling null into a number succeeds
+	// without setting it, which would give the Unix epoch
+	if string(data) == "null" {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	// Try to unmarshal as a number (Unix timestamp)
 	var timestamp int64
 	if err := json.Unmarshal(data, &timestamp); err == nil {
 		t.Time = time.Unix(timestamp, 0)
@@ -70,11 +87,6 @@ func (t *TDTime) UnmarshalJSON(data []byte) error {
 	// If not a number, try as a string
 	var timeStr string
 	if err := json.Unmarshal(data, &timeStr); err != nil {
-		// Handle null values
-		if string(data) == "null" {
-			t.Time = time.Time{}
-			return nil
-		}
 		return err
 	}
 
@@ -114,7 +126,12 @@ func (t TDTime) MarshalJSON() ([]byte, error) {
 	if t.Time.IsZero() {
 		return []byte("null"), nil
 	}
-	return []byte(`"` + t.Time.Format("2006-01-02 15:04:05 UTC") + `"`), nil
+	// Years the layout cannot hold round trip as Unix seconds
+	utc := t.Time.UTC()
+	if utc.Year() < 0 || utc.Year() > 9999 {
+		return []byte(strconv.FormatInt(utc.Unix(), 10)), nil
+	}
+	return []byte(`"` + utc.Format("2006-01-02 15:04:05 UTC") + `"`), nil
 }
 
 // Client represents a Treasure Data API client
@@ -131,23 +148,83 @@ type Client struct {
 	// Workflow API URL
 	WorkflowURL *url.URL
 
+	// Profiles API URL, for lookups authenticated by a profiles API token
+	ProfilesURL *url.URL
+
 	// API key for authentication
 	APIKey string
 
+	// Optional provider consulted for the API key on every request
+	credentials CredentialsProvider
+
 	// User agent for API requests
 	UserAgent string
 
-	// Services for different API resources
-	Databases   *DatabasesService
-	Tables      *TablesService
-	Jobs        *JobsService
-	Queries     *QueriesService
-	Results     *ResultsService
-	Users       *UsersService
-	Permissions *PermissionsService
-	BulkImport  *BulkImportService
-	CDP         *CDPService
-	Workflow    *WorkflowService
+	// Application identity set by WithAppName
+	appName string
+
+	// Rate limit tracking and optional throttling
+	rateLimiter rateLimiter
+
+	// Middleware wrapped around the HTTP transport
+	middleware []Middleware
+
+	// Optional request observer
+	metrics ClientMetrics
+
+	// Optional structured debug logger
+	logger *slog.Logger
+
+	// Optional per-service circuit breaker
+	breaker *circuitBreaker
+
+	// Policies checked before queries are issued
+	queryPolicies []QueryPolicy
+
+	// Region selected with WithRegion, used for endpoints not stored as URLs
+	region string
+
+	// Per-service endpoints set with WithEndpointOverrides
+	endpointOverrides map[ServiceKind]string
+
+	// Services pinned to legacy or entity paths with WithAPIVersionPolicy
+	apiVersions APIVersionPolicy
+
+	// Gzip request bodies of at least compressThreshold bytes
+	compressRequests  bool
+	compressThreshold int
+
+	// Mutating requests captured by WithDryRun instead of being sent
+	dryRun *dryRunPlan
+
+	// Reject unknown response fields, set by WithStrictDecoding
+	strictDecoding bool
+
+	// Request and response dumps, set by WithHTTPDump
+	httpDump *httpDumper
+
+	// Cached GET responses, set by WithCache
+	cache *responseCache
+
+	// Requests the client may send, one list per WithAllowlist; a request
+	// must match every list, and none allows all
+	allowlists [][]AllowRule
+
+	// Services for different API resources. They are interfaces so that
+	// tests can replace them; NewClient sets the concrete *Service types.
+	Databases   DatabasesAPI
+	Tables      TablesAPI
+	Jobs        JobsAPI
+	Queries     QueriesAPI
+	Results     ResultsAPI
+	Users       UsersAPI
+	Permissions PermissionsAPI
+	BulkImport  BulkImportAPI
+	Import      ImportAPI
+	CDP         CDPAPI
+	Workflow    WorkflowAPI
+	Account     AccountAPI
+	Schedules   SchedulesAPI
 }
 
 // ClientOption is a function that configures a Client
@@ -177,6 +254,7 @@ func WithEndpoint(endpoint string) ClientOption {
 func WithRegion(region string) ClientOption {
 	return func(c *Client) error {
 		regionLower := strings.ToLower(region)
+		c.region = regionLower
 		if endpoint, ok := RegionalEndpoints[regionLower]; ok {
 			u, err := url.Parse(endpoint)
 			if err != nil {
@@ -198,6 +276,13 @@ func WithRegion(region string) ClientOption {
 			}
 			c.WorkflowURL = u
 		}
+		if profilesEndpoint, ok := ProfilesRegionalEndpoints[regionLower]; ok {
+			u, err := url.Parse(profilesEndpoint)
+			if err != nil {
+				return fmt.Errorf("invalid profiles regional endpoint for %s: %w", region, err)
+			}
+			c.ProfilesURL = u
+		}
 		return nil
 	}
 }
@@ -221,21 +306,9 @@ type SSLOptions struct {
 // WithSSLOptions configures SSL/TLS settings for the HTTP client
 func WithSSLOptions(options SSLOptions) ClientOption {
 	return func(c *Client) error {
-		if c.httpClient == nil {
-			c.httpClient = &http.Client{Timeout: defaultTimeout}
-		}
-
-		// Get the transport or create a new one
-		transport := c.httpClient.Transport
-		if transport == nil {
-			transport = http.DefaultTransport.(*http.Transport).Clone()
-			c.httpClient.Transport = transport
-		}
-
-		// Type assert to *http.Transport
-		t, ok := transport.(*http.Transport)
-		if !ok {
-			return fmt.Errorf("unable to configure SSL: transport is not *http.Transport")
+		t, err := c.httpTransport()
+		if err != nil {
+			return fmt.Errorf("unable to configure SSL: %w", err)
 		}
 
 		// Initialize TLS config if not already set
@@ -274,15 +347,13 @@ func WithSSLOptions(options SSLOptions) ClientOption {
 	}
 }
 
-// NewClient creates a new Treasure Data API client
+// NewClient creates a new Treasure Data API client. apiKey may be empty when
+// WithCredentialsProvider supplies keys instead.
 func NewClient(apiKey string, opts ...ClientOption) (*Client, error) {
-	if apiKey == "" {
-		return nil, fmt.Errorf("API key is required")
-	}
-
 	baseURL, _ := url.Parse(defaultBaseURL)
 	cdpURL, _ := url.Parse(CDPRegionalEndpoints["us"])
 	workflowURL, _ := url.Parse(WorkflowRegionalEndpoints["us"])
+	profilesURL, _ := url.Parse(ProfilesRegionalEndpoints["us"])
 
 	c := &Client{
 		httpClient: &http.Client{
@@ -291,6 +362,7 @@ func NewClient(apiKey string, opts ...ClientOption) (*Client, error) {
 		BaseURL:     baseURL,
 		CDPURL:      cdpURL,
 		WorkflowURL: workflowURL,
+		ProfilesURL: profilesURL,
 		APIKey:      apiKey,
 		UserAgent:   "treasuredata-go-sdk/1.0.0",
 	}
@@ -302,6 +374,13 @@ func NewClient(apiKey string, opts ...ClientOption) (*Client, error) {
 		}
 	}
 
+	if apiKey == "" && c.credentials == nil {
+		return nil, fmt.Errorf("API key is required")
+	}
+
+	c.applyEndpointOverrides()
+	c.applyMiddleware()
+
 	// Initialize services
 	c.Databases = &DatabasesService{client: c}
 	c.Tables = &TablesService{client: c}
@@ -311,13 +390,17 @@ func NewClient(apiKey string, opts ...ClientOption) (*Client, error) {
 	c.Users = &UsersService{client: c}
 	c.Permissions = &PermissionsService{client: c}
 	c.BulkImport = &BulkImportService{client: c}
+	c.Import = &ImportService{client: c}
 	c.CDP = &CDPService{client: c}
 	c.Workflow = &WorkflowService{client: c}
+	c.Account = &AccountService{client: c}
+	c.Schedules = &SchedulesService{client: c}
 
 	return c, nil
 }
 
-// NewRequest creates an API request
+// NewRequest creates an API request. The body is encoded as JSON, or as
+// MessagePack when it is a MsgpackBody and the endpoint accepts it.
 func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Request, error) {
 	u, err := c.BaseURL.Parse(urlStr)
 	if err != nil {
@@ -325,11 +408,9 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 	}
 
 	var buf io.ReadWriter
+	var contentType string
 	if body != nil {
-		buf = new(bytes.Buffer)
-		enc := json.NewEncoder(buf)
-		enc.SetEscapeHTML(false)
-		err := enc.Encode(body)
+		buf, contentType, err = encodeRequestBody(u.Path, body)
 		if err != nil {
 			return nil, err
 		}
@@ -341,7 +422,7 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 	}
 
 	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Type", contentType)
 	}
 
 	req.Header.Set("Authorization", fmt.Sprintf("TD1 %s", c.APIKey))
@@ -413,6 +494,34 @@ func (c *Client) NewWorkflowBinaryRequest(method, urlStr string, data []byte, co
 	return req, nil
 }
 
+// NewBinaryRequest creates an API request with a raw binary body
+func (c *Client) NewBinaryRequest(method, urlStr string, data []byte, contentType string) (*http.Request, error) {
+	u, err := c.BaseURL.Parse(urlStr)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if data != nil && contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("TD1 %s", c.APIKey))
+	req.Header.Set("User-Agent", c.UserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	return req, nil
+}
+
 // NewCDPJSONAPIRequest creates an API request for CDP JSON:API endpoints
 func (c *Client) NewCDPJSONAPIRequest(method, urlStr string, body interface{}) (*http.Request, error) {
 	u, err := c.CDPURL.Parse(urlStr)
@@ -447,6 +556,25 @@ func (c *Client) NewCDPJSONAPIRequest(method, urlStr string, body interface{}) (
 	return req, nil
 }
 
+// NewProfilesRequest creates a Profiles API request. The API authenticates
+// by the token in the query string, so no API key is set.
+func (c *Client) NewProfilesRequest(method, urlStr string) (*http.Request, error) {
+	u, err := c.ProfilesURL.Parse(urlStr)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", c.UserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	return req, nil
+}
+
 // NewWorkflowRequest creates an API request for Workflow endpoints
 func (c *Client) NewWorkflowRequest(method, urlStr string, body interface{}) (*http.Request, error) {
 	u, err := c.WorkflowURL.Parse(urlStr)
@@ -481,11 +609,83 @@ func (c *Client) NewWorkflowRequest(method, urlStr string, body interface{}) (*h
 	return req, nil
 }
 
-// Do sends an API request and returns the API response
-func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
+// send performs the HTTP round trip, applying client-side throttling when enabled
+func (c *Client) send(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if err := c.checkAllowlist(req); err != nil {
+		return nil, err
+	}
 	req = req.WithContext(ctx)
+	setContextHeaders(ctx, req)
+	c.setAppHeaders(req)
+	if resp, err := c.planRequest(req); resp != nil || err != nil {
+		return resp, err
+	}
+	if err := c.compressRequest(req); err != nil {
+		return nil, err
+	}
 
-	resp, err := c.httpClient.Do(req)
+	for attempt := 0; ; attempt++ {
+		if err := c.rateLimiter.wait(ctx); err != nil {
+			return nil, err
+		}
+		if err := c.authorize(ctx, req); err != nil {
+			return nil, err
+		}
+		if attempt == 0 {
+			if resp := c.cachedResponse(ctx, req); resp != nil {
+				return resp, nil
+			}
+		}
+
+		var service string
+		if c.breaker != nil {
+			service = c.requestInfo(req).Service
+			if err := c.breaker.allow(service); err != nil {
+				return nil, err
+			}
+		}
+
+		c.logRequest(ctx, req, attempt)
+		start := time.Now()
+		resp, err := c.doHTTP(req)
+		c.logResponse(ctx, req, resp, time.Since(start), err)
+		if c.breaker != nil {
+			c.breaker.record(service, resp, err, ctx.Err() != nil)
+		}
+		if err != nil {
+			return nil, err
+		}
+		c.rateLimiter.update(resp)
+
+		// Bodies that cannot be replayed are never retried
+		delay, retry := c.rateLimiter.retryDelay(resp, attempt)
+		if !retry || (req.Body != nil && req.GetBody == nil) {
+			return c.updateCache(req, resp)
+		}
+		resp.Body.Close()
+		c.logRetry(ctx, req, resp, attempt+1, delay)
+
+		if err := sleepContext(ctx, delay, 0); err != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+	}
+}
+
+// Do sends an API request and returns the API response
+func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}, opts ...RequestOption) (*http.Response, error) {
+	ctx, cancel := requestContext(ctx, opts)
+	defer cancel()
+
+	resp, err := c.send(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -493,20 +693,18 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*htt
 
 	err = CheckResponse(resp)
 	if err != nil {
-		return resp, err
+		return resp, c.featureError(req, err)
 	}
 
 	if v != nil && resp.StatusCode != http.StatusNoContent {
 		if w, ok := v.(io.Writer); ok {
-			io.Copy(w, resp.Body)
+			io.Copy(w, withProgress(resp.Body, 0, resp.ContentLength, newRequestOptions(opts).progress))
 		} else {
-			decErr := json.NewDecoder(resp.Body).Decode(v)
-			if decErr == io.EOF {
-				decErr = nil // ignore EOF errors caused by empty response body
-			}
-			if decErr != nil {
-				err = decErr
+			body, readErr := io.ReadAll(resp.Body)
+			if readErr != nil {
+				return resp, readErr
 			}
+			err = c.decodeResponse(ctx, req, body, v)
 		}
 	}
 
@@ -520,12 +718,18 @@ type ErrorResponse struct {
 	ErrorMsg string `json:"error"`
 	Text     string `json:"text"`
 	Severity string `json:"severity"`
+	// RequestID identifies the failed request for Treasure Data support
+	RequestID string `json:"-"`
 }
 
 func (r *ErrorResponse) Error() string {
-	return fmt.Sprintf("%v %v: %d %v",
+	msg := fmt.Sprintf("%v %v: %d %v",
 		r.Response.Request.Method, r.Response.Request.URL,
 		r.Response.StatusCode, r.Message)
+	if r.RequestID != "" {
+		msg += fmt.Sprintf(" (request ID %s)", r.RequestID)
+	}
+	return msg
 }
 
 // CheckResponse checks the API response for errors
@@ -534,7 +738,7 @@ func CheckResponse(r *http.Response) error {
 		return nil
 	}
 
-	errorResponse := &ErrorResponse{Response: r}
+	errorResponse := &ErrorResponse{Response: r, RequestID: RequestIDFromResponse(r)}
 	data, err := io.ReadAll(r.Body)
 	if err == nil && data != nil {
 		json.Unmarshal(data, errorResponse)
@@ -0,0 +1,35 @@
+package treasuredata
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func FuzzTDTimeUnmarshalJSON(f *testing.F) {
+	for _, seed := range []string{
+		`1609459200`, `"2020-06-11 10:25:10 UTC"`, `"2025-03-28T05:11:24Z"`,
+		`"2024-04-26T00:05:42.783Z"`, `null`, `""`, `"not a time"`, `-1`, `1e3`, `{}`,
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var parsed TDTime
+		if err := parsed.UnmarshalJSON(data); err != nil {
+			return
+		}
+		// Whatever was accepted must survive a marshal round trip, to the
+		// second
+		encoded, err := json.Marshal(parsed)
+		if err != nil {
+			t.Fatalf("Marshal(%v) returned error: %v", parsed.Time, err)
+		}
+		var again TDTime
+		if err := json.Unmarshal(encoded, &again); err != nil {
+			t.Fatalf("Unmarshal(%s) of %s returned error: %v", encoded, data, err)
+		}
+		if !again.Time.Equal(parsed.Time.Truncate(time.Second)) {
+			t.Fatalf("round trip of %s: %v != %v", data, again.Time, parsed.Time)
+		}
+	})
+}
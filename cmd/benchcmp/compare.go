@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// procsSuffix is the -GOMAXPROCS suffix go test appends to benchmark names
+var procsSuffix = regexp.MustCompile(`-\d+$`)
+
+// samples maps a benchmark name to its values of one metric, one per run
+type samples map[string][]float64
+
+// parseBenchmarks reads go test -bench output and returns the values of
+// metric (e.g. ns/op or allocs/op) for each benchmark
+func parseBenchmarks(r io.Reader, metric string) (samples, error) {
+	result := samples{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+		name := procsSuffix.ReplaceAllString(fields[0], "")
+		for i := 2; i+1 < len(fields); i += 2 {
+			if fields[i+1] != metric {
+				continue
+			}
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid %s value %q", name, metric, fields[i])
+			}
+			result[name] = append(result[name], value)
+		}
+	}
+	return result, scanner.Err()
+}
+
+// comparison is the change of one benchmark between two runs
+type comparison struct {
+	Name string
+	Old  float64
+	New  float64
+}
+
+// Delta returns the relative change in percent
+func (c comparison) Delta() float64 {
+	if c.Old == 0 {
+		return 0
+	}
+	return (c.New - c.Old) / c.Old * 100
+}
+
+// compare pairs the medians of the benchmarks present in both runs, sorted
+// by name
+func compare(old, current samples) []comparison {
+	var comparisons []comparison
+	for name, oldValues := range old {
+		newValues, ok := current[name]
+		if !ok {
+			continue
+		}
+		comparisons = append(comparisons, comparison{Name: name, Old: median(oldValues), New: median(newValues)})
+	}
+	sort.Slice(comparisons, func(i, j int) bool { return comparisons[i].Name < comparisons[j].Name })
+	return comparisons
+}
+
+// regressions returns the comparisons that got worse by more than threshold
+// percent. Every metric go test reports is better when lower.
+func regressions(comparisons []comparison, threshold float64) []comparison {
+	var worse []comparison
+	for _, c := range comparisons {
+		if c.Delta() > threshold {
+			worse = append(worse, c)
+		}
+	}
+	return worse
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
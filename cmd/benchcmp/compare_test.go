@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const oldOutput = `goos: linux
+BenchmarkEncodeImportRecords-8   	      30	  33000000 ns/op	 4058000 B/op	   30026 allocs/op
+BenchmarkEncodeImportRecords-8   	      30	  35000000 ns/op	 4058000 B/op	   30026 allocs/op
+BenchmarkDecodeResponse_JobList/default-8 	 10	 100000000 ns/op	  33.03 MB/s	20772133 B/op	  105346 allocs/op
+BenchmarkRemoved-8   	      30	  1000 ns/op
+PASS
+`
+
+const newOutput = `BenchmarkEncodeImportRecords-4   	      30	  34000000 ns/op	 4058000 B/op	   30026 allocs/op
+BenchmarkDecodeResponse_JobList/default-4 	 10	 130000000 ns/op	  33.03 MB/s	20772133 B/op	  105346 allocs/op
+BenchmarkDecodeResponse_JobList/default-4 	 10	 120000000 ns/op	  33.03 MB/s	20772133 B/op	  105346 allocs/op
+BenchmarkDecodeResponse_JobList/default-4 	 10	 125000000 ns/op	  33.03 MB/s	20772133 B/op	  105346 allocs/op
+`
+
+func TestCompare(t *testing.T) {
+	old, err := parseBenchmarks(strings.NewReader(oldOutput), "ns/op")
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := parseBenchmarks(strings.NewReader(newOutput), "ns/op")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comparisons := compare(old, current)
+	if len(comparisons) != 2 {
+		t.Fatalf("compare returned %+v, want the two benchmarks in both runs", comparisons)
+	}
+	if c := comparisons[1]; c.Name != "BenchmarkEncodeImportRecords" || c.Old != 34000000 || c.New != 34000000 {
+		t.Errorf("comparisons[1] = %+v, want medians of 34000000", c)
+	}
+
+	worse := regressions(comparisons, 10)
+	if len(worse) != 1 || worse[0].Name != "BenchmarkDecodeResponse_JobList/default" || worse[0].Delta() != 25 {
+		t.Errorf("regressions = %+v, want JobList/default at +25%%", worse)
+	}
+	if worse := regressions(comparisons, 30); len(worse) != 0 {
+		t.Errorf("regressions above 30%% = %+v, want none", worse)
+	}
+}
+
+func TestParseBenchmarks_Metric(t *testing.T) {
+	allocs, err := parseBenchmarks(strings.NewReader(oldOutput), "allocs/op")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := allocs["BenchmarkDecodeResponse_JobList/default"]; len(got) != 1 || got[0] != 105346 {
+		t.Errorf("allocs/op = %v, want [105346]", got)
+	}
+	if _, ok := allocs["BenchmarkRemoved"]; ok {
+		t.Error("benchmark without allocs/op should have no samples")
+	}
+}
@@ -0,0 +1,132 @@
+// Command benchcmp runs the SDK benchmarks on a base git revision and on the
+// working tree, prints a benchstat report when benchstat is installed, and
+// fails if a benchmark got slower than a threshold. Use it to validate
+// performance-motivated changes:
+//
+//	go run ./cmd/benchcmp -base main -bench 'DecodeResponse|ResultDecoder' -threshold 10
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"text/tabwriter"
+)
+
+func main() {
+	base := flag.String("base", "main", "Git revision to compare against")
+	bench := flag.String("bench", ".", "Benchmarks to run (go test -bench)")
+	count := flag.Int("count", 6, "Runs of each benchmark; more runs give steadier medians")
+	benchtime := flag.String("benchtime", "", "go test -benchtime, e.g. 2s or 20x")
+	pkgs := flag.String("pkg", ".", "Packages to benchmark")
+	metric := flag.String("metric", "ns/op", "Metric the gate checks (ns/op, B/op or allocs/op)")
+	threshold := flag.Float64("threshold", 10, "Fail when a median gets worse by more than this many percent")
+	outDir := flag.String("out", "", "Directory to keep old.txt and new.txt in (default: a temporary directory)")
+	flag.Parse()
+
+	if err := run(*base, *bench, *count, *benchtime, *pkgs, *metric, *threshold, *outDir); err != nil {
+		fmt.Fprintf(os.Stderr, "benchcmp: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(base, bench string, count int, benchtime, pkgs, metric string, threshold float64, outDir string) error {
+	if outDir == "" {
+		dir, err := os.MkdirTemp("", "benchcmp")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+		outDir = dir
+	} else if err := os.MkdirAll(outDir, 0755); err != nil {
+		return err
+	}
+
+	worktree := filepath.Join(outDir, "base")
+	if err := command("", "git", "worktree", "add", "--detach", worktree, base).Run(); err != nil {
+		return fmt.Errorf("failed to check out %s: %w", base, err)
+	}
+	defer command("", "git", "worktree", "remove", "--force", worktree).Run()
+
+	args := []string{"test", "-run", "^$", "-bench", bench, "-benchmem", "-count", fmt.Sprint(count)}
+	if benchtime != "" {
+		args = append(args, "-benchtime", benchtime)
+	}
+	args = append(args, pkgs)
+
+	oldFile := filepath.Join(outDir, "old.txt")
+	newFile := filepath.Join(outDir, "new.txt")
+	fmt.Fprintf(os.Stderr, "Benchmarking %s...\n", base)
+	if err := benchmark(worktree, args, oldFile); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Benchmarking the working tree...\n")
+	if err := benchmark("", args, newFile); err != nil {
+		return err
+	}
+
+	if path, err := exec.LookPath("benchstat"); err == nil {
+		report := command("", path, oldFile, newFile)
+		report.Stdout = os.Stdout
+		report.Run()
+		fmt.Println()
+	} else {
+		fmt.Fprintln(os.Stderr, "Install benchstat for a full report: go install golang.org/x/perf/cmd/benchstat@latest")
+	}
+
+	old, err := readSamples(oldFile, metric)
+	if err != nil {
+		return err
+	}
+	current, err := readSamples(newFile, metric)
+	if err != nil {
+		return err
+	}
+	comparisons := compare(old, current)
+	if len(comparisons) == 0 {
+		return fmt.Errorf("no benchmark matching %q ran on both revisions", bench)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(w, "BENCHMARK\tOLD %s\tNEW %s\tDELTA\t\n", metric, metric)
+	for _, c := range comparisons {
+		fmt.Fprintf(w, "%s\t%.0f\t%.0f\t%+.1f%%\t\n", c.Name, c.Old, c.New, c.Delta())
+	}
+	w.Flush()
+
+	if worse := regressions(comparisons, threshold); len(worse) > 0 {
+		return fmt.Errorf("%d benchmark(s) regressed by more than %.0f%% in %s", len(worse), threshold, metric)
+	}
+	return nil
+}
+
+// benchmark runs go test in dir and writes its output to file
+func benchmark(dir string, args []string, file string) error {
+	var out bytes.Buffer
+	cmd := command(dir, "go", args...)
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		os.Stderr.Write(out.Bytes())
+		return fmt.Errorf("go test failed: %w", err)
+	}
+	return os.WriteFile(file, out.Bytes(), 0644)
+}
+
+func readSamples(file, metric string) (samples, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseBenchmarks(f, metric)
+}
+
+func command(dir, name string, args ...string) *exec.Cmd {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Stderr = os.Stderr
+	return cmd
+}
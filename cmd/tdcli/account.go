@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// AccountCmd reports account details and usage
+type AccountCmd struct {
+	Show    AccountShowCmd    `kong:"cmd,help='Show account details and compute quota'"`
+	Cores   AccountCoresCmd   `kong:"cmd,help='Show Hive core utilization history'"`
+	Storage AccountStorageCmd `kong:"cmd,help='Show storage usage per database'"`
+}
+
+type AccountShowCmd struct{}
+
+func (a *AccountShowCmd) Run(ctx *CLIContext) error {
+	handleAccountShow(ctx.Context, ctx.Client, ctx.GlobalFlags)
+	return nil
+}
+
+type AccountCoresCmd struct {
+	From string `kong:"help='Start of the range: a lookback (7d, 36h) or a date (2006-01-02, RFC 3339)'"`
+	To   string `kong:"help='End of the range, in the same formats as --from'"`
+}
+
+func (a *AccountCoresCmd) Run(ctx *CLIContext) error {
+	now := time.Now()
+	from, err := parseTimeBound(a.From, now)
+	if err != nil {
+		return fmt.Errorf("invalid --from: %w", err)
+	}
+	to, err := parseTimeBound(a.To, now)
+	if err != nil {
+		return fmt.Errorf("invalid --to: %w", err)
+	}
+	handleAccountCores(ctx.Context, ctx.Client, from, to, ctx.GlobalFlags)
+	return nil
+}
+
+type AccountStorageCmd struct{}
+
+func (a *AccountStorageCmd) Run(ctx *CLIContext) error {
+	handleAccountStorage(ctx.Context, ctx.Client, ctx.GlobalFlags)
+	return nil
+}
+
+func handleAccountShow(ctx context.Context, client *td.Client, flags Flags) {
+	account, err := client.Account.Show(ctx)
+	if err != nil {
+		handleError(err, "Failed to get account", flags.Verbose)
+		return
+	}
+
+	csvFormatter := func(data interface{}) string {
+		a := data.(*td.Account)
+		return fmt.Sprintf("%d,%d,%d,%d,%d,%s\n",
+			a.ID,
+			a.Plan,
+			a.StorageSize,
+			a.GuaranteedCores,
+			a.MaximumCores,
+			a.CreatedAt.Format("2006-01-02 15:04:05"),
+		)
+	}
+
+	tableFormatter := func(data interface{}) string {
+		a := data.(*td.Account)
+		var tableBuilder strings.Builder
+		w := tabwriter.NewWriter(&tableBuilder, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "PROPERTY\tVALUE")
+		fmt.Fprintf(w, "ID\t%d\n", a.ID)
+		fmt.Fprintf(w, "Plan\t%d\n", a.Plan)
+		fmt.Fprintf(w, "Storage\t%s\n", formatBytes(a.StorageSize))
+		fmt.Fprintf(w, "Guaranteed Cores\t%d\n", a.GuaranteedCores)
+		fmt.Fprintf(w, "Maximum Cores\t%d\n", a.MaximumCores)
+		fmt.Fprintf(w, "Created\t%s\n", a.CreatedAt.Format("2006-01-02 15:04:05"))
+		w.Flush()
+		return tableBuilder.String()
+	}
+
+	if err := formatAndWriteOutput(account, flags.Format, flags.Output, "id,plan,storage_size,guaranteed_cores,maximum_cores,created_at", csvFormatter, tableFormatter); err != nil {
+		handleError(err, "Failed to write output", flags.Verbose)
+	}
+}
+
+func handleAccountCores(ctx context.Context, client *td.Client, from, to time.Time, flags Flags) {
+	utilization, err := client.Account.CoreUtilization(ctx, from, to)
+	if err != nil {
+		handleError(err, "Failed to get core utilization", flags.Verbose)
+		return
+	}
+
+	// History samples are returned as [timestamp, cores] pairs
+	csvFormatter := func(data interface{}) string {
+		u := data.(*td.CoreUtilization)
+		var csvBuilder strings.Builder
+		for _, sample := range u.History {
+			csvBuilder.WriteString(formatCoreSample(sample, ","))
+			csvBuilder.WriteString("\n")
+		}
+		return csvBuilder.String()
+	}
+
+	tableFormatter := func(data interface{}) string {
+		u := data.(*td.CoreUtilization)
+		var tableBuilder strings.Builder
+		fmt.Fprintf(&tableBuilder, "From: %s  To: %s  Interval: %ds\n\n",
+			u.From.Format("2006-01-02 15:04:05"), u.To.Format("2006-01-02 15:04:05"), u.Interval)
+		if len(u.History) == 0 {
+			tableBuilder.WriteString("No utilization recorded\n")
+			return tableBuilder.String()
+		}
+		w := tabwriter.NewWriter(&tableBuilder, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TIME\tCORES")
+		for _, sample := range u.History {
+			fmt.Fprintln(w, formatCoreSample(sample, "\t"))
+		}
+		w.Flush()
+		return tableBuilder.String()
+	}
+
+	if err := formatAndWriteOutput(utilization, flags.Format, flags.Output, "time,cores", csvFormatter, tableFormatter); err != nil {
+		handleError(err, "Failed to write output", flags.Verbose)
+	}
+}
+
+// formatCoreSample renders a [timestamp, cores] history sample, falling back
+// to the raw value for other shapes
+func formatCoreSample(sample interface{}, sep string) string {
+	pair, ok := sample.([]interface{})
+	if !ok || len(pair) != 2 {
+		return fmt.Sprint(sample)
+	}
+	ts, ok := pair[0].(float64)
+	if !ok {
+		return fmt.Sprint(pair[0]) + sep + fmt.Sprint(pair[1])
+	}
+	return time.Unix(int64(ts), 0).UTC().Format("2006-01-02 15:04:05") + sep + fmt.Sprint(pair[1])
+}
+
+func handleAccountStorage(ctx context.Context, client *td.Client, flags Flags) {
+	usage, err := client.Account.StorageUsage(ctx)
+	if err != nil {
+		handleError(err, "Failed to get storage usage", flags.Verbose)
+		return
+	}
+
+	csvFormatter := func(data interface{}) string {
+		u := data.(*td.StorageUsage)
+		var csvBuilder strings.Builder
+		for _, db := range u.Databases {
+			csvBuilder.WriteString(fmt.Sprintf("%s,%d,%d,%d\n",
+				db.Database,
+				db.Tables,
+				db.Records,
+				db.EstimatedStorageSize,
+			))
+		}
+		return csvBuilder.String()
+	}
+
+	tableFormatter := func(data interface{}) string {
+		u := data.(*td.StorageUsage)
+		if len(u.Databases) == 0 {
+			return "No databases found\n"
+		}
+		var tableBuilder strings.Builder
+		w := tabwriter.NewWriter(&tableBuilder, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "DATABASE\tTABLES\tRECORDS\tSIZE")
+		for _, db := range u.Databases {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%s\n",
+				db.Database,
+				db.Tables,
+				db.Records,
+				formatBytes(db.EstimatedStorageSize),
+			)
+		}
+		w.Flush()
+		tableBuilder.WriteString(fmt.Sprintf("\nAccount storage: %s\n", formatBytes(u.StorageSize)))
+		return tableBuilder.String()
+	}
+
+	if err := formatAndWriteOutput(usage, flags.Format, flags.Output, "database,tables,records,estimated_storage_size", csvFormatter, tableFormatter); err != nil {
+		handleError(err, "Failed to write output", flags.Verbose)
+	}
+}
@@ -0,0 +1,491 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// The audit trail is enabled by the [audit] section of the configuration. Every
+// mutating API request (anything but GET and HEAD) appends one record to a
+// JSONL file. Each record carries an HMAC-SHA256 signature over its content
+// and the previous record's signature, so editing, reordering or deleting
+// records is detected by "tdcli audit verify". Request bodies are never
+// recorded.
+
+// auditTailSize is how much of the log is read to find the last record
+const auditTailSize = 64 * 1024
+
+// auditLockTimeout bounds how long a record waits for another tdcli process
+// appending to the same log; a lock file older than auditLockStale was left
+// by a process that died holding it and is broken
+const (
+	auditLockTimeout = 10 * time.Second
+	auditLockStale   = time.Minute
+)
+
+// AuditConfig is the [audit] section of the configuration file
+type AuditConfig struct {
+	// Enabled turns on the audit trail. A project config cannot turn off an
+	// audit trail enabled in the home config.
+	Enabled bool `toml:"enabled"`
+	// File is the audit log; defaults to ~/.tdcli/audit.jsonl
+	File string `toml:"file,omitempty"`
+	// KeyFile holds the signing key; defaults to ~/.tdcli/audit.key, which is
+	// generated on first use
+	KeyFile string `toml:"key_file,omitempty"`
+	// Table, as database.table, receives the records through streaming
+	// import after each command
+	Table string `toml:"table,omitempty"`
+}
+
+// auditRecord is one mutating request in the audit log
+type auditRecord struct {
+	Seq       int64     `json:"seq"`
+	Time      time.Time `json:"time"`
+	User      string    `json:"user"`
+	Account   string    `json:"account,omitempty"`
+	Profile   string    `json:"profile,omitempty"`
+	Command   string    `json:"command"`
+	Method    string    `json:"method"`
+	Host      string    `json:"host"`
+	Path      string    `json:"path"`
+	Status    int       `json:"status,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Prev      string    `json:"prev"`
+	Signature string    `json:"signature"`
+}
+
+// auditLog appends signed records to the audit file
+type auditLog struct {
+	path    string
+	key     []byte
+	table   string
+	user    string
+	account string
+	profile string
+	command string
+
+	mu      sync.Mutex
+	written bool
+}
+
+// auditSkipKey marks the context of the requests that ship the audit log,
+// which are not audited themselves
+type auditSkipKey struct{}
+
+func defaultAuditPaths() (file, keyFile string, err error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", err
+	}
+	dir := filepath.Join(homeDir, ".tdcli")
+	return filepath.Join(dir, "audit.jsonl"), filepath.Join(dir, "audit.key"), nil
+}
+
+// openAuditLog returns the audit log configured in config, or nil when the
+// audit trail is off
+func openAuditLog(config *Config) (*auditLog, error) {
+	if config == nil || config.Audit == nil || !config.Audit.Enabled {
+		return nil, nil
+	}
+	file, keyFile, err := defaultAuditPaths()
+	if err != nil {
+		return nil, err
+	}
+	if config.Audit.File != "" {
+		file = config.Audit.File
+	}
+	if config.Audit.KeyFile != "" {
+		keyFile = config.Audit.KeyFile
+	}
+	key, err := loadAuditKey(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &auditLog{path: file, key: key, table: config.Audit.Table, user: auditUser()}, nil
+}
+
+// loadAuditKey reads the hex signing key, generating it if the file is missing
+func loadAuditKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(key) == 0 {
+			return nil, fmt.Errorf("audit key %s is not a hex string", path)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key)+"\n"), 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// auditUser names the local user running tdcli
+func auditUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// begin sets who runs which command for the records written from now on
+func (a *auditLog) begin(apiKey, profile, command string) {
+	if a == nil {
+		return
+	}
+	a.account, _, _ = strings.Cut(apiKey, "/")
+	a.profile = profile
+	a.command = telemetryCommandName(command)
+}
+
+// middleware records every mutating request after its response arrives
+func (a *auditLog) middleware() td.Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return td.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(req)
+			if req.Method == http.MethodGet || req.Method == http.MethodHead {
+				return resp, err
+			}
+			if skip, _ := req.Context().Value(auditSkipKey{}).(bool); skip {
+				return resp, err
+			}
+
+			record := auditRecord{
+				Method: req.Method,
+				Host:   req.URL.Host,
+				Path:   req.URL.Path,
+			}
+			if resp != nil {
+				record.Status = resp.StatusCode
+			}
+			if err != nil {
+				record.Error = err.Error()
+			}
+			if appendErr := a.append(record); appendErr != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to write audit record: %v\n", appendErr)
+			}
+			return resp, err
+		})
+	}
+}
+
+// append signs record, chaining it to the last record in the file
+func (a *auditLog) append(record auditRecord) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(a.path), 0700); err != nil {
+		return err
+	}
+	// Other tdcli processes may append to the same log; reading the last
+	// record and appending must happen under one lock or the chain forks
+	unlock, err := lockAuditLog(a.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	last, err := lastAuditRecord(a.path)
+	if err != nil {
+		return err
+	}
+	if last != nil {
+		record.Seq = last.Seq + 1
+		record.Prev = last.Signature
+	} else {
+		record.Seq = 1
+	}
+	record.Time = time.Now().UTC()
+	record.User = a.user
+	record.Account = a.account
+	record.Profile = a.profile
+	record.Command = a.command
+	record.Signature = signAuditRecord(a.key, record)
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(a.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	a.written = true
+	return nil
+}
+
+// lockAuditLog takes an exclusive lock on the log at path by creating
+// path.lock, waiting while another process holds it. The returned function
+// releases the lock.
+func lockAuditLog(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(auditLockTimeout)
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock audit log: %v", err)
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > auditLockStale {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for audit log lock %s", lockPath)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// signAuditRecord returns the hex HMAC of record without its signature
+func signAuditRecord(key []byte, record auditRecord) string {
+	record.Signature = ""
+	data, _ := json.Marshal(record)
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func readAuditLog(path string) ([]auditRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []auditRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var record auditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// lastAuditRecord returns the last record of the log, or nil if it is empty.
+// Only the end of the file is read, so appending stays cheap as the log grows.
+func lastAuditRecord(path string) (*auditRecord, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := info.Size() - auditTailSize
+	if offset < 0 {
+		offset = 0
+	}
+	tail := make([]byte, info.Size()-offset)
+	if _, err := file.ReadAt(tail, offset); err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(tail), "\n"), "\n")
+	line := lines[len(lines)-1]
+	if line == "" {
+		return nil, nil
+	}
+	var record auditRecord
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		return nil, fmt.Errorf("audit log %s ends with an unreadable record: %v", path, err)
+	}
+	return &record, nil
+}
+
+// verifyAuditLog checks every signature and the chain between records and
+// returns the number of records
+func verifyAuditLog(path string, key []byte) (int, error) {
+	records, err := readAuditLog(path)
+	if err != nil {
+		return 0, err
+	}
+	prev := ""
+	for i, record := range records {
+		if record.Seq != int64(i+1) {
+			return i, fmt.Errorf("record %d: sequence %d, expected %d (records were removed or reordered)", i+1, record.Seq, i+1)
+		}
+		if record.Prev != prev {
+			return i, fmt.Errorf("record %d: does not follow the previous record", record.Seq)
+		}
+		if !hmac.Equal([]byte(record.Signature), []byte(signAuditRecord(key, record))) {
+			return i, fmt.Errorf("record %d: signature mismatch (the record was modified)", record.Seq)
+		}
+		prev = record.Signature
+	}
+	return len(records), nil
+}
+
+func (a *auditLog) shippedPath() string {
+	return a.path + ".shipped"
+}
+
+// ship imports the records after the last shipped one into the audit table.
+// The requests it sends are not audited themselves.
+func (a *auditLog) ship(ctx context.Context, client *td.Client) (int, error) {
+	database, table, ok := strings.Cut(a.table, ".")
+	if !ok || database == "" || table == "" {
+		return 0, fmt.Errorf("audit table must be database.table, got %q", a.table)
+	}
+
+	records, err := readAuditLog(a.path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	// A tampered log is not shipped: the table would then hold records the
+	// chain cannot vouch for
+	if _, err := verifyAuditLog(a.path, a.key); err != nil {
+		return 0, fmt.Errorf("audit log %s failed verification: %v", a.path, err)
+	}
+	var shipped int64
+	if data, err := os.ReadFile(a.shippedPath()); err == nil {
+		shipped, _ = strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	}
+
+	var rows []map[string]interface{}
+	for _, record := range records {
+		if record.Seq <= shipped {
+			continue
+		}
+		rows = append(rows, map[string]interface{}{
+			"time":      record.Time.Unix(),
+			"seq":       record.Seq,
+			"user":      record.User,
+			"account":   record.Account,
+			"profile":   record.Profile,
+			"command":   record.Command,
+			"method":    record.Method,
+			"host":      record.Host,
+			"path":      record.Path,
+			"status":    record.Status,
+			"error":     record.Error,
+			"prev":      record.Prev,
+			"signature": record.Signature,
+		})
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	// Requests made by other goroutines meanwhile are still audited; only
+	// the import below is skipped
+	ctx = context.WithValue(ctx, auditSkipKey{}, true)
+
+	// The last signature identifies the batch, so a retried import is not
+	// stored twice
+	last := records[len(records)-1]
+	if _, err := client.Import.ImportRecords(ctx, database, table, last.Signature[:32], rows); err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(a.shippedPath(), []byte(strconv.FormatInt(last.Seq, 10)+"\n"), 0600); err != nil {
+		return 0, err
+	}
+	return len(rows), nil
+}
+
+// finish ships the records written by this command when a table is configured
+func (a *auditLog) finish(ctx context.Context, client *td.Client) {
+	if a == nil || !a.written || a.table == "" || client == nil {
+		return
+	}
+	if _, err := a.ship(ctx, client); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to ship audit records to %s: %v\n", a.table, err)
+	}
+}
+
+// AuditCmd inspects the audit trail
+type AuditCmd struct {
+	Verify AuditVerifyCmd `kong:"cmd,help='Check the signatures and order of the audit log'"`
+	Ship   AuditShipCmd   `kong:"cmd,help='Import audit records not yet shipped into the audit table'"`
+}
+
+type AuditVerifyCmd struct {
+	File string `kong:"help='Audit log to check (default: the configured one)'"`
+}
+
+func (a *AuditVerifyCmd) Run(ctx *CLIContext) error {
+	log, err := openAuditLog(ctx.Config)
+	if err != nil {
+		return err
+	}
+	if log == nil {
+		return fmt.Errorf("the audit trail is not enabled; set enabled = true in the [audit] section of the configuration")
+	}
+	path := log.path
+	if a.File != "" {
+		path = a.File
+	}
+
+	count, err := verifyAuditLog(path, log.key)
+	if err != nil {
+		return fmt.Errorf("audit log %s failed verification after %d valid records: %v", path, count, err)
+	}
+	fmt.Printf("Audit log %s: %d records verified\n", path, count)
+	return nil
+}
+
+type AuditShipCmd struct{}
+
+func (a *AuditShipCmd) Run(ctx *CLIContext) error {
+	log, err := openAuditLog(ctx.Config)
+	if err != nil {
+		return err
+	}
+	if log == nil || log.table == "" {
+		return fmt.Errorf("no audit table is configured; set table in the [audit] section of the configuration")
+	}
+	count, err := log.ship(ctx.Context, ctx.Client)
+	if err != nil {
+		return fmt.Errorf("failed to ship audit records: %v", err)
+	}
+	fmt.Printf("Shipped %d audit records to %s\n", count, log.table)
+	return nil
+}
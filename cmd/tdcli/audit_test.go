@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+func newTestAuditLog(t *testing.T) *auditLog {
+	t.Helper()
+	dir := t.TempDir()
+	log, err := openAuditLog(&Config{Audit: &AuditConfig{
+		Enabled: true,
+		File:    filepath.Join(dir, "audit.jsonl"),
+		KeyFile: filepath.Join(dir, "audit.key"),
+		Table:   "ops.audit",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.begin("1/secret", "prod", "tables delete <database> <table>")
+	return log
+}
+
+func TestAuditLog_RecordsMutatingRequests(t *testing.T) {
+	var imported []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/v3/table/import") {
+			imported = append(imported, r.URL.Path)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	audit := newTestAuditLog(t)
+	client, err := td.NewClient("1/secret", td.WithEndpoint(server.URL), td.WithMiddleware(audit.middleware()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	client.Databases.List(ctx)
+	client.Tables.Delete(ctx, "db", "t1")
+	client.Tables.Delete(ctx, "db", "t2")
+
+	records, err := readAuditLog(audit.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2 (GET requests are not audited)", len(records))
+	}
+	r := records[1]
+	if r.Seq != 2 || r.Prev != records[0].Signature || r.Method != "POST" || r.Path != "/v3/table/delete/db/t2" ||
+		r.Status != 200 || r.Account != "1" || r.Profile != "prod" || r.Command != "tables delete" {
+		t.Errorf("record = %+v", r)
+	}
+	if count, err := verifyAuditLog(audit.path, audit.key); err != nil || count != 2 {
+		t.Errorf("verifyAuditLog = %d, %v", count, err)
+	}
+
+	// Shipping sends the new records once and is not audited itself
+	audit.finish(ctx, client)
+	audit.finish(ctx, client)
+	if len(imported) != 1 || imported[0] != "/v3/table/import_with_id/ops/audit/"+records[1].Signature[:32]+"/msgpack.gz" {
+		t.Errorf("imported = %v", imported)
+	}
+	if records, _ := readAuditLog(audit.path); len(records) != 2 {
+		t.Errorf("shipping added audit records: %d", len(records))
+	}
+}
+
+func TestAuditLog_ConcurrentProcesses(t *testing.T) {
+	first := newTestAuditLog(t)
+
+	// Separate auditLog values share no mutex, like two tdcli processes
+	// writing the same file; only the lock file keeps the chain intact
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		log := &auditLog{path: first.path, key: first.key, user: "u"}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				if err := log.append(auditRecord{Method: "POST", Path: "/v3/table/delete/db/t"}); err != nil {
+					t.Errorf("append returned error: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if count, err := verifyAuditLog(first.path, first.key); err != nil || count != 200 {
+		t.Errorf("verifyAuditLog = %d, %v, want 200 chained records", count, err)
+	}
+	if _, err := os.Stat(first.path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock file left behind: %v", err)
+	}
+}
+
+func TestVerifyAuditLog_DetectsTampering(t *testing.T) {
+	audit := newTestAuditLog(t)
+	for _, path := range []string{"/v3/table/delete/db/a", "/v3/table/delete/db/b", "/v3/table/delete/db/c"} {
+		if err := audit.append(auditRecord{Method: "POST", Host: "api.treasuredata.com", Path: path, Status: 200}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	original, err := os.ReadFile(audit.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(string(original), "\n")
+
+	tests := map[string]string{
+		"edited":  strings.Replace(string(original), "/db/b", "/db/x", 1),
+		"removed": lines[0] + lines[2],
+		"swapped": lines[1] + lines[0] + lines[2],
+	}
+	for name, content := range tests {
+		if err := os.WriteFile(audit.path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := verifyAuditLog(audit.path, audit.key); err == nil {
+			t.Errorf("%s log passed verification", name)
+		}
+	}
+
+	if _, err := verifyAuditLog(audit.path, []byte("another key")); err == nil {
+		t.Error("log passed verification with another key")
+	}
+}
+
+func TestAuditLog_ShipRefusesTamperedLog(t *testing.T) {
+	var imports int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		imports++
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client, err := td.NewClient("1/secret", td.WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	audit := newTestAuditLog(t)
+	if err := audit.append(auditRecord{Method: "POST", Path: "/v3/table/delete/db/a"}); err != nil {
+		t.Fatal(err)
+	}
+	// A hand-edited last line with a short signature must not panic
+	record := `{"seq":2,"method":"POST","path":"/v3/table/delete/db/b","prev":"x","signature":"short"}` + "\n"
+	f, err := os.OpenFile(audit.path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(record)
+	f.Close()
+
+	if _, err := audit.ship(context.Background(), client); err == nil || !strings.Contains(err.Error(), "failed verification") {
+		t.Errorf("ship error = %v, want a verification failure", err)
+	}
+	if imports != 0 {
+		t.Errorf("a tampered log was shipped")
+	}
+}
+
+func TestAuditLog_AuditsRequestsWhileShipping(t *testing.T) {
+	audit := newTestAuditLog(t)
+	var client *td.Client
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Another goroutine's change lands while the import is in flight
+		if strings.HasPrefix(r.URL.Path, "/v3/table/import") {
+			client.Tables.Delete(context.Background(), "db", "during")
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	var err error
+	client, err = td.NewClient("1/secret", td.WithEndpoint(server.URL), td.WithMiddleware(audit.middleware()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.Tables.Delete(context.Background(), "db", "before")
+	if _, err := audit.ship(context.Background(), client); err != nil {
+		t.Fatal(err)
+	}
+	records, err := readAuditLog(audit.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[1].Path != "/v3/table/delete/db/during" {
+		t.Errorf("records = %+v, want the delete made while shipping", records)
+	}
+}
+
+func TestMergeConfig_AuditCannotBeDisabled(t *testing.T) {
+	target := &Config{Audit: &AuditConfig{Enabled: true}}
+	mergeConfig(target, &Config{Audit: &AuditConfig{Enabled: false, Table: "ops.audit"}})
+	if !target.Audit.Enabled || target.Audit.Table != "ops.audit" {
+		t.Errorf("audit = %+v", target.Audit)
+	}
+}
@@ -1,10 +1,17 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	td "github.com/mickeey2525/treasuredata-go-sdk"
 )
@@ -59,11 +66,13 @@ SUBCOMMANDS:
     create <session> <database> <table>  Create a new bulk import session
     delete, rm <session>   Delete a bulk import session
     upload <session> <part> <file>  Upload a part to session
+    upload-dir <session> <dir>      Upload every matching file in a directory
     commit <session>       Commit a bulk import session
-    perform <session>      Perform bulk import job
+    perform <session>      Perform bulk import job (--wait, --report)
     freeze <session>       Freeze a bulk import session
     unfreeze <session>     Unfreeze a bulk import session
     parts <session>        List parts in a bulk import session
+    bridge --from SRC --to DB.TABLE  Stream records from Kafka or JSONL
 
 OPTIONS:
     --format FORMAT        Output format (json, table, csv)
@@ -74,9 +83,11 @@ EXAMPLES:
     tdcli import show my_session
     tdcli import create my_session my_db my_table
     tdcli import upload my_session part1 data.csv
+    tdcli import upload-dir my_session ./data --pattern '*.csv' --parallel 8
     tdcli import commit my_session
-    tdcli import perform my_session
+    tdcli import perform my_session --wait --report
     tdcli import parts my_session
+    tdcli import bridge --from file://events.jsonl --to my_db.events
 
 `)
 }
@@ -159,6 +170,12 @@ func handleBulkImportDelete(ctx context.Context, client *td.Client, args []strin
 	err := client.BulkImport.Delete(ctx, sessionName)
 	handleError(err, "Failed to delete bulk import session", flags.Verbose)
 
+	if ledger, err := defaultPartLedger(client); err == nil {
+		if err := ledger.Forget(sessionName); err != nil && flags.Verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to remove part ledger: %v\n", err)
+		}
+	}
+
 	if flags.Verbose {
 		fmt.Printf("Successfully deleted bulk import session: %s\n", sessionName)
 	} else {
@@ -166,7 +183,21 @@ func handleBulkImportDelete(ctx context.Context, client *td.Client, args []strin
 	}
 }
 
+// bulkImportUploadOptions controls conversion and validation of JSONL part uploads
+type bulkImportUploadOptions struct {
+	Schema        string
+	DeadLetter    string
+	TimeField     string
+	MaxFutureSkew time.Duration
+	// Force re-uploads parts the local ledger has already recorded
+	Force bool
+}
+
 func handleBulkImportUpload(ctx context.Context, client *td.Client, args []string, flags Flags) {
+	handleBulkImportUploadWithOptions(ctx, client, args, bulkImportUploadOptions{}, flags)
+}
+
+func handleBulkImportUploadWithOptions(ctx context.Context, client *td.Client, args []string, opts bulkImportUploadOptions, flags Flags) {
 	if len(args) < 3 {
 		fmt.Println("Error: Session name, part name, and file path required")
 		fmt.Println("Usage: tdcli import upload <session_name> <part_name> <file_path>")
@@ -177,20 +208,37 @@ func handleBulkImportUpload(ctx context.Context, client *td.Client, args []strin
 	partName := args[1]
 	filePath := args[2]
 
-	// Open file
-	file, err := os.Open(filePath)
-	if err != nil {
-		handleError(err, "Failed to open file", flags.Verbose)
-		return
-	}
-	defer file.Close()
+	schemaPath, err := resolveSessionSchema(ctx, client, sessionName, opts.Schema)
+	handleError(err, "Failed to get bulk import session", flags.Verbose)
+
+	validator, err := newRecordValidator(schemaPath, opts.DeadLetter)
+	handleError(err, "Failed to set up record validation", flags.Verbose)
 
 	if flags.Verbose {
 		fmt.Printf("Uploading file %s as part %s to session %s...\n", filePath, partName, sessionName)
 	}
 
-	err = client.BulkImport.UploadPart(ctx, sessionName, partName, file)
+	result, err := uploadPartFile(ctx, client, openPartLedger(ctx, client, flags.Verbose), validator, sessionName, partName, filePath, schemaPath, opts)
+	closeErr := validator.Close()
 	handleError(err, "Failed to upload part", flags.Verbose)
+	handleError(closeErr, "Failed to write dead-letter file", flags.Verbose)
+
+	switch {
+	case result.Conflict != nil:
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", result.Conflict)
+		fmt.Fprintln(os.Stderr, "Use --force to overwrite it or upload under a different part name")
+		return
+	case !result.Uploaded:
+		fmt.Printf("Skipped part %s: identical content already uploaded\n", partName)
+		return
+	}
+
+	if result.Converted {
+		if validator != nil {
+			fmt.Printf("Validation: %s\n", validator.Summary())
+		}
+		fmt.Printf("Part %s: %d records, time %s\n", partName, result.Times.Count, result.Times)
+	}
 
 	if flags.Verbose {
 		fmt.Printf("Successfully uploaded part %s to session %s\n", partName, sessionName)
@@ -199,6 +247,178 @@ func handleBulkImportUpload(ctx context.Context, client *td.Client, args []strin
 	}
 }
 
+// resolveSessionSchema returns the schema to validate a session's records
+// against: the flag, or the import_schemas entry for the session's table
+func resolveSessionSchema(ctx context.Context, client *td.Client, sessionName, flagSchema string) (string, error) {
+	if flagSchema != "" {
+		return flagSchema, nil
+	}
+
+	config, err := LoadConfig()
+	if err != nil || len(config.ImportSchemas) == 0 {
+		return "", nil
+	}
+
+	session, err := client.BulkImport.Show(ctx, sessionName)
+	if err != nil {
+		return "", err
+	}
+	return resolveImportSchema("", session.Database, session.Table, config), nil
+}
+
+// openPartLedger returns the on-disk part ledger of the client's account,
+// falling back to an in-memory one when the home directory is unavailable.
+// Recorded parts are checked against the session's part list before they
+// are skipped.
+func openPartLedger(ctx context.Context, client *td.Client, verbose bool) td.PartLedger {
+	ledger, err := defaultPartLedger(client)
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Warning: part ledger unavailable: %v\n", err)
+		}
+		return newVerifiedPartLedger(ctx, client, td.NewMemoryPartLedger())
+	}
+	return newVerifiedPartLedger(ctx, client, ledger)
+}
+
+// partFileResult describes the outcome of uploading one file as a part
+type partFileResult struct {
+	Part      string
+	Hash      string
+	Uploaded  bool
+	Converted bool
+	Times     timeRange
+	// Conflict is set when the part name was already used for different
+	// content and the upload was not forced
+	Conflict error
+}
+
+// uploadPartFile uploads a file as a bulk import part. JSONL files are
+// converted to msgpack.gz with a normalized time column, and validated when a
+// validator is given; anything else is uploaded as-is, and is an error when a
+// schema is configured because it cannot be validated. Parts the ledger
+// already holds with identical content are skipped unless opts.Force is set.
+func uploadPartFile(ctx context.Context, client *td.Client, ledger td.PartLedger, validator *recordValidator, sessionName, partName, filePath, schemaPath string, opts bulkImportUploadOptions) (*partFileResult, error) {
+	convert := isJSONLFile(filePath)
+	if validator != nil && !convert {
+		return nil, fmt.Errorf("%s: schema validation only supports JSONL files (.jsonl, .ndjson)", filePath)
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Parts are identified by the source content plus the conversion settings,
+	// so re-running an upload skips parts the session already has
+	hash, err := hashUploadSource(file, convert, schemaPath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &partFileResult{Part: partName, Hash: hash, Converted: convert}
+	state, err := td.CheckPart(ledger, sessionName, partName, hash)
+	switch {
+	case state == td.PartUnchanged && !opts.Force:
+		return result, nil
+	case state == td.PartConflict && !opts.Force:
+		result.Conflict = err
+		return result, nil
+	case err != nil && state != td.PartConflict:
+		return nil, err
+	}
+
+	var data io.Reader = file
+	if convert {
+		converter := &recordConverter{TimeField: opts.TimeField, MaxFutureSkew: opts.MaxFutureSkew}
+		encoded, times, err := convertJSONLPart(file, filePath, converter, validator)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %v", filePath, err)
+		}
+		result.Times = times
+		data = bytes.NewReader(encoded)
+	}
+
+	if err := client.BulkImport.UploadPart(ctx, sessionName, partName, data); err != nil {
+		return nil, err
+	}
+	result.Uploaded = true
+
+	if err := ledger.RecordPart(sessionName, partName, hash); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record part %s in ledger: %v\n", partName, err)
+	}
+	return result, nil
+}
+
+// hashUploadSource hashes a part's source file, and for converted uploads the
+// settings that shape the converted output, then rewinds the file
+func hashUploadSource(file *os.File, convert bool, schemaPath string, opts bulkImportUploadOptions) (string, error) {
+	var r io.Reader = file
+	if convert {
+		settings := fmt.Sprintf("convert schema=%s time_field=%s max_future_skew=%s\n", schemaPath, opts.TimeField, opts.MaxFutureSkew)
+		r = io.MultiReader(strings.NewReader(settings), file)
+	}
+
+	hash, err := td.HashPart(r)
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return hash, nil
+}
+
+// isJSONLFile reports whether path looks like newline-delimited JSON
+func isJSONLFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".jsonl" || ext == ".ndjson"
+}
+
+// convertJSONLPart converts each JSON line of r into a record, validating it when
+// a validator is given, and returns the accepted records encoded as msgpack.gz
+func convertJSONLPart(r io.Reader, source string, converter *recordConverter, validator *recordValidator) ([]byte, timeRange, error) {
+	reader := bufio.NewReader(r)
+	var records []map[string]interface{}
+	var times timeRange
+	var offset int64
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) == 0 && err == io.EOF {
+			break
+		}
+		if err != nil && err != io.EOF {
+			return nil, times, err
+		}
+
+		lineOffset := offset
+		offset += int64(len(line))
+
+		raw := bytes.TrimSpace(line)
+		if len(raw) > 0 {
+			record, ts, convErr := converter.Convert(raw)
+			switch {
+			case convErr != nil && validator != nil:
+				validator.Reject(source, lineOffset, raw, convErr)
+			case convErr != nil:
+				fmt.Fprintf(os.Stderr, "Warning: skipping record at offset %d: %v\n", lineOffset, convErr)
+			case validator == nil || validator.Validate(source, lineOffset, raw, record):
+				records = append(records, record)
+				times.observe(ts)
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	encoded, err := td.EncodeImportRecords(records)
+	return encoded, times, err
+}
+
 func handleBulkImportCommit(ctx context.Context, client *td.Client, args []string, flags Flags) {
 	if len(args) == 0 {
 		fmt.Println("Error: Session name required")
@@ -228,7 +448,19 @@ func handleBulkImportCommit(ctx context.Context, client *td.Client, args []strin
 	}
 }
 
+// bulkImportPerformOptions controls waiting for and reporting on the perform job
+type bulkImportPerformOptions struct {
+	Wait bool
+	// Report prints a per-column summary of rejected records; it implies Wait
+	Report  bool
+	Timeout time.Duration
+}
+
 func handleBulkImportPerform(ctx context.Context, client *td.Client, args []string, flags Flags) {
+	handleBulkImportPerformWithOptions(ctx, client, args, bulkImportPerformOptions{}, flags)
+}
+
+func handleBulkImportPerformWithOptions(ctx context.Context, client *td.Client, args []string, opts bulkImportPerformOptions, flags Flags) {
 	if len(args) == 0 {
 		fmt.Println("Error: Session name required")
 		fmt.Println("Usage: tdcli import perform <session_name>")
@@ -250,6 +482,65 @@ func handleBulkImportPerform(ctx context.Context, client *td.Client, args []stri
 	} else {
 		fmt.Printf("Started bulk import job: %s\n", job.JobID)
 	}
+
+	if !opts.Wait && !opts.Report {
+		return
+	}
+
+	fmt.Printf("Waiting for job %s to complete...\n", job.JobID)
+	job, err = waitForJob(ctx, client, job.JobID, opts.Timeout)
+	handleError(err, "Failed to wait for bulk import job", flags.Verbose)
+
+	session, err := client.BulkImport.Show(ctx, sessionName)
+	handleError(err, "Failed to get bulk import session", flags.Verbose)
+	fmt.Printf("Job %s finished with status %s: %d valid records, %d error records, %d error parts\n",
+		job.JobID, job.Status, session.ValidRecords, session.ErrorRecords, session.ErrorParts)
+
+	if opts.Report && job.Status == "success" {
+		report, err := client.BulkImport.CoercionReport(ctx, sessionName)
+		handleError(err, "Failed to build error report", flags.Verbose)
+		if flags.Format == "json" {
+			printJSON(report)
+		} else {
+			printCoercionReport(report)
+		}
+	}
+
+	if job.Status != "success" {
+		if job.Debug != nil && job.Debug.Stderr != "" {
+			fmt.Printf("Error: %s\n", job.Debug.Stderr)
+		}
+		os.Exit(1)
+	}
+}
+
+func printCoercionReport(report *td.CoercionReport) {
+	if report.ErrorRecords == 0 {
+		fmt.Println("No records were rejected")
+		return
+	}
+
+	fmt.Printf("\nRejected records by column (%d of %d error records analyzed against %s.%s):\n",
+		report.RecordsAnalyzed, report.ErrorRecords, report.Database, report.Table)
+	if len(report.Columns) == 0 {
+		fmt.Println("No column type mismatches found; records may have been rejected for other reasons")
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "COLUMN\tTYPE\tINVALID\tMISSING\tSAMPLES")
+		for _, c := range report.Columns {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n", c.Column, c.Type, c.Invalid, c.Missing, strings.Join(c.Samples, ", "))
+		}
+		w.Flush()
+	}
+
+	if len(report.UnknownColumns) > 0 {
+		names := make([]string, 0, len(report.UnknownColumns))
+		for name := range report.UnknownColumns {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Printf("Fields not in the table schema: %s\n", strings.Join(names, ", "))
+	}
 }
 
 func handleBulkImportFreeze(ctx context.Context, client *td.Client, args []string, flags Flags) {
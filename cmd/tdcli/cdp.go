@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"time"
 
 	td "github.com/mickeey2525/treasuredata-go-sdk"
 	cdphandlers "github.com/mickeey2525/treasuredata-go-sdk/cmd/tdcli/cdp"
@@ -50,6 +51,10 @@ func handleCDPSegmentDelete(ctx context.Context, client *td.Client, args []strin
 	cdphandlers.HandleSegmentDelete(ctx, client, args, buildCDPFlags(flags))
 }
 
+func handleCDPSegmentRenameAttribute(ctx context.Context, client *td.Client, args []string, behavior string, dryRun bool, flags Flags) {
+	cdphandlers.HandleSegmentRenameAttribute(ctx, client, args, behavior, dryRun, buildCDPFlags(flags))
+}
+
 // CDP audience handlers
 func handleCDPAudienceCreate(ctx context.Context, client *td.Client, args []string, flags Flags) {
 	cdphandlers.HandleAudienceCreate(ctx, client, args, buildCDPFlags(flags))
@@ -80,6 +85,18 @@ func handleCDPAudienceBehaviors(ctx context.Context, client *td.Client, args []s
 	cdphandlers.HandleAudienceBehaviors(ctx, client, args, buildCDPFlags(flags))
 }
 
+func handleCDPAudienceAttributeUsage(ctx context.Context, client *td.Client, args []string, unusedOnly bool, flags Flags) {
+	cdphandlers.HandleAudienceAttributeUsage(ctx, client, args, unusedOnly, buildCDPFlags(flags))
+}
+
+func handleCDPAudienceExport(ctx context.Context, client *td.Client, args []string, flags Flags) {
+	cdphandlers.HandleAudienceExport(ctx, client, args, buildCDPFlags(flags))
+}
+
+func handleCDPAudienceImport(ctx context.Context, client *td.Client, args []string, name string, connections []string, skipActivations bool, flags Flags) {
+	cdphandlers.HandleAudienceImport(ctx, client, args, name, connections, skipActivations, buildCDPFlags(flags))
+}
+
 func handleCDPAudienceRun(ctx context.Context, client *td.Client, args []string, flags Flags) {
 	cdphandlers.HandleAudienceRun(ctx, client, args, buildCDPFlags(flags))
 }
@@ -92,12 +109,16 @@ func handleCDPAudienceStatistics(ctx context.Context, client *td.Client, args []
 	cdphandlers.HandleAudienceStatistics(ctx, client, args, buildCDPFlags(flags))
 }
 
-func handleCDPAudienceSampleValues(ctx context.Context, client *td.Client, args []string, flags Flags) {
-	cdphandlers.HandleAudienceSampleValues(ctx, client, args, buildCDPFlags(flags))
+func handleCDPAudienceSampleValues(ctx context.Context, client *td.Client, args []string, offset int, flags Flags) {
+	cdpFlags := buildCDPFlags(flags)
+	cdpFlags.Offset = offset
+	cdphandlers.HandleAudienceSampleValues(ctx, client, args, cdpFlags)
 }
 
-func handleCDPAudienceBehaviorSamples(ctx context.Context, client *td.Client, args []string, flags Flags) {
-	cdphandlers.HandleAudienceBehaviorSamples(ctx, client, args, buildCDPFlags(flags))
+func handleCDPAudienceBehaviorSamples(ctx context.Context, client *td.Client, args []string, offset int, flags Flags) {
+	cdpFlags := buildCDPFlags(flags)
+	cdpFlags.Offset = offset
+	cdphandlers.HandleAudienceBehaviorSamples(ctx, client, args, cdpFlags)
 }
 
 // CDP segment folder handlers
@@ -167,10 +188,18 @@ func handleCDPGetActivationExecutions(ctx context.Context, client *td.Client, ar
 	cdphandlers.HandleActivationGetExecutions(ctx, client, args, buildCDPFlags(flags))
 }
 
+func handleCDPGetActivationExecution(ctx context.Context, client *td.Client, args []string, flags Flags) {
+	cdphandlers.HandleActivationGetExecution(ctx, client, args, buildCDPFlags(flags))
+}
+
 func handleCDPListActivationsByAudience(ctx context.Context, client *td.Client, args []string, flags Flags) {
 	cdphandlers.HandleActivationListByAudience(ctx, client, args, buildCDPFlags(flags))
 }
 
+func handleCDPActivationHistory(ctx context.Context, client *td.Client, audienceID string, since time.Time, flags Flags) {
+	cdphandlers.HandleActivationHistory(ctx, client, audienceID, since, buildCDPFlags(flags))
+}
+
 func handleCDPListActivationsBySegmentFolder(ctx context.Context, client *td.Client, args []string, flags Flags) {
 	cdphandlers.HandleActivationListBySegmentFolder(ctx, client, args, buildCDPFlags(flags))
 }
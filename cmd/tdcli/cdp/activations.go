@@ -2,11 +2,16 @@ package cdp
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"text/tabwriter"
+	"time"
 
 	td "github.com/mickeey2525/treasuredata-go-sdk"
 )
@@ -71,6 +76,10 @@ func HandleActivationCreateWithStruct(ctx context.Context, client *td.Client, ar
 	fmt.Printf("Status: %s\n", activation.Status)
 }
 
+// activationListConcurrency bounds the per-audience requests of an activation
+// listing across all audiences
+const activationListConcurrency = 8
+
 // HandleActivationListWithForce lists all activations with optional force flag
 func HandleActivationListWithForce(ctx context.Context, client *td.Client, flags Flags, force bool) {
 	fmt.Println("⚠️  Warning: 'cdp activations ls' lists activations from ALL audiences.")
@@ -111,23 +120,32 @@ func HandleActivationListWithForce(ctx context.Context, client *td.Client, flags
 
 	fmt.Printf("Collecting activations from %d audiences...\n", len(audiences.Audiences))
 
-	// Collect activations from all audiences
-	var allActivations []td.CDPActivation
+	// Collect activations from all audiences, a few requests at a time
 	total := len(audiences.Audiences)
-	for i, audience := range audiences.Audiences {
-		if i%10 == 0 || i == total-1 {
-			fmt.Printf("Progress: %d/%d audiences processed...\n", i+1, total)
-		}
-
+	var processed int32
+	results, err := td.BatchMap(ctx, activationListConcurrency, audiences.Audiences, func(ctx context.Context, audience td.CDPAudience) ([]td.CDPActivation, error) {
+		defer func() {
+			if n := atomic.AddInt32(&processed, 1); n%10 == 0 || int(n) == total {
+				fmt.Printf("Progress: %d/%d audiences processed...\n", n, total)
+			}
+		}()
 		resp, err := client.CDP.ListActivations(ctx, audience.ID, nil)
 		if err != nil {
-			// Skip this audience if there's an error, but continue with others
-			if flags.Verbose {
-				fmt.Printf("Warning: Failed to get activations for audience %s: %v\n", audience.ID, err)
-			}
-			continue
+			return nil, fmt.Errorf("audience %s: %w", audience.ID, err)
+		}
+		return resp.Activations, nil
+	})
+	// Skip audiences that failed, but continue with the others
+	var batchErr *td.BatchError
+	if errors.As(err, &batchErr) && flags.Verbose {
+		for _, taskErr := range batchErr.Errors {
+			fmt.Printf("Warning: Failed to get activations for %v\n", taskErr.Err)
 		}
-		allActivations = append(allActivations, resp.Activations...)
+	}
+
+	var allActivations []td.CDPActivation
+	for _, activations := range results {
+		allActivations = append(allActivations, activations...)
 	}
 
 	fmt.Printf("Completed! Collected %d total activations from %d audiences.\n", len(allActivations), total)
@@ -343,37 +361,80 @@ func HandleActivationGetExecutions(ctx context.Context, client *td.Client, args
 	}
 
 	csvFormatter := func(data interface{}) string {
-		executions := data.([]*td.CDPActivationExecution)
+		executions := data.([]td.CDPActivationExecution)
 		var csvBuilder strings.Builder
 		for _, exec := range executions {
-			csvBuilder.WriteString(fmt.Sprintf("%s,%s,%s\n",
-				exec.ID, exec.Status, exec.CreatedAt.Format("2006-01-02 15:04:05")))
+			csvBuilder.WriteString(fmt.Sprintf("%s,%s,%s,%s,%d,%d\n",
+				exec.ID, exec.Status, exec.CreatedAt.Format("2006-01-02 15:04:05"),
+				formatExecutionFinishedAt(exec), exec.RecordsExported, exec.RecordsSkipped))
 		}
 		return csvBuilder.String()
 	}
 
 	tableFormatter := func(data interface{}) string {
-		executions := data.([]*td.CDPActivationExecution)
+		executions := data.([]td.CDPActivationExecution)
 		if len(executions) == 0 {
 			return "No executions found\n"
 		}
 		var tableBuilder strings.Builder
 		w := tabwriter.NewWriter(&tableBuilder, 0, 0, 2, ' ', 0)
-		fmt.Fprintln(w, "ID\tSTATUS\tCREATED")
+		fmt.Fprintln(w, "ID\tSTATUS\tCREATED\tFINISHED\tEXPORTED\tSKIPPED")
 		for _, exec := range executions {
-			fmt.Fprintf(w, "%s\t%s\t%s\n",
-				exec.ID, exec.Status, exec.CreatedAt.Format("2006-01-02 15:04:05"))
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\n",
+				exec.ID, exec.Status, exec.CreatedAt.Format("2006-01-02 15:04:05"),
+				formatExecutionFinishedAt(exec), exec.RecordsExported, exec.RecordsSkipped)
 		}
 		w.Flush()
 		tableBuilder.WriteString(fmt.Sprintf("\nTotal: %d executions\n", len(executions)))
 		return tableBuilder.String()
 	}
 
-	if err := formatAndWriteOutput(executions, flags.Format, flags.Output, "id,status,created_at", csvFormatter, tableFormatter); err != nil {
+	if err := formatAndWriteOutput(executions, flags.Format, flags.Output, "id,status,created_at,finished_at,records_exported,records_skipped", csvFormatter, tableFormatter); err != nil {
 		handleError(err, "Failed to write output", flags.Verbose)
 	}
 }
 
+// HandleActivationGetExecution shows one execution of an activation with its
+// record counts
+func HandleActivationGetExecution(ctx context.Context, client *td.Client, args []string, flags Flags) {
+	if len(args) < 4 {
+		handleUsageError("Usage: cdp activation execution <audience-id> <segment-id> <activation-id> <execution-id>", flags.Verbose)
+	}
+
+	exec, err := client.CDP.GetActivationExecution(ctx, args[0], args[1], args[2], args[3])
+	if err != nil {
+		handleError(err, "Failed to get activation execution", flags.Verbose)
+	}
+
+	switch flags.Format {
+	case "json":
+		printJSON(exec)
+	default:
+		fmt.Printf("ID: %s\n", exec.ID)
+		fmt.Printf("Status: %s\n", exec.Status)
+		fmt.Printf("Created: %s\n", exec.CreatedAt.Format("2006-01-02 15:04:05"))
+		if finished := formatExecutionFinishedAt(*exec); finished != "" {
+			fmt.Printf("Finished: %s\n", finished)
+			fmt.Printf("Duration: %s\n", exec.FinishedAt.Sub(exec.CreatedAt.Time).Round(time.Second))
+		}
+		fmt.Printf("Records Exported: %d\n", exec.RecordsExported)
+		fmt.Printf("Records Skipped: %d\n", exec.RecordsSkipped)
+		if exec.WorkflowAttemptID != "" {
+			fmt.Printf("Workflow Attempt: %s\n", exec.WorkflowAttemptID)
+		}
+		if exec.ErrorMessage != "" {
+			fmt.Printf("Error: %s\n", exec.ErrorMessage)
+		}
+	}
+}
+
+func formatExecutionFinishedAt(exec td.CDPActivationExecution) string {
+	if exec.FinishedAt == nil || exec.FinishedAt.IsZero() {
+		return ""
+	}
+	return exec.FinishedAt.Format("2006-01-02 15:04:05")
+}
+
 // HandleActivationListByAudience lists activations for a specific audience
 func HandleActivationListByAudience(ctx context.Context, client *td.Client, args []string, flags Flags) {
 	if len(args) < 1 {
@@ -675,3 +736,76 @@ func HandleGetWorkflowsForParentSegment(ctx context.Context, client *td.Client,
 		fmt.Printf("\nTotal: %d workflows\n", len(resp.Workflows))
 	}
 }
+
+// HandleActivationHistory lists the runs of every activation of an audience
+// since a point in time, with their workflow attempts and record counts
+func HandleActivationHistory(ctx context.Context, client *td.Client, audienceID string, since time.Time, flags Flags) {
+	runs, err := client.CDP.ActivationHistory(ctx, audienceID, &td.CDPActivationHistoryOptions{
+		Since:       since,
+		Concurrency: activationListConcurrency,
+	})
+	// Report activations whose runs could not be read, but keep the others
+	var batchErr *td.BatchError
+	if errors.As(err, &batchErr) {
+		for _, taskErr := range batchErr.Errors {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to get runs for %v\n", taskErr.Err)
+		}
+	} else if err != nil {
+		handleError(err, "Failed to get activation history", flags.Verbose)
+	}
+
+	csvFormatter := func(data interface{}) string {
+		var csvBuilder strings.Builder
+		w := csv.NewWriter(&csvBuilder)
+		for _, run := range data.([]td.CDPActivationRun) {
+			w.Write([]string{
+				run.ActivationID, run.ActivationName, run.SegmentID, run.ExecutionID, run.Status,
+				run.CreatedAt.Format(time.RFC3339), formatRunFinishedAt(run), formatRunDurationSeconds(run),
+				strconv.FormatInt(run.RecordsExported, 10), strconv.FormatInt(run.RecordsSkipped, 10), run.WorkflowAttemptID, run.WorkflowAttemptURL, run.ErrorMessage,
+			})
+		}
+		w.Flush()
+		return csvBuilder.String()
+	}
+
+	tableFormatter := func(data interface{}) string {
+		runs := data.([]td.CDPActivationRun)
+		if len(runs) == 0 {
+			return "No activation runs found\n"
+		}
+		var tableBuilder strings.Builder
+		w := tabwriter.NewWriter(&tableBuilder, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CREATED\tACTIVATION\tSTATUS\tDURATION\tEXPORTED\tSKIPPED\tATTEMPT")
+		for _, run := range runs {
+			duration := "-"
+			if run.FinishedAt != nil {
+				duration = run.Duration().Round(time.Second).String()
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%s\n",
+				run.CreatedAt.Format("2006-01-02 15:04:05"), run.ActivationName, run.Status,
+				duration, run.RecordsExported, run.RecordsSkipped, run.WorkflowAttemptID)
+		}
+		w.Flush()
+		tableBuilder.WriteString(fmt.Sprintf("\nTotal: %d runs\n", len(runs)))
+		return tableBuilder.String()
+	}
+
+	header := "activation_id,activation_name,segment_id,execution_id,status,created_at,finished_at,duration_seconds,records_exported,records_skipped,workflow_attempt_id,workflow_attempt_url,error_message"
+	if err := formatAndWriteOutput(runs, flags.Format, flags.Output, header, csvFormatter, tableFormatter); err != nil {
+		handleError(err, "Failed to write output", flags.Verbose)
+	}
+}
+
+func formatRunFinishedAt(run td.CDPActivationRun) string {
+	if run.FinishedAt == nil {
+		return ""
+	}
+	return run.FinishedAt.Format(time.RFC3339)
+}
+
+func formatRunDurationSeconds(run td.CDPActivationRun) string {
+	if run.FinishedAt == nil {
+		return ""
+	}
+	return strconv.FormatInt(int64(run.Duration().Seconds()), 10)
+}
@@ -2,8 +2,13 @@ package cdp
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 
@@ -258,6 +263,135 @@ func HandleAudienceBehaviors(ctx context.Context, client *td.Client, args []stri
 	}
 }
 
+// HandleAudienceAttributeUsage reports which attributes and behaviors are
+// referenced by the audience's segment rules
+func HandleAudienceAttributeUsage(ctx context.Context, client *td.Client, args []string, unusedOnly bool, flags Flags) {
+	if len(args) < 1 {
+		handleUsageError("Audience ID required", flags.Verbose)
+	}
+
+	report, err := client.CDP.AnalyzeAttributeUsage(ctx, args[0])
+	if err != nil {
+		handleError(err, "Failed to analyze attribute usage", flags.Verbose)
+	}
+
+	usage := append(append([]td.CDPAttributeUsage{}, report.Attributes...), report.Behaviors...)
+	if unusedOnly {
+		usage = report.Unused()
+	}
+
+	switch flags.Format {
+	case "json":
+		if unusedOnly {
+			printJSON(usage)
+		} else {
+			printJSON(report)
+		}
+	case "csv":
+		cw := csv.NewWriter(os.Stdout)
+		cw.Write([]string{"kind", "name", "segment_count", "segments"})
+		for _, u := range usage {
+			cw.Write([]string{u.Kind, u.Name, strconv.Itoa(len(u.Segments)), strings.Join(u.Segments, ";")})
+		}
+		cw.Flush()
+	default:
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KIND\tNAME\tSEGMENTS")
+		for _, u := range usage {
+			segments := strings.Join(u.Segments, ", ")
+			if !u.Used() {
+				segments = "(unused)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", u.Kind, u.Name, segments)
+		}
+		w.Flush()
+
+		fmt.Printf("\nScanned %d segments: %d unused of %d attributes and behaviors\n",
+			report.SegmentsScanned, len(report.Unused()), len(report.Attributes)+len(report.Behaviors))
+		for _, u := range report.Unknown {
+			fmt.Printf("Warning: %s %s is referenced by %s but not defined in the audience\n",
+				u.Kind, u.Name, strings.Join(u.Segments, ", "))
+		}
+	}
+}
+
+// HandleAudienceExport writes an audience definition as JSON to --output or
+// stdout, for recreating it with "cdp audiences import"
+func HandleAudienceExport(ctx context.Context, client *td.Client, args []string, flags Flags) {
+	if len(args) < 1 {
+		handleUsageError("Audience ID required", flags.Verbose)
+	}
+
+	export, err := client.CDP.ExportAudience(ctx, args[0])
+	if err != nil {
+		handleError(err, "Failed to export audience", flags.Verbose)
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		handleError(err, "Failed to encode audience export", flags.Verbose)
+	}
+
+	if flags.Output == "" {
+		fmt.Println(string(data))
+		return
+	}
+	if err := os.WriteFile(flags.Output, append(data, '\n'), 0644); err != nil {
+		handleError(err, "Failed to write audience export", flags.Verbose)
+	}
+	fmt.Printf("Exported audience %s (%d segments, %d activations) to %s\n",
+		export.Audience.Name, len(export.Segments), len(export.Activations), flags.Output)
+}
+
+// HandleAudienceImport recreates an exported audience. connections maps
+// source connection IDs to target ones as "old=new".
+func HandleAudienceImport(ctx context.Context, client *td.Client, args []string, name string, connections []string, skipActivations bool, flags Flags) {
+	if len(args) < 1 {
+		handleUsageError("Export file required", flags.Verbose)
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		handleError(err, "Failed to read audience export", flags.Verbose)
+	}
+	var export td.CDPAudienceExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		handleError(err, "Failed to parse audience export", flags.Verbose)
+	}
+
+	opts := &td.CDPAudienceImportOptions{
+		Name:            name,
+		ConnectionIDs:   make(map[string]string),
+		SkipActivations: skipActivations,
+	}
+	for _, mapping := range connections {
+		parts := strings.SplitN(mapping, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			handleUsageError(fmt.Sprintf("Invalid connection mapping: %s (expected source-id=target-id)", mapping), flags.Verbose)
+		}
+		opts.ConnectionIDs[parts[0]] = parts[1]
+	}
+
+	result, err := client.CDP.ImportAudience(ctx, &export, opts)
+	if err != nil {
+		if result != nil && result.Audience != nil {
+			fmt.Fprintf(os.Stderr, "Audience %s was created but the import is incomplete\n", result.Audience.ID)
+		}
+		handleError(err, "Failed to import audience", flags.Verbose)
+	}
+
+	if flags.Format == "json" {
+		printJSON(result)
+		return
+	}
+
+	fmt.Printf("Audience %s created (ID: %s)\n", result.Audience.Name, result.Audience.ID)
+	fmt.Printf("Folders: %d, segments: %d, activations: %d\n", len(result.FolderIDs), len(result.SegmentIDs), result.Activations)
+	for _, skipped := range result.SkippedActivations {
+		fmt.Printf("Skipped activation %s\n", skipped)
+	}
+}
+
 // HandleAudienceRun runs an audience execution
 func HandleAudienceRun(ctx context.Context, client *td.Client, args []string, flags Flags) {
 	if len(args) < 1 {
@@ -357,30 +491,13 @@ func HandleAudienceSampleValues(ctx context.Context, client *td.Client, args []s
 		handleUsageError("Audience ID and attribute name required", flags.Verbose)
 	}
 
-	values, err := client.CDP.GetAudienceSampleValues(ctx, args[0], args[1])
+	page, err := client.CDP.ListAudienceSampleValues(ctx, args[0], args[1], sampleValueListOptions(flags))
 	if err != nil {
 		handleError(err, "Failed to get sample values", flags.Verbose)
 	}
 
-	switch flags.Format {
-	case "json":
-		printJSON(values)
-	case "csv":
-		fmt.Println("value")
-		for _, value := range values {
-			fmt.Printf("%v\n", value)
-		}
-	default:
-		if len(values) == 0 {
-			fmt.Println("No sample values found")
-			return
-		}
-
-		fmt.Printf("Sample values for attribute '%s':\n", args[1])
-		for i, value := range values {
-			fmt.Printf("  %d. %v\n", i+1, value)
-		}
-		fmt.Printf("\nTotal: %d values\n", len(values))
+	if err := writeSampleValues(page, fmt.Sprintf("Sample values for attribute '%s'", args[1]), flags); err != nil {
+		handleError(err, "Failed to write sample values", flags.Verbose)
 	}
 }
 
@@ -390,32 +507,72 @@ func HandleAudienceBehaviorSamples(ctx context.Context, client *td.Client, args
 		handleUsageError("Usage: cdp audience behavior-samples <audience-id> <behavior-id> <column>", flags.Verbose)
 	}
 
-	samples, err := client.CDP.GetAudienceBehaviorSampleValues(ctx, args[0], args[1], args[2])
+	page, err := client.CDP.ListAudienceBehaviorSampleValues(ctx, args[0], args[1], args[2], sampleValueListOptions(flags))
 	if err != nil {
 		handleError(err, "Failed to get audience behavior sample values", flags.Verbose)
 	}
 
-	switch flags.Format {
+	if err := writeSampleValues(page, fmt.Sprintf("Sample values for behavior %s, column %s", args[1], args[2]), flags); err != nil {
+		handleError(err, "Failed to write sample values", flags.Verbose)
+	}
+}
+
+func sampleValueListOptions(flags Flags) *td.CDPSampleValueListOptions {
+	if flags.Limit == 0 && flags.Offset == 0 {
+		return nil
+	}
+	return &td.CDPSampleValueListOptions{Limit: flags.Limit, Offset: flags.Offset}
+}
+
+// writeSampleValues prints a page of sample values, or exports it to
+// flags.Output. A .csv or .json output file selects that format.
+func writeSampleValues(page *td.CDPSampleValuePage, title string, flags Flags) error {
+	format := flags.Format
+	switch strings.ToLower(filepath.Ext(flags.Output)) {
+	case ".csv":
+		format = "csv"
+	case ".json":
+		format = "json"
+	}
+
+	w := io.Writer(os.Stdout)
+	if flags.Output != "" {
+		file, err := os.Create(flags.Output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	switch format {
 	case "json":
-		printJSON(samples)
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(page); err != nil {
+			return err
+		}
 	case "csv":
-		fmt.Println("value,frequency")
-		for _, sample := range samples {
-			if len(sample) >= 2 {
-				fmt.Printf("%v,%v\n", sample[0], sample[1])
-			}
+		if err := td.WriteSampleValuesCSV(w, page.Values); err != nil {
+			return err
 		}
 	default:
-		if len(samples) == 0 {
-			fmt.Println("No sample values found")
-			return
+		if len(page.Values) == 0 {
+			fmt.Fprintln(w, "No sample values found")
+			break
 		}
-		fmt.Printf("Sample Values for Behavior %s, Column %s:\n", args[1], args[2])
-		for _, sample := range samples {
-			if len(sample) >= 2 {
-				fmt.Printf("  Value: %v, Frequency: %v\n", sample[0], sample[1])
-			}
+		fmt.Fprintf(w, "%s:\n", title)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VALUE\tFREQUENCY")
+		for _, value := range page.Values {
+			fmt.Fprintf(tw, "%s\t%d\n", value.String(), value.Frequency)
 		}
-		fmt.Printf("\nTotal: %d samples\n", len(samples))
+		tw.Flush()
+		fmt.Fprintf(w, "\nShowing %d-%d of %d values\n", page.Offset+1, page.Offset+len(page.Values), page.Total)
+	}
+
+	if flags.Output != "" {
+		fmt.Printf("Wrote %d sample values to %s\n", len(page.Values), flags.Output)
 	}
+	return nil
 }
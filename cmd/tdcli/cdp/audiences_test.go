@@ -3,6 +3,8 @@ package cdp
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -748,3 +750,23 @@ func TestAudienceUpdateRequestParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteSampleValuesCSVFile(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "samples.csv")
+	page := &td.CDPSampleValuePage{
+		Values: []td.CDPAudienceSampleValue{{Value: "Tokyo", Frequency: 12}, {Value: "Osaka", Frequency: 3}},
+		Total:  2,
+	}
+
+	if err := writeSampleValues(page, "Sample values", Flags{Format: "table", Output: output}); err != nil {
+		t.Fatalf("writeSampleValues returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "value,frequency\nTokyo,12\nOsaka,3\n"; string(data) != want {
+		t.Errorf("CSV = %q, want %q", data, want)
+	}
+}
@@ -20,6 +20,7 @@ type Flags struct {
 	Status             string
 	Priority           int
 	Limit              int
+	Offset             int
 	WithDetails        bool
 	Query              string
 	Folder             string
@@ -150,6 +150,44 @@ func HandleSegmentDelete(ctx context.Context, client *td.Client, args []string,
 	fmt.Printf("Segment %s deleted successfully\n", args[0])
 }
 
+// HandleSegmentRenameAttribute rewrites segment rules that reference a renamed
+// attribute or behavior column
+func HandleSegmentRenameAttribute(ctx context.Context, client *td.Client, args []string, behavior string, dryRun bool, flags Flags) {
+	if len(args) < 3 {
+		handleUsageError("Usage: cdp segment rename-attribute <audience-id> <from> <to>", flags.Verbose)
+	}
+
+	rename := td.CDPAttributeRename{From: args[1], To: args[2], Behavior: behavior}
+	changes, err := client.CDP.RenameSegmentAttribute(ctx, args[0], rename, dryRun)
+	if err != nil {
+		handleError(err, "Failed to rename attribute", flags.Verbose)
+	}
+
+	if flags.Format == "json" {
+		printJSON(changes)
+		return
+	}
+
+	if len(changes) == 0 {
+		fmt.Printf("No segments reference %s\n", rename.From)
+		return
+	}
+
+	for _, change := range changes {
+		fmt.Printf("Segment %s (%s): %d condition(s)\n", change.SegmentName, change.SegmentID, change.Replacements)
+		if dryRun {
+			fmt.Print(change.Diff())
+			fmt.Println()
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("Dry run: %d segment(s) would be updated\n", len(changes))
+	} else {
+		fmt.Printf("Updated %d segment(s)\n", len(changes))
+	}
+}
+
 // HandleSegmentFolders lists segments in a folder
 func HandleSegmentFolders(ctx context.Context, client *td.Client, args []string, flags Flags) {
 	if len(args) < 2 {
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// CDPActivationsRetryFailedCmd re-runs activations whose latest run failed
+type CDPActivationsRetryFailedCmd struct {
+	AudienceID  string `kong:"arg,help='Audience ID'"`
+	Since       string `kong:"help='Only consider runs after this lookback (24h, 2d) or date (2006-01-02)',default='24h'"`
+	Preview     bool   `kong:"help='Only list the failed activations'"`
+	Force       bool   `kong:"flag,help='Skip confirmation prompt'"`
+	Concurrency int    `kong:"help='Activations started at once',default='4'"`
+}
+
+func (c *CDPActivationsRetryFailedCmd) Run(ctx *CLIContext) error {
+	since, err := parseTimeBound(c.Since, time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+
+	failed, err := ctx.Client.CDP.FailedActivationRuns(ctx.Context, c.AudienceID, &td.CDPActivationHistoryOptions{Since: since})
+	var batchErr *td.BatchError
+	if errors.As(err, &batchErr) {
+		// Activations whose runs cannot be read are not retried
+		for _, taskErr := range batchErr.Errors {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to get runs for %v\n", taskErr.Err)
+		}
+	} else if err != nil {
+		return err
+	}
+	if len(failed) == 0 {
+		fmt.Println("No failed activations")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACTIVATION\tNAME\tFAILED AT\tERROR")
+	for _, run := range failed {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", run.ActivationID, run.ActivationName,
+			run.CreatedAt.Format("2006-01-02 15:04:05"), run.ErrorMessage)
+	}
+	w.Flush()
+
+	if c.Preview {
+		return nil
+	}
+	if !c.Force && !promptConfirmation(fmt.Sprintf("Re-run %d activations?", len(failed))) {
+		fmt.Println("Cancelled")
+		return nil
+	}
+
+	retries, err := ctx.Client.CDP.RetryActivations(ctx.Context, failed, c.Concurrency)
+	if err != nil && !errors.As(err, &batchErr) {
+		return err
+	}
+
+	started := 0
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACTIVATION\tNAME\tRESULT")
+	for _, retry := range retries {
+		result := "error: " + fmt.Sprint(retry.Err)
+		if retry.Err == nil {
+			started++
+			result = "started"
+			if retry.Execution != nil && retry.Execution.ID != "" {
+				result = "started execution " + retry.Execution.ID
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", retry.Failed.ActivationID, retry.Failed.ActivationName, result)
+	}
+	w.Flush()
+
+	fmt.Printf("\nRe-ran %d of %d failed activations\n", started, len(retries))
+	if started < len(retries) {
+		return fmt.Errorf("%d activations could not be re-run", len(retries)-started)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+type cdpBehaviorQueryOptions struct {
+	Since   string
+	Until   string
+	Columns []string
+	Timeout time.Duration
+	DryRun  bool
+}
+
+// handleCDPBehaviorQuery queries an audience behavior's matrix table, waits
+// for the job and prints its results in the global output format
+func handleCDPBehaviorQuery(ctx context.Context, client *td.Client, audienceID, behavior string, opts cdpBehaviorQueryOptions, flags Flags) error {
+	now := time.Now()
+	since, err := parseTimeBound(opts.Since, now)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	until, err := parseTimeBound(opts.Until, now)
+	if err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
+
+	queryOpts := td.BehaviorQueryOptions{Since: since, Until: until, Columns: opts.Columns, Limit: flags.Limit}
+
+	if opts.DryRun {
+		b, err := client.CDP.FindAudienceBehavior(ctx, audienceID, behavior)
+		if err != nil {
+			return err
+		}
+		query, err := td.BuildBehaviorQuery(b, queryOpts)
+		if err != nil {
+			return err
+		}
+		fmt.Println(query)
+		return nil
+	}
+
+	resp, err := client.CDP.QueryBehavior(ctx, audienceID, behavior, queryOpts, &td.IssueQueryOptions{Priority: flags.Priority})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Query job %s submitted on %s\n", resp.JobID, resp.Database)
+
+	job, err := waitForJob(ctx, client, resp.JobID, opts.Timeout)
+	if err != nil {
+		return err
+	}
+	if job.Status != "success" {
+		if job.Debug != nil && job.Debug.Stderr != "" {
+			return fmt.Errorf("job %s %s: %s", job.JobID, job.Status, job.Debug.Stderr)
+		}
+		return fmt.Errorf("job %s %s", job.JobID, job.Status)
+	}
+
+	handleQueryResult(ctx, client, []string{resp.JobID}, false, flags)
+	return nil
+}
+
+// parseTimeBound accepts a lookback such as 7d, 2w or 36h, or an absolute
+// date (2006-01-02) or RFC 3339 time. An empty value returns the zero time.
+func parseTimeBound(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+
+	unit := value[len(value)-1:]
+	if unit == "d" || unit == "w" {
+		n, err := strconv.Atoi(strings.TrimSuffix(value, unit))
+		if err != nil || n < 0 {
+			return time.Time{}, fmt.Errorf("%q is not a duration or date", value)
+		}
+		days := n
+		if unit == "w" {
+			days *= 7
+		}
+		return now.AddDate(0, 0, -days), nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("%q is not a duration or date", value)
+	}
+	return now.Add(-d), nil
+}
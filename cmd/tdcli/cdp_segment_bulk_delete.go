@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// CDPSegmentsRmBulkCmd deletes the segments of an audience whose names start
+// with a prefix, such as throwaway segments left in QA environments
+type CDPSegmentsRmBulkCmd struct {
+	AudienceID  string `kong:"arg,help='Audience ID'"`
+	Prefix      string `kong:"required,help='Delete segments whose names start with this (case sensitive)'"`
+	DryRun      bool   `kong:"help='Only list the segments that would be deleted'"`
+	Force       bool   `kong:"flag,help='Skip confirmation prompt'"`
+	Concurrency int    `kong:"help='Segments deleted at once',default='4'"`
+}
+
+func (c *CDPSegmentsRmBulkCmd) Run(ctx *CLIContext) error {
+	segments, err := ctx.Client.CDP.ListSegmentsByPrefix(ctx.Context, c.AudienceID, c.Prefix)
+	if err != nil {
+		return fmt.Errorf("failed to list segments: %w", err)
+	}
+	if len(segments) == 0 {
+		fmt.Printf("No segments start with %q\n", c.Prefix)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tUPDATED")
+	for _, segment := range segments {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", segment.ID, segment.Name, segment.UpdatedAt.Format("2006-01-02 15:04:05"))
+	}
+	w.Flush()
+
+	if c.DryRun {
+		fmt.Printf("\nDry run: %d segment(s) would be deleted\n", len(segments))
+		return nil
+	}
+	if !c.Force && !promptConfirmation(fmt.Sprintf("Delete %d segments of audience %s?", len(segments), c.AudienceID)) {
+		fmt.Println("Cancelled")
+		return nil
+	}
+
+	deletions, err := ctx.Client.CDP.DeleteSegments(ctx.Context, c.AudienceID, segments, c.Concurrency)
+	var batchErr *td.BatchError
+	if err != nil && !errors.As(err, &batchErr) {
+		return err
+	}
+
+	deleted := 0
+	for _, deletion := range deletions {
+		if deletion.Err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", deletion.Err)
+			continue
+		}
+		deleted++
+	}
+	fmt.Printf("\nDeleted %d of %d segments\n", deleted, len(deletions))
+	if batchErr != nil {
+		return fmt.Errorf("%d segment(s) could not be deleted", len(batchErr.Errors))
+	}
+	return nil
+}
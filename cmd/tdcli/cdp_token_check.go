@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// CDPTokensCheckCmd looks up a profile with a profiles API token to verify
+// the token and show what it gives access to
+type CDPTokensCheckCmd struct {
+	Token string `kong:"arg,help='Profiles API token'"`
+	Key   string `kong:"required,help='Key column and value of a known profile (e.g. td_client_id=abc123)'"`
+}
+
+// tokenCheckResult is the token scope with the names of its segments, which
+// need the API key to look up
+type tokenCheckResult struct {
+	*td.CDPTokenScope
+	SegmentNames map[string]string `json:"segment_names,omitempty"`
+}
+
+func (c *CDPTokensCheckCmd) Run(ctx *CLIContext) error {
+	keyColumn, keyValue, ok := strings.Cut(c.Key, "=")
+	if !ok || keyColumn == "" {
+		return fmt.Errorf("invalid --key %q: want column=value", c.Key)
+	}
+
+	scope, err := ctx.Client.CDP.CheckTokenScope(ctx.Context, c.Token, keyColumn, keyValue)
+	if err != nil {
+		if td.IsUnauthorized(err) || td.IsForbidden(err) {
+			return fmt.Errorf("the Profiles API rejected the token: %w", err)
+		}
+		return fmt.Errorf("failed to look up profile: %w", err)
+	}
+
+	result := tokenCheckResult{CDPTokenScope: scope, SegmentNames: map[string]string{}}
+	if len(scope.AudienceIDs) == 1 {
+		for _, id := range scope.SegmentIDs {
+			segment, err := ctx.Client.CDP.GetSegment(ctx.Context, scope.AudienceIDs[0], id)
+			if err != nil {
+				// Names are a convenience; the API key may not see the audience
+				if ctx.GlobalFlags.Verbose {
+					fmt.Fprintf(os.Stderr, "Warning: Failed to get segment %s: %v\n", id, err)
+				}
+				continue
+			}
+			result.SegmentNames[id] = segment.Name
+		}
+	}
+
+	csvFormatter := func(data interface{}) string {
+		r := data.(tokenCheckResult)
+		var sb strings.Builder
+		for _, name := range r.Attributes {
+			sb.WriteString(fmt.Sprintf("attribute,%s,\n", name))
+		}
+		for _, id := range r.SegmentIDs {
+			sb.WriteString(fmt.Sprintf("segment,%q,%s\n", r.SegmentNames[id], id))
+		}
+		return sb.String()
+	}
+
+	tableFormatter := func(data interface{}) string {
+		r := data.(tokenCheckResult)
+		var sb strings.Builder
+		sb.WriteString("Token accepted by the Profiles API\n")
+		if r.Profiles == 0 {
+			sb.WriteString(fmt.Sprintf("No profile has %s=%s; look up a known profile to see what the token returns\n", r.KeyColumn, r.KeyValue))
+			return sb.String()
+		}
+		sb.WriteString(fmt.Sprintf("Key:        %s=%s\n", r.KeyColumn, r.KeyValue))
+		sb.WriteString(fmt.Sprintf("Profiles:   %d\n", r.Profiles))
+		sb.WriteString(fmt.Sprintf("Audiences:  %s\n", strings.Join(r.AudienceIDs, ", ")))
+		sb.WriteString(fmt.Sprintf("Attributes: %s\n", orNone(strings.Join(r.Attributes, ", "))))
+		segments := make([]string, len(r.SegmentIDs))
+		for i, id := range r.SegmentIDs {
+			segments[i] = id
+			if name := r.SegmentNames[id]; name != "" {
+				segments[i] = fmt.Sprintf("%s (%s)", id, name)
+			}
+		}
+		sb.WriteString(fmt.Sprintf("Segments:   %s\n", orNone(strings.Join(segments, ", "))))
+		sb.WriteString("\nOnly segments the profile belongs to are listed\n")
+		return sb.String()
+	}
+
+	return formatAndWriteOutput(result, ctx.GlobalFlags.Format, ctx.GlobalFlags.Output, "kind,name,id", csvFormatter, tableFormatter)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
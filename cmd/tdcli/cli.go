@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"time"
 
 	td "github.com/mickeey2525/treasuredata-go-sdk"
 	"github.com/mickeey2525/treasuredata-go-sdk/cmd/tdcli/workflow"
@@ -11,11 +12,17 @@ import (
 // Global CLI structure
 type CLI struct {
 	// Global flags
-	APIKey  string `kong:"help='Treasure Data API key (format: account_id/api_key)',env='TD_API_KEY'"`
-	Region  string `kong:"help='API region (us, eu, tokyo, ap02)',default='us'"`
-	Format  string `kong:"help='Output format (json, table, csv)',default='table',enum='json,table,csv'"`
-	Output  string `kong:"help='Output to file'"`
-	Verbose bool   `kong:"short='v',help='Verbose output'"`
+	APIKey   string        `kong:"help='Treasure Data API key (format: account_id/api_key)',env='TD_API_KEY'"`
+	Region   string        `kong:"help='API region (us, eu, tokyo, ap02)',default='us'"`
+	Format   string        `kong:"help='Output format (json, table, csv)',default='table',enum='json,table,csv'"`
+	Output   string        `kong:"help='Output to file'"`
+	Verbose  bool          `kong:"short='v',help='Verbose output'"`
+	Plan     bool          `kong:"help='Print mutating API requests (POST/PUT/PATCH/DELETE) instead of sending them; reads still run'"`
+	DumpHTTP bool          `kong:"name='dump-http',help='Write every HTTP request and response to stderr, with secrets redacted'"`
+	CacheTTL time.Duration `kong:"name='cache-ttl',help='Cache database, table, policy and audience reads for this long (e.g. 5m); 0 disables',env='TD_CACHE_TTL'"`
+	Examples bool          `kong:"help='Print examples for the command instead of running it'"`
+	Profile  string        `kong:"help='Use the API key, region and TLS settings of a named profile',env='TD_PROFILE'"`
+	Allow    []string      `kong:"help='Only send API requests matching these presets (read-only, query, results, import, workflow-run) or rules such as GET td:v3/job/show/*',env='TD_ALLOW'"`
 
 	// SSL/TLS Options
 	InsecureSkipVerify bool   `kong:"help='Skip TLS certificate verification',env='TD_INSECURE_SKIP_VERIFY'"`
@@ -30,13 +37,27 @@ type CLI struct {
 	Tables    TablesCmd    `kong:"cmd,aliases='table',help='Table management'"`
 	Queries   QueriesCmd   `kong:"cmd,aliases='query,q',help='Query execution'"`
 	Jobs      JobsCmd      `kong:"cmd,aliases='job',help='Job management'"`
+	ML        MLCmd        `kong:"cmd,name='ml',help='Hivemall machine learning jobs'"`
 	Users     UsersCmd     `kong:"cmd,aliases='user',help='User management'"`
+	Account   AccountCmd   `kong:"cmd,help='Account details and usage'"`
+	Schedules SchedulesCmd `kong:"cmd,aliases='schedule,sched',help='Scheduled query management'"`
 	Perms     PermsCmd     `kong:"cmd,aliases='permissions,acl',help='Access control and permissions'"`
 	Results   ResultsCmd   `kong:"cmd,aliases='result',help='Query results management'"`
+	Pools     PoolsCmd     `kong:"cmd,aliases='pool',help='Query resource pools'"`
 	Import    ImportCmd    `kong:"cmd,aliases='bulk-import',help='Bulk data import'"`
 	CDP       CDPCmd       `kong:"cmd,help='Customer Data Platform (CDP) management'"`
+	Compare   CompareCmd   `kong:"cmd,help='Compare two environments (profiles)'"`
 	Workflow  WorkflowCmd  `kong:"cmd,aliases='wf',help='Workflow management'"`
 	Trino     TrinoCmd     `kong:"cmd,help='Trino SQL client'"`
+	Validate  ValidateCmd  `kong:"cmd,help='Check resource names before creating them'"`
+	Doctor    DoctorCmd    `kong:"cmd,help='Check connectivity and authentication for each service'"`
+	Smoke     SmokeCmd     `kong:"cmd,help='Run an end-to-end scenario against a test database'"`
+	Init      InitCmd      `kong:"cmd,help='Set up tdcli for your account'"`
+	Audit     AuditCmd     `kong:"cmd,help='Verify and ship the audit trail of mutating commands'"`
+
+	Completion     CompletionCmd     `kong:"cmd,help='Print a shell completion script (bash, zsh, fish)'"`
+	SelfUpdate     SelfUpdateCmd     `kong:"cmd,name='self-update',help='Update tdcli to the latest release'"`
+	TelemetryFlush TelemetryFlushCmd `kong:"cmd,hidden,name='telemetry-flush',help='Send spooled usage telemetry'"`
 }
 
 // Version command
@@ -49,13 +70,31 @@ func (v *VersionCmd) Run(ctx *CLIContext) error {
 	return nil
 }
 
+// SelfUpdateCmd replaces the running binary with the latest GitHub release
+type SelfUpdateCmd struct {
+	Channel string `kong:"default='stable',enum='stable,beta',help='Release channel: stable or beta (includes prereleases)'"`
+	Check   bool   `kong:"help='Only report whether an update is available'"`
+	Force   bool   `kong:"help='Reinstall even if up to date or running a development build'"`
+	Proxy   string `kong:"help='Proxy URL for downloads (default: HTTPS_PROXY from the environment)'"`
+}
+
+func (s *SelfUpdateCmd) Run(ctx *CLIContext) error {
+	return handleSelfUpdate(ctx.Context, selfUpdateOptions{
+		Channel: s.Channel,
+		Check:   s.Check,
+		Force:   s.Force,
+		Proxy:   s.Proxy,
+	})
+}
+
 // Database commands
 type DatabasesCmd struct {
-	List   DatabasesListCmd   `kong:"cmd,aliases='ls',help='List databases'"`
-	Get    DatabasesGetCmd    `kong:"cmd,aliases='show',help='Get database details'"`
-	Create DatabasesCreateCmd `kong:"cmd,help='Create a database'"`
-	Delete DatabasesDeleteCmd `kong:"cmd,aliases='rm',help='Delete a database'"`
-	Update DatabasesUpdateCmd `kong:"cmd,help='Update database properties'"`
+	List      DatabasesListCmd      `kong:"cmd,aliases='ls',help='List databases'"`
+	Get       DatabasesGetCmd       `kong:"cmd,aliases='show',help='Get database details'"`
+	Create    DatabasesCreateCmd    `kong:"cmd,help='Create a database'"`
+	Delete    DatabasesDeleteCmd    `kong:"cmd,aliases='rm',help='Delete a database'"`
+	Update    DatabasesUpdateCmd    `kong:"cmd,help='Update database properties'"`
+	Provision DatabasesProvisionCmd `kong:"cmd,help='Create a database and grant policies access to it, rolling back on failure'"`
 }
 
 type DatabasesListCmd struct{}
@@ -101,6 +140,16 @@ func (d *DatabasesUpdateCmd) Run(ctx *CLIContext) error {
 	return nil
 }
 
+type DatabasesProvisionCmd struct {
+	Name        string   `kong:"arg,help='Database name'"`
+	GrantPolicy []string `kong:"required,help='Policy (ID or name) to grant access to the database; repeatable'"`
+	Operation   []string `kong:"default='query',help='Database operations to grant: query, edit, import, manage, owner_manage, download'"`
+}
+
+func (d *DatabasesProvisionCmd) Run(ctx *CLIContext) error {
+	return handleDatabaseProvision(ctx.Context, ctx.Client, d.Name, d.GrantPolicy, d.Operation)
+}
+
 // Table commands
 type TablesCmd struct {
 	List   TablesListCmd   `kong:"cmd,aliases='ls',help='List tables in database'"`
@@ -109,6 +158,7 @@ type TablesCmd struct {
 	Delete TablesDeleteCmd `kong:"cmd,aliases='rm',help='Delete a table'"`
 	Swap   TablesSwapCmd   `kong:"cmd,help='Swap two tables'"`
 	Rename TablesRenameCmd `kong:"cmd,aliases='mv',help='Rename a table'"`
+	Apply  TablesApplyCmd  `kong:"cmd,help='Create and update tables from a schema manifest'"`
 }
 
 type TablesListCmd struct {
@@ -174,28 +224,63 @@ func (t *TablesRenameCmd) Run(ctx *CLIContext) error {
 
 // Query commands
 type QueriesCmd struct {
-	Submit QuerySubmitCmd `kong:"cmd,aliases='run',help='Submit a query for execution'"`
-	Status QueryStatusCmd `kong:"cmd,help='Check query execution status'"`
-	Result QueryResultCmd `kong:"cmd,aliases='results',help='Get query results'"`
-	List   QueryListCmd   `kong:"cmd,aliases='ls',help='List recent queries'"`
-	Cancel QueryCancelCmd `kong:"cmd,help='Cancel a running query'"`
+	Submit   QuerySubmitCmd   `kong:"cmd,aliases='run',help='Submit a query for execution'"`
+	Status   QueryStatusCmd   `kong:"cmd,help='Check query execution status'"`
+	Result   QueryResultCmd   `kong:"cmd,aliases='results',help='Get query results'"`
+	List     QueryListCmd     `kong:"cmd,aliases='ls',help='List recent queries'"`
+	Cancel   QueryCancelCmd   `kong:"cmd,help='Cancel a running query'"`
+	Estimate QueryEstimateCmd `kong:"cmd,help='Estimate the data a Trino query would scan, without running it'"`
 }
 
 type QuerySubmitCmd struct {
 	Query    string `kong:"arg,help='SQL query to execute'"`
 	Database string `kong:"required,help='Database to run query against'"`
 	Engine   string `kong:"help='Query engine: trino (default) or hive',default='trino',enum='trino,hive,presto'"`
-	Priority int    `kong:"help='Query priority (0-2)',default=0"`
+	Priority int    `kong:"help='Query priority (-2 to 2)',default=0"`
+	Preset   string `kong:"help='Priority preset: interactive, batch, backfill, or one from priority_presets in config'"`
 	Wait     bool   `kong:"help='Wait for query completion'"`
-	Timeout  int    `kong:"help='Wait timeout in seconds',default=300"`
+	Timeout  int    `kong:"help='Wait timeout in seconds',default=300,env='TD_TIMEOUT'"`
+
+	PoolName      string `kong:"name='pool-name',help='Resource pool to run the query in (overrides the preset pool)'"`
+	EngineVersion string `kong:"name='engine-version',help='Engine version, e.g. stable or experimental for Hive'"`
+	DomainKey     string `kong:"name='domain-key',help='Idempotency key: if a job was already issued with it, that job is used instead of a new one'"`
+
+	ResultURL        string `kong:"name='result-url',xor='result',help='Write results to a URL, e.g. td://@/db/table?mode=append or s3://key:secret@/bucket/path'"`
+	ResultConnection string `kong:"xor='result',help='Write results through a saved result connection'"`
+
+	Var map[string]string `kong:"placeholder='KEY=VALUE',help='Render the query as a template with this variable, e.g. {{tdString .country}}'"`
 }
 
 func (q *QuerySubmitCmd) Run(ctx *CLIContext) error {
+	query, err := renderQueryVars(q.Query, q.Var, td.QueryType(q.Engine))
+	if err != nil {
+		return err
+	}
 	// Set database in global flags for compatibility
 	ctx.GlobalFlags.Database = q.Database
 	ctx.GlobalFlags.Priority = q.Priority
 	ctx.GlobalFlags.Engine = q.Engine
-	handleQuerySubmit(ctx.Context, ctx.Client, []string{q.Query}, ctx.GlobalFlags)
+	opts := querySubmitOptions{
+		ResultURL:        q.ResultURL,
+		ResultConnection: q.ResultConnection,
+		Wait:             q.Wait,
+		Timeout:          q.Timeout,
+		PoolName:         q.PoolName,
+		EngineVersion:    q.EngineVersion,
+		DomainKey:        q.DomainKey,
+	}
+	if q.Preset != "" {
+		config, err := LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+		preset, err := config.PriorityPreset(q.Preset)
+		if err != nil {
+			return err
+		}
+		opts.Preset = &preset
+	}
+	handleQuerySubmitWithOptions(ctx.Context, ctx.Client, []string{query}, opts, ctx.GlobalFlags)
 	return nil
 }
 
@@ -209,13 +294,30 @@ func (q *QueryStatusCmd) Run(ctx *CLIContext) error {
 }
 
 type QueryResultCmd struct {
-	JobID string `kong:"arg,help='Job ID'"`
-	Limit int    `kong:"help='Limit number of result rows'"`
+	JobID        string `kong:"arg,help='Job ID'"`
+	Limit        int    `kong:"help='Limit number of result rows (0 for all)'"`
+	ResultFormat string `kong:"help='Download the results as served in this format (json, jsonl, csv, tsv, msgpack, msgpack.gz)'"`
+	Resume       bool   `kong:"help='Continue an interrupted download to --output instead of starting over'"`
+	Parallel     int    `kong:"help='Download this many ranges of the result at once, for very large results'"`
+	Types        bool   `kong:"help='With --format csv, start with a line of column names and a line of column types'"`
 }
 
 func (q *QueryResultCmd) Run(ctx *CLIContext) error {
 	ctx.GlobalFlags.Limit = q.Limit
-	handleQueryResult(ctx.Context, ctx.Client, []string{q.JobID}, ctx.GlobalFlags)
+	if q.ResultFormat != "" || q.Resume || q.Parallel > 1 {
+		if q.Types {
+			return fmt.Errorf("--types cannot be combined with --result-format, --resume or --parallel")
+		}
+		format := td.ResultFormatJSON
+		if q.ResultFormat != "" {
+			var err error
+			if format, err = td.ParseResultFormat(q.ResultFormat); err != nil {
+				return err
+			}
+		}
+		return downloadQueryResult(ctx.Context, ctx.Client, q.JobID, format, q.Resume, q.Parallel, ctx.GlobalFlags)
+	}
+	handleQueryResult(ctx.Context, ctx.Client, []string{q.JobID}, q.Types, ctx.GlobalFlags)
 	return nil
 }
 
@@ -243,15 +345,39 @@ type JobsCmd struct {
 	List   JobsListCmd   `kong:"cmd,aliases='ls',help='List jobs'"`
 	Get    JobsGetCmd    `kong:"cmd,aliases='show',help='Get job details'"`
 	Cancel JobsCancelCmd `kong:"cmd,aliases='kill',help='Cancel a running job'"`
+	Queue  JobsQueueCmd  `kong:"cmd,help='Show queued and running jobs by engine and priority'"`
+	Logs   JobsLogsCmd   `kong:"cmd,help='Print the engine output and errors of a job'"`
 }
 
 type JobsListCmd struct {
-	Status string `kong:"help='Filter by job status'"`
+	Status   string `kong:"help='Filter by job status'"`
+	From     string `kong:"help='Only jobs created after this lookback (7d, 12h), date (2006-01-02) or RFC 3339 time'"`
+	To       string `kong:"help='Only jobs created before this lookback, date or time'"`
+	Database string `kong:"help='Only jobs that ran against this database'"`
+	Limit    int    `kong:"help='Number of jobs to show (0 for all)',default='20'"`
 }
 
 func (j *JobsListCmd) Run(ctx *CLIContext) error {
-	ctx.GlobalFlags.Status = j.Status
-	handleJobList(ctx.Context, ctx.Client, ctx.GlobalFlags)
+	now := time.Now()
+	since, err := parseTimeBound(j.From, now)
+	if err != nil {
+		return fmt.Errorf("invalid --from: %w", err)
+	}
+	until, err := parseTimeBound(j.To, now)
+	if err != nil {
+		return fmt.Errorf("invalid --to: %w", err)
+	}
+	if !since.IsZero() && !until.IsZero() && !since.Before(until) {
+		return fmt.Errorf("--from must be before --to")
+	}
+
+	opts := &td.JobListOptions{
+		Status:   j.Status,
+		Since:    since,
+		Until:    until,
+		Database: j.Database,
+	}
+	handleJobListFiltered(ctx.Context, ctx.Client, opts, j.Limit, ctx.GlobalFlags)
 	return nil
 }
 
@@ -273,10 +399,21 @@ func (j *JobsCancelCmd) Run(ctx *CLIContext) error {
 	return nil
 }
 
+type JobsLogsCmd struct {
+	JobID  string `kong:"arg,help='Job ID'"`
+	Stderr bool   `kong:"help='Only print the error output'"`
+}
+
+func (j *JobsLogsCmd) Run(ctx *CLIContext) error {
+	handleJobLogs(ctx.Context, ctx.Client, j.JobID, j.Stderr, ctx.GlobalFlags)
+	return nil
+}
+
 // User commands
 type UsersCmd struct {
-	List UsersListCmd `kong:"cmd,aliases='ls',help='List users'"`
-	Get  UsersGetCmd  `kong:"cmd,aliases='show',help='Get user details'"`
+	List     UsersListCmd     `kong:"cmd,aliases='ls',help='List users'"`
+	Get      UsersGetCmd      `kong:"cmd,aliases='show',help='Get user details'"`
+	Activity UsersActivityCmd `kong:"cmd,help='Show job activity per user'"`
 }
 
 type UsersListCmd struct{}
@@ -295,6 +432,19 @@ func (u *UsersGetCmd) Run(ctx *CLIContext) error {
 	return nil
 }
 
+type UsersActivityCmd struct {
+	Since string `kong:"help='Start of the job history to scan: a lookback (30d, 2w) or a date',default='30d'"`
+}
+
+func (u *UsersActivityCmd) Run(ctx *CLIContext) error {
+	since, err := parseTimeBound(u.Since, time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	handleUserActivity(ctx.Context, ctx.Client, since, ctx.GlobalFlags)
+	return nil
+}
+
 // Permissions commands
 type PermsCmd struct {
 	Policies PermsPoliciesCmd `kong:"cmd,help='Policy management'"`
@@ -415,7 +565,11 @@ func (p *PermsUsersGetCmd) Run(ctx *CLIContext) error {
 
 // Results commands
 type ResultsCmd struct {
-	Get ResultsGetCmd `kong:"cmd,aliases='show',help='Get query results'"`
+	Get    ResultsGetCmd    `kong:"cmd,aliases='show',help='Get query results'"`
+	List   ResultsListCmd   `kong:"cmd,aliases='ls',help='List saved result connections'"`
+	Create ResultsCreateCmd `kong:"cmd,help='Save a result URL as a named result connection'"`
+	Delete ResultsDeleteCmd `kong:"cmd,aliases='rm',help='Delete a saved result connection'"`
+	Test   ResultsTestCmd   `kong:"cmd,help='Check a saved result connection before queries write to it'"`
 }
 
 type ResultsGetCmd struct {
@@ -431,16 +585,18 @@ func (r *ResultsGetCmd) Run(ctx *CLIContext) error {
 
 // Import (Bulk Import) commands
 type ImportCmd struct {
-	List     ImportListCmd     `kong:"cmd,aliases='ls',help='List bulk import sessions'"`
-	Get      ImportGetCmd      `kong:"cmd,aliases='show',help='Get bulk import session details'"`
-	Create   ImportCreateCmd   `kong:"cmd,help='Create a new bulk import session'"`
-	Delete   ImportDeleteCmd   `kong:"cmd,aliases='rm',help='Delete a bulk import session'"`
-	Upload   ImportUploadCmd   `kong:"cmd,help='Upload a part to session'"`
-	Commit   ImportCommitCmd   `kong:"cmd,help='Commit a bulk import session'"`
-	Perform  ImportPerformCmd  `kong:"cmd,help='Perform bulk import job'"`
-	Freeze   ImportFreezeCmd   `kong:"cmd,help='Freeze a bulk import session'"`
-	Unfreeze ImportUnfreezeCmd `kong:"cmd,help='Unfreeze a bulk import session'"`
-	Parts    ImportPartsCmd    `kong:"cmd,help='List parts in a bulk import session'"`
+	List      ImportListCmd      `kong:"cmd,aliases='ls',help='List bulk import sessions'"`
+	Get       ImportGetCmd       `kong:"cmd,aliases='show',help='Get bulk import session details'"`
+	Create    ImportCreateCmd    `kong:"cmd,help='Create a new bulk import session'"`
+	Delete    ImportDeleteCmd    `kong:"cmd,aliases='rm',help='Delete a bulk import session'"`
+	Upload    ImportUploadCmd    `kong:"cmd,help='Upload a part to session'"`
+	UploadDir ImportUploadDirCmd `kong:"cmd,name='upload-dir',help='Upload every matching file in a directory as parts'"`
+	Commit    ImportCommitCmd    `kong:"cmd,help='Commit a bulk import session'"`
+	Perform   ImportPerformCmd   `kong:"cmd,help='Perform bulk import job'"`
+	Freeze    ImportFreezeCmd    `kong:"cmd,help='Freeze a bulk import session'"`
+	Unfreeze  ImportUnfreezeCmd  `kong:"cmd,help='Unfreeze a bulk import session'"`
+	Parts     ImportPartsCmd     `kong:"cmd,help='List parts in a bulk import session'"`
+	Bridge    ImportBridgeCmd    `kong:"cmd,help='Stream records from Kafka or JSONL into a table'"`
 }
 
 type ImportListCmd struct{}
@@ -480,16 +636,45 @@ func (i *ImportDeleteCmd) Run(ctx *CLIContext) error {
 }
 
 type ImportUploadCmd struct {
-	Session  string `kong:"arg,help='Session name'"`
-	PartName string `kong:"arg,help='Part name'"`
-	FilePath string `kong:"arg,help='File path'"`
+	Session       string        `kong:"arg,help='Session name'"`
+	PartName      string        `kong:"arg,help='Part name'"`
+	FilePath      string        `kong:"arg,help='File path (.jsonl files are converted to msgpack.gz)'"`
+	Schema        string        `kong:"help='JSON Schema to validate JSONL records against (default: import_schemas entry in config)'"`
+	DeadLetter    string        `kong:"help='Append invalid records to this file instead of only rejecting them'"`
+	TimeField     string        `kong:"help='Field to derive the time column from (default: existing time field, else now)'"`
+	MaxFutureSkew time.Duration `kong:"help='Reject records timestamped further than this in the future (0 disables)',default='1h'"`
+	Force         bool          `kong:"help='Upload even if the part ledger shows this part was already uploaded'"`
 }
 
 func (i *ImportUploadCmd) Run(ctx *CLIContext) error {
-	handleBulkImportUpload(ctx.Context, ctx.Client, []string{i.Session, i.PartName, i.FilePath}, ctx.GlobalFlags)
+	opts := bulkImportUploadOptions{
+		Schema:        i.Schema,
+		DeadLetter:    i.DeadLetter,
+		TimeField:     i.TimeField,
+		MaxFutureSkew: i.MaxFutureSkew,
+		Force:         i.Force,
+	}
+	handleBulkImportUploadWithOptions(ctx.Context, ctx.Client, []string{i.Session, i.PartName, i.FilePath}, opts, ctx.GlobalFlags)
 	return nil
 }
 
+type ImportUploadDirCmd struct {
+	Session       string        `kong:"arg,help='Session name'"`
+	Dir           string        `kong:"arg,help='Directory to upload'"`
+	Pattern       string        `kong:"help='Glob for files to upload; matched against the file name, or the relative path if it contains /',default='*'"`
+	Parallel      int           `kong:"help='Number of concurrent uploads',default='4'"`
+	Manifest      string        `kong:"help='Upload manifest path (default: ~/.tdcli/bulk_import/<session>.manifest.json)'"`
+	Schema        string        `kong:"help='JSON Schema to validate JSONL records against (default: import_schemas entry in config)'"`
+	DeadLetter    string        `kong:"help='Append invalid records to this file instead of only rejecting them'"`
+	TimeField     string        `kong:"help='Field to derive the time column from (default: existing time field, else now)'"`
+	MaxFutureSkew time.Duration `kong:"help='Reject records timestamped further than this in the future (0 disables)',default='1h'"`
+	Force         bool          `kong:"help='Upload even if the part ledger shows a part was already uploaded'"`
+}
+
+func (i *ImportUploadDirCmd) Run(ctx *CLIContext) error {
+	return handleImportUploadDir(ctx.Context, ctx.Client, i, ctx.GlobalFlags)
+}
+
 type ImportCommitCmd struct {
 	Session string `kong:"arg,help='Session name'"`
 }
@@ -500,11 +685,15 @@ func (i *ImportCommitCmd) Run(ctx *CLIContext) error {
 }
 
 type ImportPerformCmd struct {
-	Session string `kong:"arg,help='Session name'"`
+	Session string        `kong:"arg,help='Session name'"`
+	Wait    bool          `kong:"help='Wait for the perform job to finish'"`
+	Report  bool          `kong:"help='After the job finishes, summarize rejected records by column (implies --wait)'"`
+	Timeout time.Duration `kong:"help='Maximum time to wait (0 waits indefinitely)',default='0'"`
 }
 
 func (i *ImportPerformCmd) Run(ctx *CLIContext) error {
-	handleBulkImportPerform(ctx.Context, ctx.Client, []string{i.Session}, ctx.GlobalFlags)
+	opts := bulkImportPerformOptions{Wait: i.Wait, Report: i.Report, Timeout: i.Timeout}
+	handleBulkImportPerformWithOptions(ctx.Context, ctx.Client, []string{i.Session}, opts, ctx.GlobalFlags)
 	return nil
 }
 
@@ -535,6 +724,23 @@ func (i *ImportPartsCmd) Run(ctx *CLIContext) error {
 	return nil
 }
 
+type ImportBridgeCmd struct {
+	From          string        `kong:"required,help='Source: kafka://broker[,broker]/topic?partition=N, file://path.jsonl, or - for stdin'"`
+	To            string        `kong:"required,help='Destination table (database.table)'"`
+	BatchSize     int           `kong:"help='Maximum records per import request',default='1000'"`
+	FlushInterval time.Duration `kong:"help='Maximum time to buffer records before importing',default='5s'"`
+	Checkpoint    string        `kong:"help='Offset checkpoint file (default: ~/.tdcli/bridge/<database>.<table>.json)'"`
+	MaxRetries    int           `kong:"help='Retries per batch before giving up',default='5'"`
+	Schema        string        `kong:"help='JSON Schema to validate records against (default: import_schemas entry in config)'"`
+	DeadLetter    string        `kong:"help='Append invalid records to this file instead of only rejecting them'"`
+	TimeField     string        `kong:"help='Field to derive the time column from (default: existing time field, else now)'"`
+	MaxFutureSkew time.Duration `kong:"help='Reject records timestamped further than this in the future (0 disables)',default='1h'"`
+}
+
+func (i *ImportBridgeCmd) Run(ctx *CLIContext) error {
+	return handleImportBridge(ctx.Context, ctx.Client, i, ctx.GlobalFlags)
+}
+
 // Flags struct for compatibility with existing handlers
 type Flags struct {
 	APIKey             string
@@ -559,6 +765,12 @@ type CLIContext struct {
 	Context     context.Context
 	Client      *td.Client
 	GlobalFlags Flags
+	// Config is the loaded configuration, used for named profiles
+	Config *Config
+	// Workspace is the .tdcli.yaml of the working directory, if any
+	Workspace *WorkspaceConfig
+	// Profile is the profile selected with --profile, TD_PROFILE or the workspace
+	Profile string
 }
 
 // CDP commands
@@ -570,6 +782,7 @@ type CDPCmd struct {
 	Tokens              CDPTokensCmd              `kong:"cmd,aliases='token',help='CDP token management'"`
 	Journeys            CDPJourneysCmd            `kong:"cmd,aliases='journey',help='CDP journey management'"`
 	ActivationTemplates CDPActivationTemplatesCmd `kong:"cmd,aliases='activation-template',help='CDP activation template management'"`
+	Behaviors           CDPBehaviorsCmd           `kong:"cmd,aliases='behavior',help='CDP behavior (event) data'"`
 }
 
 type CDPSegmentsCmd struct {
@@ -585,6 +798,9 @@ type CDPSegmentsCmd struct {
 	KillQuery   CDPSegmentsKillQueryCmd   `kong:"cmd,aliases='kill-query',help='Kill segment query'"`
 	Customers   CDPSegmentsCustomersCmd   `kong:"cmd,help='Get segment customers'"`
 	Statistics  CDPSegmentsStatisticsCmd  `kong:"cmd,aliases='stats',help='Get segment statistics'"`
+	RenameAttr  CDPSegmentsRenameAttrCmd  `kong:"cmd,name='rename-attribute',help='Rewrite segment rules for a renamed attribute'"`
+	RmBulk      CDPSegmentsRmBulkCmd      `kong:"cmd,name='rm-bulk',help='Delete segments whose names start with a prefix'"`
+	Rename      CDPSegmentsRenameCmd      `kong:"cmd,help='Rename segment and report references to it'"`
 }
 
 type CDPSegmentsCreateCmd struct {
@@ -640,6 +856,19 @@ func (c *CDPSegmentsDeleteCmd) Run(ctx *CLIContext) error {
 	return nil
 }
 
+type CDPSegmentsRenameAttrCmd struct {
+	AudienceID string `kong:"arg,help='Audience ID'"`
+	From       string `kong:"arg,help='Current attribute or column name'"`
+	To         string `kong:"arg,help='New attribute or column name'"`
+	Behavior   string `kong:"help='Rename a column of this behavior instead of an attribute'"`
+	DryRun     bool   `kong:"help='Show affected segments and a diff without updating them'"`
+}
+
+func (c *CDPSegmentsRenameAttrCmd) Run(ctx *CLIContext) error {
+	handleCDPSegmentRenameAttribute(ctx.Context, ctx.Client, []string{c.AudienceID, c.From, c.To}, c.Behavior, c.DryRun, ctx.GlobalFlags)
+	return nil
+}
+
 type CDPSegmentsFoldersCmd struct {
 	AudienceID string `kong:"arg,help='Audience ID'"`
 	FolderID   string `kong:"arg,help='Folder ID'"`
@@ -727,6 +956,9 @@ type CDPAudiencesCmd struct {
 	Statistics      CDPAudiencesStatisticsCmd      `kong:"cmd,aliases='stats',help='Get audience statistics'"`
 	SampleValues    CDPAudiencesSampleValuesCmd    `kong:"cmd,aliases='samples',help='Get audience sample values'"`
 	BehaviorSamples CDPAudiencesBehaviorSamplesCmd `kong:"cmd,help='Get behavior sample values'"`
+	AttributeUsage  CDPAudiencesAttributeUsageCmd  `kong:"cmd,help='Report attributes and behaviors referenced by segment rules'"`
+	Export          CDPAudiencesExportCmd          `kong:"cmd,help='Export an audience definition as JSON'"`
+	Import          CDPAudiencesImportCmd          `kong:"cmd,help='Recreate an audience from an export'"`
 }
 
 type CDPAudiencesCreateCmd struct {
@@ -804,10 +1036,13 @@ func (c *CDPAudiencesStatisticsCmd) Run(ctx *CLIContext) error {
 type CDPAudiencesSampleValuesCmd struct {
 	AudienceID string `kong:"arg,help='Audience ID'"`
 	Column     string `kong:"arg,help='Column name'"`
+	Limit      int    `kong:"help='Maximum number of values to show (default all)'"`
+	Offset     int    `kong:"help='Number of values to skip'"`
 }
 
 func (c *CDPAudiencesSampleValuesCmd) Run(ctx *CLIContext) error {
-	handleCDPAudienceSampleValues(ctx.Context, ctx.Client, []string{c.AudienceID, c.Column}, ctx.GlobalFlags)
+	ctx.GlobalFlags.Limit = c.Limit
+	handleCDPAudienceSampleValues(ctx.Context, ctx.Client, []string{c.AudienceID, c.Column}, c.Offset, ctx.GlobalFlags)
 	return nil
 }
 
@@ -815,13 +1050,76 @@ type CDPAudiencesBehaviorSamplesCmd struct {
 	AudienceID string `kong:"arg,help='Audience ID'"`
 	BehaviorID string `kong:"arg,help='Behavior ID'"`
 	Column     string `kong:"arg,help='Column name'"`
+	Limit      int    `kong:"help='Maximum number of values to show (default all)'"`
+	Offset     int    `kong:"help='Number of values to skip'"`
 }
 
 func (c *CDPAudiencesBehaviorSamplesCmd) Run(ctx *CLIContext) error {
-	handleCDPAudienceBehaviorSamples(ctx.Context, ctx.Client, []string{c.AudienceID, c.BehaviorID, c.Column}, ctx.GlobalFlags)
+	ctx.GlobalFlags.Limit = c.Limit
+	handleCDPAudienceBehaviorSamples(ctx.Context, ctx.Client, []string{c.AudienceID, c.BehaviorID, c.Column}, c.Offset, ctx.GlobalFlags)
+	return nil
+}
+
+type CDPAudiencesAttributeUsageCmd struct {
+	AudienceID string `kong:"arg,help='Audience ID'"`
+	Unused     bool   `kong:"help='Only list attributes and behaviors no segment references'"`
+}
+
+func (c *CDPAudiencesAttributeUsageCmd) Run(ctx *CLIContext) error {
+	handleCDPAudienceAttributeUsage(ctx.Context, ctx.Client, []string{c.AudienceID}, c.Unused, ctx.GlobalFlags)
+	return nil
+}
+
+type CDPAudiencesExportCmd struct {
+	AudienceID string `kong:"arg,help='Audience ID'"`
+}
+
+func (c *CDPAudiencesExportCmd) Run(ctx *CLIContext) error {
+	handleCDPAudienceExport(ctx.Context, ctx.Client, []string{c.AudienceID}, ctx.GlobalFlags)
+	return nil
+}
+
+type CDPAudiencesImportCmd struct {
+	File            string   `kong:"arg,help='Audience export file'"`
+	Name            string   `kong:"help='Name for the new audience (default exported name)'"`
+	Connection      []string `kong:"help='Map a source connection ID to a target one (source-id=target-id)'"`
+	SkipActivations bool     `kong:"help='Do not recreate activations'"`
+}
+
+func (c *CDPAudiencesImportCmd) Run(ctx *CLIContext) error {
+	handleCDPAudienceImport(ctx.Context, ctx.Client, []string{c.File}, c.Name, c.Connection, c.SkipActivations, ctx.GlobalFlags)
 	return nil
 }
 
+type CDPBehaviorsCmd struct {
+	Query CDPBehaviorsQueryCmd `kong:"cmd,help='Query events from an audience behavior table'"`
+}
+
+type CDPBehaviorsQueryCmd struct {
+	AudienceID string        `kong:"arg,help='Audience ID'"`
+	Behavior   string        `kong:"arg,help='Behavior ID or name'"`
+	Since      string        `kong:"help='Only events after this lookback (7d, 2w, 36h) or date (2006-01-02)'"`
+	Until      string        `kong:"help='Only events before this lookback or date'"`
+	Columns    []string      `kong:"help='Behavior columns to select (comma-separated, default all)'"`
+	Limit      int           `kong:"help='Limit number of rows',default='100'"`
+	Priority   int           `kong:"help='Job priority (-2 to 2)',default='0'"`
+	Timeout    time.Duration `kong:"help='Maximum time to wait for the query',default='10m'"`
+	DryRun     bool          `kong:"help='Print the generated query without running it'"`
+}
+
+func (c *CDPBehaviorsQueryCmd) Run(ctx *CLIContext) error {
+	ctx.GlobalFlags.Limit = c.Limit
+	ctx.GlobalFlags.Priority = c.Priority
+	opts := cdpBehaviorQueryOptions{
+		Since:   c.Since,
+		Until:   c.Until,
+		Columns: c.Columns,
+		Timeout: c.Timeout,
+		DryRun:  c.DryRun,
+	}
+	return handleCDPBehaviorQuery(ctx.Context, ctx.Client, c.AudienceID, c.Behavior, opts, ctx.GlobalFlags)
+}
+
 type CDPActivationsCmd struct {
 	Create              CDPActivationsCreateCmd              `kong:"cmd,help='Create activation'"`
 	CreateWithStruct    CDPActivationsCreateWithStructCmd    `kong:"cmd,help='Create activation with struct'"`
@@ -832,6 +1130,9 @@ type CDPActivationsCmd struct {
 	Delete              CDPActivationsDeleteCmd              `kong:"cmd,aliases='rm',help='Delete activation'"`
 	Execute             CDPActivationsExecuteCmd             `kong:"cmd,help='Execute activation'"`
 	Executions          CDPActivationsExecutionsCmd          `kong:"cmd,help='Get activation executions'"`
+	Execution           CDPActivationsExecutionCmd           `kong:"cmd,help='Get one activation execution with its record counts'"`
+	History             CDPActivationsHistoryCmd             `kong:"cmd,help='Runs of every activation of an audience'"`
+	RetryFailed         CDPActivationsRetryFailedCmd         `kong:"cmd,help='Re-run activations whose latest run failed'"`
 	ListByAudience      CDPActivationsListByAudienceCmd      `kong:"cmd,help='List activations by audience'"`
 	ListBySegmentFolder CDPActivationsListBySegmentFolderCmd `kong:"cmd,help='List activations by segment folder'"`
 	RunSegment          CDPActivationsRunSegmentCmd          `kong:"cmd,help='Run activation for segment'"`
@@ -940,6 +1241,32 @@ func (c *CDPActivationsExecutionsCmd) Run(ctx *CLIContext) error {
 	return nil
 }
 
+type CDPActivationsExecutionCmd struct {
+	AudienceID   string `kong:"arg,help='Audience ID'"`
+	SegmentID    string `kong:"arg,help='Segment ID'"`
+	ActivationID string `kong:"arg,help='Activation ID'"`
+	ExecutionID  string `kong:"arg,help='Execution ID'"`
+}
+
+func (c *CDPActivationsExecutionCmd) Run(ctx *CLIContext) error {
+	handleCDPGetActivationExecution(ctx.Context, ctx.Client, []string{c.AudienceID, c.SegmentID, c.ActivationID, c.ExecutionID}, ctx.GlobalFlags)
+	return nil
+}
+
+type CDPActivationsHistoryCmd struct {
+	AudienceID string `kong:"arg,help='Audience ID'"`
+	Since      string `kong:"help='Only runs after this lookback (30d, 2w, 36h) or date (2006-01-02)',default='30d'"`
+}
+
+func (c *CDPActivationsHistoryCmd) Run(ctx *CLIContext) error {
+	since, err := parseTimeBound(c.Since, time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	handleCDPActivationHistory(ctx.Context, ctx.Client, c.AudienceID, since, ctx.GlobalFlags)
+	return nil
+}
+
 type CDPActivationsListByAudienceCmd struct {
 	AudienceID string `kong:"arg,help='Audience ID'"`
 }
@@ -1015,6 +1342,7 @@ type CDPFoldersCmd struct {
 	UpdateEntity CDPFoldersUpdateEntityCmd `kong:"cmd,help='Update entity folder'"`
 	DeleteEntity CDPFoldersDeleteEntityCmd `kong:"cmd,help='Delete entity folder'"`
 	GetEntities  CDPFoldersGetEntitiesCmd  `kong:"cmd,help='Get entities by folder'"`
+	Rename       CDPFoldersRenameCmd       `kong:"cmd,help='Rename folder and report references to it'"`
 }
 
 type CDPFoldersListCmd struct {
@@ -1128,6 +1456,7 @@ type CDPTokensCmd struct {
 	GetEntity    CDPTokensGetEntityCmd    `kong:"cmd,aliases='get,show',help='Get entity token details'"`
 	UpdateEntity CDPTokensUpdateEntityCmd `kong:"cmd,help='Update entity token'"`
 	DeleteEntity CDPTokensDeleteEntityCmd `kong:"cmd,aliases='rm',help='Delete entity token'"`
+	Check        CDPTokensCheckCmd        `kong:"cmd,help='Look up a profile with a profiles API token and show the attributes and segments it returns'"`
 }
 
 type CDPTokensListCmd struct {
@@ -1500,6 +1829,7 @@ type WorkflowCmd struct {
 	Get      WorkflowGetCmd      `kong:"cmd,aliases='show',help='Get workflow details'"`
 	Create   WorkflowCreateCmd   `kong:"cmd,help='Create a new workflow'"`
 	Update   WorkflowUpdateCmd   `kong:"cmd,help='Update workflow'"`
+	Rename   WorkflowRenameCmd   `kong:"cmd,help='Rename workflow and report workflows that call it'"`
 	Delete   WorkflowDeleteCmd   `kong:"cmd,aliases='rm',help='Delete workflow'"`
 	Start    WorkflowStartCmd    `kong:"cmd,aliases='run',help='Start workflow execution'"`
 	Init     WorkflowInitCmd     `kong:"cmd,help='Create a sample workflow project'"`
@@ -1510,11 +1840,23 @@ type WorkflowCmd struct {
 	Projects WorkflowProjectsCmd `kong:"cmd,aliases='project,proj',help='Workflow project management'"`
 }
 
-type WorkflowListCmd struct{}
+type WorkflowListCmd struct {
+	Project       string `kong:"help='Only workflows of this project'"`
+	Status        string `kong:"help='Only workflows with this status'"`
+	ScheduledOnly bool   `kong:"name='scheduled-only',help='Only workflows with a schedule'"`
+	Search        string `kong:"help='Match workflow and project names on the server'"`
+	Limit         int    `kong:"help='Maximum number of workflows to list (0 for all)',default='0'"`
+}
 
 func (w *WorkflowListCmd) Run(ctx *CLIContext) error {
 	flags := workflow.Flags(ctx.GlobalFlags)
-	workflow.HandleWorkflowList(ctx.Context, ctx.Client, flags)
+	opts := &td.WorkflowListOptions{
+		Project:       w.Project,
+		Status:        w.Status,
+		ScheduledOnly: w.ScheduledOnly,
+		Search:        w.Search,
+	}
+	workflow.HandleWorkflowListFiltered(ctx.Context, ctx.Client, opts, w.Limit, flags)
 	return nil
 }
 
@@ -1637,6 +1979,8 @@ type WorkflowScheduleCmd struct {
 	Enable  WorkflowScheduleEnableCmd  `kong:"cmd,help='Enable workflow schedule'"`
 	Disable WorkflowScheduleDisableCmd `kong:"cmd,help='Disable workflow schedule'"`
 	Update  WorkflowScheduleUpdateCmd  `kong:"cmd,help='Update workflow schedule'"`
+	Pause   WorkflowSchedulePauseCmd   `kong:"cmd,help='Disable workflow schedule for a maintenance window and resume it afterwards'"`
+	Delete  WorkflowScheduleDeleteCmd  `kong:"cmd,aliases='rm',help='Delete workflow schedule'"`
 }
 
 type WorkflowScheduleGetCmd struct {
@@ -1684,9 +2028,56 @@ func (w *WorkflowScheduleUpdateCmd) Run(ctx *CLIContext) error {
 	return nil
 }
 
+type WorkflowSchedulePauseCmd struct {
+	WorkflowID int           `kong:"arg,help='Workflow ID'"`
+	For        time.Duration `kong:"name='for',help='Length of the pause (e.g. 2h, 30m)'"`
+	Until      string        `kong:"help='Time to resume at (RFC3339, e.g. 2026-01-02T06:00:00+09:00)'"`
+}
+
+func (w *WorkflowSchedulePauseCmd) Run(ctx *CLIContext) error {
+	var until time.Time
+	switch {
+	case w.For > 0 && w.Until != "":
+		return fmt.Errorf("--for and --until cannot be used together")
+	case w.For > 0:
+		until = time.Now().Add(w.For)
+	case w.Until != "":
+		t, err := time.Parse(time.RFC3339, w.Until)
+		if err != nil {
+			return fmt.Errorf("invalid --until %q: want RFC3339, e.g. 2026-01-02T06:00:00Z", w.Until)
+		}
+		until = t
+	default:
+		return fmt.Errorf("either --for or --until is required")
+	}
+	if !until.After(time.Now()) {
+		return fmt.Errorf("the pause must end in the future")
+	}
+
+	flags := workflow.Flags(ctx.GlobalFlags)
+	workflow.HandleWorkflowSchedulePause(ctx.Context, ctx.Client, fmt.Sprintf("%d", w.WorkflowID), until, flags)
+	return nil
+}
+
+type WorkflowScheduleDeleteCmd struct {
+	WorkflowID int  `kong:"arg,help='Workflow ID'"`
+	Force      bool `kong:"flag,help='Skip confirmation prompt'"`
+}
+
+func (w *WorkflowScheduleDeleteCmd) Run(ctx *CLIContext) error {
+	if !w.Force && !promptConfirmation(fmt.Sprintf("Delete the schedule of workflow %d?", w.WorkflowID)) {
+		fmt.Println("Cancelled")
+		return nil
+	}
+	flags := workflow.Flags(ctx.GlobalFlags)
+	workflow.HandleWorkflowScheduleDelete(ctx.Context, ctx.Client, []string{fmt.Sprintf("%d", w.WorkflowID)}, flags)
+	return nil
+}
+
 type WorkflowTasksCmd struct {
 	List WorkflowTasksListCmd `kong:"cmd,aliases='ls',help='List workflow tasks'"`
 	Get  WorkflowTasksGetCmd  `kong:"cmd,aliases='show',help='Get task details'"`
+	Jobs WorkflowTasksJobsCmd `kong:"cmd,help='List TD jobs launched by td> tasks with their status'"`
 }
 
 type WorkflowTasksListCmd struct {
@@ -1712,6 +2103,17 @@ func (w *WorkflowTasksGetCmd) Run(ctx *CLIContext) error {
 	return nil
 }
 
+type WorkflowTasksJobsCmd struct {
+	WorkflowID int `kong:"arg,help='Workflow ID'"`
+	AttemptID  int `kong:"arg,help='Attempt ID'"`
+}
+
+func (w *WorkflowTasksJobsCmd) Run(ctx *CLIContext) error {
+	flags := workflow.Flags(ctx.GlobalFlags)
+	workflow.HandleWorkflowTaskJobs(ctx.Context, ctx.Client, []string{fmt.Sprintf("%d", w.WorkflowID), fmt.Sprintf("%d", w.AttemptID)}, flags)
+	return nil
+}
+
 type WorkflowLogsCmd struct {
 	Attempt WorkflowLogsAttemptCmd `kong:"cmd,help='Get attempt log'"`
 	Task    WorkflowLogsTaskCmd    `kong:"cmd,help='Get task log'"`
@@ -1756,6 +2158,7 @@ type WorkflowProjectsCmd struct {
 	Create    WorkflowProjectsCreateCmd    `kong:"cmd,help='Create a new project'"`
 	Push      WorkflowProjectsPushCmd      `kong:"cmd,help='Push project from directory (alias for create)'"`
 	Download  WorkflowProjectsDownloadCmd  `kong:"cmd,help='Download project archive and extract to directory'"`
+	Restore   WorkflowProjectsRestoreCmd   `kong:"cmd,help='Push the last revision of a deleted project again'"`
 	Workflows WorkflowProjectsWorkflowsCmd `kong:"cmd,aliases='wf',help='List workflows in project'"`
 	Secrets   WorkflowProjectsSecretsCmd   `kong:"cmd,aliases='secret',help='Project secrets management'"`
 	Hooks     WorkflowProjectsHooksCmd     `kong:"cmd,aliases='hook',help='Workflow hooks management'"`
@@ -1770,12 +2173,16 @@ func (w *WorkflowProjectsListCmd) Run(ctx *CLIContext) error {
 }
 
 type WorkflowProjectsGetCmd struct {
-	ProjectID string `kong:"arg,help='Project ID or name'"`
+	ProjectID string `kong:"arg,optional,help='Project ID or name (defaults to workflow_project in .tdcli.yaml)'"`
 }
 
 func (w *WorkflowProjectsGetCmd) Run(ctx *CLIContext) error {
+	project, err := ctx.workflowProject(w.ProjectID)
+	if err != nil {
+		return err
+	}
 	flags := workflow.Flags(ctx.GlobalFlags)
-	workflow.HandleWorkflowProjectGet(ctx.Context, ctx.Client, []string{w.ProjectID}, flags)
+	workflow.HandleWorkflowProjectGet(ctx.Context, ctx.Client, []string{project}, flags)
 	return nil
 }
 
@@ -1802,20 +2209,36 @@ func (w *WorkflowProjectsPushCmd) Run(ctx *CLIContext) error {
 }
 
 type WorkflowProjectsDownloadCmd struct {
-	ProjectIdentifier string `kong:"arg,help='Project ID or name'"`
+	ProjectIdentifier string `kong:"arg,optional,help='Project ID or name (defaults to workflow_project in .tdcli.yaml)'"`
 	OutputDir         string `kong:"optional,help='Output directory (defaults to project name)'"`
 	Revision          string `kong:"help='Specific revision to download'"`
 }
 
 func (w *WorkflowProjectsDownloadCmd) Run(ctx *CLIContext) error {
-	args := []string{w.ProjectIdentifier}
+	project, err := ctx.workflowProject(w.ProjectIdentifier)
+	if err != nil {
+		return err
+	}
+	args := []string{project}
 	if w.OutputDir != "" {
 		args = append(args, w.OutputDir)
 	}
 	// For now, we'll handle revision in the handler function
 	// We can extend this later to pass revision through args or flags
 	flags := workflow.Flags(ctx.GlobalFlags)
-	workflow.HandleWorkflowProjectDownload(ctx.Context, ctx.Client, args, flags)
+	// Archives are small unless the project carries data files
+	bar := newTerminalProgressBar("Downloading " + project)
+	workflow.HandleWorkflowProjectDownload(ctx.Context, ctx.Client, args, flags, td.WithProgress(bar.Update))
+	return nil
+}
+
+type WorkflowProjectsRestoreCmd struct {
+	ProjectID string `kong:"arg,help='ID of the deleted project'"`
+}
+
+func (w *WorkflowProjectsRestoreCmd) Run(ctx *CLIContext) error {
+	flags := workflow.Flags(ctx.GlobalFlags)
+	workflow.HandleWorkflowProjectRestore(ctx.Context, ctx.Client, []string{w.ProjectID}, flags)
 	return nil
 }
 
@@ -1950,16 +2373,22 @@ type TrinoQueryCmd struct {
 	Database string `kong:"help='Database/schema to use',default='sample_datasets'"`
 	Limit    int    `kong:"help='Limit number of result rows'"`
 	PageSize int    `kong:"help='Page size for pagination (0 = no pagination)',default='0'"`
+
+	Var map[string]string `kong:"placeholder='KEY=VALUE',help='Render the query as a template with this variable, e.g. {{tdIdentifier .table}}'"`
 }
 
 func (t *TrinoQueryCmd) Run(ctx *CLIContext) error {
+	query, err := renderQueryVars(t.Query, t.Var, td.QueryTypeTrino)
+	if err != nil {
+		return err
+	}
 	ctx.GlobalFlags.Database = t.Database
 	ctx.GlobalFlags.Limit = t.Limit
 	// Add page size to flags for non-interactive queries
 	if t.PageSize > 0 {
-		handleTrinoQueryWithPagination(ctx.Context, ctx.Client, []string{t.Query}, ctx.GlobalFlags, t.PageSize)
+		handleTrinoQueryWithPagination(ctx.Context, ctx.Client, []string{query}, ctx.GlobalFlags, t.PageSize)
 	} else {
-		handleTrinoQuery(ctx.Context, ctx.Client, []string{t.Query}, ctx.GlobalFlags)
+		handleTrinoQuery(ctx.Context, ctx.Client, []string{query}, ctx.GlobalFlags)
 	}
 	return nil
 }
@@ -1999,6 +2428,7 @@ type TrinoShowCmd struct {
 	Type     string `kong:"arg,help='What to show: schemas, tables, columns',enum='schemas,tables,columns'"`
 	Table    string `kong:"help='Table name (required for columns)'"`
 	Database string `kong:"help='Database/schema to use',default='sample_datasets'"`
+	Like     string `kong:"help='Only show schemas or tables whose name contains this text'"`
 }
 
 func (t *TrinoShowCmd) Run(ctx *CLIContext) error {
@@ -2007,7 +2437,7 @@ func (t *TrinoShowCmd) Run(ctx *CLIContext) error {
 	if t.Table != "" {
 		args = append(args, t.Table)
 	}
-	handleTrinoShow(ctx.Context, ctx.Client, args, ctx.GlobalFlags)
+	handleTrinoShow(ctx.Context, ctx.Client, args, t.Like, ctx.GlobalFlags)
 	return nil
 }
 
@@ -0,0 +1,437 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// Statuses of a compareEntry
+const (
+	compareOnlyInSource = "only_in_source"
+	compareOnlyInTarget = "only_in_target"
+	compareDifferent    = "different"
+)
+
+// compareEntry is one difference between the source and target environments
+type compareEntry struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// compareProfiles are the flags shared by the compare subcommands
+type compareProfiles struct {
+	ProfileSrc string `kong:"name='profile-src',help='Profile of the source environment (default: global --api-key/--region)'"`
+	ProfileDst string `kong:"name='profile-dst',help='Profile of the target environment (default: global --api-key/--region)'"`
+	ExitCode   bool   `kong:"help='Exit with status 1 when differences are found'"`
+}
+
+// clients returns the source and target clients for the selected profiles
+func (p *compareProfiles) clients(ctx *CLIContext) (*td.Client, *td.Client, error) {
+	if p.ProfileSrc == p.ProfileDst {
+		return nil, nil, fmt.Errorf("--profile-src and --profile-dst must name different environments")
+	}
+	src, err := profileClient(ctx.Config, p.ProfileSrc, ctx.Client)
+	if err != nil {
+		return nil, nil, fmt.Errorf("source: %w", err)
+	}
+	dst, err := profileClient(ctx.Config, p.ProfileDst, ctx.Client)
+	if err != nil {
+		return nil, nil, fmt.Errorf("target: %w", err)
+	}
+	return src, dst, nil
+}
+
+// report prints the differences and applies --exit-code
+func (p *compareProfiles) report(entries []compareEntry, flags Flags) error {
+	if err := printCompareEntries(entries, p.ProfileSrc, p.ProfileDst, flags); err != nil {
+		return err
+	}
+	if p.ExitCode && len(entries) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+type CompareCmd struct {
+	Databases CompareDatabasesCmd `kong:"cmd,aliases='db',help='Compare databases and tables'"`
+	Policies  ComparePoliciesCmd  `kong:"cmd,help='Compare access control policies and their permissions'"`
+	CDP       CompareCDPCmd       `kong:"cmd,name='cdp',help='Compare CDP audiences, attributes, segments and activations'"`
+}
+
+type CompareDatabasesCmd struct {
+	compareProfiles `kong:"embed"`
+	Tables          bool `kong:"help='Also compare tables and their schemas in databases present in both'"`
+}
+
+func (c *CompareDatabasesCmd) Run(ctx *CLIContext) error {
+	src, dst, err := c.clients(ctx)
+	if err != nil {
+		return err
+	}
+	entries, err := compareDatabases(ctx.Context, src, dst, c.Tables)
+	if err != nil {
+		return err
+	}
+	return c.report(entries, ctx.GlobalFlags)
+}
+
+type ComparePoliciesCmd struct {
+	compareProfiles `kong:"embed"`
+}
+
+func (c *ComparePoliciesCmd) Run(ctx *CLIContext) error {
+	src, dst, err := c.clients(ctx)
+	if err != nil {
+		return err
+	}
+	entries, err := comparePolicies(ctx.Context, src, dst)
+	if err != nil {
+		return err
+	}
+	return c.report(entries, ctx.GlobalFlags)
+}
+
+type CompareCDPCmd struct {
+	compareProfiles `kong:"embed"`
+}
+
+func (c *CompareCDPCmd) Run(ctx *CLIContext) error {
+	src, dst, err := c.clients(ctx)
+	if err != nil {
+		return err
+	}
+	entries, err := compareCDP(ctx.Context, src, dst)
+	if err != nil {
+		return err
+	}
+	return c.report(entries, ctx.GlobalFlags)
+}
+
+// diffNames reports names present on only one side and returns the names
+// present in both, sorted
+func diffNames(kind, prefix string, src, dst []string) ([]compareEntry, []string) {
+	inDst := make(map[string]bool, len(dst))
+	for _, name := range dst {
+		inDst[name] = true
+	}
+	inSrc := make(map[string]bool, len(src))
+	for _, name := range src {
+		inSrc[name] = true
+	}
+
+	var entries []compareEntry
+	var common []string
+	for _, name := range src {
+		if inDst[name] {
+			common = append(common, name)
+		} else {
+			entries = append(entries, compareEntry{Kind: kind, Name: prefix + name, Status: compareOnlyInSource})
+		}
+	}
+	for _, name := range dst {
+		if !inSrc[name] {
+			entries = append(entries, compareEntry{Kind: kind, Name: prefix + name, Status: compareOnlyInTarget})
+		}
+	}
+	sort.Strings(common)
+	return entries, common
+}
+
+func compareDatabases(ctx context.Context, src, dst *td.Client, withTables bool) ([]compareEntry, error) {
+	srcDBs, err := src.Databases.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("source: %w", err)
+	}
+	dstDBs, err := dst.Databases.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("target: %w", err)
+	}
+
+	var srcNames, dstNames []string
+	for _, db := range srcDBs {
+		srcNames = append(srcNames, db.Name)
+	}
+	for _, db := range dstDBs {
+		dstNames = append(dstNames, db.Name)
+	}
+	entries, common := diffNames("database", "", srcNames, dstNames)
+	if !withTables {
+		return entries, nil
+	}
+
+	for _, db := range common {
+		srcTables, err := src.Tables.List(ctx, db)
+		if err != nil {
+			return nil, fmt.Errorf("source: %w", err)
+		}
+		dstTables, err := dst.Tables.List(ctx, db)
+		if err != nil {
+			return nil, fmt.Errorf("target: %w", err)
+		}
+
+		srcByName := make(map[string]td.Table, len(srcTables))
+		var srcTableNames, dstTableNames []string
+		for _, table := range srcTables {
+			srcByName[table.Name] = table
+			srcTableNames = append(srcTableNames, table.Name)
+		}
+		dstByName := make(map[string]td.Table, len(dstTables))
+		for _, table := range dstTables {
+			dstByName[table.Name] = table
+			dstTableNames = append(dstTableNames, table.Name)
+		}
+
+		tableEntries, commonTables := diffNames("table", db+".", srcTableNames, dstTableNames)
+		entries = append(entries, tableEntries...)
+		for _, name := range commonTables {
+			s, d := srcByName[name], dstByName[name]
+			var details []string
+			if s.Type != d.Type {
+				details = append(details, fmt.Sprintf("type %s vs %s", s.Type, d.Type))
+			}
+			if s.Schema != d.Schema {
+				details = append(details, "schema differs")
+			}
+			if len(details) > 0 {
+				entries = append(entries, compareEntry{Kind: "table", Name: db + "." + name, Status: compareDifferent, Detail: strings.Join(details, ", ")})
+			}
+		}
+	}
+	return entries, nil
+}
+
+func comparePolicies(ctx context.Context, src, dst *td.Client) ([]compareEntry, error) {
+	srcPolicies, err := src.Permissions.ListPolicies(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("source: %w", err)
+	}
+	dstPolicies, err := dst.Permissions.ListPolicies(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("target: %w", err)
+	}
+
+	srcByName := make(map[string]td.AccessControlPolicy, len(srcPolicies))
+	dstByName := make(map[string]td.AccessControlPolicy, len(dstPolicies))
+	var srcNames, dstNames []string
+	for _, policy := range srcPolicies {
+		srcByName[policy.Name] = policy
+		srcNames = append(srcNames, policy.Name)
+	}
+	for _, policy := range dstPolicies {
+		dstByName[policy.Name] = policy
+		dstNames = append(dstNames, policy.Name)
+	}
+
+	entries, common := diffNames("policy", "", srcNames, dstNames)
+	for _, name := range common {
+		s, d := srcByName[name], dstByName[name]
+		var details []string
+		if s.Description != d.Description {
+			details = append(details, "description differs")
+		}
+
+		// Policy IDs differ between accounts; permissions are compared by content
+		srcPerms, err := src.Permissions.GetPolicyPermissions(ctx, s.ID)
+		if err != nil {
+			return nil, fmt.Errorf("source: %w", err)
+		}
+		dstPerms, err := dst.Permissions.GetPolicyPermissions(ctx, d.ID)
+		if err != nil {
+			return nil, fmt.Errorf("target: %w", err)
+		}
+		if !reflect.DeepEqual(permissionFingerprint(srcPerms), permissionFingerprint(dstPerms)) {
+			details = append(details, "permissions differ")
+		}
+
+		if len(details) > 0 {
+			entries = append(entries, compareEntry{Kind: "policy", Name: name, Status: compareDifferent, Detail: strings.Join(details, ", ")})
+		}
+	}
+	return entries, nil
+}
+
+// permissionFingerprint returns the policy's permissions without
+// account-specific IDs, for comparison across accounts
+func permissionFingerprint(perms *td.AccessControlPermissions) interface{} {
+	data, _ := json.Marshal(perms)
+	var generic interface{}
+	json.Unmarshal(data, &generic)
+	return stripIDs(generic)
+}
+
+func stripIDs(node interface{}) interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for key, value := range n {
+			if key == "id" || strings.HasSuffix(key, "_id") {
+				delete(n, key)
+				continue
+			}
+			n[key] = stripIDs(value)
+		}
+	case []interface{}:
+		for i, value := range n {
+			n[i] = stripIDs(value)
+		}
+	}
+	return node
+}
+
+func compareCDP(ctx context.Context, src, dst *td.Client) ([]compareEntry, error) {
+	srcAudiences, err := src.CDP.ListAudiences(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("source: %w", err)
+	}
+	dstAudiences, err := dst.CDP.ListAudiences(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("target: %w", err)
+	}
+
+	srcIDs := make(map[string]string)
+	dstIDs := make(map[string]string)
+	var srcNames, dstNames []string
+	for _, audience := range srcAudiences.Audiences {
+		srcIDs[audience.Name] = audience.ID
+		srcNames = append(srcNames, audience.Name)
+	}
+	for _, audience := range dstAudiences.Audiences {
+		dstIDs[audience.Name] = audience.ID
+		dstNames = append(dstNames, audience.Name)
+	}
+
+	entries, common := diffNames("audience", "", srcNames, dstNames)
+	for _, name := range common {
+		s, err := src.CDP.ExportAudience(ctx, srcIDs[name])
+		if err != nil {
+			return nil, fmt.Errorf("source: %w", err)
+		}
+		d, err := dst.CDP.ExportAudience(ctx, dstIDs[name])
+		if err != nil {
+			return nil, fmt.Errorf("target: %w", err)
+		}
+		entries = append(entries, compareAudienceExports(name, s, d)...)
+	}
+	return entries, nil
+}
+
+// compareAudienceExports diffs the definitions of an audience that exists in
+// both environments
+func compareAudienceExports(audience string, src, dst *td.CDPAudienceExport) []compareEntry {
+	prefix := audience + "/"
+	var entries []compareEntry
+
+	if src.Audience.Master != dst.Audience.Master {
+		entries = append(entries, compareEntry{Kind: "audience", Name: audience, Status: compareDifferent,
+			Detail: fmt.Sprintf("master table %s.%s vs %s.%s", src.Audience.Master.ParentDatabaseName, src.Audience.Master.ParentTableName,
+				dst.Audience.Master.ParentDatabaseName, dst.Audience.Master.ParentTableName)})
+	}
+
+	var srcNames, dstNames []string
+	for _, attribute := range src.Attributes {
+		srcNames = append(srcNames, attribute.Name)
+	}
+	for _, attribute := range dst.Attributes {
+		dstNames = append(dstNames, attribute.Name)
+	}
+	attributeEntries, _ := diffNames("attribute", prefix, srcNames, dstNames)
+	entries = append(entries, attributeEntries...)
+
+	srcNames, dstNames = nil, nil
+	for _, behavior := range src.Behaviors {
+		srcNames = append(srcNames, behavior.Name)
+	}
+	for _, behavior := range dst.Behaviors {
+		dstNames = append(dstNames, behavior.Name)
+	}
+	behaviorEntries, _ := diffNames("behavior", prefix, srcNames, dstNames)
+	entries = append(entries, behaviorEntries...)
+
+	srcRules := make(map[string]interface{})
+	dstRules := make(map[string]interface{})
+	srcNames, dstNames = nil, nil
+	for _, segment := range src.Segments {
+		srcRules[segment.Name] = segment.Rule
+		srcNames = append(srcNames, segment.Name)
+	}
+	for _, segment := range dst.Segments {
+		dstRules[segment.Name] = segment.Rule
+		dstNames = append(dstNames, segment.Name)
+	}
+	segmentEntries, commonSegments := diffNames("segment", prefix, srcNames, dstNames)
+	entries = append(entries, segmentEntries...)
+	for _, name := range commonSegments {
+		// Segment references carry environment-specific IDs
+		if !reflect.DeepEqual(stripIDs(normalizeJSON(srcRules[name])), stripIDs(normalizeJSON(dstRules[name]))) {
+			entries = append(entries, compareEntry{Kind: "segment", Name: prefix + name, Status: compareDifferent, Detail: "rule differs"})
+		}
+	}
+
+	srcNames, dstNames = nil, nil
+	for _, activation := range src.Activations {
+		srcNames = append(srcNames, activation.Name)
+	}
+	for _, activation := range dst.Activations {
+		dstNames = append(dstNames, activation.Name)
+	}
+	activationEntries, _ := diffNames("activation", prefix, srcNames, dstNames)
+	return append(entries, activationEntries...)
+}
+
+// normalizeJSON converts a value to its generic JSON form
+func normalizeJSON(v interface{}) interface{} {
+	data, _ := json.Marshal(v)
+	var generic interface{}
+	json.Unmarshal(data, &generic)
+	return generic
+}
+
+func printCompareEntries(entries []compareEntry, srcName, dstName string, flags Flags) error {
+	if srcName == "" {
+		srcName = "source"
+	}
+	if dstName == "" {
+		dstName = "target"
+	}
+
+	switch flags.Format {
+	case "json":
+		if entries == nil {
+			entries = []compareEntry{}
+		}
+		printJSON(entries)
+	case "csv":
+		fmt.Println("kind,name,status,detail")
+		for _, e := range entries {
+			fmt.Printf("%s,%s,%s,%s\n", e.Kind, e.Name, e.Status, e.Detail)
+		}
+	default:
+		if len(entries) == 0 {
+			fmt.Printf("No differences between %s and %s\n", srcName, dstName)
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, e := range entries {
+			marker, where := "~", e.Detail
+			switch e.Status {
+			case compareOnlyInSource:
+				marker, where = "-", "only in "+srcName
+			case compareOnlyInTarget:
+				marker, where = "+", "only in "+dstName
+			}
+			fmt.Fprintf(w, "%s %s\t%s\t%s\n", marker, e.Kind, e.Name, where)
+		}
+		w.Flush()
+		fmt.Printf("\n%d difference(s)\n", len(entries))
+	}
+	return nil
+}
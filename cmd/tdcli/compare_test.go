@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+func newCompareTestClient(t *testing.T, mux *http.ServeMux) *td.Client {
+	t.Helper()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	client, err := td.NewClient("1/abc", td.WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestCompareDatabases(t *testing.T) {
+	srcMux := http.NewServeMux()
+	srcMux.HandleFunc("/v3/database/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"databases": [{"name": "shared"}, {"name": "dev_only"}]}`)
+	})
+	srcMux.HandleFunc("/v3/table/list/shared", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tables": [{"name": "events", "type": "log", "schema": "[[\"user\",\"string\"]]"}, {"name": "tmp", "type": "log"}]}`)
+	})
+	dstMux := http.NewServeMux()
+	dstMux.HandleFunc("/v3/database/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"databases": [{"name": "shared"}, {"name": "prod_only"}]}`)
+	})
+	dstMux.HandleFunc("/v3/table/list/shared", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tables": [{"name": "events", "type": "log", "schema": "[[\"user\",\"int\"]]"}]}`)
+	})
+
+	src := newCompareTestClient(t, srcMux)
+	dst := newCompareTestClient(t, dstMux)
+
+	entries, err := compareDatabases(context.Background(), src, dst, true)
+	if err != nil {
+		t.Fatalf("compareDatabases returned error: %v", err)
+	}
+	want := []compareEntry{
+		{Kind: "database", Name: "dev_only", Status: compareOnlyInSource},
+		{Kind: "database", Name: "prod_only", Status: compareOnlyInTarget},
+		{Kind: "table", Name: "shared.tmp", Status: compareOnlyInSource},
+		{Kind: "table", Name: "shared.events", Status: compareDifferent, Detail: "schema differs"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entries = %+v, want %+v", entries, want)
+	}
+}
+
+func TestCompareAudienceExports(t *testing.T) {
+	src := &td.CDPAudienceExport{
+		Attributes: []td.CDPAudienceAttribute{{Name: "gender"}, {Name: "age"}},
+		Segments: []td.CDPSegment{
+			{ID: "1", Name: "Women", Rule: map[string]interface{}{"type": "Reference", "id": "10"}},
+			{ID: "2", Name: "Adults", Rule: map[string]interface{}{"type": "And", "conditions": []interface{}{}}},
+		},
+	}
+	dst := &td.CDPAudienceExport{
+		Attributes: []td.CDPAudienceAttribute{{Name: "gender"}},
+		Segments: []td.CDPSegment{
+			{ID: "7", Name: "Women", Rule: map[string]interface{}{"type": "Reference", "id": "70"}},
+			{ID: "8", Name: "Adults", Rule: map[string]interface{}{"type": "Or", "conditions": []interface{}{}}},
+		},
+	}
+
+	entries := compareAudienceExports("Customers", src, dst)
+	want := []compareEntry{
+		{Kind: "attribute", Name: "Customers/age", Status: compareOnlyInSource},
+		{Kind: "segment", Name: "Customers/Adults", Status: compareDifferent, Detail: "rule differs"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entries = %+v, want %+v", entries, want)
+	}
+}
+
+func TestProfileClient(t *testing.T) {
+	config := &Config{Profiles: map[string]ProfileConfig{
+		"prod":   {APIKey: "1/prod", Region: "eu"},
+		"broken": {APIKey: "not-a-key"},
+	}}
+
+	client, err := profileClient(config, "prod", nil)
+	if err != nil {
+		t.Fatalf("profileClient returned error: %v", err)
+	}
+	if client.APIKey != "1/prod" || client.BaseURL.Host != "api.eu01.treasuredata.com" {
+		t.Errorf("Client = %s at %s, want the eu prod profile", client.APIKey, client.BaseURL)
+	}
+
+	fallback, _ := td.NewClient("1/default")
+	if client, _ := profileClient(config, "", fallback); client != fallback {
+		t.Error("An empty profile should use the global client")
+	}
+	for _, name := range []string{"missing", "broken"} {
+		if _, err := profileClient(config, name, nil); err == nil {
+			t.Errorf("Expected error for profile %q", name)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/kong"
+)
+
+// completeCommand is the hidden first argument the completion scripts call
+// tdcli with to get candidates for the word being completed
+const completeCommand = "__complete"
+
+// completionScripts are the shell hooks printed by "tdcli completion"
+var completionScripts = map[string]string{
+	"bash": `_tdcli() {
+    local IFS=$'\n'
+    COMPREPLY=($(tdcli __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _tdcli tdcli
+`,
+	"zsh": `autoload -U +X bashcompinit && bashcompinit
+_tdcli() {
+    local IFS=$'\n'
+    COMPREPLY=($(tdcli __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _tdcli tdcli
+`,
+	"fish": `complete -c tdcli -f -a '(tdcli __complete (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null)'
+`,
+}
+
+// CompletionCmd prints a shell completion script
+type CompletionCmd struct {
+	Shell string `kong:"arg,enum='bash,zsh,fish',help='Shell to print the completion script for (bash, zsh, fish)'"`
+}
+
+func (c *CompletionCmd) Run(ctx *CLIContext) error {
+	fmt.Print(completionScripts[c.Shell])
+	return nil
+}
+
+// completeWords returns the subcommands and flags that can follow words. The
+// last word is the one being completed and may be empty.
+func completeWords(app *kong.Kong, words []string) []string {
+	current := ""
+	if len(words) > 0 {
+		current = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+	_, node := resolveCommandPath(app, words)
+
+	var candidates []string
+	if strings.HasPrefix(current, "-") {
+		for n := node; n != nil; n = n.Parent {
+			for _, flag := range n.Flags {
+				if !flag.Hidden {
+					candidates = append(candidates, "--"+flag.Name)
+				}
+			}
+		}
+	} else {
+		for _, child := range node.Children {
+			if child.Type == kong.CommandNode && !child.Hidden {
+				candidates = append(candidates, child.Name)
+			}
+		}
+	}
+
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, current) {
+			matches = append(matches, c)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// printCompletions writes the candidates for words, one per line
+func printCompletions(w io.Writer, app *kong.Kong, words []string) {
+	for _, c := range completeWords(app, words) {
+		fmt.Fprintln(w, c)
+	}
+}
+
+// installCompletion hooks completion into the startup file of shell under
+// home and returns the file it changed. Installing twice is a no-op.
+func installCompletion(shell, home string) (string, error) {
+	var path, line string
+	switch shell {
+	case "bash":
+		path, line = filepath.Join(home, ".bashrc"), "source <(tdcli completion bash)"
+	case "zsh":
+		path, line = filepath.Join(home, ".zshrc"), "source <(tdcli completion zsh)"
+	case "fish":
+		path, line = filepath.Join(home, ".config", "fish", "completions", "tdcli.fish"), "tdcli completion fish | source"
+	default:
+		return "", fmt.Errorf("completion is not available for %q (supported: bash, zsh, fish)", shell)
+	}
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if strings.Contains(string(existing), line) {
+		return path, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		line = "\n" + line
+	}
+	_, err = fmt.Fprintf(f, "%s\n", line)
+	return path, err
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompleteWords(t *testing.T) {
+	tests := []struct {
+		words []string
+		want  []string
+	}{
+		{[]string{"wf", "ta"}, []string{"tasks"}},
+		{[]string{"workflow", "tasks", ""}, []string{"get", "jobs", "list"}},
+		{[]string{"--format", "json", "db", "cr"}, []string{"create"}},
+		{[]string{"db", "list", "--for"}, []string{"--format"}},
+		{[]string{"tele"}, nil},
+	}
+	for _, tt := range tests {
+		if got := completeWords(newTestParser(t), tt.words); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("completeWords(%q) = %q, want %q", tt.words, got, tt.want)
+		}
+	}
+}
+
+func TestInstallCompletion(t *testing.T) {
+	home := t.TempDir()
+	rc := filepath.Join(home, ".zshrc")
+	if err := os.WriteFile(rc, []byte("export EDITOR=vi"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		path, err := installCompletion("zsh", home)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path != rc {
+			t.Errorf("path = %q, want %q", path, rc)
+		}
+	}
+	data, _ := os.ReadFile(rc)
+	if got, want := string(data), "export EDITOR=vi\nsource <(tdcli completion zsh)\n"; got != want {
+		t.Errorf(".zshrc = %q, want %q", got, want)
+	}
+
+	path, err := installCompletion("fish", home)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(path, filepath.Join("fish", "completions", "tdcli.fish")) {
+		t.Errorf("fish path = %q", path)
+	}
+
+	if _, err := installCompletion("tcsh", home); err == nil {
+		t.Error("expected an error for an unsupported shell")
+	}
+}
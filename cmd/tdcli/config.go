@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/BurntSushi/toml"
+	td "github.com/mickeey2525/treasuredata-go-sdk"
 )
 
 // Config represents the CLI configuration
@@ -20,6 +23,113 @@ type Config struct {
 	CertFile           string `toml:"cert_file"`
 	KeyFile            string `toml:"key_file"`
 	CAFile             string `toml:"ca_file"`
+
+	// ImportSchemas maps destination tables (database.table) to JSON Schema
+	// files used to validate records before they are imported
+	ImportSchemas map[string]string `toml:"import_schemas,omitempty"`
+
+	// PriorityPresets adds or overrides the named presets used by
+	// "query submit --preset"
+	PriorityPresets map[string]td.PriorityPreset `toml:"priority_presets,omitempty"`
+
+	// QueryPolicy restricts the queries submitted with this configuration
+	QueryPolicy *QueryPolicyConfig `toml:"query_policy,omitempty"`
+
+	// Telemetry enables anonymous usage telemetry when "on"; it is off by
+	// default. It is only read from the home config.
+	Telemetry string `toml:"telemetry,omitempty"`
+	// TelemetryEndpoint is where spooled telemetry events are sent. Events
+	// stay in the local spool when it is empty. It is only read from the
+	// home config.
+	TelemetryEndpoint string `toml:"telemetry_endpoint,omitempty"`
+
+	// VersionCheck set to "off" stops the hint printed when tdcli is
+	// significantly older than the latest release
+	VersionCheck string `toml:"version_check,omitempty"`
+
+	// AllowedOperations restricts the API requests tdcli may send, as
+	// allowlist presets ("query", "read-only", ...) or rules such as
+	// "GET td:v3/job/show/*". Empty allows everything.
+	AllowedOperations []string `toml:"allowed_operations,omitempty"`
+
+	// allowlists holds the allowed_operations of each merged config file;
+	// requests must be allowed by all of them
+	allowlists [][]string
+
+	// Audit records mutating requests to a signed local log
+	Audit *AuditConfig `toml:"audit,omitempty"`
+
+	// Profiles holds named environments for commands that work with two
+	// accounts, such as "compare --profile-src dev --profile-dst prod"
+	Profiles map[string]ProfileConfig `toml:"profiles,omitempty"`
+}
+
+// ProfileConfig holds the connection settings of a named environment
+type ProfileConfig struct {
+	APIKey             string `toml:"api_key"`
+	Region             string `toml:"region,omitempty"`
+	InsecureSkipVerify bool   `toml:"insecure_skip_verify,omitempty"`
+	CertFile           string `toml:"cert_file,omitempty"`
+	KeyFile            string `toml:"key_file,omitempty"`
+	CAFile             string `toml:"ca_file,omitempty"`
+}
+
+// QueryPolicyConfig is the [query_policy] section of the configuration file
+type QueryPolicyConfig struct {
+	// MaxPriority is the highest priority queries may be submitted with
+	MaxPriority *int `toml:"max_priority,omitempty"`
+	// AllowedPools limits the resource pools queries may name. Nil allows
+	// any pool; an empty list, such as the intersection of two configs with
+	// no pool in common, allows none.
+	AllowedPools []string `toml:"allowed_pools,omitempty"`
+}
+
+// Policies converts the configuration into SDK query policies
+func (p *QueryPolicyConfig) Policies() []td.QueryPolicy {
+	if p == nil {
+		return nil
+	}
+	var policies []td.QueryPolicy
+	if p.MaxPriority != nil {
+		policies = append(policies, td.MaxPriorityPolicy(*p.MaxPriority))
+	}
+	if p.AllowedPools != nil {
+		policies = append(policies, td.AllowedPoolsPolicy(p.AllowedPools...))
+	}
+	return policies
+}
+
+// PriorityPreset returns the named preset from the built-in presets merged
+// with those in the configuration file
+func (c *Config) PriorityPreset(name string) (td.PriorityPreset, error) {
+	presets := td.DefaultPriorityPresets()
+	for presetName, preset := range c.PriorityPresets {
+		presets[presetName] = preset
+	}
+
+	preset, ok := presets[name]
+	if !ok {
+		names := make([]string, 0, len(presets))
+		for presetName := range presets {
+			names = append(names, presetName)
+		}
+		sort.Strings(names)
+		return td.PriorityPreset{}, fmt.Errorf("unknown priority preset %q (available: %s)", name, strings.Join(names, ", "))
+	}
+	return preset, nil
+}
+
+// OperationAllowlists returns the allowlists requests must all pass: one per
+// config file that sets allowed_operations, plus flag when it is not empty
+func (c *Config) OperationAllowlists(flag []string) [][]string {
+	lists := append([][]string{}, c.allowlists...)
+	if len(lists) == 0 && len(c.AllowedOperations) > 0 {
+		lists = append(lists, c.AllowedOperations)
+	}
+	if len(flag) > 0 {
+		lists = append(lists, flag)
+	}
+	return lists
 }
 
 // DefaultConfig returns a config with default values
@@ -40,8 +150,11 @@ func LoadConfig() (*Config, error) {
 		mergeConfig(config, homeConfig)
 	}
 
-	// Try to load from current directory (higher priority)
+	// Try to load from current directory (higher priority). Telemetry is
+	// only read from the home config or the environment, so a checked-out
+	// project cannot opt the user in or redirect their events.
 	if localConfig, err := loadConfigFromCurrentDir(); err == nil {
+		localConfig.Telemetry, localConfig.TelemetryEndpoint = "", ""
 		mergeConfig(config, localConfig)
 	}
 
@@ -111,6 +224,88 @@ func mergeConfig(target, source *Config) {
 	if source.CAFile != "" {
 		target.CAFile = source.CAFile
 	}
+	if source.Telemetry != "" {
+		target.Telemetry = source.Telemetry
+	}
+	if source.TelemetryEndpoint != "" {
+		target.TelemetryEndpoint = source.TelemetryEndpoint
+	}
+	if source.VersionCheck != "" {
+		target.VersionCheck = source.VersionCheck
+	}
+	for name, preset := range source.PriorityPresets {
+		if target.PriorityPresets == nil {
+			target.PriorityPresets = map[string]td.PriorityPreset{}
+		}
+		target.PriorityPresets[name] = preset
+	}
+	// Policies only tighten: a project config cannot raise the home config's
+	// maximum priority or allow a pool it does not
+	if source.QueryPolicy != nil {
+		if target.QueryPolicy == nil {
+			target.QueryPolicy = &QueryPolicyConfig{}
+		}
+		if max := source.QueryPolicy.MaxPriority; max != nil {
+			if target.QueryPolicy.MaxPriority == nil || *max < *target.QueryPolicy.MaxPriority {
+				target.QueryPolicy.MaxPriority = max
+			}
+		}
+		if pools := source.QueryPolicy.AllowedPools; pools != nil {
+			target.QueryPolicy.AllowedPools = intersectPools(target.QueryPolicy.AllowedPools, pools)
+		}
+	}
+	// Allowlists only tighten too: each file's list is applied on top of the
+	// others, so a project config cannot allow what the home config refuses
+	if len(source.AllowedOperations) > 0 {
+		if len(target.AllowedOperations) == 0 {
+			target.AllowedOperations = source.AllowedOperations
+		}
+		target.allowlists = append(target.allowlists, source.AllowedOperations)
+	}
+	// Like policies, a project config can enable the audit trail but not
+	// disable it
+	if source.Audit != nil {
+		if target.Audit == nil {
+			target.Audit = &AuditConfig{}
+		}
+		target.Audit.Enabled = target.Audit.Enabled || source.Audit.Enabled
+		if source.Audit.File != "" {
+			target.Audit.File = source.Audit.File
+		}
+		if source.Audit.KeyFile != "" {
+			target.Audit.KeyFile = source.Audit.KeyFile
+		}
+		if source.Audit.Table != "" {
+			target.Audit.Table = source.Audit.Table
+		}
+	}
+	for name, profile := range source.Profiles {
+		if target.Profiles == nil {
+			target.Profiles = map[string]ProfileConfig{}
+		}
+		target.Profiles[name] = profile
+	}
+	for table, schema := range source.ImportSchemas {
+		if target.ImportSchemas == nil {
+			target.ImportSchemas = map[string]string{}
+		}
+		target.ImportSchemas[table] = schema
+	}
+}
+
+// intersectPools returns the pools of current that next also allows; a nil
+// current allows every pool, so next is taken as is
+func intersectPools(current, next []string) []string {
+	if current == nil {
+		return append([]string{}, next...)
+	}
+	pools := []string{}
+	for _, pool := range current {
+		if slices.Contains(next, pool) {
+			pools = append(pools, pool)
+		}
+	}
+	return pools
 }
 
 // SaveConfig saves configuration to the specified path
@@ -172,6 +367,40 @@ func (c *ConfigShowCmd) Run(ctx *CLIContext) error {
 	fmt.Printf("Client Cert: %s\n", config.CertFile)
 	fmt.Printf("Client Key: %s\n", config.KeyFile)
 	fmt.Printf("CA File: %s\n", config.CAFile)
+	if telemetryEnabled(config) {
+		fmt.Println("Telemetry: on")
+	} else {
+		fmt.Println("Telemetry: off")
+	}
+	if config.VersionCheck == "off" {
+		fmt.Println("Version Check: off")
+	} else {
+		fmt.Println("Version Check: on")
+	}
+	if config.Audit != nil && config.Audit.Enabled {
+		fmt.Println("Audit Trail: on")
+	} else {
+		fmt.Println("Audit Trail: off")
+	}
+	if len(config.Profiles) > 0 {
+		names := make([]string, 0, len(config.Profiles))
+		for name := range config.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Println("Profiles:")
+		for _, name := range names {
+			profile := config.Profiles[name]
+			fmt.Printf("  %s: %s (%s)\n", name, maskAPIKey(profile.APIKey), profile.Region)
+		}
+	}
+
+	if ws := ctx.Workspace; ws != nil {
+		fmt.Printf("\nWorkspace (%s):\n", ws.Path)
+		fmt.Printf("  Profile: %s\n", ws.Profile)
+		fmt.Printf("  Database: %s\n", ws.Database)
+		fmt.Printf("  Workflow Project: %s\n", ws.WorkflowProject)
+	}
 
 	fmt.Println("\nConfiguration file locations (in priority order):")
 	for i, path := range GetConfigPaths() {
@@ -187,7 +416,7 @@ func (c *ConfigShowCmd) Run(ctx *CLIContext) error {
 
 // ConfigSetCmd sets a configuration value
 type ConfigSetCmd struct {
-	Key    string `kong:"arg,help='Configuration key (api_key, region, format, output, insecure_skip_verify, cert_file, key_file, ca_file)'"`
+	Key    string `kong:"arg,help='Configuration key (api_key, region, format, output, insecure_skip_verify, cert_file, key_file, ca_file, telemetry, telemetry_endpoint, version_check)'"`
 	Value  string `kong:"arg,help='Configuration value'"`
 	Global bool   `kong:"help='Save to global config (~/.tdcli/.tdcli.toml)'"`
 }
@@ -199,6 +428,10 @@ func (c *ConfigSetCmd) Run(ctx *CLIContext) error {
 		config = DefaultConfig()
 	}
 
+	if (c.Key == "telemetry" || c.Key == "telemetry_endpoint") && !c.Global {
+		return fmt.Errorf("%s is only read from the home config; use --global", c.Key)
+	}
+
 	// Set the value
 	switch c.Key {
 	case "api_key":
@@ -263,6 +496,25 @@ func (c *ConfigSetCmd) Run(ctx *CLIContext) error {
 			}
 		}
 		config.CAFile = c.Value
+	case "telemetry":
+		switch c.Value {
+		case "on", "off":
+		default:
+			return fmt.Errorf("invalid telemetry setting: %s. Use on or off", c.Value)
+		}
+		config.Telemetry = c.Value
+	case "telemetry_endpoint":
+		if c.Value != "" && !strings.HasPrefix(c.Value, "https://") {
+			return fmt.Errorf("telemetry endpoint must be an https URL")
+		}
+		config.TelemetryEndpoint = c.Value
+	case "version_check":
+		switch c.Value {
+		case "on", "off":
+		default:
+			return fmt.Errorf("invalid version_check setting: %s. Use on or off", c.Value)
+		}
+		config.VersionCheck = c.Value
 	default:
 		return fmt.Errorf("unknown configuration key: %s", c.Key)
 	}
@@ -292,7 +544,7 @@ func (c *ConfigSetCmd) Run(ctx *CLIContext) error {
 
 // ConfigGetCmd gets a configuration value
 type ConfigGetCmd struct {
-	Key string `kong:"arg,help='Configuration key (api_key, region, format, output, insecure_skip_verify, cert_file, key_file, ca_file)'"`
+	Key string `kong:"arg,help='Configuration key (api_key, region, format, output, insecure_skip_verify, cert_file, key_file, ca_file, telemetry, telemetry_endpoint, version_check)'"`
 }
 
 func (c *ConfigGetCmd) Run(ctx *CLIContext) error {
@@ -323,6 +575,12 @@ func (c *ConfigGetCmd) Run(ctx *CLIContext) error {
 		value = config.KeyFile
 	case "ca_file":
 		value = config.CAFile
+	case "telemetry":
+		value = config.Telemetry
+	case "telemetry_endpoint":
+		value = config.TelemetryEndpoint
+	case "version_check":
+		value = config.VersionCheck
 	default:
 		return fmt.Errorf("unknown configuration key: %s", c.Key)
 	}
@@ -336,6 +594,21 @@ func (c *ConfigGetCmd) Run(ctx *CLIContext) error {
 	return nil
 }
 
+// regionDescriptions describe the regions offered by the setup prompts
+var regionDescriptions = map[string]string{
+	"us":    "United States (api.treasuredata.com)",
+	"eu":    "Europe (api.eu01.treasuredata.com)",
+	"tokyo": "Japan (api.treasuredata.co.jp)",
+	"ap02":  "Asia Pacific (api.ap02.treasuredata.com)",
+}
+
+// formatDescriptions describe the output formats offered by the setup prompts
+var formatDescriptions = map[string]string{
+	"table": "Human-readable table format",
+	"json":  "JSON format for programmatic use",
+	"csv":   "CSV format for spreadsheet import",
+}
+
 // ConfigInitCmd initializes a configuration file
 type ConfigInitCmd struct {
 	Global bool `kong:"help='Create global config (~/.tdcli/.tdcli.toml)'"`
@@ -377,23 +650,14 @@ func (c *ConfigInitCmd) Run(ctx *CLIContext) error {
 	config.APIKey = apiKey
 
 	// Prompt for Region
-	region, err := promptChoice("Region", []string{"us", "eu", "tokyo", "ap02"}, "us", map[string]string{
-		"us":    "United States (api.treasuredata.com)",
-		"eu":    "Europe (api.eu01.treasuredata.com)",
-		"tokyo": "Japan (api.treasuredata.co.jp)",
-		"ap02":  "Asia Pacific (api.ap02.treasuredata.com)",
-	})
+	region, err := promptChoice("Region", []string{"us", "eu", "tokyo", "ap02"}, "us", regionDescriptions)
 	if err != nil {
 		return err
 	}
 	config.Region = region
 
 	// Prompt for Format
-	format, err := promptChoice("Output Format", []string{"table", "json", "csv"}, "table", map[string]string{
-		"table": "Human-readable table format",
-		"json":  "JSON format for programmatic use",
-		"csv":   "CSV format for spreadsheet import",
-	})
+	format, err := promptChoice("Output Format", []string{"table", "json", "csv"}, "table", formatDescriptions)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+func TestMergeConfig_QueryPolicy(t *testing.T) {
+	one, two := 1, 2
+
+	target := DefaultConfig()
+	mergeConfig(target, &Config{QueryPolicy: &QueryPolicyConfig{MaxPriority: &one}})
+	mergeConfig(target, &Config{QueryPolicy: &QueryPolicyConfig{MaxPriority: &two, AllowedPools: []string{"batch"}}})
+
+	if got := *target.QueryPolicy.MaxPriority; got != 1 {
+		t.Errorf("MaxPriority = %d, want the stricter 1", got)
+	}
+	if len(target.QueryPolicy.AllowedPools) != 1 {
+		t.Errorf("AllowedPools = %v, want [batch]", target.QueryPolicy.AllowedPools)
+	}
+	if got := len(target.QueryPolicy.Policies()); got != 2 {
+		t.Errorf("Policies = %d, want 2", got)
+	}
+}
+
+func TestMergeConfig_AllowedPools(t *testing.T) {
+	target := DefaultConfig()
+	mergeConfig(target, &Config{QueryPolicy: &QueryPolicyConfig{AllowedPools: []string{"batch", "adhoc"}}})
+	mergeConfig(target, &Config{QueryPolicy: &QueryPolicyConfig{AllowedPools: []string{"adhoc", "etl"}}})
+	if got := target.QueryPolicy.AllowedPools; len(got) != 1 || got[0] != "adhoc" {
+		t.Errorf("AllowedPools = %v, want [adhoc]", got)
+	}
+
+	// A project config with no pool in common allows none rather than all
+	mergeConfig(target, &Config{QueryPolicy: &QueryPolicyConfig{AllowedPools: []string{"etl"}}})
+	if got := target.QueryPolicy.AllowedPools; got == nil || len(got) != 0 {
+		t.Errorf("AllowedPools = %#v, want an empty list", got)
+	}
+	if got := len(target.QueryPolicy.Policies()); got != 1 {
+		t.Errorf("Policies = %d, want the pools policy", got)
+	}
+}
+
+func TestConfig_OperationAllowlists(t *testing.T) {
+	target := DefaultConfig()
+	mergeConfig(target, &Config{AllowedOperations: []string{"query"}})
+	mergeConfig(target, &Config{AllowedOperations: []string{"read-only", "import"}})
+
+	lists := target.OperationAllowlists([]string{"GET td:v3/job/**"})
+	if len(lists) != 3 || lists[0][0] != "query" || lists[1][0] != "read-only" || lists[2][0] != "GET td:v3/job/**" {
+		t.Errorf("OperationAllowlists = %v, want the home, project and flag lists", lists)
+	}
+	if lists := DefaultConfig().OperationAllowlists(nil); len(lists) != 0 {
+		t.Errorf("OperationAllowlists without any = %v, want none", lists)
+	}
+}
+
+func TestConfig_PriorityPreset(t *testing.T) {
+	config := &Config{}
+	config.PriorityPresets = map[string]td.PriorityPreset{"batch": {Priority: -2, PoolName: "etl"}}
+
+	preset, err := config.PriorityPreset("batch")
+	if err != nil || preset.Priority != -2 || preset.PoolName != "etl" {
+		t.Errorf("PriorityPreset(batch) = %+v, %v, want override from config", preset, err)
+	}
+	if preset, err := config.PriorityPreset("interactive"); err != nil || preset.Priority != 1 {
+		t.Errorf("PriorityPreset(interactive) = %+v, %v, want built-in", preset, err)
+	}
+	if _, err := config.PriorityPreset("urgent"); err == nil {
+		t.Error("Expected error for unknown preset")
+	}
+}
+
+func TestLoadConfig_TelemetryOnlyFromHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	project := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	local := `telemetry = "on"
+telemetry_endpoint = "https://attacker.example.com/events"
+format = "json"
+`
+	if err := os.WriteFile(filepath.Join(project, "tdcli.toml"), []byte(local), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Telemetry != "" || config.TelemetryEndpoint != "" || config.Format != "json" {
+		t.Errorf("project config gave telemetry %q endpoint %q format %q, want only the format", config.Telemetry, config.TelemetryEndpoint, config.Format)
+	}
+
+	homeConfig := `telemetry = "on"
+telemetry_endpoint = "https://telemetry.example.com/events"
+`
+	if err := os.MkdirAll(filepath.Join(home, ".tdcli"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".tdcli", ".tdcli.toml"), []byte(homeConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err = LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Telemetry != "on" || config.TelemetryEndpoint != "https://telemetry.example.com/events" {
+		t.Errorf("home config gave telemetry %q endpoint %q", config.Telemetry, config.TelemetryEndpoint)
+	}
+
+	t.Setenv("TDCLI_TELEMETRY_ENDPOINT", "https://other.example.com/events")
+	if got := telemetryEndpoint(config); got != "https://other.example.com/events" {
+		t.Errorf("telemetryEndpoint = %q, want the environment override", got)
+	}
+}
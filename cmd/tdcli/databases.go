@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 
@@ -233,3 +234,44 @@ func handleDatabaseUpdate(ctx context.Context, client *td.Client, args []string,
 	fmt.Printf("Database update functionality would be implemented here for: %s\n", database.Name)
 	fmt.Println("Note: Check Treasure Data API documentation for updateable database properties")
 }
+
+// resolvePolicyID accepts a policy ID or a policy name
+func resolvePolicyID(ctx context.Context, client *td.Client, policy string) (int, error) {
+	if id, err := strconv.Atoi(policy); err == nil {
+		return id, nil
+	}
+	policies, err := client.Permissions.ListPolicies(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list policies: %v", err)
+	}
+	for _, p := range policies {
+		if p.Name == policy {
+			return p.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("policy %q not found", policy)
+}
+
+func handleDatabaseProvision(ctx context.Context, client *td.Client, name string, policies, operations []string) error {
+	var grants []td.DatabaseGrant
+	for _, policy := range policies {
+		policyID, err := resolvePolicyID(ctx, client, policy)
+		if err != nil {
+			return err
+		}
+		for _, operation := range operations {
+			grants = append(grants, td.DatabaseGrant{PolicyID: policyID, Operation: operation})
+		}
+	}
+
+	database, err := client.Databases.Provision(ctx, name, grants)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created database: %s\n", database.Name)
+	for _, grant := range grants {
+		fmt.Printf("Granted %s to policy %d\n", grant.Operation, grant.PolicyID)
+	}
+	return nil
+}
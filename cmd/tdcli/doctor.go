@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// DoctorCmd checks that tdcli can reach and authenticate with each service
+type DoctorCmd struct {
+	Timeout time.Duration `kong:"help='Give up on a service after this long',default='10s'"`
+}
+
+func (d *DoctorCmd) Run(ctx *CLIContext) error {
+	checkCtx, cancel := context.WithTimeout(ctx.Context, d.Timeout)
+	defer cancel()
+	return handleDoctor(checkCtx, ctx.Client, ctx.GlobalFlags)
+}
+
+func handleDoctor(ctx context.Context, client *td.Client, flags Flags) error {
+	report, err := client.HealthCheck(ctx)
+	if err != nil && report == nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+
+	csvFormatter := func(data interface{}) string {
+		r := data.(*td.HealthReport)
+		var csvBuilder strings.Builder
+		for _, s := range r.Services {
+			csvBuilder.WriteString(fmt.Sprintf("%s,%s,%t,%t,%d,%d,%q\n",
+				s.Service,
+				s.URL,
+				s.Reachable,
+				s.Healthy,
+				s.StatusCode,
+				s.Latency.Milliseconds(),
+				s.Error,
+			))
+		}
+		return csvBuilder.String()
+	}
+
+	tableFormatter := func(data interface{}) string {
+		r := data.(*td.HealthReport)
+		var tableBuilder strings.Builder
+		w := tabwriter.NewWriter(&tableBuilder, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SERVICE\tSTATUS\tLATENCY\tURL\tDETAIL")
+		for _, s := range r.Services {
+			status := "ok"
+			switch {
+			case !s.Reachable:
+				status = "unreachable"
+			case !s.Healthy:
+				status = "error"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				s.Service,
+				status,
+				s.Latency.Round(time.Millisecond),
+				s.URL,
+				s.Error,
+			)
+		}
+		w.Flush()
+		if r.Healthy() {
+			tableBuilder.WriteString("\nAll services are healthy\n")
+		}
+		return tableBuilder.String()
+	}
+
+	if err := formatAndWriteOutput(report, flags.Format, flags.Output, "service,url,reachable,healthy,status_code,latency_ms,error", csvFormatter, tableFormatter); err != nil {
+		return err
+	}
+	if !report.Healthy() {
+		return fmt.Errorf("one or more services are unhealthy")
+	}
+	return nil
+}
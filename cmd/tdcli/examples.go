@@ -0,0 +1,327 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/kong"
+)
+
+// commandExample is a runnable command line shown by --examples
+type commandExample struct {
+	Description string
+	Command     string
+}
+
+// commandExamples maps canonical command paths (command names without
+// aliases, e.g. "queries submit") to curated examples. Tests check that every
+// path exists and that every example parses.
+var commandExamples = map[string][]commandExample{
+	"databases list": {
+		{"List databases as CSV", "tdcli db list --format csv"},
+	},
+	"databases create": {
+		{"Create a database", "tdcli db create analytics"},
+	},
+	"tables list": {
+		{"List the tables of a database", "tdcli table list sample_datasets"},
+	},
+	"tables get": {
+		{"Show a table's schema and row count", "tdcli table show sample_datasets www_access"},
+	},
+	"tables swap": {
+		{"Swap a freshly built table into place", "tdcli table swap analytics daily_sales daily_sales_tmp"},
+	},
+	"queries submit": {
+		{"Run a Trino query and wait for it to finish", `tdcli query submit --database sample_datasets --wait "SELECT COUNT(1) FROM www_access"`},
+		{"Run a Hive query at low priority", `tdcli query submit --database sample_datasets --engine hive --preset backfill "SELECT method, COUNT(1) FROM www_access GROUP BY method"`},
+		{"Try a Hive query on the experimental engine in a dedicated pool", `tdcli query submit --database sample_datasets --engine hive --engine-version experimental --pool-name hive_batch "SELECT COUNT(1) FROM www_access"`},
+		{"Submit at most once per day, even when a scheduler retries", `tdcli query submit --database sample_datasets --domain-key daily-report-2025-01-01 "SELECT COUNT(1) FROM www_access"`},
+		{"Fill in template variables", `tdcli query submit --database sample_datasets --var method=GET "SELECT COUNT(1) FROM www_access WHERE method = {{tdString .method}}"`},
+		{"Write the results to another table", `tdcli query submit --database sample_datasets --result-url "td://@/analytics/access_counts?mode=append" "SELECT method, COUNT(1) AS n FROM www_access GROUP BY method"`},
+	},
+	"queries estimate": {
+		{"Check how much a query would scan before running it", `tdcli query estimate --database sample_datasets "SELECT * FROM www_access WHERE TD_TIME_RANGE(time, '2014-10-01', '2014-10-02')"`},
+		{"Fail a CI check on full-table scans", `tdcli query estimate --database sample_datasets --fail-on-full-scan --format json "SELECT * FROM www_access"`},
+	},
+	"queries result": {
+		{"Download a job's results as CSV", "tdcli query result 12345 --format csv --output results.csv"},
+		{"Stream every row as JSON lines into another tool", "tdcli query result 12345 --format json --limit 0"},
+		{"Print CSV results under a header of column names and types", "tdcli query result 12345 --format csv --types"},
+		{"Download compressed MessagePack results as served", "tdcli query result 12345 --result-format msgpack.gz --output results.msgpack.gz"},
+		{"Continue an interrupted download of a large result", "tdcli query result 12345 --result-format csv --output results.csv --resume"},
+		{"Fetch a very large result over 8 connections", "tdcli query result 12345 --result-format msgpack.gz --output results.msgpack.gz --parallel 8"},
+	},
+	"results list": {
+		{"List saved result connections with credentials masked", "tdcli results list"},
+	},
+	"results create": {
+		{"Save a TD table as a result connection", "tdcli results create daily_summary td://@/reports/daily?mode=replace"},
+		{"Save an S3 destination without putting the key in shell history", "tdcli results create my_s3 --url-env S3_RESULT_URL"},
+	},
+	"results test": {
+		{"Fail a CI job when a result connection is misconfigured", "tdcli results test daily_summary"},
+	},
+	"results delete": {
+		{"Delete a result connection without prompting", "tdcli results delete my_s3 --force"},
+	},
+	"pools list": {
+		{"List the pools named by presets and allowed_pools", "tdcli pools list"},
+		{"Include the pools of CDP parent segments", "tdcli pools list --cdp --format json"},
+	},
+	"jobs list": {
+		{"List running jobs", "tdcli jobs list --status running"},
+		{"List every job against a database in the last day", "tdcli jobs list --database sales --from 1d --limit 0"},
+	},
+	"jobs get": {
+		{"Show a job's status, query and timings", "tdcli jobs show 12345"},
+	},
+	"jobs logs": {
+		{"See why a query failed", "tdcli jobs logs 12345 --stderr"},
+	},
+	"jobs cancel": {
+		{"Kill a runaway job", "tdcli jobs kill 12345"},
+	},
+	"jobs queue": {
+		{"Watch queued and running jobs", "tdcli jobs queue --watch --interval 10s"},
+	},
+	"users activity": {
+		{"Find users without jobs in the last 90 days", "tdcli users activity --since 90d"},
+	},
+	"account cores": {
+		{"Show core utilization over the last week", "tdcli account cores --from 7d"},
+	},
+	"account storage": {
+		{"Show which databases use the most storage", "tdcli account storage"},
+	},
+	"schedules list": {
+		{"List scheduled queries and when they run next", "tdcli schedules list"},
+	},
+	"schedules create": {
+		{"Run a Trino query every day at midnight Tokyo time", "tdcli schedules create daily_report '0 0 * * *' 'SELECT COUNT(1) FROM events' --database analytics --timezone Asia/Tokyo"},
+	},
+	"schedules run": {
+		{"Re-run the three scheduled times starting October 1st", "tdcli schedules run daily_report 2026-10-01 --num 3"},
+	},
+	"schedules backfill": {
+		{"List the runs a backfill of September would start", "tdcli schedules backfill daily_report 2026-09-01 2026-10-01 --preview"},
+		{"Backfill the last week, two runs at a time", "tdcli schedules backfill daily_report 7d --concurrency 2 --force"},
+	},
+	"schedules history": {
+		{"Show the last 10 runs of a schedule", "tdcli schedules history daily_report --limit 10"},
+	},
+	"perms policies list": {
+		{"List access control policies", "tdcli perms policies list"},
+	},
+	"import upload-dir": {
+		{"Upload a directory of JSONL files, 8 at a time", `tdcli import upload-dir events_20240101 ./export --pattern "*.jsonl" --parallel 8`},
+	},
+	"import perform": {
+		{"Run the import job for a committed session", "tdcli import perform events_20240101"},
+	},
+	"cdp audiences list": {
+		{"List parent segments (audiences)", "tdcli cdp audiences list"},
+	},
+	"cdp audiences sample-values": {
+		{"See typical values of an attribute", "tdcli cdp audiences samples 123 country"},
+	},
+	"cdp audiences attribute-usage": {
+		{"Find attributes no segment uses", "tdcli cdp audiences attribute-usage 123 --unused"},
+	},
+	"cdp segments list": {
+		{"List the segments of an audience", "tdcli cdp segments list 123"},
+	},
+	"cdp segments rm-bulk": {
+		{"List the throwaway segments a cleanup would delete", "tdcli cdp segments rm-bulk 123 --prefix tmp_ --dry-run"},
+		{"Delete them without prompting, 8 at a time", "tdcli cdp segments rm-bulk 123 --prefix tmp_ --force --concurrency 8"},
+	},
+	"cdp segments rename": {
+		{"See what refers to a segment before renaming it", "tdcli cdp segments rename 123 456 \"Buyers 2025\" --dry-run"},
+		{"Rename it and the funnel stages named after it", "tdcli cdp segments rename 123 456 \"Buyers 2025\" --cascade"},
+	},
+	"cdp folders rename": {
+		{"Rename a segment folder", "tdcli cdp folders rename 123 789 Campaigns"},
+	},
+	"cdp activations execution": {
+		{"Show how many records a run exported and skipped", "tdcli cdp activations execution 123 456 789 1011"},
+	},
+	"cdp activations history": {
+		{"Export a month of activation runs for SLA reporting", "tdcli cdp activations history 123 --since 30d --format csv --output runs.csv"},
+	},
+	"cdp activations retry-failed": {
+		{"List activations that failed in the last day", "tdcli cdp activations retry-failed 123 --since 24h --preview"},
+		{"Re-run them after an outage without prompting", "tdcli cdp activations retry-failed 123 --since 24h --force"},
+	},
+	"cdp tokens check": {
+		{"Verify a profiles API token against a known profile", "tdcli cdp tokens check $PROFILES_TOKEN --key td_client_id=abc123"},
+	},
+	"cdp behaviors query": {
+		{"Preview a week of behavior events", "tdcli cdp behaviors query 123 purchases --since 7d --limit 20"},
+		{"Print the generated query only", "tdcli cdp behaviors query 123 purchases --since 7d --dry-run"},
+	},
+	"compare databases": {
+		{"Compare databases and table schemas between profiles", "tdcli compare databases --profile-src staging --profile-dst production --tables"},
+	},
+	"workflow list": {
+		{"List the scheduled workflows of a project that are in error", "tdcli wf ls --project daily_etl --scheduled-only --status error"},
+	},
+	"workflow start": {
+		{"Start a workflow with parameters", `tdcli wf start 4567 --params '{"target_date": "2024-01-01"}'`},
+	},
+	"workflow attempts list": {
+		{"List the attempts of a workflow", "tdcli wf attempts list 4567"},
+	},
+	"workflow rename": {
+		{"List the workflows that call a workflow before renaming it", "tdcli wf rename 4567 load_orders --dry-run"},
+	},
+	"workflow schedule pause": {
+		{"Skip a workflow's runs during two hours of maintenance", "tdcli wf schedule pause 4567 --for 2h"},
+	},
+	"workflow tasks jobs": {
+		{"Show the TD jobs an attempt ran", "tdcli wf tasks jobs 4567 890"},
+	},
+	"workflow projects push": {
+		{"Upload a project directory", "tdcli wf projects push my_project ./my_project"},
+	},
+	"workflow projects restore": {
+		{"Bring back a deleted project from its last revision", "tdcli wf projects restore 1234"},
+	},
+	"workflow logs task": {
+		{"Read the log of a failed task", "tdcli wf logs task 4567 890 12"},
+	},
+	"trino query": {
+		{"Run an interactive Trino query", `tdcli trino query "SELECT * FROM www_access" --database sample_datasets --limit 10`},
+	},
+	"trino interactive": {
+		{"Open a Trino SQL shell", "tdcli trino interactive --database sample_datasets"},
+	},
+	"validate name": {
+		{"Check a table name before creating it", "tdcli validate name table daily-sales"},
+	},
+	"doctor": {
+		{"Check connectivity and authentication", "tdcli doctor --timeout 5s"},
+	},
+	"smoke": {
+		{"Run the end-to-end scenario against the default test database", "tdcli smoke"},
+		{"Keep the test table for inspection", "tdcli smoke --database qa_smoke --keep"},
+	},
+	"init": {
+		{"Set up a second account as a named profile", "tdcli init --profile staging"},
+	},
+	"completion": {
+		{"Print the zsh completion script", "tdcli completion zsh"},
+	},
+	"databases provision": {
+		{"Create a database queryable by the analysts policy", "tdcli db provision sales --grant-policy analysts"},
+		{"Grant several operations to two policies", "tdcli db provision sales --grant-policy 12 --grant-policy etl --operation query,import"},
+	},
+	"tables apply": {
+		{"Preview the changes a manifest makes", "tdcli tables apply schema.yaml --preview"},
+		{"Provision tables without prompting", "tdcli tables apply schema.yaml --force"},
+	},
+	"audit verify": {
+		{"Check the audit log has not been modified", "tdcli audit verify"},
+	},
+}
+
+// wantsExamples reports whether args ask for examples instead of running
+func wantsExamples(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		if arg == "--examples" {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveCommandPath returns the canonical path of the command named in
+// args, e.g. "databases list" for "db ls --format csv", and the deepest node
+// reached. Flags and their values are skipped and arguments end the path.
+func resolveCommandPath(app *kong.Kong, args []string) (string, *kong.Node) {
+	node := app.Model.Node
+	var path []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "-") {
+			if flag := findFlag(node, arg); flag != nil && !flag.IsBool() && !strings.Contains(arg, "=") {
+				i++
+			}
+			continue
+		}
+
+		child := findChild(node, arg)
+		if child == nil {
+			break
+		}
+		node = child
+		path = append(path, child.Name)
+	}
+	return strings.Join(path, " "), node
+}
+
+// findChild returns the subcommand of node named or aliased name
+func findChild(node *kong.Node, name string) *kong.Node {
+	for _, child := range node.Children {
+		if child.Type != kong.CommandNode {
+			continue
+		}
+		if child.Name == name {
+			return child
+		}
+		for _, alias := range child.Aliases {
+			if alias == name {
+				return child
+			}
+		}
+	}
+	return nil
+}
+
+// findFlag returns the flag arg refers to, looking at node and its parents
+func findFlag(node *kong.Node, arg string) *kong.Flag {
+	name, _, _ := strings.Cut(arg, "=")
+	for n := node; n != nil; n = n.Parent {
+		for _, flag := range n.Flags {
+			if name == "--"+flag.Name || (flag.Short != 0 && name == "-"+string(flag.Short)) {
+				return flag
+			}
+		}
+	}
+	return nil
+}
+
+// printExamples writes the examples of the command named in args, or of all
+// commands below it when it is a group
+func printExamples(w io.Writer, app *kong.Kong, args []string) error {
+	path, _ := resolveCommandPath(app, args)
+
+	var paths []string
+	for p := range commandExamples {
+		if path == "" || p == path || strings.HasPrefix(p, path+" ") {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no examples for \"tdcli %s\"; see \"tdcli %s --help\"", path, path)
+	}
+	sort.Strings(paths)
+
+	for i, p := range paths {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "tdcli %s:\n", p)
+		for _, ex := range commandExamples[p] {
+			fmt.Fprintf(w, "\n  # %s\n  %s\n", ex.Description, ex.Command)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/kong"
+)
+
+func newTestParser(t *testing.T) *kong.Kong {
+	t.Helper()
+	var cli CLI
+	app, err := kong.New(&cli, kong.Name("tdcli"), kong.Vars{"version": "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+// splitCommandLine splits a shell command line, honoring single and double
+// quotes
+func splitCommandLine(line string) []string {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg := false
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote, inArg = r, true
+		case r == ' ':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args
+}
+
+func TestCommandExamplesParse(t *testing.T) {
+	for path, examples := range commandExamples {
+		if got, _ := resolveCommandPath(newTestParser(t), strings.Fields(path)); got != path {
+			t.Errorf("examples registered for unknown command %q", path)
+			continue
+		}
+
+		for _, ex := range examples {
+			args := splitCommandLine(ex.Command)
+			if len(args) == 0 || args[0] != "tdcli" {
+				t.Errorf("%q: example does not start with tdcli", ex.Command)
+				continue
+			}
+			if got, _ := resolveCommandPath(newTestParser(t), args[1:]); got != path {
+				t.Errorf("%q runs %q, but is registered for %q", ex.Command, got, path)
+			}
+			if _, err := newTestParser(t).Parse(args[1:]); err != nil {
+				t.Errorf("%q does not parse: %v", ex.Command, err)
+			}
+			if ex.Description == "" {
+				t.Errorf("%q has no description", ex.Command)
+			}
+		}
+	}
+}
+
+func TestResolveCommandPath(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"db", "ls"}, "databases list"},
+		{[]string{"--format", "table", "db", "ls", "--examples"}, "databases list"},
+		{[]string{"--region=eu", "-v", "q", "submit", "SELECT 1"}, "queries submit"},
+		{[]string{"wf", "tasks", "jobs", "1", "2"}, "workflow tasks jobs"},
+		{[]string{"--examples"}, ""},
+	}
+	for _, tt := range tests {
+		if got, _ := resolveCommandPath(newTestParser(t), tt.args); got != tt.want {
+			t.Errorf("resolveCommandPath(%v) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestPrintExamples(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printExamples(&buf, newTestParser(t), []string{"jobs", "--examples"}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"tdcli jobs list:", "tdcli jobs queue:", "# Kill a runaway job", "tdcli jobs kill 12345"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "tdcli queries") {
+		t.Errorf("output includes other groups:\n%s", out)
+	}
+
+	if err := printExamples(&buf, newTestParser(t), []string{"db", "delete", "--examples"}); err == nil {
+		t.Error("expected an error for a command without examples")
+	}
+}
+
+func TestWantsExamples(t *testing.T) {
+	if !wantsExamples([]string{"db", "list", "--examples"}) {
+		t.Error("expected --examples to be detected")
+	}
+	if wantsExamples([]string{"trino", "query", "--", "--examples"}) {
+		t.Error("--examples after -- is an argument")
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/kong"
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+const (
+	// featureCheckInterval is how long probed feature availability is reused
+	featureCheckInterval = 24 * time.Hour
+	// featureCheckTimeout bounds the probes run before printing help
+	featureCheckTimeout = 3 * time.Second
+)
+
+// featureCommands maps the optional services to their command groups
+var featureCommands = map[td.ServiceKind]string{
+	td.ServiceCDP:      "cdp",
+	td.ServiceWorkflow: "workflow",
+}
+
+// featureState is persisted between runs in ~/.tdcli/features.json
+type featureState struct {
+	// Key is a hash of the API key and region the probes ran with
+	Key       string    `json:"key"`
+	CheckedAt time.Time `json:"checked_at"`
+	Disabled  []string  `json:"disabled,omitempty"`
+}
+
+// helpRequested reports whether args print help rather than run a command
+func helpRequested(args []string) bool {
+	if len(args) == 0 {
+		return true
+	}
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		if arg == "-h" || arg == "--help" {
+			return true
+		}
+	}
+	return false
+}
+
+// argValue returns the value of a long flag in args, in either the
+// "--flag value" or "--flag=value" form
+func argValue(args []string, flag string) string {
+	for i, arg := range args {
+		if value, ok := strings.CutPrefix(arg, flag+"="); ok {
+			return value
+		}
+		if arg == flag && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// hideDisabledCommands hides the command groups of optional services the
+// account cannot use from help output. Availability is probed with the API
+// key from the flags, TD_API_KEY or the config file and cached for a day;
+// without a key, or when a probe fails, every group stays visible.
+func hideDisabledCommands(app *kong.Kong, args []string, config *Config) {
+	apiKey := argValue(args, "--api-key")
+	if apiKey == "" {
+		apiKey = os.Getenv("TD_API_KEY")
+	}
+	region := argValue(args, "--region")
+	if region == "" {
+		region = os.Getenv("TD_REGION")
+	}
+	if config != nil {
+		if apiKey == "" {
+			apiKey = config.APIKey
+		}
+		if region == "" {
+			region = config.Region
+		}
+	}
+	if apiKey == "" || !isValidAPIKey(apiKey) {
+		return
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return
+	}
+	client, err := newCLIClient(apiKey, region, td.SSLOptions{}, config)
+	if err != nil {
+		return
+	}
+	path := filepath.Join(homeDir, ".tdcli", "features.json")
+	hideCommands(app, disabledFeatures(path, apiKey, region, time.Now(), client.FeatureEnabled))
+}
+
+// disabledFeatures returns the command groups of unavailable services, from
+// the state file at path when it is fresh for the same key, or by probing
+func disabledFeatures(path, apiKey, region string, now time.Time, probe func(context.Context, td.ServiceKind) (bool, error)) []string {
+	sum := sha256.Sum256([]byte(apiKey + "@" + region))
+	key := hex.EncodeToString(sum[:8])
+
+	var state featureState
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &state)
+		if state.Key == key && now.Sub(state.CheckedAt) < featureCheckInterval {
+			return state.Disabled
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), featureCheckTimeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	state = featureState{Key: key, CheckedAt: now}
+	failed := false
+	for service, command := range featureCommands {
+		wg.Add(1)
+		go func(service td.ServiceKind, command string) {
+			defer wg.Done()
+			enabled, err := probe(ctx, service)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				failed = true
+			case !enabled:
+				state.Disabled = append(state.Disabled, command)
+			}
+		}(service, command)
+	}
+	wg.Wait()
+
+	// Do not remember a partial answer; the next help run probes again
+	if !failed {
+		if data, err := json.Marshal(state); err == nil {
+			if err := os.MkdirAll(filepath.Dir(path), 0700); err == nil {
+				os.WriteFile(path, data, 0600)
+			}
+		}
+	}
+	return state.Disabled
+}
+
+// hideCommands marks the named top-level commands hidden
+func hideCommands(app *kong.Kong, names []string) {
+	for _, node := range app.Model.Children {
+		for _, name := range names {
+			if node.Name == name {
+				node.Hidden = true
+			}
+		}
+	}
+}
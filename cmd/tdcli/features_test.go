@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/kong"
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+func TestHelpRequested(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{nil, true},
+		{[]string{"--help"}, true},
+		{[]string{"cdp", "-h"}, true},
+		{[]string{"db", "list"}, false},
+		{[]string{"query", "submit", "--", "--help"}, false},
+	}
+	for _, tt := range tests {
+		if got := helpRequested(tt.args); got != tt.want {
+			t.Errorf("helpRequested(%v) = %t, want %t", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestArgValue(t *testing.T) {
+	args := []string{"--region", "eu", "--api-key=1/abc", "--help"}
+	if got := argValue(args, "--region"); got != "eu" {
+		t.Errorf("region = %q", got)
+	}
+	if got := argValue(args, "--api-key"); got != "1/abc" {
+		t.Errorf("api key = %q", got)
+	}
+	if got := argValue(args, "--format"); got != "" {
+		t.Errorf("format = %q", got)
+	}
+}
+
+func TestDisabledFeatures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "features.json")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var probes atomic.Int32
+	probe := func(ctx context.Context, service td.ServiceKind) (bool, error) {
+		probes.Add(1)
+		return service != td.ServiceCDP, nil
+	}
+
+	if got := disabledFeatures(path, "1/abc", "us", now, probe); !reflect.DeepEqual(got, []string{"cdp"}) {
+		t.Errorf("disabled = %v, want [cdp]", got)
+	}
+	// A fresh result for the same key is reused
+	disabledFeatures(path, "1/abc", "us", now.Add(time.Hour), probe)
+	if got := probes.Load(); got != 2 {
+		t.Errorf("probes = %d, want 2", got)
+	}
+	// Another key or an old result probes again
+	disabledFeatures(path, "2/def", "us", now.Add(time.Hour), probe)
+	disabledFeatures(path, "2/def", "us", now.Add(48*time.Hour), probe)
+	if got := probes.Load(); got != 6 {
+		t.Errorf("probes = %d, want 6", got)
+	}
+
+	// Failed probes hide nothing and are not remembered
+	failing := filepath.Join(t.TempDir(), "features.json")
+	fail := func(ctx context.Context, service td.ServiceKind) (bool, error) {
+		probes.Add(1)
+		return false, errors.New("network down")
+	}
+	if got := disabledFeatures(failing, "1/abc", "us", now, fail); len(got) != 0 {
+		t.Errorf("disabled = %v, want none", got)
+	}
+	disabledFeatures(failing, "1/abc", "us", now, fail)
+	if got := probes.Load(); got != 10 {
+		t.Errorf("probes = %d, want 10", got)
+	}
+}
+
+func TestHideCommands(t *testing.T) {
+	var cli CLI
+	app, err := kong.New(&cli, kong.Name("tdcli"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hideCommands(app, []string{"cdp"})
+
+	for _, node := range app.Model.Children {
+		if want := node.Name == "cdp"; node.Hidden != want && node.Name != "telemetry-flush" {
+			t.Errorf("%s hidden = %t, want %t", node.Name, node.Hidden, want)
+		}
+	}
+}
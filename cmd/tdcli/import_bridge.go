@@ -0,0 +1,473 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+	"github.com/segmentio/kafka-go"
+)
+
+// bridgeMessage is a single raw record read from a bridge source
+type bridgeMessage struct {
+	Partition int
+	Offset    int64 // position of this message in the partition
+	Next      int64 // position to resume from once this message is imported
+	Value     []byte
+}
+
+// bridgeSource is a stream of JSON records that can be resumed from an offset
+type bridgeSource interface {
+	// Read returns the next message, io.EOF when a finite source is exhausted,
+	// or the context error when ctx expires before a message arrives
+	Read(ctx context.Context) (bridgeMessage, error)
+	Close() error
+}
+
+// bridgeCheckpoint records the next offset to read for each source partition
+type bridgeCheckpoint struct {
+	Source    string           `json:"source"`
+	Offsets   map[string]int64 `json:"offsets"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// loadBridgeCheckpoint reads a checkpoint file, returning an empty checkpoint if it does not exist
+func loadBridgeCheckpoint(path, source string) (*bridgeCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &bridgeCheckpoint{Source: source, Offsets: map[string]int64{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint %s: %v", path, err)
+	}
+
+	var cp bridgeCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %v", path, err)
+	}
+	if cp.Source != source {
+		return nil, fmt.Errorf("checkpoint %s belongs to source %s, not %s (remove it or use --checkpoint)", path, cp.Source, source)
+	}
+	if cp.Offsets == nil {
+		cp.Offsets = map[string]int64{}
+	}
+	return &cp, nil
+}
+
+// save atomically writes the checkpoint so a crash never leaves a truncated file
+func (cp *bridgeCheckpoint) save(path string) error {
+	cp.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// offset returns the stored offset for a partition
+func (cp *bridgeCheckpoint) offset(partition int) (int64, bool) {
+	off, ok := cp.Offsets[strconv.Itoa(partition)]
+	return off, ok
+}
+
+// openBridgeSource opens the source described by from, resuming at the checkpointed offset
+func openBridgeSource(from string, cp *bridgeCheckpoint) (bridgeSource, error) {
+	switch {
+	case strings.HasPrefix(from, "kafka://"):
+		return openKafkaSource(strings.TrimPrefix(from, "kafka://"), cp)
+	case from == "-":
+		return &jsonlSource{reader: bufio.NewReader(os.Stdin)}, nil
+	default:
+		return openJSONLSource(strings.TrimPrefix(from, "file://"), cp)
+	}
+}
+
+// jsonlSource reads newline-delimited JSON records, using byte offsets as positions
+type jsonlSource struct {
+	reader *bufio.Reader
+	closer io.Closer
+	offset int64
+}
+
+func openJSONLSource(path string, cp *bridgeCheckpoint) (*jsonlSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var start int64
+	if off, ok := cp.offset(0); ok {
+		if _, err := file.Seek(off, io.SeekStart); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to seek to checkpoint offset %d: %v", off, err)
+		}
+		start = off
+	}
+
+	return &jsonlSource{reader: bufio.NewReader(file), closer: file, offset: start}, nil
+}
+
+func (s *jsonlSource) Read(ctx context.Context) (bridgeMessage, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return bridgeMessage{}, err
+		}
+
+		line, err := s.reader.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			return bridgeMessage{}, err
+		}
+		if err != nil && err != io.EOF {
+			return bridgeMessage{}, err
+		}
+
+		msg := bridgeMessage{Offset: s.offset, Next: s.offset + int64(len(line))}
+		s.offset = msg.Next
+
+		msg.Value = bytes.TrimSpace(line)
+		if len(msg.Value) == 0 {
+			continue
+		}
+		return msg, nil
+	}
+}
+
+func (s *jsonlSource) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// kafkaSource reads a single topic partition
+type kafkaSource struct {
+	reader    *kafka.Reader
+	partition int
+}
+
+// openKafkaSource opens brokers/topic?partition=N (brokers are comma separated)
+func openKafkaSource(spec string, cp *bridgeCheckpoint) (*kafkaSource, error) {
+	brokerPart, rest, ok := strings.Cut(spec, "/")
+	if !ok || brokerPart == "" {
+		return nil, fmt.Errorf("invalid kafka source: expected kafka://broker[,broker]/topic")
+	}
+
+	topic, rawQuery, _ := strings.Cut(rest, "?")
+	if topic == "" {
+		return nil, fmt.Errorf("invalid kafka source: topic is required")
+	}
+
+	partition := 0
+	for _, param := range strings.Split(rawQuery, "&") {
+		key, value, _ := strings.Cut(param, "=")
+		if key != "partition" {
+			continue
+		}
+		p, err := strconv.Atoi(value)
+		if err != nil || p < 0 {
+			return nil, fmt.Errorf("invalid kafka partition: %s", value)
+		}
+		partition = p
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   strings.Split(brokerPart, ","),
+		Topic:     topic,
+		Partition: partition,
+	})
+
+	start := kafka.FirstOffset
+	if off, ok := cp.offset(partition); ok {
+		start = off
+	}
+	if err := reader.SetOffset(start); err != nil {
+		reader.Close()
+		return nil, fmt.Errorf("failed to set kafka offset: %v", err)
+	}
+
+	return &kafkaSource{reader: reader, partition: partition}, nil
+}
+
+func (s *kafkaSource) Read(ctx context.Context) (bridgeMessage, error) {
+	msg, err := s.reader.ReadMessage(ctx)
+	if err != nil {
+		return bridgeMessage{}, err
+	}
+	return bridgeMessage{
+		Partition: s.partition,
+		Offset:    msg.Offset,
+		Next:      msg.Offset + 1,
+		Value:     msg.Value,
+	}, nil
+}
+
+func (s *kafkaSource) Close() error {
+	return s.reader.Close()
+}
+
+// importBridge batches records from a source into the streaming import API
+type importBridge struct {
+	client        *td.Client
+	source        bridgeSource
+	sourceName    string
+	database      string
+	table         string
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+	checkpoint    *bridgeCheckpoint
+	checkpointAt  string
+	validator     *recordValidator
+	converter     *recordConverter
+	verbose       bool
+
+	records   []map[string]interface{}
+	batch     []bridgeMessage
+	batchTime timeRange
+
+	imported int64
+	skipped  int64
+	batches  int
+	allTime  timeRange
+}
+
+// run consumes the source until it is exhausted or ctx is cancelled, flushing pending records before returning
+func (b *importBridge) run(ctx context.Context) error {
+	deadline := time.Now().Add(b.flushInterval)
+
+	for {
+		readCtx, cancel := context.WithDeadline(ctx, deadline)
+		msg, err := b.source.Read(readCtx)
+		cancel()
+
+		switch {
+		case err == nil:
+			b.add(msg)
+			if len(b.batch) >= b.batchSize {
+				if err := b.flush(ctx); err != nil {
+					return err
+				}
+				deadline = time.Now().Add(b.flushInterval)
+			}
+		case errors.Is(err, io.EOF):
+			return b.flush(ctx)
+		case ctx.Err() != nil:
+			// Interrupted: give pending records a bounded chance to land before exiting
+			flushCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			return b.flush(flushCtx)
+		case errors.Is(err, context.DeadlineExceeded):
+			if err := b.flush(ctx); err != nil {
+				return err
+			}
+			deadline = time.Now().Add(b.flushInterval)
+		default:
+			return fmt.Errorf("failed to read from source: %v", err)
+		}
+	}
+}
+
+func (b *importBridge) add(msg bridgeMessage) {
+	b.batch = append(b.batch, msg)
+
+	record, ts, err := b.converter.Convert(msg.Value)
+	if err != nil {
+		b.skipped++
+		if b.validator != nil {
+			b.validator.Reject(b.sourceName, msg.Offset, msg.Value, err)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "Warning: skipping record at partition %d offset %d: %v\n", msg.Partition, msg.Offset, err)
+		return
+	}
+	if b.validator != nil && !b.validator.Validate(b.sourceName, msg.Offset, msg.Value, record) {
+		b.skipped++
+		return
+	}
+	b.records = append(b.records, record)
+	b.batchTime.observe(ts)
+}
+
+// flush imports the buffered records and then advances the checkpoint.
+// The checkpoint is only written after a successful import, so a crash
+// replays the batch: delivery is at-least-once. Retries of a batch reuse its
+// unique ID, so the server drops a retry whose earlier attempt landed, but a
+// batch replayed after a crash can end at a different record, since flushes
+// also happen on a timer, and is then imported again under a new ID.
+func (b *importBridge) flush(ctx context.Context) error {
+	if len(b.batch) == 0 {
+		return nil
+	}
+
+	if len(b.records) > 0 {
+		uniqueID := b.batchID()
+		var err error
+		for attempt := 0; ; attempt++ {
+			_, err = b.client.Import.ImportRecords(ctx, b.database, b.table, uniqueID, b.records)
+			if err == nil || attempt >= b.maxRetries || ctx.Err() != nil {
+				break
+			}
+			wait := time.Duration(1<<attempt) * time.Second
+			fmt.Fprintf(os.Stderr, "Warning: import failed (attempt %d/%d), retrying in %s: %v\n", attempt+1, b.maxRetries+1, wait, err)
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to import batch %s: %v", uniqueID, err)
+		}
+	}
+
+	// Dead letters must be durable before their offsets are checkpointed
+	if err := b.validator.Flush(); err != nil {
+		return fmt.Errorf("failed to write dead-letter file: %v", err)
+	}
+
+	for _, msg := range b.batch {
+		b.checkpoint.Offsets[strconv.Itoa(msg.Partition)] = msg.Next
+	}
+	if b.checkpointAt != "" {
+		if err := b.checkpoint.save(b.checkpointAt); err != nil {
+			return fmt.Errorf("failed to save checkpoint: %v", err)
+		}
+	}
+
+	b.imported += int64(len(b.records))
+	b.batches++
+	b.allTime.merge(b.batchTime)
+	if b.verbose {
+		last := b.batch[len(b.batch)-1]
+		fmt.Printf("Imported %d records into %s.%s (partition %d, next offset %d, time %s)\n", len(b.records), b.database, b.table, last.Partition, last.Next, b.batchTime)
+	}
+
+	b.batch = b.batch[:0]
+	b.records = b.records[:0]
+	b.batchTime = timeRange{}
+	return nil
+}
+
+// batchID derives the import ID shared by the retries of a batch from its
+// source position
+func (b *importBridge) batchID() string {
+	first, last := b.batch[0], b.batch[len(b.batch)-1]
+	key := fmt.Sprintf("%s|%d|%d|%d", b.sourceName, first.Partition, first.Offset, last.Next)
+	sum := md5.Sum([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// defaultBridgeCheckpointPath returns ~/.tdcli/bridge/<database>.<table>.json
+func defaultBridgeCheckpointPath(database, table string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".tdcli", "bridge", fmt.Sprintf("%s.%s.json", database, table)), nil
+}
+
+func handleImportBridge(ctx context.Context, client *td.Client, cmd *ImportBridgeCmd, flags Flags) error {
+	database, table, ok := strings.Cut(cmd.To, ".")
+	if !ok || database == "" || table == "" {
+		return fmt.Errorf("invalid destination %q: expected database.table", cmd.To)
+	}
+	if cmd.BatchSize <= 0 {
+		return fmt.Errorf("batch size must be positive")
+	}
+	if cmd.FlushInterval <= 0 {
+		return fmt.Errorf("flush interval must be positive")
+	}
+
+	// stdin cannot be rewound, so it never uses a checkpoint
+	checkpointPath := cmd.Checkpoint
+	if checkpointPath == "" && cmd.From != "-" {
+		var err error
+		checkpointPath, err = defaultBridgeCheckpointPath(database, table)
+		if err != nil {
+			return fmt.Errorf("failed to determine checkpoint path: %v", err)
+		}
+	}
+
+	checkpoint := &bridgeCheckpoint{Source: cmd.From, Offsets: map[string]int64{}}
+	if checkpointPath != "" {
+		var err error
+		checkpoint, err = loadBridgeCheckpoint(checkpointPath, cmd.From)
+		if err != nil {
+			return err
+		}
+	}
+
+	source, err := openBridgeSource(cmd.From, checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to open source: %v", err)
+	}
+	defer source.Close()
+
+	config, err := LoadConfig()
+	if err != nil {
+		config = DefaultConfig()
+	}
+	validator, err := newRecordValidator(resolveImportSchema(cmd.Schema, database, table, config), cmd.DeadLetter)
+	if err != nil {
+		return err
+	}
+	defer validator.Close()
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	bridge := &importBridge{
+		client:        client,
+		source:        source,
+		sourceName:    cmd.From,
+		database:      database,
+		table:         table,
+		batchSize:     cmd.BatchSize,
+		flushInterval: cmd.FlushInterval,
+		maxRetries:    cmd.MaxRetries,
+		checkpoint:    checkpoint,
+		checkpointAt:  checkpointPath,
+		validator:     validator,
+		converter:     &recordConverter{TimeField: cmd.TimeField, MaxFutureSkew: cmd.MaxFutureSkew},
+		verbose:       flags.Verbose,
+	}
+
+	if flags.Verbose {
+		fmt.Printf("Bridging %s to %s.%s (batch size %d, flush interval %s)\n", cmd.From, database, table, cmd.BatchSize, cmd.FlushInterval)
+		if checkpointPath != "" {
+			fmt.Printf("Checkpoint: %s\n", checkpointPath)
+		}
+	}
+
+	err = bridge.run(ctx)
+	fmt.Printf("Imported %d records in %d batches (%d skipped)\n", bridge.imported, bridge.batches, bridge.skipped)
+	if bridge.allTime.Count > 0 {
+		fmt.Printf("Time range: %s\n", bridge.allTime)
+	}
+	if validator != nil {
+		fmt.Printf("Validation: %s\n", validator.Summary())
+	}
+	return err
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+func TestImportBridge_JSONLCheckpoint(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/v3/table/import_with_id/db/tbl/") {
+			t.Errorf("Unexpected request path: %s", r.URL.Path)
+		}
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"database":"db","table":"tbl"}`))
+	}))
+	defer server.Close()
+
+	client, err := td.NewClient("1/test", td.WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "events.jsonl")
+	data := "{\"n\":1}\n{\"n\":2}\nbroken\n\n{\"n\":3}\n"
+	if err := os.WriteFile(input, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &ImportBridgeCmd{
+		From:          "file://" + input,
+		To:            "db.tbl",
+		BatchSize:     2,
+		FlushInterval: time.Minute,
+		Checkpoint:    filepath.Join(dir, "checkpoint.json"),
+		MaxFutureSkew: time.Hour,
+	}
+
+	if err := handleImportBridge(context.Background(), client, cmd, Flags{}); err != nil {
+		t.Fatalf("handleImportBridge returned error: %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("Import requests = %d, want 2", got)
+	}
+
+	cp, err := loadBridgeCheckpoint(cmd.Checkpoint, cmd.From)
+	if err != nil {
+		t.Fatalf("loadBridgeCheckpoint returned error: %v", err)
+	}
+	if off, _ := cp.offset(0); off != int64(len(data)) {
+		t.Errorf("Checkpoint offset = %d, want %d", off, len(data))
+	}
+
+	// A second run resumes at the checkpoint and imports nothing
+	if err := handleImportBridge(context.Background(), client, cmd, Flags{}); err != nil {
+		t.Fatalf("handleImportBridge returned error: %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("Import requests after resume = %d, want 2", got)
+	}
+
+	// A checkpoint for a different source is rejected
+	if _, err := loadBridgeCheckpoint(cmd.Checkpoint, "kafka://localhost:9092/events"); err == nil {
+		t.Error("Expected error for mismatched checkpoint source")
+	}
+}
+
+func TestImportBridge_InvalidOptions(t *testing.T) {
+	for _, cmd := range []*ImportBridgeCmd{
+		{From: "-", To: "db.tbl", BatchSize: 0, FlushInterval: time.Second},
+		{From: "-", To: "db.tbl", BatchSize: 10, FlushInterval: 0},
+		{From: "-", To: "db.tbl", BatchSize: 10, FlushInterval: -time.Second},
+	} {
+		if err := handleImportBridge(context.Background(), nil, cmd, Flags{}); err == nil {
+			t.Errorf("handleImportBridge(batch size %d, flush interval %s) succeeded, want an error", cmd.BatchSize, cmd.FlushInterval)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// partLedgerEntry records one uploaded part
+type partLedgerEntry struct {
+	SHA256     string    `json:"sha256"`
+	UploadedAt time.Time `json:"uploaded_at"`
+}
+
+// partLedgerFile is the on-disk form of a session's ledger
+type partLedgerFile struct {
+	Session string                     `json:"session"`
+	Parts   map[string]partLedgerEntry `json:"parts"`
+}
+
+// filePartLedger is a td.PartLedger persisted under ~/.tdcli/bulk_import so
+// repeated uploads to the same session can skip parts that are already there.
+// Each endpoint and account gets its own directory, since session names are
+// only unique within one account.
+type filePartLedger struct {
+	dir   string
+	mu    sync.Mutex
+	cache map[string]*partLedgerFile
+}
+
+var _ td.PartLedger = (*filePartLedger)(nil)
+
+func newFilePartLedger(dir string) *filePartLedger {
+	return &filePartLedger{dir: dir, cache: map[string]*partLedgerFile{}}
+}
+
+// defaultPartLedger returns the ledger stored in the user's tdcli directory
+// for the client's endpoint and account
+func defaultPartLedger(client *td.Client) (*filePartLedger, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return newFilePartLedger(filepath.Join(homeDir, ".tdcli", "bulk_import", partLedgerScope(client))), nil
+}
+
+// partLedgerScope names the ledger directory of an endpoint and account, the
+// account being the key's ID before the slash
+func partLedgerScope(client *td.Client) string {
+	account, _, _ := strings.Cut(client.APIKey, "/")
+	return ledgerScopeUnsafe.ReplaceAllString(client.BaseURL.Host+"_"+account, "_")
+}
+
+var ledgerScopeUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+func (l *filePartLedger) path(session string) string {
+	return filepath.Join(l.dir, session+".json")
+}
+
+// load returns the ledger for a session; the caller must hold l.mu
+func (l *filePartLedger) load(session string) (*partLedgerFile, error) {
+	if f, ok := l.cache[session]; ok {
+		return f, nil
+	}
+
+	f := &partLedgerFile{Session: session, Parts: map[string]partLedgerEntry{}}
+	data, err := os.ReadFile(l.path(session))
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read part ledger: %v", err)
+	default:
+		if err := json.Unmarshal(data, f); err != nil {
+			return nil, fmt.Errorf("failed to parse part ledger %s: %v", l.path(session), err)
+		}
+		if f.Parts == nil {
+			f.Parts = map[string]partLedgerEntry{}
+		}
+	}
+
+	l.cache[session] = f
+	return f, nil
+}
+
+// PartHash returns the hash recorded for a part
+func (l *filePartLedger) PartHash(session, part string) (string, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := l.load(session)
+	if err != nil {
+		return "", false, err
+	}
+	entry, ok := f.Parts[part]
+	return entry.SHA256, ok, nil
+}
+
+// RecordPart stores the hash of an uploaded part and rewrites the ledger file
+func (l *filePartLedger) RecordPart(session, part, hash string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := l.load(session)
+	if err != nil {
+		return err
+	}
+	f.Parts[part] = partLedgerEntry{SHA256: hash, UploadedAt: time.Now().UTC()}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return err
+	}
+
+	path := l.path(session)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Forget removes a session's ledger, e.g. after the session is deleted
+func (l *filePartLedger) Forget(session string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.cache, session)
+	if err := os.Remove(l.path(session)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// verifiedPartLedger only reports parts as recorded when the session on the
+// server still lists them, so a ledger entry left by a deleted session, or
+// by another tool, never causes a part to be skipped
+type verifiedPartLedger struct {
+	td.PartLedger
+	ctx    context.Context
+	client *td.Client
+
+	mu    sync.Mutex
+	parts map[string]map[string]bool
+}
+
+func newVerifiedPartLedger(ctx context.Context, client *td.Client, ledger td.PartLedger) *verifiedPartLedger {
+	return &verifiedPartLedger{PartLedger: ledger, ctx: ctx, client: client, parts: map[string]map[string]bool{}}
+}
+
+// PartHash returns the recorded hash of a part the session still has
+func (l *verifiedPartLedger) PartHash(session, part string) (string, bool, error) {
+	hash, ok, err := l.PartLedger.PartHash(session, part)
+	if err != nil || !ok {
+		return hash, ok, err
+	}
+
+	uploaded, err := l.sessionParts(session)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to list parts of session %s: %v", session, err)
+	}
+	if !uploaded[part] {
+		return "", false, nil
+	}
+	return hash, true, nil
+}
+
+// sessionParts lists a session's parts once and caches them; parts uploaded
+// later in the run are recorded as they succeed
+func (l *verifiedPartLedger) sessionParts(session string) (map[string]bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if parts, ok := l.parts[session]; ok {
+		return parts, nil
+	}
+	list, err := l.client.BulkImport.ListParts(l.ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	parts := make(map[string]bool, len(list))
+	for _, p := range list {
+		parts[p.Name] = true
+	}
+	l.parts[session] = parts
+	return parts, nil
+}
+
+// RecordPart records an uploaded part in the underlying ledger and the cache
+func (l *verifiedPartLedger) RecordPart(session, part, hash string) error {
+	l.mu.Lock()
+	if parts, ok := l.parts[session]; ok {
+		parts[part] = true
+	}
+	l.mu.Unlock()
+	return l.PartLedger.RecordPart(session, part, hash)
+}
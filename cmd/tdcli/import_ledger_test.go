@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+func TestFilePartLedger(t *testing.T) {
+	dir := t.TempDir()
+
+	ledger := newFilePartLedger(dir)
+	if err := ledger.RecordPart("session", "part1", "abc"); err != nil {
+		t.Fatalf("RecordPart returned error: %v", err)
+	}
+
+	// A fresh ledger reads what the previous run recorded
+	reopened := newFilePartLedger(dir)
+	if state, err := td.CheckPart(reopened, "session", "part1", "abc"); state != td.PartUnchanged || err != nil {
+		t.Errorf("CheckPart identical = %v, %v, want PartUnchanged", state, err)
+	}
+	if state, err := td.CheckPart(reopened, "session", "part1", "def"); state != td.PartConflict || err == nil {
+		t.Errorf("CheckPart changed = %v, %v, want PartConflict with error", state, err)
+	}
+	if state, _ := td.CheckPart(reopened, "other", "part1", "abc"); state != td.PartNew {
+		t.Errorf("CheckPart other session = %v, want PartNew", state)
+	}
+
+	if err := reopened.Forget("session"); err != nil {
+		t.Fatalf("Forget returned error: %v", err)
+	}
+	if state, _ := td.CheckPart(newFilePartLedger(dir), "session", "part1", "abc"); state != td.PartNew {
+		t.Errorf("CheckPart after Forget = %v, want PartNew", state)
+	}
+}
+
+func TestPartLedgerScope(t *testing.T) {
+	a, _ := td.NewClient("1/abc", td.WithEndpoint("https://api.treasuredata.com"))
+	b, _ := td.NewClient("2/abc", td.WithEndpoint("https://api.treasuredata.com"))
+	c, _ := td.NewClient("1/abc", td.WithEndpoint("https://api.treasuredata.co.jp"))
+
+	scopes := map[string]bool{partLedgerScope(a): true, partLedgerScope(b): true, partLedgerScope(c): true}
+	if len(scopes) != 3 {
+		t.Errorf("Scopes = %v, want one per endpoint and account", scopes)
+	}
+	if got := partLedgerScope(a); got != "api.treasuredata.com_1" {
+		t.Errorf("partLedgerScope = %s, want api.treasuredata.com_1", got)
+	}
+}
+
+func TestVerifiedPartLedger(t *testing.T) {
+	mux := http.NewServeMux()
+	lists := 0
+	mux.HandleFunc("/v3/bulk_import/list_parts/session", func(w http.ResponseWriter, r *http.Request) {
+		lists++
+		fmt.Fprint(w, `{"parts": [{"name": "part1", "size": 10}]}`)
+	})
+
+	client := newCompareTestClient(t, mux)
+
+	local := td.NewMemoryPartLedger()
+	local.RecordPart("session", "part1", "abc")
+	local.RecordPart("session", "part2", "def")
+	ledger := newVerifiedPartLedger(context.Background(), client, local)
+
+	if state, _ := td.CheckPart(ledger, "session", "part1", "abc"); state != td.PartUnchanged {
+		t.Errorf("CheckPart listed part = %v, want PartUnchanged", state)
+	}
+	// part2 is in the local ledger but not in the session, so it is uploaded again
+	if state, _ := td.CheckPart(ledger, "session", "part2", "def"); state != td.PartNew {
+		t.Errorf("CheckPart unlisted part = %v, want PartNew", state)
+	}
+	if lists != 1 {
+		t.Errorf("ListParts called %d times, want 1", lists)
+	}
+}
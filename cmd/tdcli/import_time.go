@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// importTimeLayouts are the string timestamp formats accepted for the time column
+var importTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// recordConverter decodes raw JSON records and normalizes their time column
+type recordConverter struct {
+	// TimeField names the field the time column is derived from. When empty,
+	// an existing "time" field is used, otherwise the current time.
+	TimeField string
+	// MaxFutureSkew rejects records whose time is further than this in the
+	// future. Zero disables the check.
+	MaxFutureSkew time.Duration
+
+	now func() time.Time
+}
+
+// timeRange tracks the earliest and latest record times in a batch or part
+type timeRange struct {
+	Min   int64
+	Max   int64
+	Count int64
+}
+
+func (r *timeRange) observe(t int64) {
+	if r.Count == 0 || t < r.Min {
+		r.Min = t
+	}
+	if r.Count == 0 || t > r.Max {
+		r.Max = t
+	}
+	r.Count++
+}
+
+func (r *timeRange) merge(other timeRange) {
+	if other.Count == 0 {
+		return
+	}
+	if r.Count == 0 {
+		*r = other
+		return
+	}
+	if other.Min < r.Min {
+		r.Min = other.Min
+	}
+	if other.Max > r.Max {
+		r.Max = other.Max
+	}
+	r.Count += other.Count
+}
+
+func (r timeRange) String() string {
+	if r.Count == 0 {
+		return "no records"
+	}
+	layout := "2006-01-02 15:04:05 UTC"
+	return fmt.Sprintf("%s to %s", time.Unix(r.Min, 0).UTC().Format(layout), time.Unix(r.Max, 0).UTC().Format(layout))
+}
+
+// Convert decodes a JSON object into an import record with an integer time column
+func (c *recordConverter) Convert(value []byte) (map[string]interface{}, int64, error) {
+	dec := json.NewDecoder(bytes.NewReader(value))
+	dec.UseNumber()
+
+	var record map[string]interface{}
+	if err := dec.Decode(&record); err != nil {
+		return nil, 0, fmt.Errorf("invalid JSON record: %v", err)
+	}
+	if record == nil {
+		return nil, 0, fmt.Errorf("record is not a JSON object")
+	}
+
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+
+	field := c.TimeField
+	if field == "" {
+		field = "time"
+	}
+
+	var ts int64
+	if raw, ok := record[field]; ok && raw != nil {
+		parsed, err := parseImportTime(raw)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid %s field: %v", field, err)
+		}
+		ts = parsed
+	} else if c.TimeField != "" {
+		return nil, 0, fmt.Errorf("missing time field %q", c.TimeField)
+	} else {
+		ts = now().Unix()
+	}
+
+	if c.MaxFutureSkew > 0 {
+		if ahead := time.Unix(ts, 0).Sub(now()); ahead > c.MaxFutureSkew {
+			return nil, 0, fmt.Errorf("time %s is %s in the future (tolerance %s)",
+				time.Unix(ts, 0).UTC().Format(time.RFC3339), ahead.Round(time.Second), c.MaxFutureSkew)
+		}
+	}
+
+	record["time"] = ts
+	return record, ts, nil
+}
+
+// parseImportTime converts a JSON value into Unix seconds. Numbers above 1e12
+// are treated as milliseconds, which is what most event producers emit.
+func parseImportTime(v interface{}) (int64, error) {
+	switch val := v.(type) {
+	case json.Number:
+		return parseImportTimeNumber(val.String())
+	case float64:
+		return normalizeEpoch(int64(val)), nil
+	case int64:
+		return normalizeEpoch(val), nil
+	case int:
+		return normalizeEpoch(int64(val)), nil
+	case string:
+		s := strings.TrimSpace(val)
+		if ts, err := parseImportTimeNumber(s); err == nil {
+			return ts, nil
+		}
+		for _, layout := range importTimeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t.Unix(), nil
+			}
+		}
+		return 0, fmt.Errorf("unrecognized timestamp %q", val)
+	default:
+		return 0, fmt.Errorf("unsupported timestamp type %T", v)
+	}
+}
+
+func parseImportTimeNumber(s string) (int64, error) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return normalizeEpoch(i), nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	return normalizeEpoch(int64(f)), nil
+}
+
+func normalizeEpoch(v int64) int64 {
+	if v > 1e12 || v < -1e12 {
+		return v / 1000
+	}
+	return v
+}
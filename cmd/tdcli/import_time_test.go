@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordConverter_Convert(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	converter := &recordConverter{MaxFutureSkew: 10 * time.Minute, now: func() time.Time { return now }}
+
+	tests := []struct {
+		name  string
+		input string
+		want  int64
+	}{
+		{"existing seconds", `{"time":1700000000}`, 1700000000},
+		{"existing milliseconds", `{"time":1700000000123}`, 1700000000},
+		{"numeric string", `{"time":"1700000000"}`, 1700000000},
+		{"rfc3339 string", `{"time":"2024-06-01T11:00:00Z"}`, now.Add(-time.Hour).Unix()},
+		{"missing uses now", `{"user":"a"}`, now.Unix()},
+		{"within tolerance", `{"time":"2024-06-01T12:05:00Z"}`, now.Add(5 * time.Minute).Unix()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, ts, err := converter.Convert([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Convert returned error: %v", err)
+			}
+			if ts != tt.want || record["time"] != tt.want {
+				t.Errorf("Convert time = %d (record %v), want %d", ts, record["time"], tt.want)
+			}
+		})
+	}
+
+	for _, invalid := range []string{
+		`not json`,
+		`null`,
+		`[1,2]`,
+		`{"time":"yesterday"}`,
+		`{"time":"2024-06-01T13:00:00Z"}`, // an hour ahead of the clock
+	} {
+		if _, _, err := converter.Convert([]byte(invalid)); err == nil {
+			t.Errorf("Expected error for %s", invalid)
+		}
+	}
+}
+
+func TestRecordConverter_TimeField(t *testing.T) {
+	converter := &recordConverter{TimeField: "event_at"}
+
+	record, ts, err := converter.Convert([]byte(`{"event_at":"2024-01-02 03:04:05","user":"a"}`))
+	if err != nil {
+		t.Fatalf("Convert returned error: %v", err)
+	}
+	want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC).Unix()
+	if ts != want || record["time"] != want {
+		t.Errorf("Convert time = %d, want %d", ts, want)
+	}
+	if record["event_at"] == nil {
+		t.Error("Expected the source field to be preserved")
+	}
+
+	if _, _, err := converter.Convert([]byte(`{"user":"a"}`)); err == nil {
+		t.Error("Expected error when the named time field is missing")
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+	var r timeRange
+	if r.String() != "no records" {
+		t.Errorf("Empty range = %q", r.String())
+	}
+
+	r.observe(200)
+	r.observe(100)
+	other := timeRange{}
+	other.observe(300)
+	r.merge(other)
+
+	if r.Min != 100 || r.Max != 300 || r.Count != 3 {
+		t.Errorf("Range = %+v, want min 100 max 300 count 3", r)
+	}
+}
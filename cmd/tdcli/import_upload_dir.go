@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// Upload manifest statuses
+const (
+	manifestUploaded = "uploaded"
+	manifestSkipped  = "skipped"
+	manifestConflict = "conflict"
+	manifestFailed   = "failed"
+)
+
+// uploadManifestEntry records what happened to one file of a directory upload
+type uploadManifestEntry struct {
+	Path    string `json:"path"`
+	Part    string `json:"part"`
+	SHA256  string `json:"sha256,omitempty"`
+	Status  string `json:"status"`
+	Records int64  `json:"records,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// uploadManifest is written after every file so an interrupted directory
+// upload can be inspected and resumed
+type uploadManifest struct {
+	Session   string                          `json:"session"`
+	Dir       string                          `json:"dir"`
+	Pattern   string                          `json:"pattern"`
+	UpdatedAt time.Time                       `json:"updated_at"`
+	Files     map[string]*uploadManifestEntry `json:"files"`
+
+	mu sync.Mutex
+}
+
+func loadUploadManifest(path, session, dir, pattern string) (*uploadManifest, error) {
+	m := &uploadManifest{Session: session, Dir: dir, Pattern: pattern, Files: map[string]*uploadManifestEntry{}}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %v", path, err)
+	}
+
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %v", path, err)
+	}
+	if m.Session != session {
+		return nil, fmt.Errorf("manifest %s belongs to session %s, not %s (remove it or use --manifest)", path, m.Session, session)
+	}
+	if m.Files == nil {
+		m.Files = map[string]*uploadManifestEntry{}
+	}
+	m.Dir, m.Pattern = dir, pattern
+	return m, nil
+}
+
+// record stores an entry and atomically rewrites the manifest
+func (m *uploadManifest) record(path string, entry *uploadManifestEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Files[entry.Path] = entry
+	m.UpdatedAt = time.Now().UTC()
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func defaultUploadManifestPath(session string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".tdcli", "bulk_import", session+".manifest.json"), nil
+}
+
+// uploadDirFile is a file selected for a directory upload
+type uploadDirFile struct {
+	Path string // relative to the upload directory, slash-separated
+	Part string
+}
+
+// collectUploadFiles walks dir for regular files matching pattern. Patterns
+// containing a slash match the relative path, others match the file name.
+// Hidden files and directories are ignored.
+func collectUploadFiles(dir, pattern string) ([]uploadDirFile, error) {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+	}
+
+	var files []uploadDirFile
+	parts := map[string]string{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		subject := d.Name()
+		if strings.Contains(pattern, "/") {
+			subject = rel
+		}
+		if ok, _ := filepath.Match(pattern, subject); !ok {
+			return nil
+		}
+
+		part := uploadPartName(rel)
+		if other, ok := parts[part]; ok {
+			return fmt.Errorf("files %s and %s both map to part name %s", other, rel, part)
+		}
+		parts[part] = rel
+		files = append(files, uploadDirFile{Path: rel, Part: part})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// uploadPartName derives a part name from a file's relative path, replacing
+// directory separators and characters part names cannot contain with '_'
+func uploadPartName(rel string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, rel)
+}
+
+func handleImportUploadDir(ctx context.Context, client *td.Client, cmd *ImportUploadDirCmd, flags Flags) error {
+	if cmd.Parallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1")
+	}
+
+	info, err := os.Stat(cmd.Dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", cmd.Dir)
+	}
+
+	files, err := collectUploadFiles(cmd.Dir, cmd.Pattern)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fmt.Printf("No files in %s match %s\n", cmd.Dir, cmd.Pattern)
+		return nil
+	}
+
+	manifestPath := cmd.Manifest
+	if manifestPath == "" {
+		if manifestPath, err = defaultUploadManifestPath(cmd.Session); err != nil {
+			return fmt.Errorf("failed to determine manifest path: %v", err)
+		}
+	}
+	absDir, _ := filepath.Abs(cmd.Dir)
+	manifest, err := loadUploadManifest(manifestPath, cmd.Session, absDir, cmd.Pattern)
+	if err != nil {
+		return err
+	}
+
+	opts := bulkImportUploadOptions{
+		Schema:        cmd.Schema,
+		DeadLetter:    cmd.DeadLetter,
+		TimeField:     cmd.TimeField,
+		MaxFutureSkew: cmd.MaxFutureSkew,
+		Force:         cmd.Force,
+	}
+
+	schemaPath, err := resolveSessionSchema(ctx, client, cmd.Session, opts.Schema)
+	if err != nil {
+		return fmt.Errorf("failed to get bulk import session: %v", err)
+	}
+	validator, err := newRecordValidator(schemaPath, opts.DeadLetter)
+	if err != nil {
+		return err
+	}
+
+	ledger := openPartLedger(ctx, client, flags.Verbose)
+
+	if flags.Verbose {
+		previous := 0
+		for _, entry := range manifest.Files {
+			if entry.Status == manifestUploaded {
+				previous++
+			}
+		}
+		if previous > 0 {
+			fmt.Printf("Resuming: manifest records %d files already uploaded\n", previous)
+		}
+		fmt.Printf("Uploading %d files from %s to session %s with %d workers\n", len(files), cmd.Dir, cmd.Session, cmd.Parallel)
+	}
+
+	counts := map[string]int{}
+	var countsMu sync.Mutex
+
+	jobs := make(chan uploadDirFile)
+	var wg sync.WaitGroup
+	for i := 0; i < cmd.Parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range jobs {
+				entry := &uploadManifestEntry{Path: f.Path, Part: f.Part}
+				result, err := uploadPartFile(ctx, client, ledger, validator, cmd.Session, f.Part,
+					filepath.Join(cmd.Dir, filepath.FromSlash(f.Path)), schemaPath, opts)
+
+				switch {
+				case err != nil:
+					entry.Status = manifestFailed
+					entry.Error = err.Error()
+					fmt.Fprintf(os.Stderr, "Failed to upload %s as part %s: %v\n", f.Path, f.Part, err)
+				case result.Conflict != nil:
+					entry.Status = manifestConflict
+					entry.SHA256 = result.Hash
+					entry.Error = result.Conflict.Error()
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", result.Conflict)
+				case !result.Uploaded:
+					entry.Status = manifestSkipped
+					entry.SHA256 = result.Hash
+					if flags.Verbose {
+						fmt.Printf("Skipped %s: part %s already uploaded\n", f.Path, f.Part)
+					}
+				default:
+					entry.Status = manifestUploaded
+					entry.SHA256 = result.Hash
+					entry.Records = result.Times.Count
+					if result.Converted {
+						fmt.Printf("Uploaded %s as part %s (%d records, time %s)\n", f.Path, f.Part, result.Times.Count, result.Times)
+					} else {
+						fmt.Printf("Uploaded %s as part %s\n", f.Path, f.Part)
+					}
+				}
+
+				if err := manifest.record(manifestPath, entry); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to update manifest: %v\n", err)
+				}
+
+				countsMu.Lock()
+				counts[entry.Status]++
+				countsMu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, f := range files {
+		select {
+		case jobs <- f:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := validator.Close(); err != nil {
+		return fmt.Errorf("failed to write dead-letter file: %v", err)
+	}
+
+	fmt.Printf("Uploaded %d, skipped %d unchanged, %d conflicts, %d failed (manifest: %s)\n",
+		counts[manifestUploaded], counts[manifestSkipped], counts[manifestConflict], counts[manifestFailed], manifestPath)
+	if validator != nil {
+		fmt.Printf("Validation: %s\n", validator.Summary())
+	}
+	if counts[manifestConflict] > 0 && !cmd.Force {
+		fmt.Fprintln(os.Stderr, "Use --force to overwrite conflicting parts")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if counts[manifestFailed] > 0 {
+		return fmt.Errorf("%d of %d files failed to upload; re-run to retry them", counts[manifestFailed], len(files))
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+func TestCollectUploadFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.csv", "b.jsonl", "nested/c d.csv", ".hidden.csv", ".git/x.csv"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := collectUploadFiles(dir, "*.csv")
+	if err != nil {
+		t.Fatalf("collectUploadFiles returned error: %v", err)
+	}
+
+	var got []string
+	for _, f := range files {
+		got = append(got, f.Path+"="+f.Part)
+	}
+	want := "a.csv=a.csv,nested/c d.csv=nested_c_d.csv"
+	if strings.Join(got, ",") != want {
+		t.Errorf("collectUploadFiles = %v, want %s", got, want)
+	}
+
+	if files, _ := collectUploadFiles(dir, "nested/*"); len(files) != 1 {
+		t.Errorf("Path pattern matched %d files, want 1", len(files))
+	}
+	if _, err := collectUploadFiles(dir, "["); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+}
+
+func TestImportUploadDir_Resume(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var mu sync.Mutex
+	uploaded := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/v3/bulk_import/list_parts/session" {
+			var parts []td.BulkImportPart
+			for name := range uploaded {
+				if name != "bad.csv" {
+					parts = append(parts, td.BulkImportPart{Name: name})
+				}
+			}
+			json.NewEncoder(w).Encode(td.BulkImportPartListResponse{Parts: parts})
+			return
+		}
+
+		part := strings.TrimPrefix(r.URL.Path, "/v3/bulk_import/upload_part/session/")
+		uploaded[part]++
+		if part == "bad.csv" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := td.NewClient("1/test", td.WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"one.csv":   "a,b\n1,2\n",
+		"two.jsonl": "{\"n\":1}\n",
+		"bad.csv":   "a,b\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := &ImportUploadDirCmd{
+		Session:       "session",
+		Dir:           dir,
+		Pattern:       "*",
+		Parallel:      2,
+		Manifest:      filepath.Join(t.TempDir(), "manifest.json"),
+		MaxFutureSkew: time.Hour,
+	}
+
+	if err := handleImportUploadDir(context.Background(), client, cmd, Flags{}); err == nil {
+		t.Fatal("Expected error for failed part")
+	}
+
+	// The second run retries only the failed file
+	if err := handleImportUploadDir(context.Background(), client, cmd, Flags{}); err == nil {
+		t.Fatal("Expected error for failed part on retry")
+	}
+	if uploaded["one.csv"] != 1 || uploaded["two.jsonl"] != 1 || uploaded["bad.csv"] != 2 {
+		t.Errorf("Uploads = %v, want one.csv and two.jsonl once, bad.csv twice", uploaded)
+	}
+
+	manifest, err := loadUploadManifest(cmd.Manifest, "session", dir, "*")
+	if err != nil {
+		t.Fatalf("loadUploadManifest returned error: %v", err)
+	}
+	if got := manifest.Files["one.csv"].Status; got != manifestSkipped {
+		t.Errorf("one.csv status = %s, want %s", got, manifestSkipped)
+	}
+	if got := manifest.Files["bad.csv"].Status; got != manifestFailed {
+		t.Errorf("bad.csv status = %s, want %s", got, manifestFailed)
+	}
+
+	if _, err := loadUploadManifest(cmd.Manifest, "other", dir, "*"); err == nil {
+		t.Error("Expected error for manifest of another session")
+	}
+}
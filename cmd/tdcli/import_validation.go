@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// recordValidator checks import records against a JSON Schema and routes
+// invalid records to an optional dead-letter file. It is safe for concurrent use.
+type recordValidator struct {
+	schema     *jsonschema.Schema
+	schemaPath string
+	deadLetter *os.File
+	writer     *bufio.Writer
+	mu         sync.Mutex
+
+	Valid   int64
+	Invalid int64
+}
+
+// deadLetterEntry is one line of a dead-letter file
+type deadLetterEntry struct {
+	Source string          `json:"source,omitempty"`
+	Offset int64           `json:"offset"`
+	Error  string          `json:"error"`
+	Record json.RawMessage `json:"record,omitempty"`
+	Raw    string          `json:"raw,omitempty"`
+}
+
+// resolveImportSchema returns the schema for a destination table: the explicit
+// flag wins, then the per-table entry in the configuration file
+func resolveImportSchema(flagSchema, database, table string, config *Config) string {
+	if flagSchema != "" {
+		return flagSchema
+	}
+	if config == nil || config.ImportSchemas == nil {
+		return ""
+	}
+	return config.ImportSchemas[database+"."+table]
+}
+
+// newRecordValidator compiles the schema and opens the dead-letter file for appending.
+// It returns nil when no schema is configured.
+func newRecordValidator(schemaPath, deadLetterPath string) (*recordValidator, error) {
+	if schemaPath == "" {
+		if deadLetterPath != "" {
+			return nil, fmt.Errorf("--dead-letter requires a schema")
+		}
+		return nil, nil
+	}
+
+	schema, err := jsonschema.Compile(schemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile schema %s: %v", schemaPath, err)
+	}
+
+	v := &recordValidator{schema: schema, schemaPath: schemaPath}
+	if deadLetterPath != "" {
+		file, err := os.OpenFile(deadLetterPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open dead-letter file %s: %v", deadLetterPath, err)
+		}
+		v.deadLetter = file
+		v.writer = bufio.NewWriter(file)
+	}
+	return v, nil
+}
+
+// Validate reports whether record conforms to the schema. Invalid records are
+// counted and, when a dead-letter file is configured, written to it.
+func (v *recordValidator) Validate(source string, offset int64, raw []byte, record map[string]interface{}) bool {
+	err := v.schema.Validate(record)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err == nil {
+		v.Valid++
+		return true
+	}
+
+	v.Invalid++
+	v.reject(source, offset, raw, err)
+	return false
+}
+
+// Reject records a record that could not be decoded at all
+func (v *recordValidator) Reject(source string, offset int64, raw []byte, err error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.Invalid++
+	v.reject(source, offset, raw, err)
+}
+
+func (v *recordValidator) reject(source string, offset int64, raw []byte, err error) {
+	if v.writer == nil {
+		fmt.Fprintf(os.Stderr, "Warning: rejecting record at offset %d: %v\n", offset, err)
+		return
+	}
+
+	entry := deadLetterEntry{Source: source, Offset: offset, Error: err.Error()}
+	if json.Valid(raw) {
+		entry.Record = json.RawMessage(raw)
+	} else {
+		entry.Raw = string(raw)
+	}
+
+	data, _ := json.Marshal(entry)
+	v.writer.Write(data)
+	v.writer.WriteByte('\n')
+}
+
+// Flush writes buffered dead-letter entries to disk
+func (v *recordValidator) Flush() error {
+	if v == nil || v.writer == nil {
+		return nil
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.writer.Flush(); err != nil {
+		return err
+	}
+	return v.deadLetter.Sync()
+}
+
+// Close flushes and closes the dead-letter file
+func (v *recordValidator) Close() error {
+	if v == nil || v.deadLetter == nil {
+		return nil
+	}
+	if err := v.Flush(); err != nil {
+		v.deadLetter.Close()
+		return err
+	}
+	return v.deadLetter.Close()
+}
+
+// Summary describes the validation counts
+func (v *recordValidator) Summary() string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.deadLetter != nil {
+		return fmt.Sprintf("%d valid, %d invalid (written to %s)", v.Valid, v.Invalid, v.deadLetter.Name())
+	}
+	return fmt.Sprintf("%d valid, %d invalid (rejected)", v.Valid, v.Invalid)
+}
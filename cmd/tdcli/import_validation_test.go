@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+const testEventSchema = `{
+	"type": "object",
+	"required": ["user_id"],
+	"properties": {
+		"user_id": {"type": "string"},
+		"amount": {"type": "number", "minimum": 0}
+	}
+}`
+
+func writeTestSchema(t *testing.T, dir string) string {
+	t.Helper()
+	path := filepath.Join(dir, "events.schema.json")
+	if err := os.WriteFile(path, []byte(testEventSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolveImportSchema(t *testing.T) {
+	config := &Config{ImportSchemas: map[string]string{"db.events": "events.json"}}
+
+	if got := resolveImportSchema("flag.json", "db", "events", config); got != "flag.json" {
+		t.Errorf("resolveImportSchema with flag = %s, want flag.json", got)
+	}
+	if got := resolveImportSchema("", "db", "events", config); got != "events.json" {
+		t.Errorf("resolveImportSchema from config = %s, want events.json", got)
+	}
+	if got := resolveImportSchema("", "db", "other", config); got != "" {
+		t.Errorf("resolveImportSchema for unconfigured table = %s, want empty", got)
+	}
+}
+
+func TestRecordValidator_DeadLetter(t *testing.T) {
+	dir := t.TempDir()
+	deadLetter := filepath.Join(dir, "dead.jsonl")
+
+	validator, err := newRecordValidator(writeTestSchema(t, dir), deadLetter)
+	if err != nil {
+		t.Fatalf("newRecordValidator returned error: %v", err)
+	}
+
+	input := "{\"user_id\":\"a\",\"amount\":1}\n{\"amount\":-5}\nnot json\n{\"user_id\":\"b\"}\n"
+	encoded, times, err := convertJSONLPart(strings.NewReader(input), "input.jsonl", &recordConverter{}, validator)
+	if err != nil {
+		t.Fatalf("convertJSONLPart returned error: %v", err)
+	}
+	if times.Count != 2 {
+		t.Errorf("Time range count = %d, want 2", times.Count)
+	}
+	if err := validator.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	if len(encoded) == 0 {
+		t.Error("Expected encoded valid records")
+	}
+	if validator.Valid != 2 || validator.Invalid != 2 {
+		t.Errorf("Counts = %d valid, %d invalid, want 2 and 2", validator.Valid, validator.Invalid)
+	}
+
+	file, err := os.Open(deadLetter)
+	if err != nil {
+		t.Fatalf("Failed to open dead-letter file: %v", err)
+	}
+	defer file.Close()
+
+	var entries []deadLetterEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry deadLetterEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Invalid dead-letter line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("Dead-letter entries = %d, want 2", len(entries))
+	}
+	if entries[0].Record == nil || entries[0].Offset != int64(len("{\"user_id\":\"a\",\"amount\":1}\n")) {
+		t.Errorf("First entry = %+v, want schema violation with record", entries[0])
+	}
+	if entries[1].Raw != "not json" {
+		t.Errorf("Second entry raw = %q, want %q", entries[1].Raw, "not json")
+	}
+}
+
+func TestNewRecordValidator_NoSchema(t *testing.T) {
+	validator, err := newRecordValidator("", "")
+	if err != nil || validator != nil {
+		t.Errorf("newRecordValidator without schema = %v, %v, want nil, nil", validator, err)
+	}
+
+	if _, err := newRecordValidator("", "dead.jsonl"); err == nil {
+		t.Error("Expected error for dead-letter file without schema")
+	}
+}
+
+func TestUploadPartFile_SchemaRequiresJSONL(t *testing.T) {
+	dir := t.TempDir()
+	validator, err := newRecordValidator(writeTestSchema(t, dir), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "events.csv")
+	if err := os.WriteFile(path, []byte("user_id,amount\na,1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = uploadPartFile(context.Background(), nil, td.NewMemoryPartLedger(), validator, "s", "p", path, "", bulkImportUploadOptions{})
+	if err == nil || !strings.Contains(err.Error(), "only supports JSONL") {
+		t.Errorf("err = %v, want an error for a CSV file with a schema", err)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// InitCmd walks new users through connecting tdcli to their account. With
+// --profile the key is saved as that named profile instead of the defaults.
+type InitCmd struct {
+	NoVerify bool `kong:"help='Save without checking the key against the API'"`
+}
+
+// initSettings are the answers collected by the setup wizard
+type initSettings struct {
+	APIKey string
+	Region string
+	Format string
+}
+
+func (c *InitCmd) Run(ctx *CLIContext) error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %v", err)
+	}
+	savePath := filepath.Join(homeDir, ".tdcli", ".tdcli.toml")
+	profileName := ctx.Profile
+
+	// Start from the saved file, not the merged config, so settings from a
+	// project .tdcli.toml are not copied into the global one
+	config, err := loadConfigFromFile(savePath)
+	if os.IsNotExist(err) {
+		config = DefaultConfig()
+	} else if err != nil {
+		return fmt.Errorf("failed to read %s: %v", savePath, err)
+	}
+	current := initSettings{APIKey: config.APIKey, Region: config.Region, Format: config.Format}
+	if profileName != "" {
+		profile := config.Profiles[profileName]
+		current = initSettings{APIKey: profile.APIKey, Region: profile.Region}
+	}
+
+	fmt.Println("Welcome to Treasure Data CLI!")
+	if profileName != "" {
+		fmt.Printf("Setting up profile %q in %s\n", profileName, savePath)
+	} else {
+		fmt.Printf("Setting up %s\n", savePath)
+	}
+	fmt.Println()
+
+	settings, err := promptInitSettings(current, profileName == "")
+	if err != nil {
+		return err
+	}
+
+	if !c.NoVerify {
+		fmt.Println()
+		fmt.Println("Checking the API key...")
+		account, err := verifyAPIKey(ctx.Context, settings, config)
+		if err != nil {
+			fmt.Printf("✗ Could not verify the API key: %v\n", err)
+			if !promptConfirmation("Save anyway?") {
+				fmt.Println("Setup cancelled.")
+				return nil
+			}
+		} else {
+			fmt.Printf("✓ Authenticated to account %d\n", account.ID)
+		}
+	}
+
+	applyInitSettings(config, profileName, settings)
+	if err := SaveConfig(config, savePath); err != nil {
+		return fmt.Errorf("failed to save config: %v", err)
+	}
+	// The file holds an API key
+	if err := os.Chmod(savePath, 0600); err != nil {
+		return fmt.Errorf("failed to restrict config permissions: %v", err)
+	}
+	fmt.Printf("✓ Configuration saved: %s\n", savePath)
+
+	if shell := filepath.Base(os.Getenv("SHELL")); completionScripts[shell] != "" {
+		fmt.Println()
+		if promptConfirmation(fmt.Sprintf("Install %s completion for tdcli?", shell)) {
+			path, err := installCompletion(shell, homeDir)
+			if err != nil {
+				fmt.Printf("✗ Could not install completion: %v\n", err)
+			} else {
+				fmt.Printf("✓ Completion installed in %s (open a new shell to use it)\n", path)
+			}
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("You're all set. Try:")
+	if profileName != "" {
+		fmt.Printf("  tdcli compare databases --profile-src %s --profile-dst <other profile>\n", profileName)
+	} else {
+		fmt.Println("  tdcli db list")
+		fmt.Println("  tdcli doctor")
+	}
+	return nil
+}
+
+// promptInitSettings asks for the API key, region and, unless a profile is
+// being set up, the default output format. Enter keeps current values.
+func promptInitSettings(current initSettings, askFormat bool) (initSettings, error) {
+	settings := current
+
+	keyPrompt := "API Key (format: account_id/api_key)"
+	if current.APIKey != "" {
+		keyPrompt = fmt.Sprintf("API Key (press Enter to keep %s)", maskAPIKey(current.APIKey))
+	}
+	apiKey, err := promptInput(keyPrompt, "", func(value string) error {
+		if value == "" && current.APIKey != "" {
+			return nil
+		}
+		return validateAPIKey(value)
+	})
+	if err != nil {
+		return settings, err
+	}
+	if apiKey != "" {
+		settings.APIKey = apiKey
+	}
+
+	region := current.Region
+	if regionDescriptions[region] == "" {
+		region = "us"
+	}
+	if settings.Region, err = promptChoice("Region", []string{"us", "eu", "tokyo", "ap02"}, region, regionDescriptions); err != nil {
+		return settings, err
+	}
+
+	if askFormat {
+		format := current.Format
+		if formatDescriptions[format] == "" {
+			format = "table"
+		}
+		if settings.Format, err = promptChoice("Default Output Format", []string{"table", "json", "csv"}, format, formatDescriptions); err != nil {
+			return settings, err
+		}
+	}
+	return settings, nil
+}
+
+// verifyAPIKey checks that the key authenticates in the chosen region
+func verifyAPIKey(ctx context.Context, settings initSettings, config *Config) (*td.Account, error) {
+	client, err := newCLIClient(settings.APIKey, settings.Region, td.SSLOptions{
+		InsecureSkipVerify: config.InsecureSkipVerify,
+		CertFile:           config.CertFile,
+		KeyFile:            config.KeyFile,
+		CAFile:             config.CAFile,
+	}, config)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	return client.Account.Show(ctx)
+}
+
+// applyInitSettings stores settings as the defaults of config, or as the named
+// profile, keeping the profile's other connection settings
+func applyInitSettings(config *Config, profile string, settings initSettings) {
+	if profile == "" {
+		config.APIKey = settings.APIKey
+		config.Region = settings.Region
+		if settings.Format != "" {
+			config.Format = settings.Format
+		}
+		return
+	}
+
+	if config.Profiles == nil {
+		config.Profiles = map[string]ProfileConfig{}
+	}
+	p := config.Profiles[profile]
+	p.APIKey = settings.APIKey
+	p.Region = settings.Region
+	config.Profiles[profile] = p
+}
@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestApplyInitSettings(t *testing.T) {
+	config := DefaultConfig()
+	config.Profiles = map[string]ProfileConfig{
+		"prod": {APIKey: "1/old", CAFile: "/etc/ca.pem"},
+	}
+
+	applyInitSettings(config, "", initSettings{APIKey: "1/abc", Region: "eu", Format: "json"})
+	if config.APIKey != "1/abc" || config.Region != "eu" || config.Format != "json" {
+		t.Errorf("defaults = %+v", config)
+	}
+
+	applyInitSettings(config, "prod", initSettings{APIKey: "2/def", Region: "tokyo"})
+	prod := config.Profiles["prod"]
+	if prod.APIKey != "2/def" || prod.Region != "tokyo" || prod.CAFile != "/etc/ca.pem" {
+		t.Errorf("prod = %+v", prod)
+	}
+	if config.APIKey != "1/abc" {
+		t.Errorf("profile setup changed the default key to %q", config.APIKey)
+	}
+}
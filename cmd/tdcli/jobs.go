@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	td "github.com/mickeey2525/treasuredata-go-sdk"
 )
@@ -25,6 +27,13 @@ func handleJobCommands(ctx context.Context, client *td.Client, args []string, fl
 		handleJobGet(ctx, client, subArgs, flags)
 	case "cancel", "kill":
 		handleJobCancel(ctx, client, subArgs, flags)
+	case "logs":
+		if len(subArgs) == 0 {
+			fmt.Println("Error: Job ID required")
+			fmt.Println("Usage: tdcli job logs <job_id>")
+			os.Exit(1)
+		}
+		handleJobLogs(ctx, client, subArgs[0], false, flags)
 	default:
 		fmt.Printf("Unknown job subcommand: %s\n", subcommand)
 		printJobUsage()
@@ -43,37 +52,51 @@ SUBCOMMANDS:
     list, ls               List jobs
     get, show <job_id>     Get job details
     cancel, kill <job_id>  Cancel a running job
+    logs <job_id>          Print the engine output and errors of a job
 
 OPTIONS:
     --status STATUS        Filter by job status
+    --from, --to TIME      Created in a range: lookback (7d, 12h), date or RFC 3339 time
+    --database DB          Filter by database
+    --limit N              Number of jobs to show, 0 for all (default 20)
     --format FORMAT        Output format (json, table, csv)
     --verbose, -v          Verbose output
 
 EXAMPLES:
     tdcli job list
     tdcli job list --status running
+    tdcli job list --from 2d --database sales --limit 0
     tdcli job show 12345
+    tdcli job logs 12345
     tdcli job cancel 12345
 
 `)
 }
 
 func handleJobList(ctx context.Context, client *td.Client, flags Flags) {
-	var opts *td.JobListOptions
-	if flags.Status != "" {
-		opts = &td.JobListOptions{Status: flags.Status}
-	}
+	handleJobListFiltered(ctx, client, &td.JobListOptions{Status: flags.Status}, defaultJobListLimit, flags)
+}
 
-	jobsResp, err := client.Jobs.List(ctx, opts)
-	handleError(err, "Failed to list jobs", flags.Verbose)
+// defaultJobListLimit is the number of jobs "jobs list" shows by default
+const defaultJobListLimit = 20
+
+// handleJobListFiltered lists the jobs matching opts, newest first, paging
+// until limit jobs are found (0 for all)
+func handleJobListFiltered(ctx context.Context, client *td.Client, opts *td.JobListOptions, limit int, flags Flags) {
+	jobs := []td.Job{}
+	it := client.Jobs.ListAll(ctx, opts)
+	for (limit <= 0 || len(jobs) < limit) && it.Next(ctx) {
+		jobs = append(jobs, it.Value())
+	}
+	handleError(it.Err(), "Failed to list jobs", flags.Verbose)
 
 	switch flags.Format {
 	case "json":
-		printJSON(jobsResp.Jobs)
+		printJSON(jobs)
 	case "csv":
-		printJobsCSV(jobsResp.Jobs)
+		printJobsCSV(jobs)
 	default:
-		printJobsTable(jobsResp.Jobs)
+		printJobsTable(jobs)
 	}
 }
 
@@ -96,6 +119,34 @@ func handleJobGet(ctx context.Context, client *td.Client, args []string, flags F
 	}
 }
 
+// handleJobLogs prints the debug output of a job, the progress output first
+// and the errors last where they are easiest to find
+func handleJobLogs(ctx context.Context, client *td.Client, jobID string, stderrOnly bool, flags Flags) {
+	logs, err := client.Jobs.GetLogs(ctx, jobID)
+	handleError(err, "Failed to get job logs", flags.Verbose)
+
+	if flags.Format == "json" {
+		printJSON(logs)
+		return
+	}
+
+	if logs.Cmdout == "" && logs.Stderr == "" {
+		fmt.Printf("Job %s has no logs yet\n", jobID)
+		return
+	}
+	if !stderrOnly && logs.Cmdout != "" {
+		fmt.Println("==> Output <==")
+		fmt.Println(strings.TrimRight(logs.Cmdout, "\n"))
+	}
+	if logs.Stderr != "" {
+		if !stderrOnly && logs.Cmdout != "" {
+			fmt.Println()
+		}
+		fmt.Println("==> Errors <==")
+		fmt.Println(strings.TrimRight(logs.Stderr, "\n"))
+	}
+}
+
 func handleJobCancel(ctx context.Context, client *td.Client, args []string, flags Flags) {
 	if len(args) == 0 {
 		fmt.Println("Error: Job ID required")
@@ -142,6 +193,15 @@ func printJobDetails(job td.Job) {
 	fmt.Fprintf(w, "Records\t%d\n", job.NumRecords)
 	w.Flush()
 
+	if columns, err := job.ResultSchema(); err == nil && len(columns) > 0 {
+		fmt.Printf("\nResult Schema:\n")
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, column := range columns {
+			fmt.Fprintf(w, "  %s\t%s\n", column.Name, column.Type)
+		}
+		w.Flush()
+	}
+
 	if job.Query.Value != "" {
 		fmt.Printf("\nQuery:\n%s\n", job.Query.Value)
 	}
@@ -198,3 +258,11 @@ func printJobsCSV(jobs []td.Job) {
 		)
 	}
 }
+
+// waitForJob polls a job until it finishes. A zero timeout waits indefinitely.
+func waitForJob(ctx context.Context, client *td.Client, jobID string, timeout time.Duration) (*td.Job, error) {
+	if _, err := client.Jobs.WaitForCompletion(ctx, jobID, td.WaitOptions{MaxWait: timeout}); err != nil {
+		return nil, err
+	}
+	return client.Jobs.Get(ctx, jobID)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// clearScreen moves the cursor home and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+type JobsQueueCmd struct {
+	Watch    bool          `kong:"short='w',help='Refresh until interrupted'"`
+	Interval time.Duration `kong:"help='Refresh interval for --watch',default='5s'"`
+}
+
+func (j *JobsQueueCmd) Run(ctx *CLIContext) error {
+	if !j.Watch {
+		return handleJobQueue(ctx.Context, ctx.Client, ctx.GlobalFlags)
+	}
+	if j.Interval < time.Second {
+		return fmt.Errorf("--interval must be at least 1s")
+	}
+
+	ticker := time.NewTicker(j.Interval)
+	defer ticker.Stop()
+	for {
+		fmt.Print(clearScreen)
+		if err := handleJobQueue(ctx.Context, ctx.Client, ctx.GlobalFlags); err != nil {
+			return err
+		}
+		fmt.Printf("\nRefreshing every %s, press Ctrl+C to stop\n", j.Interval)
+
+		select {
+		case <-ctx.Context.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func handleJobQueue(ctx context.Context, client *td.Client, flags Flags) error {
+	queue, err := client.Jobs.Queue(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get job queue: %w", err)
+	}
+
+	csvFormatter := func(data interface{}) string {
+		q := data.(*td.JobQueue)
+		var csvBuilder strings.Builder
+		for _, g := range q.Groups {
+			csvBuilder.WriteString(fmt.Sprintf("%s,%d,%d,%d,%.0f,%.0f\n",
+				g.Type,
+				g.Priority,
+				g.Queued,
+				g.Running,
+				g.LongestWait.Seconds(),
+				g.TypicalWait.Seconds(),
+			))
+		}
+		return csvBuilder.String()
+	}
+
+	tableFormatter := func(data interface{}) string {
+		q := data.(*td.JobQueue)
+		var tableBuilder strings.Builder
+		fmt.Fprintf(&tableBuilder, "Job queue at %s: %d queued, %d running\n\n",
+			q.At.Format("2006-01-02 15:04:05"), len(q.Queued), len(q.Running))
+		if len(q.Groups) == 0 {
+			tableBuilder.WriteString("No queued or running jobs\n")
+			return tableBuilder.String()
+		}
+
+		w := tabwriter.NewWriter(&tableBuilder, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TYPE\tPRIORITY\tQUEUED\tRUNNING\tLONGEST WAIT\tTYPICAL WAIT")
+		groups := make(map[string]td.JobQueueGroup)
+		for _, g := range q.Groups {
+			groups[fmt.Sprintf("%s/%d", g.Type, g.Priority)] = g
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%s\n",
+				g.Type,
+				g.Priority,
+				g.Queued,
+				g.Running,
+				formatWait(g.LongestWait),
+				formatWait(g.TypicalWait),
+			)
+		}
+		w.Flush()
+
+		if len(q.Queued) > 0 {
+			tableBuilder.WriteString("\nQueued jobs:\n")
+			w = tabwriter.NewWriter(&tableBuilder, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "JOB_ID\tTYPE\tPRIORITY\tUSER\tDATABASE\tWAITING\tEST. REMAINING")
+			for _, job := range q.Queued {
+				waited := q.At.Sub(job.CreatedAt.Time)
+				g := groups[fmt.Sprintf("%s/%d", job.Type, job.Priority)]
+				remaining := "-"
+				if g.TypicalWait > 0 {
+					remaining = formatWait(g.EstimatedWait(waited))
+				}
+				fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+					job.JobID,
+					job.Type,
+					job.Priority,
+					job.UserName,
+					job.Database,
+					formatWait(waited),
+					remaining,
+				)
+			}
+			w.Flush()
+		}
+		tableBuilder.WriteString("\nTypical wait is the median queue time of the last 100 jobs of the same type.\n")
+		return tableBuilder.String()
+	}
+
+	return formatAndWriteOutput(queue, flags.Format, flags.Output, "type,priority,queued,running,longest_wait_seconds,typical_wait_seconds", csvFormatter, tableFormatter)
+}
+
+// formatWait renders a wait time rounded to the second, or "-" for zero
+func formatWait(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return d.Round(time.Second).String()
+}
@@ -44,7 +44,14 @@ func isFlagExplicitlySet(flagName string) bool {
 func main() {
 	var cli CLI
 
-	ctx := kong.Parse(&cli,
+	// The workspace config fills flags, so it is loaded before parsing
+	workspace, err := loadWorkspaceConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	parser := kong.Must(&cli,
 		kong.Name("tdcli"),
 		kong.Description("Treasure Data CLI Tool"),
 		kong.UsageOnError(),
@@ -54,8 +61,24 @@ func main() {
 		kong.Vars{
 			"version": version,
 		},
+		kong.Resolvers(workspaceResolver(workspace)),
 	)
 
+	// Completion scripts call tdcli with the words typed so far
+	if len(os.Args) > 1 && os.Args[1] == completeCommand {
+		printCompletions(os.Stdout, parser, os.Args[2:])
+		os.Exit(0)
+	}
+
+	// Examples need no valid arguments, so they are printed before parsing
+	if wantsExamples(os.Args[1:]) {
+		if err := printExamples(os.Stdout, parser, os.Args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Load configuration from files after parsing (so flags can override config)
 	config, err := LoadConfig()
 	if err != nil {
@@ -63,6 +86,13 @@ func main() {
 		config = DefaultConfig()
 	}
 
+	// Leave CDP and Workflow out of help when the account cannot use them
+	if helpRequested(os.Args[1:]) {
+		hideDisabledCommands(parser, os.Args[1:], config)
+	}
+	ctx, err := parser.Parse(os.Args[1:])
+	parser.FatalIfErrorf(err)
+
 	// Apply config values if not overridden by flags/env
 	// Check if values were explicitly set via command line flags or environment
 	regionExplicitlySet := isFlagExplicitlySet("--region") || os.Getenv("TD_REGION") != ""
@@ -80,6 +110,30 @@ func main() {
 	// Get command for validation
 	command := ctx.Command()
 
+	// A profile from --profile, TD_PROFILE or .tdcli.yaml replaces the default
+	// key, region and TLS settings; --api-key and TD_API_KEY still win
+	if cli.Profile != "" && cli.APIKey == "" {
+		profile, ok := config.Profiles[cli.Profile]
+		if !ok && command != "version" && command != "init" && !strings.HasPrefix(command, "config") {
+			fmt.Fprintf(os.Stderr, "Error: profile %q is not defined; add it with: tdcli init --profile %s\n", cli.Profile, cli.Profile)
+			os.Exit(1)
+		}
+		if ok {
+			cli.APIKey = profile.APIKey
+			if !regionExplicitlySet && profile.Region != "" {
+				cli.Region = profile.Region
+				regionExplicitlySet = true
+			}
+			if !sslExplicitlySet {
+				cli.InsecureSkipVerify = profile.InsecureSkipVerify
+				cli.CertFile = profile.CertFile
+				cli.KeyFile = profile.KeyFile
+				cli.CAFile = profile.CAFile
+				sslExplicitlySet = true
+			}
+		}
+	}
+
 	if cli.APIKey == "" && config.APIKey != "" {
 		cli.APIKey = config.APIKey
 	}
@@ -109,7 +163,12 @@ func main() {
 	}
 
 	// Validate API key for non-version and non-config commands
-	if command != "version" && !strings.HasPrefix(command, "config") {
+	// init asks for the key, and completion only prints a script
+	// compare builds its clients from profiles and only needs a key without them
+	if command != "version" && command != "self-update" && command != "telemetry-flush" && !strings.HasPrefix(command, "config") &&
+		command != "init" && !strings.HasPrefix(command, "completion") && command != "audit verify" &&
+		!strings.HasPrefix(command, "validate") &&
+		!(strings.HasPrefix(command, "compare") && cli.APIKey == "") {
 		if cli.APIKey == "" {
 			fmt.Println("Error: API key required.")
 			fmt.Println("Set it via:")
@@ -126,28 +185,54 @@ func main() {
 		}
 	}
 
+	// Look up the latest release in the background to hint at outdated versions
+	var versionCheck *versionChecker
+	if command != "self-update" && command != "telemetry-flush" {
+		versionCheck = startVersionCheck(config)
+	}
+
+	// Regulated teams enable an audit trail of mutating requests in config
+	audit, err := openAuditLog(config)
+	if err != nil {
+		log.Fatalf("Failed to open audit log: %v", err)
+	}
+	audit.begin(cli.APIKey, cli.Profile, command)
+
 	// Create client if API key is provided
 	var client *td.Client
 	if cli.APIKey != "" {
 		var err error
-		// Create client with region configuration
-		clientOptions := []td.ClientOption{}
-		if cli.Region != "" {
-			clientOptions = append(clientOptions, td.WithRegion(cli.Region))
-		}
-
-		// Apply SSL options if any are configured
-		if cli.InsecureSkipVerify || cli.CertFile != "" || cli.KeyFile != "" || cli.CAFile != "" {
-			sslOptions := td.SSLOptions{
-				InsecureSkipVerify: cli.InsecureSkipVerify,
-				CertFile:           cli.CertFile,
-				KeyFile:            cli.KeyFile,
-				CAFile:             cli.CAFile,
+		var extra []td.ClientOption
+		if versionCheck != nil {
+			extra = append(extra, td.WithMiddleware(versionCheck.middleware()))
+		}
+		if audit != nil {
+			extra = append(extra, td.WithMiddleware(audit.middleware()))
+		}
+		if cli.Plan {
+			extra = append(extra, td.WithDryRun())
+		}
+		if cli.DumpHTTP {
+			extra = append(extra, td.WithHTTPDump(os.Stderr, true))
+		}
+		if cli.CacheTTL > 0 {
+			extra = append(extra, td.WithCache(td.NewMemoryCache(), cli.CacheTTL))
+		}
+		// --allow narrows the config files' allowlists rather than replacing them
+		for _, allowed := range config.OperationAllowlists(cli.Allow) {
+			rules, err := td.ParseAllowlist(allowed)
+			if err != nil {
+				log.Fatalf("Invalid allowed operations: %v", err)
 			}
-			clientOptions = append(clientOptions, td.WithSSLOptions(sslOptions))
+			extra = append(extra, td.WithAllowlist(rules...))
 		}
 
-		client, err = td.NewClient(cli.APIKey, clientOptions...)
+		client, err = newCLIClient(cli.APIKey, cli.Region, td.SSLOptions{
+			InsecureSkipVerify: cli.InsecureSkipVerify,
+			CertFile:           cli.CertFile,
+			KeyFile:            cli.KeyFile,
+			CAFile:             cli.CAFile,
+		}, config, extra...)
 		if err != nil {
 			log.Fatalf("Failed to create client: %v", err)
 		}
@@ -158,10 +243,25 @@ func main() {
 		Context:     context.Background(),
 		Client:      client,
 		GlobalFlags: cli.ToFlags(),
+		Config:      config,
+		Workspace:   workspace,
+		Profile:     cli.Profile,
+	}
+
+	// Record opt-in usage telemetry; handleError records failures for
+	// handlers that exit directly
+	if command != "telemetry-flush" {
+		activeTelemetry = startTelemetry(config, command)
 	}
 
 	// Execute the command
 	err = ctx.Run(cliContext)
+	activeTelemetry.finish(err)
+	audit.finish(cliContext.Context, client)
+	versionCheck.finish(os.Stderr)
+	if client != nil && client.DryRun() {
+		printPlan(os.Stderr, client.Plan())
+	}
 	if err != nil {
 		handleError(err, "Command failed", cli.Verbose)
 	}
@@ -176,6 +276,7 @@ func isValidAPIKey(apiKey string) bool {
 
 func handleError(err error, message string, verbose bool) {
 	if err != nil {
+		activeTelemetry.finish(err)
 		if verbose {
 			log.Fatalf("%s: %v", message, err)
 		} else {
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// MLCmd submits Hivemall training and prediction jobs
+type MLCmd struct {
+	Train   MLTrainCmd   `kong:"cmd,help='Train a Hivemall model from a table of features and labels'"`
+	Predict MLPredictCmd `kong:"cmd,aliases='apply',help='Score a table with a trained Hivemall model'"`
+}
+
+type MLTrainCmd struct {
+	Database      string `kong:"required,help='Database to run the job in'"`
+	Task          string `kong:"required,enum='classification,regression',help='Learning task (classification, regression)'"`
+	TrainingTable string `kong:"required,name='training-table',help='Table with the training examples'"`
+	ModelTable    string `kong:"required,name='model-table',help='Table to write the model to (overwritten)'"`
+	Features      string `kong:"help='Column holding array<string> features',default='features'"`
+	Label         string `kong:"help='Label column',default='label'"`
+	Options       string `kong:"help='Hivemall learner options, e.g. \"-loss logloss -opt AdaGrad\"'"`
+	Priority      int    `kong:"help='Job priority (-2 to 2)',default=0"`
+	PrintQuery    bool   `kong:"name='print-query',help='Print the generated Hive query instead of submitting it'"`
+	Wait          bool   `kong:"help='Wait for the job to complete'"`
+}
+
+func (m *MLTrainCmd) Run(ctx *CLIContext) error {
+	opts := &td.HivemallTrainOptions{
+		Task:           td.HivemallTask(m.Task),
+		TrainingTable:  m.TrainingTable,
+		ModelTable:     m.ModelTable,
+		FeaturesColumn: m.Features,
+		LabelColumn:    m.Label,
+		Options:        m.Options,
+	}
+	if m.PrintQuery {
+		return printMLQuery(opts.Query())
+	}
+
+	job, err := ctx.Client.Queries.IssueHivemallTrain(ctx.Context, m.Database, opts, &td.IssueQueryOptions{Priority: m.Priority})
+	if err != nil {
+		return fmt.Errorf("failed to submit training job: %w", err)
+	}
+	reportMLJob(ctx.Context, ctx.Client, job.JobID, m.Wait, ctx.GlobalFlags)
+	return nil
+}
+
+type MLPredictCmd struct {
+	Database    string `kong:"required,help='Database to run the job in'"`
+	Task        string `kong:"required,enum='classification,regression',help='Task the model was trained for (classification, regression)'"`
+	ModelTable  string `kong:"required,name='model-table',help='Table holding the trained model'"`
+	InputTable  string `kong:"required,name='input-table',help='Table to score'"`
+	OutputTable string `kong:"required,name='output-table',help='Table to write predictions to (overwritten)'"`
+	IDColumn    string `kong:"name='id-column',help='Column identifying input rows',default='rowid'"`
+	Features    string `kong:"help='Column holding array<string> features',default='features'"`
+	Priority    int    `kong:"help='Job priority (-2 to 2)',default=0"`
+	PrintQuery  bool   `kong:"name='print-query',help='Print the generated Hive query instead of submitting it'"`
+	Wait        bool   `kong:"help='Wait for the job to complete'"`
+}
+
+func (m *MLPredictCmd) Run(ctx *CLIContext) error {
+	opts := &td.HivemallPredictOptions{
+		Task:           td.HivemallTask(m.Task),
+		ModelTable:     m.ModelTable,
+		InputTable:     m.InputTable,
+		OutputTable:    m.OutputTable,
+		IDColumn:       m.IDColumn,
+		FeaturesColumn: m.Features,
+	}
+	if m.PrintQuery {
+		return printMLQuery(opts.Query())
+	}
+
+	job, err := ctx.Client.Queries.IssueHivemallPredict(ctx.Context, m.Database, opts, &td.IssueQueryOptions{Priority: m.Priority})
+	if err != nil {
+		return fmt.Errorf("failed to submit prediction job: %w", err)
+	}
+	reportMLJob(ctx.Context, ctx.Client, job.JobID, m.Wait, ctx.GlobalFlags)
+	return nil
+}
+
+func printMLQuery(query string, err error) error {
+	if err != nil {
+		return err
+	}
+	fmt.Println(query)
+	return nil
+}
+
+func reportMLJob(ctx context.Context, client *td.Client, jobID string, wait bool, flags Flags) {
+	fmt.Printf("Job submitted successfully\n")
+	fmt.Printf("Job ID: %s\n", jobID)
+	if wait {
+		handleQueryWait(ctx, client, jobID, flags)
+	}
+}
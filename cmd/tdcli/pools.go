@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// PoolsCmd groups the resource pool commands
+type PoolsCmd struct {
+	List PoolsListCmd `kong:"cmd,help='List the resource pools named in the configuration and, with --cdp, by parent segments'"`
+}
+
+// PoolsListCmd lists known resource pools. The API has no call that lists
+// the pools of an account, so pools are collected from where they are named:
+// priority presets, the allowed_pools query policy and CDP parent segments.
+type PoolsListCmd struct {
+	CDP bool `kong:"name='cdp',help='Also list the Hive and Trino pools of CDP parent segments'"`
+}
+
+// resourcePool is a pool name and where it was found
+type resourcePool struct {
+	Name string `json:"name"`
+	// Engine is hive or trino, or empty when the pool is not tied to one
+	Engine string `json:"engine,omitempty"`
+	Source string `json:"source"`
+}
+
+func (p *PoolsListCmd) Run(ctx *CLIContext) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %v", err)
+	}
+
+	pools := configuredPools(config)
+	if p.CDP {
+		segmentPools, err := parentSegmentPools(ctx.Context, ctx.Client)
+		if err != nil {
+			return err
+		}
+		pools = append(pools, segmentPools...)
+	}
+	sortPools(pools)
+
+	csvFormatter := func(data interface{}) string {
+		var csvBuilder strings.Builder
+		for _, pool := range data.([]resourcePool) {
+			csvBuilder.WriteString(fmt.Sprintf("%s,%s,%q\n", pool.Name, pool.Engine, pool.Source))
+		}
+		return csvBuilder.String()
+	}
+
+	tableFormatter := func(data interface{}) string {
+		pools := data.([]resourcePool)
+		if len(pools) == 0 {
+			return "No resource pools are named in the configuration; queries use the account's default pools\n"
+		}
+		var tableBuilder strings.Builder
+		w := tabwriter.NewWriter(&tableBuilder, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "POOL\tENGINE\tSOURCE")
+		for _, pool := range pools {
+			engine := pool.Engine
+			if engine == "" {
+				engine = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", pool.Name, engine, pool.Source)
+		}
+		w.Flush()
+		return tableBuilder.String()
+	}
+
+	return formatAndWriteOutput(pools, ctx.GlobalFlags.Format, ctx.GlobalFlags.Output, "name,engine,source", csvFormatter, tableFormatter)
+}
+
+// configuredPools returns the pools of the priority presets and of the
+// allowed_pools query policy
+func configuredPools(config *Config) []resourcePool {
+	pools := []resourcePool{}
+	for name, preset := range config.PriorityPresets {
+		if preset.PoolName != "" {
+			pools = append(pools, resourcePool{Name: preset.PoolName, Source: "preset " + name})
+		}
+	}
+	if config.QueryPolicy != nil {
+		for _, name := range config.QueryPolicy.AllowedPools {
+			pools = append(pools, resourcePool{Name: name, Source: "allowed_pools"})
+		}
+	}
+	return pools
+}
+
+// parentSegmentPools returns the pools CDP parent segments run their
+// workflows in
+func parentSegmentPools(ctx context.Context, client *td.Client) ([]resourcePool, error) {
+	resp, err := client.CDP.ListAudiences(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list parent segments: %w", err)
+	}
+
+	var pools []resourcePool
+	for _, audience := range resp.Audiences {
+		source := fmt.Sprintf("parent segment %s (%s)", audience.Name, audience.ID)
+		if audience.HivePoolName != nil && *audience.HivePoolName != "" {
+			pools = append(pools, resourcePool{Name: *audience.HivePoolName, Engine: "hive", Source: source})
+		}
+		if audience.PrestoPoolName != nil && *audience.PrestoPoolName != "" {
+			pools = append(pools, resourcePool{Name: *audience.PrestoPoolName, Engine: "trino", Source: source})
+		}
+	}
+	return pools, nil
+}
+
+func sortPools(pools []resourcePool) {
+	sort.Slice(pools, func(i, j int) bool {
+		if pools[i].Name != pools[j].Name {
+			return pools[i].Name < pools[j].Name
+		}
+		return pools[i].Source < pools[j].Source
+	})
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+func TestConfiguredPools(t *testing.T) {
+	config := &Config{
+		PriorityPresets: map[string]td.PriorityPreset{
+			"heavy": {Priority: 0, PoolName: "hive_batch"},
+			"quick": {Priority: 1},
+		},
+		QueryPolicy: &QueryPolicyConfig{AllowedPools: []string{"hive_batch", "adhoc"}},
+	}
+
+	pools := configuredPools(config)
+	sortPools(pools)
+	want := "[{adhoc  allowed_pools} {hive_batch  allowed_pools} {hive_batch  preset heavy}]"
+	if got := fmt.Sprint(pools); got != want {
+		t.Errorf("configuredPools = %s, want %s", got, want)
+	}
+
+	if pools := configuredPools(&Config{}); len(pools) != 0 {
+		t.Errorf("configuredPools of an empty config = %v", pools)
+	}
+}
+
+func TestParentSegmentPools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/audiences" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `[{"id":"1","name":"customers","hivePoolName":"cdp_hive","prestoPoolName":null},{"id":"2","name":"leads","hivePoolName":"","prestoPoolName":"cdp_trino"}]`)
+	}))
+	defer server.Close()
+
+	client, err := td.NewClient("1/test", td.WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.CDPURL = client.BaseURL
+
+	pools, err := parentSegmentPools(context.Background(), client)
+	if err != nil {
+		t.Fatalf("parentSegmentPools returned error: %v", err)
+	}
+	want := "[{cdp_hive hive parent segment customers (1)} {cdp_trino trino parent segment leads (2)}]"
+	if got := fmt.Sprint(pools); got != want {
+		t.Errorf("parentSegmentPools = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// newCLIClient creates a client with the connection settings shared by all
+// commands: region, TLS options and configured query policies
+func newCLIClient(apiKey, region string, ssl td.SSLOptions, config *Config, extra ...td.ClientOption) (*td.Client, error) {
+	clientOptions := []td.ClientOption{}
+	if region != "" {
+		clientOptions = append(clientOptions, td.WithRegion(region))
+	}
+
+	// Apply SSL options if any are configured
+	if ssl.InsecureSkipVerify || ssl.CertFile != "" || ssl.KeyFile != "" || ssl.CAFile != "" {
+		clientOptions = append(clientOptions, td.WithSSLOptions(ssl))
+	}
+
+	if config != nil {
+		if policies := config.QueryPolicy.Policies(); len(policies) > 0 {
+			clientOptions = append(clientOptions, td.WithQueryPolicy(policies...))
+		}
+	}
+
+	return td.NewClient(apiKey, append(clientOptions, extra...)...)
+}
+
+// profileClient returns a client for the named profile. An empty name
+// returns fallback, the client built from the global flags.
+func profileClient(config *Config, name string, fallback *td.Client) (*td.Client, error) {
+	if name == "" {
+		if fallback == nil {
+			return nil, fmt.Errorf("API key required: set --api-key or choose a profile")
+		}
+		return fallback, nil
+	}
+
+	if config == nil {
+		return nil, fmt.Errorf("profile %q not found", name)
+	}
+	profile, ok := config.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found; add a [profiles.%s] section to the config file", name, name)
+	}
+	if !isValidAPIKey(profile.APIKey) {
+		return nil, fmt.Errorf("profile %q has no valid api_key (format: account_id/api_key)", name)
+	}
+
+	region := profile.Region
+	if region == "" {
+		region = "us"
+	}
+	return newCLIClient(profile.APIKey, region, td.SSLOptions{
+		InsecureSkipVerify: profile.InsecureSkipVerify,
+		CertFile:           profile.CertFile,
+		KeyFile:            profile.KeyFile,
+		CAFile:             profile.CAFile,
+	}, config)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	progressBarWidth    = 30
+	progressBarInterval = 200 * time.Millisecond
+)
+
+// progressBar draws the progress of a download on a single, updating line,
+// at most a few times a second. Its Update method is a td.ProgressFunc.
+type progressBar struct {
+	mu          sync.Mutex
+	w           io.Writer
+	label       string
+	done, total int64
+	last        time.Time
+	// width is the length of the line drawn last, to clear what a shorter
+	// line leaves behind; zero when no line is open
+	width int
+}
+
+func newProgressBar(w io.Writer, label string) *progressBar {
+	return &progressBar{w: w, label: label, total: -1}
+}
+
+// newTerminalProgressBar draws on stderr only when it is a terminal, for
+// downloads where progress is a convenience rather than the output
+func newTerminalProgressBar(label string) *progressBar {
+	if !isTerminal(os.Stderr) {
+		return newProgressBar(io.Discard, label)
+	}
+	return newProgressBar(os.Stderr, label)
+}
+
+// Update records that done of total bytes were downloaded; total is -1 when
+// unknown. The line ends once done reaches a known total, so output that
+// follows the download starts on a new line.
+func (p *progressBar) Update(done, total int64) {
+	p.mu.Lock()
+	p.done, p.total = done, total
+	complete := total > 0 && done >= total
+	if now := time.Now(); now.Sub(p.last) >= progressBarInterval && !complete {
+		p.last = now
+		p.draw()
+	}
+	p.mu.Unlock()
+	if complete {
+		p.Done()
+	}
+}
+
+// Done draws the final state and ends the line. Calling it again, or
+// before any progress, does nothing.
+func (p *progressBar) Done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.done == 0 && p.width == 0 {
+		return
+	}
+	p.draw()
+	fmt.Fprintln(p.w)
+	p.done, p.width = 0, 0
+}
+
+func (p *progressBar) draw() {
+	line := progressLine(p.label, p.done, p.total)
+	pad := ""
+	if len(line) < p.width {
+		pad = strings.Repeat(" ", p.width-len(line))
+	}
+	fmt.Fprintf(p.w, "\r%s%s", line, pad)
+	p.width = len(line)
+}
+
+// progressLine formats a progress line such as
+// "out.csv [=======>      ]  52% 1.0 MB / 2.0 MB"
+func progressLine(label string, done, total int64) string {
+	if total <= 0 {
+		return fmt.Sprintf("%s %s", label, formatBytes(done))
+	}
+	fraction := min(float64(done)/float64(total), 1)
+	filled := int(fraction * progressBarWidth)
+	bar := strings.Repeat("=", filled)
+	if filled < progressBarWidth {
+		bar += ">" + strings.Repeat(" ", progressBarWidth-filled-1)
+	}
+	return fmt.Sprintf("%s [%s] %3.0f%% %s / %s", label, bar, fraction*100, formatBytes(done), formatBytes(total))
+}
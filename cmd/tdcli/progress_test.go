@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestProgressLine(t *testing.T) {
+	tests := []struct {
+		done, total int64
+		want        string
+	}{
+		{0, 2048, "out.csv [>                             ]   0% 0 B / 2.0 KB"},
+		{1024, 2048, "out.csv [===============>              ]  50% 1.0 KB / 2.0 KB"},
+		{2048, 2048, "out.csv [==============================] 100% 2.0 KB / 2.0 KB"},
+		{1536, -1, "out.csv 1.5 KB"},
+	}
+	for _, tt := range tests {
+		if got := progressLine("out.csv", tt.done, tt.total); got != tt.want {
+			t.Errorf("progressLine(%d, %d) = %q, want %q", tt.done, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	var buf bytes.Buffer
+	bar := newProgressBar(&buf, "out")
+	bar.Done()
+	if buf.Len() != 0 {
+		t.Errorf("Done before any progress wrote %q", buf.String())
+	}
+
+	// The first update draws, the next ones within the interval do not, and
+	// reaching the total ends the line
+	bar.Update(10, 30)
+	bar.Update(20, 30)
+	bar.Update(30, 30)
+	bar.Done()
+	want := "\r" + progressLine("out", 10, 30) + "\r" + progressLine("out", 30, 30) + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("drew %q, want %q", got, want)
+	}
+
+	// Without a total, Done ends the line
+	buf.Reset()
+	bar = newProgressBar(&buf, "out")
+	bar.Update(5, -1)
+	bar.Done()
+	if got := buf.String(); got != "\rout 5 B\rout 5 B\n" {
+		t.Errorf("drew %q", got)
+	}
+}
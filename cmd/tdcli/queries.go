@@ -2,16 +2,35 @@ package main
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	td "github.com/mickeey2525/treasuredata-go-sdk"
+	"github.com/mickeey2525/treasuredata-go-sdk/querytemplate"
 )
 
+// renderQueryVars renders query as a template when --var values are given,
+// quoting them for the engine it runs on. Without them the query is used
+// verbatim, so literal "{{" needs no escaping.
+func renderQueryVars(query string, vars map[string]string, queryType td.QueryType) (string, error) {
+	if len(vars) == 0 {
+		return query, nil
+	}
+	rendered, err := querytemplate.RenderStringsFor(queryType, query, vars)
+	if err != nil {
+		return "", fmt.Errorf("query template: %w", err)
+	}
+	return rendered, nil
+}
+
 func handleQueryCommands(ctx context.Context, client *td.Client, args []string, flags Flags) {
 	if len(args) == 0 || args[0] == "help" {
 		printQueryUsage()
@@ -27,7 +46,7 @@ func handleQueryCommands(ctx context.Context, client *td.Client, args []string,
 	case "status":
 		handleQueryStatus(ctx, client, subArgs, flags)
 	case "result", "results":
-		handleQueryResult(ctx, client, subArgs, flags)
+		handleQueryResult(ctx, client, subArgs, false, flags)
 	case "list", "ls":
 		handleQueryList(ctx, client, flags)
 	case "cancel":
@@ -53,31 +72,66 @@ SUBCOMMANDS:
     result, results <job_id> Get query results
     list, ls               List recent queries
     cancel <job_id>        Cancel a running query
+    estimate <query>       Estimate the data a Trino query would scan
 
 OPTIONS:
     --database DATABASE    Database to run query against (required for submit)
     --engine ENGINE        Query engine: trino (default) or hive
-    --priority PRIORITY    Query priority (0-2, default: 0)
-    --result-url URL       Result output URL
+    --priority PRIORITY    Query priority (-2 to 2, default: 0)
+    --preset NAME          Priority preset (interactive, batch, backfill, or from config)
+    --pool-name NAME       Resource pool to run the query in
+    --engine-version VER   Engine version (stable or experimental for Hive)
+    --domain-key KEY       Reuse the job already submitted with this key
+    --result-url URL       Write results to a URL (td://@/db/table, s3://...)
+    --result-connection NAME  Write results through a saved result connection
     --type TYPE            Result format type
     --wait                 Wait for query completion
     --timeout SECONDS      Wait timeout in seconds (default: 300)
     --format FORMAT        Output format (json, table, csv)
     --limit LIMIT          Limit number of result rows
+    --fail-on-full-scan    Fail estimate when a table has no bounded time range
     --verbose, -v          Verbose output
 
 EXAMPLES:
     tdcli q submit "SELECT COUNT(*) FROM my_table" --database my_db
     tdcli q submit "SELECT * FROM users LIMIT 10" --database analytics --wait
+    tdcli q submit "SELECT COUNT(1) FROM events" --database analytics --engine hive --engine-version experimental --pool-name batch
+    tdcli q submit "SELECT * FROM users" --database analytics --result-url "td://@/reports/users?mode=replace"
     tdcli q status 12345
     tdcli q result 12345 --format csv
     tdcli q list
     tdcli q cancel 12345
+    tdcli q estimate "SELECT * FROM events WHERE TD_TIME_RANGE(time, '2025-01-01', '2025-01-02')" --database analytics
 
 `)
 }
 
+// querySubmitOptions holds submit options that are not global flags
+type querySubmitOptions struct {
+	// ResultURL is a result output URL such as td://@/db/table or s3://...
+	ResultURL string
+	// ResultConnection is the name of a saved result connection
+	ResultConnection string
+	// Preset supplies priority, retry limit and pool; --priority overrides it
+	Preset *td.PriorityPreset
+	// Wait waits for the job through Queries.SubmitAndWait
+	Wait bool
+	// Timeout is the wait timeout in seconds; zero uses TD_TIMEOUT or 300
+	Timeout int
+	// PoolName is the resource pool; it overrides the preset pool
+	PoolName string
+	// EngineVersion selects the engine version, e.g. stable or experimental
+	// for Hive
+	EngineVersion string
+	// DomainKey makes resubmitting reuse the job issued with the same key
+	DomainKey string
+}
+
 func handleQuerySubmit(ctx context.Context, client *td.Client, args []string, flags Flags) {
+	handleQuerySubmitWithOptions(ctx, client, args, querySubmitOptions{}, flags)
+}
+
+func handleQuerySubmitWithOptions(ctx context.Context, client *td.Client, args []string, submitOpts querySubmitOptions, flags Flags) {
 	if len(args) == 0 {
 		fmt.Println("Error: Query string required")
 		fmt.Println("Usage: tdcli q submit \"<query>\" --database <database>")
@@ -120,26 +174,77 @@ func handleQuerySubmit(ctx context.Context, client *td.Client, args []string, fl
 	opts := &td.IssueQueryOptions{
 		Query: query,
 	}
-	if flags.Priority > 0 {
+	if submitOpts.Preset != nil {
+		opts.ApplyPreset(*submitOpts.Preset)
+	}
+	if flags.Priority != 0 {
 		opts.Priority = flags.Priority
 	}
+	if submitOpts.PoolName != "" {
+		opts.PoolName = submitOpts.PoolName
+	}
+	opts.EngineVersion = submitOpts.EngineVersion
+	opts.DomainKey = submitOpts.DomainKey
+
+	switch {
+	case submitOpts.ResultURL != "" && submitOpts.ResultConnection != "":
+		fmt.Println("Error: --result-url and --result-connection cannot be used together")
+		os.Exit(1)
+	case submitOpts.ResultURL != "":
+		opts.Result = submitOpts.ResultURL
+	case submitOpts.ResultConnection != "":
+		err := opts.SetResultOutput(td.ResultToConnection{Name: submitOpts.ResultConnection})
+		handleError(err, "Invalid result connection", flags.Verbose)
+	}
 
 	if flags.Verbose {
 		fmt.Printf("Submitting query to database: %s\n", database)
 		fmt.Printf("Query engine: %s\n", engine)
+		if opts.EngineVersion != "" {
+			fmt.Printf("Engine version: %s\n", opts.EngineVersion)
+		}
+		if opts.PoolName != "" {
+			fmt.Printf("Resource pool: %s\n", opts.PoolName)
+		}
 		fmt.Printf("Query: %s\n", query)
+		if opts.Result != "" {
+			fmt.Printf("Result output: %s\n", redactResultURL(opts.Result))
+		}
 	}
 
-	job, err := client.Queries.Issue(ctx, engine, database, opts)
-	handleError(err, "Failed to submit query", flags.Verbose)
+	if !submitOpts.Wait && os.Getenv("TD_WAIT") != "true" && !containsFlag(os.Args, "--wait") {
+		job, err := client.Queries.Issue(ctx, engine, database, opts)
+		handleError(err, "Failed to submit query", flags.Verbose)
 
-	fmt.Printf("Query submitted successfully\n")
-	fmt.Printf("Job ID: %s\n", job.JobID)
+		if job.Existing {
+			fmt.Printf("A job with domain key %s was already submitted\n", opts.DomainKey)
+		} else {
+			fmt.Printf("Query submitted successfully\n")
+		}
+		fmt.Printf("Job ID: %s\n", job.JobID)
+		return
+	}
 
-	// If wait flag is set, wait for completion
-	if os.Getenv("TD_WAIT") == "true" || containsFlag(os.Args, "--wait") {
-		handleQueryWait(ctx, client, job.JobID, flags)
+	timeout := queryWaitTimeout(submitOpts.Timeout)
+	result, err := client.Queries.SubmitAndWait(ctx, td.SubmitRequest{
+		Type:     engine,
+		Database: database,
+		Options:  *opts,
+		Wait:     queryWaitOptions(timeout, flags),
+		OnSubmit: func(jobID string) {
+			fmt.Printf("Query submitted successfully\n")
+			fmt.Printf("Job ID: %s\n", jobID)
+			fmt.Printf("Waiting for job %s to complete (timeout: %s)...\n", jobID, timeout)
+		},
+	})
+	var failed *td.JobFailedError
+	if err != nil && !errors.As(err, &failed) {
+		handleError(err, "Failed to wait for query", flags.Verbose)
+	}
+	if result.Existing {
+		fmt.Printf("Job %s was submitted earlier with domain key %s\n", result.Job.JobID, opts.DomainKey)
 	}
+	printJobOutcome(result.Job, flags)
 }
 
 func handleQueryStatus(ctx context.Context, client *td.Client, args []string, flags Flags) {
@@ -162,7 +267,9 @@ func handleQueryStatus(ctx context.Context, client *td.Client, args []string, fl
 	}
 }
 
-func handleQueryResult(ctx context.Context, client *td.Client, args []string, flags Flags) {
+// handleQueryResult prints a job's results. With types, CSV results start with
+// a line of column names and a line of column types.
+func handleQueryResult(ctx context.Context, client *td.Client, args []string, types bool, flags Flags) {
 	if len(args) == 0 {
 		fmt.Println("Error: Job ID required")
 		fmt.Println("Usage: tdcli q result <job_id>")
@@ -183,14 +290,15 @@ func handleQueryResult(ctx context.Context, client *td.Client, args []string, fl
 		return
 	}
 
-	// Get results
-	format := "json" // Default format for API
-	if flags.Format == "csv" {
-		format = "csv"
+	// CSV and JSON are streamed as they arrive; only the table is buffered
+	if flags.Format == "csv" || flags.Format == "json" {
+		err := streamQueryResult(ctx, client, job, types, flags, os.Stdout, os.Stderr)
+		handleError(err, "Failed to get query results", flags.Verbose)
+		return
 	}
 
 	opts := &td.GetResultOptions{
-		Format: td.ResultFormat(format),
+		Format: td.ResultFormatJSON,
 	}
 	if flags.Limit > 0 {
 		opts.Limit = flags.Limit
@@ -199,24 +307,164 @@ func handleQueryResult(ctx context.Context, client *td.Client, args []string, fl
 	handleError(err, "Failed to get query results", flags.Verbose)
 	defer resultReader.Close()
 
-	// Read all results
 	resultsBytes, err := io.ReadAll(resultReader)
 	handleError(err, "Failed to read query results", flags.Verbose)
-	results := string(resultsBytes)
+	printQueryResultsTable(string(resultsBytes), flags.Limit)
+}
 
-	switch flags.Format {
-	case "json":
-		printJSON(results)
-	case "csv":
-		fmt.Print(results)
-	default:
-		// Try to format as table if it's JSON
-		if format == "json" {
-			printQueryResultsTable(results, flags.Limit)
-		} else {
-			fmt.Print(results)
+// streamQueryResult copies a finished job's results to --output, or to
+// stdout, as they are downloaded: CSV with --format csv and one JSON object
+// per line with --format json. A limit of 0 writes every row. Writing to a
+// file shows the download's progress on stderr.
+func streamQueryResult(ctx context.Context, client *td.Client, job *td.Job, types bool, flags Flags, stdout, stderr io.Writer) error {
+	format := td.ResultFormatJSONL
+	if flags.Format == "csv" {
+		format = td.ResultFormatCSV
+	}
+	var reqOpts []td.RequestOption
+	if flags.Output != "" {
+		bar := newProgressBar(stderr, flags.Output)
+		defer bar.Done()
+		reqOpts = append(reqOpts, td.WithProgress(bar.Update))
+	}
+	body, err := client.Results.GetResult(ctx, job.JobID, &td.GetResultOptions{Format: format, Limit: flags.Limit}, reqOpts...)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	out := stdout
+	var file *os.File
+	if flags.Output != "" {
+		if file, err = os.Create(flags.Output); err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if types && format == td.ResultFormatCSV {
+		columns, err := job.ResultSchema()
+		if err != nil {
+			return fmt.Errorf("failed to read the result schema: %w", err)
+		}
+		if _, err := io.WriteString(out, csvSchemaHeader(columns)); err != nil {
+			return err
+		}
+	}
+	if _, err := io.Copy(out, body); err != nil {
+		return fmt.Errorf("failed to read query results: %w", err)
+	}
+	if file != nil {
+		return file.Close()
+	}
+	return nil
+}
+
+// csvSchemaHeader returns a CSV line of the column names followed by a line of
+// their types, for the header-less CSV results of the API
+func csvSchemaHeader(columns []td.TableColumn) string {
+	if len(columns) == 0 {
+		return ""
+	}
+	var buf strings.Builder
+	w := csv.NewWriter(&buf)
+	names := make([]string, len(columns))
+	types := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+		types[i] = column.Type
+	}
+	w.Write(names)
+	w.Write(types)
+	w.Flush()
+	return buf.String()
+}
+
+// downloadQueryResult writes a job's results as served in format to
+// --output, or to stdout. Binary formats are not written to a terminal.
+// With resume, an interrupted download to --output continues where it
+// stopped. With parallel above 1, that many ranges are fetched at once.
+func downloadQueryResult(ctx context.Context, client *td.Client, jobID string, format td.ResultFormat, resume bool, parallel int, flags Flags) error {
+	if resume && (flags.Output == "" || flags.Limit > 0) {
+		return errors.New("--resume needs --output and cannot be combined with --limit")
+	}
+	if parallel > 1 && (resume || flags.Limit > 0) {
+		return errors.New("--parallel cannot be combined with --resume or --limit")
+	}
+	job, err := client.Jobs.Get(ctx, jobID)
+	if err != nil {
+		return fmt.Errorf("failed to get job status: %w", err)
+	}
+	if job.Status != string(td.JobStateSuccess) {
+		return fmt.Errorf("job %s has no results: status %s", jobID, job.Status)
+	}
+
+	// Progress is shown only when stdout is free of the results
+	var stderr io.Writer = io.Discard
+	if flags.Output != "" {
+		stderr = os.Stderr
+	}
+	bar := newProgressBar(stderr, flags.Output)
+	defer bar.Done()
+	progress := []td.RequestOption{td.WithProgress(bar.Update)}
+
+	// Whole results go through a checkpointed download; limited ones are small
+	if flags.Output != "" && flags.Limit == 0 && parallel <= 1 {
+		reqOpts := append([]td.RequestOption{td.WithResultFormat(format)}, progress...)
+		if resume {
+			reqOpts = append(reqOpts, td.WithResume())
+		}
+		n, err := client.Jobs.DownloadResult(ctx, jobID, flags.Output, reqOpts...)
+		if err != nil {
+			if resume {
+				return fmt.Errorf("%w (run again to resume)", err)
+			}
+			return err
 		}
+		bar.Done()
+		fmt.Fprintf(os.Stderr, "Wrote %d bytes of %s results to %s\n", n, format, flags.Output)
+		return nil
 	}
+
+	var out io.Writer = os.Stdout
+	if flags.Output != "" {
+		file, err := os.Create(flags.Output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	} else if format.Binary() && isTerminal(os.Stdout) {
+		return fmt.Errorf("%s results are binary; use --output or redirect stdout", format)
+	}
+	if parallel > 1 {
+		n, err := client.Jobs.DownloadResultParallel(ctx, jobID, out, parallel, append(progress, td.WithResultFormat(format))...)
+		if err != nil {
+			return fmt.Errorf("failed to download query results: %w", err)
+		}
+		if flags.Output != "" {
+			bar.Done()
+			fmt.Fprintf(os.Stderr, "Wrote %d bytes of %s results to %s\n", n, format, flags.Output)
+		}
+		return nil
+	}
+	body, err := client.Results.GetResult(ctx, jobID, &td.GetResultOptions{Format: format, Limit: flags.Limit}, progress...)
+	if err != nil {
+		return fmt.Errorf("failed to get query results: %w", err)
+	}
+	defer body.Close()
+
+	if _, err := io.Copy(out, body); err != nil {
+		return fmt.Errorf("failed to download query results: %w", err)
+	}
+	return nil
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
 
 func handleQueryList(ctx context.Context, client *td.Client, flags Flags) {
@@ -263,58 +511,67 @@ func handleQueryCancel(ctx context.Context, client *td.Client, args []string, fl
 }
 
 func handleQueryWait(ctx context.Context, client *td.Client, jobID string, flags Flags) {
-	timeout := 300 // Default 5 minutes
-	if timeoutEnv := os.Getenv("TD_TIMEOUT"); timeoutEnv != "" {
-		if t, err := strconv.Atoi(timeoutEnv); err == nil {
-			timeout = t
-		}
-	}
+	timeout := queryWaitTimeout(0)
+	fmt.Printf("Waiting for job %s to complete (timeout: %s)...\n", jobID, timeout)
 
-	fmt.Printf("Waiting for job %s to complete (timeout: %ds)...\n", jobID, timeout)
-
-	start := time.Now()
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
+	if _, err := client.Jobs.WaitForCompletion(ctx, jobID, queryWaitOptions(timeout, flags)); err != nil {
+		fmt.Printf("Error waiting for job %s: %v\n", jobID, err)
+		return
+	}
+	job, err := client.Jobs.Get(ctx, jobID)
+	if err != nil {
+		fmt.Printf("Error checking job status: %v\n", err)
+		return
+	}
+	printJobOutcome(job, flags)
+}
 
-	for {
-		select {
-		case <-ctx.Done():
-			fmt.Println("Context cancelled")
-			return
-		case <-ticker.C:
-			if time.Since(start).Seconds() > float64(timeout) {
-				fmt.Printf("Timeout waiting for job %s\n", jobID)
-				return
+// queryWaitTimeout returns seconds as a duration, falling back to TD_TIMEOUT
+// and then to 5 minutes
+func queryWaitTimeout(seconds int) time.Duration {
+	if seconds <= 0 {
+		seconds = 300
+		if timeoutEnv := os.Getenv("TD_TIMEOUT"); timeoutEnv != "" {
+			if t, err := strconv.Atoi(timeoutEnv); err == nil {
+				seconds = t
 			}
+		}
+	}
+	return time.Duration(seconds) * time.Second
+}
 
-			job, err := client.Jobs.Get(ctx, jobID)
-			if err != nil {
-				fmt.Printf("Error checking job status: %v\n", err)
-				return
+// queryWaitOptions polls every 2 seconds, printing each status in verbose mode
+func queryWaitOptions(timeout time.Duration, flags Flags) td.WaitOptions {
+	opts := td.WaitOptions{
+		PollInterval:    2 * time.Second,
+		MaxPollInterval: 10 * time.Second,
+		MaxWait:         timeout,
+	}
+	if flags.Verbose {
+		opts.OnStatus = func(status *td.JobStatus) {
+			if !status.State().Terminal() {
+				fmt.Printf("Job %s status: %s\n", status.JobID, status.Status)
 			}
+		}
+	}
+	return opts
+}
 
-			switch job.Status {
-			case "success":
-				fmt.Printf("Job %s completed successfully\n", jobID)
-				if flags.Verbose {
-					printJobDetails(*job)
-				}
-				return
-			case "error":
-				fmt.Printf("Job %s failed\n", jobID)
-				if job.Debug != nil && job.Debug.Stderr != "" {
-					fmt.Printf("Error: %s\n", job.Debug.Stderr)
-				}
-				return
-			case "killed":
-				fmt.Printf("Job %s was cancelled\n", jobID)
-				return
-			default:
-				if flags.Verbose {
-					fmt.Printf("Job %s status: %s\n", jobID, job.Status)
-				}
-			}
+// printJobOutcome reports how a finished job ended
+func printJobOutcome(job *td.Job, flags Flags) {
+	switch td.JobState(job.Status) {
+	case td.JobStateSuccess:
+		fmt.Printf("Job %s completed successfully\n", job.JobID)
+		if flags.Verbose {
+			printJobDetails(*job)
 		}
+	case td.JobStateError:
+		fmt.Printf("Job %s failed\n", job.JobID)
+		if job.Debug != nil && job.Debug.Stderr != "" {
+			fmt.Printf("Error: %s\n", job.Debug.Stderr)
+		}
+	case td.JobStateKilled:
+		fmt.Printf("Job %s was cancelled\n", job.JobID)
 	}
 }
 
@@ -337,3 +594,24 @@ func containsFlag(args []string, flag string) bool {
 	}
 	return false
 }
+
+// redactResultURL hides credentials embedded in a result URL, or in the
+// JSON settings of an output connector
+func redactResultURL(resultURL string) string {
+	if strings.HasPrefix(resultURL, "{") {
+		var settings map[string]interface{}
+		if err := json.Unmarshal([]byte(resultURL), &settings); err != nil {
+			return "{...}"
+		}
+		data, err := json.Marshal(redactSettings(settings))
+		if err != nil {
+			return "{...}"
+		}
+		return string(data)
+	}
+	u, err := url.Parse(resultURL)
+	if err != nil || u.User == nil {
+		return resultURL
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+func TestRenderQueryVars(t *testing.T) {
+	query := `SELECT * FROM {{tdIdentifier .table}} WHERE name = {{tdString .name}}`
+
+	got, err := renderQueryVars(query, map[string]string{"table": "users", "name": "O'Brien"}, td.QueryTypeTrino)
+	if err != nil || got != `SELECT * FROM "users" WHERE name = 'O''Brien'` {
+		t.Errorf("renderQueryVars = %q, %v", got, err)
+	}
+
+	// --engine hive quotes for Hive, where a trailing backslash would
+	// otherwise escape the closing quote
+	got, err = renderQueryVars(query, map[string]string{"table": "users", "name": `x\`}, td.QueryTypeHive)
+	if err != nil || got != "SELECT * FROM `users` WHERE name = 'x\\\\'" {
+		t.Errorf("renderQueryVars for hive = %q, %v", got, err)
+	}
+
+	// Without variables the query is not treated as a template
+	if got, err := renderQueryVars(query, nil, td.QueryTypeTrino); err != nil || got != query {
+		t.Errorf("renderQueryVars without vars = %q, %v", got, err)
+	}
+
+	if _, err := renderQueryVars(`SELECT * FROM {{.table}}`, map[string]string{"table": "users"}, td.QueryTypeTrino); err == nil {
+		t.Error("Expected error for an unquoted variable")
+	}
+}
+
+func TestCSVSchemaHeader(t *testing.T) {
+	columns := []td.TableColumn{{Name: "id", Type: "bigint"}, {Name: "tags", Type: "map(varchar, array(varchar))"}}
+	want := "id,tags\nbigint,\"map(varchar, array(varchar))\"\n"
+	if got := csvSchemaHeader(columns); got != want {
+		t.Errorf("csvSchemaHeader = %q, want %q", got, want)
+	}
+	if got := csvSchemaHeader(nil); got != "" {
+		t.Errorf("csvSchemaHeader(nil) = %q, want empty", got)
+	}
+}
+
+func TestDownloadQueryResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/job/show/1":
+			w.Write([]byte(`{"job_id": "1", "status": "success"}`))
+		case "/v3/job/show/2":
+			w.Write([]byte(`{"job_id": "2", "status": "running"}`))
+		case "/v3/job/result/1":
+			if got := r.URL.Query().Get("format"); got != "msgpack.gz" {
+				t.Errorf("format = %q", got)
+			}
+			w.Write([]byte("\x1f\x8b\x08packed"))
+		default:
+			t.Errorf("Unexpected request path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := td.NewClient("1/test", td.WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := filepath.Join(t.TempDir(), "result.msgpack.gz")
+	if err := downloadQueryResult(context.Background(), client, "1", td.ResultFormatMessagePackGzip, false, 0, Flags{Output: output}); err != nil {
+		t.Fatalf("downloadQueryResult returned error: %v", err)
+	}
+	if data, _ := os.ReadFile(output); string(data) != "\x1f\x8b\x08packed" {
+		t.Errorf("downloaded %q", data)
+	}
+
+	if err := downloadQueryResult(context.Background(), client, "2", td.ResultFormatCSV, false, 0, Flags{Output: output}); err == nil {
+		t.Error("Expected error for a job without results")
+	}
+}
+
+func TestStreamQueryResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/job/result/1" {
+			t.Errorf("Unexpected request path: %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("limit"); got != "" {
+			t.Errorf("limit = %q, want none", got)
+		}
+		switch r.URL.Query().Get("format") {
+		case "csv":
+			w.Write([]byte("1,alice\n2,bob\n"))
+		case "jsonl":
+			w.Write([]byte(`{"id":1,"name":"alice"}` + "\n"))
+		default:
+			t.Errorf("format = %q", r.URL.Query().Get("format"))
+		}
+	}))
+	defer server.Close()
+
+	client, err := td.NewClient("1/test", td.WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := &td.Job{JobID: "1", HiveResultSchema: `[["id","bigint"],["name","varchar"]]`}
+
+	var stdout, stderr bytes.Buffer
+	if err := streamQueryResult(context.Background(), client, job, false, Flags{Format: "json"}, &stdout, &stderr); err != nil {
+		t.Fatalf("streamQueryResult returned error: %v", err)
+	}
+	if got := stdout.String(); got != `{"id":1,"name":"alice"}`+"\n" {
+		t.Errorf("stdout = %q", got)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("stderr = %q, want nothing when writing to stdout", stderr.String())
+	}
+
+	output := filepath.Join(t.TempDir(), "result.csv")
+	stdout.Reset()
+	if err := streamQueryResult(context.Background(), client, job, true, Flags{Format: "csv", Output: output}, &stdout, &stderr); err != nil {
+		t.Fatalf("streamQueryResult returned error: %v", err)
+	}
+	if data, _ := os.ReadFile(output); string(data) != "id,name\nbigint,varchar\n1,alice\n2,bob\n" {
+		t.Errorf("wrote %q", data)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("stdout = %q, want nothing when writing to a file", stdout.String())
+	}
+	if !strings.HasSuffix(stderr.String(), "100% 14 B / 14 B\n") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
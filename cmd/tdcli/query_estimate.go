@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+type QueryEstimateCmd struct {
+	Query    string            `kong:"arg,help='Trino query to estimate'"`
+	Database string            `kong:"required,help='Database to run query against'"`
+	Timeout  int               `kong:"help='Wait timeout in seconds',default=300,env='TD_TIMEOUT'"`
+	Var      map[string]string `kong:"placeholder='KEY=VALUE',help='Render the query as a template with this variable'"`
+
+	FailOnFullScan bool `kong:"name='fail-on-full-scan',help='Exit with an error when a table is read without a bounded time range'"`
+}
+
+func (q *QueryEstimateCmd) Run(ctx *CLIContext) error {
+	query, err := renderQueryVars(q.Query, q.Var, td.QueryTypeTrino)
+	if err != nil {
+		return err
+	}
+	opts := queryWaitOptions(queryWaitTimeout(q.Timeout), ctx.GlobalFlags)
+	estimate, err := ctx.Client.Queries.EstimateScan(ctx.Context, q.Database, query, opts)
+	if err != nil {
+		return fmt.Errorf("failed to estimate query: %w", err)
+	}
+	if err := writeQueryEstimate(ctx, estimate); err != nil {
+		return err
+	}
+	if full := estimate.FullScans(); q.FailOnFullScan && len(full) > 0 {
+		return fmt.Errorf("%d table(s) would be fully scanned", len(full))
+	}
+	return nil
+}
+
+func writeQueryEstimate(ctx *CLIContext, estimate *td.QueryEstimate) error {
+	csvFormatter := func(data interface{}) string {
+		e := data.(*td.QueryEstimate)
+		var sb strings.Builder
+		for _, t := range e.Tables {
+			sb.WriteString(fmt.Sprintf("%s,%s,%s,%s,%q,%d,%t\n", t.Database, t.Table,
+				estimateValue(t, t.Rows), estimateValue(t, t.Bytes), formatTimeRanges(t.TimeRanges), t.Partitions, t.FullScan))
+		}
+		return sb.String()
+	}
+
+	tableFormatter := func(data interface{}) string {
+		e := data.(*td.QueryEstimate)
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("%-40s %12s %10s %-42s %s\n", "TABLE", "ROWS", "SIZE", "TIME RANGE", "PARTITIONS"))
+		for _, t := range e.Tables {
+			size := "unknown"
+			if t.Known {
+				size = formatBytes(t.Bytes)
+			}
+			partitions := fmt.Sprint(t.Partitions)
+			if t.FullScan {
+				partitions = "all"
+			}
+			sb.WriteString(fmt.Sprintf("%-40s %12s %10s %-42s %s\n", t.Database+"."+t.Table,
+				estimateValue(t, t.Rows), size, formatTimeRanges(t.TimeRanges), partitions))
+		}
+
+		total := formatBytes(e.Bytes)
+		if e.Unknown {
+			total = "at least " + total
+		}
+		sb.WriteString(fmt.Sprintf("\nEstimated scan: %s, %d rows, %d partition(s)\n", total, e.Rows, e.Partitions))
+		for _, t := range e.FullScans() {
+			sb.WriteString(fmt.Sprintf("Warning: %s.%s has no bounded time range and would be fully scanned\n", t.Database, t.Table))
+		}
+		return sb.String()
+	}
+
+	return formatAndWriteOutput(estimate, ctx.GlobalFlags.Format, ctx.GlobalFlags.Output,
+		"database,table,rows,bytes,time_range,partitions,full_scan", csvFormatter, tableFormatter)
+}
+
+// estimateValue prints a planner estimate, or "unknown" without statistics
+func estimateValue(t td.TableScanEstimate, value int64) string {
+	if !t.Known {
+		return "unknown"
+	}
+	return fmt.Sprint(value)
+}
+
+func formatTimeRanges(ranges []td.TimeRange) string {
+	if len(ranges) == 0 {
+		return "(none)"
+	}
+	bound := func(t *time.Time) string {
+		if t == nil {
+			return "*"
+		}
+		return t.Format("2006-01-02 15:04")
+	}
+	parts := make([]string, len(ranges))
+	for i, r := range ranges {
+		parts[i] = bound(r.From) + " - " + bound(r.To)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// renameFlags are the flags shared by the rename commands
+type renameFlags struct {
+	DryRun  bool
+	Cascade bool
+	Force   bool
+}
+
+// CDPSegmentsRenameCmd renames a segment after listing the activations,
+// funnels and journeys that refer to it
+type CDPSegmentsRenameCmd struct {
+	AudienceID string `kong:"arg,help='Audience ID'"`
+	SegmentID  string `kong:"arg,help='Segment ID'"`
+	NewName    string `kong:"arg,help='New segment name'"`
+	DryRun     bool   `kong:"help='Only list the references, do not rename'"`
+	Cascade    bool   `kong:"help='Also update references that hold the old name where the API allows it'"`
+	Force      bool   `kong:"flag,help='Skip confirmation prompt'"`
+}
+
+func (c *CDPSegmentsRenameCmd) Run(ctx *CLIContext) error {
+	return runRename(ctx, "segment "+c.SegmentID, renameFlags{c.DryRun, c.Cascade, c.Force}, func(opts *td.RenameOptions) (*td.RenameReport, error) {
+		return ctx.Client.CDP.RenameSegment(ctx.Context, c.AudienceID, c.SegmentID, c.NewName, opts)
+	})
+}
+
+// CDPFoldersRenameCmd renames a segment folder after listing the funnels and
+// journeys built on it
+type CDPFoldersRenameCmd struct {
+	AudienceID string `kong:"arg,help='Audience ID'"`
+	FolderID   string `kong:"arg,help='Folder ID'"`
+	NewName    string `kong:"arg,help='New folder name'"`
+	DryRun     bool   `kong:"help='Only list the references, do not rename'"`
+	Cascade    bool   `kong:"help='Also update references that hold the old name where the API allows it'"`
+	Force      bool   `kong:"flag,help='Skip confirmation prompt'"`
+}
+
+func (c *CDPFoldersRenameCmd) Run(ctx *CLIContext) error {
+	return runRename(ctx, "folder "+c.FolderID, renameFlags{c.DryRun, c.Cascade, c.Force}, func(opts *td.RenameOptions) (*td.RenameReport, error) {
+		return ctx.Client.CDP.RenameAudienceFolder(ctx.Context, c.AudienceID, c.FolderID, c.NewName, opts)
+	})
+}
+
+// WorkflowRenameCmd renames a workflow after listing the workflows of its
+// project that call it by name
+type WorkflowRenameCmd struct {
+	WorkflowID int    `kong:"arg,help='Workflow ID'"`
+	NewName    string `kong:"arg,help='New workflow name'"`
+	DryRun     bool   `kong:"help='Only list the references, do not rename'"`
+	Cascade    bool   `kong:"help='Also update references that hold the old name where the API allows it'"`
+	Force      bool   `kong:"flag,help='Skip confirmation prompt'"`
+}
+
+func (w *WorkflowRenameCmd) Run(ctx *CLIContext) error {
+	id := strconv.Itoa(w.WorkflowID)
+	return runRename(ctx, "workflow "+id, renameFlags{w.DryRun, w.Cascade, w.Force}, func(opts *td.RenameOptions) (*td.RenameReport, error) {
+		return ctx.Client.Workflow.RenameWorkflow(ctx.Context, id, w.NewName, opts)
+	})
+}
+
+// runRename lists the references with a dry run of rename, asks for
+// confirmation unless forced, then renames
+func runRename(ctx *CLIContext, what string, flags renameFlags, rename func(*td.RenameOptions) (*td.RenameReport, error)) error {
+	report, err := rename(&td.RenameOptions{DryRun: true})
+	if err != nil {
+		return fmt.Errorf("failed to check references of %s: %w", what, err)
+	}
+
+	if flags.DryRun {
+		return writeRenameReport(ctx, report)
+	}
+	if !flags.Force {
+		if ctx.GlobalFlags.Format != "table" {
+			return fmt.Errorf("--force is required to rename with %s output", ctx.GlobalFlags.Format)
+		}
+		fmt.Printf("Rename %s from %q to %q\n", what, report.OldName, report.NewName)
+		printRenameReferences(report, flags.Cascade)
+		if !promptConfirmation(fmt.Sprintf("Rename %s?", what)) {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	report, err = rename(&td.RenameOptions{Cascade: flags.Cascade})
+	if err != nil {
+		if report != nil && report.Renamed {
+			return fmt.Errorf("renamed %s, but failed to update references: %w", what, err)
+		}
+		return fmt.Errorf("failed to rename %s: %w", what, err)
+	}
+	return writeRenameReport(ctx, report)
+}
+
+func printRenameReferences(report *td.RenameReport, cascade bool) {
+	if len(report.References) == 0 {
+		fmt.Println("No references found")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tID\tNAME\tSTATUS\tDETAIL")
+	for _, ref := range report.References {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", ref.Kind, orNone(ref.ID), ref.Name, renameStatus(ref, cascade), ref.Detail)
+	}
+	w.Flush()
+}
+
+func renameStatus(ref td.RenameReference, cascade bool) string {
+	switch {
+	case ref.Updated:
+		return "updated"
+	case !ref.Breaks:
+		return "ok"
+	case cascade && ref.CanCascade():
+		return "will update"
+	case ref.CanCascade():
+		return "breaks (use --cascade)"
+	}
+	return "breaks"
+}
+
+func writeRenameReport(ctx *CLIContext, report *td.RenameReport) error {
+	csvFormatter := func(data interface{}) string {
+		r := data.(*td.RenameReport)
+		var sb strings.Builder
+		for _, ref := range r.References {
+			sb.WriteString(fmt.Sprintf("%s,%s,%q,%t,%t,%q\n", ref.Kind, ref.ID, ref.Name, ref.Breaks, ref.Updated, ref.Detail))
+		}
+		return sb.String()
+	}
+
+	tableFormatter := func(data interface{}) string {
+		r := data.(*td.RenameReport)
+		var sb strings.Builder
+		if !r.Renamed {
+			sb.WriteString(fmt.Sprintf("Dry run: %q would be renamed to %q\n", r.OldName, r.NewName))
+			for _, ref := range r.References {
+				sb.WriteString(fmt.Sprintf("  %s %s %s: %s\n", ref.Kind, orNone(ref.ID), ref.Name, ref.Detail))
+			}
+			sb.WriteString(fmt.Sprintf("%d reference(s), %d hold the old name\n", len(r.References), len(r.Breaking())))
+			return sb.String()
+		}
+		sb.WriteString(fmt.Sprintf("Renamed %q to %q\n", r.OldName, r.NewName))
+		for _, ref := range r.References {
+			if ref.Updated {
+				sb.WriteString(fmt.Sprintf("Updated %s %s (%s)\n", ref.Kind, ref.ID, ref.Name))
+			}
+		}
+		if breaking := r.Breaking(); len(breaking) > 0 {
+			sb.WriteString(fmt.Sprintf("Warning: %d reference(s) still hold the old name:\n", len(breaking)))
+			for _, ref := range breaking {
+				sb.WriteString(fmt.Sprintf("  %s %s %s: %s\n", ref.Kind, orNone(ref.ID), ref.Name, ref.Detail))
+			}
+		}
+		return sb.String()
+	}
+
+	return formatAndWriteOutput(report, ctx.GlobalFlags.Format, ctx.GlobalFlags.Output, "kind,id,name,breaks,updated,detail", csvFormatter, tableFormatter)
+}
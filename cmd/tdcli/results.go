@@ -39,7 +39,7 @@ SUBCOMMANDS:
     get, show <job_id>     Get query results
 
 OPTIONS:
-    --format FORMAT        Result format (json, csv, tsv, jsonl)
+    --format FORMAT        Result format (json, jsonl, csv, tsv, msgpack, msgpack.gz)
     --limit LIMIT          Limit number of rows
     --output FILE          Save results to file
     --verbose, -v          Verbose output
@@ -69,7 +69,9 @@ func handleResultGet(ctx context.Context, client *td.Client, args []string, flag
 	if format == "" || format == "table" {
 		format = "json" // Default to JSON for API
 	}
-	opts.Format = td.ResultFormat(format)
+	resultFormat, err := td.ParseResultFormat(format)
+	handleError(err, "Invalid result format", flags.Verbose)
+	opts.Format = resultFormat
 
 	// Set limit
 	if flags.Limit > 0 {
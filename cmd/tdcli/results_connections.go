@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// ResultsListCmd lists the result connections saved in the account
+type ResultsListCmd struct{}
+
+func (r *ResultsListCmd) Run(ctx *CLIContext) error {
+	results, err := ctx.Client.Results.ListResults(ctx.Context)
+	if err != nil {
+		return fmt.Errorf("failed to list results: %w", err)
+	}
+	for i := range results {
+		results[i] = redactResult(results[i])
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+
+	csvFormatter := func(data interface{}) string {
+		var csvBuilder strings.Builder
+		for _, result := range data.([]td.Result) {
+			csvBuilder.WriteString(fmt.Sprintf("%s,%s,%q\n", result.Name, resultType(result), result.URL))
+		}
+		return csvBuilder.String()
+	}
+
+	tableFormatter := func(data interface{}) string {
+		results := data.([]td.Result)
+		if len(results) == 0 {
+			return "No result connections found\n"
+		}
+		var tableBuilder strings.Builder
+		w := tabwriter.NewWriter(&tableBuilder, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tTYPE\tURL")
+		for _, result := range results {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", result.Name, resultType(result), result.URL)
+		}
+		w.Flush()
+		return tableBuilder.String()
+	}
+
+	return formatAndWriteOutput(results, ctx.GlobalFlags.Format, ctx.GlobalFlags.Output, "name,type,url", csvFormatter, tableFormatter)
+}
+
+// ResultsCreateCmd saves a result URL under a name that queries can write to
+// with --result-connection
+type ResultsCreateCmd struct {
+	Name    string            `kong:"arg,help='Result connection name'"`
+	URL     string            `kong:"arg,optional,help='Result URL, e.g. td://@/db/table or s3://key:secret@/bucket/path'"`
+	URLEnv  string            `kong:"name='url-env',placeholder='VAR',help='Read the result URL from this environment variable, keeping credentials out of shell history'"`
+	Setting map[string]string `kong:"placeholder='KEY=VALUE',help='Additional connection setting'"`
+}
+
+func (r *ResultsCreateCmd) Run(ctx *CLIContext) error {
+	resultURL := r.URL
+	if r.URLEnv != "" {
+		if resultURL != "" {
+			return fmt.Errorf("give the result URL as an argument or with --url-env, not both")
+		}
+		resultURL = os.Getenv(r.URLEnv)
+		if resultURL == "" {
+			return fmt.Errorf("environment variable %s is not set", r.URLEnv)
+		}
+	}
+	if resultURL == "" {
+		return fmt.Errorf("a result URL is required")
+	}
+
+	var settings map[string]interface{}
+	if len(r.Setting) > 0 {
+		settings = make(map[string]interface{}, len(r.Setting))
+		for key, value := range r.Setting {
+			settings[key] = value
+		}
+	}
+
+	result, err := ctx.Client.Results.CreateResult(ctx.Context, r.Name, resultURL, settings)
+	if err != nil {
+		return fmt.Errorf("failed to create result %s: %w", r.Name, err)
+	}
+	if ctx.GlobalFlags.Format == "json" {
+		return formatAndWriteOutput(redactResult(*result), "json", ctx.GlobalFlags.Output, "", nil, nil)
+	}
+	fmt.Printf("Result connection %s created (%s)\n", r.Name, redactResultURL(resultURL))
+	return nil
+}
+
+// ResultsDeleteCmd deletes a saved result connection
+type ResultsDeleteCmd struct {
+	Name  string `kong:"arg,help='Result connection name'"`
+	Force bool   `kong:"flag,help='Skip confirmation prompt'"`
+}
+
+func (r *ResultsDeleteCmd) Run(ctx *CLIContext) error {
+	if !r.Force && !promptConfirmation(fmt.Sprintf("Delete result connection %s? Queries and schedules writing to it will fail", r.Name)) {
+		fmt.Println("Cancelled")
+		return nil
+	}
+	if err := ctx.Client.Results.DeleteResult(ctx.Context, r.Name); err != nil {
+		return fmt.Errorf("failed to delete result %s: %w", r.Name, err)
+	}
+	fmt.Printf("Result connection %s deleted\n", r.Name)
+	return nil
+}
+
+// resultType returns the type of a result connection, falling back to the
+// scheme of its URL or the type of its connector settings
+func resultType(result td.Result) string {
+	if result.Type != "" {
+		return result.Type
+	}
+	if strings.HasPrefix(result.URL, "{") {
+		var connector struct {
+			Type string `json:"type"`
+		}
+		json.Unmarshal([]byte(result.URL), &connector)
+		return connector.Type
+	}
+	if i := strings.Index(result.URL, "://"); i > 0 {
+		return result.URL[:i]
+	}
+	return ""
+}
+
+// redactResult hides the credentials in a result's URL and settings
+func redactResult(result td.Result) td.Result {
+	result.URL = redactResultURL(result.URL)
+	result.Settings = redactSettings(result.Settings)
+	return result
+}
+
+// redactSettings returns a copy of connection settings with the values of
+// credential keys hidden
+func redactSettings(settings map[string]interface{}) map[string]interface{} {
+	if len(settings) == 0 {
+		return settings
+	}
+	redacted := make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		lower := strings.ToLower(key)
+		if strings.Contains(lower, "password") || strings.Contains(lower, "secret") || strings.Contains(lower, "token") || strings.Contains(lower, "private_key") || strings.Contains(lower, "keyfile") {
+			value = "xxxxx"
+		}
+		redacted[key] = value
+	}
+	return redacted
+}
+
+// ResultsTestCmd checks a saved result connection, exiting non-zero when it
+// has problems, for pre-flight checks in CI
+type ResultsTestCmd struct {
+	Name string `kong:"arg,help='Result connection name'"`
+}
+
+func (r *ResultsTestCmd) Run(ctx *CLIContext) error {
+	check, err := ctx.Client.Results.TestConnection(ctx.Context, r.Name)
+	if err != nil {
+		return fmt.Errorf("failed to check result %s: %w", r.Name, err)
+	}
+
+	csvFormatter := func(data interface{}) string {
+		c := data.(*td.ResultConnectionCheck)
+		var csvBuilder strings.Builder
+		for _, problem := range c.Problems {
+			csvBuilder.WriteString(fmt.Sprintf("problem,%q\n", problem))
+		}
+		for _, note := range c.Notes {
+			csvBuilder.WriteString(fmt.Sprintf("note,%q\n", note))
+		}
+		return csvBuilder.String()
+	}
+
+	tableFormatter := func(data interface{}) string {
+		c := data.(*td.ResultConnectionCheck)
+		var b strings.Builder
+		fmt.Fprintf(&b, "Result connection %s (%s)\n", c.Name, c.Type)
+		for _, problem := range c.Problems {
+			fmt.Fprintf(&b, "  ✗ %s\n", problem)
+		}
+		for _, note := range c.Notes {
+			fmt.Fprintf(&b, "  - %s\n", note)
+		}
+		if c.OK() {
+			b.WriteString("No problems found\n")
+		}
+		return b.String()
+	}
+
+	if err := formatAndWriteOutput(check, ctx.GlobalFlags.Format, ctx.GlobalFlags.Output, "kind,message", csvFormatter, tableFormatter); err != nil {
+		return err
+	}
+	if !check.OK() {
+		return fmt.Errorf("result connection %s has %d problem(s)", r.Name, len(check.Problems))
+	}
+	return nil
+}
@@ -0,0 +1,390 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// SchedulesCmd manages scheduled (saved) queries
+type SchedulesCmd struct {
+	List     SchedulesListCmd     `kong:"cmd,aliases='ls',help='List scheduled queries'"`
+	Show     SchedulesShowCmd     `kong:"cmd,aliases='get',help='Show a scheduled query'"`
+	Create   SchedulesCreateCmd   `kong:"cmd,help='Create a scheduled query'"`
+	Update   SchedulesUpdateCmd   `kong:"cmd,help='Change a scheduled query'"`
+	Delete   SchedulesDeleteCmd   `kong:"cmd,aliases='rm',help='Delete a scheduled query'"`
+	Run      SchedulesRunCmd      `kong:"cmd,help='Run a scheduled query now for a scheduled time'"`
+	History  SchedulesHistoryCmd  `kong:"cmd,help='Show past runs of a scheduled query'"`
+	Backfill SchedulesBackfillCmd `kong:"cmd,help='Run a scheduled query for every scheduled time in a past range'"`
+}
+
+// scheduleEngine returns the schedule API's name for a query engine, which
+// calls Trino presto
+func scheduleEngine(engine string) string {
+	if engine == "trino" {
+		return "presto"
+	}
+	return engine
+}
+
+type SchedulesListCmd struct{}
+
+func (s *SchedulesListCmd) Run(ctx *CLIContext) error {
+	handleScheduleList(ctx.Context, ctx.Client, ctx.GlobalFlags)
+	return nil
+}
+
+type SchedulesShowCmd struct {
+	Name string `kong:"arg,help='Schedule name'"`
+}
+
+func (s *SchedulesShowCmd) Run(ctx *CLIContext) error {
+	schedule, err := ctx.Client.Schedules.Get(ctx.Context, s.Name)
+	if err != nil {
+		return err
+	}
+	if ctx.GlobalFlags.Format == "json" {
+		printJSON(schedule)
+		return nil
+	}
+	printScheduleDetails(schedule)
+	return nil
+}
+
+type SchedulesCreateCmd struct {
+	Name       string `kong:"arg,help='Schedule name'"`
+	Cron       string `kong:"arg,help='Cron expression, e.g. \"0 0 * * *\" or @daily'"`
+	Query      string `kong:"arg,help='SQL query'"`
+	Database   string `kong:"required,help='Database to run the query against'"`
+	Engine     string `kong:"help='Query engine: trino (default) or hive',default='trino',enum='trino,hive,presto'"`
+	Timezone   string `kong:"help='Time zone of the cron expression (default UTC)'"`
+	Delay      int    `kong:"help='Seconds to wait after the scheduled time'"`
+	Priority   int    `kong:"help='Query priority (-2 to 2)'"`
+	RetryLimit int    `kong:"help='Automatic retries of a failed run'"`
+	ResultURL  string `kong:"name='result-url',help='Write results to a URL, e.g. td://@/db/table?mode=replace'"`
+}
+
+func (s *SchedulesCreateCmd) Run(ctx *CLIContext) error {
+	opts := &td.ScheduleOptions{
+		Cron:       s.Cron,
+		Query:      s.Query,
+		Database:   s.Database,
+		Type:       scheduleEngine(s.Engine),
+		Timezone:   s.Timezone,
+		Delay:      &s.Delay,
+		Priority:   &s.Priority,
+		RetryLimit: &s.RetryLimit,
+		Result:     s.ResultURL,
+	}
+	schedule, err := ctx.Client.Schedules.Create(ctx.Context, s.Name, opts)
+	if err != nil {
+		return fmt.Errorf("failed to create schedule: %w", err)
+	}
+	fmt.Printf("Schedule %s created\n", schedule.Name)
+	return nil
+}
+
+type SchedulesUpdateCmd struct {
+	Name       string `kong:"arg,help='Schedule name'"`
+	Cron       string `kong:"help='New cron expression'"`
+	Query      string `kong:"help='New SQL query'"`
+	Database   string `kong:"help='New database'"`
+	Engine     string `kong:"help='New query engine: trino or hive'"`
+	Timezone   string `kong:"help='New time zone'"`
+	Delay      *int   `kong:"help='New delay in seconds'"`
+	Priority   *int   `kong:"help='New query priority (-2 to 2)'"`
+	RetryLimit *int   `kong:"help='New retry limit'"`
+	ResultURL  string `kong:"name='result-url',help='New result output URL'"`
+}
+
+func (s *SchedulesUpdateCmd) Run(ctx *CLIContext) error {
+	switch s.Engine {
+	case "", "trino", "hive", "presto":
+	default:
+		return fmt.Errorf("invalid --engine %q: use trino or hive", s.Engine)
+	}
+	opts := &td.ScheduleOptions{
+		Cron:       s.Cron,
+		Query:      s.Query,
+		Database:   s.Database,
+		Type:       scheduleEngine(s.Engine),
+		Timezone:   s.Timezone,
+		Delay:      s.Delay,
+		Priority:   s.Priority,
+		RetryLimit: s.RetryLimit,
+		Result:     s.ResultURL,
+	}
+	if *opts == (td.ScheduleOptions{}) {
+		return fmt.Errorf("nothing to update; give at least one of --cron, --query, --database, --engine, --timezone, --delay, --priority, --retry-limit or --result-url")
+	}
+	if _, err := ctx.Client.Schedules.Update(ctx.Context, s.Name, opts); err != nil {
+		return fmt.Errorf("failed to update schedule: %w", err)
+	}
+	fmt.Printf("Schedule %s updated\n", s.Name)
+	return nil
+}
+
+type SchedulesDeleteCmd struct {
+	Name  string `kong:"arg,help='Schedule name'"`
+	Force bool   `kong:"flag,help='Skip confirmation prompt'"`
+}
+
+func (s *SchedulesDeleteCmd) Run(ctx *CLIContext) error {
+	if !s.Force && !promptConfirmation(fmt.Sprintf("Delete schedule %s?", s.Name)) {
+		fmt.Println("Cancelled")
+		return nil
+	}
+	if err := ctx.Client.Schedules.Delete(ctx.Context, s.Name); err != nil {
+		return fmt.Errorf("failed to delete schedule: %w", err)
+	}
+	fmt.Printf("Schedule %s deleted\n", s.Name)
+	return nil
+}
+
+type SchedulesRunCmd struct {
+	Name string `kong:"arg,help='Schedule name'"`
+	Time string `kong:"arg,optional,help='Scheduled time to run for: a date (2006-01-02), RFC 3339 time or lookback (1d); default now'"`
+	Num  int    `kong:"help='Number of consecutive scheduled times to run, starting at TIME',default='1'"`
+}
+
+func (s *SchedulesRunCmd) Run(ctx *CLIContext) error {
+	at := time.Now()
+	if s.Time != "" {
+		var err error
+		if at, err = parseTimeBound(s.Time, at); err != nil {
+			return fmt.Errorf("invalid time: %w", err)
+		}
+	}
+	jobs, err := ctx.Client.Schedules.Run(ctx.Context, s.Name, at, s.Num)
+	if err != nil {
+		return fmt.Errorf("failed to run schedule: %w", err)
+	}
+	if ctx.GlobalFlags.Format == "json" {
+		printJSON(jobs)
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "JOB ID\tTYPE\tSCHEDULED AT")
+	for _, job := range jobs {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", job.JobID, job.Type, job.ScheduledAt.Format("2006-01-02 15:04:05"))
+	}
+	return w.Flush()
+}
+
+type SchedulesHistoryCmd struct {
+	Name  string `kong:"arg,help='Schedule name'"`
+	Limit int    `kong:"help='Number of runs to show',default='20'"`
+}
+
+func (s *SchedulesHistoryCmd) Run(ctx *CLIContext) error {
+	handleScheduleHistory(ctx.Context, ctx.Client, s.Name, s.Limit, ctx.GlobalFlags)
+	return nil
+}
+
+type SchedulesBackfillCmd struct {
+	Name        string `kong:"arg,help='Schedule name'"`
+	From        string `kong:"arg,help='Start of the range: a date (2006-01-02) in the schedule time zone, RFC 3339 time or lookback (7d)'"`
+	To          string `kong:"arg,optional,help='End of the range, exclusive; default now'"`
+	Preview     bool   `kong:"help='Only list the scheduled times'"`
+	Force       bool   `kong:"flag,help='Skip confirmation prompt'"`
+	Concurrency int    `kong:"help='Runs started at once',default='4'"`
+	MaxRuns     int    `kong:"name='max-runs',help='Refuse ranges with more scheduled times than this',default='1000'"`
+}
+
+func (s *SchedulesBackfillCmd) Run(ctx *CLIContext) error {
+	schedule, err := ctx.Client.Schedules.Get(ctx.Context, s.Name)
+	if err != nil {
+		return err
+	}
+	loc := time.UTC
+	if schedule.Timezone != "" {
+		if loc, err = time.LoadLocation(schedule.Timezone); err != nil {
+			return fmt.Errorf("schedule %s: %w", s.Name, err)
+		}
+	}
+
+	now := time.Now()
+	from, err := parseScheduleTime(s.From, loc, now)
+	if err != nil {
+		return fmt.Errorf("invalid FROM: %w", err)
+	}
+	to := now
+	if s.To != "" {
+		if to, err = parseScheduleTime(s.To, loc, now); err != nil {
+			return fmt.Errorf("invalid TO: %w", err)
+		}
+	}
+	if !from.Before(to) {
+		return fmt.Errorf("FROM (%s) must be before TO (%s)", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	times, err := schedule.TimesBetween(from, to, s.MaxRuns)
+	if err != nil {
+		return err
+	}
+	if len(times) == 0 {
+		fmt.Printf("No scheduled times of %s (%s) between %s and %s\n", s.Name, schedule.Cron,
+			from.Format(time.RFC3339), to.Format(time.RFC3339))
+		return nil
+	}
+	fmt.Printf("%d scheduled times of %s (%s, %s):\n", len(times), s.Name, schedule.Cron, loc)
+	for _, t := range times {
+		fmt.Printf("  %s\n", t.Format("2006-01-02 15:04:05 MST"))
+	}
+
+	if s.Preview {
+		return nil
+	}
+	if !s.Force && !promptConfirmation(fmt.Sprintf("Start %d runs of %s?", len(times), s.Name)) {
+		fmt.Println("Cancelled")
+		return nil
+	}
+
+	jobs, err := ctx.Client.Schedules.Backfill(ctx.Context, s.Name, from, to, &td.ScheduleBackfillOptions{
+		Concurrency: s.Concurrency,
+		MaxRuns:     s.MaxRuns,
+	})
+	var batchErr *td.BatchError
+	if errors.As(err, &batchErr) {
+		for _, taskErr := range batchErr.Errors {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to start %v\n", taskErr.Err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to backfill schedule: %w", err)
+	}
+
+	if ctx.GlobalFlags.Format == "json" {
+		printJSON(jobs)
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "JOB ID\tTYPE\tSCHEDULED AT")
+		for _, job := range jobs {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", job.JobID, job.Type, job.ScheduledAt.Format("2006-01-02 15:04:05"))
+		}
+		w.Flush()
+		fmt.Printf("\nStarted %d of %d runs\n", len(jobs), len(times))
+	}
+	if batchErr != nil {
+		return fmt.Errorf("%d runs could not be started", len(batchErr.Errors))
+	}
+	return nil
+}
+
+// parseScheduleTime parses a time as parseTimeBound does, except that a bare
+// date is midnight in the schedule's time zone rather than UTC
+func parseScheduleTime(value string, loc *time.Location, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return t, nil
+	}
+	return parseTimeBound(value, now)
+}
+
+func handleScheduleList(ctx context.Context, client *td.Client, flags Flags) {
+	schedules, err := client.Schedules.List(ctx)
+	if err != nil {
+		handleError(err, "Failed to list schedules", flags.Verbose)
+		return
+	}
+
+	csvFormatter := func(data interface{}) string {
+		var csvBuilder strings.Builder
+		for _, s := range data.([]td.Schedule) {
+			csvBuilder.WriteString(fmt.Sprintf("%s,%q,%s,%s,%s,%s\n",
+				s.Name, s.Cron, s.Timezone, s.Type, s.Database, formatScheduleNextTime(s)))
+		}
+		return csvBuilder.String()
+	}
+
+	tableFormatter := func(data interface{}) string {
+		schedules := data.([]td.Schedule)
+		if len(schedules) == 0 {
+			return "No schedules found\n"
+		}
+		var tableBuilder strings.Builder
+		w := tabwriter.NewWriter(&tableBuilder, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tCRON\tTIMEZONE\tENGINE\tDATABASE\tNEXT RUN")
+		for _, s := range schedules {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				s.Name, s.Cron, s.Timezone, s.Type, s.Database, formatScheduleNextTime(s))
+		}
+		w.Flush()
+		tableBuilder.WriteString(fmt.Sprintf("\nTotal: %d schedules\n", len(schedules)))
+		return tableBuilder.String()
+	}
+
+	if err := formatAndWriteOutput(schedules, flags.Format, flags.Output, "name,cron,timezone,type,database,next_time", csvFormatter, tableFormatter); err != nil {
+		handleError(err, "Failed to write output", flags.Verbose)
+	}
+}
+
+func handleScheduleHistory(ctx context.Context, client *td.Client, name string, limit int, flags Flags) {
+	var opts *td.ScheduleHistoryOptions
+	if limit > 0 {
+		opts = &td.ScheduleHistoryOptions{To: limit}
+	}
+	history, err := client.Schedules.History(ctx, name, opts)
+	if err != nil {
+		handleError(err, "Failed to get schedule history", flags.Verbose)
+		return
+	}
+
+	csvFormatter := func(data interface{}) string {
+		var csvBuilder strings.Builder
+		for _, run := range data.([]td.ScheduleHistoryEntry) {
+			csvBuilder.WriteString(fmt.Sprintf("%s,%s,%s,%d\n",
+				run.ScheduledAt.Format("2006-01-02 15:04:05"), run.JobID, run.Status, run.Duration))
+		}
+		return csvBuilder.String()
+	}
+
+	tableFormatter := func(data interface{}) string {
+		history := data.([]td.ScheduleHistoryEntry)
+		if len(history) == 0 {
+			return "No runs found\n"
+		}
+		var tableBuilder strings.Builder
+		w := tabwriter.NewWriter(&tableBuilder, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SCHEDULED AT\tJOB ID\tSTATUS\tDURATION")
+		for _, run := range history {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+				run.ScheduledAt.Format("2006-01-02 15:04:05"), run.JobID, run.Status,
+				time.Duration(run.Duration)*time.Second)
+		}
+		w.Flush()
+		return tableBuilder.String()
+	}
+
+	if err := formatAndWriteOutput(history, flags.Format, flags.Output, "scheduled_at,job_id,status,duration", csvFormatter, tableFormatter); err != nil {
+		handleError(err, "Failed to write output", flags.Verbose)
+	}
+}
+
+func printScheduleDetails(s *td.Schedule) {
+	fmt.Printf("Name: %s\n", s.Name)
+	fmt.Printf("Cron: %s\n", s.Cron)
+	fmt.Printf("Timezone: %s\n", s.Timezone)
+	fmt.Printf("Delay: %ds\n", s.Delay)
+	fmt.Printf("Engine: %s\n", s.Type)
+	fmt.Printf("Database: %s\n", s.Database)
+	fmt.Printf("Priority: %d\n", s.Priority)
+	fmt.Printf("Retry Limit: %d\n", s.RetryLimit)
+	if s.Result != "" {
+		fmt.Printf("Result: %s\n", s.Result)
+	}
+	fmt.Printf("Next Run: %s\n", formatScheduleNextTime(*s))
+	fmt.Printf("Owner: %s\n", s.UserName)
+	fmt.Printf("Query:\n%s\n", s.Query)
+}
+
+func formatScheduleNextTime(s td.Schedule) string {
+	if s.NextTime == nil || s.NextTime.IsZero() {
+		return "paused"
+	}
+	return s.NextTime.Format("2006-01-02 15:04:05")
+}
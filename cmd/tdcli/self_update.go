@@ -0,0 +1,388 @@
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	releaseOwner = "mickeey2525"
+	releaseRepo  = "treasuredata-go-sdk"
+
+	// updateChannelStable only considers full releases; updateChannelBeta also
+	// considers prereleases
+	updateChannelStable = "stable"
+	updateChannelBeta   = "beta"
+)
+
+var (
+	// githubAPIURL is the GitHub API base URL, overridden in tests
+	githubAPIURL = "https://api.github.com"
+
+	// updatePublicKey is the base64 ed25519 key that signs checksums.txt.
+	// Release builds set it with -ldflags "-X main.updatePublicKey=...";
+	// when set, self-update refuses releases without a valid signature.
+	updatePublicKey = ""
+)
+
+// githubRelease is the subset of the GitHub release object used for updates
+type githubRelease struct {
+	TagName    string        `json:"tag_name"`
+	Prerelease bool          `json:"prerelease"`
+	Draft      bool          `json:"draft"`
+	HTMLURL    string        `json:"html_url"`
+	Assets     []githubAsset `json:"assets"`
+}
+
+type githubAsset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+func (r *githubRelease) asset(name string) *githubAsset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// platformAsset finds the archive built for this OS and architecture, named
+// by the goreleaser template <project>_<Os>_<arch>.tar.gz
+func (r *githubRelease) platformAsset() *githubAsset {
+	arch := runtime.GOARCH
+	switch arch {
+	case "amd64":
+		arch = "x86_64"
+	case "386":
+		arch = "i386"
+	}
+	goos := strings.ToUpper(runtime.GOOS[:1]) + runtime.GOOS[1:]
+	suffix := fmt.Sprintf("_%s_%s.tar.gz", goos, arch)
+
+	for i := range r.Assets {
+		if strings.HasSuffix(r.Assets[i].Name, suffix) {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+type selfUpdateOptions struct {
+	Channel string
+	Check   bool
+	Force   bool
+	Proxy   string
+}
+
+func handleSelfUpdate(ctx context.Context, opts selfUpdateOptions) error {
+	if opts.Channel != updateChannelStable && opts.Channel != updateChannelBeta {
+		return fmt.Errorf("invalid channel %q: use %s or %s", opts.Channel, updateChannelStable, updateChannelBeta)
+	}
+
+	httpClient, err := newUpdateHTTPClient(opts.Proxy)
+	if err != nil {
+		return err
+	}
+
+	release, err := latestRelease(ctx, httpClient, opts.Channel)
+	if err != nil {
+		return err
+	}
+
+	latest := strings.TrimPrefix(release.TagName, "v")
+	current := strings.TrimPrefix(version, "v")
+	if version == "dev" && !opts.Force {
+		return fmt.Errorf("this is a development build; latest %s release is %s (use --force to replace it)", opts.Channel, latest)
+	}
+	if version != "dev" && compareVersions(latest, current) <= 0 && !opts.Force {
+		fmt.Printf("tdcli %s is up to date (latest %s release: %s)\n", current, opts.Channel, latest)
+		return nil
+	}
+	if opts.Check {
+		fmt.Printf("tdcli %s is available (current: %s)\n%s\n", latest, current, release.HTMLURL)
+		return nil
+	}
+
+	archive := release.platformAsset()
+	if archive == nil {
+		return fmt.Errorf("release %s has no build for %s/%s", release.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	checksums := release.asset("checksums.txt")
+	if checksums == nil {
+		return fmt.Errorf("release %s has no checksums.txt", release.TagName)
+	}
+
+	checksumData, err := downloadAsset(ctx, httpClient, checksums.BrowserDownloadURL)
+	if err != nil {
+		return err
+	}
+	if updatePublicKey != "" {
+		sigAsset := release.asset("checksums.txt.sig")
+		if sigAsset == nil {
+			return fmt.Errorf("release %s is not signed", release.TagName)
+		}
+		sig, err := downloadAsset(ctx, httpClient, sigAsset.BrowserDownloadURL)
+		if err != nil {
+			return err
+		}
+		if err := verifyChecksumSignature(checksumData, sig, updatePublicKey); err != nil {
+			return err
+		}
+	}
+
+	want, err := lookupChecksum(checksumData, archive.Name)
+	if err != nil {
+		return err
+	}
+
+	archiveData, err := downloadAsset(ctx, httpClient, archive.BrowserDownloadURL)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(archiveData)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", archive.Name, got, want)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the running binary: %w", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return fmt.Errorf("failed to locate the running binary: %w", err)
+	}
+
+	if err := replaceExecutable(exe, archiveData); err != nil {
+		return err
+	}
+
+	fmt.Printf("Updated tdcli %s -> %s\n", current, latest)
+	return nil
+}
+
+// newUpdateHTTPClient honours HTTPS_PROXY and friends, or an explicit proxy URL
+func newUpdateHTTPClient(proxy string) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return &http.Client{Transport: transport, Timeout: 5 * time.Minute}, nil
+}
+
+// latestRelease returns the newest release on the channel. Drafts are ignored.
+func latestRelease(ctx context.Context, httpClient *http.Client, channel string) (*githubRelease, error) {
+	if channel == updateChannelStable {
+		var release githubRelease
+		if err := getGitHubJSON(ctx, httpClient, fmt.Sprintf("/repos/%s/%s/releases/latest", releaseOwner, releaseRepo), &release); err != nil {
+			return nil, err
+		}
+		return &release, nil
+	}
+
+	var releases []githubRelease
+	if err := getGitHubJSON(ctx, httpClient, fmt.Sprintf("/repos/%s/%s/releases?per_page=20", releaseOwner, releaseRepo), &releases); err != nil {
+		return nil, err
+	}
+	var newest *githubRelease
+	for i := range releases {
+		r := &releases[i]
+		if r.Draft {
+			continue
+		}
+		if newest == nil || compareVersions(strings.TrimPrefix(r.TagName, "v"), strings.TrimPrefix(newest.TagName, "v")) > 0 {
+			newest = r
+		}
+	}
+	if newest == nil {
+		return nil, fmt.Errorf("no releases found")
+	}
+	return newest, nil
+}
+
+func getGitHubJSON(ctx context.Context, httpClient *http.Client, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", githubAPIURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "tdcli/"+version)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to check for releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to check for releases: GitHub returned %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func downloadAsset(ctx context.Context, httpClient *http.Client, assetURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", assetURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "tdcli/"+version)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", assetURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", assetURL, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// lookupChecksum finds name in a sha256sum-format checksums file
+func lookupChecksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum for %s in checksums.txt", name)
+}
+
+// verifyChecksumSignature checks a base64 ed25519 signature of checksums.txt
+func verifyChecksumSignature(checksums, sig []byte, publicKey string) error {
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid built-in update signing key")
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return fmt.Errorf("invalid checksums.txt signature: %w", err)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), checksums, signature) {
+		return fmt.Errorf("checksums.txt signature verification failed")
+	}
+	return nil
+}
+
+// replaceExecutable extracts the tdcli binary from a release archive next to
+// exe and renames it into place, so the binary is never left half-written
+func replaceExecutable(exe string, archive []byte) error {
+	binaryName := "tdcli"
+	if runtime.GOOS == "windows" {
+		binaryName += ".exe"
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return fmt.Errorf("failed to read release archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return fmt.Errorf("release archive does not contain %s", binaryName)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read release archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || filepath.Base(header.Name) != binaryName {
+			continue
+		}
+
+		tmp, err := os.CreateTemp(filepath.Dir(exe), ".tdcli-update-*")
+		if err != nil {
+			return fmt.Errorf("failed to write the new binary (is %s writable?): %w", filepath.Dir(exe), err)
+		}
+		defer os.Remove(tmp.Name())
+
+		if _, err := io.Copy(tmp, tr); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to write the new binary: %w", err)
+		}
+		if err := tmp.Close(); err != nil {
+			return err
+		}
+		if err := os.Chmod(tmp.Name(), 0755); err != nil {
+			return err
+		}
+
+		// Windows cannot overwrite a running executable, but can rename it
+		if runtime.GOOS == "windows" {
+			old := exe + ".old"
+			os.Remove(old)
+			if err := os.Rename(exe, old); err != nil {
+				return fmt.Errorf("failed to move the current binary aside: %w", err)
+			}
+		}
+		if err := os.Rename(tmp.Name(), exe); err != nil {
+			return fmt.Errorf("failed to replace %s: %w", exe, err)
+		}
+		return nil
+	}
+}
+
+// compareVersions compares semantic versions without a leading "v". A
+// prerelease sorts before the release it precedes.
+func compareVersions(a, b string) int {
+	aCore, aPre, _ := strings.Cut(a, "-")
+	bCore, bPre, _ := strings.Cut(b, "-")
+
+	aParts := strings.Split(aCore, ".")
+	bParts := strings.Split(bCore, ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var x, y int
+		if i < len(aParts) {
+			x, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			y, _ = strconv.Atoi(bParts[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	case aPre < bPre:
+		return -1
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.0", "1.1.9", 1},
+		{"1.2.0", "1.2.0", 0},
+		{"1.10.0", "1.9.0", 1},
+		{"1.2.0-beta.1", "1.2.0", -1},
+		{"1.2.0-beta.2", "1.2.0-beta.1", 1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestLatestRelease_BetaChannel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"tag_name": "v1.3.0-beta.1", "prerelease": true},
+			{"tag_name": "v1.4.0", "draft": true},
+			{"tag_name": "v1.2.0"}
+		]`)
+	}))
+	defer server.Close()
+	githubAPIURL = server.URL
+	defer func() { githubAPIURL = "https://api.github.com" }()
+
+	release, err := latestRelease(context.Background(), server.Client(), updateChannelBeta)
+	if err != nil {
+		t.Fatalf("latestRelease returned error: %v", err)
+	}
+	if release.TagName != "v1.3.0-beta.1" {
+		t.Errorf("TagName = %q, want v1.3.0-beta.1", release.TagName)
+	}
+}
+
+func TestLookupChecksum(t *testing.T) {
+	checksums := []byte("abc123  treasuredata-go-sdk_Linux_x86_64.tar.gz\ndef456  treasuredata-go-sdk_Darwin_arm64.tar.gz\n")
+
+	sum, err := lookupChecksum(checksums, "treasuredata-go-sdk_Darwin_arm64.tar.gz")
+	if err != nil || sum != "def456" {
+		t.Errorf("lookupChecksum = %q, %v, want def456", sum, err)
+	}
+	if _, err := lookupChecksum(checksums, "missing.tar.gz"); err == nil {
+		t.Error("Expected error for a missing archive")
+	}
+}
+
+func TestVerifyChecksumSignature(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := base64.StdEncoding.EncodeToString(public)
+	checksums := []byte("abc123  tdcli.tar.gz\n")
+	sig := []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(private, checksums)))
+
+	if err := verifyChecksumSignature(checksums, sig, key); err != nil {
+		t.Errorf("verifyChecksumSignature returned error: %v", err)
+	}
+	if err := verifyChecksumSignature([]byte("tampered"), sig, key); err == nil {
+		t.Error("Expected error for tampered checksums")
+	}
+}
+
+func TestReplaceExecutable(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "tdcli")
+	if err := os.WriteFile(exe, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range map[string]string{"README.md": "readme", "tdcli": "new"} {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+
+	if err := replaceExecutable(exe, buf.Bytes()); err != nil {
+		t.Fatalf("replaceExecutable returned error: %v", err)
+	}
+	data, err := os.ReadFile(exe)
+	if err != nil || string(data) != "new" {
+		t.Errorf("Binary = %q, %v, want new", data, err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(exe)); len(entries) != 1 {
+		t.Errorf("Expected no leftover temp files, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// SmokeCmd runs an end-to-end scenario against a dedicated test database:
+// create a table, import rows, count them with a query and drop the table
+type SmokeCmd struct {
+	Database string        `kong:"help='Test database; created if missing',default='tdcli_smoke'"`
+	Rows     int           `kong:"help='Number of rows to import',default='10'"`
+	Timeout  time.Duration `kong:"help='Give up after this long; imported rows can take a few minutes to become queryable',default='10m'"`
+	Interval time.Duration `kong:"help='Delay between count queries while waiting for the imported rows',default='30s'"`
+	Keep     bool          `kong:"help='Keep the test table instead of deleting it'"`
+}
+
+// smokeStep is the outcome of one step of the smoke scenario
+type smokeStep struct {
+	Step     string        `json:"step"`
+	OK       bool          `json:"ok"`
+	Duration time.Duration `json:"duration_ns"`
+	Detail   string        `json:"detail,omitempty"`
+}
+
+// smokeReport is the outcome of a smoke run
+type smokeReport struct {
+	Database string      `json:"database"`
+	Table    string      `json:"table"`
+	Steps    []smokeStep `json:"steps"`
+}
+
+// OK reports whether every step passed
+func (r *smokeReport) OK() bool {
+	for _, step := range r.Steps {
+		if !step.OK {
+			return false
+		}
+	}
+	return len(r.Steps) > 0
+}
+
+func (s *SmokeCmd) Run(ctx *CLIContext) error {
+	if s.Rows <= 0 {
+		return fmt.Errorf("--rows must be positive")
+	}
+	runCtx, cancel := context.WithTimeout(ctx.Context, s.Timeout)
+	defer cancel()
+
+	report := runSmoke(runCtx, ctx.Client, s.Database, fmt.Sprintf("smoke_%d", time.Now().Unix()), s.Rows, s.Interval, s.Keep)
+	return writeSmokeReport(report, ctx.GlobalFlags)
+}
+
+// runSmoke runs the scenario and records every step. It stops at the first
+// failure but always deletes the table it created, unless keep is set.
+func runSmoke(ctx context.Context, client *td.Client, database, table string, rows int, interval time.Duration, keep bool) *smokeReport {
+	report := &smokeReport{Database: database, Table: table}
+	step := func(name string, fn func() (string, error)) bool {
+		start := time.Now()
+		detail, err := fn()
+		result := smokeStep{Step: name, OK: err == nil, Duration: time.Since(start), Detail: detail}
+		if err != nil {
+			result.Detail = err.Error()
+		}
+		report.Steps = append(report.Steps, result)
+		return err == nil
+	}
+
+	ok := step("database", func() (string, error) {
+		if _, err := client.Databases.Get(ctx, database); err == nil {
+			return "exists", nil
+		} else if !td.IsNotFound(err) {
+			return "", err
+		}
+		if _, err := client.Databases.Create(ctx, database); err != nil {
+			return "", err
+		}
+		return "created", nil
+	})
+	if !ok {
+		return report
+	}
+
+	if !step("create table", func() (string, error) {
+		_, err := client.Tables.Create(ctx, database, table, "log")
+		return "", err
+	}) {
+		return report
+	}
+	if !keep {
+		defer step("delete table", func() (string, error) {
+			// Clean up even when the scenario ran out of time
+			return "", client.Tables.Delete(context.WithoutCancel(ctx), database, table)
+		})
+	}
+
+	if !step("import", func() (string, error) {
+		now := time.Now().Unix()
+		records := make([]map[string]interface{}, rows)
+		for i := range records {
+			records[i] = map[string]interface{}{"time": now, "id": i, "name": fmt.Sprintf("row-%d", i)}
+		}
+		// The table name doubles as the unique ID, so a retried import is not
+		// counted twice
+		if _, err := client.Import.ImportRecords(ctx, database, table, table, records); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d rows", rows), nil
+	}) {
+		return report
+	}
+
+	step("query", func() (string, error) {
+		query := "SELECT COUNT(1) AS n FROM " + td.QualifiedName(table)
+		for attempt := 1; ; attempt++ {
+			count, jobID, err := smokeCount(ctx, client, database, query)
+			if err != nil {
+				return "", err
+			}
+			if count == int64(rows) {
+				return fmt.Sprintf("%d rows after %d queries (job %s)", count, attempt, jobID), nil
+			}
+			if count > int64(rows) {
+				return "", fmt.Errorf("counted %d rows, imported %d (job %s)", count, rows, jobID)
+			}
+			select {
+			case <-ctx.Done():
+				return "", fmt.Errorf("counted %d of %d rows before giving up (job %s)", count, rows, jobID)
+			case <-time.After(interval):
+			}
+		}
+	})
+	return report
+}
+
+// smokeCount runs a COUNT query and returns its single value
+func smokeCount(ctx context.Context, client *td.Client, database, query string) (int64, string, error) {
+	result, err := client.Queries.SubmitAndWait(ctx, td.SubmitRequest{
+		Type:     td.QueryTypeTrino,
+		Database: database,
+		Options:  td.IssueQueryOptions{Query: query},
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	jobID := result.Job.JobID
+
+	rows, err := result.Decoder(ctx)
+	if err != nil {
+		return 0, jobID, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, jobID, err
+		}
+		return 0, jobID, fmt.Errorf("job %s returned no rows", jobID)
+	}
+	n, ok := rows.Row()["n"].(json.Number)
+	if !ok {
+		return 0, jobID, fmt.Errorf("job %s returned %v, want a count", jobID, rows.Row()["n"])
+	}
+	count, err := n.Int64()
+	return count, jobID, err
+}
+
+func writeSmokeReport(report *smokeReport, flags Flags) error {
+	csvFormatter := func(data interface{}) string {
+		r := data.(*smokeReport)
+		var csvBuilder strings.Builder
+		for _, s := range r.Steps {
+			csvBuilder.WriteString(fmt.Sprintf("%s,%t,%d,%q\n", s.Step, s.OK, s.Duration.Milliseconds(), s.Detail))
+		}
+		return csvBuilder.String()
+	}
+
+	tableFormatter := func(data interface{}) string {
+		r := data.(*smokeReport)
+		var tableBuilder strings.Builder
+		fmt.Fprintf(&tableBuilder, "Table: %s.%s\n\n", r.Database, r.Table)
+		w := tabwriter.NewWriter(&tableBuilder, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "STEP\tSTATUS\tDURATION\tDETAIL")
+		for _, s := range r.Steps {
+			status := "ok"
+			if !s.OK {
+				status = "failed"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Step, status, s.Duration.Round(time.Millisecond), s.Detail)
+		}
+		w.Flush()
+		if r.OK() {
+			tableBuilder.WriteString("\nSmoke test passed\n")
+		}
+		return tableBuilder.String()
+	}
+
+	if err := formatAndWriteOutput(report, flags.Format, flags.Output, "step,ok,duration_ms,detail", csvFormatter, tableFormatter); err != nil {
+		return err
+	}
+	if !report.OK() {
+		return fmt.Errorf("smoke test failed")
+	}
+	return nil
+}
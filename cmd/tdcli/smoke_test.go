@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+func TestRunSmoke(t *testing.T) {
+	var deleted bool
+	counts := []int{0, 3}
+	queries := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/database/show/smoke", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+	})
+	mux.HandleFunc("/v3/database/create/smoke", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"database":"smoke"}`)
+	})
+	mux.HandleFunc("/v3/table/create/smoke/smoke_1/log", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"database":"smoke","table":"smoke_1","type":"log"}`)
+	})
+	mux.HandleFunc("/v3/table/import_with_id/smoke/smoke_1/smoke_1/msgpack.gz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"database":"smoke","table":"smoke_1","elapsed_time":0.1}`)
+	})
+	mux.HandleFunc("/v3/job/issue/trino/smoke", func(w http.ResponseWriter, r *http.Request) {
+		queries++
+		fmt.Fprintf(w, `{"job_id":"%d"}`, queries)
+	})
+	mux.HandleFunc("/v3/job/status/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"success"}`)
+	})
+	mux.HandleFunc("/v3/job/show/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/v3/job/show/")
+		fmt.Fprintf(w, `{"job_id":%q,"status":"success","hive_result_schema":"[[\"n\", \"bigint\"]]"}`, id)
+	})
+	mux.HandleFunc("/v3/job/result/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "[%d]\n", counts[queries-1])
+	})
+	mux.HandleFunc("/v3/table/delete/smoke/smoke_1", func(w http.ResponseWriter, r *http.Request) {
+		deleted = true
+		fmt.Fprint(w, `{"database":"smoke","table":"smoke_1"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := td.NewClient("1/test", td.WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := runSmoke(context.Background(), client, "smoke", "smoke_1", 3, time.Millisecond, false)
+	if !report.OK() {
+		t.Fatalf("smoke failed: %+v", report.Steps)
+	}
+	var steps []string
+	for _, s := range report.Steps {
+		steps = append(steps, s.Step)
+	}
+	if got := strings.Join(steps, ","); got != "database,create table,import,query,delete table" {
+		t.Errorf("steps = %s", got)
+	}
+	if report.Steps[0].Detail != "created" || !strings.Contains(report.Steps[3].Detail, "after 2 queries") {
+		t.Errorf("unexpected details: %+v", report.Steps)
+	}
+	if !deleted {
+		t.Error("table was not deleted")
+	}
+
+	// A count above the number of imported rows fails the run
+	counts, queries, deleted = []int{5}, 0, false
+	report = runSmoke(context.Background(), client, "smoke", "smoke_1", 3, time.Millisecond, false)
+	if report.OK() || !deleted {
+		t.Errorf("expected a failed run that still deletes the table: %+v", report.Steps)
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+	"gopkg.in/yaml.v3"
+)
+
+// tableManifest is the file read by "tables apply":
+//
+//	database: analytics
+//	tables:
+//	  - name: events
+//	    expire_days: 90
+//	    columns:
+//	      - {name: user_id, type: string}
+//	      - {name: amount, type: double}
+//	  - name: users
+//	    database: crm
+//	    columns:
+//	      - {name: email, type: string, alias: mail}
+type tableManifest struct {
+	// Database is the default for tables that do not name one
+	Database string               `yaml:"database"`
+	Tables   []tableManifestEntry `yaml:"tables"`
+}
+
+type tableManifestEntry struct {
+	Name       string                `yaml:"name"`
+	Database   string                `yaml:"database"`
+	ExpireDays *int                  `yaml:"expire_days"`
+	Columns    []tableManifestColumn `yaml:"columns"`
+}
+
+type tableManifestColumn struct {
+	Name  string `yaml:"name"`
+	Type  string `yaml:"type"`
+	Alias string `yaml:"alias"`
+}
+
+// loadTableManifest reads a YAML (or JSON) manifest and validates it
+func loadTableManifest(path string) ([]td.TableSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var manifest tableManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %v", path, err)
+	}
+	if len(manifest.Tables) == 0 {
+		return nil, fmt.Errorf("manifest %s declares no tables", path)
+	}
+
+	seen := map[string]bool{}
+	specs := make([]td.TableSpec, 0, len(manifest.Tables))
+	for i, entry := range manifest.Tables {
+		spec := td.TableSpec{Database: entry.Database, Name: entry.Name, ExpireDays: entry.ExpireDays}
+		if spec.Database == "" {
+			spec.Database = manifest.Database
+		}
+		if spec.Database == "" {
+			return nil, fmt.Errorf("table %d (%s): no database; set database on the table or at the top of the manifest", i+1, entry.Name)
+		}
+		if err := td.ValidateTableName(spec.Name); err != nil {
+			return nil, fmt.Errorf("table %d: %v", i+1, err)
+		}
+		key := spec.Database + "." + spec.Name
+		if seen[key] {
+			return nil, fmt.Errorf("table %s is declared twice", key)
+		}
+		seen[key] = true
+
+		columns := map[string]bool{}
+		for _, column := range entry.Columns {
+			if column.Name == "" || column.Type == "" {
+				return nil, fmt.Errorf("table %s: every column needs a name and a type", key)
+			}
+			if columns[column.Name] {
+				return nil, fmt.Errorf("table %s: column %s is declared twice", key, column.Name)
+			}
+			columns[column.Name] = true
+			spec.Columns = append(spec.Columns, td.TableColumn{Name: column.Name, Type: column.Type, Alias: column.Alias})
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// printTablePlan writes the changes as a diff and returns how many tables
+// change
+func printTablePlan(w io.Writer, changes []td.TableChange, verbose bool) int {
+	var create, update, unchanged int
+	for _, change := range changes {
+		name := change.Database + "." + change.Table
+		switch change.Action {
+		case td.TableActionCreate:
+			create++
+			fmt.Fprintf(w, "+ %s (create)\n", name)
+		case td.TableActionUpdate:
+			update++
+			fmt.Fprintf(w, "~ %s (update)\n", name)
+		default:
+			unchanged++
+			if verbose {
+				fmt.Fprintf(w, "  %s (no changes)\n", name)
+			}
+			continue
+		}
+		for _, column := range change.Added {
+			fmt.Fprintf(w, "    + %s\n", formatManifestColumn(column))
+		}
+		for _, column := range change.Retyped {
+			fmt.Fprintf(w, "    ~ %s\n", formatManifestColumn(column))
+		}
+		for _, column := range change.Removed {
+			fmt.Fprintf(w, "    - %s\n", formatManifestColumn(column))
+		}
+		if change.ExpireDays != nil {
+			fmt.Fprintf(w, "    expire_days = %d\n", *change.ExpireDays)
+		}
+	}
+	fmt.Fprintf(w, "Plan: %d to create, %d to update, %d unchanged\n", create, update, unchanged)
+	return create + update
+}
+
+func formatManifestColumn(column td.TableColumn) string {
+	if column.Alias != "" {
+		return fmt.Sprintf("%s %s (alias %s)", column.Name, column.Type, column.Alias)
+	}
+	return column.Name + " " + column.Type
+}
+
+// TablesApplyCmd creates and updates tables from a manifest
+type TablesApplyCmd struct {
+	Manifest    string `kong:"arg,help='YAML manifest of tables and their schemas'"`
+	Prune       bool   `kong:"help='Remove columns missing from the manifest from table schemas (the data is kept)'"`
+	Preview     bool   `kong:"help='Only print the changes'"`
+	Force       bool   `kong:"flag,help='Skip confirmation prompt'"`
+	Concurrency int    `kong:"help='Tables changed at once',default='4'"`
+}
+
+func (t *TablesApplyCmd) Run(ctx *CLIContext) error {
+	specs, err := loadTableManifest(t.Manifest)
+	if err != nil {
+		return err
+	}
+
+	changes, err := ctx.Client.Tables.Plan(ctx.Context, specs, &td.TablePlanOptions{Prune: t.Prune})
+	if err != nil {
+		return err
+	}
+	if printTablePlan(os.Stdout, changes, ctx.GlobalFlags.Verbose) == 0 {
+		fmt.Println("Nothing to do")
+		return nil
+	}
+	if t.Preview {
+		return nil
+	}
+	if !t.Force && !promptConfirmation("Apply these changes?") {
+		fmt.Println("Cancelled")
+		return nil
+	}
+
+	if err := ctx.Client.Tables.Apply(ctx.Context, changes, t.Concurrency); err != nil {
+		return fmt.Errorf("failed to apply manifest: %w", err)
+	}
+	fmt.Println("Manifest applied")
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+func TestLoadTableManifest(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "schema.yaml")
+	manifest := `database: analytics
+tables:
+  - name: events
+    expire_days: 90
+    columns:
+      - {name: user_id, type: string}
+      - {name: amount, type: double}
+  - name: users
+    database: crm
+    columns:
+      - {name: email, type: string, alias: mail}
+`
+	if err := os.WriteFile(path, []byte(manifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	specs, err := loadTableManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(specs) != 2 || specs[0].Database != "analytics" || *specs[0].ExpireDays != 90 || len(specs[0].Columns) != 2 {
+		t.Fatalf("specs = %+v", specs)
+	}
+	if specs[1].Database != "crm" || specs[1].Columns[0].Alias != "mail" {
+		t.Errorf("users spec = %+v", specs[1])
+	}
+
+	bad := map[string]string{
+		"no database":      "tables:\n  - name: events\n",
+		"duplicate table":  "database: a\ntables:\n  - name: t\n  - name: t\n",
+		"duplicate column": "database: a\ntables:\n  - name: t\n    columns:\n      - {name: c, type: long}\n      - {name: c, type: long}\n",
+		"missing type":     "database: a\ntables:\n  - name: t\n    columns:\n      - {name: c}\n",
+		"no tables":        "database: a\n",
+	}
+	for name, content := range bad {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadTableManifest(path); err == nil {
+			t.Errorf("%s: manifest was accepted", name)
+		}
+	}
+}
+
+func TestPrintTablePlan(t *testing.T) {
+	ninety := 90
+	changes := []td.TableChange{
+		{Database: "a", Table: "new", Action: td.TableActionCreate, Added: []td.TableColumn{{Name: "id", Type: "long"}}},
+		{Database: "a", Table: "old", Action: td.TableActionUpdate, Removed: []td.TableColumn{{Name: "x", Type: "string"}}, ExpireDays: &ninety},
+		{Database: "a", Table: "same", Action: td.TableActionNone},
+	}
+	var buf bytes.Buffer
+	if n := printTablePlan(&buf, changes, false); n != 2 {
+		t.Errorf("changed = %d, want 2", n)
+	}
+	for _, want := range []string{"+ a.new (create)", "    + id long", "~ a.old (update)", "    - x string", "expire_days = 90", "Plan: 1 to create, 1 to update, 1 unchanged"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("plan output missing %q:\n%s", want, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), "a.same") {
+		t.Error("unchanged table printed without verbose")
+	}
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// Telemetry is opt-in: nothing is recorded unless the config sets
+// telemetry = "on". Events only carry the command name, duration, an error
+// class, and the tdcli version and platform; never arguments, data, or keys.
+
+const (
+	// telemetrySpoolLimit caps the spool so an unreachable endpoint cannot
+	// grow it without bound
+	telemetrySpoolLimit = 256 * 1024
+	// telemetryFlushBatch is the number of spooled events that triggers a flush
+	telemetryFlushBatch = 20
+	// telemetryFlushInterval flushes smaller batches at least this often
+	telemetryFlushInterval = 24 * time.Hour
+)
+
+// telemetryEvent is one spooled command execution
+type telemetryEvent struct {
+	InstallID  string    `json:"install_id"`
+	Command    string    `json:"command"`
+	DurationMS int64     `json:"duration_ms"`
+	ErrorClass string    `json:"error_class,omitempty"`
+	Version    string    `json:"version"`
+	OS         string    `json:"os"`
+	Arch       string    `json:"arch"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// telemetry records command executions to the local spool
+type telemetry struct {
+	dir      string
+	endpoint string
+	command  string
+	start    time.Time
+	recorded bool
+}
+
+// activeTelemetry is set while a command runs with telemetry enabled, so
+// handleError can record failures before exiting
+var activeTelemetry *telemetry
+
+// telemetryEnabled reports whether the user opted in. TDCLI_TELEMETRY=off
+// overrides the config file.
+func telemetryEnabled(config *Config) bool {
+	if env := os.Getenv("TDCLI_TELEMETRY"); env != "" {
+		return parseTelemetrySetting(env)
+	}
+	return config != nil && parseTelemetrySetting(config.Telemetry)
+}
+
+// telemetryEndpoint returns where events are sent; TDCLI_TELEMETRY_ENDPOINT
+// overrides the config file
+func telemetryEndpoint(config *Config) string {
+	if env := os.Getenv("TDCLI_TELEMETRY_ENDPOINT"); env != "" {
+		return env
+	}
+	return config.TelemetryEndpoint
+}
+
+func parseTelemetrySetting(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "on", "true", "1", "yes":
+		return true
+	}
+	return false
+}
+
+func defaultTelemetryDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".tdcli", "telemetry"), nil
+}
+
+// startTelemetry begins timing a command when telemetry is enabled
+func startTelemetry(config *Config, command string) *telemetry {
+	if !telemetryEnabled(config) {
+		return nil
+	}
+	dir, err := defaultTelemetryDir()
+	if err != nil {
+		return nil
+	}
+	return &telemetry{dir: dir, endpoint: telemetryEndpoint(config), command: telemetryCommandName(command), start: time.Now()}
+}
+
+// telemetryCommandName strips argument placeholders such as <query> from a
+// kong command path so that only the command itself is recorded
+func telemetryCommandName(command string) string {
+	var words []string
+	for _, word := range strings.Fields(command) {
+		if !strings.HasPrefix(word, "<") {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// classifyTelemetryError maps an error to a coarse class that reveals nothing
+// about the request
+func classifyTelemetryError(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, td.ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, td.ErrBadRequest):
+		return "bad_request"
+	case errors.Is(err, td.ErrUnauthorized):
+		return "unauthorized"
+	case errors.Is(err, td.ErrForbidden):
+		return "forbidden"
+	case errors.Is(err, td.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, td.ErrConflict):
+		return "conflict"
+	case errors.Is(err, td.ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, td.ErrServerError):
+		return "server_error"
+	}
+	var policyErr *td.PolicyViolationError
+	if errors.As(err, &policyErr) {
+		return "policy"
+	}
+	return "other"
+}
+
+// finish records the command outcome and starts a background flush when due.
+// Only the first call records; later calls are no-ops.
+func (t *telemetry) finish(err error) {
+	if t == nil || t.recorded {
+		return
+	}
+	t.recorded = true
+
+	event := telemetryEvent{
+		InstallID:  t.installID(),
+		Command:    t.command,
+		DurationMS: time.Since(t.start).Milliseconds(),
+		ErrorClass: classifyTelemetryError(err),
+		Version:    version,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		Timestamp:  time.Now().UTC().Truncate(time.Hour),
+	}
+	if err := t.spool(event); err != nil {
+		return
+	}
+	if t.endpoint != "" && t.flushDue() {
+		t.flushInBackground()
+	}
+}
+
+func (t *telemetry) spoolPath() string {
+	return filepath.Join(t.dir, "spool.jsonl")
+}
+
+// installID returns a random identifier generated on first use. It is not
+// derived from the account or machine.
+func (t *telemetry) installID() string {
+	path := filepath.Join(t.dir, "install_id")
+	if data, err := os.ReadFile(path); err == nil && len(data) > 0 {
+		return strings.TrimSpace(string(data))
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	id := hex.EncodeToString(b)
+	if err := os.MkdirAll(t.dir, 0700); err == nil {
+		os.WriteFile(path, []byte(id), 0600)
+	}
+	return id
+}
+
+func (t *telemetry) spool(event telemetryEvent) error {
+	if err := os.MkdirAll(t.dir, 0700); err != nil {
+		return err
+	}
+	if info, err := os.Stat(t.spoolPath()); err == nil && info.Size() > telemetrySpoolLimit {
+		return fmt.Errorf("telemetry spool is full")
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(t.spoolPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// flushDue reports whether enough events are spooled or the last flush is old
+func (t *telemetry) flushDue() bool {
+	if info, err := os.Stat(filepath.Join(t.dir, "last_flush")); err != nil || time.Since(info.ModTime()) > telemetryFlushInterval {
+		return true
+	}
+	events, _ := readTelemetrySpool(t.spoolPath())
+	return len(events) >= telemetryFlushBatch
+}
+
+// flushInBackground re-executes tdcli to send the spool so the current command
+// does not wait on the network
+func (t *telemetry) flushInBackground() {
+	exe, err := os.Executable()
+	if err != nil {
+		return
+	}
+	cmd := exec.Command(exe, "telemetry-flush")
+	cmd.Env = append(os.Environ(), "TDCLI_TELEMETRY=on")
+	if err := cmd.Start(); err == nil {
+		cmd.Process.Release()
+	}
+}
+
+func readTelemetrySpool(path string) ([]telemetryEvent, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var events []telemetryEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event telemetryEvent
+		if json.Unmarshal(scanner.Bytes(), &event) == nil {
+			events = append(events, event)
+		}
+	}
+	return events, scanner.Err()
+}
+
+// flushTelemetry sends spooled events to endpoint. The spool is moved aside
+// first so commands running concurrently keep appending to a fresh file; if
+// sending fails the events are kept for the next flush.
+func flushTelemetry(ctx context.Context, dir, endpoint string) error {
+	if endpoint == "" {
+		return fmt.Errorf("telemetry_endpoint is not configured")
+	}
+
+	spool := filepath.Join(dir, "spool.jsonl")
+	sending := filepath.Join(dir, fmt.Sprintf("sending-%d.jsonl", os.Getpid()))
+	if err := os.Rename(spool, sending); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// Include batches left behind by earlier failed flushes
+	paths, _ := filepath.Glob(filepath.Join(dir, "sending-*.jsonl"))
+	var events []telemetryEvent
+	for _, path := range paths {
+		batch, err := readTelemetrySpool(path)
+		if err != nil {
+			continue
+		}
+		events = append(events, batch...)
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"events": events})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "tdcli/"+version)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("telemetry endpoint returned %s", resp.Status)
+	}
+
+	for _, path := range paths {
+		os.Remove(path)
+	}
+	now := time.Now()
+	marker := filepath.Join(dir, "last_flush")
+	if err := os.WriteFile(marker, nil, 0600); err == nil {
+		os.Chtimes(marker, now, now)
+	}
+	return nil
+}
+
+// TelemetryFlushCmd sends the telemetry spool; it is started in the background
+type TelemetryFlushCmd struct{}
+
+func (t *TelemetryFlushCmd) Run(ctx *CLIContext) error {
+	config, err := LoadConfig()
+	if err != nil || !telemetryEnabled(config) {
+		return nil
+	}
+	dir, err := defaultTelemetryDir()
+	if err != nil {
+		return err
+	}
+	return flushTelemetry(ctx.Context, dir, telemetryEndpoint(config))
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+func TestTelemetryEnabled(t *testing.T) {
+	t.Setenv("TDCLI_TELEMETRY", "")
+	if telemetryEnabled(DefaultConfig()) {
+		t.Error("Telemetry should be off by default")
+	}
+	if !telemetryEnabled(&Config{Telemetry: "on"}) {
+		t.Error("Telemetry should be on when configured")
+	}
+
+	t.Setenv("TDCLI_TELEMETRY", "off")
+	if telemetryEnabled(&Config{Telemetry: "on"}) {
+		t.Error("TDCLI_TELEMETRY=off should override the config")
+	}
+}
+
+func TestTelemetryCommandName(t *testing.T) {
+	if got := telemetryCommandName("query submit <query>"); got != "query submit" {
+		t.Errorf("telemetryCommandName = %q, want %q", got, "query submit")
+	}
+}
+
+func TestClassifyTelemetryError(t *testing.T) {
+	notFound := &td.ErrorResponse{Response: &http.Response{StatusCode: 404}, Message: "table secret_table does not exist"}
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{fmt.Errorf("get table: %w", notFound), "not_found"},
+		{context.Canceled, "canceled"},
+		{&td.PolicyViolationError{Policy: "max-priority"}, "policy"},
+		{errors.New("open /home/user/data.csv: permission denied"), "other"},
+	}
+	for _, tt := range tests {
+		if got := classifyTelemetryError(tt.err); got != tt.want {
+			t.Errorf("classifyTelemetryError(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestTelemetry_SpoolAndFlush(t *testing.T) {
+	dir := t.TempDir()
+
+	var received []telemetryEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Events []telemetryEvent `json:"events"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		received = append(received, body.Events...)
+	}))
+	defer server.Close()
+
+	// No endpoint on the recorder so finish does not spawn a flush process
+	for _, err := range []error{nil, errors.New("boom")} {
+		tel := &telemetry{dir: dir, command: "tables list", start: time.Now()}
+		tel.finish(err)
+		tel.finish(err)
+	}
+
+	events, err := readTelemetrySpool(filepath.Join(dir, "spool.jsonl"))
+	if err != nil {
+		t.Fatalf("readTelemetrySpool: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Spooled %d events, want 2", len(events))
+	}
+	if events[0].InstallID == "" || events[0].InstallID != events[1].InstallID {
+		t.Errorf("Install IDs = %q, %q, want the same non-empty ID", events[0].InstallID, events[1].InstallID)
+	}
+	if events[1].ErrorClass != "other" {
+		t.Errorf("ErrorClass = %q, want other", events[1].ErrorClass)
+	}
+
+	if err := flushTelemetry(context.Background(), dir, server.URL); err != nil {
+		t.Fatalf("flushTelemetry: %v", err)
+	}
+	if len(received) != 2 || received[0].Command != "tables list" {
+		t.Errorf("Received %+v, want 2 tables list events", received)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "spool.jsonl")); !os.IsNotExist(err) {
+		t.Error("Spool should be removed after a successful flush")
+	}
+	paths, _ := filepath.Glob(filepath.Join(dir, "sending-*.jsonl"))
+	if len(paths) != 0 {
+		t.Errorf("Leftover batches: %v", paths)
+	}
+}
+
+func TestFlushTelemetry_KeepsEventsOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	tel := &telemetry{dir: dir, command: "jobs list", start: time.Now()}
+	tel.finish(nil)
+
+	err := flushTelemetry(context.Background(), dir, server.URL)
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("flushTelemetry error = %v, want 503", err)
+	}
+	paths, _ := filepath.Glob(filepath.Join(dir, "sending-*.jsonl"))
+	if len(paths) != 1 {
+		t.Errorf("Expected the failed batch to be kept, got %v", paths)
+	}
+}
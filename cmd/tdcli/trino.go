@@ -587,16 +587,21 @@ func handleTrinoInteractive(ctx context.Context, _ *td.Client, _ []string, flags
 		case strings.HasPrefix(lowerInput, "show tables from "):
 			// Extract database name and show tables
 			dbName := strings.TrimSpace(input[17:]) // Remove "show tables from "
-			dbName = strings.Trim(dbName, `"'`)     // Remove quotes
-			input = fmt.Sprintf("SHOW TABLES FROM %s", td.EscapeIdentifier(dbName))
+			dbName = strings.Trim(dbName, `'`)      // Accept 'db' as well as "db"
+			parts, err := td.ParseQualifiedName(dbName)
+			if err != nil || len(parts) != 1 {
+				fmt.Printf("❌ Invalid database name: %s\n", dbName)
+				continue
+			}
+			input = fmt.Sprintf("SHOW TABLES FROM %s", td.QualifiedName(parts[0]))
 		case strings.HasPrefix(lowerInput, "describe "):
 			// Enhance describe to work with current database context
-			tableName := strings.TrimSpace(input[9:]) // Remove "describe "
-			if !strings.Contains(tableName, ".") {
-				// If no database specified, use current database
-				tableName = fmt.Sprintf("%s.%s", td.EscapeIdentifier(currentDatabase), td.EscapeIdentifier(tableName))
+			query, err := describeQuery(input[9:], currentDatabase) // Remove "describe "
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				continue
 			}
-			input = fmt.Sprintf("DESCRIBE %s", tableName)
+			input = query
 		}
 
 		// Execute query with cancellation support
@@ -847,7 +852,7 @@ func (t *trinoAutoCompleter) refreshTableCache() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := fmt.Sprintf("SHOW TABLES FROM %s", td.EscapeIdentifier(*t.database))
+	query := fmt.Sprintf("SHOW TABLES FROM %s", td.QualifiedName(*t.database))
 	rows, err := t.client.Query(ctx, query)
 	if err != nil {
 		return // Silently fail to avoid disrupting user experience
@@ -1017,7 +1022,7 @@ func switchDatabase(trinoClient **td.TDTrinoClient, config *td.TDTrinoClientConf
 	fmt.Printf("🔄 Switching to database '%s'...\\n", newDB)
 
 	// Step 1: Validate database exists by trying to access it
-	testQuery := fmt.Sprintf("SHOW TABLES FROM %s LIMIT 1", td.EscapeIdentifier(newDB))
+	testQuery := fmt.Sprintf("SHOW TABLES FROM %s LIMIT 1", td.QualifiedName(newDB))
 	testRows, testErr := (*trinoClient).Query(ctx, testQuery)
 	if testErr != nil {
 		fmt.Printf("❌ Cannot access database '%s'\\n", newDB)
@@ -1147,24 +1152,49 @@ Examples:
   SELECT * FROM nasdaq LIMIT 10;`)
 }
 
+// qualifiedTable quotes a table name as typed by the user, qualifying
+// unqualified names with database
+func qualifiedTable(table, database string) (string, error) {
+	parts, err := td.ParseQualifiedName(table)
+	if err != nil {
+		return "", fmt.Errorf("invalid table name: %w", err)
+	}
+	if len(parts) > 3 {
+		return "", fmt.Errorf("invalid table name %q: too many parts", table)
+	}
+	if len(parts) == 1 {
+		parts = []string{database, parts[0]}
+	}
+	return td.QualifiedName(parts...), nil
+}
+
+// describeQuery builds a DESCRIBE statement for a table name as typed by the user
+func describeQuery(table, database string) (string, error) {
+	name, err := qualifiedTable(table, database)
+	if err != nil {
+		return "", err
+	}
+	return "DESCRIBE " + name, nil
+}
+
 // handleTrinoDescribe describes a table structure
 func handleTrinoDescribe(ctx context.Context, client *td.Client, args []string, flags Flags) {
 	if len(args) == 0 {
 		log.Fatal("Table name is required")
 	}
 
-	tableName := args[0]
-
-	// Escape table name for safety
-	escapedTable := td.EscapeIdentifier(tableName)
-	query := fmt.Sprintf("DESCRIBE %s", escapedTable)
+	query, err := describeQuery(args[0], flags.Database)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// Execute as a regular query
 	handleTrinoQuery(ctx, client, []string{query}, flags)
 }
 
-// handleTrinoShow executes SHOW commands
-func handleTrinoShow(ctx context.Context, client *td.Client, args []string, flags Flags) {
+// handleTrinoShow executes SHOW commands. like filters schemas and tables to
+// names containing it.
+func handleTrinoShow(ctx context.Context, client *td.Client, args []string, like string, flags Flags) {
 	if len(args) == 0 {
 		log.Fatal("SHOW command type required (schemas, tables, columns)")
 	}
@@ -1177,7 +1207,7 @@ func handleTrinoShow(ctx context.Context, client *td.Client, args []string, flag
 		query = "SHOW SCHEMAS"
 	case "tables":
 		if flags.Database != "" {
-			query = fmt.Sprintf("SHOW TABLES FROM %s", td.EscapeIdentifier(flags.Database))
+			query = fmt.Sprintf("SHOW TABLES FROM %s", td.QualifiedName(flags.Database))
 		} else {
 			query = "SHOW TABLES"
 		}
@@ -1185,11 +1215,20 @@ func handleTrinoShow(ctx context.Context, client *td.Client, args []string, flag
 		if len(args) < 2 {
 			log.Fatal("Table name required for SHOW COLUMNS")
 		}
-		tableName := td.EscapeIdentifier(args[1])
-		query = fmt.Sprintf("SHOW COLUMNS FROM %s", tableName)
+		tableName, err := qualifiedTable(args[1], flags.Database)
+		if err != nil {
+			log.Fatal(err)
+		}
+		query = "SHOW COLUMNS FROM " + tableName
 	default:
 		log.Fatalf("Unknown SHOW command: %s", showType)
 	}
+	if like != "" {
+		if showType == "columns" {
+			log.Fatal("--like applies to schemas and tables")
+		}
+		query += " LIKE " + td.LikeContains(like)
+	}
 
 	// Execute as a regular query
 	handleTrinoQuery(ctx, client, []string{query}, flags)
@@ -11,8 +11,6 @@ import (
 	"strings"
 	"testing"
 	"time"
-
-	td "github.com/mickeey2525/treasuredata-go-sdk"
 )
 
 func TestTrinoQueryCmd_Run(t *testing.T) {
@@ -276,24 +274,35 @@ func TestTableQualificationLogic(t *testing.T) {
 			name:            "describe with qualified table name",
 			input:           "describe sample_datasets.nasdaq",
 			currentDatabase: "other_db",
-			expectedQuery:   "DESCRIBE sample_datasets.nasdaq",
+			expectedQuery:   `DESCRIBE "sample_datasets"."nasdaq"`,
+		},
+		{
+			name:            "describe with quoted parts",
+			input:           `describe "my.db"."t""1"`,
+			currentDatabase: "other_db",
+			expectedQuery:   `DESCRIBE "my.db"."t""1"`,
+		},
+		{
+			name:            "describe with injected statement",
+			input:           "describe nasdaq; DROP TABLE nasdaq",
+			currentDatabase: "sample_datasets",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			lowerInput := strings.ToLower(tt.input)
-			if strings.HasPrefix(lowerInput, "describe ") {
-				tableName := strings.TrimSpace(tt.input[9:]) // Remove "describe "
-				if !strings.Contains(tableName, ".") {
-					// If no database specified, use current database
-					tableName = fmt.Sprintf("%s.%s", td.EscapeIdentifier(tt.currentDatabase), td.EscapeIdentifier(tableName))
-				}
-				query := fmt.Sprintf("DESCRIBE %s", tableName)
-
-				if query != tt.expectedQuery {
-					t.Errorf("Expected query %q, got %q", tt.expectedQuery, query)
+			query, err := describeQuery(tt.input[9:], tt.currentDatabase) // Remove "describe "
+			if tt.expectedQuery == "" {
+				if err == nil {
+					t.Errorf("Expected error, got query %q", query)
 				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("describeQuery returned error: %v", err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("Expected query %q, got %q", tt.expectedQuery, query)
 			}
 		})
 	}
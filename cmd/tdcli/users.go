@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	td "github.com/mickeey2525/treasuredata-go-sdk"
 )
@@ -158,3 +159,74 @@ func handleUserGet(ctx context.Context, client *td.Client, args []string, flags
 		handleError(err, "Failed to write output", flags.Verbose)
 	}
 }
+
+func handleUserActivity(ctx context.Context, client *td.Client, since time.Time, flags Flags) {
+	activity, err := client.Users.Activity(ctx, &td.UserActivityOptions{Since: since})
+	if err != nil {
+		handleError(err, "Failed to get user activity", flags.Verbose)
+		return
+	}
+
+	lastJob := func(a td.UserActivity) string {
+		if a.LastJobAt.IsZero() {
+			return ""
+		}
+		return a.LastJobAt.Format("2006-01-02 15:04:05")
+	}
+
+	csvFormatter := func(data interface{}) string {
+		activity := data.([]td.UserActivity)
+		var csvBuilder strings.Builder
+		for _, a := range activity {
+			csvBuilder.WriteString(fmt.Sprintf("%s,%s,%t,%d,%d,%s,%s,%s\n",
+				a.User.Name,
+				a.User.Email,
+				a.User.Administrator,
+				a.JobCount,
+				a.FailedJobCount,
+				lastJob(a),
+				a.LastJobID,
+				strings.Join(a.Databases, ";"),
+			))
+		}
+		return csvBuilder.String()
+	}
+
+	tableFormatter := func(data interface{}) string {
+		activity := data.([]td.UserActivity)
+		if len(activity) == 0 {
+			return "No users found\n"
+		}
+		var tableBuilder strings.Builder
+		w := tabwriter.NewWriter(&tableBuilder, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tEMAIL\tJOBS\tFAILED\tLAST JOB\tDATABASES")
+		inactive := 0
+		for _, a := range activity {
+			last := lastJob(a)
+			if last == "" {
+				last = "-"
+				inactive++
+			}
+			email := a.User.Email
+			if email == "" {
+				email = "(not in user list)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\n",
+				a.User.Name,
+				email,
+				a.JobCount,
+				a.FailedJobCount,
+				last,
+				strings.Join(a.Databases, ","),
+			)
+		}
+		w.Flush()
+		tableBuilder.WriteString(fmt.Sprintf("\nJobs since %s; %d of %d users ran no jobs\n",
+			since.Format("2006-01-02"), inactive, len(activity)))
+		return tableBuilder.String()
+	}
+
+	if err := formatAndWriteOutput(activity, flags.Format, flags.Output, "name,email,administrator,job_count,failed_job_count,last_job_at,last_job_id,databases", csvFormatter, tableFormatter); err != nil {
+		handleError(err, "Failed to write output", flags.Verbose)
+	}
+}
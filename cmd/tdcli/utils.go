@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 
 	td "github.com/mickeey2525/treasuredata-go-sdk"
@@ -55,3 +56,15 @@ func formatAndWriteOutput(data interface{}, format, outputFile string, csvHeader
 
 	return writeOutput(output, outputFile)
 }
+
+// printPlan lists the requests captured by --plan
+func printPlan(w io.Writer, plan []td.PlannedRequest) {
+	if len(plan) == 0 {
+		fmt.Fprintln(w, "Plan: no changes would be made")
+		return
+	}
+	fmt.Fprintf(w, "Plan: %d request(s) not sent:\n", len(plan))
+	for i, req := range plan {
+		fmt.Fprintf(w, "\n%d. %s\n", i+1, req)
+	}
+}
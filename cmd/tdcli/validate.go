@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// ValidateCmd checks input locally, without calling the API
+type ValidateCmd struct {
+	Name ValidateNameCmd `kong:"cmd,help='Check a resource name against Treasure Data naming rules'"`
+}
+
+type ValidateNameCmd struct {
+	Kind string `kong:"arg,enum='database,table,audience,segment,folder,project,workflow',help='Resource type (database, table, audience, segment, folder, project, workflow)'"`
+	Name string `kong:"arg,help='Name to check'"`
+}
+
+func (v *ValidateNameCmd) Run(ctx *CLIContext) error {
+	if err := td.ValidateName(td.NameKind(v.Kind), v.Name); err != nil {
+		return err
+	}
+	fmt.Printf("%s name %q is valid\n", v.Kind, v.Name)
+	return nil
+}
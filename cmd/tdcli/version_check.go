@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+const (
+	// versionCheckInterval throttles both release lookups and printed hints
+	versionCheckInterval = 24 * time.Hour
+	// versionCheckTimeout bounds the background release lookup
+	versionCheckTimeout = 3 * time.Second
+)
+
+// versionCheckState is persisted between runs in ~/.tdcli/version_check.json
+type versionCheckState struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest,omitempty"`
+	HintedAt  time.Time `json:"hinted_at"`
+}
+
+// versionChecker looks up the latest release in the background while a
+// command runs, and watches API responses for Deprecation and Sunset headers,
+// which mean this CLI uses endpoints the API is retiring
+type versionChecker struct {
+	path  string
+	state versionCheckState
+	now   func() time.Time
+
+	mu         sync.Mutex
+	latest     string
+	deprecated string
+}
+
+// versionCheckEnabled reports whether the check is on. It is skipped for
+// development builds and can be turned off with version_check = "off".
+func versionCheckEnabled(config *Config) bool {
+	if version == "dev" || os.Getenv("TDCLI_NO_VERSION_CHECK") != "" {
+		return false
+	}
+	return config == nil || config.VersionCheck != "off"
+}
+
+// startVersionCheck loads the saved state and, when it is stale, refreshes the
+// latest release in the background. It returns nil when checks are disabled.
+func startVersionCheck(config *Config) *versionChecker {
+	if !versionCheckEnabled(config) {
+		return nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	v := &versionChecker{path: filepath.Join(homeDir, ".tdcli", "version_check.json"), now: time.Now}
+	if data, err := os.ReadFile(v.path); err == nil {
+		json.Unmarshal(data, &v.state)
+	}
+
+	if v.now().Sub(v.state.CheckedAt) > versionCheckInterval {
+		go v.refresh()
+	}
+	return v
+}
+
+func (v *versionChecker) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), versionCheckTimeout)
+	defer cancel()
+
+	httpClient, err := newUpdateHTTPClient("")
+	if err != nil {
+		return
+	}
+	release, err := latestRelease(ctx, httpClient, updateChannelStable)
+	if err != nil {
+		return
+	}
+
+	v.mu.Lock()
+	v.latest = strings.TrimPrefix(release.TagName, "v")
+	v.mu.Unlock()
+}
+
+// middleware records endpoints the API reports as deprecated
+func (v *versionChecker) middleware() td.Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return td.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(req)
+			if resp != nil && (resp.Header.Get("Deprecation") != "" || resp.Header.Get("Sunset") != "") {
+				v.mu.Lock()
+				v.deprecated = req.Method + " " + req.URL.Path
+				v.mu.Unlock()
+			}
+			return resp, err
+		})
+	}
+}
+
+// finish saves the state and prints at most one hint per interval to w
+func (v *versionChecker) finish(w io.Writer) {
+	if v == nil {
+		return
+	}
+
+	v.mu.Lock()
+	if v.latest != "" {
+		v.state.Latest = v.latest
+		v.state.CheckedAt = v.now()
+	}
+	deprecated := v.deprecated
+	v.mu.Unlock()
+
+	var hint string
+	switch {
+	case deprecated != "":
+		hint = fmt.Sprintf("The Treasure Data API reports %s as deprecated; update tdcli with \"tdcli self-update\".", deprecated)
+	case v.state.Latest != "" && significantlyOutdated(strings.TrimPrefix(version, "v"), v.state.Latest):
+		hint = fmt.Sprintf("tdcli %s is available (you have %s); update with \"tdcli self-update\".", v.state.Latest, strings.TrimPrefix(version, "v"))
+	}
+	if hint != "" && v.now().Sub(v.state.HintedAt) > versionCheckInterval {
+		fmt.Fprintf(w, "Hint: %s Disable with \"tdcli config set version_check off\".\n", hint)
+		v.state.HintedAt = v.now()
+	}
+
+	if data, err := json.Marshal(v.state); err == nil {
+		if err := os.MkdirAll(filepath.Dir(v.path), 0700); err == nil {
+			os.WriteFile(v.path, data, 0600)
+		}
+	}
+}
+
+// significantlyOutdated reports whether latest is a major version, or two or
+// more minor versions, ahead of current. Patch releases alone do not warrant
+// a hint.
+func significantlyOutdated(current, latest string) bool {
+	curMajor, curMinor, ok1 := majorMinor(current)
+	latMajor, latMinor, ok2 := majorMinor(latest)
+	if !ok1 || !ok2 {
+		return false
+	}
+	if latMajor != curMajor {
+		return latMajor > curMajor
+	}
+	return latMinor-curMinor >= 2
+}
+
+func majorMinor(v string) (int, int, bool) {
+	core, _, _ := strings.Cut(v, "-")
+	parts := strings.Split(core, ".")
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err1 := strconv.Atoi(parts[0])
+	minor, err2 := strconv.Atoi(parts[1])
+	return major, minor, err1 == nil && err2 == nil
+}
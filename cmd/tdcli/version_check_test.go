@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+func TestSignificantlyOutdated(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"1.2.0", "1.2.5", false},
+		{"1.2.0", "1.3.0", false},
+		{"1.2.0", "1.4.0", true},
+		{"1.9.0", "2.0.0", true},
+		{"2.0.0", "1.9.0", false},
+		{"1.2.0", "garbage", false},
+	}
+	for _, tt := range tests {
+		if got := significantlyOutdated(tt.current, tt.latest); got != tt.want {
+			t.Errorf("significantlyOutdated(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
+
+func TestVersionChecker_HintIsThrottled(t *testing.T) {
+	oldVersion := version
+	version = "1.0.0"
+	defer func() { version = oldVersion }()
+
+	now := time.Now()
+	v := &versionChecker{path: filepath.Join(t.TempDir(), "version_check.json"), now: func() time.Time { return now }}
+	v.latest = "1.5.0"
+
+	var out bytes.Buffer
+	v.finish(&out)
+	if !strings.Contains(out.String(), "tdcli 1.5.0 is available") {
+		t.Errorf("Expected an update hint, got %q", out.String())
+	}
+
+	out.Reset()
+	v.finish(&out)
+	if out.Len() != 0 {
+		t.Errorf("Expected no second hint within the interval, got %q", out.String())
+	}
+}
+
+func TestVersionChecker_DeprecationHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Write([]byte(`{"databases": []}`))
+	}))
+	defer server.Close()
+
+	v := &versionChecker{path: filepath.Join(t.TempDir(), "version_check.json"), now: time.Now}
+	client, err := td.NewClient("1/abc", td.WithEndpoint(server.URL), td.WithMiddleware(v.middleware()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Databases.List(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	v.finish(&out)
+	if !strings.Contains(out.String(), "GET /v3/database/list as deprecated") {
+		t.Errorf("Expected a deprecation hint, got %q", out.String())
+	}
+}
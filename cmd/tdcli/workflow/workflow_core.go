@@ -15,15 +15,21 @@ import (
 
 // Workflow handlers
 func HandleWorkflowList(ctx context.Context, client *td.Client, flags Flags) {
-	opts := &td.WorkflowListOptions{
-		Limit:  100,
-		Offset: 0,
-	}
+	HandleWorkflowListFiltered(ctx, client, nil, 0, flags)
+}
 
-	resp, err := client.Workflow.ListWorkflows(ctx, opts)
-	if err != nil {
+// HandleWorkflowListFiltered lists the workflows matching opts, following
+// pagination until limit workflows are found (0 for all)
+func HandleWorkflowListFiltered(ctx context.Context, client *td.Client, opts *td.WorkflowListOptions, limit int, flags Flags) {
+	var workflows []td.Workflow
+	it := client.Workflow.ListAllWorkflows(ctx, opts)
+	for (limit <= 0 || len(workflows) < limit) && it.Next(ctx) {
+		workflows = append(workflows, it.Value())
+	}
+	if err := it.Err(); err != nil {
 		HandleError(err, "Failed to list workflows", flags.Verbose)
 	}
+	resp := &td.WorkflowListResponse{Workflows: workflows}
 
 	switch flags.Format {
 	case "json":
@@ -160,6 +166,17 @@ SELECT count(1) FROM www_access;
 		HandleError(err, "Failed to create sample_query.sql file", flags.Verbose)
 	}
 
+	// Create td-project.yml, shown by "workflow projects get" once pushed
+	metadataContent := `# Who to contact when this project's workflows fail
+owner: ""
+description: ""
+runbook_url: ""
+`
+	metadataPath := filepath.Join(projectName, td.WorkflowProjectMetadataFile)
+	if err := os.WriteFile(metadataPath, []byte(metadataContent), 0644); err != nil {
+		HandleError(err, "Failed to create "+td.WorkflowProjectMetadataFile+" file", flags.Verbose)
+	}
+
 	fmt.Printf("✅ Sample workflow project '%s' created successfully.\n", projectName)
 	fmt.Println("To push this project to Treasure Data, run:")
 	fmt.Printf("  tdcli workflow projects push %s %s\n", projectName, projectName)
@@ -7,8 +7,11 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
 )
 
 func TestHandleWorkflowList(t *testing.T) {
@@ -519,3 +522,41 @@ func TestHandleWorkflowListJSON(t *testing.T) {
 		}
 	}
 }
+
+func TestHandleWorkflowListFiltered(t *testing.T) {
+	client, mux, teardown := setupWorkflowTest()
+	defer teardown()
+
+	// Two full pages of 100 and a short one, so listing must go past 100
+	mux.HandleFunc("/api/workflows", func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		count := 100
+		if offset >= 200 {
+			count = 5
+		}
+		var workflows []string
+		for i := 0; i < count; i++ {
+			project := "other"
+			if (offset+i)%50 == 0 {
+				project = "etl"
+			}
+			workflows = append(workflows, fmt.Sprintf(`{"id": "%d", "name": "wf%d", "project": {"name": %q}, "status": "active"}`, offset+i, offset+i, project))
+		}
+		fmt.Fprintf(w, `{"workflows": [%s]}`, strings.Join(workflows, ","))
+	})
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	HandleWorkflowListFiltered(context.Background(), client, &td.WorkflowListOptions{Project: "etl"}, 0, Flags{Format: "csv"})
+
+	w.Close()
+	os.Stdout = oldStdout
+	output, _ := io.ReadAll(r)
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) != 6 || !strings.HasPrefix(lines[5], "200,wf200,etl") {
+		t.Errorf("got %d lines:\n%s", len(lines), output)
+	}
+}
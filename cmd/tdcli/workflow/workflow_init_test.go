@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
 )
 
 func TestWorkflowInitCmd(t *testing.T) {
@@ -78,4 +80,9 @@ func TestWorkflowInitCmd(t *testing.T) {
 	if !strings.Contains(string(sqlContent), expectedSQL) {
 		t.Errorf("Expected SQL content to contain %q, but got: %s", expectedSQL, string(sqlContent))
 	}
+
+	// 6. The metadata template parses
+	if _, err := td.ReadWorkflowProjectMetadata(projectName); err != nil {
+		t.Errorf("td-project.yml template does not parse: %v", err)
+	}
 }
@@ -6,7 +6,9 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"text/tabwriter"
 
 	td "github.com/mickeey2525/treasuredata-go-sdk"
@@ -70,24 +72,45 @@ func HandleWorkflowProjectGet(ctx context.Context, client *td.Client, args []str
 		HandleError(err, "Failed to get workflow project", flags.Verbose)
 	}
 
+	// Ownership is published in the archive; projects without it still show
+	metadata, err := client.Workflow.GetProjectMetadata(ctx, project.ID, "")
+	if err != nil {
+		if !td.IsNotFound(err) {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to read project metadata: %v\n", err)
+		}
+		metadata = &td.WorkflowProjectMetadata{}
+	}
+
 	switch flags.Format {
 	case "json":
-		PrintJSON(project)
+		PrintJSON(struct {
+			*td.WorkflowProject
+			Metadata *td.WorkflowProjectMetadata `json:"metadata,omitempty"`
+		}{project, nilIfEmpty(metadata)})
 	case "csv":
-		fmt.Println("id,name,revision,archive_type,archive_md5,created_at,updated_at,deleted_at")
+		fmt.Println("id,name,revision,archive_type,archive_md5,created_at,updated_at,deleted_at,owner,description,runbook_url")
 		deletedAt := ""
 		if project.DeletedAt != nil {
 			deletedAt = project.DeletedAt.Time.UTC().Format("2006-01-02 15:04:05")
 		}
-		fmt.Printf("%s,%s,%s,%s,%s,%s,%s,%s\n",
+		fmt.Printf("%s,%s,%s,%s,%s,%s,%s,%s,%s,%s,%s\n",
 			project.ID, project.Name, project.Revision, project.ArchiveType,
 			project.ArchiveMD5,
 			project.CreatedAt.Time.UTC().Format("2006-01-02 15:04:05"),
 			project.UpdatedAt.Time.UTC().Format("2006-01-02 15:04:05"),
-			deletedAt)
+			deletedAt, csvField(metadata.Owner), csvField(metadata.Description), csvField(metadata.RunbookURL))
 	default:
 		fmt.Printf("ID: %s\n", project.ID)
 		fmt.Printf("Name: %s\n", project.Name)
+		if metadata.Owner != "" {
+			fmt.Printf("Owner: %s\n", metadata.Owner)
+		}
+		if metadata.Description != "" {
+			fmt.Printf("Description: %s\n", metadata.Description)
+		}
+		if metadata.RunbookURL != "" {
+			fmt.Printf("Runbook: %s\n", metadata.RunbookURL)
+		}
 		fmt.Printf("Revision: %s\n", project.Revision)
 		fmt.Printf("Archive Type: %s\n", project.ArchiveType)
 		fmt.Printf("Archive MD5: %s\n", project.ArchiveMD5)
@@ -99,6 +122,21 @@ func HandleWorkflowProjectGet(ctx context.Context, client *td.Client, args []str
 	}
 }
 
+func nilIfEmpty(metadata *td.WorkflowProjectMetadata) *td.WorkflowProjectMetadata {
+	if *metadata == (td.WorkflowProjectMetadata{}) {
+		return nil
+	}
+	return metadata
+}
+
+// csvField quotes a value that contains a comma, quote or newline
+func csvField(value string) string {
+	if strings.ContainsAny(value, ",\"\n") {
+		return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+	}
+	return value
+}
+
 func HandleWorkflowProjectCreate(ctx context.Context, client *td.Client, args []string, flags Flags) {
 	if len(args) < 2 {
 		log.Fatal("Project name and path (directory or archive file) required")
@@ -252,6 +290,41 @@ func HandleWorkflowProjectSecretsDelete(ctx context.Context, client *td.Client,
 	fmt.Printf("Secret '%s' deleted successfully from project %s\n", args[1], projectID)
 }
 
+func HandleWorkflowProjectRestore(ctx context.Context, client *td.Client, args []string, flags Flags) {
+	if len(args) < 1 {
+		log.Fatal("Project ID required")
+	}
+
+	projectID := args[0]
+
+	project, err := client.Workflow.RestoreProject(ctx, projectID)
+	if err != nil {
+		HandleError(err, "Failed to restore workflow project", flags.Verbose)
+	}
+
+	// Secret values cannot be read back, so list the keys to set again
+	var secrets []string
+	if resp, err := client.Workflow.GetProjectSecrets(ctx, projectID); err == nil {
+		for key := range resp.Secrets {
+			secrets = append(secrets, key)
+		}
+		sort.Strings(secrets)
+	}
+
+	switch flags.Format {
+	case "json":
+		PrintJSON(project)
+	case "csv":
+		fmt.Println("id,name,revision")
+		fmt.Printf("%s,%s,%s\n", project.ID, project.Name, project.Revision)
+	default:
+		fmt.Printf("Restored project %s as project %s (revision %s)\n", project.Name, project.ID, project.Revision)
+		if len(secrets) > 0 {
+			fmt.Printf("Secrets are not restored; set them again: %s\n", strings.Join(secrets, ", "))
+		}
+	}
+}
+
 // Wrapper functions for test compatibility
 func handleWorkflowProjectList(ctx context.Context, client *td.Client, flags Flags) {
 	HandleWorkflowProjectList(ctx, client, flags)
@@ -281,7 +354,11 @@ func handleWorkflowProjectSecretsDelete(ctx context.Context, client *td.Client,
 	HandleWorkflowProjectSecretsDelete(ctx, client, args, flags)
 }
 
-func HandleWorkflowProjectDownload(ctx context.Context, client *td.Client, args []string, flags Flags) {
+func handleWorkflowProjectRestore(ctx context.Context, client *td.Client, args []string, flags Flags) {
+	HandleWorkflowProjectRestore(ctx, client, args, flags)
+}
+
+func HandleWorkflowProjectDownload(ctx context.Context, client *td.Client, args []string, flags Flags, reqOpts ...td.RequestOption) {
 	if len(args) < 1 {
 		log.Fatal("Project ID or name required")
 	}
@@ -327,9 +404,9 @@ func HandleWorkflowProjectDownload(ctx context.Context, client *td.Client, args
 
 		// Download by ID
 		if revision != "" {
-			err = client.Workflow.DownloadProjectToDirectoryWithRevision(ctx, projectIdentifier, revision, outputDir)
+			err = client.Workflow.DownloadProjectToDirectoryWithRevision(ctx, projectIdentifier, revision, outputDir, reqOpts...)
 		} else {
-			err = client.Workflow.DownloadProjectToDirectory(ctx, projectIdentifier, outputDir)
+			err = client.Workflow.DownloadProjectToDirectory(ctx, projectIdentifier, outputDir, reqOpts...)
 		}
 	} else {
 		// It's not numeric, try to find by name
@@ -354,9 +431,9 @@ func HandleWorkflowProjectDownload(ctx context.Context, client *td.Client, args
 
 		// Download by name
 		if revision != "" {
-			err = client.Workflow.DownloadProjectByNameToDirectoryWithRevision(ctx, projectIdentifier, revision, outputDir)
+			err = client.Workflow.DownloadProjectByNameToDirectoryWithRevision(ctx, projectIdentifier, revision, outputDir, reqOpts...)
 		} else {
-			err = client.Workflow.DownloadProjectByNameToDirectory(ctx, projectIdentifier, outputDir)
+			err = client.Workflow.DownloadProjectByNameToDirectory(ctx, projectIdentifier, outputDir, reqOpts...)
 		}
 	}
 
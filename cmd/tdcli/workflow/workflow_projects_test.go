@@ -653,3 +653,55 @@ func TestHandleWorkflowProjectCreateArchiveFile(t *testing.T) {
 		t.Errorf("Expected success message, got: %s", outputStr)
 	}
 }
+
+func TestHandleWorkflowProjectRestore(t *testing.T) {
+	client, mux, teardown := setupWorkflowTest()
+	defer teardown()
+
+	mux.HandleFunc("/api/projects/123", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "123", "name": "etl", "revision": "rev-1", "deletedAt": "2024-01-02T00:00:00Z"}`)
+	})
+	mux.HandleFunc("/api/projects/123/archive", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("revision"); got != "rev-1" {
+			t.Errorf("Expected revision rev-1, got %q", got)
+		}
+		w.Write([]byte("archive"))
+	})
+	mux.HandleFunc("/api/projects", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"projects": []}`)
+		case "PUT":
+			fmt.Fprint(w, `{"id": "456", "name": "etl", "revision": "rev-1"}`)
+		default:
+			t.Errorf("Unexpected %s request", r.Method)
+		}
+	})
+	mux.HandleFunc("/api/projects/123/secrets", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"secrets": {"token": "", "password": ""}}`)
+	})
+
+	// Capture stdout
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	// Call the function
+	flags := Flags{Format: "table"}
+	handleWorkflowProjectRestore(context.Background(), client, []string{"123"}, flags)
+
+	// Restore stdout and read output
+	w.Close()
+	os.Stdout = oldStdout
+	output, _ := io.ReadAll(r)
+	outputStr := string(output)
+
+	for _, expected := range []string{
+		"Restored project etl as project 456 (revision rev-1)",
+		"set them again: password, token",
+	} {
+		if !strings.Contains(outputStr, expected) {
+			t.Errorf("Expected output to contain %q, but got:\n%s", expected, outputStr)
+		}
+	}
+}
@@ -2,9 +2,14 @@ package workflow
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	"time"
 
 	td "github.com/mickeey2525/treasuredata-go-sdk"
 )
@@ -88,6 +93,56 @@ func HandleWorkflowScheduleDisable(ctx context.Context, client *td.Client, args
 	fmt.Printf("Workflow schedule disabled successfully\n")
 }
 
+// HandleWorkflowSchedulePause disables a workflow schedule until the given
+// time and waits to enable it again. Interrupting the wait enables the
+// schedule right away, so a pause cannot outlive the command by accident.
+func HandleWorkflowSchedulePause(ctx context.Context, client *td.Client, workflowID string, until time.Time, flags Flags) {
+	schedule, err := client.Workflow.GetWorkflowSchedule(td.WithoutCache(ctx), workflowID)
+	if err != nil {
+		HandleError(err, "Failed to get workflow schedule", flags.Verbose)
+	}
+	if schedule.DisabledAt != nil && !schedule.DisabledAt.IsZero() {
+		log.Fatalf("Workflow schedule of %s is already disabled; enable it first", workflowID)
+	}
+
+	if _, err := client.Workflow.DisableWorkflowSchedule(ctx, workflowID); err != nil {
+		HandleError(err, "Failed to disable workflow schedule", flags.Verbose)
+	}
+	fmt.Printf("Workflow schedule paused until %s\n", until.Format("2006-01-02 15:04:05 MST"))
+	fmt.Println("Keep this command running to resume the schedule; press Ctrl-C to resume it now")
+
+	waitCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	schedule, err = client.Workflow.ResumeWorkflowScheduleAt(waitCtx, workflowID, until)
+	if errors.Is(err, context.Canceled) && ctx.Err() == nil {
+		fmt.Println("Interrupted; resuming the schedule now")
+		schedule, err = client.Workflow.EnableWorkflowSchedule(ctx, workflowID)
+	}
+	if err != nil {
+		HandleError(err, fmt.Sprintf("Failed to resume workflow schedule; run \"tdcli wf schedule enable %s\"", workflowID), flags.Verbose)
+	}
+
+	fmt.Printf("Workflow schedule resumed\n")
+	if schedule.NextTime != nil {
+		fmt.Printf("Next run: %s\n", schedule.NextTime.Format("2006-01-02 15:04:05"))
+	}
+}
+
+func HandleWorkflowScheduleDelete(ctx context.Context, client *td.Client, args []string, flags Flags) {
+	if len(args) < 1 {
+		log.Fatal("Workflow ID required")
+	}
+
+	workflowID := args[0]
+
+	if err := client.Workflow.DeleteWorkflowSchedule(ctx, workflowID); err != nil {
+		HandleError(err, "Failed to delete workflow schedule", flags.Verbose)
+	}
+
+	fmt.Printf("Workflow schedule of %s deleted successfully\n", workflowID)
+}
+
 func HandleWorkflowScheduleUpdate(ctx context.Context, client *td.Client, args []string, flags Flags) {
 	if len(args) < 4 {
 		log.Fatal("Workflow ID, cron expression, timezone, and delay required")
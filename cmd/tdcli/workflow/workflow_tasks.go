@@ -8,6 +8,7 @@ import (
 	"os"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	td "github.com/mickeey2525/treasuredata-go-sdk"
 )
@@ -110,3 +111,55 @@ func HandleWorkflowTaskGet(ctx context.Context, client *td.Client, args []string
 		}
 	}
 }
+
+// HandleWorkflowTaskJobs lists the TD jobs launched by the td> tasks of an attempt
+func HandleWorkflowTaskJobs(ctx context.Context, client *td.Client, args []string, flags Flags) {
+	if len(args) < 2 {
+		log.Fatal("Workflow ID and attempt ID required")
+	}
+
+	tasks, err := client.Workflow.TaskJobs(ctx, args[0], args[1])
+	if err != nil {
+		HandleError(err, "Failed to list workflow task jobs", flags.Verbose)
+	}
+
+	switch flags.Format {
+	case "json":
+		PrintJSON(tasks)
+	case "csv":
+		fmt.Println("task_id,full_name,operator,job_id,type,status,duration")
+		for _, task := range tasks {
+			for _, job := range task.Jobs {
+				fmt.Printf("%s,%s,%s,%s,%s,%s,%d\n",
+					task.Task.ID, task.Task.FullName, task.Operator,
+					job.JobID, job.Type, job.Status, job.Duration)
+			}
+		}
+	default:
+		if len(tasks) == 0 {
+			fmt.Println("No TD jobs found")
+			return
+		}
+
+		jobs := 0
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TASK\tOPERATOR\tJOB_ID\tTYPE\tSTATUS\tDURATION")
+		for _, task := range tasks {
+			for _, job := range task.Jobs {
+				jobs++
+				status := job.Status
+				if status == "" {
+					status = "(deleted)"
+				}
+				duration := "-"
+				if job.Duration > 0 {
+					duration = (time.Duration(job.Duration) * time.Second).String()
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+					task.Task.FullName, task.Operator, job.JobID, job.Type, status, duration)
+			}
+		}
+		w.Flush()
+		fmt.Printf("\nTotal: %d jobs in %d tasks\n", jobs, len(tasks))
+	}
+}
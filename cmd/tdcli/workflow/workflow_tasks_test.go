@@ -277,3 +277,37 @@ func TestHandleWorkflowTaskListJSON(t *testing.T) {
 		}
 	}
 }
+
+func TestHandleWorkflowTaskJobs(t *testing.T) {
+	client, mux, teardown := setupWorkflowTest()
+	defer teardown()
+
+	mux.HandleFunc("/api/workflows/123/attempts/456/tasks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tasks": [
+			{"id": "task1", "full_name": "+wf+query", "config": {"td>": "q.sql"},
+			 "started_at": "2024-01-01T09:00:00Z", "store_params": {"td": {"last_job_id": "789"}}}
+		]}`)
+	})
+	mux.HandleFunc("/api/workflows/123/attempts/456/tasks/task1/log", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "Started presto job id=789:\nselect 1\n")
+	})
+	mux.HandleFunc("/v3/job/show/789", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"job_id": "789", "type": "presto", "status": "success", "duration": 90}`)
+	})
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	HandleWorkflowTaskJobs(context.Background(), client, []string{"123", "456"}, Flags{Format: "table"})
+
+	w.Close()
+	os.Stdout = oldStdout
+	output, _ := io.ReadAll(r)
+
+	for _, expected := range []string{"TASK", "+wf+query", "td", "789", "presto", "success", "1m30s", "Total: 1 jobs in 1 tasks"} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected output to contain %q, but got:\n%s", expected, output)
+		}
+	}
+}
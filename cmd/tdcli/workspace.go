@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/alecthomas/kong"
+	"gopkg.in/yaml.v3"
+)
+
+// workspaceFileName is the per-directory project config, looked up from the
+// working directory towards the filesystem root
+const workspaceFileName = ".tdcli.yaml"
+
+// WorkspaceConfig pins the environment of a project directory so that
+// running tdcli inside it targets the right account and resources. Values
+// apply only when the matching flag or environment variable is not set; all
+// other settings still come from .tdcli.toml.
+type WorkspaceConfig struct {
+	// Profile selects a [profiles.NAME] entry of the global config, as if
+	// --profile were given
+	Profile string `yaml:"profile"`
+	// Database is the default for --database flags
+	Database string `yaml:"database"`
+	// WorkflowProject is the default project for workflow project commands
+	WorkflowProject string `yaml:"workflow_project"`
+
+	// Path is the file the settings were read from
+	Path string `yaml:"-"`
+}
+
+// findWorkspaceConfig returns the nearest .tdcli.yaml at or above dir, or
+// nil when there is none
+func findWorkspaceConfig(dir string) (*WorkspaceConfig, error) {
+	for {
+		path := filepath.Join(dir, workspaceFileName)
+		data, err := os.ReadFile(path)
+		if err == nil {
+			var ws WorkspaceConfig
+			if err := yaml.Unmarshal(data, &ws); err != nil {
+				return nil, fmt.Errorf("invalid %s: %v", path, err)
+			}
+			ws.Path = path
+			return &ws, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// loadWorkspaceConfig finds the workspace config of the working directory
+func loadWorkspaceConfig() (*WorkspaceConfig, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return findWorkspaceConfig(dir)
+}
+
+// workspaceResolver fills the --profile and --database flags from ws when
+// they are not given on the command line or in the environment
+func workspaceResolver(ws *WorkspaceConfig) kong.Resolver {
+	return kong.ResolverFunc(func(context *kong.Context, parent *kong.Path, flag *kong.Flag) (any, error) {
+		if ws == nil {
+			return nil, nil
+		}
+		// Kong applies environment variables as defaults, which resolvers
+		// would otherwise replace
+		for _, env := range flag.Envs {
+			if _, ok := os.LookupEnv(env); ok {
+				return nil, nil
+			}
+		}
+		var value string
+		switch flag.Name {
+		case "profile":
+			value = ws.Profile
+		case "database":
+			value = ws.Database
+		}
+		if value == "" {
+			return nil, nil
+		}
+		return value, nil
+	})
+}
+
+// workflowProject returns project, or the workspace project when it is empty
+func (ctx *CLIContext) workflowProject(project string) (string, error) {
+	if project != "" {
+		return project, nil
+	}
+	if ctx.Workspace != nil && ctx.Workspace.WorkflowProject != "" {
+		return ctx.Workspace.WorkflowProject, nil
+	}
+	return "", fmt.Errorf("project name required: pass it as an argument or set workflow_project in %s", workspaceFileName)
+}
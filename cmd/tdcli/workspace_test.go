@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/kong"
+)
+
+func TestFindWorkspaceConfig(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "pipelines", "daily")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "profile: staging\ndatabase: analytics\nworkflow_project: daily_pipeline\n"
+	if err := os.WriteFile(filepath.Join(root, workspaceFileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ws, err := findWorkspaceConfig(nested)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws == nil || ws.Profile != "staging" || ws.Database != "analytics" || ws.WorkflowProject != "daily_pipeline" ||
+		ws.Path != filepath.Join(root, workspaceFileName) {
+		t.Fatalf("workspace = %+v", ws)
+	}
+
+	if err := os.WriteFile(filepath.Join(nested, workspaceFileName), []byte("profile: [prod"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := findWorkspaceConfig(nested); err == nil {
+		t.Error("expected an error for invalid YAML")
+	}
+}
+
+func TestWorkspaceResolver(t *testing.T) {
+	ws := &WorkspaceConfig{Profile: "staging", Database: "analytics"}
+	parse := func(args ...string) *CLI {
+		t.Helper()
+		var cli CLI
+		app, err := kong.New(&cli, kong.Name("tdcli"), kong.Vars{"version": "test"}, kong.Resolvers(workspaceResolver(ws)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := app.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		return &cli
+	}
+
+	cli := parse("query", "submit", "SELECT 1")
+	if cli.Profile != "staging" || cli.Queries.Submit.Database != "analytics" {
+		t.Errorf("profile = %q, database = %q; want workspace values", cli.Profile, cli.Queries.Submit.Database)
+	}
+
+	cli = parse("--profile", "prod", "query", "submit", "--database", "sales", "SELECT 1")
+	if cli.Profile != "prod" || cli.Queries.Submit.Database != "sales" {
+		t.Errorf("profile = %q, database = %q; want flag values", cli.Profile, cli.Queries.Submit.Database)
+	}
+
+	t.Setenv("TD_PROFILE", "dev")
+	if cli = parse("db", "list"); cli.Profile != "dev" {
+		t.Errorf("profile = %q, want TD_PROFILE to win over the workspace", cli.Profile)
+	}
+}
+
+func TestWorkflowProject(t *testing.T) {
+	ctx := &CLIContext{Workspace: &WorkspaceConfig{WorkflowProject: "daily_pipeline"}}
+	if got, _ := ctx.workflowProject("other"); got != "other" {
+		t.Errorf("workflowProject(other) = %q", got)
+	}
+	if got, _ := ctx.workflowProject(""); got != "daily_pipeline" {
+		t.Errorf("workflowProject(\"\") = %q", got)
+	}
+	if _, err := (&CLIContext{}).workflowProject(""); err == nil {
+		t.Error("expected an error without a project")
+	}
+}
@@ -27,6 +27,7 @@ func runErrorHandlingTests(apiKey string) {
 		if tdErr, ok := err.(*td.ErrorResponse); ok {
 			fmt.Printf("✅ Proper error handling for non-existent database\n")
 			fmt.Printf("   Status Code: %d\n", tdErr.Response.StatusCode)
+			fmt.Printf("   IsNotFound: %t\n", td.IsNotFound(err))
 			fmt.Printf("   Error Message: %s\n", tdErr.Message)
 			if tdErr.ErrorMsg != "" {
 				fmt.Printf("   Error Detail: %s\n", tdErr.ErrorMsg)
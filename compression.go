@@ -0,0 +1,90 @@
+package treasuredata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WithRequestCompression gzip-compresses request bodies of at least threshold
+// bytes and sends them with "Content-Encoding: gzip". A threshold of 0
+// compresses every body. Bodies that are already gzip data, such as workflow
+// project archives, or that would not shrink are sent unchanged.
+func WithRequestCompression(threshold int) ClientOption {
+	return func(c *Client) error {
+		if threshold < 0 {
+			return fmt.Errorf("compression threshold must not be negative")
+		}
+		c.compressRequests = true
+		c.compressThreshold = threshold
+		return nil
+	}
+}
+
+// compressRequest replaces the request body with its gzip encoding when
+// request compression is enabled and the body qualifies
+func (c *Client) compressRequest(req *http.Request) error {
+	if !c.compressRequests || req.Body == nil || req.Body == http.NoBody || req.Header.Get("Content-Encoding") != "" {
+		return nil
+	}
+	if req.ContentLength >= 0 && req.ContentLength < int64(c.compressThreshold) {
+		return nil
+	}
+
+	var data []byte
+	var err error
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		data, err = io.ReadAll(body)
+		body.Close()
+		if err != nil {
+			return err
+		}
+	} else {
+		data, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return err
+		}
+		setRequestBody(req, data)
+	}
+
+	if len(data) < c.compressThreshold || isGzip(data) {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		return fmt.Errorf("failed to compress request body: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to compress request body: %w", err)
+	}
+	if buf.Len() >= len(data) {
+		return nil
+	}
+
+	setRequestBody(req, buf.Bytes())
+	req.Header = req.Header.Clone()
+	req.Header.Set("Content-Encoding", "gzip")
+	return nil
+}
+
+// setRequestBody sets a replayable in-memory request body
+func setRequestBody(req *http.Request, data []byte) {
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.ContentLength = int64(len(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+}
+
+func isGzip(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b
+}
@@ -0,0 +1,88 @@
+package treasuredata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithRequestCompression(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	if err := WithRequestCompression(1024)(client); err != nil {
+		t.Fatal(err)
+	}
+
+	var encodings []string
+	mux.HandleFunc("/v3/job/issue/trino/db", func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+		body := io.Reader(r.Body)
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Fatalf("Body is not gzip data: %v", err)
+			}
+			body = gz
+		}
+		var opts IssueQueryOptions
+		if err := json.NewDecoder(body).Decode(&opts); err != nil {
+			t.Errorf("Failed to decode body: %v", err)
+		}
+		if !strings.HasPrefix(opts.Query, "SELECT") {
+			t.Errorf("Query = %q", opts.Query)
+		}
+		fmt.Fprint(w, `{"job_id": "1"}`)
+	})
+
+	small := &IssueQueryOptions{Query: "SELECT 1"}
+	large := &IssueQueryOptions{Query: "SELECT " + strings.Repeat("col, ", 500) + "1"}
+	for _, opts := range []*IssueQueryOptions{small, large} {
+		if _, err := client.Queries.Issue(context.Background(), QueryTypeTrino, "db", opts); err != nil {
+			t.Fatalf("Issue returned error: %v", err)
+		}
+	}
+
+	if len(encodings) != 2 || encodings[0] != "" || encodings[1] != "gzip" {
+		t.Errorf("Content-Encoding = %q, want only the large body compressed", encodings)
+	}
+}
+
+func TestWithRequestCompression_SkipsGzipBodies(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	if err := WithRequestCompression(0)(client); err != nil {
+		t.Fatal(err)
+	}
+
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	gz.Write(bytes.Repeat([]byte("digdag"), 1000))
+	gz.Close()
+
+	mux.HandleFunc("/api/projects", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Content-Encoding"); got != "" {
+			t.Errorf("Content-Encoding = %q, want none for an archive", got)
+		}
+		data, _ := io.ReadAll(r.Body)
+		if !bytes.Equal(data, archive.Bytes()) {
+			t.Error("Archive body was modified")
+		}
+		fmt.Fprint(w, `{"id": "1", "name": "proj"}`)
+	})
+
+	if _, err := client.Workflow.CreateProject(context.Background(), "proj", archive.Bytes()); err != nil {
+		t.Fatalf("CreateProject returned error: %v", err)
+	}
+}
+
+func TestWithRequestCompression_Invalid(t *testing.T) {
+	if _, err := NewClient("1/abc", WithRequestCompression(-1)); err == nil {
+		t.Error("Expected error for a negative threshold")
+	}
+}
@@ -0,0 +1,110 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CredentialsProvider supplies the API key used to authenticate requests.
+// The client calls APIKey before every request attempt, so keys rotated in
+// Vault, AWS Secrets Manager or similar stores take effect without
+// recreating the client.
+type CredentialsProvider interface {
+	APIKey(ctx context.Context) (string, error)
+}
+
+// CredentialsProviderFunc adapts a function to a CredentialsProvider
+type CredentialsProviderFunc func(ctx context.Context) (string, error)
+
+// APIKey calls f(ctx)
+func (f CredentialsProviderFunc) APIKey(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticCredentials is a CredentialsProvider that always returns the same key
+type StaticCredentials string
+
+// APIKey returns the static key
+func (s StaticCredentials) APIKey(context.Context) (string, error) {
+	return string(s), nil
+}
+
+// CachedCredentials wraps a provider whose lookups are expensive, reusing a
+// fetched key for a fixed time before asking the provider again
+type CachedCredentials struct {
+	provider CredentialsProvider
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	key     string
+	expires time.Time
+}
+
+// NewCachedCredentials returns a provider that caches keys from provider
+// for ttl
+func NewCachedCredentials(provider CredentialsProvider, ttl time.Duration) *CachedCredentials {
+	return &CachedCredentials{provider: provider, ttl: ttl, now: time.Now}
+}
+
+// APIKey returns the cached key, refreshing it from the wrapped provider once
+// it has expired
+func (c *CachedCredentials) APIKey(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.key != "" && c.now().Before(c.expires) {
+		return c.key, nil
+	}
+
+	key, err := c.provider.APIKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.key = key
+	c.expires = c.now().Add(c.ttl)
+	return key, nil
+}
+
+// Invalidate drops the cached key so the next request fetches a fresh one,
+// e.g. after the API rejected the key
+func (c *CachedCredentials) Invalidate() {
+	c.mu.Lock()
+	c.key = ""
+	c.mu.Unlock()
+}
+
+// WithCredentialsProvider authenticates requests with keys from provider
+// instead of the static API key passed to NewClient, which may then be empty
+func WithCredentialsProvider(provider CredentialsProvider) ClientOption {
+	return func(c *Client) error {
+		if provider == nil {
+			return fmt.Errorf("credentials provider must not be nil")
+		}
+		c.credentials = provider
+		return nil
+	}
+}
+
+// authorize sets the Authorization header from the credentials provider, if
+// one is configured
+func (c *Client) authorize(ctx context.Context, req *http.Request) error {
+	if c.credentials == nil {
+		return nil
+	}
+
+	key, err := c.credentials.APIKey(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get API key: %w", err)
+	}
+	if key == "" {
+		return fmt.Errorf("credentials provider returned an empty API key")
+	}
+
+	req.Header = req.Header.Clone()
+	req.Header.Set("Authorization", fmt.Sprintf("TD1 %s", key))
+	return nil
+}
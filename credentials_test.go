@@ -0,0 +1,84 @@
+package treasuredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithCredentialsProvider(t *testing.T) {
+	var auth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		fmt.Fprint(w, `{"databases": []}`)
+	}))
+	defer server.Close()
+
+	var calls int32
+	provider := CredentialsProviderFunc(func(ctx context.Context) (string, error) {
+		n := atomic.AddInt32(&calls, 1)
+		return fmt.Sprintf("1/key-%d", n), nil
+	})
+
+	client, err := NewClient("", WithEndpoint(server.URL), WithCredentialsProvider(provider))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := client.Databases.List(context.Background()); err != nil {
+			t.Fatalf("Databases.List returned error: %v", err)
+		}
+	}
+
+	if len(auth) != 2 || auth[0] != "TD1 1/key-1" || auth[1] != "TD1 1/key-2" {
+		t.Errorf("Authorization headers = %v, want rotated keys", auth)
+	}
+}
+
+func TestWithCredentialsProvider_Error(t *testing.T) {
+	client, err := NewClient("", WithCredentialsProvider(CredentialsProviderFunc(func(context.Context) (string, error) {
+		return "", errors.New("vault sealed")
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Databases.List(context.Background()); err == nil || err.Error() != "failed to get API key: vault sealed" {
+		t.Errorf("Databases.List error = %v, want provider error", err)
+	}
+
+	if _, err := NewClient(""); err == nil {
+		t.Error("Expected error without an API key or provider")
+	}
+}
+
+func TestCachedCredentials(t *testing.T) {
+	var calls int
+	cached := NewCachedCredentials(CredentialsProviderFunc(func(context.Context) (string, error) {
+		calls++
+		return fmt.Sprintf("key-%d", calls), nil
+	}), time.Minute)
+	now := time.Now()
+	cached.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	first, _ := cached.APIKey(ctx)
+	second, _ := cached.APIKey(ctx)
+	if first != "key-1" || second != "key-1" {
+		t.Errorf("Keys = %s, %s, want the cached key", first, second)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if key, _ := cached.APIKey(ctx); key != "key-2" {
+		t.Errorf("Key after expiry = %s, want key-2", key)
+	}
+
+	cached.Invalidate()
+	if key, _ := cached.APIKey(ctx); key != "key-3" {
+		t.Errorf("Key after Invalidate = %s, want key-3", key)
+	}
+}
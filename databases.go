@@ -66,6 +66,10 @@ func (s *DatabasesService) Get(ctx context.Context, name string) (*Database, err
 
 // Create creates a new database
 func (s *DatabasesService) Create(ctx context.Context, name string) (*Database, error) {
+	if err := ValidateDatabaseName(name); err != nil {
+		return nil, err
+	}
+
 	u := fmt.Sprintf("%s/database/create/%s", apiVersion, name)
 
 	req, err := s.client.NewRequest("POST", u, nil)
@@ -0,0 +1,151 @@
+package treasuredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DatabaseGrant gives an access control policy an operation on a database
+type DatabaseGrant struct {
+	PolicyID int
+	// Operation is query, edit, import, manage, owner_manage or download
+	Operation string
+}
+
+// databaseOperations are the operations a DatabasesPermission accepts
+var databaseOperations = []string{"query", "edit", "import", "manage", "owner_manage", "download"}
+
+// ProvisionError reports a Provision that failed part way and what rolling
+// it back left behind
+type ProvisionError struct {
+	Database string
+	// Err is the failure that stopped provisioning
+	Err error
+	// RollbackErr is set when undoing the completed steps also failed
+	RollbackErr error
+}
+
+func (e *ProvisionError) Error() string {
+	if e.RollbackErr != nil {
+		return fmt.Sprintf("provisioning database %s failed: %v; rollback failed: %v", e.Database, e.Err, e.RollbackErr)
+	}
+	return fmt.Sprintf("provisioning database %s failed and was rolled back: %v", e.Database, e.Err)
+}
+
+func (e *ProvisionError) Unwrap() error {
+	return e.Err
+}
+
+// Provision creates a database and grants policies operations on it. The
+// policies are checked before anything is created. If a grant fails, the
+// policies already updated get their previous permissions back and the
+// database is deleted, so that either every step takes effect or none does;
+// the failure is returned as a *ProvisionError.
+func (s *DatabasesService) Provision(ctx context.Context, name string, grants []DatabaseGrant) (*Database, error) {
+	for _, grant := range grants {
+		if !containsString(databaseOperations, grant.Operation) {
+			return nil, fmt.Errorf("invalid database operation %q (valid: %s)", grant.Operation, strings.Join(databaseOperations, ", "))
+		}
+	}
+
+	// Read every policy first: an unknown policy fails before the database exists
+	original := make(map[int]*AccessControlPermissions, len(grants))
+	for _, grant := range grants {
+		if _, ok := original[grant.PolicyID]; ok {
+			continue
+		}
+		permissions, err := s.client.Permissions.GetPolicyPermissions(ctx, grant.PolicyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read permissions of policy %d: %w", grant.PolicyID, err)
+		}
+		original[grant.PolicyID] = permissions
+	}
+
+	db, err := s.Create(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if db.ID == "" {
+		// The create response may only carry the name
+		if db, err = s.Get(ctx, name); err != nil {
+			return nil, s.rollbackProvision(ctx, name, err, nil, original)
+		}
+	}
+
+	// Each policy is updated once with all of its grants
+	var policyIDs []int
+	wanted := map[int]*AccessControlPermissions{}
+	changed := map[int]bool{}
+	for _, grant := range grants {
+		permissions, ok := wanted[grant.PolicyID]
+		if !ok {
+			copied := *original[grant.PolicyID]
+			copied.Databases = append([]DatabasesPermission{}, copied.Databases...)
+			permissions = &copied
+			wanted[grant.PolicyID] = permissions
+			policyIDs = append(policyIDs, grant.PolicyID)
+		}
+		if grantDatabase(permissions, grant.Operation, db.ID) {
+			changed[grant.PolicyID] = true
+		}
+	}
+
+	updated := map[int]*AccessControlPermissions{}
+	for _, policyID := range policyIDs {
+		if !changed[policyID] {
+			continue
+		}
+		if _, err := s.client.Permissions.UpdatePolicyPermissions(ctx, policyID, wanted[policyID]); err != nil {
+			err = fmt.Errorf("failed to grant permissions on %s to policy %d: %w", name, policyID, err)
+			return nil, s.rollbackProvision(ctx, name, err, updated, original)
+		}
+		updated[policyID] = wanted[policyID]
+	}
+	return db, nil
+}
+
+// grantDatabase adds operation on the database to permissions and reports
+// whether they changed. An entry without IDs already covers every database.
+func grantDatabase(permissions *AccessControlPermissions, operation, databaseID string) bool {
+	for i, p := range permissions.Databases {
+		if p.Operation != operation {
+			continue
+		}
+		if p.IDs == "" || containsString(strings.Split(p.IDs, ","), databaseID) {
+			return false
+		}
+		permissions.Databases[i].IDs = p.IDs + "," + databaseID
+		return true
+	}
+	permissions.Databases = append(permissions.Databases, DatabasesPermission{Operation: operation, IDs: databaseID})
+	return true
+}
+
+// rollbackProvision restores the policies in updated to their original
+// permissions and deletes the database. It runs even if ctx is done. A
+// policy that had no database permissions keeps an entry naming the deleted
+// database's ID, because an empty list is left out of the update.
+func (s *DatabasesService) rollbackProvision(ctx context.Context, name string, cause error, updated, original map[int]*AccessControlPermissions) error {
+	ctx = context.WithoutCancel(ctx)
+	var errs []error
+	for policyID := range updated {
+		if _, err := s.client.Permissions.UpdatePolicyPermissions(ctx, policyID, original[policyID]); err != nil {
+			errs = append(errs, fmt.Errorf("restore policy %d: %w", policyID, err))
+		}
+	}
+	if err := s.Delete(ctx, name); err != nil {
+		errs = append(errs, fmt.Errorf("delete database %s: %w", name, err))
+	}
+	return &ProvisionError{Database: name, Err: cause, RollbackErr: errors.Join(errs...)}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
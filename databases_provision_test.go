@@ -0,0 +1,110 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestDatabasesService_Provision(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/access_control/policies/7/permissions", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"Databases": [{"operation": "query", "ids": "11"}]}`)
+		case "PATCH":
+			var body AccessControlPermissions
+			json.NewDecoder(r.Body).Decode(&body)
+			if len(body.Databases) != 2 || body.Databases[0].IDs != "11,42" || body.Databases[1] != (DatabasesPermission{Operation: "import", IDs: "42"}) {
+				t.Errorf("permissions = %+v", body.Databases)
+			}
+			json.NewEncoder(w).Encode(body)
+		}
+	})
+	mux.HandleFunc("/v3/database/create/sales", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, `{"database": "sales"}`)
+	})
+	mux.HandleFunc("/v3/database/show/sales", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "sales", "id": "42"}`)
+	})
+
+	db, err := client.Databases.Provision(context.Background(), "sales", []DatabaseGrant{
+		{PolicyID: 7, Operation: "query"},
+		{PolicyID: 7, Operation: "import"},
+	})
+	if err != nil {
+		t.Fatalf("Provision returned error: %v", err)
+	}
+	if db.ID != "42" {
+		t.Errorf("db = %+v", db)
+	}
+}
+
+func TestDatabasesService_Provision_RollsBack(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var restored, deleted bool
+	mux.HandleFunc("/v3/access_control/policies/1/permissions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			var body AccessControlPermissions
+			json.NewDecoder(r.Body).Decode(&body)
+			restored = len(body.Databases) == 1 && body.Databases[0].IDs == "5"
+		}
+		fmt.Fprint(w, `{"Databases": [{"operation": "edit", "ids": "5"}]}`)
+	})
+	mux.HandleFunc("/v3/access_control/policies/2/permissions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error": "forbidden"}`)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	})
+	mux.HandleFunc("/v3/database/create/sales", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "sales", "id": "42"}`)
+	})
+	mux.HandleFunc("/v3/database/delete/sales", func(w http.ResponseWriter, r *http.Request) {
+		deleted = true
+		fmt.Fprint(w, `{"database": "sales"}`)
+	})
+
+	_, err := client.Databases.Provision(context.Background(), "sales", []DatabaseGrant{
+		{PolicyID: 1, Operation: "edit"},
+		{PolicyID: 2, Operation: "query"},
+	})
+	var provisionErr *ProvisionError
+	if !errors.As(err, &provisionErr) || provisionErr.RollbackErr != nil {
+		t.Fatalf("err = %v, want a *ProvisionError with a clean rollback", err)
+	}
+	if !errors.Is(err, ErrForbidden) {
+		t.Errorf("err = %v, want it to wrap ErrForbidden", err)
+	}
+	if !restored || !deleted {
+		t.Errorf("restored = %v, deleted = %v", restored, deleted)
+	}
+}
+
+func TestDatabasesService_Provision_UnknownPolicy(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/access_control/policies/9/permissions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error": "not found"}`)
+	})
+	mux.HandleFunc("/v3/database/create/sales", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("database was created for an unknown policy")
+	})
+
+	_, err := client.Databases.Provision(context.Background(), "sales", []DatabaseGrant{{PolicyID: 9, Operation: "query"}})
+	if !IsNotFound(err) {
+		t.Errorf("err = %v, want not found", err)
+	}
+}
@@ -0,0 +1,228 @@
+package treasuredata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// WithStrictDecoding makes response decoding fail with an *UnknownFieldError
+// when the API returns a field the target type does not declare, so changes
+// in response shape surface as errors instead of silently ignored data. Each
+// unknown field is also logged at warn level when WithLogger is set. Types
+// with their own UnmarshalJSON method are decoded leniently.
+func WithStrictDecoding() ClientOption {
+	return func(c *Client) error {
+		c.strictDecoding = true
+		return nil
+	}
+}
+
+// UnknownFieldError reports a response field that strict decoding could not
+// map onto the target type
+type UnknownFieldError struct {
+	Method string
+	URL    string
+	// Field is the JSON field name as sent by the API
+	Field string
+	// Type is the Go type the response was decoded into
+	Type string
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("%s %s: unexpected field %q in response decoded into %s", e.Method, e.URL, e.Field, e.Type)
+}
+
+// decodeResponse decodes a JSON response body into v. Numeric fields sent as
+// strings (e.g. "id": "123") are accepted in either mode; in strict mode
+// unknown fields are logged and returned as an *UnknownFieldError.
+func (c *Client) decodeResponse(ctx context.Context, req *http.Request, body []byte, v interface{}) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil // ignore empty response bodies
+	}
+
+	err := decodeJSON(body, v, c.strictDecoding)
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Value == "string" && isNumericKind(typeErr.Type.Kind()) {
+		err = decodeJSON(coerceNumericStrings(body, reflect.TypeOf(v)), v, c.strictDecoding)
+	}
+
+	if field, ok := unknownField(err); ok {
+		unknown := &UnknownFieldError{
+			Method: req.Method,
+			URL:    req.URL.Redacted(),
+			Field:  field,
+			Type:   reflect.TypeOf(v).String(),
+		}
+		if c.logger != nil {
+			c.logger.WarnContext(ctx, "treasuredata: unexpected response field",
+				slog.String("method", unknown.Method),
+				slog.String("url", unknown.URL),
+				slog.String("field", unknown.Field),
+				slog.String("type", unknown.Type),
+			)
+		}
+		return unknown
+	}
+	return err
+}
+
+func decodeJSON(data []byte, v interface{}, strict bool) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	return dec.Decode(v)
+}
+
+// unknownField extracts the field name from the error encoding/json returns
+// for DisallowUnknownFields
+func unknownField(err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+	const prefix = `json: unknown field "`
+	msg := err.Error()
+	if !strings.HasPrefix(msg, prefix) {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(msg, prefix), `"`), true
+}
+
+func isNumericKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// coerceNumericStrings rewrites string values that parse as numbers into JSON
+// numbers wherever the target type expects a number. The body is returned
+// unchanged if it cannot be parsed.
+func coerceNumericStrings(body []byte, t reflect.Type) []byte {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var data interface{}
+	if err := dec.Decode(&data); err != nil {
+		return body
+	}
+	out, err := json.Marshal(coerceValue(data, t))
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+func coerceValue(data interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return data // the type decodes itself
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := data.(map[string]interface{})
+		if !ok {
+			return data
+		}
+		fields := jsonFields(t)
+		for key, value := range obj {
+			if ft, ok := lookupField(fields, key); ok {
+				obj[key] = coerceValue(value, ft)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := data.([]interface{})
+		if !ok {
+			return data
+		}
+		for i := range items {
+			items[i] = coerceValue(items[i], t.Elem())
+		}
+	case reflect.Map:
+		obj, ok := data.(map[string]interface{})
+		if !ok {
+			return data
+		}
+		for key, value := range obj {
+			obj[key] = coerceValue(value, t.Elem())
+		}
+	default:
+		s, ok := data.(string)
+		if !ok || !isNumericKind(t.Kind()) {
+			return data
+		}
+		if _, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+			return json.Number(strings.TrimSpace(s))
+		}
+	}
+	return data
+}
+
+// jsonFields maps the JSON names of a struct's fields, including promoted
+// fields of embedded structs, to their types
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for k, v := range jsonFields(ft) {
+					if _, exists := fields[k]; !exists {
+						fields[k] = v
+					}
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if strings.Contains(","+opts+",", ",string,") {
+			fields[name] = reflect.TypeOf("") // already decoded from a string
+			continue
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// lookupField matches a JSON key the way encoding/json does: exactly first,
+// then case-insensitively
+func lookupField(fields map[string]reflect.Type, key string) (reflect.Type, bool) {
+	if t, ok := fields[key]; ok {
+		return t, true
+	}
+	for name, t := range fields {
+		if strings.EqualFold(name, key) {
+			return t, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,154 @@
+package treasuredata
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDo_NumericStrings(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/test", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "123", "ratio": "0.5", "name": "42", "items": [{"count": "7"}], "sizes": {"a": "1"}}`)
+	})
+
+	var got struct {
+		ID    int64   `json:"id"`
+		Ratio float64 `json:"ratio"`
+		Name  string  `json:"name"`
+		Items []struct {
+			Count int `json:"count"`
+		} `json:"items"`
+		Sizes map[string]int64 `json:"sizes"`
+	}
+	req, _ := client.NewRequest("GET", "v3/test", nil)
+	if _, err := client.Do(context.Background(), req, &got); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if got.ID != 123 || got.Ratio != 0.5 || got.Name != "42" || got.Items[0].Count != 7 || got.Sizes["a"] != 1 {
+		t.Errorf("decoded %+v", got)
+	}
+}
+
+func TestDo_NonNumericStringStillFails(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/test", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "abc"}`)
+	})
+
+	var got struct {
+		ID int64 `json:"id"`
+	}
+	req, _ := client.NewRequest("GET", "v3/test", nil)
+	if _, err := client.Do(context.Background(), req, &got); err == nil {
+		t.Error("expected an error for a non-numeric id")
+	}
+}
+
+func TestWithStrictDecoding(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	client, mux, teardown := setup()
+	defer teardown()
+	WithStrictDecoding()(client)
+	WithLogger(logger)(client)
+
+	mux.HandleFunc("/v3/user/show/a@example.com", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "1", "email": "a@example.com", "new_field": true}`)
+	})
+
+	_, err := client.Users.Get(context.Background(), "a@example.com")
+	var unknown *UnknownFieldError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("expected *UnknownFieldError, got %v", err)
+	}
+	if unknown.Field != "new_field" || unknown.Type != "*treasuredata.User" {
+		t.Errorf("UnknownFieldError = %+v", unknown)
+	}
+	if !strings.Contains(logs.String(), "field=new_field") {
+		t.Errorf("expected the field to be logged, got %q", logs.String())
+	}
+}
+
+func TestWithStrictDecoding_KnownFields(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	WithStrictDecoding()(client)
+
+	mux.HandleFunc("/v3/test", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "5", "name": "x"}`)
+	})
+
+	var got struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	req, _ := client.NewRequest("GET", "v3/test", nil)
+	if _, err := client.Do(context.Background(), req, &got); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if got.ID != 5 {
+		t.Errorf("ID = %d, want 5", got.ID)
+	}
+}
+
+// benchmarkJobList returns a /v3/job/list response with n jobs. With
+// numericStrings, numbers are sent as strings the way some endpoints do, so
+// decoding takes the coercion path.
+func benchmarkJobList(n int, numericStrings bool) []byte {
+	quote := func(v int) string {
+		if numericStrings {
+			return fmt.Sprintf("%q", fmt.Sprint(v))
+		}
+		return fmt.Sprint(v)
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `{"count": %d, "from": 0, "to": %d, "jobs": [`, n, n-1)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, `{"job_id": "%d", "type": "presto", "database": "sample_datasets", "query": "SELECT method, COUNT(1) FROM www_access WHERE td_time_range(time, '2024-01-01') GROUP BY 1", "status": "success", "url": "https://console.treasuredata.com/jobs/%d", "user_name": "analyst@example.com", "created_at": "2024-01-01 00:00:00 UTC", "updated_at": "2024-01-01 00:01:00 UTC", "start_at": "2024-01-01 00:00:01 UTC", "end_at": "2024-01-01 00:00:59 UTC", "duration": %s, "cpu_time": %s, "result_size": %s, "num_records": %s, "priority": 0, "retry_limit": 0, "organization": null, "hive_result_schema": "[[\"method\", \"varchar\"], [\"_col1\", \"bigint\"]]", "result": ""}`,
+			1000000+i, 1000000+i, quote(58), quote(1200+i), quote(4096), quote(8))
+	}
+	buf.WriteString(`]}`)
+	return buf.Bytes()
+}
+
+func BenchmarkDecodeResponse_JobList(b *testing.B) {
+	for _, bench := range []struct {
+		name           string
+		strict         bool
+		numericStrings bool
+	}{
+		{"default", false, false},
+		{"strict", true, false},
+		{"numeric_strings", false, true},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			client, _ := NewClient("1/bench")
+			client.strictDecoding = bench.strict
+			req, _ := http.NewRequest("GET", "https://api.treasuredata.com/v3/job/list", nil)
+			body := benchmarkJobList(5000, bench.numericStrings)
+
+			b.SetBytes(int64(len(body)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var resp JobListResponse
+				if err := client.decodeResponse(context.Background(), req, body, &resp); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
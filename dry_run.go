@@ -0,0 +1,154 @@
+package treasuredata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// dryRunHeader marks the synthetic responses returned in dry-run mode
+const dryRunHeader = "X-Td-Dry-Run"
+
+// PlannedRequest is a mutating request captured instead of sent in dry-run mode
+type PlannedRequest struct {
+	Method      string
+	URL         string
+	ContentType string
+	Body        []byte
+}
+
+// MarshalJSON renders JSON bodies inline, other text as a string and binary
+// bodies (archives, msgpack) as a size summary
+func (p PlannedRequest) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Method      string      `json:"method"`
+		URL         string      `json:"url"`
+		ContentType string      `json:"content_type,omitempty"`
+		Body        interface{} `json:"body,omitempty"`
+	}{Method: p.Method, URL: p.URL, ContentType: p.ContentType}
+
+	switch {
+	case len(p.Body) == 0:
+	case json.Valid(p.Body):
+		out.Body = json.RawMessage(bytes.TrimSpace(p.Body))
+	case utf8.Valid(p.Body):
+		out.Body = string(p.Body)
+	default:
+		out.Body = fmt.Sprintf("<%d bytes>", len(p.Body))
+	}
+	return json.Marshal(out)
+}
+
+// String formats the request as a method and URL followed by its body
+func (p PlannedRequest) String() string {
+	s := p.Method + " " + p.URL
+	switch {
+	case len(p.Body) == 0:
+	case utf8.Valid(p.Body):
+		s += "\n" + strings.TrimSpace(string(p.Body))
+	default:
+		s += fmt.Sprintf("\n<%d bytes of %s>", len(p.Body), p.ContentType)
+	}
+	return s
+}
+
+// dryRunPlan collects the requests captured by a dry-run client
+type dryRunPlan struct {
+	mu       sync.Mutex
+	requests []PlannedRequest
+}
+
+// WithDryRun captures POST, PUT, PATCH and DELETE requests instead of sending
+// them, while GET requests still run. Captured calls succeed with an empty
+// 200 response, so methods return zero values (for example an empty job ID)
+// and multi-step operations continue; Client.Plan returns what would have
+// been sent. Some read-only endpoints use POST and are captured as well.
+func WithDryRun() ClientOption {
+	return func(c *Client) error {
+		c.dryRun = &dryRunPlan{}
+		return nil
+	}
+}
+
+// DryRun reports whether the client was created with WithDryRun
+func (c *Client) DryRun() bool {
+	return c.dryRun != nil
+}
+
+// Plan returns the requests captured so far in dry-run mode, in order
+func (c *Client) Plan() []PlannedRequest {
+	if c.dryRun == nil {
+		return nil
+	}
+	c.dryRun.mu.Lock()
+	defer c.dryRun.mu.Unlock()
+	return append([]PlannedRequest(nil), c.dryRun.requests...)
+}
+
+// ResetPlan discards the captured requests
+func (c *Client) ResetPlan() {
+	if c.dryRun == nil {
+		return
+	}
+	c.dryRun.mu.Lock()
+	c.dryRun.requests = nil
+	c.dryRun.mu.Unlock()
+}
+
+// IsDryRun reports whether resp is a synthetic dry-run response rather than
+// a reply from the API
+func IsDryRun(resp *http.Response) bool {
+	return resp != nil && resp.Header.Get(dryRunHeader) != ""
+}
+
+// isMutating reports whether a request with method changes server state
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// planRequest captures req and returns a synthetic empty 200 response. It
+// returns nil for requests that should still be sent.
+func (c *Client) planRequest(req *http.Request) (*http.Response, error) {
+	if c.dryRun == nil || !isMutating(req.Method) {
+		return nil, nil
+	}
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.dryRun.mu.Lock()
+	c.dryRun.requests = append(c.dryRun.requests, PlannedRequest{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		ContentType: req.Header.Get("Content-Type"),
+		Body:        body,
+	})
+	c.dryRun.mu.Unlock()
+
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{dryRunHeader: []string{"true"}},
+		Body:       http.NoBody,
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithDryRun(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	if err := WithDryRun()(client); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	mux.HandleFunc("/v3/database/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"databases": [{"name": "sales"}]}`)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected %s %s sent in dry-run mode", r.Method, r.URL.Path)
+	})
+
+	// Reads still reach the API
+	dbs, err := client.Databases.List(ctx)
+	if err != nil || len(dbs) != 1 {
+		t.Fatalf("Databases.List = %v, %v", dbs, err)
+	}
+
+	if _, err := client.Databases.Create(ctx, "staging"); err != nil {
+		t.Fatalf("Databases.Create returned error: %v", err)
+	}
+	resp, err := client.Queries.Issue(ctx, QueryTypeTrino, "sales", &IssueQueryOptions{Query: "SELECT 1"})
+	if err != nil || resp.JobID != "" {
+		t.Fatalf("Queries.Issue = %+v, %v; want an empty response", resp, err)
+	}
+
+	plan := client.Plan()
+	if len(plan) != 2 {
+		t.Fatalf("Plan has %d requests, want 2: %v", len(plan), plan)
+	}
+	if plan[0].Method != "POST" || !strings.HasSuffix(plan[0].URL, "/v3/database/create/staging") {
+		t.Errorf("plan[0] = %s", plan[0])
+	}
+
+	data, err := json.Marshal(plan[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Body struct {
+			Query string `json:"query"`
+		} `json:"body"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.Body.Query != "SELECT 1" {
+		t.Errorf("JSON of plan[1] = %s, %v", data, err)
+	}
+
+	client.ResetPlan()
+	if len(client.Plan()) != 0 {
+		t.Error("ResetPlan did not clear the plan")
+	}
+}
+
+func TestPlannedRequest_BinaryBody(t *testing.T) {
+	p := PlannedRequest{Method: "PUT", URL: "https://x/api/projects", ContentType: "application/gzip", Body: []byte{0x1f, 0x8b, 0xff}}
+	if got := p.String(); got != "PUT https://x/api/projects\n<3 bytes of application/gzip>" {
+		t.Errorf("String = %q", got)
+	}
+	data, _ := json.Marshal(p)
+	var decoded map[string]string
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded["body"] != "<3 bytes>" {
+		t.Errorf("MarshalJSON = %s, %v", data, err)
+	}
+}
+
+func TestDryRun_Disabled(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+	if client.DryRun() || client.Plan() != nil {
+		t.Error("Dry run should be off by default")
+	}
+}
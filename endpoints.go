@@ -0,0 +1,116 @@
+package treasuredata
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ServiceKind identifies one of the Treasure Data API endpoints
+type ServiceKind string
+
+const (
+	// ServiceAPI is the TD REST API (api.treasuredata.com)
+	ServiceAPI ServiceKind = "api"
+	// ServiceCDP is the CDP API (api-cdp)
+	ServiceCDP ServiceKind = "api-cdp"
+	// ServiceWorkflow is the Workflow API (api-workflow)
+	ServiceWorkflow ServiceKind = "api-workflow"
+	// ServiceProfiles is the Profiles API (cdp-lookup)
+	ServiceProfiles ServiceKind = "cdp-lookup"
+	// ServiceTrino is the Trino query endpoint (api-presto)
+	ServiceTrino ServiceKind = "api-presto"
+)
+
+// WithEndpointOverrides points individual services at custom URLs, such as
+// private endpoints reached over VPC peering. Services without an override
+// keep their regional defaults. Overrides take precedence over WithRegion and
+// WithEndpoint regardless of option order.
+//
+// A base path is kept, so "https://proxy.internal/td" sends API requests to
+// https://proxy.internal/td/v3/... The Trino override may be a host, host:port
+// or URL and is applied through TrinoEndpoint.
+func WithEndpointOverrides(overrides map[ServiceKind]string) ClientOption {
+	return func(c *Client) error {
+		for kind, endpoint := range overrides {
+			switch kind {
+			case ServiceAPI, ServiceCDP, ServiceWorkflow, ServiceProfiles:
+				if _, err := parseServiceURL(endpoint); err != nil {
+					return fmt.Errorf("invalid %s endpoint override: %w", kind, err)
+				}
+			case ServiceTrino:
+				if trinoHost(endpoint) == "" {
+					return fmt.Errorf("invalid %s endpoint override %q", kind, endpoint)
+				}
+			default:
+				return fmt.Errorf("unknown service kind %q", kind)
+			}
+
+			if c.endpointOverrides == nil {
+				c.endpointOverrides = map[ServiceKind]string{}
+			}
+			c.endpointOverrides[kind] = endpoint
+		}
+		return nil
+	}
+}
+
+// parseServiceURL parses an absolute http(s) base URL, adding a trailing slash
+// so that relative request paths resolve beneath it
+func parseServiceURL(endpoint string) (*url.URL, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%q is not an absolute http(s) URL", endpoint)
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	return u, nil
+}
+
+// applyEndpointOverrides replaces the base URLs of overridden services. It runs
+// after all options so that overrides win over region defaults.
+func (c *Client) applyEndpointOverrides() {
+	for kind, endpoint := range c.endpointOverrides {
+		u, _ := parseServiceURL(endpoint)
+		switch kind {
+		case ServiceAPI:
+			c.BaseURL = u
+		case ServiceCDP:
+			c.CDPURL = u
+		case ServiceWorkflow:
+			c.WorkflowURL = u
+		case ServiceProfiles:
+			c.ProfilesURL = u
+		}
+	}
+}
+
+// TrinoEndpoint returns the Trino host for this client: the ServiceTrino
+// override if set, otherwise the endpoint for the client's region. Use it as
+// TDTrinoClientConfig.Endpoint to keep Trino on the same connectivity.
+func (c *Client) TrinoEndpoint() string {
+	if endpoint, ok := c.endpointOverrides[ServiceTrino]; ok {
+		return trinoHost(endpoint)
+	}
+	region := c.region
+	if region == "" {
+		region = "us"
+	}
+	return TrinoRegionalEndpoints[region]
+}
+
+// trinoHost reduces a Trino endpoint given as a URL to its host[:port]
+func trinoHost(endpoint string) string {
+	if !strings.Contains(endpoint, "://") {
+		return strings.TrimSuffix(endpoint, "/")
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
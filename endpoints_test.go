@@ -0,0 +1,72 @@
+package treasuredata
+
+import (
+	"testing"
+)
+
+func TestWithEndpointOverrides(t *testing.T) {
+	// Overrides win even when WithRegion comes later
+	client, err := NewClient("1/abc",
+		WithEndpointOverrides(map[ServiceKind]string{
+			ServiceAPI:      "https://td.internal/api",
+			ServiceWorkflow: "https://workflow.internal",
+			ServiceTrino:    "https://trino.internal:8443",
+			ServiceProfiles: "https://lookup.internal",
+		}),
+		WithRegion("eu"),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	if got := client.BaseURL.String(); got != "https://td.internal/api/" {
+		t.Errorf("BaseURL = %q, want https://td.internal/api/", got)
+	}
+	if got := client.WorkflowURL.String(); got != "https://workflow.internal/" {
+		t.Errorf("WorkflowURL = %q, want https://workflow.internal/", got)
+	}
+	if got := client.ProfilesURL.String(); got != "https://lookup.internal/" {
+		t.Errorf("ProfilesURL = %q, want https://lookup.internal/", got)
+	}
+	if got, want := client.CDPURL.String(), CDPRegionalEndpoints["eu"]; got != want {
+		t.Errorf("CDPURL = %q, want regional default %q", got, want)
+	}
+	if got := client.TrinoEndpoint(); got != "trino.internal:8443" {
+		t.Errorf("TrinoEndpoint = %q, want trino.internal:8443", got)
+	}
+
+	req, err := client.NewRequest("GET", "v3/database/list", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := req.URL.String(); got != "https://td.internal/api/v3/database/list" {
+		t.Errorf("Request URL = %q, want the base path kept", got)
+	}
+}
+
+func TestWithEndpointOverrides_Invalid(t *testing.T) {
+	tests := []map[ServiceKind]string{
+		{ServiceAPI: "td.internal"},
+		{ServiceCDP: "ftp://cdp.internal"},
+		{"api-import": "https://import.internal"},
+	}
+	for _, overrides := range tests {
+		if _, err := NewClient("1/abc", WithEndpointOverrides(overrides)); err == nil {
+			t.Errorf("NewClient(%v) expected error", overrides)
+		}
+	}
+}
+
+func TestClient_TrinoEndpoint(t *testing.T) {
+	client, err := NewClient("1/abc", WithRegion("tokyo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := client.TrinoEndpoint(), TrinoRegionalEndpoints["tokyo"]; got != want {
+		t.Errorf("TrinoEndpoint = %q, want %q", got, want)
+	}
+
+	if got := buildDSN("trino.internal:8443", "db", ""); got != "https://td@trino.internal:8443/?catalog=td&schema=db" {
+		t.Errorf("buildDSN with port = %q", got)
+	}
+}
@@ -0,0 +1,115 @@
+package treasuredata
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// Sentinel errors matched by API errors through errors.Is. API calls still
+// return *ErrorResponse (or *WorkflowError), so errors.As keeps working for
+// callers that need the response itself.
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServerError  = errors.New("server error")
+)
+
+// statusSentinel returns the sentinel error for an HTTP status code
+func statusSentinel(code int) error {
+	switch {
+	case code == http.StatusBadRequest:
+		return ErrBadRequest
+	case code == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case code == http.StatusForbidden:
+		return ErrForbidden
+	case code == http.StatusNotFound:
+		return ErrNotFound
+	case code == http.StatusConflict:
+		return ErrConflict
+	case code == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case code >= 500:
+		return ErrServerError
+	}
+	return nil
+}
+
+// Is reports whether the response's status code corresponds to target, so
+// that errors.Is(err, ErrNotFound) matches a 404
+func (r *ErrorResponse) Is(target error) bool {
+	if r.Response == nil {
+		return false
+	}
+	sentinel := statusSentinel(r.Response.StatusCode)
+	return sentinel != nil && sentinel == target
+}
+
+// Is reports whether the workflow error's status code corresponds to target
+func (e *WorkflowError) Is(target error) bool {
+	sentinel := statusSentinel(e.StatusCode)
+	return sentinel != nil && sentinel == target
+}
+
+// StatusCode returns the HTTP status code carried by an API error, or 0 if
+// err did not come from an HTTP response
+func StatusCode(err error) int {
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		return errResp.Response.StatusCode
+	}
+	var wfErr *WorkflowError
+	if errors.As(err, &wfErr) {
+		return wfErr.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound reports whether err is a 404 from the API
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsUnauthorized reports whether err is a 401 from the API
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsForbidden reports whether err is a 403 from the API
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// IsConflict reports whether err is a 409 from the API
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsRateLimited reports whether err is a 429 from the API
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsRetryable reports whether retrying the request may succeed: rate
+// limiting, server errors, and network timeouts. Cancelled contexts and
+// open circuit breakers are not retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	if errors.Is(err, ErrRateLimited) {
+		return true
+	}
+	if errors.Is(err, ErrServerError) {
+		return StatusCode(err) != http.StatusNotImplemented
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
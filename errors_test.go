@@ -0,0 +1,87 @@
+package treasuredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestErrorResponse_Is(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	statuses := map[string]int{
+		"missing":   http.StatusNotFound,
+		"denied":    http.StatusUnauthorized,
+		"forbidden": http.StatusForbidden,
+		"exists":    http.StatusConflict,
+		"throttled": http.StatusTooManyRequests,
+		"broken":    http.StatusBadGateway,
+		"invalid":   http.StatusBadRequest,
+	}
+	for name, status := range statuses {
+		status := status
+		mux.HandleFunc("/v3/database/show/"+name, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			fmt.Fprint(w, `{"message": "failed"}`)
+		})
+	}
+
+	tests := []struct {
+		name      string
+		sentinel  error
+		retryable bool
+	}{
+		{"missing", ErrNotFound, false},
+		{"denied", ErrUnauthorized, false},
+		{"forbidden", ErrForbidden, false},
+		{"exists", ErrConflict, false},
+		{"throttled", ErrRateLimited, true},
+		{"broken", ErrServerError, true},
+		{"invalid", ErrBadRequest, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Databases.Get(context.Background(), tt.name)
+			if !errors.Is(err, tt.sentinel) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.sentinel)
+			}
+			if errors.Is(err, ErrNotFound) != (tt.sentinel == ErrNotFound) {
+				t.Errorf("errors.Is(%v, ErrNotFound) mismatch", err)
+			}
+			if got := IsRetryable(err); got != tt.retryable {
+				t.Errorf("IsRetryable = %v, want %v", got, tt.retryable)
+			}
+
+			// Wrapped errors still match, and the response stays reachable
+			wrapped := fmt.Errorf("loading database: %w", err)
+			var errResp *ErrorResponse
+			if !errors.Is(wrapped, tt.sentinel) || !errors.As(wrapped, &errResp) {
+				t.Errorf("Wrapped error lost its type: %v", wrapped)
+			}
+			if StatusCode(wrapped) != statuses[tt.name] {
+				t.Errorf("StatusCode = %d, want %d", StatusCode(wrapped), statuses[tt.name])
+			}
+		})
+	}
+}
+
+func TestErrorHelpers(t *testing.T) {
+	wfErr := NewWorkflowError("get workflow", http.StatusNotFound, "no such workflow")
+	if !IsNotFound(wfErr) || StatusCode(wfErr) != http.StatusNotFound {
+		t.Errorf("WorkflowError 404 not recognized: %v", wfErr)
+	}
+
+	if IsRetryable(nil) || IsRetryable(context.Canceled) || IsRetryable(errors.New("boom")) {
+		t.Error("IsRetryable returned true for a non-retryable error")
+	}
+	if IsRetryable(&CircuitOpenError{Service: MetricsServiceTD}) {
+		t.Error("IsRetryable returned true for an open circuit")
+	}
+	if StatusCode(errors.New("boom")) != 0 {
+		t.Error("StatusCode of a plain error should be 0")
+	}
+}
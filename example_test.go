@@ -0,0 +1,166 @@
+package treasuredata_test
+
+import (
+	"context"
+	"fmt"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+	"github.com/mickeey2525/treasuredata-go-sdk/tdtest"
+)
+
+// The examples below run against tdtest fixtures so that their output is
+// checked by go test. In real code, leave out the fixtures option.
+
+func Example_submitQueryAndStreamResults() {
+	client, err := td.NewClient("YOUR_API_KEY", queryFixtures().ClientOption())
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	ctx := context.Background()
+
+	// Issue a Trino query and wait for the job to finish
+	result, err := client.Queries.SubmitAndWait(ctx, td.SubmitRequest{
+		Database: "sales",
+		Options: td.IssueQueryOptions{
+			Query: "SELECT region, COUNT(1) AS orders FROM orders GROUP BY 1 ORDER BY 2 DESC",
+		},
+	})
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	// Stream the rows into structs instead of loading the whole result
+	rows, err := result.Decoder(ctx)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row struct {
+			Region string `td:"region"`
+			Orders int64  `td:"orders"`
+		}
+		if err := rows.ScanStruct(&row); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Printf("%s: %d\n", row.Region, row.Orders)
+	}
+	if err := rows.Err(); err != nil {
+		fmt.Printf("Error: %v\n", err)
+	}
+	// Output:
+	// east: 120
+	// west: 85
+}
+
+func Example_createTable() {
+	client, err := td.NewClient("YOUR_API_KEY", tableFixtures().ClientOption())
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	ctx := context.Background()
+
+	// A conflict means the database already exists, which is fine here
+	if _, err := client.Databases.Create(ctx, "sales"); err != nil && !td.IsConflict(err) {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	table, err := client.Tables.Create(ctx, "sales", "orders", "log")
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	fmt.Printf("Table: %s.%s (%s)\n", table.Database, table.Table, table.Type)
+	// Output:
+	// Table: sales.orders (log)
+}
+
+func Example_pushWorkflowProject() {
+	client, err := td.NewClient("YOUR_API_KEY", workflowFixtures().ClientOption())
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	ctx := context.Background()
+
+	// Archive the project directory and upload it as a new revision
+	project, err := client.Workflow.CreateProjectFromDirectory(ctx, "daily_etl", "testdata/examples/daily_etl")
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	fmt.Printf("Pushed %s (ID: %s)\n", project.Name, project.ID)
+
+	// Run one of its workflows now instead of waiting for the schedule
+	workflows, err := client.Workflow.ListProjectWorkflows(ctx, project.ID)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	for _, workflow := range workflows.Workflows {
+		attempt, err := client.Workflow.StartWorkflow(ctx, workflow.ID, map[string]interface{}{
+			"target_date": "2025-01-01",
+		})
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Printf("Started %s: attempt %s\n", workflow.Name, attempt.ID)
+	}
+	// Output:
+	// Pushed daily_etl (ID: 12)
+	// Started daily_etl: attempt 6789
+}
+
+func Example_createSegment() {
+	client, err := td.NewClient("YOUR_API_KEY", segmentFixtures().ClientOption())
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	ctx := context.Background()
+
+	segment, err := client.CDP.CreateSegment(ctx, "123", "High value customers",
+		"Customers who spent over 1000 in the last year",
+		"SELECT td_client_id FROM customers WHERE total_spend > 1000")
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	fmt.Printf("Created segment %s: %s\n", segment.ID, segment.Name)
+	// Output:
+	// Created segment 456: High value customers
+}
+
+func queryFixtures() *tdtest.Fixtures {
+	return tdtest.NewFixtures().
+		Handle("POST", "/v3/job/issue/trino/sales", `{"job_id": "1001", "database": "sales"}`).
+		Handle("GET", "/v3/job/status/1001", `{"job_id": "1001", "status": "success"}`).
+		Handle("GET", "/v3/job/show/1001", `{"job_id": "1001", "status": "success", "hive_result_schema": "[[\"region\", \"varchar\"], [\"orders\", \"bigint\"]]"}`).
+		Handle("GET", "/v3/job/result/1001", "[\"east\", 120]\n[\"west\", 85]\n")
+}
+
+func tableFixtures() *tdtest.Fixtures {
+	return tdtest.NewFixtures().
+		Handle("POST", "/v3/database/create/sales", `{"name": "sales"}`).
+		Handle("POST", "/v3/table/create/sales/orders/log", `{"database": "sales", "table": "orders", "type": "log"}`)
+}
+
+func workflowFixtures() *tdtest.Fixtures {
+	return tdtest.NewFixtures().
+		Handle("PUT", "/api/projects", `{"id": "12", "name": "daily_etl", "revision": "3f2a"}`).
+		Handle("GET", "/api/projects/12/workflows", `{"workflows": [{"id": "345", "name": "daily_etl", "project": {"id": "12", "name": "daily_etl"}}]}`).
+		Handle("POST", "/api/workflows/345/attempts", `{"id": "6789", "workflow_id": "345", "status": "running"}`)
+}
+
+func segmentFixtures() *tdtest.Fixtures {
+	return tdtest.NewFixtures().
+		Handle("POST", "/audiences/123/segments", `{"id": "456", "audienceId": "123", "name": "High value customers"}`)
+}
@@ -0,0 +1,103 @@
+package treasuredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrFeatureNotEnabled is matched through errors.Is by errors from optional
+// services (CDP, Workflow) that the account or API key cannot use
+var ErrFeatureNotEnabled = errors.New("feature not enabled")
+
+// featureProbes are the collection endpoints of the optional services. A 403
+// or 404 from one of them means the service is not available to the account,
+// whereas the same status from a single resource only concerns that resource.
+var featureProbes = map[ServiceKind]string{
+	ServiceCDP:      "audiences",
+	ServiceWorkflow: "api/projects",
+}
+
+// featureNames are the product names used in guidance messages
+var featureNames = map[ServiceKind]string{
+	ServiceCDP:      "Customer Data Platform (CDP)",
+	ServiceWorkflow: "Treasure Workflow",
+}
+
+// FeatureNotEnabledError reports that an optional service is not enabled for
+// the account, or that the API key has no access to it. It unwraps to the
+// original API error, so IsForbidden and IsNotFound keep working.
+type FeatureNotEnabledError struct {
+	Service ServiceKind
+	Err     error
+}
+
+func (e *FeatureNotEnabledError) Error() string {
+	return fmt.Sprintf("%s is not enabled for this account, or this API key has no access to it; "+
+		"ask your Treasure Data administrator to enable it or to grant access (%v)", featureNames[e.Service], e.Err)
+}
+
+// Unwrap returns the API error
+func (e *FeatureNotEnabledError) Unwrap() error { return e.Err }
+
+// Is reports whether target is ErrFeatureNotEnabled
+func (e *FeatureNotEnabledError) Is(target error) bool {
+	return target == ErrFeatureNotEnabled
+}
+
+// IsFeatureNotEnabled reports whether err means an optional service is not
+// available to the account
+func IsFeatureNotEnabled(err error) bool {
+	return errors.Is(err, ErrFeatureNotEnabled)
+}
+
+// FeatureEnabled reports whether an optional service (ServiceCDP or
+// ServiceWorkflow) is available to the account and API key, by listing its
+// top-level collection. Other failures, such as network errors, are returned.
+func (c *Client) FeatureEnabled(ctx context.Context, service ServiceKind) (bool, error) {
+	path, ok := featureProbes[service]
+	if !ok {
+		return false, fmt.Errorf("%s is not an optional service", service)
+	}
+
+	var req *http.Request
+	var err error
+	if service == ServiceCDP {
+		req, err = c.NewCDPRequest("GET", path, nil)
+	} else {
+		req, err = c.NewWorkflowRequest("GET", path, nil)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	_, err = c.Do(ctx, req, nil)
+	if IsFeatureNotEnabled(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// featureError wraps 403 and 404 responses from the collection endpoints of
+// optional services in a FeatureNotEnabledError
+func (c *Client) featureError(req *http.Request, err error) error {
+	code := StatusCode(err)
+	if code != http.StatusForbidden && code != http.StatusNotFound {
+		return err
+	}
+	for service, path := range featureProbes {
+		base := c.CDPURL
+		if service == ServiceWorkflow {
+			base = c.WorkflowURL
+		}
+		if base == nil {
+			continue
+		}
+		probe, parseErr := base.Parse(path)
+		if parseErr == nil && req.URL.Host == probe.Host && req.URL.Path == probe.Path {
+			return &FeatureNotEnabledError{Service: service, Err: err}
+		}
+	}
+	return err
+}
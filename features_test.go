@@ -0,0 +1,66 @@
+package treasuredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_FeatureEnabled(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	client.CDPURL = client.BaseURL
+
+	mux.HandleFunc("/audiences", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"error": "CDP is not enabled"}`)
+	})
+	mux.HandleFunc("/api/projects", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"projects": []}`)
+	})
+
+	ctx := context.Background()
+	if ok, err := client.FeatureEnabled(ctx, ServiceCDP); ok || err != nil {
+		t.Errorf("CDP enabled = %t, %v; want false, nil", ok, err)
+	}
+	if ok, err := client.FeatureEnabled(ctx, ServiceWorkflow); !ok || err != nil {
+		t.Errorf("Workflow enabled = %t, %v; want true, nil", ok, err)
+	}
+	if _, err := client.FeatureEnabled(ctx, ServiceAPI); err == nil {
+		t.Error("expected an error for a non-optional service")
+	}
+}
+
+func TestFeatureNotEnabledError(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	client.CDPURL = client.BaseURL
+
+	mux.HandleFunc("/audiences", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/audiences/1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	_, err := client.CDP.ListAudiences(context.Background())
+	var featureErr *FeatureNotEnabledError
+	if !errors.As(err, &featureErr) || featureErr.Service != ServiceCDP {
+		t.Fatalf("expected a CDP FeatureNotEnabledError, got %v", err)
+	}
+	if !IsFeatureNotEnabled(err) || !IsNotFound(err) {
+		t.Error("expected the error to match ErrFeatureNotEnabled and ErrNotFound")
+	}
+	if !strings.Contains(err.Error(), "administrator") {
+		t.Errorf("expected guidance in %q", err)
+	}
+
+	// A single resource being forbidden is not about the feature
+	_, err = client.CDP.GetAudience(context.Background(), "1")
+	if !IsForbidden(err) || IsFeatureNotEnabled(err) {
+		t.Errorf("expected a plain 403, got %v", err)
+	}
+}
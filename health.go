@@ -0,0 +1,111 @@
+package treasuredata
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// healthProbes are the lightweight endpoints HealthCheck requests, in report
+// order. CDP has no status endpoint, so its audience list is used.
+var healthProbes = []healthProbe{
+	{ServiceAPI, "v3/system/server_status"},
+	{ServiceWorkflow, "api/version"},
+	{ServiceCDP, "audiences"},
+}
+
+type healthProbe struct {
+	service ServiceKind
+	path    string
+}
+
+// ServiceHealth is the result of probing one service
+type ServiceHealth struct {
+	Service ServiceKind `json:"service"`
+	URL     string      `json:"url"`
+	// Reachable reports whether the service answered with any HTTP response
+	Reachable bool `json:"reachable"`
+	// Healthy reports whether the service answered with a 2xx response
+	Healthy    bool          `json:"healthy"`
+	StatusCode int           `json:"status_code,omitempty"`
+	Latency    time.Duration `json:"latency"`
+	// Error describes why the service is not healthy
+	Error string `json:"error,omitempty"`
+}
+
+// HealthReport is the result of Client.HealthCheck
+type HealthReport struct {
+	CheckedAt time.Time       `json:"checked_at"`
+	Services  []ServiceHealth `json:"services"`
+}
+
+// Healthy reports whether every service is healthy
+func (r *HealthReport) Healthy() bool {
+	for _, s := range r.Services {
+		if !s.Healthy {
+			return false
+		}
+	}
+	return true
+}
+
+// HealthCheck probes the API, Workflow and CDP endpoints the client is
+// configured with, concurrently, and reports reachability and latency for
+// each. Responses are never served from the cache. A failing service is
+// reported in its ServiceHealth rather than as an error; the error is only
+// non-nil when ctx ends before the probes finish.
+func (c *Client) HealthCheck(ctx context.Context) (*HealthReport, error) {
+	report := &HealthReport{CheckedAt: time.Now()}
+	services, err := BatchMap(WithoutCache(ctx), len(healthProbes), healthProbes, func(ctx context.Context, probe healthProbe) (ServiceHealth, error) {
+		return c.probeService(ctx, probe.service, probe.path), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	report.Services = services
+	return report, ctx.Err()
+}
+
+// probeService requests path on the service and times the response
+func (c *Client) probeService(ctx context.Context, service ServiceKind, path string) ServiceHealth {
+	health := ServiceHealth{Service: service}
+
+	var req *http.Request
+	var err error
+	switch service {
+	case ServiceCDP:
+		req, err = c.NewCDPRequest("GET", path, nil)
+	case ServiceWorkflow:
+		req, err = c.NewWorkflowRequest("GET", path, nil)
+	default:
+		req, err = c.NewRequest("GET", path, nil)
+	}
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	health.URL = req.URL.String()
+
+	start := time.Now()
+	resp, err := c.Do(ctx, req, nil)
+	health.Latency = time.Since(start)
+	if resp != nil {
+		health.Reachable = true
+		health.StatusCode = resp.StatusCode
+	}
+	if err != nil {
+		var errResp *ErrorResponse
+		if errors.As(err, &errResp) {
+			health.Error = http.StatusText(health.StatusCode)
+			if errResp.Message != "" {
+				health.Error += ": " + errResp.Message
+			}
+		} else {
+			health.Error = err.Error()
+		}
+		return health
+	}
+	health.Healthy = true
+	return health
+}
@@ -0,0 +1,62 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestClient_HealthCheck(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	client.CDPURL = client.BaseURL
+
+	mux.HandleFunc("/v3/system/server_status", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"status": "ok"}`)
+	})
+	mux.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"version": "0.10.5"}`)
+	})
+	mux.HandleFunc("/audiences", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"message": "invalid API key"}`)
+	})
+
+	report, err := client.HealthCheck(context.Background())
+	if err != nil {
+		t.Fatalf("HealthCheck returned error: %v", err)
+	}
+	if len(report.Services) != 3 || report.Healthy() {
+		t.Fatalf("report = %+v", report)
+	}
+
+	api, wf, cdp := report.Services[0], report.Services[1], report.Services[2]
+	if api.Service != ServiceAPI || !api.Healthy || api.StatusCode != 200 || api.Latency <= 0 {
+		t.Errorf("api = %+v", api)
+	}
+	if wf.Service != ServiceWorkflow || !wf.Healthy {
+		t.Errorf("workflow = %+v", wf)
+	}
+	if cdp.Service != ServiceCDP || cdp.Healthy || !cdp.Reachable || cdp.StatusCode != 401 ||
+		cdp.Error != "Unauthorized: invalid API key" {
+		t.Errorf("cdp = %+v", cdp)
+	}
+}
+
+func TestClient_HealthCheck_Unreachable(t *testing.T) {
+	client, _, teardown := setup()
+	client.CDPURL = client.BaseURL
+	teardown()
+
+	report, err := client.HealthCheck(context.Background())
+	if err != nil {
+		t.Fatalf("HealthCheck returned error: %v", err)
+	}
+	for _, s := range report.Services {
+		if s.Reachable || s.Healthy || s.Error == "" {
+			t.Errorf("%s = %+v, want unreachable", s.Service, s)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// HivemallTask selects the Hivemall learner used for training and how
+// predictions are computed
+type HivemallTask string
+
+const (
+	// HivemallClassification trains with train_classifier and predicts a
+	// probability with sigmoid
+	HivemallClassification HivemallTask = "classification"
+	// HivemallRegression trains with train_regressor and predicts the raw score
+	HivemallRegression HivemallTask = "regression"
+)
+
+// HivemallTrainOptions describes a Hivemall training job. The training table
+// holds one row per example with an array<string> of "name:value" features
+// and a numeric label (0/1 for classification).
+type HivemallTrainOptions struct {
+	Task          HivemallTask
+	TrainingTable string
+	// ModelTable receives one (feature, weight) row per feature
+	ModelTable string
+	// FeaturesColumn defaults to "features"
+	FeaturesColumn string
+	// LabelColumn defaults to "label"
+	LabelColumn string
+	// Options are passed to the learner, e.g. "-loss logloss -opt AdaGrad -reg l2"
+	Options string
+}
+
+// HivemallPredictOptions describes a job that applies a trained model to a
+// table with the same feature layout as the training table
+type HivemallPredictOptions struct {
+	Task        HivemallTask
+	ModelTable  string
+	InputTable  string
+	OutputTable string
+	// IDColumn identifies input rows in the output and defaults to "rowid"
+	IDColumn string
+	// FeaturesColumn defaults to "features"
+	FeaturesColumn string
+}
+
+// Query returns the Hive statement that trains the model and overwrites
+// ModelTable with the averaged feature weights
+func (o *HivemallTrainOptions) Query() (string, error) {
+	learner, err := hivemallLearner(o.Task)
+	if err != nil {
+		return "", err
+	}
+	training, err := hiveTableName("training_table", o.TrainingTable)
+	if err != nil {
+		return "", err
+	}
+	model, err := hiveTableName("model_table", o.ModelTable)
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{
+		hiveIdentifier(defaultString(o.FeaturesColumn, "features")),
+		hiveIdentifier(defaultString(o.LabelColumn, "label")),
+	}
+	if o.Options != "" {
+		args = append(args, hiveStringLiteral(o.Options))
+	}
+
+	return fmt.Sprintf(`INSERT OVERWRITE TABLE %s
+SELECT feature, avg(weight) AS weight
+FROM (
+  SELECT %s(%s) AS (feature, weight)
+  FROM %s
+) t
+GROUP BY feature`, model, learner, strings.Join(args, ", "), training), nil
+}
+
+// Query returns the Hive statement that scores InputTable with ModelTable and
+// overwrites OutputTable with (id, probability) for classification or
+// (id, predicted) for regression
+func (o *HivemallPredictOptions) Query() (string, error) {
+	var score string
+	switch o.Task {
+	case HivemallClassification:
+		score = "sigmoid(sum(m.weight * t.value)) AS probability"
+	case HivemallRegression:
+		score = "sum(m.weight * t.value) AS predicted"
+	default:
+		return "", NewValidationError("task", o.Task, fmt.Sprintf("task must be %q or %q", HivemallClassification, HivemallRegression))
+	}
+	model, err := hiveTableName("model_table", o.ModelTable)
+	if err != nil {
+		return "", err
+	}
+	input, err := hiveTableName("input_table", o.InputTable)
+	if err != nil {
+		return "", err
+	}
+	output, err := hiveTableName("output_table", o.OutputTable)
+	if err != nil {
+		return "", err
+	}
+	id := hiveIdentifier(defaultString(o.IDColumn, "rowid"))
+	features := hiveIdentifier(defaultString(o.FeaturesColumn, "features"))
+
+	return fmt.Sprintf(`INSERT OVERWRITE TABLE %s
+SELECT t.%s, %s
+FROM (
+  SELECT %s, extract_feature(fv) AS feature, extract_weight(fv) AS value
+  FROM %s LATERAL VIEW explode(%s) f AS fv
+) t
+LEFT OUTER JOIN %s m ON t.feature = m.feature
+GROUP BY t.%s`, output, id, score, id, input, features, model, id), nil
+}
+
+// IssueHivemallTrain submits a Hive job that trains a Hivemall model. issue
+// may carry priority, pool and retry settings; its Query is replaced.
+func (s *QueriesService) IssueHivemallTrain(ctx context.Context, database string, opts *HivemallTrainOptions, issue *IssueQueryOptions, reqOpts ...RequestOption) (*IssueQueryResponse, error) {
+	if opts == nil {
+		return nil, NewValidationError("options", nil, "training options are required")
+	}
+	query, err := opts.Query()
+	if err != nil {
+		return nil, err
+	}
+	return s.Issue(ctx, QueryTypeHive, database, withQuery(issue, query), reqOpts...)
+}
+
+// IssueHivemallPredict submits a Hive job that applies a Hivemall model. issue
+// may carry priority, pool and retry settings; its Query is replaced.
+func (s *QueriesService) IssueHivemallPredict(ctx context.Context, database string, opts *HivemallPredictOptions, issue *IssueQueryOptions, reqOpts ...RequestOption) (*IssueQueryResponse, error) {
+	if opts == nil {
+		return nil, NewValidationError("options", nil, "prediction options are required")
+	}
+	query, err := opts.Query()
+	if err != nil {
+		return nil, err
+	}
+	return s.Issue(ctx, QueryTypeHive, database, withQuery(issue, query), reqOpts...)
+}
+
+// withQuery returns a copy of opts with Query set, leaving the caller's value
+// untouched
+func withQuery(opts *IssueQueryOptions, query string) *IssueQueryOptions {
+	out := IssueQueryOptions{}
+	if opts != nil {
+		out = *opts
+	}
+	out.Query = query
+	return &out
+}
+
+func hivemallLearner(task HivemallTask) (string, error) {
+	switch task {
+	case HivemallClassification:
+		return "train_classifier", nil
+	case HivemallRegression:
+		return "train_regressor", nil
+	}
+	return "", NewValidationError("task", task, fmt.Sprintf("task must be %q or %q", HivemallClassification, HivemallRegression))
+}
+
+// hiveTableName quotes a table or db.table name for Hive
+func hiveTableName(field, name string) (string, error) {
+	if name == "" {
+		return "", NewValidationError(field, name, field+" is required")
+	}
+	parts := strings.Split(name, ".")
+	if len(parts) > 2 {
+		return "", NewValidationError(field, name, "must be table or database.table")
+	}
+	for i, part := range parts {
+		if part == "" {
+			return "", NewValidationError(field, name, "must be table or database.table")
+		}
+		parts[i] = hiveIdentifier(part)
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// hiveIdentifier quotes a Hive identifier with backticks. Hive does not
+// accept the double quotes used by EscapeIdentifier.
+func hiveIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// hiveStringLiteral quotes a Hive string literal. Hive escapes with
+// backslashes rather than by doubling quotes as Trino does.
+func hiveStringLiteral(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\x00", "")
+	return "'" + r.Replace(s) + "'"
+}
+
+func defaultString(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,131 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHivemallTrainOptions_Query(t *testing.T) {
+	opts := &HivemallTrainOptions{
+		Task:          HivemallClassification,
+		TrainingTable: "ml.train",
+		ModelTable:    "model",
+		Options:       `-loss logloss -opt AdaGrad -name it's`,
+	}
+
+	got, err := opts.Query()
+	if err != nil {
+		t.Fatalf("Query returned error: %v", err)
+	}
+	want := "INSERT OVERWRITE TABLE `model`\n" +
+		"SELECT feature, avg(weight) AS weight\n" +
+		"FROM (\n" +
+		"  SELECT train_classifier(`features`, `label`, '-loss logloss -opt AdaGrad -name it\\'s') AS (feature, weight)\n" +
+		"  FROM `ml`.`train`\n" +
+		") t\n" +
+		"GROUP BY feature"
+	if got != want {
+		t.Errorf("Query =\n%s\nwant\n%s", got, want)
+	}
+
+	opts.Task = HivemallRegression
+	opts.Options = ""
+	got, _ = opts.Query()
+	if !strings.Contains(got, "train_regressor(`features`, `label`)") {
+		t.Errorf("regression query = %s", got)
+	}
+}
+
+func TestHivemallPredictOptions_Query(t *testing.T) {
+	opts := &HivemallPredictOptions{
+		Task:        HivemallClassification,
+		ModelTable:  "model",
+		InputTable:  "test",
+		OutputTable: "prediction",
+		IDColumn:    "user_id",
+	}
+
+	got, err := opts.Query()
+	if err != nil {
+		t.Fatalf("Query returned error: %v", err)
+	}
+	for _, want := range []string{
+		"INSERT OVERWRITE TABLE `prediction`",
+		"SELECT t.`user_id`, sigmoid(sum(m.weight * t.value)) AS probability",
+		"FROM `test` LATERAL VIEW explode(`features`) f AS fv",
+		"LEFT OUTER JOIN `model` m ON t.feature = m.feature",
+		"GROUP BY t.`user_id`",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Query missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestHivemallOptions_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func() (string, error)
+	}{
+		{"unknown task", (&HivemallTrainOptions{Task: "cluster", TrainingTable: "t", ModelTable: "m"}).Query},
+		{"missing training table", (&HivemallTrainOptions{Task: HivemallRegression, ModelTable: "m"}).Query},
+		{"too many name parts", (&HivemallTrainOptions{Task: HivemallRegression, TrainingTable: "a.b.c", ModelTable: "m"}).Query},
+		{"missing output table", (&HivemallPredictOptions{Task: HivemallRegression, ModelTable: "m", InputTable: "t"}).Query},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.fn()
+			var ve *ValidationError
+			if !errors.As(err, &ve) {
+				t.Errorf("expected *ValidationError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestHiveIdentifierEscaping(t *testing.T) {
+	if got := hiveIdentifier("we`ird"); got != "`we``ird`" {
+		t.Errorf("hiveIdentifier = %s", got)
+	}
+	if got := hiveStringLiteral(`a\b'c`); got != `'a\\b\'c'` {
+		t.Errorf("hiveStringLiteral = %s", got)
+	}
+}
+
+func TestQueriesService_IssueHivemallTrain(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/issue/hive/ml", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		var body IssueQueryOptions
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(body.Query, "train_classifier") || body.Priority != 1 {
+			t.Errorf("issued %+v", body)
+		}
+		fmt.Fprint(w, mustJSON(t, IssueQueryResponse{JobID: "42", Database: "ml"}))
+	})
+
+	issue := &IssueQueryOptions{Priority: 1}
+	resp, err := client.Queries.IssueHivemallTrain(context.Background(), "ml", &HivemallTrainOptions{
+		Task:          HivemallClassification,
+		TrainingTable: "train",
+		ModelTable:    "model",
+	}, issue)
+	if err != nil {
+		t.Fatalf("IssueHivemallTrain returned error: %v", err)
+	}
+	if resp.JobID != "42" {
+		t.Errorf("JobID = %q, want 42", resp.JobID)
+	}
+	if issue.Query != "" {
+		t.Errorf("caller's options were modified: %+v", issue)
+	}
+}
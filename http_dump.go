@@ -0,0 +1,206 @@
+package treasuredata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// maxDumpBody caps how much of each body WithHTTPDump writes, so that large
+// result downloads and bulk import uploads are not buffered in memory
+const maxDumpBody = 64 << 10
+
+// redacted replaces secrets in dumps
+const redacted = "REDACTED"
+
+// WithHTTPDump writes every request and response sent by the client to w,
+// including headers and up to 64 KiB of each body. With redactSecrets the
+// API key, cookies, credentials in URLs and secret-looking JSON fields,
+// including those nested in JSON-encoded strings such as connector result
+// settings, are replaced with REDACTED; only disable it when the output
+// stays local.
+func WithHTTPDump(w io.Writer, redactSecrets bool) ClientOption {
+	return func(c *Client) error {
+		if w == nil {
+			return fmt.Errorf("HTTP dump writer cannot be nil")
+		}
+		c.httpDump = &httpDumper{w: w, redact: redactSecrets}
+		return nil
+	}
+}
+
+// httpDumper is the transport wrapper installed by WithHTTPDump
+type httpDumper struct {
+	mu     sync.Mutex
+	w      io.Writer
+	redact bool
+}
+
+func (d *httpDumper) wrap(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		// Work on a copy since reading the body for the dump replaces it
+		req = req.Clone(req.Context())
+		reqDump := d.dumpRequest(req)
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		duration := time.Since(start).Round(time.Millisecond)
+
+		var respDump string
+		if err != nil {
+			respDump = fmt.Sprintf("<<< ERROR after %s\n%v\n", duration, err)
+		} else {
+			respDump = fmt.Sprintf("<<< RESPONSE after %s\n%s", duration, d.dumpResponse(req, resp))
+		}
+
+		d.mu.Lock()
+		fmt.Fprintf(d.w, ">>> REQUEST\n%s\n%s\n", reqDump, respDump)
+		d.mu.Unlock()
+		return resp, err
+	})
+}
+
+func (d *httpDumper) dumpRequest(req *http.Request) string {
+	head, err := httputil.DumpRequestOut(req, false)
+	if err != nil {
+		return fmt.Sprintf("%s %s (dump failed: %v)\n", req.Method, req.URL.Redacted(), err)
+	}
+
+	var body []byte
+	truncated := false
+	if req.Body != nil && req.Body != http.NoBody {
+		body, truncated, req.Body = peekBody(req.Body)
+	}
+	return d.format(req, head, body, truncated, req.Header.Get("Content-Encoding"))
+}
+
+func (d *httpDumper) dumpResponse(req *http.Request, resp *http.Response) string {
+	head, err := httputil.DumpResponse(resp, false)
+	if err != nil {
+		return fmt.Sprintf("%s (dump failed: %v)\n", resp.Status, err)
+	}
+
+	var body []byte
+	truncated := false
+	if resp.Body != nil && resp.Body != http.NoBody {
+		body, truncated, resp.Body = peekBody(resp.Body)
+	}
+	return d.format(req, head, body, truncated, resp.Header.Get("Content-Encoding"))
+}
+
+// format joins a dumped head with a printable rendering of the body
+func (d *httpDumper) format(req *http.Request, head, body []byte, truncated bool, encoding string) string {
+	var sb strings.Builder
+	text := string(head)
+	if d.redact {
+		text = redactDumpHead(text)
+	}
+	sb.WriteString(strings.TrimRight(text, "\r\n"))
+	sb.WriteString("\n")
+
+	switch {
+	case len(body) == 0:
+	case encoding != "" && encoding != "identity", !utf8.Valid(body):
+		fmt.Fprintf(&sb, "\n<%s binary body>\n", bodySize(body, truncated))
+	case d.redact && strings.Contains(req.URL.Path, "/secrets"):
+		fmt.Fprintf(&sb, "\n<%s secret value REDACTED>\n", bodySize(body, truncated))
+	default:
+		s := string(body)
+		if d.redact {
+			s = redactDumpBody(s)
+		}
+		sb.WriteString("\n")
+		sb.WriteString(strings.TrimRight(s, "\r\n"))
+		if truncated {
+			fmt.Fprintf(&sb, "\n... (truncated after %d bytes)", maxDumpBody)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func bodySize(body []byte, truncated bool) string {
+	if truncated {
+		return fmt.Sprintf("more than %d bytes of", maxDumpBody)
+	}
+	return fmt.Sprintf("%d bytes of", len(body))
+}
+
+// peekBody reads up to maxDumpBody bytes and returns a body that replays them
+// followed by the unread remainder
+func peekBody(body io.ReadCloser) ([]byte, bool, io.ReadCloser) {
+	buf, err := io.ReadAll(io.LimitReader(body, maxDumpBody+1))
+	rest := io.MultiReader(bytes.NewReader(buf), body)
+	if err != nil {
+		rest = io.MultiReader(bytes.NewReader(buf), &errReader{err: err})
+	}
+	replay := struct {
+		io.Reader
+		io.Closer
+	}{rest, body}
+
+	if len(buf) > maxDumpBody {
+		return buf[:maxDumpBody], true, replay
+	}
+	return buf, false, replay
+}
+
+// errReader returns a read error encountered while peeking at a body
+type errReader struct{ err error }
+
+func (r *errReader) Read([]byte) (int, error) { return 0, r.err }
+
+var (
+	dumpSecretHeaders = regexp.MustCompile(`(?im)^((?:Authorization|Proxy-Authorization|Cookie|Set-Cookie|X-Api-Key):)[^\r\n]*`)
+	dumpURLUserinfo   = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://)[^/\s@"']+@`)
+	dumpQuerySecrets  = regexp.MustCompile(`(?i)([?&](?:apikey|api_key|token|access_token|secret|password)=)[^&\s"]*`)
+	dumpJSONSecrets   = regexp.MustCompile(`(?i)("[^"]*(?:password|secret|token|apikey|api_key|access_key|private_key|keyfile|credential)[^"]*"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	// dumpJSONNested matches string values that hold a JSON object, such as
+	// the connector settings ResultToBigQuery sends as a job's result
+	dumpJSONNested = regexp.MustCompile(`"\{(?:[^"\\]|\\.)*\}"`)
+)
+
+func redactDumpHead(s string) string {
+	s = dumpSecretHeaders.ReplaceAllString(s, "$1 "+redacted)
+	s = dumpURLUserinfo.ReplaceAllString(s, "${1}"+redacted+"@")
+	return dumpQuerySecrets.ReplaceAllString(s, "${1}"+redacted)
+}
+
+// RedactSecrets replaces secret-looking JSON fields, including those nested
+// in JSON-encoded strings, credentials in URLs and secret query parameters
+// in s with REDACTED. It is the redaction WithHTTPDump applies to bodies.
+func RedactSecrets(s string) string {
+	return redactDumpBody(s)
+}
+
+func redactDumpBody(s string) string {
+	s = dumpJSONNested.ReplaceAllStringFunc(s, redactNestedJSON)
+	s = dumpJSONSecrets.ReplaceAllString(s, `${1}"`+redacted+`"`)
+	s = dumpURLUserinfo.ReplaceAllString(s, "${1}"+redacted+"@")
+	return dumpQuerySecrets.ReplaceAllString(s, "${1}"+redacted)
+}
+
+// redactNestedJSON redacts a JSON string literal whose value is itself JSON,
+// where escaped quotes hide secret fields from dumpJSONSecrets
+func redactNestedJSON(quoted string) string {
+	var inner string
+	if err := json.Unmarshal([]byte(quoted), &inner); err != nil {
+		return quoted
+	}
+	clean := redactDumpBody(inner)
+	if clean == inner {
+		return quoted
+	}
+	out, err := json.Marshal(clean)
+	if err != nil {
+		return quoted
+	}
+	return string(out)
+}
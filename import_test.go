@@ -0,0 +1,144 @@
+package treasuredata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestImportService_Import(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/table/import/analytics/events/msgpack.gz", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		if ct := r.Header.Get("Content-Type"); ct != "application/octet-stream" {
+			t.Errorf("Content-Type = %s, want application/octet-stream", ct)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("Request body = %q, want %q", body, "payload")
+		}
+		fmt.Fprint(w, `{"database":"analytics","table":"events","elapsed_time":0.25}`)
+	})
+
+	resp, err := client.Import.Import(context.Background(), "analytics", "events", ImportFormatMessagePackGzip, strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("Import.Import returned error: %v", err)
+	}
+
+	if resp.Database != "analytics" || resp.Table != "events" || resp.ElapsedTime != 0.25 {
+		t.Errorf("Import.Import returned %+v", resp)
+	}
+}
+
+func TestImportService_ImportWithID(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/table/import_with_id/analytics/events/abc123/msgpack.gz", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		fmt.Fprint(w, `{"database":"analytics","table":"events","unique_id":"abc123","elapsed_time":0.1}`)
+	})
+
+	resp, err := client.Import.ImportWithID(context.Background(), "analytics", "events", "abc123", ImportFormatMessagePackGzip, strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("Import.ImportWithID returned error: %v", err)
+	}
+
+	if resp.UniqueID != "abc123" {
+		t.Errorf("UniqueID = %s, want abc123", resp.UniqueID)
+	}
+
+	if _, err := client.Import.ImportWithID(context.Background(), "analytics", "events", "", ImportFormatMessagePackGzip, strings.NewReader("payload")); err == nil {
+		t.Error("Expected error for empty unique ID")
+	}
+}
+
+func TestImportService_ImportRecords(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/table/import/analytics/events/msgpack.gz", func(w http.ResponseWriter, r *http.Request) {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Fatalf("Request body is not gzip: %v", err)
+		}
+		data, _ := io.ReadAll(gz)
+
+		// {"a":1,"time":100} with keys in sorted order
+		want := []byte{0x82, 0xa1, 'a', 0x01, 0xa4, 't', 'i', 'm', 'e', 0x64}
+		if !bytes.Equal(data, want) {
+			t.Errorf("Encoded records = %x, want %x", data, want)
+		}
+		fmt.Fprint(w, `{"database":"analytics","table":"events"}`)
+	})
+
+	records := []map[string]interface{}{{"time": json.Number("100"), "a": 1}}
+	if _, err := client.Import.ImportRecords(context.Background(), "analytics", "events", "", records); err != nil {
+		t.Fatalf("Import.ImportRecords returned error: %v", err)
+	}
+}
+
+func TestEncodeMsgpack(t *testing.T) {
+	tests := []struct {
+		name string
+		in   interface{}
+		want []byte
+	}{
+		{"nil", nil, []byte{0xc0}},
+		{"true", true, []byte{0xc3}},
+		{"negative fixint", -1, []byte{0xff}},
+		{"int8", -100, []byte{0xd0, 0x9c}},
+		{"uint16", 1000, []byte{0xcd, 0x03, 0xe8}},
+		{"float", 1.5, []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{"whole float", 2.0, []byte{0xcb, 0x40, 0, 0, 0, 0, 0, 0, 0}},
+		{"whole json number", json.Number("2e0"), []byte{0x02}},
+		{"json number", json.Number("1.5"), []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{"string", "hi", []byte{0xa2, 'h', 'i'}},
+		{"array", []interface{}{"a", nil}, []byte{0x92, 0xa1, 'a', 0xc0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encodeMsgpack(tt.in)
+			if err != nil {
+				t.Fatalf("encodeMsgpack returned error: %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("encodeMsgpack(%v) = %x, want %x", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := encodeMsgpack(struct{}{}); err == nil {
+		t.Error("Expected error for unsupported type")
+	}
+}
+
+func BenchmarkEncodeImportRecords(b *testing.B) {
+	records := make([]map[string]interface{}, 10000)
+	for i := range records {
+		records[i] = map[string]interface{}{
+			"time":    int64(1700000000 + i),
+			"user_id": fmt.Sprintf("u%d", i),
+			"path":    "/products/view",
+			"amount":  float64(i) * 1.5,
+			"tags":    []interface{}{"web", "mobile"},
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := EncodeImportRecords(records); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
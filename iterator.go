@@ -0,0 +1,199 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// listAllPageSize is the page size ListAll methods request when the caller
+// does not set one
+const listAllPageSize = 100
+
+// Iterator steps through a paginated list, fetching pages as they are needed:
+//
+//	it := client.Jobs.ListAll(ctx, nil)
+//	for it.Next(ctx) {
+//		job := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// The context given to ListAll bounds the whole iteration: once it is done,
+// Next stops with its error, interrupting a page request in flight. The
+// context given to Next applies to the page request it triggers.
+type Iterator[T any] struct {
+	base    context.Context
+	fetch   func(ctx context.Context) ([]T, bool, error)
+	page    []T
+	index   int
+	current T
+	more    bool
+	err     error
+}
+
+// newIterator returns an iterator bounded by base over pages returned by
+// fetch. fetch returns the next page and whether further pages may follow.
+func newIterator[T any](base context.Context, fetch func(ctx context.Context) ([]T, bool, error)) *Iterator[T] {
+	return &Iterator[T]{base: base, fetch: fetch, more: true}
+}
+
+// Next advances to the next item, fetching the next page when the current one
+// is exhausted. It returns false when the list ends or a request fails.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	for it.index >= len(it.page) {
+		if !it.more || it.err != nil {
+			return false
+		}
+		it.page, it.more, it.err = it.fetchPage(ctx)
+		it.index = 0
+		if it.err != nil {
+			return false
+		}
+	}
+
+	it.current = it.page[it.index]
+	it.index++
+	return true
+}
+
+// fetchPage fetches the next page with ctx, cancelled early if the base
+// context is done first
+func (it *Iterator[T]) fetchPage(ctx context.Context) ([]T, bool, error) {
+	if err := it.base.Err(); err != nil {
+		return nil, false, err
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	stop := context.AfterFunc(it.base, func() {
+		cancel(it.base.Err())
+	})
+	defer stop()
+
+	page, more, err := it.fetch(ctx)
+	if err != nil && it.base.Err() != nil {
+		return nil, false, it.base.Err()
+	}
+	return page, more, err
+}
+
+// Value returns the current item
+func (it *Iterator[T]) Value() T {
+	return it.current
+}
+
+// Err returns the error that stopped iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All collects the remaining items
+func (it *Iterator[T]) All(ctx context.Context) ([]T, error) {
+	var items []T
+	for it.Next(ctx) {
+		items = append(items, it.Value())
+	}
+	return items, it.Err()
+}
+
+// ListAll returns an iterator over all jobs matching opts, following from/to
+// pagination. opts.From and opts.To bound the range as in List; paging stops
+// early at the first job created before opts.Since.
+func (s *JobsService) ListAll(ctx context.Context, opts *JobListOptions, reqOpts ...RequestOption) *Iterator[Job] {
+	page := JobListOptions{}
+	if opts != nil {
+		page = *opts
+	}
+	from, last := page.From, page.To
+
+	return newIterator(ctx, func(ctx context.Context) ([]Job, bool, error) {
+		page.From = from
+		page.To = from + listAllPageSize - 1
+		if last > 0 && page.To > last {
+			page.To = last
+		}
+
+		resp, err := s.listPage(ctx, &page, reqOpts...)
+		if err != nil {
+			return nil, false, err
+		}
+		from += len(resp.Jobs)
+		more := len(resp.Jobs) >= page.To-page.From+1 && (last == 0 || from <= last)
+
+		var jobs []Job
+		for _, job := range resp.Jobs {
+			if !page.Since.IsZero() && job.CreatedAt.Before(page.Since) {
+				// Newest first: every later job is older still
+				return jobs, false, nil
+			}
+			if page.matches(job) {
+				jobs = append(jobs, job)
+			}
+		}
+		return jobs, more, nil
+	})
+}
+
+// ListAll returns an iterator over all databases. The database list API is
+// not paginated, so the iterator fetches a single page.
+func (s *DatabasesService) ListAll(ctx context.Context) *Iterator[Database] {
+	return newIterator(ctx, func(ctx context.Context) ([]Database, bool, error) {
+		databases, err := s.List(ctx)
+		return databases, false, err
+	})
+}
+
+// ListAllSegments returns an iterator over all segments of an audience,
+// following limit/offset pagination. opts.Limit sets the page size.
+func (s *CDPService) ListAllSegments(ctx context.Context, audienceID string, opts *CDPSegmentListOptions) *Iterator[CDPSegment] {
+	page := CDPSegmentListOptions{}
+	if opts != nil {
+		page = *opts
+	}
+	if page.Limit <= 0 {
+		page.Limit = listAllPageSize
+	}
+
+	return newIterator(ctx, func(ctx context.Context) ([]CDPSegment, bool, error) {
+		resp, err := s.ListSegments(ctx, audienceID, &page)
+		if err != nil {
+			return nil, false, err
+		}
+		page.Offset += len(resp.Segments)
+		return resp.Segments, len(resp.Segments) >= page.Limit, nil
+	})
+}
+
+// ListAllWorkflowAttempts returns an iterator over all attempts of a
+// workflow, newest first, following the last_id cursor. opts.Limit sets the
+// page size.
+func (s *WorkflowService) ListAllWorkflowAttempts(ctx context.Context, workflowID string, opts *WorkflowAttemptListOptions) *Iterator[WorkflowAttempt] {
+	page := WorkflowAttemptListOptions{}
+	if opts != nil {
+		page = *opts
+	}
+	if page.Limit <= 0 {
+		page.Limit = listAllPageSize
+	}
+
+	return newIterator(ctx, func(ctx context.Context) ([]WorkflowAttempt, bool, error) {
+		resp, err := s.ListWorkflowAttempts(ctx, workflowID, &page)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(resp.Attempts) < page.Limit {
+			return resp.Attempts, false, nil
+		}
+
+		lastID, err := strconv.Atoi(resp.Attempts[len(resp.Attempts)-1].ID)
+		if err != nil {
+			return nil, false, fmt.Errorf("unexpected attempt ID %q: %w", resp.Attempts[len(resp.Attempts)-1].ID, err)
+		}
+		page.LastID = lastID
+		page.Offset = 0
+		return resp.Attempts, true, nil
+	})
+}
@@ -0,0 +1,199 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestJobsService_ListAll(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	const total = 250
+	var requests int
+	mux.HandleFunc("/v3/job/list", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		from, _ := strconv.Atoi(r.URL.Query().Get("from"))
+		to, _ := strconv.Atoi(r.URL.Query().Get("to"))
+		if r.URL.Query().Get("status") != "success" {
+			t.Errorf("status filter was not passed through")
+		}
+		var resp JobListResponse
+		for i := from; i <= to && i < total; i++ {
+			resp.Jobs = append(resp.Jobs, Job{JobID: strconv.Itoa(i)})
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+
+	jobs, err := client.Jobs.ListAll(context.Background(), &JobListOptions{Status: "success"}).All(context.Background())
+	if err != nil {
+		t.Fatalf("ListAll returned error: %v", err)
+	}
+	if len(jobs) != total || jobs[total-1].JobID != "249" {
+		t.Errorf("Got %d jobs, want %d in order", len(jobs), total)
+	}
+	if requests != 3 {
+		t.Errorf("Made %d requests, want 3", requests)
+	}
+
+	jobs, err = client.Jobs.ListAll(context.Background(), &JobListOptions{From: 10, To: 19, Status: "success"}).All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 10 || jobs[0].JobID != "10" {
+		t.Errorf("Bounded ListAll returned %d jobs starting at %v", len(jobs), jobs)
+	}
+}
+
+func TestJobsService_ListAll_filters(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	// Job i was created i hours before now, alternating between two databases
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	var requests int
+	mux.HandleFunc("/v3/job/list", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		from, _ := strconv.Atoi(r.URL.Query().Get("from"))
+		to, _ := strconv.Atoi(r.URL.Query().Get("to"))
+		var resp JobListResponse
+		for i := from; i <= to; i++ {
+			db := "sales"
+			if i%2 == 1 {
+				db = "logs"
+			}
+			resp.Jobs = append(resp.Jobs, Job{
+				JobID:     strconv.Itoa(i),
+				Database:  db,
+				CreatedAt: TDTime{Time: now.Add(-time.Duration(i) * time.Hour)},
+			})
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+
+	// Jobs 10 to 150 hours old, i.e. 10..150; 141 jobs, 71 in sales
+	opts := &JobListOptions{Since: now.Add(-150 * time.Hour), Until: now.Add(-9*time.Hour - time.Minute), Database: "sales"}
+	jobs, err := client.Jobs.ListAll(context.Background(), opts).All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 71 || jobs[0].JobID != "10" || jobs[70].JobID != "150" {
+		t.Errorf("got %d jobs from %v to %v", len(jobs), jobs[0].JobID, jobs[len(jobs)-1].JobID)
+	}
+	if requests != 2 {
+		t.Errorf("made %d requests, want paging to stop at the first job before Since", requests)
+	}
+
+	resp, err := client.Jobs.List(context.Background(), &JobListOptions{From: 0, To: 9, Database: "logs"})
+	if err != nil || len(resp.Jobs) != 5 || resp.Jobs[0].JobID != "1" {
+		t.Errorf("List = %+v, %v", resp, err)
+	}
+}
+
+func TestCDPService_ListAllSegments(t *testing.T) {
+	client, mux, teardown := setupCDP()
+	defer teardown()
+
+	mux.HandleFunc("/audiences/1/segments", func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if limit != 2 {
+			t.Errorf("limit = %d, want 2", limit)
+		}
+		var segments []CDPSegment
+		for i := offset; i < offset+limit && i < 5; i++ {
+			segments = append(segments, CDPSegment{ID: strconv.Itoa(i)})
+		}
+		json.NewEncoder(w).Encode(segments)
+	})
+
+	it := client.CDP.ListAllSegments(context.Background(), "1", &CDPSegmentListOptions{Limit: 2})
+	var ids []string
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Value().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Iterator error: %v", err)
+	}
+	if fmt.Sprint(ids) != "[0 1 2 3 4]" {
+		t.Errorf("Segment IDs = %v", ids)
+	}
+}
+
+func TestWorkflowService_ListAllWorkflowAttempts(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/workflows/7/attempts", func(w http.ResponseWriter, r *http.Request) {
+		start := 5
+		if lastID := r.URL.Query().Get("last_id"); lastID != "" {
+			start, _ = strconv.Atoi(lastID)
+			start--
+		}
+		var resp WorkflowAttemptListResponse
+		for id := start; id > start-3 && id > 0; id-- {
+			resp.Attempts = append(resp.Attempts, WorkflowAttempt{ID: strconv.Itoa(id)})
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+
+	attempts, err := client.Workflow.ListAllWorkflowAttempts(context.Background(), "7", &WorkflowAttemptListOptions{Limit: 3}).All(context.Background())
+	if err != nil {
+		t.Fatalf("ListAllWorkflowAttempts returned error: %v", err)
+	}
+	if len(attempts) != 5 || attempts[0].ID != "5" || attempts[4].ID != "1" {
+		t.Errorf("Attempts = %+v", attempts)
+	}
+}
+
+func TestIterator_Error(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/database/list", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message": "forbidden"}`)
+	})
+
+	it := client.Databases.ListAll(context.Background())
+	if it.Next(context.Background()) {
+		t.Fatal("Next should return false on error")
+	}
+	if it.Err() == nil {
+		t.Error("Expected an error")
+	}
+	if it.Next(context.Background()) {
+		t.Error("Next should keep returning false after an error")
+	}
+}
+
+func TestIterator_BaseContextCancelled(t *testing.T) {
+	client, mux, teardown := setupCDP()
+	defer teardown()
+
+	mux.HandleFunc("/audiences/1/segments", func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		json.NewEncoder(w).Encode([]CDPSegment{{ID: strconv.Itoa(offset)}, {ID: strconv.Itoa(offset + 1)}})
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it := client.CDP.ListAllSegments(ctx, "1", &CDPSegmentListOptions{Limit: 2})
+	var ids []string
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Value().ID)
+		cancel()
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want context.Canceled", it.Err())
+	}
+	if fmt.Sprint(ids) != "[0 1]" {
+		t.Errorf("Segment IDs = %v, want only the first page", ids)
+	}
+}
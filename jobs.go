@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"time"
 )
 
 // JobsService handles communication with the job related methods of the Treasure Data API.
@@ -119,9 +120,20 @@ type Job struct {
 	Debug                   *JobDebug      `json:"debug,omitempty"`
 }
 
+// ResultSchema returns the columns of the job's results, parsed from
+// HiveResultSchema. It is empty until the job has finished, and for jobs that
+// return no rows.
+func (j *Job) ResultSchema() ([]TableColumn, error) {
+	return ParseTableSchema(j.HiveResultSchema)
+}
+
 // JobDebug contains debug information for a job
 type JobDebug struct {
+	// Cmdout is the engine's progress output, such as Hive stage progress
+	// or Trino query statistics
 	Cmdout string `json:"cmdout"`
+	// Stderr holds the engine's errors and warnings, including the reason a
+	// failed query failed
 	Stderr string `json:"stderr"`
 }
 
@@ -135,14 +147,60 @@ type JobListResponse struct {
 
 // JobListOptions represents options for listing jobs
 type JobListOptions struct {
+	// From and To are the positions of the first and last job to return in
+	// the list, which is ordered newest first
 	From   int    `url:"from,omitempty"`
 	To     int    `url:"to,omitempty"`
 	Status string `url:"status,omitempty"`
 	Slow   bool   `url:"slow,omitempty"`
+
+	// The filters below are applied by the client to each page, so a
+	// filtered page may hold fewer jobs than From and To select
+
+	// Since keeps jobs created at or after it; ListAll stops paging at the
+	// first older job
+	Since time.Time `url:"-"`
+	// Until keeps jobs created before it
+	Until time.Time `url:"-"`
+	// Database keeps jobs that ran against this database
+	Database string `url:"-"`
+}
+
+// matches reports whether job passes the client-side filters of opts
+func (opts *JobListOptions) matches(job Job) bool {
+	if opts == nil {
+		return true
+	}
+	if !opts.Since.IsZero() && job.CreatedAt.Before(opts.Since) {
+		return false
+	}
+	if !opts.Until.IsZero() && !job.CreatedAt.Before(opts.Until) {
+		return false
+	}
+	if opts.Database != "" && job.Database != opts.Database {
+		return false
+	}
+	return true
 }
 
 // List returns a list of jobs
-func (s *JobsService) List(ctx context.Context, opts *JobListOptions) (*JobListResponse, error) {
+func (s *JobsService) List(ctx context.Context, opts *JobListOptions, reqOpts ...RequestOption) (*JobListResponse, error) {
+	resp, err := s.listPage(ctx, opts, reqOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]Job, 0, len(resp.Jobs))
+	for _, job := range resp.Jobs {
+		if opts.matches(job) {
+			filtered = append(filtered, job)
+		}
+	}
+	resp.Jobs = filtered
+	return resp, nil
+}
+
+func (s *JobsService) listPage(ctx context.Context, opts *JobListOptions, reqOpts ...RequestOption) (*JobListResponse, error) {
 	u := fmt.Sprintf("%s/job/list", apiVersion)
 
 	if opts != nil {
@@ -159,7 +217,7 @@ func (s *JobsService) List(ctx context.Context, opts *JobListOptions) (*JobListR
 	}
 
 	var resp JobListResponse
-	_, err = s.client.Do(ctx, req, &resp)
+	_, err = s.client.Do(ctx, req, &resp, reqOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -168,7 +226,7 @@ func (s *JobsService) List(ctx context.Context, opts *JobListOptions) (*JobListR
 }
 
 // Get returns a specific job by ID
-func (s *JobsService) Get(ctx context.Context, jobID string) (*Job, error) {
+func (s *JobsService) Get(ctx context.Context, jobID string, reqOpts ...RequestOption) (*Job, error) {
 	u := fmt.Sprintf("%s/job/show/%s", apiVersion, jobID)
 
 	req, err := s.client.NewRequest("GET", u, nil)
@@ -177,7 +235,7 @@ func (s *JobsService) Get(ctx context.Context, jobID string) (*Job, error) {
 	}
 
 	var job Job
-	_, err = s.client.Do(ctx, req, &job)
+	_, err = s.client.Do(ctx, req, &job, reqOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -185,6 +243,28 @@ func (s *JobsService) Get(ctx context.Context, jobID string) (*Job, error) {
 	return &job, nil
 }
 
+// GetResultSchema returns the result columns of a job; see Job.ResultSchema
+func (s *JobsService) GetResultSchema(ctx context.Context, jobID string, reqOpts ...RequestOption) ([]TableColumn, error) {
+	job, err := s.Get(ctx, jobID, reqOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return job.ResultSchema()
+}
+
+// GetLogs returns the output the engine of a job wrote, read from the debug
+// section of the job. Jobs without a debug section give empty logs.
+func (s *JobsService) GetLogs(ctx context.Context, jobID string, reqOpts ...RequestOption) (*JobDebug, error) {
+	job, err := s.Get(ctx, jobID, reqOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if job.Debug == nil {
+		return &JobDebug{}, nil
+	}
+	return job.Debug, nil
+}
+
 // JobStatus represents job status information
 type JobStatus struct {
 	Status     string `json:"status"`
@@ -200,7 +280,7 @@ type JobStatus struct {
 }
 
 // Status returns the status of a job
-func (s *JobsService) Status(ctx context.Context, jobID string) (*JobStatus, error) {
+func (s *JobsService) Status(ctx context.Context, jobID string, reqOpts ...RequestOption) (*JobStatus, error) {
 	u := fmt.Sprintf("%s/job/status/%s", apiVersion, jobID)
 
 	req, err := s.client.NewRequest("GET", u, nil)
@@ -209,7 +289,7 @@ func (s *JobsService) Status(ctx context.Context, jobID string) (*JobStatus, err
 	}
 
 	var status JobStatus
-	_, err = s.client.Do(ctx, req, &status)
+	_, err = s.client.Do(ctx, req, &status, reqOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -218,7 +298,7 @@ func (s *JobsService) Status(ctx context.Context, jobID string) (*JobStatus, err
 }
 
 // StatusByDomainKey returns the status of a job by domain key
-func (s *JobsService) StatusByDomainKey(ctx context.Context, domainKey string) (*JobStatus, error) {
+func (s *JobsService) StatusByDomainKey(ctx context.Context, domainKey string, reqOpts ...RequestOption) (*JobStatus, error) {
 	u := fmt.Sprintf("%s/job/status_by_domain_key/%s", apiVersion, domainKey)
 
 	req, err := s.client.NewRequest("GET", u, nil)
@@ -227,7 +307,7 @@ func (s *JobsService) StatusByDomainKey(ctx context.Context, domainKey string) (
 	}
 
 	var status JobStatus
-	_, err = s.client.Do(ctx, req, &status)
+	_, err = s.client.Do(ctx, req, &status, reqOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -236,7 +316,7 @@ func (s *JobsService) StatusByDomainKey(ctx context.Context, domainKey string) (
 }
 
 // Kill kills a running job
-func (s *JobsService) Kill(ctx context.Context, jobID string) error {
+func (s *JobsService) Kill(ctx context.Context, jobID string, reqOpts ...RequestOption) error {
 	u := fmt.Sprintf("%s/job/kill/%s", apiVersion, jobID)
 
 	req, err := s.client.NewRequest("POST", u, nil)
@@ -244,7 +324,7 @@ func (s *JobsService) Kill(ctx context.Context, jobID string) error {
 		return err
 	}
 
-	_, err = s.client.Do(ctx, req, nil)
+	_, err = s.client.Do(ctx, req, nil, reqOpts...)
 	return err
 }
 
@@ -256,7 +336,7 @@ type ResultExportOptions struct {
 }
 
 // ResultExport exports the results of a job
-func (s *JobsService) ResultExport(ctx context.Context, jobID string, opts *ResultExportOptions) (*Job, error) {
+func (s *JobsService) ResultExport(ctx context.Context, jobID string, opts *ResultExportOptions, reqOpts ...RequestOption) (*Job, error) {
 	u := fmt.Sprintf("%s/job/result_export/%s", apiVersion, jobID)
 
 	req, err := s.client.NewRequest("POST", u, opts)
@@ -265,7 +345,7 @@ func (s *JobsService) ResultExport(ctx context.Context, jobID string, opts *Resu
 	}
 
 	var job Job
-	_, err = s.client.Do(ctx, req, &job)
+	_, err = s.client.Do(ctx, req, &job, reqOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,204 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// WithResume makes Jobs.DownloadResult continue an interrupted download of
+// the same job and format from its checkpoint instead of starting over
+func WithResume() RequestOption {
+	return func(o *requestOptions) {
+		o.resume = true
+	}
+}
+
+// WithResultFormat sets the format Jobs.DownloadResult downloads, JSON if
+// not given
+func WithResultFormat(format ResultFormat) RequestOption {
+	return func(o *requestOptions) {
+		o.resultFormat = format
+	}
+}
+
+// downloadCheckpoint is stored next to a partial download and identifies
+// what the partial file holds
+type downloadCheckpoint struct {
+	JobID  string       `json:"job_id"`
+	Format ResultFormat `json:"format"`
+	// ETag and LastModified validate the partial file against the result
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// DownloadResult saves a job's results to path and returns the file size.
+// Data is written to path+".part" and renamed once complete; a checkpoint
+// in path+".checkpoint" records which job and format it holds. With
+// WithResume, an interrupted download continues with an HTTP Range request
+// from the end of the partial file. If the server does not honor the range
+// or the result changed, the download starts over.
+func (s *JobsService) DownloadResult(ctx context.Context, jobID, path string, reqOpts ...RequestOption) (int64, error) {
+	o := newRequestOptions(reqOpts)
+	format := o.resultFormat
+	if format == "" {
+		format = ResultFormatJSON
+	}
+	if !format.Valid() {
+		_, err := ParseResultFormat(string(format))
+		return 0, err
+	}
+
+	partPath := path + ".part"
+	checkpointPath := path + ".checkpoint"
+
+	var offset int64
+	var checkpoint downloadCheckpoint
+	if o.resume {
+		offset, checkpoint = resumableDownload(partPath, checkpointPath, jobID, format)
+	}
+
+	u := fmt.Sprintf("%s/job/result/%s?format=%s", apiVersion, jobID, format)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return 0, err
+	}
+	// Ranges count encoded bytes, so the body must not be decompressed
+	req.Header.Set("Accept-Encoding", "identity")
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if checkpoint.ETag != "" {
+			req.Header.Set("If-Range", checkpoint.ETag)
+		} else if checkpoint.LastModified != "" {
+			req.Header.Set("If-Range", checkpoint.LastModified)
+		}
+	}
+
+	ctx, cancel := requestContext(WithoutCache(ctx), reqOpts)
+	defer cancel()
+	resp, err := s.client.send(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0 && rangeTotal(resp) == offset:
+		// The partial file already holds the whole result
+		return offset, finishDownload(partPath, checkpointPath, path)
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		if start := rangeStart(resp); start != offset {
+			return 0, fmt.Errorf("download of job %s resumed at byte %d, expected %d", jobID, start, offset)
+		}
+	default:
+		if err := CheckResponse(resp); err != nil {
+			return 0, err
+		}
+		// A full response replaces any partial file
+		offset = 0
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if offset > 0 {
+		flags = os.O_WRONLY | os.O_APPEND
+	} else {
+		checkpoint = downloadCheckpoint{
+			JobID:        jobID,
+			Format:       format,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		}
+		data, err := json.Marshal(checkpoint)
+		if err != nil {
+			return 0, err
+		}
+		if err := os.WriteFile(checkpointPath, data, 0644); err != nil {
+			return 0, err
+		}
+	}
+
+	total := resp.ContentLength
+	if total >= 0 {
+		total += offset
+	}
+	body := withProgress(resp.Body, offset, total, o.progress)
+
+	file, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return 0, err
+	}
+	n, copyErr := io.Copy(file, body)
+	if err := file.Close(); err != nil && copyErr == nil {
+		copyErr = err
+	}
+	if copyErr != nil {
+		return 0, fmt.Errorf("download of job %s interrupted after %d bytes: %w", jobID, offset+n, copyErr)
+	}
+	return offset + n, finishDownload(partPath, checkpointPath, path)
+}
+
+// resumableDownload returns the size of a partial download of the job's
+// results in format and its checkpoint, or zero if there is none to continue
+func resumableDownload(partPath, checkpointPath, jobID string, format ResultFormat) (int64, downloadCheckpoint) {
+	var checkpoint downloadCheckpoint
+	data, err := os.ReadFile(checkpointPath)
+	if err != nil || json.Unmarshal(data, &checkpoint) != nil {
+		return 0, downloadCheckpoint{}
+	}
+	if checkpoint.JobID != jobID || checkpoint.Format != format {
+		return 0, downloadCheckpoint{}
+	}
+	info, err := os.Stat(partPath)
+	if err != nil {
+		return 0, downloadCheckpoint{}
+	}
+	return info.Size(), checkpoint
+}
+
+func finishDownload(partPath, checkpointPath, path string) error {
+	if err := os.Rename(partPath, path); err != nil {
+		return err
+	}
+	if err := os.Remove(checkpointPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// rangeStart returns the first byte of a "Content-Range: bytes start-end/total"
+// response, or -1
+func rangeStart(resp *http.Response) int64 {
+	spec, ok := strings.CutPrefix(resp.Header.Get("Content-Range"), "bytes ")
+	if !ok {
+		return -1
+	}
+	start, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// rangeTotal returns the total size of a "Content-Range: bytes */total"
+// response, or -1
+func rangeTotal(resp *http.Response) int64 {
+	_, total, ok := strings.Cut(resp.Header.Get("Content-Range"), "/")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(total, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
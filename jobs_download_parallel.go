@@ -0,0 +1,205 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultDownloadChunkSize is the size of the ranges Jobs.DownloadResultParallel
+// fetches when WithChunkSize is not given
+const DefaultDownloadChunkSize = 32 << 20
+
+// WithChunkSize sets the size of the ranges Jobs.DownloadResultParallel
+// fetches
+func WithChunkSize(size int64) RequestOption {
+	return func(o *requestOptions) {
+		o.chunkSize = size
+	}
+}
+
+// DownloadResultParallel writes a job's results to w, fetching up to
+// parallel ranges of the result at once and writing them in order. It
+// returns the number of bytes written. At most parallel chunks are held in
+// memory, so memory use is bounded by parallel times the chunk size.
+//
+// The first range request also learns the result size. If the server does
+// not honor ranges, the result is copied from that response in a single
+// stream. When the server sends an ETag or Last-Modified header, every later
+// range must come from the same version of the result (checked with
+// If-Range), or the download fails.
+func (s *JobsService) DownloadResultParallel(ctx context.Context, jobID string, w io.Writer, parallel int, reqOpts ...RequestOption) (int64, error) {
+	o := newRequestOptions(reqOpts)
+	format := o.resultFormat
+	if format == "" {
+		format = ResultFormatJSON
+	}
+	if !format.Valid() {
+		_, err := ParseResultFormat(string(format))
+		return 0, err
+	}
+	chunkSize := o.chunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultDownloadChunkSize
+	}
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	ctx, cancel := requestContext(WithoutCache(ctx), reqOpts)
+	defer cancel()
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	d := &rangeDownload{
+		client: s.client,
+		url:    fmt.Sprintf("%s/job/result/%s?format=%s", apiVersion, jobID, format),
+		jobID:  jobID,
+	}
+	resp, err := d.get(ctx, 0, chunkSize, "")
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		resp.Body.Close()
+		return 0, nil
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		// The server sent the whole result
+		defer resp.Body.Close()
+		n, err := io.Copy(w, withProgress(resp.Body, 0, resp.ContentLength, o.progress))
+		if err != nil {
+			return n, fmt.Errorf("download of job %s interrupted after %d bytes: %w", jobID, n, err)
+		}
+		return n, nil
+	}
+	total := rangeTotal(resp)
+	validator := resp.Header.Get("ETag")
+	if validator == "" {
+		validator = resp.Header.Get("Last-Modified")
+	}
+	if total < 0 {
+		resp.Body.Close()
+		return 0, fmt.Errorf("download of job %s: no result size in Content-Range %q", jobID, resp.Header.Get("Content-Range"))
+	}
+	first, err := d.read(resp, 0, min(chunkSize, total))
+	if err != nil {
+		return 0, err
+	}
+
+	chunks := int((total + chunkSize - 1) / chunkSize)
+	results := make([]chan rangeChunk, chunks)
+	for i := range results {
+		results[i] = make(chan rangeChunk, 1)
+	}
+	results[0] <- rangeChunk{data: first}
+
+	// Each slot is taken when a chunk is requested and given back once it is
+	// written, so requests never run more than parallel chunks ahead
+	slots := make(chan struct{}, parallel)
+	slots <- struct{}{}
+	go func() {
+		for i := 1; i < chunks; i++ {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func(i int) {
+				start := int64(i) * chunkSize
+				data, err := d.fetch(ctx, start, min(chunkSize, total-start), validator)
+				results[i] <- rangeChunk{data: data, err: err}
+			}(i)
+		}
+	}()
+
+	var written int64
+	for i := 0; i < chunks; i++ {
+		var chunk rangeChunk
+		select {
+		case chunk = <-results[i]:
+		case <-ctx.Done():
+			return written, ctx.Err()
+		}
+		if chunk.err != nil {
+			return written, chunk.err
+		}
+		n, err := w.Write(chunk.data)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+		if o.progress != nil {
+			o.progress(written, total)
+		}
+		<-slots
+	}
+	return written, nil
+}
+
+// rangeChunk is one fetched range of a result
+type rangeChunk struct {
+	data []byte
+	err  error
+}
+
+// rangeDownload fetches byte ranges of one job result
+type rangeDownload struct {
+	client *Client
+	url    string
+	jobID  string
+}
+
+// get requests size bytes from start, returning a 200 or 206 response
+func (d *rangeDownload) get(ctx context.Context, start, size int64, validator string) (*http.Response, error) {
+	req, err := d.client.NewRequest("GET", d.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Ranges count encoded bytes, so the body must not be decompressed
+	req.Header.Set("Accept-Encoding", "identity")
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, start+size-1))
+	if validator != "" {
+		req.Header.Set("If-Range", validator)
+	}
+	resp, err := d.client.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && start == 0 && rangeTotal(resp) == 0 {
+		// An empty result has no first byte to request
+		return resp, nil
+	}
+	if err := CheckResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// fetch returns size bytes of the result from start
+func (d *rangeDownload) fetch(ctx context.Context, start, size int64, validator string) ([]byte, error) {
+	resp, err := d.get(ctx, start, size, validator)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, fmt.Errorf("result of job %s changed during the download", d.jobID)
+	}
+	return d.read(resp, start, size)
+}
+
+// read reads a 206 response, checking that it holds size bytes from start
+func (d *rangeDownload) read(resp *http.Response, start, size int64) ([]byte, error) {
+	defer resp.Body.Close()
+	if got := rangeStart(resp); got != start {
+		return nil, fmt.Errorf("download of job %s got a range at byte %d, expected %d", d.jobID, got, start)
+	}
+	data := make([]byte, max(size, 0))
+	if _, err := io.ReadFull(resp.Body, data); err != nil {
+		return nil, fmt.Errorf("download of job %s interrupted at byte %d: %w", d.jobID, start, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,166 @@
+package treasuredata
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// serveResult serves data with range support, as the result endpoint does
+func serveResult(etag string, data []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+	}
+}
+
+func TestJobsService_DownloadResultParallel(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	result := []byte(strings.Repeat("0123456789", 100))
+	var mu sync.Mutex
+	var ranges []string
+	mux.HandleFunc("/v3/job/result/123", func(w http.ResponseWriter, r *http.Request) {
+		testURL(t, r, "/v3/job/result/123?format=csv")
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		mu.Unlock()
+		if r.Header.Get("Range") != "bytes=0-63" && r.Header.Get("If-Range") != `"v1"` {
+			t.Errorf("If-Range = %q", r.Header.Get("If-Range"))
+		}
+		serveResult(`"v1"`, result)(w, r)
+	})
+
+	var buf bytes.Buffer
+	var progress []int64
+	n, err := client.Jobs.DownloadResultParallel(context.Background(), "123", &buf, 4,
+		WithResultFormat(ResultFormatCSV), WithChunkSize(64),
+		WithProgress(func(done, total int64) {
+			if total != int64(len(result)) {
+				t.Errorf("progress total = %d", total)
+			}
+			progress = append(progress, done)
+		}))
+	if err != nil {
+		t.Fatalf("DownloadResultParallel returned error: %v", err)
+	}
+	if n != int64(len(result)) || !bytes.Equal(buf.Bytes(), result) {
+		t.Errorf("downloaded %d bytes %q", n, buf.String())
+	}
+	if len(progress) != 16 || progress[0] != 64 || progress[15] != n {
+		t.Errorf("progress = %v", progress)
+	}
+	if len(ranges) != 16 {
+		t.Errorf("requested %d ranges, want 16: %v", len(ranges), ranges)
+	}
+}
+
+func TestJobsService_DownloadResultParallel_ServerIgnoresRange(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/result/123", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 1}`+"\n"+`{"id": 2}`+"\n")
+	})
+
+	var buf bytes.Buffer
+	n, err := client.Jobs.DownloadResultParallel(context.Background(), "123", &buf, 4, WithChunkSize(4))
+	if err != nil {
+		t.Fatalf("DownloadResultParallel returned error: %v", err)
+	}
+	if n != 20 || buf.String() != `{"id": 1}`+"\n"+`{"id": 2}`+"\n" {
+		t.Errorf("downloaded %d bytes %q", n, buf.String())
+	}
+}
+
+func TestJobsService_DownloadResultParallel_ResultChanged(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	result := []byte(strings.Repeat("x", 100))
+	var mu sync.Mutex
+	calls := 0
+	mux.HandleFunc("/v3/job/result/123", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		etag := `"v1"`
+		if calls > 1 {
+			etag = `"v2"`
+		}
+		mu.Unlock()
+		serveResult(etag, result)(w, r)
+	})
+
+	_, err := client.Jobs.DownloadResultParallel(context.Background(), "123", io.Discard, 2, WithChunkSize(10))
+	if err == nil || !strings.Contains(err.Error(), "changed during the download") {
+		t.Errorf("err = %v, want a changed result error", err)
+	}
+}
+
+func TestJobsService_DownloadResultParallel_Empty(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/result/123", serveResult(`"v1"`, nil))
+
+	n, err := client.Jobs.DownloadResultParallel(context.Background(), "123", io.Discard, 4)
+	if err != nil || n != 0 {
+		t.Errorf("DownloadResultParallel = %d, %v, want an empty result", n, err)
+	}
+}
+
+// slowReader simulates a bandwidth-limited connection by pausing on every read
+type slowReader struct {
+	io.ReadSeeker
+}
+
+func (r slowReader) Read(p []byte) (int, error) {
+	time.Sleep(time.Millisecond)
+	return r.ReadSeeker.Read(p)
+}
+
+func BenchmarkJobsService_DownloadResult(b *testing.B) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	result := bytes.Repeat([]byte("0123456789abcdef"), 1<<19) // 8 MiB
+	mux.HandleFunc("/v3/job/result/123", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "", time.Time{}, slowReader{bytes.NewReader(result)})
+	})
+	dir := b.TempDir()
+
+	b.Run("single", func(b *testing.B) {
+		b.SetBytes(int64(len(result)))
+		for i := 0; i < b.N; i++ {
+			if _, err := client.Jobs.DownloadResult(context.Background(), "123", filepath.Join(dir, "single")); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	for _, parallel := range []int{4, 8} {
+		b.Run(fmt.Sprintf("parallel_%d", parallel), func(b *testing.B) {
+			b.SetBytes(int64(len(result)))
+			for i := 0; i < b.N; i++ {
+				file, err := os.Create(filepath.Join(dir, "parallel"))
+				if err != nil {
+					b.Fatal(err)
+				}
+				_, err = client.Jobs.DownloadResultParallel(context.Background(), "123", file, parallel, WithChunkSize(1<<20))
+				file.Close()
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
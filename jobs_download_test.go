@@ -0,0 +1,104 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestJobsService_DownloadResult_Resume(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	const result = "id,name\n1,alice\n2,bob\n"
+	var ranges []string
+	mux.HandleFunc("/v3/job/result/123", func(w http.ResponseWriter, r *http.Request) {
+		testURL(t, r, "/v3/job/result/123?format=csv")
+		if got := r.Header.Get("Accept-Encoding"); got != "identity" {
+			t.Errorf("Accept-Encoding = %q", got)
+		}
+		ranges = append(ranges, r.Header.Get("Range"))
+		w.Header().Set("ETag", `"v1"`)
+		spec := strings.TrimPrefix(r.Header.Get("Range"), "bytes=")
+		if spec == "" {
+			fmt.Fprint(w, result)
+			return
+		}
+		if spec == "10-" && r.Header.Get("If-Range") != `"v1"` {
+			t.Errorf("If-Range = %q", r.Header.Get("If-Range"))
+		}
+		start, _ := strconv.Atoi(strings.TrimSuffix(spec, "-"))
+		if start >= len(result) {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", len(result)))
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(result)-1, len(result)))
+		w.WriteHeader(http.StatusPartialContent)
+		fmt.Fprint(w, result[start:])
+	})
+
+	path := filepath.Join(t.TempDir(), "result.csv")
+	// An interrupted download left the first 10 bytes behind
+	os.WriteFile(path+".part", []byte(result[:10]), 0644)
+	os.WriteFile(path+".checkpoint", []byte(`{"job_id":"123","format":"csv","etag":"\"v1\""}`), 0644)
+
+	n, err := client.Jobs.DownloadResult(context.Background(), "123", path, WithResultFormat(ResultFormatCSV), WithResume())
+	if err != nil {
+		t.Fatalf("DownloadResult returned error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != result || n != int64(len(result)) {
+		t.Errorf("downloaded %d bytes: %q", n, data)
+	}
+	if ranges[0] != "bytes=10-" {
+		t.Errorf("Range = %q", ranges[0])
+	}
+	if _, err := os.Stat(path + ".checkpoint"); !os.IsNotExist(err) {
+		t.Error("checkpoint was not removed")
+	}
+
+	// A partial file that is already complete only needs renaming
+	os.WriteFile(path+".part", []byte(result), 0644)
+	os.WriteFile(path+".checkpoint", []byte(`{"job_id":"123","format":"csv"}`), 0644)
+	if n, err := client.Jobs.DownloadResult(context.Background(), "123", path, WithResultFormat(ResultFormatCSV), WithResume()); err != nil || n != int64(len(result)) {
+		t.Errorf("complete part: n = %d, err = %v", n, err)
+	}
+
+	// Without WithResume, or for another job, the download starts over
+	os.WriteFile(path+".part", []byte("garbage"), 0644)
+	os.WriteFile(path+".checkpoint", []byte(`{"job_id":"999","format":"csv"}`), 0644)
+	ranges = nil
+	if _, err := client.Jobs.DownloadResult(context.Background(), "123", path, WithResultFormat(ResultFormatCSV), WithResume()); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if string(data) != result || ranges[0] != "" {
+		t.Errorf("restarted download = %q, ranges = %q", data, ranges)
+	}
+}
+
+func TestJobsService_DownloadResult_ServerIgnoresRange(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/result/123", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[1]`)
+	})
+
+	path := filepath.Join(t.TempDir(), "result.json")
+	os.WriteFile(path+".part", []byte(`[1`), 0644)
+	os.WriteFile(path+".checkpoint", []byte(`{"job_id":"123","format":"json"}`), 0644)
+
+	if _, err := client.Jobs.DownloadResult(context.Background(), "123", path, WithResume()); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `[1]` {
+		t.Errorf("downloaded %q", data)
+	}
+}
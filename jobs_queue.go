@@ -0,0 +1,131 @@
+package treasuredata
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// queueSampleSize is the number of recent jobs used to estimate queue times
+const queueSampleSize = 100
+
+// JobQueue is a snapshot of the account's queued and running jobs
+type JobQueue struct {
+	// At is when the snapshot was taken
+	At      time.Time `json:"at"`
+	Queued  []Job     `json:"queued"`
+	Running []Job     `json:"running"`
+	// Groups summarize the jobs by engine type and priority, highest
+	// priority first
+	Groups []JobQueueGroup `json:"groups"`
+}
+
+// JobQueueGroup summarizes the queued and running jobs of one engine type
+// and priority. The job API does not report resource pools, so jobs of all
+// pools of an engine are grouped together.
+type JobQueueGroup struct {
+	Type     string `json:"type"`
+	Priority int    `json:"priority"`
+	Queued   int    `json:"queued"`
+	Running  int    `json:"running"`
+	// LongestWait is how long the oldest queued job has been waiting
+	LongestWait time.Duration `json:"longest_wait"`
+	// TypicalWait is the median time recent jobs of this type spent queued
+	// before starting, or zero if no recent job of the type started
+	TypicalWait time.Duration `json:"typical_wait"`
+}
+
+// EstimatedWait estimates how much longer a job that has been queued for
+// waited will wait, from the typical queue time of its group
+func (g JobQueueGroup) EstimatedWait(waited time.Duration) time.Duration {
+	if g.TypicalWait <= waited {
+		return 0
+	}
+	return g.TypicalWait - waited
+}
+
+// Queue lists the queued and running jobs and estimates queue times from the
+// most recent jobs
+func (s *JobsService) Queue(ctx context.Context) (*JobQueue, error) {
+	var queued, running, recent []Job
+	err := Batch(ctx, 3,
+		func(ctx context.Context) error {
+			var err error
+			queued, err = s.ListAll(ctx, &JobListOptions{Status: "queued"}).All(ctx)
+			return err
+		},
+		func(ctx context.Context) error {
+			var err error
+			running, err = s.ListAll(ctx, &JobListOptions{Status: "running"}).All(ctx)
+			return err
+		},
+		func(ctx context.Context) error {
+			resp, err := s.List(ctx, &JobListOptions{From: 0, To: queueSampleSize - 1})
+			if err == nil {
+				recent = resp.Jobs
+			}
+			return err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	queue := &JobQueue{At: now, Queued: queued, Running: running}
+	typical := typicalQueueTimes(recent)
+
+	type key struct {
+		jobType  string
+		priority int
+	}
+	groups := make(map[key]*JobQueueGroup)
+	group := func(job Job) *JobQueueGroup {
+		k := key{job.Type, job.Priority}
+		if groups[k] == nil {
+			groups[k] = &JobQueueGroup{Type: job.Type, Priority: job.Priority, TypicalWait: typical[job.Type]}
+		}
+		return groups[k]
+	}
+	for _, job := range queue.Queued {
+		g := group(job)
+		g.Queued++
+		if wait := now.Sub(job.CreatedAt.Time); wait > g.LongestWait {
+			g.LongestWait = wait
+		}
+	}
+	for _, job := range queue.Running {
+		group(job).Running++
+	}
+
+	for _, g := range groups {
+		queue.Groups = append(queue.Groups, *g)
+	}
+	sort.Slice(queue.Groups, func(i, j int) bool {
+		a, b := queue.Groups[i], queue.Groups[j]
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		return a.Type < b.Type
+	})
+	return queue, nil
+}
+
+// typicalQueueTimes returns the median time between creation and start of the
+// given jobs per engine type
+func typicalQueueTimes(jobs []Job) map[string]time.Duration {
+	waits := make(map[string][]time.Duration)
+	for _, job := range jobs {
+		if job.StartAt.IsZero() || job.CreatedAt.IsZero() || job.StartAt.Before(job.CreatedAt.Time) {
+			continue
+		}
+		waits[job.Type] = append(waits[job.Type], job.StartAt.Sub(job.CreatedAt.Time))
+	}
+
+	typical := make(map[string]time.Duration, len(waits))
+	for jobType, ws := range waits {
+		sort.Slice(ws, func(i, j int) bool { return ws[i] < ws[j] })
+		typical[jobType] = ws[len(ws)/2]
+	}
+	return typical
+}
@@ -0,0 +1,68 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestJobsService_Queue(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	now := time.Now().Unix()
+	mux.HandleFunc("/v3/job/list", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("status") {
+		case "queued":
+			fmt.Fprintf(w, `{"jobs": [
+				{"job_id": "10", "type": "hive", "priority": 0, "status": "queued", "created_at": %d},
+				{"job_id": "11", "type": "hive", "priority": 0, "status": "queued", "created_at": %d},
+				{"job_id": "12", "type": "presto", "priority": 1, "status": "queued", "created_at": %d}
+			]}`, now-600, now-60, now-30)
+		case "running":
+			fmt.Fprintf(w, `{"jobs": [
+				{"job_id": "9", "type": "hive", "priority": 0, "status": "running", "created_at": %d, "start_at": %d}
+			]}`, now-900, now-800)
+		default:
+			// Recent jobs: hive waited 100s, 200s and 300s; presto 5s
+			fmt.Fprintf(w, `{"jobs": [
+				{"job_id": "1", "type": "hive", "status": "success", "created_at": %d, "start_at": %d},
+				{"job_id": "2", "type": "hive", "status": "success", "created_at": %d, "start_at": %d},
+				{"job_id": "3", "type": "hive", "status": "error", "created_at": %d, "start_at": %d},
+				{"job_id": "4", "type": "presto", "status": "success", "created_at": %d, "start_at": %d},
+				{"job_id": "5", "type": "presto", "status": "queued", "created_at": %d}
+			]}`, now-1000, now-900, now-1000, now-800, now-1000, now-700, now-100, now-95, now-30)
+		}
+	})
+
+	queue, err := client.Jobs.Queue(context.Background())
+	if err != nil {
+		t.Fatalf("Jobs.Queue returned error: %v", err)
+	}
+
+	if len(queue.Queued) != 3 || len(queue.Running) != 1 {
+		t.Fatalf("queued=%d running=%d, want 3 and 1", len(queue.Queued), len(queue.Running))
+	}
+	if len(queue.Groups) != 2 {
+		t.Fatalf("groups = %+v", queue.Groups)
+	}
+
+	presto, hive := queue.Groups[0], queue.Groups[1]
+	if presto.Type != "presto" || presto.Priority != 1 || presto.Queued != 1 || presto.TypicalWait != 5*time.Second {
+		t.Errorf("presto group = %+v", presto)
+	}
+	if hive.Type != "hive" || hive.Queued != 2 || hive.Running != 1 || hive.TypicalWait != 200*time.Second {
+		t.Errorf("hive group = %+v", hive)
+	}
+	if hive.LongestWait < 600*time.Second || hive.LongestWait > 610*time.Second {
+		t.Errorf("hive LongestWait = %v, want about 10m", hive.LongestWait)
+	}
+	if got := hive.EstimatedWait(60 * time.Second); got != 140*time.Second {
+		t.Errorf("EstimatedWait(1m) = %v, want 2m20s", got)
+	}
+	if got := hive.EstimatedWait(10 * time.Minute); got != 0 {
+		t.Errorf("EstimatedWait(10m) = %v, want 0", got)
+	}
+}
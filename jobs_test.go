@@ -317,6 +317,68 @@ func TestJobsService_Get_NotFound(t *testing.T) {
 	}
 }
 
+func TestJobsService_GetResultSchema(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/show/12345", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"job_id": "12345", "type": "hive", "status": "success", "hive_result_schema": "[[\"td_client_id\", \"string\"], [\"cnt\", \"bigint\"]]"}`)
+	})
+	mux.HandleFunc("/v3/job/show/67890", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"job_id": "67890", "type": "hive", "status": "running", "hive_result_schema": null}`)
+	})
+
+	ctx := context.Background()
+	columns, err := client.Jobs.GetResultSchema(ctx, "12345")
+	if err != nil {
+		t.Fatalf("Jobs.GetResultSchema returned error: %v", err)
+	}
+	want := []TableColumn{{Name: "td_client_id", Type: "string"}, {Name: "cnt", Type: "bigint"}}
+	if !reflect.DeepEqual(columns, want) {
+		t.Errorf("Jobs.GetResultSchema returned %+v, want %+v", columns, want)
+	}
+
+	columns, err = client.Jobs.GetResultSchema(ctx, "67890")
+	if err != nil {
+		t.Fatalf("Jobs.GetResultSchema returned error: %v", err)
+	}
+	if len(columns) != 0 {
+		t.Errorf("Jobs.GetResultSchema for a running job returned %+v, want none", columns)
+	}
+}
+
+func TestJobsService_GetLogs(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/show/12345", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"job_id": "12345", "status": "error", "debug": {"cmdout": "started at 2024-01-01", "stderr": "Query failed: line 1:8: Column 'x' cannot be resolved"}}`)
+	})
+	mux.HandleFunc("/v3/job/show/67890", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"job_id": "67890", "status": "queued"}`)
+	})
+
+	ctx := context.Background()
+	logs, err := client.Jobs.GetLogs(ctx, "12345")
+	if err != nil {
+		t.Fatalf("Jobs.GetLogs returned error: %v", err)
+	}
+	want := &JobDebug{Cmdout: "started at 2024-01-01", Stderr: "Query failed: line 1:8: Column 'x' cannot be resolved"}
+	if *logs != *want {
+		t.Errorf("Jobs.GetLogs returned %+v, want %+v", logs, want)
+	}
+
+	logs, err = client.Jobs.GetLogs(ctx, "67890")
+	if err != nil {
+		t.Fatalf("Jobs.GetLogs returned error: %v", err)
+	}
+	if *logs != (JobDebug{}) {
+		t.Errorf("Jobs.GetLogs without a debug section returned %+v, want empty logs", logs)
+	}
+}
+
 func TestJobsService_Status(t *testing.T) {
 	client, mux, teardown := setup()
 	defer teardown()
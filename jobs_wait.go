@@ -0,0 +1,103 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// JobState is the state of a job as reported by the API
+type JobState string
+
+const (
+	JobStateQueued  JobState = "queued"
+	JobStateBooting JobState = "booting"
+	JobStateRunning JobState = "running"
+	JobStateSuccess JobState = "success"
+	JobStateError   JobState = "error"
+	JobStateKilled  JobState = "killed"
+)
+
+// Terminal reports whether a job in this state has finished
+func (s JobState) Terminal() bool {
+	return s == JobStateSuccess || s == JobStateError || s == JobStateKilled
+}
+
+// State returns the job's status as a JobState
+func (s *JobStatus) State() JobState {
+	return JobState(s.Status)
+}
+
+// WaitOptions controls how WaitForCompletion polls a job
+type WaitOptions struct {
+	// PollInterval is the delay before the second status check. It grows by
+	// half after each check, up to MaxPollInterval. Defaults to 2 seconds.
+	PollInterval time.Duration
+	// MaxPollInterval caps the delay between checks. Defaults to 30 seconds.
+	MaxPollInterval time.Duration
+	// MaxWait gives up after this long. Zero waits until ctx is done.
+	MaxWait time.Duration
+	// OnStatus, if set, is called with every status fetched, including the
+	// terminal one
+	OnStatus func(*JobStatus)
+}
+
+const (
+	defaultWaitPollInterval    = 2 * time.Second
+	defaultWaitMaxPollInterval = 30 * time.Second
+)
+
+// WaitForCompletion polls a job until it succeeds, fails or is killed and
+// returns its final status; check the outcome with status.State(). A job that
+// fails is not an error. An error is returned when a status check fails, or
+// when MaxWait passes or ctx is done first; the latter wraps ctx.Err() or
+// context.DeadlineExceeded.
+func (s *JobsService) WaitForCompletion(ctx context.Context, jobID string, opts WaitOptions) (*JobStatus, error) {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = defaultWaitPollInterval
+	}
+	maxInterval := opts.MaxPollInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultWaitMaxPollInterval
+	}
+	if interval > maxInterval {
+		interval = maxInterval
+	}
+
+	var deadline <-chan time.Time
+	if opts.MaxWait > 0 {
+		timer := time.NewTimer(opts.MaxWait)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	for {
+		status, err := s.Status(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		if opts.OnStatus != nil {
+			opts.OnStatus(status)
+		}
+		if status.State().Terminal() {
+			return status, nil
+		}
+
+		wait := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			wait.Stop()
+			return nil, fmt.Errorf("job %s did not finish: %w", jobID, ctx.Err())
+		case <-deadline:
+			wait.Stop()
+			return nil, fmt.Errorf("job %s did not finish within %s: %w", jobID, opts.MaxWait, context.DeadlineExceeded)
+		case <-wait.C:
+		}
+
+		interval += interval / 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package treasuredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestJobsService_WaitForCompletion(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	states := []string{"queued", "running", "running", "error"}
+	calls := 0
+	mux.HandleFunc("/v3/job/status/123", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprintf(w, `{"job_id": "123", "status": %q}`, states[calls])
+		calls++
+	})
+
+	var seen []JobState
+	status, err := client.Jobs.WaitForCompletion(context.Background(), "123", WaitOptions{
+		PollInterval: time.Millisecond,
+		OnStatus:     func(s *JobStatus) { seen = append(seen, s.State()) },
+	})
+	if err != nil {
+		t.Fatalf("WaitForCompletion returned error: %v", err)
+	}
+	if status.State() != JobStateError {
+		t.Errorf("state = %q, want %q", status.State(), JobStateError)
+	}
+	if len(seen) != 4 || seen[0] != JobStateQueued || seen[3] != JobStateError {
+		t.Errorf("OnStatus saw %v", seen)
+	}
+}
+
+func TestJobsService_WaitForCompletion_MaxWait(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/status/123", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"job_id": "123", "status": "running"}`)
+	})
+
+	_, err := client.Jobs.WaitForCompletion(context.Background(), "123", WaitOptions{
+		PollInterval: time.Millisecond,
+		MaxWait:      20 * time.Millisecond,
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestJobState_Terminal(t *testing.T) {
+	for _, s := range []JobState{JobStateSuccess, JobStateError, JobStateKilled} {
+		if !s.Terminal() {
+			t.Errorf("%q should be terminal", s)
+		}
+	}
+	for _, s := range []JobState{JobStateQueued, JobStateBooting, JobStateRunning, "unknown"} {
+		if s.Terminal() {
+			t.Errorf("%q should not be terminal", s)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package treasuredata
+
+import (
+	"context"
+	"time"
+)
+
+// JobStatusUpdate is a state transition of a watched job
+type JobStatusUpdate struct {
+	JobID string
+	// Previous is the state before the transition; empty for the first update
+	Previous JobState
+	// State is the new state; Status holds the full status it was read from
+	State  JobState
+	Status *JobStatus
+	// Err is set on the last update when a status check failed. The job may
+	// still be running.
+	Err error
+}
+
+// Watch polls a job and sends an update on the returned channel whenever its
+// state changes, starting with the current state. The channel is closed after
+// the job reaches a terminal state, after a status check fails (reported in
+// the last update's Err) or once ctx is done; cancel ctx to stop watching.
+// Polling backs off as in WaitForCompletion with the default WaitOptions.
+//
+// The first status check is made before Watch returns, so an unknown job
+// is reported as an error rather than on the channel.
+func (s *JobsService) Watch(ctx context.Context, jobID string) (<-chan JobStatusUpdate, error) {
+	return s.WatchWithOptions(ctx, jobID, WaitOptions{})
+}
+
+// WatchWithOptions is Watch with control over polling. MaxWait ends the watch
+// with an update whose Err wraps context.DeadlineExceeded, and OnStatus is
+// called with every status fetched, not only transitions.
+func (s *JobsService) WatchWithOptions(ctx context.Context, jobID string, opts WaitOptions) (<-chan JobStatusUpdate, error) {
+	if jobID == "" {
+		return nil, NewValidationError("jobID", jobID, "cannot be empty")
+	}
+	status, err := s.Status(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(chan JobStatusUpdate, 1)
+	if opts.OnStatus != nil {
+		opts.OnStatus(status)
+	}
+	updates <- JobStatusUpdate{JobID: jobID, State: status.State(), Status: status}
+	if status.State().Terminal() {
+		close(updates)
+		return updates, nil
+	}
+
+	go func() {
+		defer close(updates)
+		send := func(update JobStatusUpdate) {
+			select {
+			case updates <- update:
+			case <-ctx.Done():
+			}
+		}
+
+		// The current status was just fetched, so wait one interval before
+		// WaitForCompletion checks again
+		interval := opts.PollInterval
+		if interval <= 0 {
+			interval = defaultWaitPollInterval
+		}
+		wait := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			wait.Stop()
+			return
+		case <-wait.C:
+		}
+
+		state := status.State()
+		onStatus := opts.OnStatus
+		opts.OnStatus = func(status *JobStatus) {
+			if onStatus != nil {
+				onStatus(status)
+			}
+			if status.State() == state {
+				return
+			}
+			send(JobStatusUpdate{JobID: jobID, Previous: state, State: status.State(), Status: status})
+			state = status.State()
+		}
+		_, err := s.WaitForCompletion(ctx, jobID, opts)
+		if err != nil && ctx.Err() == nil {
+			send(JobStatusUpdate{JobID: jobID, Previous: state, State: state, Err: err})
+		}
+	}()
+	return updates, nil
+}
@@ -0,0 +1,106 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestJobsService_Watch(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	states := []string{"queued", "queued", "running", "running", "success"}
+	calls := 0
+	mux.HandleFunc("/v3/job/status/123", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprintf(w, `{"job_id": "123", "status": %q}`, states[calls])
+		calls++
+	})
+
+	updates, err := client.Jobs.WatchWithOptions(context.Background(), "123", WaitOptions{PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Jobs.Watch returned error: %v", err)
+	}
+
+	var got []string
+	for update := range updates {
+		if update.Err != nil {
+			t.Fatalf("unexpected error update: %v", update.Err)
+		}
+		got = append(got, fmt.Sprintf("%s->%s", update.Previous, update.State))
+	}
+	if want := []string{"->queued", "queued->running", "running->success"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("updates = %v, want %v", got, want)
+	}
+	if calls != len(states) {
+		t.Errorf("status checks = %d, want %d", calls, len(states))
+	}
+}
+
+func TestJobsService_Watch_Errors(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/status/404", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+	})
+	if _, err := client.Jobs.Watch(context.Background(), "404"); !IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+	if _, err := client.Jobs.Watch(context.Background(), ""); err == nil {
+		t.Error("Expected error for empty job ID")
+	}
+
+	calls := 0
+	mux.HandleFunc("/v3/job/status/1", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls > 1 {
+			http.Error(w, `{"error": "boom"}`, http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"job_id": "1", "status": "running"}`)
+	})
+	updates, err := client.Jobs.WatchWithOptions(context.Background(), "1", WaitOptions{PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Jobs.Watch returned error: %v", err)
+	}
+	if first := <-updates; first.State != JobStateRunning {
+		t.Errorf("first update = %+v", first)
+	}
+	last, ok := <-updates
+	if !ok || last.Err == nil || last.State != JobStateRunning {
+		t.Errorf("Expected an error update, got %+v", last)
+	}
+	if _, ok := <-updates; ok {
+		t.Error("Expected the channel to be closed after the error")
+	}
+}
+
+func TestJobsService_Watch_Cancel(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/status/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"job_id": "1", "status": "running"}`)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates, err := client.Jobs.WatchWithOptions(ctx, "1", WaitOptions{PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Jobs.Watch returned error: %v", err)
+	}
+	<-updates
+	cancel()
+
+	select {
+	case _, ok := <-updates:
+		if ok {
+			t.Error("Expected no update after cancel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel was not closed after cancel")
+	}
+}
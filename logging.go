@@ -0,0 +1,67 @@
+package treasuredata
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// WithLogger emits structured debug logs for every request attempt, retry
+// and response. The API key is never logged.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) error {
+		c.logger = logger
+		return nil
+	}
+}
+
+func (c *Client) logRequest(ctx context.Context, req *http.Request, attempt int) {
+	if c.logger == nil {
+		return
+	}
+	c.logger.DebugContext(ctx, "treasuredata: sending request",
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.Redacted()),
+		slog.Int("attempt", attempt+1),
+	)
+}
+
+func (c *Client) logResponse(ctx context.Context, req *http.Request, resp *http.Response, duration time.Duration, err error) {
+	if c.logger == nil {
+		return
+	}
+	if err != nil {
+		c.logger.DebugContext(ctx, "treasuredata: request failed",
+			slog.String("method", req.Method),
+			slog.String("url", req.URL.Redacted()),
+			slog.Duration("duration", duration),
+			slog.Any("error", err),
+		)
+		return
+	}
+	attrs := []any{
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.Redacted()),
+		slog.Int("status", resp.StatusCode),
+		slog.Duration("duration", duration),
+	}
+	if id := RequestIDFromResponse(resp); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	c.logger.DebugContext(ctx, "treasuredata: received response", attrs...)
+}
+
+// logRetry logs a retry; attempt is the zero-based index of the upcoming attempt
+func (c *Client) logRetry(ctx context.Context, req *http.Request, resp *http.Response, attempt int, delay time.Duration) {
+	if c.logger == nil {
+		return
+	}
+	c.logger.DebugContext(ctx, "treasuredata: retrying request",
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.Redacted()),
+		slog.Int("status", resp.StatusCode),
+		slog.Int("attempt", attempt+1),
+		slog.Duration("delay", delay),
+	)
+}
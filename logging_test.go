@@ -0,0 +1,56 @@
+package treasuredata
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithLogger(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	if err := WithLogger(logger)(client); err != nil {
+		t.Fatal(err)
+	}
+	if err := WithRateLimiter(RateLimiterOptions{MaxRetries: 1})(client); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	mux.HandleFunc("/v3/database/list", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"databases": []}`)
+	})
+
+	if _, err := client.Databases.List(context.Background()); err != nil {
+		t.Fatalf("Databases.List returned error: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		`msg="treasuredata: sending request" method=GET`,
+		"status=429",
+		`msg="treasuredata: retrying request"`,
+		"attempt=2",
+		"status=200",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Log output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, client.APIKey) {
+		t.Error("Log output contains the API key")
+	}
+}
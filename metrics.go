@@ -0,0 +1,92 @@
+package treasuredata
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Service names reported in RequestInfo
+const (
+	MetricsServiceTD       = "td"
+	MetricsServiceCDP      = "cdp"
+	MetricsServiceWorkflow = "workflow"
+)
+
+// RequestInfo identifies an HTTP request made by the client
+type RequestInfo struct {
+	// Service is the API the request was sent to: MetricsServiceTD,
+	// MetricsServiceCDP, MetricsServiceWorkflow, or the host for other URLs
+	Service string
+	Method  string
+	Path    string
+}
+
+// ClientMetrics observes every HTTP request the client makes. Retried requests
+// are reported once per attempt. Implementations must be safe for concurrent use.
+type ClientMetrics interface {
+	// OnRequestStart is called before the request is sent
+	OnRequestStart(ctx context.Context, info RequestInfo)
+	// OnRequestEnd is called when the response headers arrive or the request
+	// fails. statusCode is 0 when no response was received.
+	OnRequestEnd(ctx context.Context, info RequestInfo, statusCode int, duration time.Duration, err error)
+}
+
+// WithMetrics reports request starts, completions, status codes and latencies to m
+func WithMetrics(m ClientMetrics) ClientOption {
+	return func(c *Client) error {
+		c.metrics = m
+		return nil
+	}
+}
+
+// requestInfo classifies a request by the base URL it was sent to
+func (c *Client) requestInfo(req *http.Request) RequestInfo {
+	info := RequestInfo{Service: req.URL.Host, Method: req.Method, Path: req.URL.Path}
+
+	// Base URLs may share a host behind proxies, so the longest matching
+	// base path wins
+	bases := []struct {
+		service string
+		url     *url.URL
+	}{
+		{MetricsServiceTD, c.BaseURL},
+		{MetricsServiceCDP, c.CDPURL},
+		{MetricsServiceWorkflow, c.WorkflowURL},
+	}
+
+	matched := -1
+	for _, base := range bases {
+		if base.url == nil || base.url.Host != req.URL.Host || !strings.HasPrefix(req.URL.Path, base.url.Path) {
+			continue
+		}
+		if len(base.url.Path) > matched {
+			info.Service = base.service
+			matched = len(base.url.Path)
+		}
+	}
+	return info
+}
+
+// doHTTP sends a single request attempt, reporting it to the configured metrics
+func (c *Client) doHTTP(req *http.Request) (*http.Response, error) {
+	if c.metrics == nil {
+		return c.httpClient.Do(req)
+	}
+
+	ctx := req.Context()
+	info := c.requestInfo(req)
+	c.metrics.OnRequestStart(ctx, info)
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	c.metrics.OnRequestEnd(ctx, info, status, time.Since(start), err)
+	return resp, err
+}
@@ -0,0 +1,75 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingMetrics struct {
+	mu     sync.Mutex
+	starts []RequestInfo
+	ends   []string
+}
+
+func (m *recordingMetrics) OnRequestStart(ctx context.Context, info RequestInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.starts = append(m.starts, info)
+}
+
+func (m *recordingMetrics) OnRequestEnd(ctx context.Context, info RequestInfo, statusCode int, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ends = append(m.ends, fmt.Sprintf("%s %s %s %d", info.Service, info.Method, info.Path, statusCode))
+}
+
+func TestWithMetrics(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/v3/database/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"databases": []}`)
+	})
+	mux.HandleFunc("/cdp/audiences", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error": "not found"}`)
+	})
+
+	metrics := &recordingMetrics{}
+	client, err := NewClient("test-api-key", WithMetrics(metrics))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	client.CDPURL, _ = url.Parse(server.URL + "/cdp/")
+	client.WorkflowURL, _ = url.Parse("http://workflow.invalid/")
+
+	ctx := context.Background()
+	if _, err := client.Databases.List(ctx); err != nil {
+		t.Errorf("Databases.List returned error: %v", err)
+	}
+	if _, err := client.CDP.ListAudiences(ctx); err == nil {
+		t.Error("Expected error from CDP.ListAudiences")
+	}
+
+	want := []string{
+		"td GET /v3/database/list 200",
+		"cdp GET /cdp/audiences 404",
+	}
+	if len(metrics.starts) != len(want) || len(metrics.ends) != len(want) {
+		t.Fatalf("Recorded %d starts and %d ends, want %d each", len(metrics.starts), len(metrics.ends), len(want))
+	}
+	for i := range want {
+		if metrics.ends[i] != want[i] {
+			t.Errorf("Request %d = %q, want %q", i, metrics.ends[i], want[i])
+		}
+	}
+}
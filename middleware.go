@@ -0,0 +1,50 @@
+package treasuredata
+
+import "net/http"
+
+// Middleware wraps an http.RoundTripper to add cross-cutting behavior such as
+// custom headers, auth refresh, or request mutation
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts an ordinary function to the http.RoundTripper interface
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req)
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// WithMiddleware adds middleware to the request chain used by every service.
+// Middleware run in the order given: the first one sees the request first and
+// the response last.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *Client) error {
+		c.middleware = append(c.middleware, middleware...)
+		return nil
+	}
+}
+
+// applyMiddleware wraps the HTTP client's transport with the configured middleware.
+// The HTTP dump, if any, is innermost so it shows requests as sent. The HTTP
+// client is copied so that a client passed to WithHTTPClient is not modified.
+func (c *Client) applyMiddleware() {
+	if len(c.middleware) == 0 && c.httpDump == nil {
+		return
+	}
+
+	transport := c.httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if c.httpDump != nil {
+		transport = c.httpDump.wrap(transport)
+	}
+
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		transport = c.middleware[i](transport)
+	}
+
+	httpClient := *c.httpClient
+	httpClient.Transport = transport
+	c.httpClient = &httpClient
+}
@@ -0,0 +1,86 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestWithMiddleware_AllServices(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var seen []string
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if got := strings.Join(r.Header.Values("X-Custom"), ","); got != "outer,inner" {
+			t.Errorf("%s: X-Custom = %q, want %q", r.URL.Path, got, "outer,inner")
+		}
+		seen = append(seen, r.URL.Path)
+		switch r.URL.Path {
+		case "/v3/database/list":
+			fmt.Fprint(w, `{"databases": []}`)
+		case "/audiences":
+			fmt.Fprint(w, `[]`)
+		case "/api/workflows":
+			fmt.Fprint(w, `{"workflows": []}`)
+		default:
+			fmt.Fprint(w, `a,b`)
+		}
+	})
+
+	addHeader := func(value string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				req.Header.Add("X-Custom", value)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	httpClient := &http.Client{}
+	client, err := NewClient("test-api-key",
+		WithHTTPClient(httpClient),
+		WithMiddleware(addHeader("outer")),
+		WithMiddleware(addHeader("inner")),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	u, _ := url.Parse(server.URL + "/")
+	client.BaseURL = u
+	client.CDPURL = u
+	client.WorkflowURL = u
+
+	ctx := context.Background()
+	if _, err := client.Databases.List(ctx); err != nil {
+		t.Errorf("Databases.List returned error: %v", err)
+	}
+	if _, err := client.CDP.ListAudiences(ctx); err != nil {
+		t.Errorf("CDP.ListAudiences returned error: %v", err)
+	}
+	if _, err := client.Workflow.ListWorkflows(ctx, nil); err != nil {
+		t.Errorf("Workflow.ListWorkflows returned error: %v", err)
+	}
+	body, err := client.Results.GetResult(ctx, "123", nil)
+	if err != nil {
+		t.Errorf("Results.GetResult returned error: %v", err)
+	} else {
+		io.Copy(io.Discard, body)
+		body.Close()
+	}
+
+	if len(seen) != 4 {
+		t.Errorf("Server saw %d requests, want 4: %v", len(seen), seen)
+	}
+
+	if httpClient.Transport != nil {
+		t.Error("WithMiddleware modified the caller's http.Client")
+	}
+}
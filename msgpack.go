@@ -0,0 +1,474 @@
+package treasuredata
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+// msgpackEncoder writes values in MessagePack format.
+// It supports the value types produced by encoding/json decoding plus
+// common Go scalar types, which covers the records accepted by the import API.
+type msgpackEncoder struct {
+	buf *bytes.Buffer
+}
+
+// encodeMsgpack encodes v as a single MessagePack value
+func encodeMsgpack(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := &msgpackEncoder{buf: &buf}
+	if err := enc.encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (e *msgpackEncoder) encode(v interface{}) error {
+	switch val := v.(type) {
+	case nil:
+		e.buf.WriteByte(0xc0)
+	case bool:
+		if val {
+			e.buf.WriteByte(0xc3)
+		} else {
+			e.buf.WriteByte(0xc2)
+		}
+	case int:
+		e.encodeInt(int64(val))
+	case int8:
+		e.encodeInt(int64(val))
+	case int16:
+		e.encodeInt(int64(val))
+	case int32:
+		e.encodeInt(int64(val))
+	case int64:
+		e.encodeInt(val)
+	case uint:
+		e.encodeUint(uint64(val))
+	case uint8:
+		e.encodeUint(uint64(val))
+	case uint16:
+		e.encodeUint(uint64(val))
+	case uint32:
+		e.encodeUint(uint64(val))
+	case uint64:
+		e.encodeUint(val)
+	case float32:
+		e.encodeFloat(float64(val))
+	case float64:
+		e.encodeFloat(val)
+	case json.Number:
+		// Whole numbers decoded from JSON, such as 1e3 or a "time" of
+		// 1700000000.0, are written as integers so they keep their integer
+		// column type; float64 values are always written as floats
+		if i, err := val.Int64(); err == nil {
+			e.encodeInt(i)
+		} else if f, err := val.Float64(); err != nil {
+			e.encodeString(val.String())
+		} else if f == math.Trunc(f) && f >= -(1<<63) && f < (1<<63) {
+			e.encodeInt(int64(f))
+		} else {
+			e.encodeFloat(f)
+		}
+	case string:
+		e.encodeString(val)
+	case []byte:
+		e.encodeBinary(val)
+	case time.Time:
+		e.encodeInt(val.Unix())
+	case []interface{}:
+		e.encodeArrayHeader(len(val))
+		for _, item := range val {
+			if err := e.encode(item); err != nil {
+				return err
+			}
+		}
+	case []string:
+		e.encodeArrayHeader(len(val))
+		for _, item := range val {
+			e.encodeString(item)
+		}
+	case map[string]interface{}:
+		// Sort keys so that identical records always produce identical bytes
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		e.encodeMapHeader(len(val))
+		for _, k := range keys {
+			e.encodeString(k)
+			if err := e.encode(val[k]); err != nil {
+				return fmt.Errorf("failed to encode field %q: %w", k, err)
+			}
+		}
+	default:
+		return fmt.Errorf("msgpack: unsupported type %T", v)
+	}
+	return nil
+}
+
+func (e *msgpackEncoder) encodeInt(i int64) {
+	if i >= 0 {
+		e.encodeUint(uint64(i))
+		return
+	}
+
+	switch {
+	case i >= -32:
+		e.buf.WriteByte(byte(i))
+	case i >= math.MinInt8:
+		e.buf.WriteByte(0xd0)
+		e.buf.WriteByte(byte(i))
+	case i >= math.MinInt16:
+		e.buf.WriteByte(0xd1)
+		e.writeUint16(uint16(i))
+	case i >= math.MinInt32:
+		e.buf.WriteByte(0xd2)
+		e.writeUint32(uint32(i))
+	default:
+		e.buf.WriteByte(0xd3)
+		e.writeUint64(uint64(i))
+	}
+}
+
+func (e *msgpackEncoder) encodeUint(u uint64) {
+	switch {
+	case u <= 0x7f:
+		e.buf.WriteByte(byte(u))
+	case u <= math.MaxUint8:
+		e.buf.WriteByte(0xcc)
+		e.buf.WriteByte(byte(u))
+	case u <= math.MaxUint16:
+		e.buf.WriteByte(0xcd)
+		e.writeUint16(uint16(u))
+	case u <= math.MaxUint32:
+		e.buf.WriteByte(0xce)
+		e.writeUint32(uint32(u))
+	default:
+		e.buf.WriteByte(0xcf)
+		e.writeUint64(u)
+	}
+}
+
+func (e *msgpackEncoder) encodeFloat(f float64) {
+	e.buf.WriteByte(0xcb)
+	e.writeUint64(math.Float64bits(f))
+}
+
+func (e *msgpackEncoder) encodeString(s string) {
+	n := len(s)
+	switch {
+	case n <= 31:
+		e.buf.WriteByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		e.buf.WriteByte(0xd9)
+		e.buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		e.buf.WriteByte(0xda)
+		e.writeUint16(uint16(n))
+	default:
+		e.buf.WriteByte(0xdb)
+		e.writeUint32(uint32(n))
+	}
+	e.buf.WriteString(s)
+}
+
+func (e *msgpackEncoder) encodeBinary(b []byte) {
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		e.buf.WriteByte(0xc4)
+		e.buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		e.buf.WriteByte(0xc5)
+		e.writeUint16(uint16(n))
+	default:
+		e.buf.WriteByte(0xc6)
+		e.writeUint32(uint32(n))
+	}
+	e.buf.Write(b)
+}
+
+func (e *msgpackEncoder) encodeArrayHeader(n int) {
+	switch {
+	case n <= 15:
+		e.buf.WriteByte(0x90 | byte(n))
+	case n <= math.MaxUint16:
+		e.buf.WriteByte(0xdc)
+		e.writeUint16(uint16(n))
+	default:
+		e.buf.WriteByte(0xdd)
+		e.writeUint32(uint32(n))
+	}
+}
+
+func (e *msgpackEncoder) encodeMapHeader(n int) {
+	switch {
+	case n <= 15:
+		e.buf.WriteByte(0x80 | byte(n))
+	case n <= math.MaxUint16:
+		e.buf.WriteByte(0xde)
+		e.writeUint16(uint16(n))
+	default:
+		e.buf.WriteByte(0xdf)
+		e.writeUint32(uint32(n))
+	}
+}
+
+func (e *msgpackEncoder) writeUint16(v uint16) {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], v)
+	e.buf.Write(b[:])
+}
+
+func (e *msgpackEncoder) writeUint32(v uint32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	e.buf.Write(b[:])
+}
+
+func (e *msgpackEncoder) writeUint64(v uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	e.buf.Write(b[:])
+}
+
+// msgpackDecoder reads a stream of MessagePack values. Integers decode to
+// int64 (uint64 above math.MaxInt64), floats to float64, strings to string,
+// binary to []byte, arrays to []interface{} and maps to map[string]interface{}.
+type msgpackDecoder struct {
+	r *bufio.Reader
+}
+
+func newMsgpackDecoder(r io.Reader) *msgpackDecoder {
+	return &msgpackDecoder{r: bufio.NewReader(r)}
+}
+
+// decode reads the next value, returning io.EOF at the end of the stream
+func (d *msgpackDecoder) decode() (interface{}, error) {
+	c, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := d.decodeValue(c)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return v, err
+}
+
+func (d *msgpackDecoder) next() (interface{}, error) {
+	c, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	return d.decodeValue(c)
+}
+
+func (d *msgpackDecoder) decodeValue(c byte) (interface{}, error) {
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xf0 == 0x80:
+		return d.decodeMap(int(c & 0x0f))
+	case c&0xf0 == 0x90:
+		return d.decodeArray(int(c & 0x0f))
+	case c&0xe0 == 0xa0:
+		return d.readString(int(c & 0x1f))
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.readLength(c - 0xc4)
+		if err != nil {
+			return nil, err
+		}
+		return d.readBytes(n)
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.readLength(c - 0xc7)
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeExt(n)
+	case 0xca:
+		b, err := d.readBytes(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 0xcb:
+		b, err := d.readBytes(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		b, err := d.readBytes(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		u := readUintBE(b)
+		if u > math.MaxInt64 {
+			return u, nil
+		}
+		return int64(u), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		b, err := d.readBytes(size)
+		if err != nil {
+			return nil, err
+		}
+		// Sign-extend from the encoded width
+		shift := uint(64 - 8*size)
+		return int64(readUintBE(b)<<shift) >> shift, nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.decodeExt(1 << (c - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.readLength(c - 0xd9)
+		if err != nil {
+			return nil, err
+		}
+		return d.readString(n)
+	case 0xdc, 0xdd:
+		n, err := d.readLength(c - 0xdc + 1)
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(n)
+	case 0xde, 0xdf:
+		n, err := d.readLength(c - 0xde + 1)
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(n)
+	}
+	return nil, fmt.Errorf("msgpack: invalid type byte 0x%02x", c)
+}
+
+// readLength reads a big-endian length of 1, 2 or 4 bytes (width 0, 1 or 2)
+func (d *msgpackDecoder) readLength(width byte) (int, error) {
+	b, err := d.readBytes(1 << width)
+	if err != nil {
+		return 0, err
+	}
+	return int(readUintBE(b)), nil
+}
+
+// A corrupt or hostile length header can claim up to 4 GiB, so the decoder
+// preallocates at most these sizes and grows values as their data is read
+const (
+	msgpackMaxPreallocBytes = 64 << 10
+	msgpackMaxPreallocItems = 1024
+)
+
+func (d *msgpackDecoder) readBytes(n int) ([]byte, error) {
+	if n <= msgpackMaxPreallocBytes {
+		b := make([]byte, n)
+		if _, err := io.ReadFull(d.r, b); err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(msgpackMaxPreallocBytes)
+	if _, err := io.CopyN(&buf, d.r, int64(n)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (d *msgpackDecoder) readString(n int) (string, error) {
+	b, err := d.readBytes(n)
+	return string(b), err
+}
+
+func (d *msgpackDecoder) decodeArray(n int) ([]interface{}, error) {
+	arr := make([]interface{}, 0, min(n, msgpackMaxPreallocItems))
+	for i := 0; i < n; i++ {
+		v, err := d.next()
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, v)
+	}
+	return arr, nil
+}
+
+func (d *msgpackDecoder) decodeMap(n int) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, min(n, msgpackMaxPreallocItems))
+	for i := 0; i < n; i++ {
+		k, err := d.next()
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.next()
+		if err != nil {
+			return nil, err
+		}
+		switch key := k.(type) {
+		case string:
+			m[key] = v
+		case []byte:
+			m[string(key)] = v
+		default:
+			m[fmt.Sprint(key)] = v
+		}
+	}
+	return m, nil
+}
+
+// decodeExt reads an extension value. The timestamp extension (-1) decodes to
+// time.Time; other extensions are returned as their raw payload.
+func (d *msgpackDecoder) decodeExt(n int) (interface{}, error) {
+	typ, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	b, err := d.readBytes(n)
+	if err != nil {
+		return nil, err
+	}
+	if int8(typ) != -1 {
+		return b, nil
+	}
+
+	switch n {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(b)), 0).UTC(), nil
+	case 8:
+		v := binary.BigEndian.Uint64(b)
+		return time.Unix(int64(v&0x3ffffffff), int64(v>>34)).UTC(), nil
+	case 12:
+		return time.Unix(int64(binary.BigEndian.Uint64(b[4:])), int64(binary.BigEndian.Uint32(b[:4]))).UTC(), nil
+	}
+	return nil, fmt.Errorf("msgpack: invalid timestamp length %d", n)
+}
+
+func readUintBE(b []byte) uint64 {
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return u
+}
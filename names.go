@@ -0,0 +1,166 @@
+package treasuredata
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NameKind identifies the kind of resource a name is validated for
+type NameKind string
+
+// Resource kinds accepted by ValidateName
+const (
+	NameKindDatabase        NameKind = "database"
+	NameKindTable           NameKind = "table"
+	NameKindAudience        NameKind = "audience"
+	NameKindSegment         NameKind = "segment"
+	NameKindFolder          NameKind = "folder"
+	NameKindWorkflowProject NameKind = "project"
+	NameKindWorkflow        NameKind = "workflow"
+)
+
+// NameKinds lists the kinds accepted by ValidateName
+var NameKinds = []NameKind{
+	NameKindDatabase, NameKindTable, NameKindAudience, NameKindSegment,
+	NameKindFolder, NameKindWorkflowProject, NameKindWorkflow,
+}
+
+const (
+	minTableNameLength = 3
+	maxTableNameLength = 255
+	maxDisplayNameLen  = 255
+)
+
+// reservedWords are Trino reserved keywords. Databases and tables named after
+// them can be created but must be quoted in every query.
+var reservedWords = map[string]bool{
+	"alter": true, "and": true, "as": true, "between": true, "by": true, "case": true,
+	"cast": true, "constraint": true, "create": true, "cross": true, "cube": true,
+	"current_date": true, "current_time": true, "current_timestamp": true, "current_user": true,
+	"deallocate": true, "delete": true, "describe": true, "distinct": true, "drop": true,
+	"else": true, "end": true, "escape": true, "except": true, "execute": true, "exists": true,
+	"extract": true, "false": true, "for": true, "from": true, "full": true, "group": true,
+	"grouping": true, "having": true, "in": true, "inner": true, "insert": true,
+	"intersect": true, "into": true, "is": true, "join": true, "left": true, "like": true,
+	"localtime": true, "localtimestamp": true, "natural": true, "normalize": true, "not": true,
+	"null": true, "on": true, "or": true, "order": true, "outer": true, "prepare": true,
+	"recursive": true, "right": true, "rollup": true, "select": true, "table": true,
+	"then": true, "true": true, "uescape": true, "union": true, "unnest": true, "using": true,
+	"values": true, "when": true, "where": true, "with": true,
+}
+
+// reservedDatabases are system databases that cannot be created
+var reservedDatabases = map[string]bool{
+	"information_schema": true,
+	"sys":                true,
+}
+
+// ValidateName checks name against the constraints of the given resource
+// kind and returns a *ValidationError describing the first violation
+func ValidateName(kind NameKind, name string) error {
+	switch kind {
+	case NameKindDatabase:
+		return ValidateDatabaseName(name)
+	case NameKindTable:
+		return ValidateTableName(name)
+	case NameKindAudience, NameKindSegment, NameKindFolder:
+		return validateDisplayName(kind, name)
+	case NameKindWorkflowProject:
+		return ValidateWorkflowProjectName(name)
+	case NameKindWorkflow:
+		return ValidateWorkflowName(name)
+	}
+	return fmt.Errorf("unknown name kind %q", kind)
+}
+
+// ValidateDatabaseName checks a database name: 3 to 255 characters of
+// lowercase letters, digits and underscores, not a system database or a
+// reserved word
+func ValidateDatabaseName(name string) error {
+	if err := validateIdentifierName(NameKindDatabase, name); err != nil {
+		return err
+	}
+	if reservedDatabases[name] {
+		return nameError(NameKindDatabase, name, "is a reserved system database")
+	}
+	return nil
+}
+
+// ValidateTableName checks a table name: 3 to 255 characters of lowercase
+// letters, digits and underscores, not a reserved word
+func ValidateTableName(name string) error {
+	return validateIdentifierName(NameKindTable, name)
+}
+
+func validateIdentifierName(kind NameKind, name string) error {
+	if name == "" {
+		return nameError(kind, name, "cannot be empty")
+	}
+	if len(name) < minTableNameLength || len(name) > maxTableNameLength {
+		return nameError(kind, name, fmt.Sprintf("must be %d to %d characters long", minTableNameLength, maxTableNameLength))
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_') {
+			return nameError(kind, name, fmt.Sprintf("contains %q; only lowercase letters, digits and underscores are allowed", r))
+		}
+	}
+	if reservedWords[name] {
+		return nameError(kind, name, "is a reserved SQL keyword")
+	}
+	return nil
+}
+
+// validateDisplayName checks an audience, segment or folder name: not blank,
+// at most 255 characters and free of control characters
+func validateDisplayName(kind NameKind, name string) error {
+	if strings.TrimSpace(name) == "" {
+		return nameError(kind, name, "cannot be empty")
+	}
+	if utf8.RuneCountInString(name) > maxDisplayNameLen {
+		return nameError(kind, name, fmt.Sprintf("must be at most %d characters long", maxDisplayNameLen))
+	}
+	if name != strings.TrimSpace(name) {
+		return nameError(kind, name, "cannot start or end with whitespace")
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return nameError(kind, name, "cannot contain control characters")
+		}
+	}
+	return nil
+}
+
+// ValidateWorkflowProjectName checks a workflow project name with the CDP name
+// rules, additionally rejecting characters with meaning in URLs and paths
+func ValidateWorkflowProjectName(name string) error {
+	if err := validateDisplayName(NameKindWorkflowProject, name); err != nil {
+		return err
+	}
+	if i := strings.IndexAny(name, `/\?#&%`); i >= 0 {
+		return nameError(NameKindWorkflowProject, name, fmt.Sprintf("cannot contain %q", name[i]))
+	}
+	return nil
+}
+
+// ValidateWorkflowName checks a workflow name: letters, digits, underscores,
+// hyphens and dots, as used for the .dig file name
+func ValidateWorkflowName(name string) error {
+	if name == "" {
+		return nameError(NameKindWorkflow, name, "cannot be empty")
+	}
+	if len(name) > maxDisplayNameLen {
+		return nameError(NameKindWorkflow, name, fmt.Sprintf("must be at most %d characters long", maxDisplayNameLen))
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' || r == '.') {
+			return nameError(NameKindWorkflow, name, fmt.Sprintf("contains %q; only letters, digits, '_', '-' and '.' are allowed", r))
+		}
+	}
+	return nil
+}
+
+func nameError(kind NameKind, name, message string) *ValidationError {
+	return NewValidationError(string(kind)+" name", name, fmt.Sprintf("%q %s", name, message))
+}
@@ -0,0 +1,74 @@
+package treasuredata
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		kind  NameKind
+		name  string
+		valid bool
+	}{
+		{NameKindDatabase, "sales_2024", true},
+		{NameKindDatabase, "db", false},
+		{NameKindDatabase, "Sales", false},
+		{NameKindDatabase, "sales-db", false},
+		{NameKindDatabase, "information_schema", false},
+		{NameKindTable, "events", true},
+		{NameKindTable, "select", false},
+		{NameKindTable, strings.Repeat("a", 256), false},
+		{NameKindAudience, "Customers (JP)", true},
+		{NameKindSegment, "  ", false},
+		{NameKindSegment, " leading", false},
+		{NameKindFolder, "tab\there", false},
+		{NameKindSegment, strings.Repeat("é", 255), true},
+		{NameKindWorkflowProject, "nightly etl", true},
+		{NameKindWorkflowProject, "etl/nightly", false},
+		{NameKindWorkflowProject, "a&b", false},
+		{NameKindWorkflow, "daily_load.v2", true},
+		{NameKindWorkflow, "daily load", false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateName(tt.kind, tt.name)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateName(%s, %q) = %v, want valid %v", tt.kind, tt.name, err, tt.valid)
+		}
+		var vErr *ValidationError
+		if err != nil && !errors.As(err, &vErr) {
+			t.Errorf("ValidateName(%s, %q) returned %T, want *ValidationError", tt.kind, tt.name, err)
+		}
+	}
+
+	if err := ValidateName("bucket", "name"); err == nil {
+		t.Error("Expected error for an unknown kind")
+	}
+}
+
+func TestCreate_ValidatesNames(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+	client.CDPURL = client.BaseURL
+	ctx := context.Background()
+
+	// Invalid names fail before any request reaches the (empty) mux
+	if _, err := client.Databases.Create(ctx, "Bad-Name"); err == nil {
+		t.Error("Databases.Create: expected validation error")
+	}
+	if _, err := client.Tables.Create(ctx, "sales", "from", "log"); err == nil {
+		t.Error("Tables.Create: expected validation error")
+	}
+	if err := client.Tables.Rename(ctx, "sales", "events", "x"); err == nil {
+		t.Error("Tables.Rename: expected validation error")
+	}
+	if _, err := client.CDP.CreateSegment(ctx, "1", "", "", ""); err == nil {
+		t.Error("CDP.CreateSegment: expected validation error")
+	}
+	if _, err := client.Workflow.CreateProject(ctx, "a/b", nil); err == nil {
+		t.Error("Workflow.CreateProject: expected validation error")
+	}
+}
@@ -0,0 +1,45 @@
+package treasuredata
+
+import "io"
+
+// ProgressFunc reports the progress of a download: bytesDone bytes of
+// bytesTotal, which is -1 when the server did not send the size. It is
+// called after every read from the network, so it should return quickly;
+// throttle any rendering inside it.
+type ProgressFunc func(bytesDone, bytesTotal int64)
+
+// WithProgress calls fn as the body of a download is read. It applies to
+// Results.GetResult, Jobs.DownloadResult, Jobs.DownloadResultParallel and
+// the workflow project downloads. A resumed download starts from the bytes
+// already on disk.
+func WithProgress(fn ProgressFunc) RequestOption {
+	return func(o *requestOptions) {
+		o.progress = fn
+	}
+}
+
+// progressReader calls fn with the running total of the bytes read
+type progressReader struct {
+	io.ReadCloser
+	done  int64
+	total int64
+	fn    ProgressFunc
+}
+
+// withProgress wraps body so that reads are reported to fn, starting from
+// done bytes. A nil fn returns body unchanged.
+func withProgress(body io.ReadCloser, done, total int64, fn ProgressFunc) io.ReadCloser {
+	if fn == nil {
+		return body
+	}
+	return &progressReader{ReadCloser: body, done: done, total: total, fn: fn}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.ReadCloser.Read(b)
+	if n > 0 {
+		p.done += int64(n)
+		p.fn(p.done, p.total)
+	}
+	return n, err
+}
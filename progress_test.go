@@ -0,0 +1,71 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithProgress(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	const result = "id,name\n1,alice\n2,bob\n"
+	mux.HandleFunc("/v3/job/result/123", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") == "bytes=10-" {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes 10-%d/%d", len(result)-1, len(result)))
+			w.Header().Set("Content-Length", fmt.Sprint(len(result)-10))
+			w.WriteHeader(http.StatusPartialContent)
+			fmt.Fprint(w, result[10:])
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(result)))
+		fmt.Fprint(w, result)
+	})
+	mux.HandleFunc("/api/projects/1/archive", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "7")
+		fmt.Fprint(w, "archive")
+	})
+
+	var done, total int64
+	progress := WithProgress(func(bytesDone, bytesTotal int64) {
+		if bytesDone < done {
+			t.Errorf("progress went back from %d to %d", done, bytesDone)
+		}
+		done, total = bytesDone, bytesTotal
+	})
+	check := func(name string, wantTotal int64) {
+		t.Helper()
+		if done != wantTotal || total != wantTotal {
+			t.Errorf("%s: last progress = %d/%d, want %d/%d", name, done, total, wantTotal, wantTotal)
+		}
+		done, total = 0, 0
+	}
+
+	body, err := client.Results.GetResult(context.Background(), "123", &GetResultOptions{Format: ResultFormatCSV}, progress)
+	if err != nil {
+		t.Fatalf("GetResult returned error: %v", err)
+	}
+	io.Copy(io.Discard, body)
+	body.Close()
+	check("GetResult", int64(len(result)))
+
+	// A resumed download counts the bytes already on disk
+	path := filepath.Join(t.TempDir(), "result.csv")
+	os.WriteFile(path+".part", []byte(result[:10]), 0644)
+	os.WriteFile(path+".checkpoint", []byte(`{"job_id":"123","format":"csv"}`), 0644)
+	if _, err := client.Jobs.DownloadResult(context.Background(), "123", path, WithResultFormat(ResultFormatCSV), WithResume(), progress); err != nil {
+		t.Fatalf("DownloadResult returned error: %v", err)
+	}
+	check("DownloadResult", int64(len(result)))
+
+	client.WorkflowURL = client.BaseURL
+	if _, err := client.Workflow.DownloadProject(context.Background(), "1", progress); err != nil {
+		t.Fatalf("DownloadProject returned error: %v", err)
+	}
+	check("DownloadProject", 7)
+}
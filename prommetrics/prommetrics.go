@@ -0,0 +1,98 @@
+// Package prommetrics reports Treasure Data client requests to Prometheus.
+//
+//	metrics := prommetrics.New(prometheus.DefaultRegisterer)
+//	client, err := td.NewClient(apiKey, td.WithMetrics(metrics))
+package prommetrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// Metrics implements td.ClientMetrics with Prometheus collectors labelled by
+// service and method. Paths are not used as labels because they embed
+// database, table and job identifiers.
+type Metrics struct {
+	inFlight *prometheus.GaugeVec
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+var _ td.ClientMetrics = (*Metrics)(nil)
+
+// Options customizes the collectors created by NewWithOptions
+type Options struct {
+	// Namespace prefixes every metric name (default "treasuredata")
+	Namespace string
+	// Buckets are the latency histogram buckets in seconds (default prometheus.DefBuckets)
+	Buckets []float64
+}
+
+// New creates the collectors with default options and registers them with reg.
+// A nil reg leaves them unregistered.
+func New(reg prometheus.Registerer) *Metrics {
+	m, err := NewWithOptions(reg, Options{})
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// NewWithOptions creates the collectors and registers them with reg, returning
+// an error if registration fails
+func NewWithOptions(reg prometheus.Registerer, opts Options) (*Metrics, error) {
+	if opts.Namespace == "" {
+		opts.Namespace = "treasuredata"
+	}
+	if opts.Buckets == nil {
+		opts.Buckets = prometheus.DefBuckets
+	}
+
+	m := &Metrics{
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: opts.Namespace,
+			Subsystem: "client",
+			Name:      "requests_in_flight",
+			Help:      "Treasure Data API requests currently in flight.",
+		}, []string{"service"}),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: opts.Namespace,
+			Subsystem: "client",
+			Name:      "requests_total",
+			Help:      "Treasure Data API requests by service, method and status code (0 for transport errors).",
+		}, []string{"service", "method", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: opts.Namespace,
+			Subsystem: "client",
+			Name:      "request_duration_seconds",
+			Help:      "Treasure Data API request latency until response headers are received.",
+			Buckets:   opts.Buckets,
+		}, []string{"service", "method"}),
+	}
+
+	if reg != nil {
+		for _, c := range []prometheus.Collector{m.inFlight, m.requests, m.duration} {
+			if err := reg.Register(c); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return m, nil
+}
+
+// OnRequestStart increments the in-flight gauge
+func (m *Metrics) OnRequestStart(ctx context.Context, info td.RequestInfo) {
+	m.inFlight.WithLabelValues(info.Service).Inc()
+}
+
+// OnRequestEnd records the request outcome and latency
+func (m *Metrics) OnRequestEnd(ctx context.Context, info td.RequestInfo, statusCode int, duration time.Duration, err error) {
+	m.inFlight.WithLabelValues(info.Service).Dec()
+	m.requests.WithLabelValues(info.Service, info.Method, strconv.Itoa(statusCode)).Inc()
+	m.duration.WithLabelValues(info.Service, info.Method).Observe(duration.Seconds())
+}
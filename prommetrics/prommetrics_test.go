@@ -0,0 +1,44 @@
+package prommetrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := New(reg)
+
+	ctx := context.Background()
+	info := td.RequestInfo{Service: td.MetricsServiceTD, Method: "GET", Path: "/v3/job/list"}
+	m.OnRequestStart(ctx, info)
+	if got := testutil.ToFloat64(m.inFlight.WithLabelValues("td")); got != 1 {
+		t.Errorf("In-flight = %v, want 1", got)
+	}
+	m.OnRequestEnd(ctx, info, 200, 50*time.Millisecond, nil)
+	m.OnRequestStart(ctx, info)
+	m.OnRequestEnd(ctx, info, 0, time.Second, context.DeadlineExceeded)
+
+	if got := testutil.ToFloat64(m.inFlight.WithLabelValues("td")); got != 0 {
+		t.Errorf("In-flight = %v, want 0", got)
+	}
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("td", "GET", "200")); got != 1 {
+		t.Errorf("200 requests = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("td", "GET", "0")); got != 1 {
+		t.Errorf("Failed requests = %v, want 1", got)
+	}
+	if got := testutil.CollectAndCount(m.duration); got != 1 {
+		t.Errorf("Duration series = %d, want 1", got)
+	}
+
+	if _, err := NewWithOptions(reg, Options{}); err == nil {
+		t.Error("Expected error registering duplicate collectors")
+	}
+}
@@ -22,16 +22,34 @@ const (
 	QueryTypePresto QueryType = "presto"
 )
 
+// Hive engine versions accepted in IssueQueryOptions.EngineVersion
+const (
+	// HiveEngineVersionStable is the default Hive version of the account
+	HiveEngineVersionStable = "stable"
+	// HiveEngineVersionExperimental is the next Hive version, for testing
+	// queries before it becomes stable
+	HiveEngineVersionExperimental = "experimental"
+)
+
 // IssueQueryOptions represents options for issuing a query
 type IssueQueryOptions struct {
-	Query         string `json:"query"`
-	Priority      int    `json:"priority,omitempty"`
-	RetryLimit    int    `json:"retry_limit,omitempty"`
-	Result        string `json:"result,omitempty"`
-	DomainKey     string `json:"domain_key,omitempty"`
-	PoolName      string `json:"pool_name,omitempty"`
-	Type          string `json:"type,omitempty"`
+	Query      string `json:"query"`
+	Priority   int    `json:"priority,omitempty"`
+	RetryLimit int    `json:"retry_limit,omitempty"`
+	Result     string `json:"result,omitempty"`
+	// DomainKey makes the submission idempotent: the API rejects a second
+	// job with the same key, and Issue then returns the existing job instead
+	DomainKey string `json:"domain_key,omitempty"`
+	// PoolName is the resource pool the job runs in. Both Hive and Trino
+	// use the account's default pool when it is empty.
+	PoolName string `json:"pool_name,omitempty"`
+	Type     string `json:"type,omitempty"`
+	// EngineVersion selects the engine version, such as
+	// HiveEngineVersionExperimental for Hive. Empty uses the default.
 	EngineVersion string `json:"engine_version,omitempty"`
+
+	// resultOutput is the destination given to SetResultOutput
+	resultOutput ResultOutput
 }
 
 // IssueQueryResponse represents the response from issuing a query
@@ -39,10 +57,20 @@ type IssueQueryResponse struct {
 	Job      string `json:"job"`
 	JobID    string `json:"job_id"`
 	Database string `json:"database"`
+	// Existing reports that a job with the same domain key had already been
+	// issued and JobID is that job
+	Existing bool `json:"-"`
 }
 
 // Issue submits a new query job
-func (s *QueriesService) Issue(ctx context.Context, queryType QueryType, database string, opts *IssueQueryOptions) (*IssueQueryResponse, error) {
+func (s *QueriesService) Issue(ctx context.Context, queryType QueryType, database string, opts *IssueQueryOptions, reqOpts ...RequestOption) (*IssueQueryResponse, error) {
+	if err := s.client.checkQueryPolicies(ctx, queryType, database, opts); err != nil {
+		return nil, err
+	}
+	if err := s.client.prepareResultOutput(ctx, opts); err != nil {
+		return nil, err
+	}
+
 	u := fmt.Sprintf("%s/job/issue/%s/%s", apiVersion, queryType, database)
 
 	req, err := s.client.NewRequest("POST", u, opts)
@@ -51,10 +79,24 @@ func (s *QueriesService) Issue(ctx context.Context, queryType QueryType, databas
 	}
 
 	var resp IssueQueryResponse
-	_, err = s.client.Do(ctx, req, &resp)
+	_, err = s.client.Do(ctx, req, &resp, reqOpts...)
 	if err != nil {
+		if opts != nil && opts.DomainKey != "" && IsConflict(err) {
+			return s.existingJob(ctx, database, opts.DomainKey, err, reqOpts)
+		}
 		return nil, err
 	}
 
 	return &resp, nil
 }
+
+// existingJob looks up the job that holds domainKey after the API rejected a
+// duplicate submission with conflict, e.g. when a retried submission had
+// already succeeded
+func (s *QueriesService) existingJob(ctx context.Context, database, domainKey string, conflict error, reqOpts []RequestOption) (*IssueQueryResponse, error) {
+	status, err := s.client.Jobs.StatusByDomainKey(ctx, domainKey, reqOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w; looking up the job with domain key %q failed: %v", conflict, domainKey, err)
+	}
+	return &IssueQueryResponse{Job: status.JobID, JobID: status.JobID, Database: database, Existing: true}, nil
+}
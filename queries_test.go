@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -409,3 +410,62 @@ func ExampleQueriesService_Issue_with_retry() {
 
 	fmt.Printf("Query with retry submitted: Job ID %s\n", resp.JobID)
 }
+
+func TestQueriesService_Issue_DuplicateDomainKey(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/issue/trino/db", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"error": "Domain key has already been taken", "severity": "error"}`)
+	})
+	mux.HandleFunc("/v3/job/status_by_domain_key/nightly-2025-01-01", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"job_id": "42", "status": "running"}`)
+	})
+
+	job, err := client.Queries.Issue(context.Background(), QueryTypeTrino, "db", &IssueQueryOptions{
+		Query:     "SELECT 1",
+		DomainKey: "nightly-2025-01-01",
+	})
+	if err != nil {
+		t.Fatalf("Issue returned error: %v", err)
+	}
+	if job.JobID != "42" || !job.Existing {
+		t.Errorf("Issue = %+v, want the existing job 42", job)
+	}
+}
+
+func TestQueriesService_Issue_ConflictWithoutDomainKey(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/issue/trino/db", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"error": "conflict"}`)
+	})
+
+	_, err := client.Queries.Issue(context.Background(), QueryTypeTrino, "db", &IssueQueryOptions{Query: "SELECT 1"})
+	if !IsConflict(err) {
+		t.Errorf("err = %v, want a conflict", err)
+	}
+}
+
+func TestQueriesService_Issue_DomainKeyLookupFails(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/issue/trino/db", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"error": "Domain key has already been taken"}`)
+	})
+	mux.HandleFunc("/v3/job/status_by_domain_key/key", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error": "not found"}`)
+	})
+
+	_, err := client.Queries.Issue(context.Background(), QueryTypeTrino, "db", &IssueQueryOptions{Query: "SELECT 1", DomainKey: "key"})
+	if !IsConflict(err) || !strings.Contains(err.Error(), `domain key "key"`) {
+		t.Errorf("err = %v, want the conflict with the failed lookup", err)
+	}
+}
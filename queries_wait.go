@@ -0,0 +1,103 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// SubmitRequest describes a query for SubmitAndWait
+type SubmitRequest struct {
+	// Type is the query engine. Defaults to QueryTypeTrino.
+	Type     QueryType
+	Database string
+	// Options carries the query text and any priority, pool or result output
+	Options IssueQueryOptions
+	// Wait controls polling. Its OnStatus reports progress while the job runs.
+	Wait WaitOptions
+	// OnSubmit, if set, is called with the job ID once the job is issued,
+	// before waiting starts
+	OnSubmit func(jobID string)
+}
+
+// QueryResult is a finished query job and a handle to its results
+type QueryResult struct {
+	Job *Job
+	// Existing reports that the submission's domain key was taken and Job is
+	// the job issued with it before
+	Existing bool
+
+	client *Client
+}
+
+// State returns the final state of the job
+func (r *QueryResult) State() JobState {
+	return JobState(r.Job.Status)
+}
+
+// Open streams the job's results; see ResultsService.GetResult
+func (r *QueryResult) Open(ctx context.Context, opts *GetResultOptions) (io.ReadCloser, error) {
+	return r.client.Results.GetResult(ctx, r.Job.JobID, opts)
+}
+
+// JSONL returns a scanner over the job's results in JSONL format
+func (r *QueryResult) JSONL(ctx context.Context) (*JSONLScanner, error) {
+	return r.client.Results.GetResultJSONL(ctx, r.Job.JobID)
+}
+
+// JobFailedError is returned by SubmitAndWait when the job finishes without
+// succeeding
+type JobFailedError struct {
+	JobID string
+	State JobState
+	// Message is the job's debug stderr, if any
+	Message string
+}
+
+func (e *JobFailedError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("job %s finished with state %s", e.JobID, e.State)
+	}
+	return fmt.Sprintf("job %s finished with state %s: %s", e.JobID, e.State, e.Message)
+}
+
+// SubmitAndWait issues a query, waits for the job to finish and returns a
+// handle to its results. If the job fails or is killed, the handle is
+// returned together with a *JobFailedError. If waiting stops early because
+// of ctx or Wait.MaxWait, the error wraps the context error and the job keeps
+// running on the server; OnSubmit is the way to learn its ID. With
+// Options.DomainKey, submitting again waits for the job issued the first time
+// instead of running the query twice.
+func (s *QueriesService) SubmitAndWait(ctx context.Context, req SubmitRequest) (*QueryResult, error) {
+	queryType := req.Type
+	if queryType == "" {
+		queryType = QueryTypeTrino
+	}
+
+	opts := req.Options
+	issued, err := s.Issue(ctx, queryType, req.Database, &opts)
+	if err != nil {
+		return nil, err
+	}
+	if req.OnSubmit != nil {
+		req.OnSubmit(issued.JobID)
+	}
+
+	if _, err := s.client.Jobs.WaitForCompletion(ctx, issued.JobID, req.Wait); err != nil {
+		return nil, err
+	}
+
+	job, err := s.client.Jobs.Get(ctx, issued.JobID)
+	if err != nil {
+		return nil, err
+	}
+	result := &QueryResult{Job: job, Existing: issued.Existing, client: s.client}
+	if result.State() != JobStateSuccess {
+		failed := &JobFailedError{JobID: job.JobID, State: result.State()}
+		if job.Debug != nil {
+			failed.Message = job.Debug.Stderr
+		}
+		return result, failed
+	}
+	return result, nil
+}
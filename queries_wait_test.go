@@ -0,0 +1,127 @@
+package treasuredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestQueriesService_SubmitAndWait(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/issue/trino/db", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, `{"job_id": "123", "database": "db"}`)
+	})
+	states := []string{"running", "success"}
+	calls := 0
+	mux.HandleFunc("/v3/job/status/123", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"job_id": "123", "status": %q}`, states[calls])
+		calls++
+	})
+	mux.HandleFunc("/v3/job/show/123", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"job_id": "123", "status": "success", "num_records": 1}`)
+	})
+	mux.HandleFunc("/v3/job/result/123", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"n": 1}`+"\n")
+	})
+
+	var submitted string
+	progress := 0
+	result, err := client.Queries.SubmitAndWait(context.Background(), SubmitRequest{
+		Database: "db",
+		Options:  IssueQueryOptions{Query: "SELECT 1 AS n"},
+		Wait: WaitOptions{
+			PollInterval: time.Millisecond,
+			OnStatus:     func(*JobStatus) { progress++ },
+		},
+		OnSubmit: func(jobID string) { submitted = jobID },
+	})
+	if err != nil {
+		t.Fatalf("SubmitAndWait returned error: %v", err)
+	}
+	if submitted != "123" || progress != 2 {
+		t.Errorf("submitted = %q, progress = %d", submitted, progress)
+	}
+	if result.State() != JobStateSuccess || result.Job.NumRecords != 1 {
+		t.Errorf("result = %+v", result.Job)
+	}
+
+	scanner, err := result.JSONL(context.Background())
+	if err != nil {
+		t.Fatalf("JSONL returned error: %v", err)
+	}
+	defer scanner.Close()
+	var row map[string]interface{}
+	if !scanner.Scan() {
+		t.Fatalf("no rows, err = %v", scanner.Err())
+	}
+	if err := scanner.Decode(&row); err != nil || row["n"] != float64(1) {
+		t.Errorf("row = %v, err = %v", row, err)
+	}
+}
+
+func TestQueriesService_SubmitAndWait_Failed(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/issue/hive/db", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"job_id": "123"}`)
+	})
+	mux.HandleFunc("/v3/job/status/123", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"job_id": "123", "status": "error"}`)
+	})
+	mux.HandleFunc("/v3/job/show/123", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"job_id": "123", "status": "error", "debug": {"stderr": "syntax error"}}`)
+	})
+
+	result, err := client.Queries.SubmitAndWait(context.Background(), SubmitRequest{
+		Type:     QueryTypeHive,
+		Database: "db",
+		Options:  IssueQueryOptions{Query: "SELEC 1"},
+	})
+	var failed *JobFailedError
+	if !errors.As(err, &failed) || failed.State != JobStateError || failed.Message != "syntax error" {
+		t.Fatalf("err = %v, want a *JobFailedError", err)
+	}
+	if result == nil || result.Job.JobID != "123" {
+		t.Errorf("result = %+v, want the failed job", result)
+	}
+}
+
+func TestQueriesService_SubmitAndWait_ExistingDomainKey(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/issue/trino/db", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"error": "Domain key has already been taken"}`)
+	})
+	mux.HandleFunc("/v3/job/status_by_domain_key/report-2025-01-01", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"job_id": "55", "status": "success"}`)
+	})
+	mux.HandleFunc("/v3/job/status/55", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"job_id": "55", "status": "success"}`)
+	})
+	mux.HandleFunc("/v3/job/show/55", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"job_id": "55", "status": "success"}`)
+	})
+
+	var submitted string
+	result, err := client.Queries.SubmitAndWait(context.Background(), SubmitRequest{
+		Database: "db",
+		Options:  IssueQueryOptions{Query: "SELECT 1", DomainKey: "report-2025-01-01"},
+		Wait:     WaitOptions{PollInterval: time.Millisecond},
+		OnSubmit: func(jobID string) { submitted = jobID },
+	})
+	if err != nil {
+		t.Fatalf("SubmitAndWait returned error: %v", err)
+	}
+	if submitted != "55" || result.Job.JobID != "55" || !result.Existing {
+		t.Errorf("submitted = %q, result = %+v, existing = %t", submitted, result.Job, result.Existing)
+	}
+}
@@ -0,0 +1,253 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timePartitionSeconds is the span of a time partition of a TD table
+const timePartitionSeconds = 3600
+
+// QueryEstimate is the scan a Trino query is expected to do, read from its
+// IO plan
+type QueryEstimate struct {
+	// JobID is the EXPLAIN job the estimate came from
+	JobID  string              `json:"job_id,omitempty"`
+	Tables []TableScanEstimate `json:"tables"`
+	// Bytes and Rows add up the tables with statistics; see Unknown
+	Bytes int64 `json:"bytes"`
+	Rows  int64 `json:"rows"`
+	// Partitions adds up the time partitions of the tables that are not full
+	// scans
+	Partitions int `json:"partitions"`
+	// Unknown reports that the planner had no statistics for some table, so
+	// Bytes and Rows are a lower bound
+	Unknown bool `json:"unknown,omitempty"`
+}
+
+// FullScans returns the tables read without a bounded time range
+func (e *QueryEstimate) FullScans() []TableScanEstimate {
+	var full []TableScanEstimate
+	for _, table := range e.Tables {
+		if table.FullScan {
+			full = append(full, table)
+		}
+	}
+	return full
+}
+
+// TableScanEstimate is the expected scan of one input table
+type TableScanEstimate struct {
+	Database string `json:"database"`
+	Table    string `json:"table"`
+	// Rows and Bytes are the planner's estimate, valid when Known
+	Rows  int64 `json:"rows"`
+	Bytes int64 `json:"bytes"`
+	Known bool  `json:"known"`
+	// TimeRanges are the ranges of the time column the query reads
+	TimeRanges []TimeRange `json:"time_ranges,omitempty"`
+	// Partitions is the number of hourly time partitions in TimeRanges.
+	// It is zero for a full scan.
+	Partitions int `json:"partitions"`
+	// FullScan reports that the time column is not bounded on both sides,
+	// so every partition is read
+	FullScan bool `json:"full_scan"`
+}
+
+// TimeRange is a range of the time column; a nil bound is unbounded
+type TimeRange struct {
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+}
+
+// EstimateScan asks Trino for the IO plan of query with
+// EXPLAIN (TYPE IO, FORMAT JSON) and returns the estimated scan. The EXPLAIN
+// runs as a job but reads no data. Hive has no equivalent.
+func (s *QueriesService) EstimateScan(ctx context.Context, database, query string, wait WaitOptions) (*QueryEstimate, error) {
+	query = strings.TrimRight(strings.TrimSpace(query), "; \t\n")
+	if query == "" {
+		return nil, NewValidationError("query", query, "cannot be empty")
+	}
+	if strings.HasPrefix(strings.ToUpper(query), "EXPLAIN") {
+		return nil, NewValidationError("query", query, "is already an EXPLAIN")
+	}
+
+	result, err := s.SubmitAndWait(ctx, SubmitRequest{
+		Type:     QueryTypeTrino,
+		Database: database,
+		Options:  IssueQueryOptions{Query: "EXPLAIN (TYPE IO, FORMAT JSON) " + query},
+		Wait:     wait,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := result.Decoder(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("job %s returned no IO plan", result.Job.JobID)
+	}
+	var plan string
+	for _, value := range rows.Row() {
+		plan, _ = value.(string)
+	}
+
+	estimate, err := ParseIOPlan([]byte(plan))
+	if err != nil {
+		return nil, fmt.Errorf("job %s: %w", result.Job.JobID, err)
+	}
+	estimate.JobID = result.Job.JobID
+	return estimate, nil
+}
+
+// ioPlan is the JSON of EXPLAIN (TYPE IO, FORMAT JSON)
+type ioPlan struct {
+	InputTableColumnInfos []struct {
+		Table struct {
+			SchemaTable struct {
+				Schema string `json:"schema"`
+				Table  string `json:"table"`
+			} `json:"schemaTable"`
+		} `json:"table"`
+		Constraint *struct {
+			None              bool                 `json:"none"`
+			ColumnConstraints []ioColumnConstraint `json:"columnConstraints"`
+		} `json:"constraint"`
+		// ColumnConstraints is where older Trino versions put constraints
+		ColumnConstraints []ioColumnConstraint `json:"columnConstraints"`
+		Estimate          *struct {
+			OutputRowCount    *float64 `json:"outputRowCount"`
+			OutputSizeInBytes *float64 `json:"outputSizeInBytes"`
+		} `json:"estimate"`
+	} `json:"inputTableColumnInfos"`
+}
+
+type ioColumnConstraint struct {
+	ColumnName string `json:"columnName"`
+	Domain     struct {
+		Ranges []struct {
+			Low  ioMarker `json:"low"`
+			High ioMarker `json:"high"`
+		} `json:"ranges"`
+	} `json:"domain"`
+}
+
+type ioMarker struct {
+	Value *string `json:"value"`
+	Bound string  `json:"bound"`
+}
+
+// nonFiniteNumber matches the NaN and Infinity values Trino writes for
+// missing statistics, which are not valid JSON
+var nonFiniteNumber = regexp.MustCompile(`:\s*-?(NaN|Infinity)\b`)
+
+// ParseIOPlan reads the output of EXPLAIN (TYPE IO, FORMAT JSON)
+func ParseIOPlan(data []byte) (*QueryEstimate, error) {
+	data = nonFiniteNumber.ReplaceAll(data, []byte(": null"))
+	var plan ioPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("invalid IO plan: %w", err)
+	}
+
+	estimate := &QueryEstimate{Tables: []TableScanEstimate{}}
+	for _, info := range plan.InputTableColumnInfos {
+		table := TableScanEstimate{
+			Database: info.Table.SchemaTable.Schema,
+			Table:    info.Table.SchemaTable.Table,
+		}
+		if e := info.Estimate; e != nil && e.OutputRowCount != nil && e.OutputSizeInBytes != nil {
+			table.Rows = int64(*e.OutputRowCount)
+			table.Bytes = int64(*e.OutputSizeInBytes)
+			table.Known = true
+		}
+
+		constraints := info.ColumnConstraints
+		empty := false
+		if info.Constraint != nil {
+			constraints = append(constraints, info.Constraint.ColumnConstraints...)
+			empty = info.Constraint.None
+		}
+		if !empty {
+			if err := table.setTimeRanges(constraints); err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", table.Database, table.Table, err)
+			}
+		}
+
+		if table.Known {
+			estimate.Bytes += table.Bytes
+			estimate.Rows += table.Rows
+		} else {
+			estimate.Unknown = true
+		}
+		estimate.Partitions += table.Partitions
+		estimate.Tables = append(estimate.Tables, table)
+	}
+	return estimate, nil
+}
+
+// setTimeRanges reads the constraint on the time column. A table without one
+// is a full scan.
+func (t *TableScanEstimate) setTimeRanges(constraints []ioColumnConstraint) error {
+	t.FullScan = true
+	for _, constraint := range constraints {
+		if constraint.ColumnName != "time" {
+			continue
+		}
+		t.FullScan = false
+		for _, r := range constraint.Domain.Ranges {
+			var timeRange TimeRange
+			from, err := r.Low.unix()
+			if err != nil {
+				return err
+			}
+			to, err := r.High.unix()
+			if err != nil {
+				return err
+			}
+			if from == nil || to == nil {
+				t.FullScan = true
+			}
+			if from != nil {
+				tm := time.Unix(*from, 0).UTC()
+				timeRange.From = &tm
+			}
+			if to != nil {
+				tm := time.Unix(*to, 0).UTC()
+				timeRange.To = &tm
+			}
+			if from != nil && to != nil {
+				first := *from - *from%timePartitionSeconds
+				t.Partitions += int(math.Ceil(float64(*to-first) / timePartitionSeconds))
+			}
+			t.TimeRanges = append(t.TimeRanges, timeRange)
+		}
+	}
+	if t.FullScan {
+		t.Partitions = 0
+	}
+	return nil
+}
+
+// unix returns the marker's time in Unix seconds, or nil when unbounded
+func (m ioMarker) unix() (*int64, error) {
+	if m.Value == nil {
+		return nil, nil
+	}
+	seconds, err := strconv.ParseInt(*m.Value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time bound %q", *m.Value)
+	}
+	return &seconds, nil
+}
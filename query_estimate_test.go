@@ -0,0 +1,128 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testIOPlan = `{
+  "inputTableColumnInfos" : [ {
+    "table" : {"catalog" : "td", "schemaTable" : {"schema" : "sales", "table" : "orders"}},
+    "constraint" : {"none" : false, "columnConstraints" : [ {
+      "columnName" : "time", "type" : "bigint",
+      "domain" : {"nullsAllowed" : false, "ranges" : [ {
+        "low" : {"value" : "1704067200", "bound" : "EXACTLY"},
+        "high" : {"value" : "1704153600", "bound" : "BELOW"}
+      } ]}
+    } ]},
+    "estimate" : {"outputRowCount" : 5000.0, "outputSizeInBytes" : 1048576.0, "cpuCost" : 1048576.0, "maxMemory" : 0.0, "networkCost" : 0.0}
+  }, {
+    "table" : {"catalog" : "td", "schemaTable" : {"schema" : "sales", "table" : "customers"}},
+    "constraint" : {"none" : false, "columnConstraints" : [ ]},
+    "estimate" : {"outputRowCount" : NaN, "outputSizeInBytes" : NaN, "cpuCost" : NaN, "maxMemory" : 0.0, "networkCost" : 0.0}
+  } ]
+}`
+
+func TestParseIOPlan(t *testing.T) {
+	estimate, err := ParseIOPlan([]byte(testIOPlan))
+	if err != nil {
+		t.Fatalf("ParseIOPlan returned error: %v", err)
+	}
+	if len(estimate.Tables) != 2 {
+		t.Fatalf("tables = %+v", estimate.Tables)
+	}
+
+	orders := estimate.Tables[0]
+	if orders.Database != "sales" || orders.Table != "orders" || !orders.Known || orders.Rows != 5000 || orders.Bytes != 1048576 {
+		t.Errorf("orders = %+v", orders)
+	}
+	if orders.FullScan || orders.Partitions != 24 || len(orders.TimeRanges) != 1 {
+		t.Errorf("orders scan = %+v, want 24 partitions", orders)
+	}
+	if from := orders.TimeRanges[0].From; from == nil || !from.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("orders from = %v", from)
+	}
+
+	customers := estimate.Tables[1]
+	if customers.Known || !customers.FullScan || customers.Partitions != 0 {
+		t.Errorf("customers = %+v, want an unknown full scan", customers)
+	}
+
+	if estimate.Bytes != 1048576 || estimate.Rows != 5000 || estimate.Partitions != 24 || !estimate.Unknown {
+		t.Errorf("estimate = %+v", estimate)
+	}
+	if full := estimate.FullScans(); len(full) != 1 || full[0].Table != "customers" {
+		t.Errorf("FullScans = %+v", full)
+	}
+}
+
+func TestParseIOPlan_OpenRange(t *testing.T) {
+	plan := `{"inputTableColumnInfos": [{
+		"table": {"schemaTable": {"schema": "sales", "table": "orders"}},
+		"constraint": {"none": false, "columnConstraints": [{"columnName": "time", "domain": {"ranges": [
+			{"low": {"value": "1704067200", "bound": "ABOVE"}, "high": {"bound": "BELOW"}}
+		]}}]}
+	}]}`
+	estimate, err := ParseIOPlan([]byte(plan))
+	if err != nil {
+		t.Fatalf("ParseIOPlan returned error: %v", err)
+	}
+	table := estimate.Tables[0]
+	if !table.FullScan || table.Partitions != 0 || table.TimeRanges[0].To != nil {
+		t.Errorf("table = %+v, want a full scan with an open upper bound", table)
+	}
+}
+
+func TestParseIOPlan_Invalid(t *testing.T) {
+	if _, err := ParseIOPlan([]byte("Fragment 0 [SINGLE]")); err == nil {
+		t.Error("expected an error for a text plan")
+	}
+}
+
+func TestQueriesService_EstimateScan(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/issue/trino/sales", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if query, _ := body["query"].(string); query != "EXPLAIN (TYPE IO, FORMAT JSON) SELECT * FROM orders" {
+			t.Errorf("query = %q", query)
+		}
+		fmt.Fprint(w, `{"job_id": "77", "database": "sales"}`)
+	})
+	mux.HandleFunc("/v3/job/status/77", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"job_id": "77", "status": "success"}`)
+	})
+	mux.HandleFunc("/v3/job/show/77", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"job_id": "77", "status": "success", "hive_result_schema": "[[\"Query Plan\", \"varchar\"]]"}`)
+	})
+	mux.HandleFunc("/v3/job/result/77", func(w http.ResponseWriter, r *http.Request) {
+		row, _ := json.Marshal([]string{testIOPlan})
+		fmt.Fprintf(w, "%s\n", row)
+	})
+
+	estimate, err := client.Queries.EstimateScan(context.Background(), "sales", "SELECT * FROM orders;\n", WaitOptions{PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("EstimateScan returned error: %v", err)
+	}
+	if estimate.JobID != "77" || len(estimate.Tables) != 2 || estimate.Bytes != 1048576 {
+		t.Errorf("estimate = %+v", estimate)
+	}
+}
+
+func TestQueriesService_EstimateScan_Explain(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Queries.EstimateScan(context.Background(), "sales", "explain select 1", WaitOptions{})
+	if err == nil || !strings.Contains(err.Error(), "EXPLAIN") {
+		t.Errorf("err = %v, want a validation error", err)
+	}
+}
@@ -0,0 +1,127 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Built-in priority preset names
+const (
+	PresetInteractive = "interactive"
+	PresetBatch       = "batch"
+	PresetBackfill    = "backfill"
+)
+
+// Job priorities accepted by the API
+const (
+	MinJobPriority = -2
+	MaxJobPriority = 2
+)
+
+// PriorityPreset is a named combination of job priority, retry limit and
+// resource pool
+type PriorityPreset struct {
+	Priority   int    `toml:"priority" json:"priority"`
+	RetryLimit int    `toml:"retry_limit" json:"retry_limit,omitempty"`
+	PoolName   string `toml:"pool_name" json:"pool_name,omitempty"`
+}
+
+// DefaultPriorityPresets returns the built-in presets: interactive queries
+// run ahead of batch work, and backfills yield to both
+func DefaultPriorityPresets() map[string]PriorityPreset {
+	return map[string]PriorityPreset{
+		PresetInteractive: {Priority: 1},
+		PresetBatch:       {Priority: 0, RetryLimit: 1},
+		PresetBackfill:    {Priority: -1, RetryLimit: 3},
+	}
+}
+
+// ApplyPreset sets the priority, retry limit and pool from a preset. An empty
+// preset pool leaves PoolName unchanged.
+func (o *IssueQueryOptions) ApplyPreset(p PriorityPreset) {
+	o.Priority = p.Priority
+	o.RetryLimit = p.RetryLimit
+	if p.PoolName != "" {
+		o.PoolName = p.PoolName
+	}
+}
+
+// QueryPolicy inspects a query before it is issued and returns an error to
+// reject it. Policies may also adjust opts.
+type QueryPolicy func(ctx context.Context, queryType QueryType, database string, opts *IssueQueryOptions) error
+
+// PolicyViolationError is returned when a query policy rejects a query
+type PolicyViolationError struct {
+	Policy string
+	Reason string
+}
+
+func (e *PolicyViolationError) Error() string {
+	return fmt.Sprintf("query rejected by %s policy: %s", e.Policy, e.Reason)
+}
+
+// WithQueryPolicy adds policies that every Queries.Issue call must pass.
+// Policies run in order and the first error stops the query from being sent.
+func WithQueryPolicy(policies ...QueryPolicy) ClientOption {
+	return func(c *Client) error {
+		c.queryPolicies = append(c.queryPolicies, policies...)
+		return nil
+	}
+}
+
+// MaxPriorityPolicy rejects queries with a priority above max, e.g. to keep
+// priority 2 for administrators
+func MaxPriorityPolicy(max int) QueryPolicy {
+	return func(ctx context.Context, queryType QueryType, database string, opts *IssueQueryOptions) error {
+		if opts.Priority > max {
+			return &PolicyViolationError{
+				Policy: "max-priority",
+				Reason: fmt.Sprintf("priority %d exceeds the allowed maximum of %d", opts.Priority, max),
+			}
+		}
+		return nil
+	}
+}
+
+// AllowedPoolsPolicy rejects queries that name a resource pool outside pools,
+// so with no pools every named pool is rejected. Queries without a pool are
+// allowed.
+func AllowedPoolsPolicy(pools ...string) QueryPolicy {
+	return func(ctx context.Context, queryType QueryType, database string, opts *IssueQueryOptions) error {
+		if opts.PoolName == "" {
+			return nil
+		}
+		for _, pool := range pools {
+			if opts.PoolName == pool {
+				return nil
+			}
+		}
+		if len(pools) == 0 {
+			return &PolicyViolationError{
+				Policy: "allowed-pools",
+				Reason: fmt.Sprintf("pool %q is not allowed; no pools are", opts.PoolName),
+			}
+		}
+		return &PolicyViolationError{
+			Policy: "allowed-pools",
+			Reason: fmt.Sprintf("pool %q is not one of %s", opts.PoolName, strings.Join(pools, ", ")),
+		}
+	}
+}
+
+// checkQueryPolicies validates the priority range and runs the configured policies
+func (c *Client) checkQueryPolicies(ctx context.Context, queryType QueryType, database string, opts *IssueQueryOptions) error {
+	if opts == nil {
+		return nil
+	}
+	if opts.Priority < MinJobPriority || opts.Priority > MaxJobPriority {
+		return fmt.Errorf("priority %d is out of range [%d, %d]", opts.Priority, MinJobPriority, MaxJobPriority)
+	}
+	for _, policy := range c.queryPolicies {
+		if err := policy(ctx, queryType, database, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
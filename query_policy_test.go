@@ -0,0 +1,69 @@
+package treasuredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestIssueQueryOptions_ApplyPreset(t *testing.T) {
+	opts := &IssueQueryOptions{Query: "SELECT 1", PoolName: "default"}
+	opts.ApplyPreset(DefaultPriorityPresets()[PresetBackfill])
+	if opts.Priority != -1 || opts.RetryLimit != 3 || opts.PoolName != "default" {
+		t.Errorf("After backfill preset = %+v", opts)
+	}
+
+	opts.ApplyPreset(PriorityPreset{Priority: 1, PoolName: "adhoc"})
+	if opts.Priority != 1 || opts.RetryLimit != 0 || opts.PoolName != "adhoc" {
+		t.Errorf("After custom preset = %+v", opts)
+	}
+}
+
+func TestQueriesService_Issue_Policy(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	if err := WithQueryPolicy(MaxPriorityPolicy(1), AllowedPoolsPolicy("batch"))(client); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	mux.HandleFunc("/v3/job/issue/trino/db", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"job_id": "1"}`)
+	})
+
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		opts    IssueQueryOptions
+		wantErr bool
+	}{
+		{"allowed", IssueQueryOptions{Query: "SELECT 1", Priority: 1, PoolName: "batch"}, false},
+		{"no pool", IssueQueryOptions{Query: "SELECT 1"}, false},
+		{"priority too high", IssueQueryOptions{Query: "SELECT 1", Priority: 2}, true},
+		{"pool not allowed", IssueQueryOptions{Query: "SELECT 1", PoolName: "adhoc"}, true},
+		{"priority out of range", IssueQueryOptions{Query: "SELECT 1", Priority: -3}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := calls
+			_, err := client.Queries.Issue(ctx, QueryTypeTrino, "db", &tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Issue error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && calls != before {
+				t.Error("Rejected query was sent to the server")
+			}
+		})
+	}
+
+	_, err := client.Queries.Issue(ctx, QueryTypeTrino, "db", &IssueQueryOptions{Query: "SELECT 1", Priority: 2})
+	var violation *PolicyViolationError
+	if !errors.As(err, &violation) || violation.Policy != "max-priority" {
+		t.Errorf("Error = %v, want max-priority *PolicyViolationError", err)
+	}
+}
@@ -0,0 +1,273 @@
+// Package querytemplate renders SQL from text/template with values that are
+// always quoted or escaped. Every action that produces output must end in one
+// of the td* functions, so a plain {{.table}} is rejected at parse time:
+//
+//	query, err := querytemplate.Render(
+//		`SELECT * FROM {{tdIdentifier .db .table}}
+//		 WHERE country = {{tdString .country}} AND {{tdTimeRange "time" .since ""}}
+//		 LIMIT {{tdNumber .limit}}`,
+//		map[string]any{"db": "sales", "table": "events", "country": "JP", "since": "2024-01-01", "limit": 100})
+//
+// Values are quoted for Trino. Hive reads double quotes as a string and a
+// backslash as an escape, so Hive queries are rendered with RenderFor and
+// the other *For functions.
+package querytemplate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// safeFuncs are the functions whose output may be written into a query
+var safeFuncs = map[string]bool{
+	"tdIdentifier": true,
+	"tdString":     true,
+	"tdNumber":     true,
+	"tdTimeRange":  true,
+}
+
+// Funcs returns the Trino quoting functions for use in other templates:
+//
+//	tdIdentifier "db" "table"  -> "db"."table"
+//	tdString "it's"            -> 'it''s'
+//	tdNumber "100"             -> 100 (errors on non-numbers)
+//	tdTimeRange "time" "2024-01-01" "" ["UTC"] -> TD_TIME_RANGE("time", '2024-01-01', NULL, 'UTC')
+func Funcs() template.FuncMap {
+	return FuncsFor(td.QueryTypeTrino)
+}
+
+// FuncsFor returns the quoting functions for the engine a query runs on.
+// For td.QueryTypeHive, tdIdentifier quotes with backticks and tdString
+// escapes with backslashes.
+func FuncsFor(queryType td.QueryType) template.FuncMap {
+	return template.FuncMap{
+		"tdIdentifier": func(parts ...interface{}) (string, error) {
+			return identifier(queryType, parts...)
+		},
+		"tdString": func(v interface{}) string {
+			return td.EscapeStringLiteralFor(queryType, fmt.Sprint(v))
+		},
+		"tdNumber": Number,
+		"tdTimeRange": func(column interface{}, start, end interface{}, timeZone ...string) (string, error) {
+			return timeRange(queryType, column, start, end, timeZone...)
+		},
+	}
+}
+
+// Identifier quotes each part of a qualified name for Trino and joins them
+// with dots
+func Identifier(parts ...interface{}) (string, error) {
+	return identifier(td.QueryTypeTrino, parts...)
+}
+
+func identifier(queryType td.QueryType, parts ...interface{}) (string, error) {
+	if len(parts) == 0 {
+		return "", fmt.Errorf("tdIdentifier requires at least one name")
+	}
+	names := make([]string, len(parts))
+	for i, part := range parts {
+		names[i] = fmt.Sprint(part)
+		if names[i] == "" {
+			return "", fmt.Errorf("tdIdentifier: empty name")
+		}
+	}
+	return td.QualifiedNameFor(queryType, names...), nil
+}
+
+// String quotes a value as a Trino string literal
+func String(v interface{}) string {
+	return td.EscapeStringLiteral(fmt.Sprint(v))
+}
+
+// Number formats an integer or float, parsing strings such as CLI variables,
+// with td.NumberLiteral, so negative numbers are parenthesized. It fails for
+// anything that is not a finite number.
+func Number(v interface{}) (string, error) {
+	switch n := v.(type) {
+	case int, int64, float64:
+		return numberLiteral(n)
+	case string:
+		if i, err := strconv.ParseInt(strings.TrimSpace(n), 10, 64); err == nil {
+			return numberLiteral(i)
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		if err != nil {
+			return "", fmt.Errorf("tdNumber: %q is not a number", n)
+		}
+		return numberLiteral(f)
+	}
+	return "", fmt.Errorf("tdNumber: unsupported type %T", v)
+}
+
+func numberLiteral(v interface{}) (string, error) {
+	s, err := td.NumberLiteral(v)
+	if err != nil {
+		return "", fmt.Errorf("tdNumber: %w", err)
+	}
+	return s, nil
+}
+
+// TimeRange returns a TD_TIME_RANGE call on column. Bounds may be time.Time
+// values (as Unix seconds), integers (Unix seconds) or strings in a format
+// TD_TIME_RANGE accepts; empty strings and nil leave the bound open. An
+// optional fourth argument sets the time zone for string bounds. The column
+// and strings are quoted for Trino.
+func TimeRange(column interface{}, start, end interface{}, timeZone ...string) (string, error) {
+	return timeRange(td.QueryTypeTrino, column, start, end, timeZone...)
+}
+
+func timeRange(queryType td.QueryType, column interface{}, start, end interface{}, timeZone ...string) (string, error) {
+	if len(timeZone) > 1 {
+		return "", fmt.Errorf("tdTimeRange takes at most one time zone")
+	}
+	col := fmt.Sprint(column)
+	if col == "" {
+		return "", fmt.Errorf("tdTimeRange: empty column")
+	}
+
+	args := []string{td.EscapeIdentifierFor(queryType, col)}
+	for _, bound := range []interface{}{start, end} {
+		s, err := timeBound(queryType, bound)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, s)
+	}
+	if len(timeZone) == 1 && timeZone[0] != "" {
+		args = append(args, td.EscapeStringLiteralFor(queryType, timeZone[0]))
+	}
+	return "TD_TIME_RANGE(" + strings.Join(args, ", ") + ")", nil
+}
+
+func timeBound(queryType td.QueryType, v interface{}) (string, error) {
+	switch b := v.(type) {
+	case nil:
+		return "NULL", nil
+	case time.Time:
+		return td.TimeRangeBound(b), nil
+	case int, int64:
+		return td.NumberLiteral(b)
+	case string:
+		if b == "" {
+			return "NULL", nil
+		}
+		return td.EscapeStringLiteralFor(queryType, b), nil
+	}
+	return "", fmt.Errorf("tdTimeRange: unsupported bound type %T", v)
+}
+
+// New returns a template with the Trino quoting functions installed.
+// Missing variables are errors rather than "<no value>".
+func New(name string) *template.Template {
+	return NewFor(td.QueryTypeTrino, name)
+}
+
+// NewFor is New with the quoting functions of queryType
+func NewFor(queryType td.QueryType, name string) *template.Template {
+	return template.New(name).Funcs(FuncsFor(queryType)).Option("missingkey=error")
+}
+
+// Parse parses a Trino query template and verifies that every output action
+// ends in a quoting function
+func Parse(query string) (*template.Template, error) {
+	return ParseFor(td.QueryTypeTrino, query)
+}
+
+// ParseFor is Parse for a query that runs on queryType
+func ParseFor(queryType td.QueryType, query string) (*template.Template, error) {
+	t, err := NewFor(queryType, "query").Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	for _, tmpl := range t.Templates() {
+		if tmpl.Tree == nil {
+			continue
+		}
+		if err := checkNode(tmpl.Tree, tmpl.Tree.Root); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Render parses and executes a Trino query template with vars
+func Render(query string, vars map[string]interface{}) (string, error) {
+	return RenderFor(td.QueryTypeTrino, query, vars)
+}
+
+// RenderFor is Render for a query that runs on queryType
+func RenderFor(queryType td.QueryType, query string, vars map[string]interface{}) (string, error) {
+	t, err := ParseFor(queryType, query)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, vars); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// RenderStrings renders a Trino query with string variables, as collected
+// from key=value command line flags
+func RenderStrings(query string, vars map[string]string) (string, error) {
+	return RenderStringsFor(td.QueryTypeTrino, query, vars)
+}
+
+// RenderStringsFor is RenderStrings for a query that runs on queryType
+func RenderStringsFor(queryType td.QueryType, query string, vars map[string]string) (string, error) {
+	values := make(map[string]interface{}, len(vars))
+	for k, v := range vars {
+		values[k] = v
+	}
+	return RenderFor(queryType, query, values)
+}
+
+// checkNode rejects output actions that bypass the quoting functions
+func checkNode(tree *parse.Tree, node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkNode(tree, child); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		// Assignments such as {{$x := .y}} produce no output
+		if len(n.Pipe.Decl) > 0 {
+			return nil
+		}
+		last := n.Pipe.Cmds[len(n.Pipe.Cmds)-1]
+		if fn, ok := last.Args[0].(*parse.IdentifierNode); ok && safeFuncs[fn.Ident] {
+			return nil
+		}
+		location, context := tree.ErrorContext(n)
+		return fmt.Errorf("%s: %s must end in tdIdentifier, tdString, tdNumber or tdTimeRange", location, context)
+	case *parse.IfNode:
+		return checkBranch(tree, &n.BranchNode)
+	case *parse.RangeNode:
+		return checkBranch(tree, &n.BranchNode)
+	case *parse.WithNode:
+		return checkBranch(tree, &n.BranchNode)
+	case *parse.TemplateNode:
+		location, _ := tree.ErrorContext(n)
+		return fmt.Errorf("%s: {{template}} is not supported in query templates", location)
+	}
+	return nil
+}
+
+func checkBranch(tree *parse.Tree, b *parse.BranchNode) error {
+	if err := checkNode(tree, b.List); err != nil {
+		return err
+	}
+	return checkNode(tree, b.ElseList)
+}
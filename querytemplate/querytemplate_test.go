@@ -0,0 +1,106 @@
+package querytemplate
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+func TestRender(t *testing.T) {
+	query, err := Render(`SELECT * FROM {{tdIdentifier .db .table}}
+WHERE country = {{tdString .country}} AND {{tdTimeRange "time" .since "" "Asia/Tokyo"}}
+{{- if .limit}}
+LIMIT {{tdNumber .limit}}{{end}}`, map[string]interface{}{
+		"db":      "sales",
+		"table":   `ev"ents`,
+		"country": "J'P",
+		"since":   "2024-01-01",
+		"limit":   100,
+	})
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	want := `SELECT * FROM "sales"."ev""ents"
+WHERE country = 'J''P' AND TD_TIME_RANGE("time", '2024-01-01', NULL, 'Asia/Tokyo')
+LIMIT 100`
+	if query != want {
+		t.Errorf("Render =\n%s\nwant\n%s", query, want)
+	}
+}
+
+func TestRenderFor_Hive(t *testing.T) {
+	query, err := RenderStringsFor(td.QueryTypeHive,
+		`SELECT * FROM {{tdIdentifier .db .table}} WHERE a = {{tdString .a}} AND b = {{tdString .b}} AND {{tdTimeRange "time" .since ""}}`,
+		map[string]string{"db": "sales", "table": "events", "a": `\`, "b": " OR 1=1 --", "since": "2024-01-01"})
+	if err != nil {
+		t.Fatalf("RenderStringsFor returned error: %v", err)
+	}
+	want := "SELECT * FROM `sales`.`events` WHERE a = '\\\\' AND b = ' OR 1=1 --' AND TD_TIME_RANGE(`time`, '2024-01-01', NULL)"
+	if query != want {
+		t.Errorf("RenderStringsFor(hive) =\n%s\nwant\n%s", query, want)
+	}
+}
+
+func TestRender_RejectsUnquotedOutput(t *testing.T) {
+	for _, query := range []string{
+		`SELECT * FROM {{.table}}`,
+		`SELECT {{tdString .x | printf "%s"}}`,
+		`{{if .x}}{{.x}}{{end}}`,
+		`{{range .xs}}{{.}}{{end}}`,
+		`{{define "t"}}x{{end}}{{template "t"}}`,
+	} {
+		if _, err := Render(query, map[string]interface{}{"table": "t", "x": "1", "xs": []string{"a"}}); err == nil {
+			t.Errorf("Render(%q) succeeded, want error", query)
+		}
+	}
+
+	// Quoted pipelines, ranges and assignments are fine
+	query, err := Render(`{{$t := .table}}{{.table | tdIdentifier}} IN ({{range $i, $v := .xs}}{{if $i}}, {{end}}{{tdString $v}}{{end}})`,
+		map[string]interface{}{"table": "t", "xs": []string{"a", "b"}})
+	if err != nil || query != `"t" IN ('a', 'b')` {
+		t.Errorf("Render = %q, %v", query, err)
+	}
+}
+
+func TestRender_MissingVariable(t *testing.T) {
+	if _, err := RenderStrings(`SELECT {{tdString .missing}}`, map[string]string{}); err == nil {
+		t.Error("Expected error for a missing variable")
+	}
+}
+
+func TestNumber(t *testing.T) {
+	for in, want := range map[interface{}]string{"42": "42", " 1.5 ": "1.5", int64(7): "7", 2.0: "2", "-5": "(-5)", -0.5: "(-0.5)"} {
+		got, err := Number(in)
+		if err != nil || got != want {
+			t.Errorf("Number(%v) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []interface{}{"1; DROP TABLE x", "NaN", true} {
+		if _, err := Number(in); err == nil {
+			t.Errorf("Number(%v) succeeded, want error", in)
+		}
+	}
+}
+
+func TestRenderStrings_NegativeNumber(t *testing.T) {
+	query, err := RenderStrings("SELECT 1-{{tdNumber .n}} FROM t\nWHERE {{tdTimeRange \"time\" .n \"\"}}", map[string]string{"n": "-5"})
+	if err != nil || query != "SELECT 1-(-5) FROM t\nWHERE TD_TIME_RANGE(\"time\", '-5', NULL)" {
+		t.Errorf("RenderStrings = %q, %v", query, err)
+	}
+	if got, err := TimeRange("time", -60, time.Unix(-60, 0)); err != nil || got != `TD_TIME_RANGE("time", (-60), (-60))` {
+		t.Errorf("TimeRange with negative bounds = %q, %v", got, err)
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+	got, err := TimeRange("time", time.Unix(1700000000, 0), int64(1700003600))
+	if err != nil || got != `TD_TIME_RANGE("time", 1700000000, 1700003600)` {
+		t.Errorf("TimeRange = %q, %v", got, err)
+	}
+	if _, err := TimeRange("time", 1.5, nil); err == nil || !strings.Contains(err.Error(), "float64") {
+		t.Errorf("Expected unsupported type error, got %v", err)
+	}
+}
@@ -0,0 +1,237 @@
+package treasuredata
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// likeEscape is the escape character used by the LIKE helpers
+const likeEscape = `\`
+
+// EscapeIdentifier quotes a Trino identifier such as a database, table or
+// column name. Embedded double quotes are doubled and NUL characters, which
+// would truncate the identifier on the server, are removed. In Hive, double
+// quotes make a string literal; use EscapeHiveIdentifier there.
+func EscapeIdentifier(identifier string) string {
+	identifier = strings.ReplaceAll(identifier, "\x00", "")
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
+
+// EscapeStringLiteral quotes a Trino string literal. Embedded single quotes
+// are doubled and NUL characters are removed; backslashes have no special
+// meaning in Trino and are kept as is. Hive treats a backslash as an escape,
+// so a value ending in one would end the literal early; use
+// EscapeHiveStringLiteral there.
+func EscapeStringLiteral(literal string) string {
+	literal = strings.ReplaceAll(literal, "\x00", "")
+	return `'` + strings.ReplaceAll(literal, `'`, `''`) + `'`
+}
+
+// hiveStringEscaper escapes the characters Hive's lexer reads specially in a
+// single-quoted string
+var hiveStringEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\x00", "")
+
+// EscapeHiveIdentifier quotes a Hive identifier with backticks. Embedded
+// backticks are doubled and NUL characters are removed.
+func EscapeHiveIdentifier(identifier string) string {
+	identifier = strings.ReplaceAll(identifier, "\x00", "")
+	return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
+}
+
+// EscapeHiveStringLiteral quotes a Hive string literal. Backslashes and
+// single quotes are escaped with a backslash and NUL characters are removed.
+func EscapeHiveStringLiteral(literal string) string {
+	return `'` + hiveStringEscaper.Replace(literal) + `'`
+}
+
+// QualifiedName quotes each non-empty part and joins them with dots, e.g.
+// QualifiedName("sales", "events") returns "sales"."events". Empty parts are
+// skipped so that an unset database yields an unqualified name.
+func QualifiedName(parts ...string) string {
+	var quoted []string
+	for _, part := range parts {
+		if part != "" {
+			quoted = append(quoted, EscapeIdentifier(part))
+		}
+	}
+	return strings.Join(quoted, ".")
+}
+
+// HiveQualifiedName is QualifiedName for Hive, quoting each part with
+// backticks: `sales`.`events`
+func HiveQualifiedName(parts ...string) string {
+	var quoted []string
+	for _, part := range parts {
+		if part != "" {
+			quoted = append(quoted, EscapeHiveIdentifier(part))
+		}
+	}
+	return strings.Join(quoted, ".")
+}
+
+// EscapeIdentifierFor quotes an identifier for the engine a query runs on:
+// with backticks for QueryTypeHive and double quotes otherwise
+func EscapeIdentifierFor(queryType QueryType, identifier string) string {
+	if queryType == QueryTypeHive {
+		return EscapeHiveIdentifier(identifier)
+	}
+	return EscapeIdentifier(identifier)
+}
+
+// EscapeStringLiteralFor quotes a string literal for the engine a query runs
+// on
+func EscapeStringLiteralFor(queryType QueryType, literal string) string {
+	if queryType == QueryTypeHive {
+		return EscapeHiveStringLiteral(literal)
+	}
+	return EscapeStringLiteral(literal)
+}
+
+// QualifiedNameFor quotes a qualified name for the engine a query runs on
+func QualifiedNameFor(queryType QueryType, parts ...string) string {
+	if queryType == QueryTypeHive {
+		return HiveQualifiedName(parts...)
+	}
+	return QualifiedName(parts...)
+}
+
+// ParseQualifiedName splits a possibly quoted, dotted name as typed by a user
+// (sales.events, "my.db"."events", sales."Event ""log""") into its unquoted
+// parts. NUL characters are rejected rather than dropped, so that a name
+// cannot silently turn into another one.
+func ParseQualifiedName(name string) ([]string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("empty name")
+	}
+	if strings.ContainsRune(name, 0) {
+		return nil, fmt.Errorf("NUL character in %q", name)
+	}
+
+	var parts []string
+	var current strings.Builder
+	quoted := false
+	wasQuoted := false
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case quoted && c == '"':
+			if i+1 < len(name) && name[i+1] == '"' {
+				current.WriteByte('"')
+				i++
+			} else {
+				quoted = false
+			}
+		case quoted:
+			current.WriteByte(c)
+		case c == '"':
+			if current.Len() > 0 || wasQuoted {
+				return nil, fmt.Errorf("unexpected quote in %q", name)
+			}
+			quoted, wasQuoted = true, true
+		case c == '.':
+			if current.Len() == 0 {
+				return nil, fmt.Errorf("empty name part in %q", name)
+			}
+			parts = append(parts, current.String())
+			current.Reset()
+			wasQuoted = false
+		case c == ' ' || c == '\t':
+			return nil, fmt.Errorf("unquoted whitespace in %q", name)
+		default:
+			if wasQuoted {
+				return nil, fmt.Errorf("unexpected %q after quoted part in %q", c, name)
+			}
+			current.WriteByte(c)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in %q", name)
+	}
+	if current.Len() == 0 {
+		return nil, fmt.Errorf("empty name part in %q", name)
+	}
+	return append(parts, current.String()), nil
+}
+
+// EscapeQualifiedName parses name with ParseQualifiedName and re-quotes every
+// part, turning user input into a safe table reference
+func EscapeQualifiedName(name string) (string, error) {
+	parts, err := ParseQualifiedName(name)
+	if err != nil {
+		return "", err
+	}
+	return QualifiedName(parts...), nil
+}
+
+// EscapeLikePattern escapes the LIKE wildcards % and _ (and the escape
+// character itself) so that value matches literally. Use it with an
+// ESCAPE '\' clause, or through LikeLiteral, LikePrefix and LikeContains.
+func EscapeLikePattern(value string) string {
+	r := strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_")
+	return r.Replace(value)
+}
+
+// LikeLiteral returns a LIKE operand matching value exactly, including its
+// ESCAPE clause: 'a\_b' ESCAPE '\'
+func LikeLiteral(value string) string {
+	return likeOperand(EscapeLikePattern(value))
+}
+
+// LikePrefix returns a LIKE operand matching strings that start with prefix
+func LikePrefix(prefix string) string {
+	return likeOperand(EscapeLikePattern(prefix) + "%")
+}
+
+// LikeContains returns a LIKE operand matching strings that contain substr
+func LikeContains(substr string) string {
+	return likeOperand("%" + EscapeLikePattern(substr) + "%")
+}
+
+func likeOperand(pattern string) string {
+	return EscapeStringLiteral(pattern) + " ESCAPE " + EscapeStringLiteral(likeEscape)
+}
+
+// NumberLiteral formats an integer or finite float as a SQL number. Negative
+// numbers are parenthesized, as in (-5), so that a minus sign written before
+// the value cannot turn it into a -- comment.
+func NumberLiteral(v any) (string, error) {
+	var s string
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s = strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s = strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return "", fmt.Errorf("%v is not a finite number", f)
+		}
+		bitSize := 64
+		if rv.Kind() == reflect.Float32 {
+			bitSize = 32
+		}
+		s = strconv.FormatFloat(f, 'f', -1, bitSize)
+	default:
+		return "", fmt.Errorf("unsupported type %T", v)
+	}
+	if strings.HasPrefix(s, "-") {
+		return "(" + s + ")", nil
+	}
+	return s, nil
+}
+
+// TimeRangeBound formats t as Unix seconds for TD_TIME_RANGE and the time
+// column, through NumberLiteral; the zero time is NULL, an open bound
+func TimeRangeBound(t time.Time) string {
+	if t.IsZero() {
+		return "NULL"
+	}
+	s, _ := NumberLiteral(t.Unix())
+	return s
+}
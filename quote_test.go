@@ -0,0 +1,196 @@
+package treasuredata
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEscape_RemovesNUL(t *testing.T) {
+	if got := EscapeIdentifier("ta\x00ble"); got != `"table"` {
+		t.Errorf("EscapeIdentifier = %q", got)
+	}
+	if got := EscapeStringLiteral("a\x00'b"); got != `'a''b'` {
+		t.Errorf("EscapeStringLiteral = %q", got)
+	}
+}
+
+func TestHiveEscaping(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"O'Brien", `'O\'Brien'`},
+		// A trailing backslash must not escape the closing quote
+		{`a\`, `'a\\'`},
+		{`\' OR 1=1 --`, `'\\\' OR 1=1 --'`},
+		{"a\x00b", `'ab'`},
+	}
+	for _, tt := range tests {
+		if got := EscapeHiveStringLiteral(tt.input); got != tt.want {
+			t.Errorf("EscapeHiveStringLiteral(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+	if got := EscapeHiveIdentifier("ev`en\x00ts"); got != "`ev``ents`" {
+		t.Errorf("EscapeHiveIdentifier = %s", got)
+	}
+	if got := HiveQualifiedName("", "sales", "events"); got != "`sales`.`events`" {
+		t.Errorf("HiveQualifiedName = %s", got)
+	}
+}
+
+func TestQualifiedName(t *testing.T) {
+	if got := QualifiedName("sales", `ev"ents`); got != `"sales"."ev""ents"` {
+		t.Errorf("QualifiedName = %q", got)
+	}
+	if got := QualifiedName("", "events"); got != `"events"` {
+		t.Errorf("QualifiedName with empty database = %q", got)
+	}
+}
+
+func TestParseQualifiedName(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"events", []string{"events"}},
+		{"sales.events", []string{"sales", "events"}},
+		{`"my.db"."Event ""log"""`, []string{"my.db", `Event "log"`}},
+		{`td.sales."events"`, []string{"td", "sales", "events"}},
+		{`""`, nil},
+		{"", nil},
+		{"sales.", nil},
+		{".events", nil},
+		{`"unterminated`, nil},
+		{`"a"b`, nil},
+		{`a"b"`, nil},
+		{"events; DROP TABLE x", nil},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseQualifiedName(tt.input)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("ParseQualifiedName(%q) = %q, want error", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseQualifiedName(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseQualifiedName(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+
+		// Quoting the parts again must round-trip
+		quoted, err := EscapeQualifiedName(tt.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, err := ParseQualifiedName(quoted)
+		if err != nil || !reflect.DeepEqual(again, tt.want) {
+			t.Errorf("round trip of %q via %q = %q, %v", tt.input, quoted, again, err)
+		}
+	}
+}
+
+func TestLikeHelpers(t *testing.T) {
+	if got := EscapeLikePattern(`50%_off\`); got != `50\%\_off\\` {
+		t.Errorf("EscapeLikePattern = %q", got)
+	}
+	if got := LikeLiteral("a_b"); got != `'a\_b' ESCAPE '\'` {
+		t.Errorf("LikeLiteral = %q", got)
+	}
+	if got := LikePrefix("user's_"); got != `'user''s\_%' ESCAPE '\'` {
+		t.Errorf("LikePrefix = %q", got)
+	}
+	if got := LikeContains("100%"); got != `'%100\%%' ESCAPE '\'` {
+		t.Errorf("LikeContains = %q", got)
+	}
+}
+
+func FuzzEscapeIdentifier(f *testing.F) {
+	for _, seed := range []string{"events", `my "table"`, "sales.events", "ta\x00ble", `""`, "日本語", ""} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, identifier string) {
+		escaped := EscapeIdentifier(identifier)
+		if strings.ContainsRune(escaped, 0) {
+			t.Fatalf("EscapeIdentifier(%q) = %q contains NUL", identifier, escaped)
+		}
+		want := strings.ReplaceAll(identifier, "\x00", "")
+		if want == "" {
+			return
+		}
+		parts, err := ParseQualifiedName(escaped)
+		if err != nil {
+			t.Fatalf("ParseQualifiedName(%q) returned error: %v", escaped, err)
+		}
+		if len(parts) != 1 || parts[0] != want {
+			t.Fatalf("ParseQualifiedName(EscapeIdentifier(%q)) = %q", identifier, parts)
+		}
+	})
+}
+
+func FuzzEscapeStringLiteral(f *testing.F) {
+	for _, seed := range []string{"JP", "it's", "''", `back\slash`, "a\x00b", ""} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, literal string) {
+		escaped := EscapeStringLiteral(literal)
+		if len(escaped) < 2 || escaped[0] != '\'' || escaped[len(escaped)-1] != '\'' {
+			t.Fatalf("EscapeStringLiteral(%q) = %q is not quoted", literal, escaped)
+		}
+		// The literal must end exactly where the quoting ends
+		if end := quotedEnd(escaped, 0, '\'', false); end != len(escaped) {
+			t.Fatalf("EscapeStringLiteral(%q) = %q ends at %d", literal, escaped, end)
+		}
+		if hive := EscapeHiveStringLiteral(literal); quotedEnd(hive, 0, '\'', true) != len(hive) {
+			t.Fatalf("EscapeHiveStringLiteral(%q) = %q ends early", literal, hive)
+		}
+		inner := strings.ReplaceAll(escaped[1:len(escaped)-1], "''", "'")
+		if want := strings.ReplaceAll(literal, "\x00", ""); inner != want {
+			t.Fatalf("EscapeStringLiteral(%q) unquotes to %q", literal, inner)
+		}
+	})
+}
+
+func FuzzParseQualifiedName(f *testing.F) {
+	for _, seed := range []string{"sales.events", `"my.db"."events"`, `sales."Event ""log"""`, `"unterminated`, "a..b", `"a"b`, "a b"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		parts, err := ParseQualifiedName(name)
+		if err != nil {
+			return
+		}
+		for _, part := range parts {
+			if part == "" {
+				t.Fatalf("ParseQualifiedName(%q) = %q has an empty part", name, parts)
+			}
+		}
+		// Quoting the parts again must give back the same parts
+		escaped := QualifiedName(parts...)
+		again, err := ParseQualifiedName(escaped)
+		if err != nil {
+			t.Fatalf("ParseQualifiedName(%q) returned error: %v", escaped, err)
+		}
+		if !reflect.DeepEqual(again, parts) {
+			t.Fatalf("round trip of %q: %q != %q", name, again, parts)
+		}
+	})
+}
+
+func TestNumberLiteral(t *testing.T) {
+	for in, want := range map[any]string{5: "5", -5: "(-5)", uint8(7): "7", 1.5: "1.5", float32(-0.1): "(-0.1)"} {
+		if got, err := NumberLiteral(in); err != nil || got != want {
+			t.Errorf("NumberLiteral(%v) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []any{math.NaN(), math.Inf(-1), "5", true} {
+		if _, err := NumberLiteral(in); err == nil {
+			t.Errorf("NumberLiteral(%v) succeeded, want error", in)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package treasuredata
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit represents the API rate limit state reported by the most recent response
+type RateLimit struct {
+	// Limit is the number of requests allowed in the current window
+	Limit int
+	// Remaining is the number of requests left in the current window
+	Remaining int
+	// Reset is when the current window ends
+	Reset time.Time
+	// Known reports whether any rate limit headers have been received
+	Known bool
+}
+
+// RateLimiterOptions configures client-side throttling based on rate limit headers
+type RateLimiterOptions struct {
+	// Threshold is the remaining request count at or below which requests
+	// are delayed until the window resets. Defaults to 1.
+	Threshold int
+	// MaxWait caps how long a single request is delayed. Defaults to 1 minute.
+	MaxWait time.Duration
+	// MaxRetries is how many times a 429 response is retried after waiting.
+	// Defaults to 3; a negative value disables retries, so 429 responses are
+	// returned as errors while requests are still throttled.
+	MaxRetries int
+}
+
+// rateLimiter tracks rate limit headers and delays requests when the limit is close
+type rateLimiter struct {
+	mu      sync.Mutex
+	state   RateLimit
+	enabled bool
+	opts    RateLimiterOptions
+}
+
+// WithRateLimiter enables client-side throttling. Requests are delayed when the
+// remaining quota drops to the threshold, and 429 responses are retried after
+// the server-provided delay instead of being returned as errors.
+func WithRateLimiter(opts RateLimiterOptions) ClientOption {
+	return func(c *Client) error {
+		if opts.Threshold <= 0 {
+			opts.Threshold = 1
+		}
+		if opts.MaxWait <= 0 {
+			opts.MaxWait = time.Minute
+		}
+		if opts.MaxRetries == 0 {
+			opts.MaxRetries = 3
+		} else if opts.MaxRetries < 0 {
+			opts.MaxRetries = 0
+		}
+		c.rateLimiter.mu.Lock()
+		c.rateLimiter.enabled = true
+		c.rateLimiter.opts = opts
+		c.rateLimiter.mu.Unlock()
+		return nil
+	}
+}
+
+// RateLimit returns the rate limit state reported by the most recent API response
+func (c *Client) RateLimit() RateLimit {
+	c.rateLimiter.mu.Lock()
+	defer c.rateLimiter.mu.Unlock()
+	return c.rateLimiter.state
+}
+
+// wait blocks until the current window resets when the remaining quota is at the threshold
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	if !l.enabled || !l.state.Known || l.state.Remaining > l.opts.Threshold {
+		l.mu.Unlock()
+		return nil
+	}
+	delay := time.Until(l.state.Reset)
+	maxWait := l.opts.MaxWait
+	l.mu.Unlock()
+
+	return sleepContext(ctx, delay, maxWait)
+}
+
+// update records the rate limit headers of a response
+func (l *rateLimiter) update(resp *http.Response) {
+	limit, hasLimit := headerInt(resp.Header, "X-RateLimit-Limit")
+	remaining, hasRemaining := headerInt(resp.Header, "X-RateLimit-Remaining")
+	if !hasLimit && !hasRemaining {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.state.Known = true
+	if hasLimit {
+		l.state.Limit = limit
+	}
+	if hasRemaining {
+		l.state.Remaining = remaining
+	}
+	if reset, ok := headerInt(resp.Header, "X-RateLimit-Reset"); ok {
+		l.state.Reset = parseResetHeader(int64(reset))
+	}
+}
+
+// retryDelay reports how long to wait before retrying a throttled response
+func (l *rateLimiter) retryDelay(resp *http.Response, attempt int) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.enabled || resp.StatusCode != http.StatusTooManyRequests || attempt >= l.opts.MaxRetries {
+		return 0, false
+	}
+
+	delay := time.Duration(1<<attempt) * time.Second
+	if secs, ok := headerInt(resp.Header, "Retry-After"); ok {
+		delay = time.Duration(secs) * time.Second
+	} else if l.state.Known && !l.state.Reset.IsZero() {
+		delay = time.Until(l.state.Reset)
+	}
+
+	if delay > l.opts.MaxWait {
+		delay = l.opts.MaxWait
+	}
+	return delay, true
+}
+
+// parseResetHeader interprets X-RateLimit-Reset as either a Unix timestamp or seconds from now
+func parseResetHeader(v int64) time.Time {
+	// Values this large can only be epoch seconds
+	if v > 1000000000 {
+		return time.Unix(v, 0)
+	}
+	return time.Now().Add(time.Duration(v) * time.Second)
+}
+
+func headerInt(h http.Header, key string) (int, bool) {
+	v := h.Get(key)
+	if v == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// sleepContext sleeps for d (capped at max) or until ctx is done
+func sleepContext(ctx context.Context, d, max time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	if max > 0 && d > max {
+		d = max
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,139 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClient_RateLimit(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/database/list", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.Header().Set("X-RateLimit-Reset", "30")
+		fmt.Fprint(w, `{"databases": []}`)
+	})
+
+	if got := client.RateLimit(); got.Known {
+		t.Errorf("RateLimit before any request = %+v, want unknown", got)
+	}
+
+	if _, err := client.Databases.List(context.Background()); err != nil {
+		t.Fatalf("Databases.List returned error: %v", err)
+	}
+
+	got := client.RateLimit()
+	if !got.Known || got.Limit != 100 || got.Remaining != 42 {
+		t.Errorf("RateLimit = %+v, want limit 100 remaining 42", got)
+	}
+	if until := time.Until(got.Reset); until < 25*time.Second || until > 30*time.Second {
+		t.Errorf("RateLimit reset in %v, want about 30s", until)
+	}
+}
+
+func TestClient_RateLimiter_Retries429(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	if err := WithRateLimiter(RateLimiterOptions{MaxRetries: 2})(client); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	mux.HandleFunc("/v3/database/list", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"databases": []}`)
+	})
+
+	if _, err := client.Databases.List(context.Background()); err != nil {
+		t.Fatalf("Databases.List returned error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Server received %d calls, want 2", calls)
+	}
+}
+
+func TestClient_RateLimiter_NoRetries(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	if err := WithRateLimiter(RateLimiterOptions{MaxRetries: -1})(client); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	mux.HandleFunc("/v3/database/list", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	_, err := client.Databases.List(context.Background())
+	if !IsRateLimited(err) {
+		t.Errorf("Databases.List error = %v, want a rate limit error", err)
+	}
+	if calls != 1 {
+		t.Errorf("Server received %d calls, want 1", calls)
+	}
+}
+
+func TestClient_RateLimiter_Disabled(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/database/list", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	_, err := client.Databases.List(context.Background())
+	if tdErr, ok := err.(*ErrorResponse); !ok || tdErr.Response.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Databases.List error = %v, want 429 ErrorResponse", err)
+	}
+}
+
+func TestClient_RateLimiter_DelaysNearLimit(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	if err := WithRateLimiter(RateLimiterOptions{Threshold: 1, MaxWait: 50 * time.Millisecond})(client); err != nil {
+		t.Fatal(err)
+	}
+
+	mux.HandleFunc("/v3/database/list", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "10")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "60")
+		fmt.Fprint(w, `{"databases": []}`)
+	})
+
+	ctx := context.Background()
+	if _, err := client.Databases.List(ctx); err != nil {
+		t.Fatalf("Databases.List returned error: %v", err)
+	}
+
+	start := time.Now()
+	if _, err := client.Databases.List(ctx); err != nil {
+		t.Fatalf("Databases.List returned error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Second request took %v, want it delayed by at least 50ms", elapsed)
+	}
+
+	// A cancelled context aborts the wait
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := client.Databases.List(cancelled); err == nil {
+		t.Error("Expected error for cancelled context")
+	}
+}
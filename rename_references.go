@@ -0,0 +1,87 @@
+package treasuredata
+
+// RenameReference is an object that refers to a segment, folder or workflow
+// being renamed
+type RenameReference struct {
+	// Kind is activation, funnel, journey, segment or workflow
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Breaks reports that the reference holds the old name, so the rename
+	// does not carry over to it. References by ID follow a rename.
+	Breaks bool   `json:"breaks"`
+	Detail string `json:"detail"`
+	// Updated reports that RenameOptions.Cascade rewrote the reference to
+	// the new name
+	Updated bool `json:"updated,omitempty"`
+
+	// cascade rewrites the reference, if the API allows it
+	cascade func() error
+}
+
+// CanCascade reports whether the reference can be updated to the new name
+// through the API
+func (r *RenameReference) CanCascade() bool {
+	return r.cascade != nil
+}
+
+// RenameOptions controls a rename that checks references
+type RenameOptions struct {
+	// DryRun reports the references without renaming anything
+	DryRun bool
+	// Cascade updates the references that hold the old name where the API
+	// allows it; the others are only reported
+	Cascade bool
+}
+
+// RenameReport lists what refers to a renamed object
+type RenameReport struct {
+	OldName    string            `json:"old_name"`
+	NewName    string            `json:"new_name"`
+	References []RenameReference `json:"references"`
+	// Renamed is false for a dry run
+	Renamed bool `json:"renamed"`
+}
+
+// Breaking returns the references that hold the old name and were not
+// updated
+func (r *RenameReport) Breaking() []RenameReference {
+	var breaking []RenameReference
+	for _, ref := range r.References {
+		if ref.Breaks && !ref.Updated {
+			breaking = append(breaking, ref)
+		}
+	}
+	return breaking
+}
+
+// applyRename renames through rename unless opts is a dry run, then
+// cascades the references that allow it. On error the report so far is
+// returned with it.
+func applyRename(report *RenameReport, opts *RenameOptions, rename func() error) (*RenameReport, error) {
+	if opts == nil {
+		opts = &RenameOptions{}
+	}
+	if opts.DryRun {
+		return report, nil
+	}
+	if err := rename(); err != nil {
+		return report, err
+	}
+	report.Renamed = true
+
+	if !opts.Cascade {
+		return report, nil
+	}
+	for i := range report.References {
+		ref := &report.References[i]
+		if !ref.Breaks || ref.cascade == nil {
+			continue
+		}
+		if err := ref.cascade(); err != nil {
+			return report, err
+		}
+		ref.Updated = true
+	}
+	return report, nil
+}
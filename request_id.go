@@ -0,0 +1,103 @@
+package treasuredata
+
+import (
+	"context"
+	"net/http"
+)
+
+// requestIDHeaders are response headers that carry the ID Treasure Data
+// assigned to a request, in order of preference
+var requestIDHeaders = []string{"X-Request-Id", "X-Td-Request-Id", "X-Amzn-Requestid"}
+
+// RequestIDFromResponse returns the ID Treasure Data assigned to the request
+// of resp, such as one returned by Client.Do, or "" when the response has
+// none. Include it when reporting a failure to support; errors carry it as
+// ErrorResponse.RequestID.
+func RequestIDFromResponse(resp *http.Response) string {
+	if resp == nil {
+		return ""
+	}
+	for _, header := range requestIDHeaders {
+		if id := resp.Header.Get(header); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+type correlationIDKey struct{}
+type idempotencyKeyKey struct{}
+type headersKey struct{}
+
+// protectedHeaders cannot be set through WithHeader, so a context cannot
+// change the credentials or framing of a request
+var protectedHeaders = map[string]bool{
+	"Authorization":     true,
+	"Host":              true,
+	"Content-Length":    true,
+	"Transfer-Encoding": true,
+}
+
+// WithCorrelationID returns a context whose requests carry id in the
+// X-Correlation-Id header, to tie API calls to an application trace
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID set with
+// WithCorrelationID
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// WithIdempotencyKey returns a context whose requests carry key in the
+// Idempotency-Key header, so a retried submission can be recognized
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey{}, key)
+}
+
+// WithHeader returns a context whose requests carry the header key: value,
+// such as an audit tag or an experiment flag. Calls accumulate, and a later
+// value for the same key replaces an earlier one. Authorization, Host and
+// framing headers are ignored.
+func WithHeader(ctx context.Context, key, value string) context.Context {
+	key = http.CanonicalHeaderKey(key)
+	if protectedHeaders[key] {
+		return ctx
+	}
+	headers := HeadersFromContext(ctx)
+	if headers == nil {
+		headers = http.Header{}
+	}
+	headers.Set(key, value)
+	return context.WithValue(ctx, headersKey{}, headers)
+}
+
+// HeadersFromContext returns a copy of the headers set with WithHeader
+func HeadersFromContext(ctx context.Context) http.Header {
+	headers, _ := ctx.Value(headersKey{}).(http.Header)
+	return headers.Clone()
+}
+
+// setContextHeaders adds the WithHeader headers, correlation ID and
+// idempotency key from ctx
+func setContextHeaders(ctx context.Context, req *http.Request) {
+	correlationID := CorrelationIDFromContext(ctx)
+	idempotencyKey, _ := ctx.Value(idempotencyKeyKey{}).(string)
+	headers, _ := ctx.Value(headersKey{}).(http.Header)
+	if correlationID == "" && idempotencyKey == "" && len(headers) == 0 {
+		return
+	}
+
+	req.Header = req.Header.Clone()
+	for key, values := range headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	if correlationID != "" {
+		req.Header.Set("X-Correlation-Id", correlationID)
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+}
@@ -0,0 +1,94 @@
+package treasuredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRequestIDFromResponse(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/database/list", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Correlation-Id"); got != "trace-42" {
+			t.Errorf("X-Correlation-Id = %q, want trace-42", got)
+		}
+		if got := r.Header.Get("Idempotency-Key"); got != "submit-1" {
+			t.Errorf("Idempotency-Key = %q, want submit-1", got)
+		}
+		w.Header().Set("X-Request-Id", "req-123")
+		fmt.Fprint(w, `{"databases": []}`)
+	})
+
+	ctx := WithIdempotencyKey(WithCorrelationID(context.Background(), "trace-42"), "submit-1")
+	req, err := client.NewRequest("GET", "v3/database/list", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(ctx, req, nil)
+	if err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if id := RequestIDFromResponse(resp); id != "req-123" {
+		t.Errorf("RequestIDFromResponse = %q, want req-123", id)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d", resp.StatusCode)
+	}
+	if req.Header.Get("X-Correlation-Id") != "" {
+		t.Error("The caller's request headers should not be modified")
+	}
+}
+
+func TestErrorResponse_RequestID(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/database/show/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Td-Request-Id", "td-req-9")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message": "Database not found"}`)
+	})
+
+	_, err := client.Databases.Get(context.Background(), "missing")
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) {
+		t.Fatalf("Expected *ErrorResponse, got %T: %v", err, err)
+	}
+	if errResp.RequestID != "td-req-9" || !strings.Contains(err.Error(), "request ID td-req-9") {
+		t.Errorf("Error = %v, want request ID td-req-9", err)
+	}
+}
+
+func TestWithHeader(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var got http.Header
+	mux.HandleFunc("/v3/database/list", func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		fmt.Fprint(w, `{"databases": []}`)
+	})
+
+	ctx := WithHeader(context.Background(), "x-audit-tag", "nightly-report")
+	parent := WithHeader(ctx, "X-Experiment", "a")
+	ctx = WithHeader(parent, "X-Experiment", "b")
+	ctx = WithHeader(ctx, "Authorization", "TD1 other/key")
+
+	if _, err := client.Databases.List(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got.Get("X-Audit-Tag") != "nightly-report" || got.Get("X-Experiment") != "b" {
+		t.Errorf("headers = %v", got)
+	}
+	if got.Get("Authorization") != "TD1 test-api-key" {
+		t.Errorf("Authorization = %q, want the client's key", got.Get("Authorization"))
+	}
+	if HeadersFromContext(parent).Get("X-Experiment") != "a" {
+		t.Error("WithHeader modified the parent context")
+	}
+}
@@ -0,0 +1,85 @@
+package treasuredata
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// RequestOption customizes a single API call, such as giving a long result
+// download a longer deadline than quick metadata calls made by the same client.
+// Request options are accepted by the calls that can run long or move data:
+// the Jobs get, list, status, kill, export and download methods,
+// Queries.Issue and the Hivemall queries, the Results reads (GetResult,
+// GetResultJSON, GetResultJSONL and Decoder), the bulk import uploads,
+// Perform and ErrorRecords, the workflow project downloads, and Client.Do.
+// Other calls, such as job waiting and watching, result connections and the
+// database, table, user, CDP and workflow metadata methods, take their
+// deadline from ctx.
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	timeout  time.Duration
+	deadline time.Time
+	// resume and resultFormat apply to Jobs.DownloadResult, resultFormat and
+	// chunkSize to Jobs.DownloadResultParallel
+	resume       bool
+	resultFormat ResultFormat
+	chunkSize    int64
+	// progress applies to downloads, see WithProgress
+	progress ProgressFunc
+}
+
+// WithTimeout limits the call, including rate limit retries, to d. For calls
+// that return a stream, the timeout also covers reading the stream.
+func WithTimeout(d time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = d
+	}
+}
+
+// WithDeadline makes the call fail once t has passed. When both WithTimeout and
+// WithDeadline are given, the earlier one applies.
+func WithDeadline(t time.Time) RequestOption {
+	return func(o *requestOptions) {
+		o.deadline = t
+	}
+}
+
+// newRequestOptions applies opts to the zero options
+func newRequestOptions(opts []RequestOption) requestOptions {
+	var o requestOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// requestContext derives the context for a call from its request options. The
+// returned cancel func must always be called.
+func requestContext(ctx context.Context, opts []RequestOption) (context.Context, context.CancelFunc) {
+	o := newRequestOptions(opts)
+	deadline := o.deadline
+	if o.timeout > 0 {
+		if t := time.Now().Add(o.timeout); deadline.IsZero() || t.Before(deadline) {
+			deadline = t
+		}
+	}
+	if deadline.IsZero() {
+		return ctx, func() {}
+	}
+	return context.WithDeadline(ctx, deadline)
+}
+
+// cancelOnClose releases a request context when the response body it guards
+// is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
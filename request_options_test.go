@@ -0,0 +1,67 @@
+package treasuredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithTimeout(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/show/12345", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		fmt.Fprint(w, `{"job_id": "12345"}`)
+	})
+
+	_, err := client.Jobs.Get(context.Background(), "12345", WithTimeout(20*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Jobs.Get error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestWithTimeout_StreamOutlivesCall(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/result/12345", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "a,1\nb,2\n")
+	})
+
+	body, err := client.Results.GetResult(context.Background(), "12345", nil, WithTimeout(time.Minute))
+	if err != nil {
+		t.Fatalf("Results.GetResult returned error: %v", err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("Reading result after GetResult returned: %v", err)
+	}
+	if string(data) != "a,1\nb,2\n" {
+		t.Errorf("Result = %q", data)
+	}
+}
+
+func TestRequestContext(t *testing.T) {
+	ctx, cancel := requestContext(context.Background(), nil)
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("Context without options should have no deadline")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	ctx, cancel = requestContext(context.Background(), []RequestOption{WithTimeout(time.Hour), WithDeadline(deadline)})
+	defer cancel()
+	if got, ok := ctx.Deadline(); !ok || !got.Equal(deadline) {
+		t.Errorf("Deadline = %v, want the earlier %v", got, deadline)
+	}
+}
@@ -0,0 +1,394 @@
+package treasuredata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResultDecoder reads job results row by row and scans them into structs.
+// Rows may be JSON objects keyed by column name or JSON arrays in column
+// order; arrays need the columns of the job's result schema.
+type ResultDecoder struct {
+	dec     *json.Decoder
+	closer  io.Closer
+	columns []string
+
+	row map[string]interface{}
+	err error
+}
+
+// NewResultDecoder decodes rows from r. columns names the values of array
+// rows and may be nil when rows are objects.
+func NewResultDecoder(r io.Reader, columns []TableColumn) *ResultDecoder {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	d := &ResultDecoder{dec: dec}
+	if closer, ok := r.(io.Closer); ok {
+		d.closer = closer
+	}
+	for _, column := range columns {
+		d.columns = append(d.columns, column.Name)
+	}
+	return d
+}
+
+// Decoder streams a job's results in JSONL format for scanning into structs.
+// The job is looked up to learn its result columns.
+func (s *ResultsService) Decoder(ctx context.Context, jobID string, reqOpts ...RequestOption) (*ResultDecoder, error) {
+	job, err := s.client.Jobs.Get(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return jobResultDecoder(ctx, s, job, reqOpts...)
+}
+
+// Decoder streams the job's results for scanning into structs
+func (r *QueryResult) Decoder(ctx context.Context) (*ResultDecoder, error) {
+	return jobResultDecoder(ctx, r.client.Results, r.Job)
+}
+
+func jobResultDecoder(ctx context.Context, results ResultsAPI, job *Job, reqOpts ...RequestOption) (*ResultDecoder, error) {
+	columns, err := job.ResultSchema()
+	if err != nil {
+		return nil, err
+	}
+	body, err := results.GetResult(ctx, job.JobID, &GetResultOptions{Format: ResultFormatJSONL}, reqOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewResultDecoder(body, columns), nil
+}
+
+// Columns returns the column names used for array rows
+func (d *ResultDecoder) Columns() []string {
+	return d.columns
+}
+
+// Next reads the next row and reports whether there is one. Check Err once
+// it returns false.
+func (d *ResultDecoder) Next() bool {
+	if d.err != nil {
+		return false
+	}
+
+	var value interface{}
+	if err := d.dec.Decode(&value); err != nil {
+		if err != io.EOF {
+			d.err = err
+		}
+		d.row = nil
+		return false
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		d.row = v
+	case []interface{}:
+		if len(d.columns) == 0 {
+			d.err = errors.New("result rows are arrays but the result columns are unknown")
+			return false
+		}
+		d.row = make(map[string]interface{}, len(v))
+		for i, column := range d.columns {
+			if i < len(v) {
+				d.row[column] = v[i]
+			}
+		}
+	default:
+		d.err = fmt.Errorf("unexpected result row %v", value)
+		return false
+	}
+	return true
+}
+
+// Row returns the current row keyed by column name. Numbers are json.Number.
+func (d *ResultDecoder) Row() map[string]interface{} {
+	return d.row
+}
+
+// ScanStruct copies the current row into the struct dst points to. Fields
+// are matched with the column named by their `td:"column"` tag, or by a
+// case-insensitive match of the field name; `td:"-"` skips a field. Values
+// are converted to the field type: numbers and numeric strings to ints,
+// uints and floats, numbers and strings to bool, Unix seconds and
+// timestamp strings to time.Time and TDTime, and JSON values to slices,
+// maps and structs. Null leaves a field at its zero value, or nil for a
+// pointer. Columns without a field are ignored.
+func (d *ResultDecoder) ScanStruct(dst interface{}) error {
+	if d.row == nil {
+		return errors.New("ScanStruct called without a current row")
+	}
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("ScanStruct needs a non-nil pointer to a struct, got %T", dst)
+	}
+	v = v.Elem()
+
+	for _, field := range resultFields(v.Type()) {
+		value, ok := d.row[field.column]
+		if !ok {
+			for column, columnValue := range d.row {
+				if strings.EqualFold(column, field.column) {
+					value, ok = columnValue, true
+					break
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+		if err := setResultValue(v.FieldByIndex(field.index), value); err != nil {
+			return fmt.Errorf("column %q into field %s: %w", field.column, field.name, err)
+		}
+	}
+	return nil
+}
+
+// Err returns the first error met while reading rows
+func (d *ResultDecoder) Err() error {
+	return d.err
+}
+
+// Close closes the underlying reader
+func (d *ResultDecoder) Close() error {
+	if d.closer == nil {
+		return nil
+	}
+	return d.closer.Close()
+}
+
+// resultField is a struct field that receives a column
+type resultField struct {
+	name   string
+	column string
+	index  []int
+}
+
+var resultFieldCache sync.Map // reflect.Type -> []resultField
+
+// resultFields lists the exported fields of t that columns map to,
+// including those of embedded structs
+func resultFields(t reflect.Type) []resultField {
+	if cached, ok := resultFieldCache.Load(t); ok {
+		return cached.([]resultField)
+	}
+
+	var fields []resultField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("td")
+		if tag == "-" || !f.IsExported() {
+			continue
+		}
+		if f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct && !isResultTime(f.Type) {
+			for _, inner := range resultFields(f.Type) {
+				inner.index = append([]int{i}, inner.index...)
+				fields = append(fields, inner)
+			}
+			continue
+		}
+		column := tag
+		if column == "" {
+			column = f.Name
+		}
+		fields = append(fields, resultField{name: f.Name, column: column, index: []int{i}})
+	}
+
+	resultFieldCache.Store(t, fields)
+	return fields
+}
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	tdTimeType = reflect.TypeOf(TDTime{})
+)
+
+func isResultTime(t reflect.Type) bool {
+	return t == timeType || t == tdTimeType
+}
+
+// setResultValue converts a decoded JSON value to the type of field
+func setResultValue(field reflect.Value, value interface{}) error {
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	if field.Kind() == reflect.Pointer {
+		elem := reflect.New(field.Type().Elem())
+		if err := setResultValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	switch field.Type() {
+	case timeType, tdTimeType:
+		t, err := parseResultTime(value)
+		if err != nil {
+			return err
+		}
+		if field.Type() == tdTimeType {
+			field.Set(reflect.ValueOf(TDTime{Time: t}))
+		} else {
+			field.Set(reflect.ValueOf(t))
+		}
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		switch v := value.(type) {
+		case string:
+			field.SetString(v)
+		case json.Number:
+			field.SetString(v.String())
+		case bool:
+			field.SetString(strconv.FormatBool(v))
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			field.SetString(string(data))
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := resultNumber(value)
+		if err != nil {
+			return err
+		}
+		i, err := strconv.ParseInt(n, 10, 64)
+		if err != nil {
+			f, ferr := strconv.ParseFloat(n, 64)
+			if ferr != nil || f != math.Trunc(f) || f < math.MinInt64 || f > math.MaxInt64 {
+				return fmt.Errorf("cannot convert %v to %s", value, field.Type())
+			}
+			i = int64(f)
+		}
+		if field.OverflowInt(i) {
+			return fmt.Errorf("%v overflows %s", value, field.Type())
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := resultNumber(value)
+		if err != nil {
+			return err
+		}
+		u, err := strconv.ParseUint(n, 10, 64)
+		if err != nil {
+			return fmt.Errorf("cannot convert %v to %s", value, field.Type())
+		}
+		if field.OverflowUint(u) {
+			return fmt.Errorf("%v overflows %s", value, field.Type())
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		n, err := resultNumber(value)
+		if err != nil {
+			return err
+		}
+		f, err := strconv.ParseFloat(n, 64)
+		if err != nil {
+			return fmt.Errorf("cannot convert %v to %s", value, field.Type())
+		}
+		if field.OverflowFloat(f) {
+			return fmt.Errorf("%v overflows %s", value, field.Type())
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		switch v := value.(type) {
+		case bool:
+			field.SetBool(v)
+		case json.Number:
+			field.SetBool(v.String() != "0")
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("cannot convert %q to bool", v)
+			}
+			field.SetBool(b)
+		default:
+			return fmt.Errorf("cannot convert %v to bool", value)
+		}
+	default:
+		// Arrays, maps and structs arrive as JSON values, or as JSON text
+		// in a string column
+		data, ok := value.(string)
+		raw := []byte(data)
+		if !ok {
+			var err error
+			if raw, err = json.Marshal(value); err != nil {
+				return err
+			}
+		}
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		if err := dec.Decode(field.Addr().Interface()); err != nil {
+			return fmt.Errorf("cannot convert %v to %s: %w", value, field.Type(), err)
+		}
+	}
+	return nil
+}
+
+// resultNumber returns the text of a number or numeric string
+func resultNumber(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case json.Number:
+		return v.String(), nil
+	case string:
+		return strings.TrimSpace(v), nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+		return "0", nil
+	}
+	return "", fmt.Errorf("cannot convert %v to a number", value)
+}
+
+// resultTimeLayouts are the timestamp formats of Hive and Trino results, in
+// addition to those TDTime accepts
+var resultTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999 MST",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// parseResultTime reads Unix seconds or a timestamp string
+func parseResultTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return time.Unix(i, 0).UTC(), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return time.Time{}, err
+		}
+		sec, frac := math.Modf(f)
+		return time.Unix(int64(sec), int64(frac*1e9)).UTC(), nil
+	case string:
+		var t TDTime
+		data, _ := json.Marshal(v)
+		if err := t.UnmarshalJSON(data); err == nil {
+			return t.Time, nil
+		}
+		for _, layout := range resultTimeLayouts {
+			if parsed, err := time.Parse(layout, v); err == nil {
+				return parsed, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("cannot parse %q as a time", v)
+	}
+	return time.Time{}, fmt.Errorf("cannot convert %v to a time", value)
+}
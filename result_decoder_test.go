@@ -0,0 +1,166 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type resultRow struct {
+	UserID   int64     `td:"user_id"`
+	Name     string    `td:"name"`
+	Amount   float64   `td:"amount"`
+	Active   bool      `td:"active"`
+	SignedUp TDTime    `td:"signed_up"`
+	Seen     time.Time `td:"last_seen"`
+	Tags     []string  `td:"tags"`
+	Score    *int      `td:"score"`
+	Country  string
+	Ignored  string `td:"-"`
+}
+
+func TestResultDecoder_ScanStruct(t *testing.T) {
+	input := `{"user_id": "42", "name": "Alice", "amount": 12, "active": "true", "signed_up": 1700000000, "last_seen": "2024-05-01 10:20:30.123", "tags": "[\"a\",\"b\"]", "score": null, "COUNTRY": "JP", "ignored": "x", "extra": 1}
+{"user_id": 7, "name": null, "amount": "1.5", "active": 0, "signed_up": "2024-01-02 03:04:05 UTC", "last_seen": 1714558830, "tags": ["c"], "score": 3}
+`
+	dec := NewResultDecoder(strings.NewReader(input), nil)
+
+	var rows []resultRow
+	for dec.Next() {
+		var row resultRow
+		if err := dec.ScanStruct(&row); err != nil {
+			t.Fatalf("ScanStruct: %v", err)
+		}
+		rows = append(rows, row)
+	}
+	if err := dec.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows", len(rows))
+	}
+
+	first := rows[0]
+	if first.UserID != 42 || first.Name != "Alice" || first.Amount != 12 || !first.Active || first.Country != "JP" || first.Ignored != "" {
+		t.Errorf("first = %+v", first)
+	}
+	if first.SignedUp.Unix() != 1700000000 || first.Seen.Format("15:04:05.000") != "10:20:30.123" {
+		t.Errorf("times = %v, %v", first.SignedUp, first.Seen)
+	}
+	if len(first.Tags) != 2 || first.Score != nil {
+		t.Errorf("tags = %v, score = %v", first.Tags, first.Score)
+	}
+
+	second := rows[1]
+	if second.Name != "" || second.Amount != 1.5 || second.Active || second.Score == nil || *second.Score != 3 || second.SignedUp.Year() != 2024 {
+		t.Errorf("second = %+v", second)
+	}
+}
+
+func TestResultDecoder_ArrayRows(t *testing.T) {
+	columns := []TableColumn{{Name: "id", Type: "bigint"}, {Name: "name", Type: "varchar"}}
+	dec := NewResultDecoder(strings.NewReader("[1, \"a\"]\n[2, \"b\"]\n"), columns)
+
+	var got []string
+	for dec.Next() {
+		var row struct {
+			ID   int    `td:"id"`
+			Name string `td:"name"`
+		}
+		if err := dec.ScanStruct(&row); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, fmt.Sprintf("%d:%s", row.ID, row.Name))
+	}
+	if strings.Join(got, ",") != "1:a,2:b" {
+		t.Errorf("rows = %v, err = %v", got, dec.Err())
+	}
+}
+
+func TestResultDecoder_ConversionErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		dst   interface{}
+	}{
+		{`{"n": 1.5}`, &struct{ N int }{}},
+		{`{"n": 300}`, &struct{ N int8 }{}},
+		{`{"n": -1}`, &struct{ N uint }{}},
+		{`{"n": "yes please"}`, &struct{ N bool }{}},
+		{`{"n": "tomorrow"}`, &struct{ N time.Time }{}},
+	}
+	for _, tt := range tests {
+		dec := NewResultDecoder(strings.NewReader(tt.input), nil)
+		if !dec.Next() {
+			t.Fatalf("%s: no row", tt.input)
+		}
+		if err := dec.ScanStruct(tt.dst); err == nil {
+			t.Errorf("%s into %T: no error", tt.input, tt.dst)
+		}
+	}
+
+	dec := NewResultDecoder(strings.NewReader("[1]"), nil)
+	if dec.Next() || dec.Err() == nil {
+		t.Error("array rows without columns should fail")
+	}
+}
+
+func TestResultsService_Decoder(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/job/show/123", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"job_id": "123", "status": "success", "hive_result_schema": "[[\"cnt\",\"bigint\"]]"}`)
+	})
+	mux.HandleFunc("/v3/job/result/123", func(w http.ResponseWriter, r *http.Request) {
+		testURL(t, r, "/v3/job/result/123?format=jsonl")
+		fmt.Fprint(w, "[10]\n")
+	})
+
+	dec, err := client.Results.Decoder(context.Background(), "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dec.Close()
+
+	var row struct {
+		Count int `td:"cnt"`
+	}
+	if !dec.Next() {
+		t.Fatalf("no row: %v", dec.Err())
+	}
+	if err := dec.ScanStruct(&row); err != nil || row.Count != 10 {
+		t.Errorf("row = %+v, err = %v", row, err)
+	}
+}
+
+func BenchmarkResultDecoder_ScanStruct(b *testing.B) {
+	columns := []TableColumn{
+		{Name: "user_id", Type: "bigint"}, {Name: "name", Type: "varchar"}, {Name: "amount", Type: "double"},
+		{Name: "active", Type: "boolean"}, {Name: "signed_up", Type: "bigint"}, {Name: "last_seen", Type: "varchar"},
+		{Name: "tags", Type: "array(varchar)"}, {Name: "score", Type: "integer"}, {Name: "country", Type: "varchar"},
+	}
+	var input strings.Builder
+	for i := 0; i < 10000; i++ {
+		fmt.Fprintf(&input, `[%d, "user %d", %d.25, true, 1700000000, "2024-05-01 10:20:30.123", ["a", "b"], %d, "JP"]`+"\n", i, i, i, i%100)
+	}
+	data := input.String()
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dec := NewResultDecoder(strings.NewReader(data), columns)
+		for dec.Next() {
+			var row resultRow
+			if err := dec.ScanStruct(&row); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if err := dec.Err(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+// Package resultconv converts Treasure Data job results to Apache Arrow
+// record batches and Parquet files without a CSV round trip.
+//
+//	rr, err := resultconv.NewJobRecordReader(ctx, client, jobID, resultconv.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	defer rr.Release()
+//	rows, err := resultconv.WriteParquet(f, rr, nil)
+//
+// Result columns are typed from the job's result schema: booleans, integers,
+// floating point numbers and dates get the matching Arrow type, and every
+// other type, including decimals, timestamps and nested types, is kept as a
+// string. Nested values are written as JSON.
+package resultconv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// DefaultBatchSize is the number of rows in a record batch when
+// Options.BatchSize is not set
+const DefaultBatchSize = 10000
+
+// Options customizes a RecordReader
+type Options struct {
+	// BatchSize is the maximum number of rows per record batch
+	BatchSize int
+	// Allocator allocates the record batches; defaults to
+	// memory.DefaultAllocator
+	Allocator memory.Allocator
+}
+
+// ArrowType returns the Arrow type a result column of the given Hive or
+// Trino type is converted to
+func ArrowType(tdType string) arrow.DataType {
+	base := strings.ToLower(strings.TrimSpace(tdType))
+	if i := strings.IndexAny(base, "(<"); i >= 0 {
+		base = strings.TrimSpace(base[:i])
+	}
+	switch base {
+	case "boolean":
+		return arrow.FixedWidthTypes.Boolean
+	case "tinyint", "smallint", "int", "integer", "bigint", "long":
+		return arrow.PrimitiveTypes.Int64
+	case "real", "float", "double":
+		return arrow.PrimitiveTypes.Float64
+	case "date":
+		return arrow.FixedWidthTypes.Date32
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
+
+// Schema returns the Arrow schema of a job result. Every field is nullable.
+func Schema(columns []td.TableColumn) *arrow.Schema {
+	fields := make([]arrow.Field, len(columns))
+	for i, column := range columns {
+		fields[i] = arrow.Field{
+			Name:     column.Name,
+			Type:     ArrowType(column.Type),
+			Nullable: true,
+			Metadata: arrow.NewMetadata([]string{"td_type"}, []string{column.Type}),
+		}
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+// RecordReader reads a job result as Arrow record batches. It implements
+// array.RecordReader.
+type RecordReader struct {
+	refCount  int64
+	dec       *td.ResultDecoder
+	schema    *arrow.Schema
+	builder   *array.RecordBuilder
+	batchSize int
+
+	rec  arrow.Record
+	rows int64
+	err  error
+}
+
+var _ array.RecordReader = (*RecordReader)(nil)
+
+// NewRecordReader reads the rows of dec in batches typed by columns, the
+// job's result schema. The reader closes dec when it is released.
+func NewRecordReader(dec *td.ResultDecoder, columns []td.TableColumn, opts Options) (*RecordReader, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("result schema has no columns")
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	mem := opts.Allocator
+	if mem == nil {
+		mem = memory.DefaultAllocator
+	}
+
+	schema := Schema(columns)
+	return &RecordReader{
+		refCount:  1,
+		dec:       dec,
+		schema:    schema,
+		builder:   array.NewRecordBuilder(mem, schema),
+		batchSize: batchSize,
+	}, nil
+}
+
+// NewJobRecordReader streams the result of a finished job as record batches
+func NewJobRecordReader(ctx context.Context, client *td.Client, jobID string, opts Options) (*RecordReader, error) {
+	job, err := client.Jobs.Get(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := job.ResultSchema()
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("job %s has no result schema", jobID)
+	}
+
+	body, err := client.Results.GetResult(ctx, jobID, &td.GetResultOptions{Format: td.ResultFormatJSONL})
+	if err != nil {
+		return nil, err
+	}
+	return NewRecordReader(td.NewResultDecoder(body, columns), columns, opts)
+}
+
+// Retain increases the reference count of the reader
+func (r *RecordReader) Retain() {
+	atomic.AddInt64(&r.refCount, 1)
+}
+
+// Release decreases the reference count of the reader. At zero it frees the
+// current batch and closes the result stream.
+func (r *RecordReader) Release() {
+	if atomic.AddInt64(&r.refCount, -1) != 0 {
+		return
+	}
+	if r.rec != nil {
+		r.rec.Release()
+		r.rec = nil
+	}
+	r.builder.Release()
+	r.dec.Close()
+}
+
+// Schema returns the Arrow schema of the result
+func (r *RecordReader) Schema() *arrow.Schema {
+	return r.schema
+}
+
+// Next reads the next batch of up to BatchSize rows and reports whether there
+// is one. Check Err once it returns false.
+func (r *RecordReader) Next() bool {
+	if r.rec != nil {
+		r.rec.Release()
+		r.rec = nil
+	}
+	if r.err != nil {
+		return false
+	}
+
+	n := 0
+	for n < r.batchSize && r.dec.Next() {
+		r.rows++
+		if err := r.appendRow(r.dec.Row()); err != nil {
+			r.err = fmt.Errorf("row %d: %w", r.rows, err)
+			return false
+		}
+		n++
+	}
+	if err := r.dec.Err(); err != nil {
+		r.err = err
+		return false
+	}
+	if n == 0 {
+		return false
+	}
+	r.rec = r.builder.NewRecord()
+	return true
+}
+
+// Record returns the current batch. It is valid until the next call to Next;
+// retain it to keep it longer.
+func (r *RecordReader) Record() arrow.Record {
+	return r.rec
+}
+
+// Err returns the error that stopped Next, if any
+func (r *RecordReader) Err() error {
+	return r.err
+}
+
+// appendRow appends the values of row to the builders of each column
+func (r *RecordReader) appendRow(row map[string]interface{}) error {
+	for i, field := range r.schema.Fields() {
+		if err := appendValue(r.builder.Field(i), row[field.Name]); err != nil {
+			return fmt.Errorf("column %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+func appendValue(b array.Builder, value interface{}) error {
+	if value == nil {
+		b.AppendNull()
+		return nil
+	}
+
+	switch b := b.(type) {
+	case *array.BooleanBuilder:
+		switch v := value.(type) {
+		case bool:
+			b.Append(v)
+		case string:
+			parsed, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("invalid boolean %q", v)
+			}
+			b.Append(parsed)
+		default:
+			return fmt.Errorf("invalid boolean %v", value)
+		}
+	case *array.Int64Builder:
+		i, err := strconv.ParseInt(numberString(value), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %v", value)
+		}
+		b.Append(i)
+	case *array.Float64Builder:
+		f, err := strconv.ParseFloat(numberString(value), 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %v", value)
+		}
+		b.Append(f)
+	case *array.Date32Builder:
+		s, _ := value.(string)
+		t, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			return fmt.Errorf("invalid date %v", value)
+		}
+		b.Append(arrow.Date32FromTime(t))
+	case *array.StringBuilder:
+		switch v := value.(type) {
+		case string:
+			b.Append(v)
+		case json.Number:
+			b.Append(v.String())
+		case bool:
+			b.Append(strconv.FormatBool(v))
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			b.Append(string(data))
+		}
+	default:
+		return fmt.Errorf("unsupported builder %T", b)
+	}
+	return nil
+}
+
+// numberString returns a number from a result row as a string; numbers are
+// json.Number and Trino writes NaN and Infinity as strings
+func numberString(value interface{}) string {
+	switch v := value.(type) {
+	case json.Number:
+		return v.String()
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// WriteParquet writes every batch of rr to w as a Parquet file and returns
+// the number of rows written. A nil props uses the Parquet defaults.
+func WriteParquet(w io.Writer, rr array.RecordReader, props *parquet.WriterProperties) (int64, error) {
+	// Hide any Close method: the Parquet writer would close w, which belongs
+	// to the caller
+	fw, err := pqarrow.NewFileWriter(rr.Schema(), struct{ io.Writer }{w}, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return 0, err
+	}
+
+	var rows int64
+	for rr.Next() {
+		rec := rr.Record()
+		if err := fw.Write(rec); err != nil {
+			fw.Close()
+			return rows, err
+		}
+		rows += rec.NumRows()
+	}
+	if err := rr.Err(); err != nil {
+		fw.Close()
+		return rows, err
+	}
+	return rows, fw.Close()
+}
+
+// WriteJobParquet writes the result of a finished job to w as a Parquet file
+// and returns the number of rows written
+func WriteJobParquet(ctx context.Context, client *td.Client, jobID string, w io.Writer, props *parquet.WriterProperties) (int64, error) {
+	rr, err := NewJobRecordReader(ctx, client, jobID, Options{})
+	if err != nil {
+		return 0, err
+	}
+	defer rr.Release()
+	return WriteParquet(w, rr, props)
+}
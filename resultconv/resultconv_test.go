@@ -0,0 +1,143 @@
+package resultconv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+var testColumns = []td.TableColumn{
+	{Name: "id", Type: "bigint"},
+	{Name: "score", Type: "double"},
+	{Name: "ok", Type: "boolean"},
+	{Name: "day", Type: "date"},
+	{Name: "name", Type: "varchar"},
+	{Name: "tags", Type: "array(varchar)"},
+}
+
+const testRows = `[1, 0.5, true, "2025-01-02", "a", ["x", "y"]]
+[2, "NaN", false, null, "b", null]
+[null, 3, null, "2025-01-03", null, []]
+`
+
+func TestArrowType(t *testing.T) {
+	tests := map[string]arrow.DataType{
+		"bigint":               arrow.PrimitiveTypes.Int64,
+		"INT":                  arrow.PrimitiveTypes.Int64,
+		"double":               arrow.PrimitiveTypes.Float64,
+		"boolean":              arrow.FixedWidthTypes.Boolean,
+		"date":                 arrow.FixedWidthTypes.Date32,
+		"decimal(10,2)":        arrow.BinaryTypes.String,
+		"timestamp(3)":         arrow.BinaryTypes.String,
+		"map<string,string>":   arrow.BinaryTypes.String,
+		"row(a bigint)":        arrow.BinaryTypes.String,
+		"varchar(255)":         arrow.BinaryTypes.String,
+		"something_unexpected": arrow.BinaryTypes.String,
+	}
+	for tdType, want := range tests {
+		if got := ArrowType(tdType); !arrow.TypeEqual(got, want) {
+			t.Errorf("ArrowType(%q) = %s, want %s", tdType, got, want)
+		}
+	}
+}
+
+func TestRecordReader(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	rr, err := NewRecordReader(td.NewResultDecoder(strings.NewReader(testRows), testColumns), testColumns, Options{BatchSize: 2, Allocator: mem})
+	if err != nil {
+		t.Fatalf("NewRecordReader returned error: %v", err)
+	}
+	defer rr.Release()
+
+	var sizes []int64
+	var got []string
+	for rr.Next() {
+		rec := rr.Record()
+		sizes = append(sizes, rec.NumRows())
+		ids := rec.Column(0).(*array.Int64)
+		tags := rec.Column(5).(*array.String)
+		for i := 0; i < int(rec.NumRows()); i++ {
+			got = append(got, fmt.Sprintf("%s/%s", ids.ValueStr(i), tags.ValueStr(i)))
+		}
+	}
+	if err := rr.Err(); err != nil {
+		t.Fatalf("Err = %v", err)
+	}
+	if fmt.Sprint(sizes) != "[2 1]" {
+		t.Errorf("batch sizes = %v, want [2 1]", sizes)
+	}
+	if want := `[1/["x","y"] 2/(null) (null)/[]]`; fmt.Sprint(got) != want {
+		t.Errorf("rows = %v, want %s", got, want)
+	}
+}
+
+func TestRecordReader_InvalidValue(t *testing.T) {
+	rows := `[1, 0.5, true, "2025-01-02", "a", null]` + "\n" + `["x", 0.5, true, null, "a", null]` + "\n"
+	rr, err := NewRecordReader(td.NewResultDecoder(strings.NewReader(rows), testColumns), testColumns, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rr.Release()
+
+	if rr.Next() {
+		t.Fatal("Expected Next to fail on an invalid integer")
+	}
+	if err := rr.Err(); err == nil || !strings.Contains(err.Error(), "row 2: column id") {
+		t.Errorf("Err = %v", err)
+	}
+}
+
+func TestWriteJobParquet(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/job/show/42", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"job_id":"42","status":"success","hive_result_schema":"[[\"id\",\"bigint\"],[\"score\",\"double\"],[\"ok\",\"boolean\"],[\"day\",\"date\"],[\"name\",\"varchar\"],[\"tags\",\"array(varchar)\"]]"}`)
+	})
+	mux.HandleFunc("/v3/job/result/42", func(w http.ResponseWriter, r *http.Request) {
+		if format := r.URL.Query().Get("format"); format != "jsonl" {
+			t.Errorf("format = %s, want jsonl", format)
+		}
+		fmt.Fprint(w, testRows)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := td.NewClient("1/test", td.WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	rows, err := WriteJobParquet(context.Background(), client, "42", &buf, nil)
+	if err != nil {
+		t.Fatalf("WriteJobParquet returned error: %v", err)
+	}
+	if rows != 3 {
+		t.Errorf("rows = %d, want 3", rows)
+	}
+
+	table, err := pqarrow.ReadTable(context.Background(), bytes.NewReader(buf.Bytes()), parquet.NewReaderProperties(nil), pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		t.Fatalf("reading the Parquet file: %v", err)
+	}
+	defer table.Release()
+	if table.NumRows() != 3 || table.NumCols() != int64(len(testColumns)) {
+		t.Errorf("table is %d x %d", table.NumRows(), table.NumCols())
+	}
+	if got := table.Schema().Field(3).Type; !arrow.TypeEqual(got, arrow.FixedWidthTypes.Date32) {
+		t.Errorf("day column type = %s, want date32", got)
+	}
+}
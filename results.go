@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 )
 
 // ResultsService handles communication with the result related methods of the Treasure Data API.
@@ -27,19 +28,66 @@ const (
 	ResultFormatJSONL ResultFormat = "jsonl"
 	// ResultFormatMessagePack returns results in MessagePack format
 	ResultFormatMessagePack ResultFormat = "msgpack"
+	// ResultFormatMessagePackGzip returns results in gzip-compressed
+	// MessagePack format, the most compact for large downloads
+	ResultFormatMessagePackGzip ResultFormat = "msgpack.gz"
 )
 
+// ResultFormats lists every format the result API serves
+var ResultFormats = []ResultFormat{
+	ResultFormatJSON,
+	ResultFormatJSONL,
+	ResultFormatCSV,
+	ResultFormatTSV,
+	ResultFormatMessagePack,
+	ResultFormatMessagePackGzip,
+}
+
+// ParseResultFormat returns the ResultFormat named s
+func ParseResultFormat(s string) (ResultFormat, error) {
+	f := ResultFormat(strings.ToLower(strings.TrimSpace(s)))
+	if !f.Valid() {
+		names := make([]string, len(ResultFormats))
+		for i, format := range ResultFormats {
+			names[i] = string(format)
+		}
+		return "", fmt.Errorf("unknown result format %q (valid: %s)", s, strings.Join(names, ", "))
+	}
+	return f, nil
+}
+
+// Valid reports whether the result API serves f
+func (f ResultFormat) Valid() bool {
+	for _, format := range ResultFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Binary reports whether results in f are not text
+func (f ResultFormat) Binary() bool {
+	return f == ResultFormatMessagePack || f == ResultFormatMessagePackGzip
+}
+
 // GetResultOptions represents options for retrieving job results
 type GetResultOptions struct {
 	Format ResultFormat `url:"format,omitempty"`
 	Limit  int          `url:"limit,omitempty"`
 }
 
-// GetResult retrieves the results of a completed job
-func (s *ResultsService) GetResult(ctx context.Context, jobID string, opts *GetResultOptions) (io.ReadCloser, error) {
+// GetResult retrieves the results of a completed job in opts.Format, or the
+// server's default format if unset. The body is returned as served, so
+// msgpack.gz results stay compressed.
+func (s *ResultsService) GetResult(ctx context.Context, jobID string, opts *GetResultOptions, reqOpts ...RequestOption) (io.ReadCloser, error) {
 	u := fmt.Sprintf("%s/job/result/%s", apiVersion, jobID)
 
 	if opts != nil {
+		if opts.Format != "" && !opts.Format.Valid() {
+			_, err := ParseResultFormat(string(opts.Format))
+			return nil, err
+		}
 		var err error
 		u, err = addOptions(u, opts)
 		if err != nil {
@@ -52,24 +100,29 @@ func (s *ResultsService) GetResult(ctx context.Context, jobID string, opts *GetR
 		return nil, err
 	}
 
-	resp, err := s.client.httpClient.Do(req.WithContext(ctx))
+	// The request context must outlive this call while the body is read
+	ctx, cancel := requestContext(ctx, reqOpts)
+	resp, err := s.client.send(ctx, req)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
 	if err := CheckResponse(resp); err != nil {
 		resp.Body.Close()
+		cancel()
 		return nil, err
 	}
 
-	return resp.Body, nil
+	body := withProgress(resp.Body, 0, resp.ContentLength, newRequestOptions(reqOpts).progress)
+	return &cancelOnClose{ReadCloser: body, cancel: cancel}, nil
 }
 
 // GetResultJSON retrieves job results and decodes them as JSON
-func (s *ResultsService) GetResultJSON(ctx context.Context, jobID string, v interface{}) error {
+func (s *ResultsService) GetResultJSON(ctx context.Context, jobID string, v interface{}, reqOpts ...RequestOption) error {
 	opts := &GetResultOptions{Format: ResultFormatJSON}
 
-	body, err := s.GetResult(ctx, jobID, opts)
+	body, err := s.GetResult(ctx, jobID, opts, reqOpts...)
 	if err != nil {
 		return err
 	}
@@ -79,10 +132,10 @@ func (s *ResultsService) GetResultJSON(ctx context.Context, jobID string, v inte
 }
 
 // GetResultJSONL retrieves job results in JSONL format and returns a scanner
-func (s *ResultsService) GetResultJSONL(ctx context.Context, jobID string) (*JSONLScanner, error) {
+func (s *ResultsService) GetResultJSONL(ctx context.Context, jobID string, reqOpts ...RequestOption) (*JSONLScanner, error) {
 	opts := &GetResultOptions{Format: ResultFormatJSONL}
 
-	body, err := s.GetResult(ctx, jobID, opts)
+	body, err := s.GetResult(ctx, jobID, opts, reqOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -161,6 +214,50 @@ func (s *ResultsService) ListResults(ctx context.Context) ([]Result, error) {
 	return resp.Results, nil
 }
 
+// GetResultConnection returns the result configuration named name. The API
+// has no endpoint for a single result, so it is looked up in the list.
+func (s *ResultsService) GetResultConnection(ctx context.Context, name string) (*Result, error) {
+	results, err := s.ListResults(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		if results[i].Name == name {
+			return &results[i], nil
+		}
+	}
+	return nil, fmt.Errorf("result %s: %w", name, ErrNotFound)
+}
+
+// CreateResultOutput saves a typed destination such as ResultToS3 as a
+// result configuration, which queries can then write to by name with
+// ResultToConnection
+func (s *ResultsService) CreateResultOutput(ctx context.Context, name string, out ResultOutput) (*Result, error) {
+	if _, ok := out.(ResultToConnection); ok {
+		return nil, fmt.Errorf("a result connection cannot be saved as another result connection")
+	}
+	if err := validateResultName(name); err != nil {
+		return nil, err
+	}
+	resultURL, err := out.ResultURL()
+	if err != nil {
+		return nil, err
+	}
+	return s.CreateResult(ctx, name, resultURL, nil)
+}
+
+// validateResultName checks that name can be used both in the result API
+// path and as the Result of a query
+func validateResultName(name string) error {
+	if name == "" {
+		return NewValidationError("name", name, "cannot be empty")
+	}
+	if _, err := (ResultToConnection{Name: name}).ResultURL(); err != nil {
+		return NewValidationError("name", name, "cannot contain ':', '/' or '?'")
+	}
+	return nil
+}
+
 // CreateResult creates a new result configuration
 func (s *ResultsService) CreateResult(ctx context.Context, name, url string, settings map[string]interface{}) (*Result, error) {
 	u := fmt.Sprintf("%s/result/create/%s", apiVersion, name)
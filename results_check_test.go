@@ -0,0 +1,69 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResultsService_TestConnection(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/result/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"results": [
+			{"name": "to_td", "url": "td://@/reports/daily?mode=replace"},
+			{"name": "to_missing_db", "url": "td://@/nope/daily"},
+			{"name": "bad_update", "url": "td://@/reports/daily?mode=update"},
+			{"name": "s3_no_secret", "url": "s3://AKID@/bucket/key.csv", "type": "s3"},
+			{"name": "pg", "url": "postgresql://etl:pw@db.example.com/analytics/daily?mode=update&unique=id"},
+			{"name": "pg_broken", "url": "postgresql:///analytics"},
+			{"name": "sheets", "url": "gspreadsheet://sheet"},
+			{"name": "bq", "url": "{\"type\":\"bigquery\",\"project\":\"p\"}"},
+			{"name": "bad_connector", "url": "{\"project\":\"p\"}"}
+		]}`)
+	})
+	mux.HandleFunc("/v3/database/show/reports", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "reports"}`)
+	})
+	mux.HandleFunc("/v3/database/show/nope", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+	})
+
+	tests := []struct {
+		name     string
+		problems string
+	}{
+		{"to_td", ""},
+		{"to_missing_db", "database nope does not exist"},
+		{"bad_update", "mode update needs unique_key"},
+		{"s3_no_secret", "no secret access key"},
+		{"pg", ""},
+		{"pg_broken", `no host; no user; path "/analytics" is not /database/table`},
+		{"sheets", ""},
+		{"bq", ""},
+		{"bad_connector", "connector settings have no type"},
+	}
+	for _, tt := range tests {
+		check, err := client.Results.TestConnection(context.Background(), tt.name)
+		if err != nil {
+			t.Errorf("%s: TestConnection returned error: %v", tt.name, err)
+			continue
+		}
+		if got := strings.Join(check.Problems, "; "); got != tt.problems {
+			t.Errorf("%s: problems = %q, want %q", tt.name, got, tt.problems)
+		}
+		if check.OK() != (tt.problems == "") {
+			t.Errorf("%s: OK = %t", tt.name, check.OK())
+		}
+		if len(check.Notes) == 0 && check.OK() {
+			t.Errorf("%s: expected notes on what was checked", tt.name)
+		}
+	}
+
+	if _, err := client.Results.TestConnection(context.Background(), "missing"); !IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
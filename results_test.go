@@ -422,6 +422,75 @@ func TestResultsService_ListResults(t *testing.T) {
 	}
 }
 
+func TestResultsService_GetResultConnection(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/result/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"results": [{"name": "a", "url": "td://@/db/a"}, {"name": "b", "url": "td://@/db/b"}]}`)
+	})
+
+	result, err := client.Results.GetResultConnection(context.Background(), "b")
+	if err != nil {
+		t.Fatalf("Results.GetResultConnection returned error: %v", err)
+	}
+	if result.URL != "td://@/db/b" {
+		t.Errorf("URL = %s, want td://@/db/b", result.URL)
+	}
+
+	if _, err := client.Results.GetResultConnection(context.Background(), "missing"); !IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+func TestResultsService_CreateResultOutput(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/result/create/daily_s3", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if want := "s3://AKID:se%2Fcret@/bucket/out.csv"; body["url"] != want {
+			t.Errorf("url = %v, want %s", body["url"], want)
+		}
+		fmt.Fprint(w, `{"name": "daily_s3"}`)
+	})
+
+	out := ResultToS3{AccessKeyID: "AKID", SecretAccessKey: "se/cret", Bucket: "bucket", Path: "out.csv"}
+	result, err := client.Results.CreateResultOutput(context.Background(), "daily_s3", out)
+	if err != nil {
+		t.Fatalf("Results.CreateResultOutput returned error: %v", err)
+	}
+	if result.Name != "daily_s3" {
+		t.Errorf("Name = %s, want daily_s3", result.Name)
+	}
+
+	if _, err := client.Results.CreateResultOutput(context.Background(), "copy", ResultToConnection{Name: "daily_s3"}); err == nil {
+		t.Error("Expected error for a result connection")
+	}
+	if _, err := client.Results.CreateResultOutput(context.Background(), "a/b", out); err == nil {
+		t.Error("Expected error for a name with a slash")
+	}
+	if _, err := client.Results.CreateResultOutput(context.Background(), "broken", ResultToS3{}); err == nil {
+		t.Error("Expected error for an incomplete destination")
+	}
+}
+
+func TestResultsService_DeleteResult(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/result/delete/daily_s3", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, `{"name": "daily_s3"}`)
+	})
+
+	if err := client.Results.DeleteResult(context.Background(), "daily_s3"); err != nil {
+		t.Errorf("Results.DeleteResult returned error: %v", err)
+	}
+}
+
 func TestResultsService_ListResults_Empty(t *testing.T) {
 	client, mux, teardown := setup()
 	defer teardown()
@@ -476,6 +545,7 @@ func TestResultsService_GetResult_AllFormats(t *testing.T) {
 		{ResultFormatTSV, "id\t1", "text/tab-separated-values"},
 		{ResultFormatJSONL, `{"id":1}`, "application/jsonl"},
 		{ResultFormatMessagePack, "msgpack_data", "application/msgpack"},
+		{ResultFormatMessagePackGzip, "\x1f\x8bmsgpack_data", "application/octet-stream"},
 	}
 
 	for _, tt := range formats {
@@ -512,6 +582,31 @@ func TestResultsService_GetResult_AllFormats(t *testing.T) {
 	}
 }
 
+func TestParseResultFormat(t *testing.T) {
+	for _, format := range ResultFormats {
+		got, err := ParseResultFormat(strings.ToUpper(string(format)))
+		if err != nil || got != format {
+			t.Errorf("ParseResultFormat(%q) = %q, %v", format, got, err)
+		}
+	}
+	if _, err := ParseResultFormat("xml"); err == nil {
+		t.Error("ParseResultFormat accepted xml")
+	}
+	if !ResultFormatMessagePackGzip.Binary() || ResultFormatTSV.Binary() {
+		t.Error("Binary misreports formats")
+	}
+}
+
+func TestResultsService_GetResult_InvalidFormat(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Results.GetResult(context.Background(), "123456", &GetResultOptions{Format: "xml"})
+	if err == nil || !strings.Contains(err.Error(), "msgpack.gz") {
+		t.Errorf("err = %v, want an unknown format error", err)
+	}
+}
+
 // Example tests demonstrating common result operations
 
 func ExampleResultsService_GetResult() {
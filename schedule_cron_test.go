@@ -0,0 +1,48 @@
+package treasuredata
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScheduleTimesBetween(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		cron, timezone string
+		from, to       time.Time
+		want           []string
+	}{
+		{"@daily", "", from, from.AddDate(0, 0, 3), []string{"2026-10-01T00:00:00Z", "2026-10-02T00:00:00Z", "2026-10-03T00:00:00Z"}},
+		{"*/30 9-10 * * *", "", from, from.Add(11 * time.Hour), []string{"2026-10-01T09:00:00Z", "2026-10-01T09:30:00Z", "2026-10-01T10:00:00Z", "2026-10-01T10:30:00Z"}},
+		// October 1st 2026 is a Thursday; day of month and day of week are ORed
+		{"0 0 9 * mon", "", from, from.AddDate(0, 0, 10), []string{"2026-10-05T00:00:00Z", "2026-10-09T00:00:00Z"}},
+		{"0 0 * * 7", "", from, from.AddDate(0, 0, 10), []string{"2026-10-04T00:00:00Z"}},
+		{"0 9 * * *", "Asia/Kolkata", from, from.AddDate(0, 0, 1), []string{"2026-10-01T09:00:00+05:30"}},
+		// 02:30 on 2026-03-29 does not exist in Berlin
+		{"30 2 * 3 *", "Europe/Berlin", time.Date(2026, 3, 29, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), []string{"2026-03-30T02:30:00+02:00"}},
+	}
+	for _, tt := range tests {
+		times, err := Schedule{Cron: tt.cron, Timezone: tt.timezone}.TimesBetween(tt.from, tt.to, 100)
+		if err != nil {
+			t.Errorf("%s: %v", tt.cron, err)
+			continue
+		}
+		var got []string
+		for _, tm := range times {
+			got = append(got, tm.Format(time.RFC3339))
+		}
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("%s: got %v, want %v", tt.cron, got, tt.want)
+		}
+	}
+
+	if _, err := (Schedule{Cron: "* * * * *"}).TimesBetween(from, from.Add(time.Hour), 10); err == nil {
+		t.Error("expected an error past the limit")
+	}
+	for _, cron := range []string{"0 0 * *", "60 * * * *", "0 0 32 * *", "*/0 * * * *", "0 0 * foo *"} {
+		if _, err := parseCron(cron); err == nil {
+			t.Errorf("parseCron(%q) should fail", cron)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package treasuredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SchedulesService handles communication with the scheduled query (saved
+// query) related methods of the Treasure Data API.
+type SchedulesService struct {
+	client *Client
+}
+
+// Schedule is a saved query that runs on a cron schedule
+type Schedule struct {
+	Name     string `json:"name"`
+	Cron     string `json:"cron"`
+	Timezone string `json:"timezone"`
+	// Delay is the number of seconds a run waits after its scheduled time
+	Delay      int        `json:"delay"`
+	Type       string     `json:"type"`
+	Query      QueryField `json:"query"`
+	Database   string     `json:"database"`
+	UserName   string     `json:"user_name"`
+	Priority   int        `json:"priority"`
+	RetryLimit int        `json:"retry_limit"`
+	Result     string     `json:"result"`
+	CreatedAt  TDTime     `json:"created_at"`
+	// NextTime is when the schedule runs next; nil if it is paused
+	NextTime *TDTime `json:"next_time"`
+}
+
+// ScheduleOptions are the settings of a schedule. Create needs Cron, Query
+// and Database; Update changes only the fields that are set.
+type ScheduleOptions struct {
+	Cron     string `json:"cron,omitempty"`
+	Query    string `json:"query,omitempty"`
+	Database string `json:"database,omitempty"`
+	// Type is the query engine, hive or presto (Trino)
+	Type       string `json:"type,omitempty"`
+	Timezone   string `json:"timezone,omitempty"`
+	Delay      *int   `json:"delay,omitempty"`
+	Priority   *int   `json:"priority,omitempty"`
+	RetryLimit *int   `json:"retry_limit,omitempty"`
+	Result     string `json:"result,omitempty"`
+}
+
+// ScheduledJob is a job started by running a schedule
+type ScheduledJob struct {
+	JobID       string `json:"job_id"`
+	Type        string `json:"type"`
+	ScheduledAt TDTime `json:"scheduled_at"`
+}
+
+// ScheduleHistoryEntry is a past run of a schedule
+type ScheduleHistoryEntry struct {
+	Job
+	ScheduledAt TDTime `json:"scheduled_at"`
+}
+
+// ScheduleHistoryOptions selects a page of a schedule's history, newest
+// first
+type ScheduleHistoryOptions struct {
+	From int `url:"from,omitempty"`
+	To   int `url:"to,omitempty"`
+}
+
+type scheduleListResponse struct {
+	Schedules []Schedule `json:"schedules"`
+}
+
+type scheduleRunResponse struct {
+	Jobs []ScheduledJob `json:"jobs"`
+}
+
+type scheduleHistoryResponse struct {
+	History []ScheduleHistoryEntry `json:"history"`
+}
+
+// List returns every schedule of the account
+func (s *SchedulesService) List(ctx context.Context) ([]Schedule, error) {
+	u := fmt.Sprintf("%s/schedule/list", apiVersion)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp scheduleListResponse
+	_, err = s.client.Do(ctx, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Schedules, nil
+}
+
+// Get returns the schedule named name. The API has no endpoint for a single
+// schedule, so it is looked up in the list.
+func (s *SchedulesService) Get(ctx context.Context, name string) (*Schedule, error) {
+	schedules, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range schedules {
+		if schedules[i].Name == name {
+			return &schedules[i], nil
+		}
+	}
+	return nil, fmt.Errorf("schedule %s: %w", name, ErrNotFound)
+}
+
+// Create saves a scheduled query
+func (s *SchedulesService) Create(ctx context.Context, name string, opts *ScheduleOptions) (*Schedule, error) {
+	if opts == nil || opts.Cron == "" || opts.Query == "" || opts.Database == "" {
+		return nil, errors.New("a schedule needs a cron expression, a query and a database")
+	}
+
+	u := fmt.Sprintf("%s/schedule/create/%s", apiVersion, url.PathEscape(name))
+
+	req, err := s.client.NewRequest("POST", u, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedule Schedule
+	_, err = s.client.Do(ctx, req, &schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+// Update changes the settings of a schedule
+func (s *SchedulesService) Update(ctx context.Context, name string, opts *ScheduleOptions) (*Schedule, error) {
+	u := fmt.Sprintf("%s/schedule/update/%s", apiVersion, url.PathEscape(name))
+
+	req, err := s.client.NewRequest("POST", u, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedule Schedule
+	_, err = s.client.Do(ctx, req, &schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+// Delete removes a schedule. Jobs it already started are not affected.
+func (s *SchedulesService) Delete(ctx context.Context, name string) error {
+	u := fmt.Sprintf("%s/schedule/delete/%s", apiVersion, url.PathEscape(name))
+
+	req, err := s.client.NewRequest("POST", u, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// Run starts num runs of a schedule as if scheduled at t and the cron times
+// that follow it, and returns their jobs. num below 1 runs once.
+func (s *SchedulesService) Run(ctx context.Context, name string, t time.Time, num int) ([]ScheduledJob, error) {
+	u := fmt.Sprintf("%s/schedule/run/%s/%d", apiVersion, url.PathEscape(name), t.Unix())
+
+	var body interface{}
+	if num > 1 {
+		body = map[string]string{"num": strconv.Itoa(num)}
+	}
+	req, err := s.client.NewRequest("POST", u, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp scheduleRunResponse
+	_, err = s.client.Do(ctx, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Jobs, nil
+}
+
+// History returns past runs of a schedule, newest first
+func (s *SchedulesService) History(ctx context.Context, name string, opts *ScheduleHistoryOptions) ([]ScheduleHistoryEntry, error) {
+	u := fmt.Sprintf("%s/schedule/history/%s", apiVersion, url.PathEscape(name))
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp scheduleHistoryResponse
+	_, err = s.client.Do(ctx, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.History, nil
+}
+
+// defaultScheduleBackfillConcurrency bounds the runs started at once
+const defaultScheduleBackfillConcurrency = 4
+
+// defaultScheduleBackfillMaxRuns guards against backfilling far more runs
+// than intended, e.g. a minutely schedule over a year
+const defaultScheduleBackfillMaxRuns = 1000
+
+// TimesBetween returns the times in [from, to) at which the schedule's cron
+// expression fires in its time zone (UTC if unset), at most limit of them.
+func (s Schedule) TimesBetween(from, to time.Time, limit int) ([]time.Time, error) {
+	spec, err := parseCron(s.Cron)
+	if err != nil {
+		return nil, err
+	}
+	loc := time.UTC
+	if s.Timezone != "" {
+		if loc, err = time.LoadLocation(s.Timezone); err != nil {
+			return nil, fmt.Errorf("schedule %s: %w", s.Name, err)
+		}
+	}
+	return spec.between(from, to, loc, limit)
+}
+
+// ScheduleBackfillOptions specifies optional parameters to Backfill
+type ScheduleBackfillOptions struct {
+	// Concurrency is the number of runs started at once (default 4)
+	Concurrency int
+	// MaxRuns fails the backfill before starting anything if the range
+	// holds more scheduled times (default 1000)
+	MaxRuns int
+}
+
+// Backfill runs a schedule once for every scheduled time in [from, to), as
+// the cron expression would have, and returns the jobs in scheduled order.
+// If some runs cannot be started, the jobs of the others are returned along
+// with a *BatchError.
+func (s *SchedulesService) Backfill(ctx context.Context, name string, from, to time.Time, opts *ScheduleBackfillOptions) ([]ScheduledJob, error) {
+	if opts == nil {
+		opts = &ScheduleBackfillOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultScheduleBackfillConcurrency
+	}
+	maxRuns := opts.MaxRuns
+	if maxRuns <= 0 {
+		maxRuns = defaultScheduleBackfillMaxRuns
+	}
+	if !from.Before(to) {
+		return nil, errors.New("backfill range is empty: from must be before to")
+	}
+
+	schedule, err := s.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	times, err := schedule.TimesBetween(from, to, maxRuns)
+	if err != nil {
+		return nil, err
+	}
+
+	results, batchErr := BatchMap(ctx, concurrency, times, func(ctx context.Context, t time.Time) ([]ScheduledJob, error) {
+		jobs, err := s.Run(ctx, name, t, 1)
+		if err != nil {
+			return nil, fmt.Errorf("run at %s: %w", t.Format(time.RFC3339), err)
+		}
+		return jobs, nil
+	})
+
+	var jobs []ScheduledJob
+	for _, result := range results {
+		jobs = append(jobs, result...)
+	}
+	return jobs, batchErr
+}
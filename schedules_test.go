@@ -0,0 +1,181 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSchedulesService_ListAndGet(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/schedule/list", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"schedules": [
+			{"name": "daily", "cron": "0 0 * * *", "timezone": "UTC", "delay": 0, "type": "presto", "query": "SELECT 1", "database": "analytics", "next_time": "2026-10-18 00:00:00 UTC"},
+			{"name": "hourly", "cron": "@hourly", "type": "hive", "query": "SELECT 2", "database": "logs", "next_time": null}
+		]}`)
+	})
+
+	schedules, err := client.Schedules.List(context.Background())
+	if err != nil {
+		t.Fatalf("Schedules.List returned error: %v", err)
+	}
+	if len(schedules) != 2 || schedules[0].Query.String() != "SELECT 1" || schedules[0].NextTime.Day() != 18 || schedules[1].NextTime != nil {
+		t.Errorf("Schedules.List returned %+v", schedules)
+	}
+
+	schedule, err := client.Schedules.Get(context.Background(), "hourly")
+	if err != nil || schedule.Database != "logs" {
+		t.Errorf("Schedules.Get = %+v, %v", schedule, err)
+	}
+	if _, err := client.Schedules.Get(context.Background(), "missing"); !IsNotFound(err) {
+		t.Errorf("Schedules.Get(missing) err = %v", err)
+	}
+}
+
+func TestSchedulesService_CreateUpdateDelete(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/schedule/create/daily_report", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["cron"] != "0 0 * * *" || body["database"] != "analytics" || body["priority"] != float64(0) {
+			t.Errorf("create body = %v", body)
+		}
+		fmt.Fprint(w, `{"name": "daily_report", "cron": "0 0 * * *", "database": "analytics"}`)
+	})
+	mux.HandleFunc("/v3/schedule/update/daily_report", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if len(body) != 1 || body["cron"] != "@hourly" {
+			t.Errorf("update body = %v", body)
+		}
+		fmt.Fprint(w, `{"name": "daily_report", "cron": "@hourly"}`)
+	})
+	deleted := false
+	mux.HandleFunc("/v3/schedule/delete/daily_report", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		deleted = true
+		fmt.Fprint(w, `{"name": "daily_report"}`)
+	})
+
+	if _, err := client.Schedules.Create(context.Background(), "daily_report", &ScheduleOptions{Cron: "0 0 * * *"}); err == nil {
+		t.Error("Create without a query should fail")
+	}
+
+	zero := 0
+	created, err := client.Schedules.Create(context.Background(), "daily_report", &ScheduleOptions{
+		Cron: "0 0 * * *", Query: "SELECT 1", Database: "analytics", Priority: &zero,
+	})
+	if err != nil || created.Name != "daily_report" {
+		t.Fatalf("Create = %+v, %v", created, err)
+	}
+
+	updated, err := client.Schedules.Update(context.Background(), "daily_report", &ScheduleOptions{Cron: "@hourly"})
+	if err != nil || updated.Cron != "@hourly" {
+		t.Errorf("Update = %+v, %v", updated, err)
+	}
+
+	if err := client.Schedules.Delete(context.Background(), "daily_report"); err != nil || !deleted {
+		t.Errorf("Delete err = %v, deleted = %v", err, deleted)
+	}
+}
+
+func TestSchedulesService_RunAndHistory(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	at := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	mux.HandleFunc(fmt.Sprintf("/v3/schedule/run/daily/%d", at.Unix()), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["num"] != "2" {
+			t.Errorf("run body = %v", body)
+		}
+		fmt.Fprint(w, `{"jobs": [
+			{"job_id": "1", "type": "presto", "scheduled_at": "2026-10-01 00:00:00 UTC"},
+			{"job_id": "2", "type": "presto", "scheduled_at": "2026-10-02 00:00:00 UTC"}
+		]}`)
+	})
+	mux.HandleFunc("/v3/schedule/history/daily", func(w http.ResponseWriter, r *http.Request) {
+		testURL(t, r, "/v3/schedule/history/daily?to=10")
+		fmt.Fprint(w, `{"history": [{"job_id": "2", "status": "success", "scheduled_at": "2026-10-02 00:00:00 UTC"}], "count": 1}`)
+	})
+
+	jobs, err := client.Schedules.Run(context.Background(), "daily", at, 2)
+	if err != nil || len(jobs) != 2 || jobs[1].ScheduledAt.Day() != 2 {
+		t.Errorf("Run = %+v, %v", jobs, err)
+	}
+
+	history, err := client.Schedules.History(context.Background(), "daily", &ScheduleHistoryOptions{To: 10})
+	if err != nil || len(history) != 1 || history[0].JobID != "2" || history[0].Status != "success" {
+		t.Errorf("History = %+v, %v", history, err)
+	}
+}
+
+func TestSchedulesService_Backfill(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/schedule/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"schedules": [{"name": "daily", "cron": "0 6 * * *", "timezone": "Asia/Tokyo"}]}`)
+	})
+	var mu sync.Mutex
+	var ran []int64
+	mux.HandleFunc("/v3/schedule/run/daily/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		unix, _ := strconv.ParseInt(path.Base(r.URL.Path), 10, 64)
+		mu.Lock()
+		ran = append(ran, unix)
+		mu.Unlock()
+		if unix == time.Date(2026, 10, 2, 6, 0, 0, 0, tokyo(t)).Unix() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, `{"jobs": [{"job_id": "%d", "type": "presto"}]}`, unix)
+	})
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, tokyo(t))
+	jobs, err := client.Schedules.Backfill(context.Background(), "daily", from, from.AddDate(0, 0, 3), &ScheduleBackfillOptions{Concurrency: 2})
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Errors) != 1 || batchErr.Errors[0].Index != 1 {
+		t.Fatalf("err = %v, want the second run to fail", err)
+	}
+	if len(ran) != 3 {
+		t.Errorf("ran %d times, want 3", len(ran))
+	}
+	want := []string{
+		strconv.FormatInt(from.Add(6*time.Hour).Unix(), 10),
+		strconv.FormatInt(from.AddDate(0, 0, 2).Add(6*time.Hour).Unix(), 10),
+	}
+	if len(jobs) != 2 || jobs[0].JobID != want[0] || jobs[1].JobID != want[1] {
+		t.Errorf("jobs = %+v, want IDs %v", jobs, want)
+	}
+
+	if _, err := client.Schedules.Backfill(context.Background(), "daily", from, from.AddDate(1, 0, 0), &ScheduleBackfillOptions{MaxRuns: 30}); err == nil {
+		t.Error("expected an error for more runs than MaxRuns")
+	}
+}
+
+func tokyo(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip(err)
+	}
+	return loc
+}
@@ -0,0 +1,420 @@
+package treasuredata
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// The service interfaces below list every method of the corresponding
+// service. Client holds its services through them, so code that depends on
+// the SDK can replace a service with a test double:
+//
+//	type fakeJobs struct{ td.JobsAPI } // embed to implement only what the test needs
+//
+//	func (fakeJobs) Get(ctx context.Context, jobID string, _ ...td.RequestOption) (*td.Job, error) {
+//		return &td.Job{JobID: jobID, Status: "success"}, nil
+//	}
+//
+//	client.Jobs = fakeJobs{}
+
+// DatabasesAPI is implemented by *DatabasesService and held in Client.Databases.
+type DatabasesAPI interface {
+	List(ctx context.Context) ([]Database, error)
+	Get(ctx context.Context, name string) (*Database, error)
+	Create(ctx context.Context, name string) (*Database, error)
+	Delete(ctx context.Context, name string) error
+
+	ListAll(ctx context.Context) *Iterator[Database]
+	Provision(ctx context.Context, name string, grants []DatabaseGrant) (*Database, error)
+}
+
+// TablesAPI is implemented by *TablesService and held in Client.Tables.
+type TablesAPI interface {
+	List(ctx context.Context, database string) ([]Table, error)
+	Get(ctx context.Context, database, table string) (*Table, error)
+	Create(ctx context.Context, database, table string, tableType string) (*TableCreateResponse, error)
+	Delete(ctx context.Context, database, table string) error
+	Swap(ctx context.Context, database, table1, table2 string) error
+	Rename(ctx context.Context, database, oldName, newName string) error
+	Update(ctx context.Context, database, table string, opts *UpdateOptions) error
+	Plan(ctx context.Context, specs []TableSpec, opts *TablePlanOptions) ([]TableChange, error)
+	Apply(ctx context.Context, changes []TableChange, concurrency int) error
+}
+
+// JobsAPI is implemented by *JobsService and held in Client.Jobs.
+type JobsAPI interface {
+	List(ctx context.Context, opts *JobListOptions, reqOpts ...RequestOption) (*JobListResponse, error)
+	Get(ctx context.Context, jobID string, reqOpts ...RequestOption) (*Job, error)
+	GetLogs(ctx context.Context, jobID string, reqOpts ...RequestOption) (*JobDebug, error)
+	GetResultSchema(ctx context.Context, jobID string, reqOpts ...RequestOption) ([]TableColumn, error)
+	Status(ctx context.Context, jobID string, reqOpts ...RequestOption) (*JobStatus, error)
+	StatusByDomainKey(ctx context.Context, domainKey string, reqOpts ...RequestOption) (*JobStatus, error)
+	Kill(ctx context.Context, jobID string, reqOpts ...RequestOption) error
+	ResultExport(ctx context.Context, jobID string, opts *ResultExportOptions, reqOpts ...RequestOption) (*Job, error)
+	Queue(ctx context.Context) (*JobQueue, error)
+	WaitForCompletion(ctx context.Context, jobID string, opts WaitOptions) (*JobStatus, error)
+	Watch(ctx context.Context, jobID string) (<-chan JobStatusUpdate, error)
+	WatchWithOptions(ctx context.Context, jobID string, opts WaitOptions) (<-chan JobStatusUpdate, error)
+	DownloadResult(ctx context.Context, jobID, path string, reqOpts ...RequestOption) (int64, error)
+	DownloadResultParallel(ctx context.Context, jobID string, w io.Writer, parallel int, reqOpts ...RequestOption) (int64, error)
+
+	ListAll(ctx context.Context, opts *JobListOptions, reqOpts ...RequestOption) *Iterator[Job]
+}
+
+// QueriesAPI is implemented by *QueriesService and held in Client.Queries.
+type QueriesAPI interface {
+	Issue(ctx context.Context, queryType QueryType, database string, opts *IssueQueryOptions, reqOpts ...RequestOption) (*IssueQueryResponse, error)
+	IssueHivemallTrain(ctx context.Context, database string, opts *HivemallTrainOptions, issue *IssueQueryOptions, reqOpts ...RequestOption) (*IssueQueryResponse, error)
+	IssueHivemallPredict(ctx context.Context, database string, opts *HivemallPredictOptions, issue *IssueQueryOptions, reqOpts ...RequestOption) (*IssueQueryResponse, error)
+	SubmitAndWait(ctx context.Context, req SubmitRequest) (*QueryResult, error)
+	EstimateScan(ctx context.Context, database, query string, wait WaitOptions) (*QueryEstimate, error)
+}
+
+// ResultsAPI is implemented by *ResultsService and held in Client.Results.
+type ResultsAPI interface {
+	GetResult(ctx context.Context, jobID string, opts *GetResultOptions, reqOpts ...RequestOption) (io.ReadCloser, error)
+	GetResultJSON(ctx context.Context, jobID string, v interface{}, reqOpts ...RequestOption) error
+	GetResultJSONL(ctx context.Context, jobID string, reqOpts ...RequestOption) (*JSONLScanner, error)
+	Decoder(ctx context.Context, jobID string, reqOpts ...RequestOption) (*ResultDecoder, error)
+	ListResults(ctx context.Context) ([]Result, error)
+	GetResultConnection(ctx context.Context, name string) (*Result, error)
+	CreateResultOutput(ctx context.Context, name string, out ResultOutput) (*Result, error)
+	TestConnection(ctx context.Context, name string) (*ResultConnectionCheck, error)
+	CreateResult(ctx context.Context, name, url string, settings map[string]interface{}) (*Result, error)
+	DeleteResult(ctx context.Context, name string) error
+}
+
+// UsersAPI is implemented by *UsersService and held in Client.Users.
+type UsersAPI interface {
+	List(ctx context.Context) ([]User, error)
+	Get(ctx context.Context, email string) (*User, error)
+	Create(ctx context.Context, opts *CreateUserOptions) (*User, error)
+	Delete(ctx context.Context, email string) error
+	ListAPIKeys(ctx context.Context, email string) ([]APIKey, error)
+	AddAPIKey(ctx context.Context, email string) (*APIKey, error)
+	RemoveAPIKey(ctx context.Context, email, key string) error
+	Activity(ctx context.Context, opts *UserActivityOptions) ([]UserActivity, error)
+}
+
+// PermissionsAPI is implemented by *PermissionsService and held in Client.Permissions.
+type PermissionsAPI interface {
+	ListPolicies(ctx context.Context, opts *ListPoliciesOptions) ([]AccessControlPolicy, error)
+	GetPolicy(ctx context.Context, policyID int) (*AccessControlPolicy, error)
+	CreatePolicy(ctx context.Context, name, description string) (*AccessControlPolicy, error)
+	UpdatePolicy(ctx context.Context, policyID int, name, description string) (*AccessControlPolicy, error)
+	DeletePolicy(ctx context.Context, policyID int) (*AccessControlPolicy, error)
+	ListUserPolicies(ctx context.Context, userID int) ([]AccessControlPolicy, error)
+	UpdateUserPolicies(ctx context.Context, userID int, policyIDs []string) ([]AccessControlPolicy, error)
+	AttachUserToPolicy(ctx context.Context, userID, policyID int) (*AccessControlPolicy, error)
+	DetachUserFromPolicy(ctx context.Context, userID, policyID int) (*AccessControlPolicy, error)
+	ListPolicyGroups(ctx context.Context) ([]AccessControlPolicyGroup, error)
+	GetPolicyGroup(ctx context.Context, groupIDOrName string) (*AccessControlPolicyGroup, error)
+	CreatePolicyGroup(ctx context.Context, name string) (*AccessControlPolicyGroup, error)
+	UpdatePolicyGroup(ctx context.Context, groupIDOrName, name string, description *string) (*AccessControlPolicyGroup, error)
+	DeletePolicyGroup(ctx context.Context, groupIDOrName string) error
+	ListPolicyGroupPolicies(ctx context.Context, groupIDOrName string) (*AccessControlPolicyGroupPolicies, error)
+	UpdatePolicyGroupPolicies(ctx context.Context, groupIDOrName string, policyIDs []int) (*AccessControlPolicyGroupPolicies, error)
+	GetPolicyPermissions(ctx context.Context, policyID int) (*AccessControlPermissions, error)
+	UpdatePolicyPermissions(ctx context.Context, policyID int, permissions *AccessControlPermissions) (*AccessControlPermissions, error)
+	GetColumnPermissions(ctx context.Context, policyID int) ([]AccessControlColumnPermission, error)
+	UpdateColumnPermissions(ctx context.Context, policyID int, permissions []AccessControlColumnPermission) ([]AccessControlColumnPermission, error)
+	ListAccessControlUsers(ctx context.Context) ([]AccessControlUser, error)
+	GetAccessControlUser(ctx context.Context, userID int) (*AccessControlUser, error)
+	GetPolicyUsers(ctx context.Context, policyID int) ([]AccessControlUserReference, error)
+	UpdatePolicyUsers(ctx context.Context, policyID int, userIDs []int) ([]AccessControlUser, error)
+	AttachPolicyToUser(ctx context.Context, policyID, userID int) (*AccessControlPolicy, error)
+	DetachPolicyFromUser(ctx context.Context, policyID, userID int) (*AccessControlPolicy, error)
+}
+
+// BulkImportAPI is implemented by *BulkImportService and held in Client.BulkImport.
+type BulkImportAPI interface {
+	Create(ctx context.Context, name, database, table string) error
+	UploadPart(ctx context.Context, name, partName string, data io.Reader, reqOpts ...RequestOption) error
+	UploadRecords(ctx context.Context, name, partName string, records []map[string]interface{}, reqOpts ...RequestOption) error
+	Delete(ctx context.Context, name string) error
+	Show(ctx context.Context, name string) (*BulkImport, error)
+	List(ctx context.Context) ([]BulkImport, error)
+	Commit(ctx context.Context, name string) error
+	Freeze(ctx context.Context, name string) error
+	Unfreeze(ctx context.Context, name string) error
+	Perform(ctx context.Context, name string, reqOpts ...RequestOption) (*Job, error)
+	ListParts(ctx context.Context, name string) ([]BulkImportPart, error)
+
+	UploadPartIfChanged(ctx context.Context, ledger PartLedger, name, partName string, data io.Reader) (*PartUploadResult, error)
+
+	ErrorRecords(ctx context.Context, name string, reqOpts ...RequestOption) ([]map[string]interface{}, error)
+	CoercionReport(ctx context.Context, name string) (*CoercionReport, error)
+}
+
+// ImportAPI is implemented by *ImportService and held in Client.Import.
+type ImportAPI interface {
+	Import(ctx context.Context, database, table string, format ImportFormat, data io.Reader) (*ImportResponse, error)
+	ImportWithID(ctx context.Context, database, table, uniqueID string, format ImportFormat, data io.Reader) (*ImportResponse, error)
+	ImportRecords(ctx context.Context, database, table, uniqueID string, records []map[string]interface{}) (*ImportResponse, error)
+}
+
+// CDPAPI is implemented by *CDPService and held in Client.CDP.
+type CDPAPI interface {
+	CreateActivationTemplate(ctx context.Context, request *CDPActivationTemplateRequest) (*CDPActivationTemplateResponse, error)
+	GetActivationTemplate(ctx context.Context, templateID string) (*CDPActivationTemplateResponse, error)
+	UpdateActivationTemplate(ctx context.Context, templateID string, request *CDPActivationTemplateRequest) (*CDPActivationTemplateResponse, error)
+	DeleteActivationTemplate(ctx context.Context, templateID string) error
+	ListActivationTemplatesByParentSegment(ctx context.Context, parentSegmentID string) (*CDPActivationTemplateListResponse, error)
+
+	CreateActivation(ctx context.Context, segmentID, name, description string, attributes map[string]interface{}) (*CDPActivation, error)
+	CreateActivationWithRequest(ctx context.Context, segmentID string, req *CDPActivationCreateRequest) (*CDPActivation, error)
+	ListActivations(ctx context.Context, audienceID string, opts *CDPActivationListOptions) (*CDPActivationListResponse, error)
+	ListSegmentActivations(ctx context.Context, segmentID string, opts *CDPActivationListOptions) (*CDPActivationListResponse, error)
+	GetActivation(ctx context.Context, audienceID, segmentID, activationID string) (*CDPActivation, error)
+	CreateSegmentActivation(ctx context.Context, audienceID, segmentID string, activation *CDPActivation) (*CDPActivation, error)
+	UpdateActivationStatus(ctx context.Context, audienceID, segmentID, activationID, status string) (*CDPActivation, error)
+	UpdateActivation(ctx context.Context, audienceID, segmentID, activationID string, req *CDPActivationUpdateRequest) (*CDPActivation, error)
+	DeleteActivation(ctx context.Context, audienceID, segmentID, activationID string) error
+	ExecuteActivation(ctx context.Context, audienceID, segmentID, activationID string) (*CDPActivationExecution, error)
+	GetActivationExecutions(ctx context.Context, audienceID, segmentID, activationID string) ([]CDPActivationExecution, error)
+	GetActivationExecution(ctx context.Context, audienceID, segmentID, activationID, executionID string) (*CDPActivationExecution, error)
+	GetAudienceActivations(ctx context.Context, audienceID string, opts *CDPActivationListOptions) (*CDPActivationListResponse, error)
+	ActivationHistory(ctx context.Context, audienceID string, opts *CDPActivationHistoryOptions) ([]CDPActivationRun, error)
+	FailedActivationRuns(ctx context.Context, audienceID string, opts *CDPActivationHistoryOptions) ([]CDPActivationRun, error)
+	RetryActivations(ctx context.Context, failed []CDPActivationRun, concurrency int) ([]CDPActivationRetry, error)
+	GetSegmentFolderActivations(ctx context.Context, segmentFolderID string, opts *CDPActivationListOptions) (*CDPActivationListResponse, error)
+	RunSegmentActivation(ctx context.Context, segmentID, activationID string) (*CDPActivationExecution, error)
+	GetParentSegmentActivations(ctx context.Context, parentSegmentID string, opts *CDPActivationListOptions) (*CDPActivationListResponse, error)
+	GetAudienceFolderSyndications(ctx context.Context, audienceID, folderID string, opts *CDPActivationListOptions) (*CDPActivationListResponse, error)
+	GetSegmentSyndications(ctx context.Context, audienceID, segmentID string, opts *CDPActivationListOptions) (*CDPActivationListResponse, error)
+	GetParentSegmentUserDefinedWorkflowProjects(ctx context.Context, parentSegmentID string) (*CDPUserDefinedWorkflowProjectListResponse, error)
+	GetParentSegmentUserDefinedWorkflows(ctx context.Context, parentSegmentID, workflowProjectName string) (*CDPUserDefinedWorkflowListResponse, error)
+	GetParentSegmentMatchedActivations(ctx context.Context, parentSegmentID string) (*CDPMatchedActivationListResponse, error)
+
+	AnalyzeAttributeUsage(ctx context.Context, audienceID string) (*CDPAttributeUsageReport, error)
+
+	ExportAudience(ctx context.Context, audienceID string) (*CDPAudienceExport, error)
+	ImportAudience(ctx context.Context, export *CDPAudienceExport, opts *CDPAudienceImportOptions) (*CDPAudienceImportResult, error)
+
+	CreateAudience(ctx context.Context, name, description, parentDatabaseName, parentTableName string) (*CDPAudience, error)
+	ListAudiences(ctx context.Context) (*CDPAudienceListResponse, error)
+	GetAudience(ctx context.Context, audienceID string) (*CDPAudience, error)
+	DeleteAudience(ctx context.Context, audienceID string) error
+	UpdateAudience(ctx context.Context, audienceID string, req *CDPAudienceUpdateRequest) (*CDPAudience, error)
+	GetAudienceAttributes(ctx context.Context, audienceID string) ([]interface{}, error)
+	GetAudienceBehaviors(ctx context.Context, audienceID string) ([]CDPAudienceBehavior, error)
+	RunAudience(ctx context.Context, audienceID string) (*CDPAudienceExecution, error)
+	GetAudienceExecutions(ctx context.Context, audienceID string) ([]CDPAudienceExecution, error)
+	GetAudienceStatistics(ctx context.Context, audienceID string) ([]CDPAudienceStatisticsPoint, error)
+	GetAudienceSampleValues(ctx context.Context, audienceID, column string) ([]CDPAudienceSampleValue, error)
+	GetAudienceBehaviorSampleValues(ctx context.Context, audienceID, behaviorID, column string) ([]CDPAudienceSampleValue, error)
+	CreateAudienceFolder(ctx context.Context, audienceID string, req *CDPAudienceFolderCreateRequest) (*CDPAudienceFolder, error)
+	GetAudienceFolder(ctx context.Context, audienceID, folderID string) (*CDPAudienceFolder, error)
+	UpdateAudienceFolder(ctx context.Context, audienceID, folderID string, req *CDPAudienceFolderUpdateRequest) (*CDPAudienceFolder, error)
+	FolderReferences(ctx context.Context, audienceID, folderID string) ([]RenameReference, error)
+	RenameAudienceFolder(ctx context.Context, audienceID, folderID, newName string, opts *RenameOptions) (*RenameReport, error)
+	DeleteAudienceFolder(ctx context.Context, audienceID, folderID string) error
+	ListFolders(ctx context.Context, audienceID string) (*CDPAudienceFolderListResponse, error)
+	GetMasterSegments(ctx context.Context) ([]interface{}, error)
+	MoveSegmentIntoFolder(ctx context.Context, audienceID, folderID string, request interface{}) error
+
+	FindAudienceBehavior(ctx context.Context, audienceID, behavior string) (*CDPAudienceBehavior, error)
+	QueryBehavior(ctx context.Context, audienceID, behavior string, opts BehaviorQueryOptions, queryOpts *IssueQueryOptions) (*IssueQueryResponse, error)
+
+	GetEntitiesByFolder(ctx context.Context, folderID string) (*CDPJSONAPIListResponse, error)
+	CreateEntityFolderWithParams(ctx context.Context, name, description string, parentFolderID *string) (*CDPFolder, error)
+	CreateEntityFolder(ctx context.Context, req *CDPFolderCreateRequest) (*CDPFolder, error)
+	GetEntityFolder(ctx context.Context, folderID string) (*CDPJSONAPIResponse, error)
+	UpdateEntityFolder(ctx context.Context, folderID string, req *CDPFolderUpdateRequest) (*CDPFolder, error)
+	DeleteEntityFolder(ctx context.Context, folderID string) error
+
+	ListFunnels(ctx context.Context, audienceID string) ([]CDPFunnel, error)
+	CreateFunnel(ctx context.Context, audienceID string, params CDPFunnelCreateRequest) (*CDPFunnel, error)
+	GetFunnel(ctx context.Context, audienceID, funnelID string) (*CDPFunnel, error)
+	UpdateFunnel(ctx context.Context, audienceID, funnelID string, params CDPFunnelCreateRequest) (*CDPFunnel, error)
+	DeleteFunnel(ctx context.Context, audienceID, funnelID string) (*CDPFunnel, error)
+	CloneFunnel(ctx context.Context, audienceID, funnelID string, params CDPFunnelCloneRequest) (*CDPFunnel, error)
+	GetFunnelStatistics(ctx context.Context, audienceID, funnelID string, limit *int64) (*CDPFunnelStatistic, error)
+	CreateEntityFunnel(ctx context.Context, params CDPFunnelEntityCreateRequest) (*CDPJSONAPIResponse, error)
+	GetEntityFunnel(ctx context.Context, funnelID string) (*CDPJSONAPIResponse, error)
+	UpdateEntityFunnel(ctx context.Context, funnelID string, updates map[string]interface{}) (*CDPJSONAPIResponse, error)
+	GetFunnelStageStatistics(ctx context.Context, funnelID string, stageID string) (*CDPJSONAPIResponse, error)
+	ListFunnelsByParentSegment(ctx context.Context, parentSegmentID string) (*CDPJSONAPIResponse, error)
+	DeleteEntityFunnel(ctx context.Context, funnelID string) error
+
+	ListJourneys(ctx context.Context, folderID string) (*CDPJourneyListResponse, error)
+	CreateJourney(ctx context.Context, request *CDPJourneyRequest) (*CDPJourneyResponse, error)
+	GetJourney(ctx context.Context, journeyID string) (*CDPJourneyResponse, error)
+	UpdateJourney(ctx context.Context, journeyID string, request *CDPJourneyRequest) (*CDPJourneyResponse, error)
+	DeleteJourney(ctx context.Context, journeyID string) error
+	GetJourneyDetail(ctx context.Context, journeyID string) (*CDPJourneyResponse, error)
+	GetAvailableBehaviorsForStep(ctx context.Context, journeyID string, stepID *string) (*CDPAvailableBehaviorsResponse, error)
+	GetActivationTemplatesForStep(ctx context.Context, journeyID string, stepID *string) (*CDPActivationTemplateListResponse, error)
+	DuplicateJourney(ctx context.Context, request *CDPJourneyDuplicateRequest) (*CDPJourneyResponse, error)
+	GetJourneyStatistics(ctx context.Context, journeyID string, from *time.Time, to *time.Time) (*CDPJourneyStatisticsResponse, error)
+	GetJourneyConversionSankeyCharts(ctx context.Context, journeyID string, from *time.Time, to *time.Time) (*CDPJourneySankeyResponse, error)
+	GetJourneyActivationSankeyCharts(ctx context.Context, journeyID string, from *time.Time, to *time.Time) (*CDPJourneySankeyResponse, error)
+	GetJourneyCustomers(ctx context.Context, journeyID string, limit *int, offset *int) (*CDPJourneyCustomersResponse, error)
+	GetJourneyStageCustomers(ctx context.Context, journeyID string, stageID string, limit *int, offset *int) (*CDPJourneyCustomersResponse, error)
+	ListJourneySegmentRules(ctx context.Context, audienceID string) (*CDPJourneySegmentRulesResponse, error)
+	ListJourneyActivations(ctx context.Context, journeyID string) (*CDPJourneyActivationsResponse, error)
+	CreateJourneyActivation(ctx context.Context, journeyID string, request *CDPJourneyActivationRequest) (*CDPJourneyActivationResponse, error)
+	GetJourneyActivation(ctx context.Context, journeyID string, activationStepID string) (*CDPJourneyActivationResponse, error)
+	UpdateJourneyActivation(ctx context.Context, journeyID string, activationStepID string, request *CDPJourneyActivationRequest) (*CDPJourneyActivationResponse, error)
+	PauseJourney(ctx context.Context, journeyID string) (*CDPJourneyResponse, error)
+	ResumeJourney(ctx context.Context, journeyID string) (*CDPJourneyResponse, error)
+
+	ListPredictiveSegments(ctx context.Context, audienceID string) ([]CDPPredictiveSegment, error)
+	CreatePredictiveSegment(ctx context.Context, audienceID string, params CDPPredictiveSegmentCreateRequest) (*CDPPredictiveSegment, error)
+	GetPredictiveSegment(ctx context.Context, audienceID, predictiveSegmentID string) (*CDPPredictiveSegment, error)
+	UpdatePredictiveSegment(ctx context.Context, audienceID, predictiveSegmentID string, params CDPPredictiveSegmentCreateRequest) (*CDPPredictiveSegment, error)
+	DeletePredictiveSegment(ctx context.Context, audienceID, predictiveSegmentID string) (*CDPPredictiveSegment, error)
+	GetPredictiveSegmentExecutions(ctx context.Context, audienceID, predictiveSegmentID string) ([]CDPPredictiveSegmentExecution, error)
+	TrainPredictiveSegment(ctx context.Context, audienceID, predictiveSegmentID string) (*CDPPredictiveSegmentExecution, error)
+	GetPredictiveSegmentGuessRule(ctx context.Context, audienceID string) (*CDPPredictiveSegmentGuessRuleResponse, error)
+	GetPredictiveSegmentModelColumns(ctx context.Context, audienceID, predictiveSegmentID string) (interface{}, error)
+	GetPredictiveSegmentModelFeatures(ctx context.Context, audienceID, predictiveSegmentID string) (interface{}, error)
+	GetPredictiveSegmentScoreHistogram(ctx context.Context, audienceID, predictiveSegmentID string) (interface{}, error)
+	CreateEntityPredictiveSegment(ctx context.Context, params CDPPredictiveSegmentEntityCreateRequest) (*CDPJSONAPIResponse, error)
+	GetEntityPredictiveSegment(ctx context.Context, predictiveSegmentID string) (*CDPJSONAPIResponse, error)
+	UpdateEntityPredictiveSegment(ctx context.Context, predictiveSegmentID string, updates map[string]interface{}) (*CDPJSONAPIResponse, error)
+	DeleteEntityPredictiveSegment(ctx context.Context, predictiveSegmentID string) (*CDPJSONAPIResponse, error)
+	RunEntityPredictiveSegment(ctx context.Context, predictiveSegmentID string) (*CDPJSONAPIResponse, error)
+	GetEntityPredictiveSegmentExecutions(ctx context.Context, predictiveSegmentID string) (*CDPJSONAPIResponse, error)
+	GetEntityPredictiveSegmentModelFeatures(ctx context.Context, predictiveSegmentID string, limit *int64) (*CDPJSONAPIResponse, error)
+	GetEntityPredictiveSegmentModelColumns(ctx context.Context, predictiveSegmentID string, limit *int64) (*CDPJSONAPIResponse, error)
+	GetEntityPredictiveSegmentModelScores(ctx context.Context, predictiveSegmentID string) (*CDPJSONAPIResponse, error)
+	GuessRuleAsyncForSegmentPredictiveSegment(ctx context.Context, segmentID string, request interface{}) (*CDPJSONAPIResponse, error)
+
+	ListAudienceSampleValues(ctx context.Context, audienceID, column string, opts *CDPSampleValueListOptions) (*CDPSampleValuePage, error)
+	ListAudienceBehaviorSampleValues(ctx context.Context, audienceID, behaviorID, column string, opts *CDPSampleValueListOptions) (*CDPSampleValuePage, error)
+
+	UpdateSegmentRule(ctx context.Context, audienceID, segmentID string, rule interface{}) (*CDPSegment, error)
+	RenameSegmentAttribute(ctx context.Context, audienceID string, rename CDPAttributeRename, dryRun bool) ([]CDPSegmentRuleChange, error)
+
+	CreateSegment(ctx context.Context, audienceID, name, description, query string) (*CDPSegment, error)
+	CreateSegmentWithRequest(ctx context.Context, audienceID string, req *CDPSegmentCreateRequest) (*CDPSegment, error)
+	ListSegments(ctx context.Context, audienceID string, opts *CDPSegmentListOptions) (*CDPSegmentListResponse, error)
+	ListSegmentsInFolder(ctx context.Context, audienceID, folderID string, opts *CDPSegmentListOptions) (*CDPSegmentListResponse, error)
+	GetSegment(ctx context.Context, audienceID, segmentID string) (*CDPSegment, error)
+	UpdateSegment(ctx context.Context, audienceID, segmentID string, updates map[string]string) (*CDPSegment, error)
+	DeleteSegment(ctx context.Context, audienceID, segmentID string) error
+	ListSegmentsByPrefix(ctx context.Context, audienceID, prefix string) ([]CDPSegment, error)
+	DeleteSegments(ctx context.Context, audienceID string, segments []CDPSegment, concurrency int) ([]CDPSegmentDeletion, error)
+	SegmentReferences(ctx context.Context, audienceID, segmentID string) ([]RenameReference, error)
+	RenameSegment(ctx context.Context, audienceID, segmentID, newName string, opts *RenameOptions) (*RenameReport, error)
+	GetSegmentFolders(ctx context.Context, folderID string) (*CDPSegmentFolderListResponse, error)
+	CreateSegmentQuery(ctx context.Context, audienceID, query string) (*CDPSegmentQuery, error)
+	GetSegmentSQL(ctx context.Context, audienceID string, segmentRules interface{}) (*CDPSegmentQuery, error)
+	GetSegmentQueryStatus(ctx context.Context, audienceID, queryID string) (*CDPSegmentQuery, error)
+	KillSegmentQuery(ctx context.Context, audienceID, queryID string) error
+	GetSegmentQueryCustomers(ctx context.Context, audienceID, queryID string, opts *CDPSegmentCustomerListOptions) (*CDPSegmentCustomerListResponse, error)
+	GetSegmentStatistics(ctx context.Context, audienceID, segmentID string) ([]CDPSegmentStatisticsPoint, error)
+	CreateEntitySegment(ctx context.Context, name, description, segmentType string, parentFolderID string, attributes map[string]interface{}) (*CDPJSONAPIResponse, error)
+	GetEntitySegment(ctx context.Context, segmentID string) (*CDPJSONAPIResponse, error)
+	ListEntitySegments(ctx context.Context) (*CDPJSONAPIListResponse, error)
+	UpdateEntitySegment(ctx context.Context, segmentID string, updates map[string]interface{}) (*CDPJSONAPIResponse, error)
+	DeleteEntitySegment(ctx context.Context, segmentID string) error
+	ListParentSegments(ctx context.Context) (*CDPParentSegmentListResponse, error)
+	GetParentSegment(ctx context.Context, parentSegmentID string) (*CDPParentSegmentResponse, error)
+
+	ListTokens(ctx context.Context, audienceID string, opts *CDPTokenListOptions) (*CDPTokenListResponse, error)
+	CreateToken(ctx context.Context, audienceID string, req *CDPLegacyTokenRequest) (*CDPToken, error)
+	GetToken(ctx context.Context, audienceID, tokenID string) (*CDPToken, error)
+	UpdateToken(ctx context.Context, audienceID, tokenID string, req *CDPLegacyTokenRequest) (*CDPToken, error)
+	DeleteToken(ctx context.Context, audienceID, tokenID string) error
+	CreateEntityToken(ctx context.Context, req *CDPTokenCreateRequest) (*CDPToken, error)
+	GetEntityToken(ctx context.Context, tokenID string) (*CDPToken, error)
+	UpdateEntityToken(ctx context.Context, tokenID string, req *CDPTokenUpdateRequest) (*CDPToken, error)
+	DeleteEntityToken(ctx context.Context, tokenID string) error
+	LookupProfiles(ctx context.Context, token, keyColumn, keyValue string) ([]CDPProfile, error)
+	CheckTokenScope(ctx context.Context, token, keyColumn, keyValue string) (*CDPTokenScope, error)
+
+	ListAllSegments(ctx context.Context, audienceID string, opts *CDPSegmentListOptions) *Iterator[CDPSegment]
+}
+
+// WorkflowAPI is implemented by *WorkflowService and held in Client.Workflow.
+type WorkflowAPI interface {
+	ListWorkflows(ctx context.Context, opts *WorkflowListOptions) (*WorkflowListResponse, error)
+	ListAllWorkflows(ctx context.Context, opts *WorkflowListOptions) *Iterator[Workflow]
+	GetWorkflow(ctx context.Context, workflowID string) (*Workflow, error)
+	CreateWorkflow(ctx context.Context, name, project, config string) (*Workflow, error)
+	UpdateWorkflow(ctx context.Context, workflowID string, updates map[string]string) (*Workflow, error)
+	WorkflowReferences(ctx context.Context, workflowID string) ([]RenameReference, error)
+	RenameWorkflow(ctx context.Context, workflowID, newName string, opts *RenameOptions) (*RenameReport, error)
+	DeleteWorkflow(ctx context.Context, workflowID string) error
+
+	StartWorkflow(ctx context.Context, workflowID string, params map[string]interface{}) (*WorkflowAttempt, error)
+	ListWorkflowAttempts(ctx context.Context, workflowID string, opts *WorkflowAttemptListOptions) (*WorkflowAttemptListResponse, error)
+	GetWorkflowAttempt(ctx context.Context, workflowID string, attemptID string) (*WorkflowAttempt, error)
+	KillWorkflowAttempt(ctx context.Context, workflowID string, attemptID string) error
+	RetryWorkflowAttempt(ctx context.Context, workflowID string, attemptID string, params map[string]interface{}) (*WorkflowAttempt, error)
+	ListWorkflowTasks(ctx context.Context, workflowID string, attemptID string) (*WorkflowTaskListResponse, error)
+	GetWorkflowTask(ctx context.Context, workflowID string, attemptID string, taskID string) (*WorkflowTask, error)
+	GetWorkflowAttemptLog(ctx context.Context, workflowID string, attemptID string) (string, error)
+	GetWorkflowTaskLog(ctx context.Context, workflowID string, attemptID string, taskID string) (string, error)
+	TaskJobs(ctx context.Context, workflowID string, attemptID string) ([]WorkflowTaskJobs, error)
+
+	ListProjects(ctx context.Context) (*WorkflowProjectListResponse, error)
+	GetProject(ctx context.Context, projectID string) (*WorkflowProject, error)
+	CreateProject(ctx context.Context, name string, archive []byte) (*WorkflowProject, error)
+	CreateProjectWithRevision(ctx context.Context, name, revision string, archive []byte) (*WorkflowProject, error)
+	RestoreProject(ctx context.Context, projectID string) (*WorkflowProject, error)
+	CreateProjectFromDirectory(ctx context.Context, name string, dirPath string) (*WorkflowProject, error)
+	CreateProjectFromDirectoryWithRevision(ctx context.Context, name, revision, dirPath string) (*WorkflowProject, error)
+	ListProjectWorkflows(ctx context.Context, projectID string) (*WorkflowListResponse, error)
+	GetProjectSecrets(ctx context.Context, projectID string) (*WorkflowProjectSecretsResponse, error)
+	SetProjectSecret(ctx context.Context, projectID string, key, value string) error
+	DeleteProjectSecret(ctx context.Context, projectID string, key string) error
+	DownloadProject(ctx context.Context, projectID string, reqOpts ...RequestOption) ([]byte, error)
+	DownloadProjectWithRevision(ctx context.Context, projectID, revision string, reqOpts ...RequestOption) ([]byte, error)
+	DownloadProjectToDirectory(ctx context.Context, projectID, outputDir string, reqOpts ...RequestOption) error
+	DownloadProjectToDirectoryWithRevision(ctx context.Context, projectID, revision, outputDir string, reqOpts ...RequestOption) error
+	GetProjectByName(ctx context.Context, projectName string) (*WorkflowProject, error)
+	FindProjectByName(ctx context.Context, projectName string) (*WorkflowProject, error)
+	DownloadProjectByNameToDirectory(ctx context.Context, projectName, outputDir string, reqOpts ...RequestOption) error
+	DownloadProjectByNameToDirectoryWithRevision(ctx context.Context, projectName, revision, outputDir string, reqOpts ...RequestOption) error
+	GetProjectMetadata(ctx context.Context, projectID, revision string) (*WorkflowProjectMetadata, error)
+
+	GetWorkflowSchedule(ctx context.Context, workflowID string) (*WorkflowSchedule, error)
+	EnableWorkflowSchedule(ctx context.Context, workflowID string) (*WorkflowSchedule, error)
+	DisableWorkflowSchedule(ctx context.Context, workflowID string) (*WorkflowSchedule, error)
+	UpdateWorkflowSchedule(ctx context.Context, workflowID string, cron, timezone string, delay int) (*WorkflowSchedule, error)
+	DeleteWorkflowSchedule(ctx context.Context, workflowID string) error
+	PauseWorkflowSchedule(ctx context.Context, workflowID string, until time.Time) (*WorkflowSchedule, error)
+	ResumeWorkflowScheduleAt(ctx context.Context, workflowID string, at time.Time) (*WorkflowSchedule, error)
+
+	ListAllWorkflowAttempts(ctx context.Context, workflowID string, opts *WorkflowAttemptListOptions) *Iterator[WorkflowAttempt]
+}
+
+// AccountAPI is implemented by *AccountService and held in Client.Account.
+type AccountAPI interface {
+	Show(ctx context.Context) (*Account, error)
+	CoreUtilization(ctx context.Context, from, to time.Time) (*CoreUtilization, error)
+	StorageUsage(ctx context.Context) (*StorageUsage, error)
+}
+
+// SchedulesAPI is implemented by *SchedulesService and held in Client.Schedules.
+type SchedulesAPI interface {
+	List(ctx context.Context) ([]Schedule, error)
+	Get(ctx context.Context, name string) (*Schedule, error)
+	Create(ctx context.Context, name string, opts *ScheduleOptions) (*Schedule, error)
+	Update(ctx context.Context, name string, opts *ScheduleOptions) (*Schedule, error)
+	Delete(ctx context.Context, name string) error
+	Run(ctx context.Context, name string, t time.Time, num int) ([]ScheduledJob, error)
+	History(ctx context.Context, name string, opts *ScheduleHistoryOptions) ([]ScheduleHistoryEntry, error)
+	Backfill(ctx context.Context, name string, from, to time.Time, opts *ScheduleBackfillOptions) ([]ScheduledJob, error)
+}
+
+// Compile-time checks that the services implement their interfaces
+var (
+	_ DatabasesAPI   = (*DatabasesService)(nil)
+	_ TablesAPI      = (*TablesService)(nil)
+	_ JobsAPI        = (*JobsService)(nil)
+	_ QueriesAPI     = (*QueriesService)(nil)
+	_ ResultsAPI     = (*ResultsService)(nil)
+	_ UsersAPI       = (*UsersService)(nil)
+	_ PermissionsAPI = (*PermissionsService)(nil)
+	_ BulkImportAPI  = (*BulkImportService)(nil)
+	_ ImportAPI      = (*ImportService)(nil)
+	_ CDPAPI         = (*CDPService)(nil)
+	_ WorkflowAPI    = (*WorkflowService)(nil)
+	_ AccountAPI     = (*AccountService)(nil)
+	_ SchedulesAPI   = (*SchedulesService)(nil)
+)
@@ -0,0 +1,74 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestServiceInterfacesAreComplete(t *testing.T) {
+	pairs := []struct {
+		service reflect.Type
+		api     reflect.Type
+	}{
+		{reflect.TypeOf(&DatabasesService{}), reflect.TypeOf((*DatabasesAPI)(nil)).Elem()},
+		{reflect.TypeOf(&TablesService{}), reflect.TypeOf((*TablesAPI)(nil)).Elem()},
+		{reflect.TypeOf(&JobsService{}), reflect.TypeOf((*JobsAPI)(nil)).Elem()},
+		{reflect.TypeOf(&QueriesService{}), reflect.TypeOf((*QueriesAPI)(nil)).Elem()},
+		{reflect.TypeOf(&ResultsService{}), reflect.TypeOf((*ResultsAPI)(nil)).Elem()},
+		{reflect.TypeOf(&UsersService{}), reflect.TypeOf((*UsersAPI)(nil)).Elem()},
+		{reflect.TypeOf(&PermissionsService{}), reflect.TypeOf((*PermissionsAPI)(nil)).Elem()},
+		{reflect.TypeOf(&BulkImportService{}), reflect.TypeOf((*BulkImportAPI)(nil)).Elem()},
+		{reflect.TypeOf(&ImportService{}), reflect.TypeOf((*ImportAPI)(nil)).Elem()},
+		{reflect.TypeOf(&CDPService{}), reflect.TypeOf((*CDPAPI)(nil)).Elem()},
+		{reflect.TypeOf(&WorkflowService{}), reflect.TypeOf((*WorkflowAPI)(nil)).Elem()},
+		{reflect.TypeOf(&AccountService{}), reflect.TypeOf((*AccountAPI)(nil)).Elem()},
+		{reflect.TypeOf(&SchedulesService{}), reflect.TypeOf((*SchedulesAPI)(nil)).Elem()},
+	}
+
+	// New service methods must be added to the interface as well, or mocks
+	// and callers going through Client would not see them
+	for _, p := range pairs {
+		for i := 0; i < p.service.NumMethod(); i++ {
+			name := p.service.Method(i).Name
+			if _, ok := p.api.MethodByName(name); !ok {
+				t.Errorf("%s is missing %s.%s", p.api.Name(), p.service.Elem().Name(), name)
+			}
+		}
+	}
+}
+
+type fakeQueries struct {
+	QueriesAPI
+	issued *IssueQueryOptions
+}
+
+func (f *fakeQueries) Issue(ctx context.Context, queryType QueryType, database string, opts *IssueQueryOptions, reqOpts ...RequestOption) (*IssueQueryResponse, error) {
+	f.issued = opts
+	return &IssueQueryResponse{JobID: "1", Database: database}, nil
+}
+
+func TestClient_ReplaceService(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	client.CDPURL = client.BaseURL
+
+	mux.HandleFunc("/audiences/123/behaviors", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]CDPAudienceBehavior{testBehavior})
+	})
+
+	// Services calling each other go through the Client fields, so a fake
+	// sees the query QueryBehavior issues
+	fake := &fakeQueries{}
+	client.Queries = fake
+
+	resp, err := client.CDP.QueryBehavior(context.Background(), "123", "purchases", BehaviorQueryOptions{}, nil)
+	if err != nil {
+		t.Fatalf("QueryBehavior returned error: %v", err)
+	}
+	if resp.JobID != "1" || fake.issued == nil || fake.issued.Query == "" {
+		t.Errorf("fake Queries not used: resp %+v, issued %+v", resp, fake.issued)
+	}
+}
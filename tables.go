@@ -128,6 +128,9 @@ func (s *TablesService) Get(ctx context.Context, database, table string) (*Table
 
 // Create creates a new table
 func (s *TablesService) Create(ctx context.Context, database, table string, tableType string) (*TableCreateResponse, error) {
+	if err := ValidateTableName(table); err != nil {
+		return nil, err
+	}
 	if tableType == "" {
 		tableType = "log"
 	}
@@ -192,6 +195,10 @@ func (s *TablesService) Swap(ctx context.Context, database, table1, table2 strin
 
 // Rename renames a table
 func (s *TablesService) Rename(ctx context.Context, database, oldName, newName string) error {
+	if err := ValidateTableName(newName); err != nil {
+		return err
+	}
+
 	u := fmt.Sprintf("%s/table/rename/%s/%s/%s", apiVersion, database, oldName, newName)
 
 	req, err := s.client.NewRequest("POST", u, nil)
@@ -0,0 +1,195 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// TableSpec is the desired state of a table, as declared in a manifest
+type TableSpec struct {
+	Database string
+	Name     string
+	// Columns lists the columns the schema must contain, in order
+	Columns []TableColumn
+	// ExpireDays, if set, is the retention of the table in days
+	ExpireDays *int
+}
+
+// TableAction is what Apply does to a table
+type TableAction string
+
+const (
+	TableActionCreate TableAction = "create"
+	TableActionUpdate TableAction = "update"
+	TableActionNone   TableAction = "none"
+)
+
+// TableChange is the difference between a TableSpec and the existing table
+type TableChange struct {
+	Database string
+	Table    string
+	Action   TableAction
+	// Added are the columns missing from the current schema
+	Added []TableColumn
+	// Retyped are the columns whose type or alias changes, with the new values
+	Retyped []TableColumn
+	// Removed are the current columns missing from the spec. They are only
+	// dropped from the schema when planning with Prune.
+	Removed []TableColumn
+	// ExpireDays is the new retention when it changes
+	ExpireDays *int
+	// Schema is the full schema set by Apply
+	Schema []TableColumn
+}
+
+// TablePlanOptions controls Plan
+type TablePlanOptions struct {
+	// Prune removes columns missing from the spec from the schema. The data
+	// is kept, but the columns are no longer visible to queries.
+	Prune bool
+}
+
+// Plan compares specs with the existing tables and returns the change each
+// spec needs, in spec order. Every database must exist.
+func (s *TablesService) Plan(ctx context.Context, specs []TableSpec, opts *TablePlanOptions) ([]TableChange, error) {
+	if opts == nil {
+		opts = &TablePlanOptions{}
+	}
+
+	existing := map[string]map[string]Table{}
+	for _, spec := range specs {
+		if _, ok := existing[spec.Database]; ok {
+			continue
+		}
+		tables, err := s.List(ctx, spec.Database)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tables in %s: %w", spec.Database, err)
+		}
+		byName := make(map[string]Table, len(tables))
+		for _, table := range tables {
+			byName[table.Name] = table
+		}
+		existing[spec.Database] = byName
+	}
+
+	changes := make([]TableChange, 0, len(specs))
+	for _, spec := range specs {
+		current, ok := existing[spec.Database][spec.Name]
+		if !ok {
+			changes = append(changes, TableChange{
+				Database:   spec.Database,
+				Table:      spec.Name,
+				Action:     TableActionCreate,
+				Added:      spec.Columns,
+				ExpireDays: spec.ExpireDays,
+				Schema:     spec.Columns,
+			})
+			continue
+		}
+
+		change, err := diffTable(spec, current, opts.Prune)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// diffTable compares spec with the current table
+func diffTable(spec TableSpec, current Table, prune bool) (TableChange, error) {
+	change := TableChange{Database: spec.Database, Table: spec.Name, Action: TableActionNone}
+
+	columns, err := current.Columns()
+	if err != nil {
+		return change, fmt.Errorf("%s.%s: %w", spec.Database, spec.Name, err)
+	}
+	currentByName := make(map[string]TableColumn, len(columns))
+	for _, column := range columns {
+		currentByName[column.Name] = column
+	}
+	wanted := make(map[string]bool, len(spec.Columns))
+	for _, column := range spec.Columns {
+		wanted[column.Name] = true
+		old, ok := currentByName[column.Name]
+		switch {
+		case !ok:
+			change.Added = append(change.Added, column)
+		case !strings.EqualFold(old.Type, column.Type) || old.Alias != column.Alias:
+			change.Retyped = append(change.Retyped, column)
+		}
+	}
+
+	change.Schema = append([]TableColumn{}, spec.Columns...)
+	for _, column := range columns {
+		if wanted[column.Name] {
+			continue
+		}
+		if prune {
+			change.Removed = append(change.Removed, column)
+		} else {
+			change.Schema = append(change.Schema, column)
+		}
+	}
+
+	if spec.ExpireDays != nil && (current.ExpireDays == nil || *current.ExpireDays != *spec.ExpireDays) {
+		change.ExpireDays = spec.ExpireDays
+	}
+	if len(change.Added) > 0 || len(change.Retyped) > 0 || len(change.Removed) > 0 || change.ExpireDays != nil {
+		change.Action = TableActionUpdate
+	}
+	return change, nil
+}
+
+// Apply carries out changes from Plan with at most concurrency requests at
+// once. Created tables are log tables. Failures are returned together as a
+// *BatchError whose indexes refer to changes.
+func (s *TablesService) Apply(ctx context.Context, changes []TableChange, concurrency int) error {
+	_, err := BatchMap(ctx, concurrency, changes, func(ctx context.Context, change TableChange) (struct{}, error) {
+		return struct{}{}, s.applyChange(ctx, change)
+	})
+	return err
+}
+
+func (s *TablesService) applyChange(ctx context.Context, change TableChange) error {
+	switch change.Action {
+	case TableActionNone:
+		return nil
+	case TableActionCreate:
+		if _, err := s.Create(ctx, change.Database, change.Table, "log"); err != nil {
+			return fmt.Errorf("failed to create %s.%s: %w", change.Database, change.Table, err)
+		}
+	case TableActionUpdate:
+	default:
+		return fmt.Errorf("unknown table action %q", change.Action)
+	}
+
+	opts := &UpdateOptions{ExpireDays: change.ExpireDays}
+	if len(change.Added) > 0 || len(change.Retyped) > 0 || len(change.Removed) > 0 {
+		opts.Schema = EncodeTableSchema(change.Schema)
+	}
+	if opts.Schema == "" && opts.ExpireDays == nil {
+		return nil
+	}
+	if err := s.Update(ctx, change.Database, change.Table, opts); err != nil {
+		return fmt.Errorf("failed to update %s.%s: %w", change.Database, change.Table, err)
+	}
+	return nil
+}
+
+// EncodeTableSchema formats columns as the schema string accepted by
+// Update, the inverse of ParseTableSchema
+func EncodeTableSchema(columns []TableColumn) string {
+	entries := make([][]string, 0, len(columns))
+	for _, column := range columns {
+		entry := []string{column.Name, column.Type}
+		if column.Alias != "" {
+			entry = append(entry, column.Alias)
+		}
+		entries = append(entries, entry)
+	}
+	data, _ := json.Marshal(entries)
+	return string(data)
+}
@@ -0,0 +1,107 @@
+package treasuredata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestTablesService_PlanAndApply(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v3/table/list/analytics", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"database": "analytics", "tables": [
+			{"name": "events", "schema": "[[\"user_id\",\"string\"],[\"amount\",\"long\"],[\"legacy\",\"string\"]]", "expire_days": 30},
+			{"name": "users", "schema": "[[\"user_id\",\"string\"]]"}
+		]}`)
+	})
+
+	var mu sync.Mutex
+	updates := map[string]UpdateOptions{}
+	var created []string
+	mux.HandleFunc("/v3/table/create/analytics/sessions/log", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		mu.Lock()
+		created = append(created, "sessions")
+		mu.Unlock()
+		fmt.Fprint(w, `{"database": "analytics", "table": "sessions", "type": "log"}`)
+	})
+	for _, table := range []string{"events", "sessions"} {
+		table := table
+		mux.HandleFunc("/v3/table/update/analytics/"+table, func(w http.ResponseWriter, r *http.Request) {
+			var opts UpdateOptions
+			json.NewDecoder(r.Body).Decode(&opts)
+			mu.Lock()
+			updates[table] = opts
+			mu.Unlock()
+			fmt.Fprint(w, `{}`)
+		})
+	}
+
+	ninety := 90
+	specs := []TableSpec{
+		{Database: "analytics", Name: "events", ExpireDays: &ninety, Columns: []TableColumn{
+			{Name: "user_id", Type: "string"},
+			{Name: "amount", Type: "double"},
+			{Name: "country", Type: "string"},
+		}},
+		{Database: "analytics", Name: "users", Columns: []TableColumn{{Name: "user_id", Type: "STRING"}}},
+		{Database: "analytics", Name: "sessions", Columns: []TableColumn{{Name: "session_id", Type: "string", Alias: "sid"}}},
+	}
+
+	changes, err := client.Tables.Plan(context.Background(), specs, nil)
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+	events := changes[0]
+	if events.Action != TableActionUpdate || len(events.Added) != 1 || events.Added[0].Name != "country" ||
+		len(events.Retyped) != 1 || events.Retyped[0].Type != "double" || len(events.Removed) != 0 ||
+		events.ExpireDays == nil || *events.ExpireDays != 90 {
+		t.Errorf("events change = %+v", events)
+	}
+	if changes[1].Action != TableActionNone {
+		t.Errorf("users change = %+v", changes[1])
+	}
+	if changes[2].Action != TableActionCreate {
+		t.Errorf("sessions change = %+v", changes[2])
+	}
+
+	if err := client.Tables.Apply(context.Background(), changes, 2); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	if len(created) != 1 {
+		t.Errorf("created = %v", created)
+	}
+	if got := updates["events"].Schema; got != `[["user_id","string"],["amount","double"],["country","string"],["legacy","string"]]` {
+		t.Errorf("events schema = %s", got)
+	}
+	if got := updates["sessions"].Schema; got != `[["session_id","string","sid"]]` {
+		t.Errorf("sessions schema = %s", got)
+	}
+	if _, ok := updates["users"]; ok {
+		t.Error("unchanged table was updated")
+	}
+
+	pruned, err := client.Tables.Plan(context.Background(), specs[:1], &TablePlanOptions{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pruned[0].Removed) != 1 || pruned[0].Removed[0].Name != "legacy" || len(pruned[0].Schema) != 3 {
+		t.Errorf("pruned change = %+v", pruned[0])
+	}
+}
+
+func TestEncodeTableSchema_RoundTrip(t *testing.T) {
+	columns := []TableColumn{{Name: "a", Type: "long"}, {Name: "b", Type: "string", Alias: "bee"}}
+	parsed, err := ParseTableSchema(EncodeTableSchema(columns))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != 2 || parsed[1] != columns[1] {
+		t.Errorf("parsed = %+v", parsed)
+	}
+}
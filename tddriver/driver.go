@@ -0,0 +1,331 @@
+// Package tddriver is a database/sql driver that runs each query as a
+// Treasure Data job: it issues the query through the job API, polls until the
+// job finishes and streams the result as rows. Use it with tools that need
+// database/sql but cannot speak the Trino wire protocol.
+//
+//	db, err := sql.Open("td", "td://1%2Fabcdef@us/sample_datasets?engine=trino")
+//	rows, err := db.QueryContext(ctx,
+//		"SELECT method, COUNT(1) FROM www_access WHERE method = :method GROUP BY 1",
+//		sql.Named("method", "GET"))
+//
+// To reuse a configured *td.Client, pass NewConnector to sql.OpenDB instead.
+//
+// TD has no server-side parameters, so arguments must be sql.Named values
+// that td.BindParamsFor quotes into :name placeholders for the DSN's engine.
+// Transactions are not supported. Cancelling a query's context kills its job.
+package tddriver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+func init() {
+	sql.Register("td", &Driver{})
+}
+
+// Driver opens connections from DSNs; see ParseDSN
+type Driver struct{}
+
+var (
+	_ driver.Driver        = (*Driver)(nil)
+	_ driver.DriverContext = (*Driver)(nil)
+)
+
+// Open returns a connection for dsn
+func (d *Driver) Open(dsn string) (driver.Conn, error) {
+	connector, err := d.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return connector.Connect(context.Background())
+}
+
+// OpenConnector parses dsn once and creates the client all its connections
+// share
+func (d *Driver) OpenConnector(dsn string) (driver.Connector, error) {
+	cfg, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	client, err := td.NewClient(cfg.APIKey, cfg.clientOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("tddriver: %w", err)
+	}
+	return NewConnector(client, *cfg), nil
+}
+
+// NewConnector returns a connector that runs queries with client, for use
+// with sql.OpenDB. The APIKey, Region and Endpoint of cfg are ignored.
+func NewConnector(client *td.Client, cfg Config) driver.Connector {
+	if cfg.Engine == "" {
+		cfg.Engine = td.QueryTypeTrino
+	}
+	return &connector{client: client, cfg: cfg}
+}
+
+type connector struct {
+	client *td.Client
+	cfg    Config
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &conn{client: c.client, cfg: c.cfg}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return &Driver{}
+}
+
+// conn holds no server state; every query is an independent job
+type conn struct {
+	client *td.Client
+	cfg    Config
+}
+
+var (
+	_ driver.QueryerContext     = (*conn)(nil)
+	_ driver.ExecerContext      = (*conn)(nil)
+	_ driver.Pinger             = (*conn)(nil)
+	_ driver.NamedValueChecker  = (*conn)(nil)
+	_ driver.ConnPrepareContext = (*conn)(nil)
+)
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{conn: c, query: query}, nil
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.Prepare(query)
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return nil, errors.New("tddriver: transactions are not supported")
+}
+
+// Ping checks that the API key can read the configured database
+func (c *conn) Ping(ctx context.Context) error {
+	if _, err := c.client.Databases.Get(ctx, c.cfg.Database); err != nil {
+		return fmt.Errorf("tddriver: %w", err)
+	}
+	return nil
+}
+
+// CheckNamedValue passes every argument through to td.BindParamsFor, which
+// accepts slices for IN lists and td.Ident for identifiers
+func (c *conn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	result, err := c.run(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := result.Job.ResultSchema()
+	if err != nil {
+		return nil, fmt.Errorf("tddriver: job %s: %w", result.Job.JobID, err)
+	}
+	body, err := result.Open(ctx, &td.GetResultOptions{Format: td.ResultFormatJSONL})
+	if err != nil {
+		return nil, fmt.Errorf("tddriver: job %s: %w", result.Job.JobID, err)
+	}
+	dec := json.NewDecoder(body)
+	dec.UseNumber()
+	return &rows{body: body, dec: dec, columns: columns}, nil
+}
+
+// ExecContext runs a statement such as INSERT INTO or CREATE TABLE AS. TD does
+// not report affected rows, so RowsAffected returns an error.
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if _, err := c.run(ctx, query, args); err != nil {
+		return nil, err
+	}
+	return driver.ResultNoRows, nil
+}
+
+// run binds args into query, issues it and waits for the job. If ctx ends
+// first, the job is killed.
+func (c *conn) run(ctx context.Context, query string, args []driver.NamedValue) (*td.QueryResult, error) {
+	query, err := bind(c.cfg.Engine, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobID string
+	result, err := c.client.Queries.SubmitAndWait(ctx, td.SubmitRequest{
+		Type:     c.cfg.Engine,
+		Database: c.cfg.Database,
+		Options: td.IssueQueryOptions{
+			Query:         query,
+			Priority:      c.cfg.Priority,
+			PoolName:      c.cfg.PoolName,
+			EngineVersion: c.cfg.EngineVersion,
+		},
+		Wait:     td.WaitOptions{PollInterval: c.cfg.PollInterval},
+		OnSubmit: func(id string) { jobID = id },
+	})
+	if err != nil {
+		if jobID != "" && ctx.Err() != nil {
+			// The job would keep running after database/sql gave up on it
+			c.client.Jobs.Kill(context.WithoutCancel(ctx), jobID)
+		}
+		return nil, fmt.Errorf("tddriver: %w", err)
+	}
+	return result, nil
+}
+
+// bind quotes named arguments into query's :name placeholders for the
+// engine the query runs on
+func bind(engine td.QueryType, query string, args []driver.NamedValue) (string, error) {
+	if len(args) == 0 {
+		return query, nil
+	}
+	params := make(map[string]any, len(args))
+	for _, arg := range args {
+		if arg.Name == "" {
+			return "", fmt.Errorf("tddriver: argument %d has no name; pass sql.Named values for :name placeholders", arg.Ordinal)
+		}
+		params[arg.Name] = arg.Value
+	}
+	bound, err := td.BindParamsFor(engine, query, params)
+	if err != nil {
+		return "", fmt.Errorf("tddriver: %w", err)
+	}
+	return bound, nil
+}
+
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+var (
+	_ driver.StmtQueryContext = (*stmt)(nil)
+	_ driver.StmtExecContext  = (*stmt)(nil)
+)
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+// NumInput is -1 because placeholders are only counted when binding
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+// rows streams a job result in JSONL format, where each row is an array in
+// column order
+type rows struct {
+	body    io.ReadCloser
+	dec     *json.Decoder
+	columns []td.TableColumn
+}
+
+var _ driver.RowsColumnTypeDatabaseTypeName = (*rows)(nil)
+
+func (r *rows) Columns() []string {
+	names := make([]string, len(r.columns))
+	for i, column := range r.columns {
+		names[i] = column.Name
+	}
+	return names
+}
+
+// ColumnTypeDatabaseTypeName returns the engine's type, such as VARCHAR or
+// BIGINT
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	return strings.ToUpper(r.columns[index].Type)
+}
+
+func (r *rows) Close() error {
+	return r.body.Close()
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	var row interface{}
+	if err := r.dec.Decode(&row); err != nil {
+		return err
+	}
+	switch row := row.(type) {
+	case []interface{}:
+		for i := range dest {
+			var value interface{}
+			if i < len(row) {
+				value = row[i]
+			}
+			if err := convert(&dest[i], value); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		for i := range dest {
+			if err := convert(&dest[i], row[r.columns[i].Name]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("tddriver: unexpected result row %v", row)
+	}
+	return nil
+}
+
+// convert stores a decoded JSON value as a driver.Value. Integers become
+// int64, other numbers float64, and arrays, maps and rows JSON text.
+func convert(dest *driver.Value, value interface{}) error {
+	switch v := value.(type) {
+	case nil, string, bool:
+		*dest = v
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			*dest = n
+		} else if f, err := v.Float64(); err == nil {
+			*dest = f
+		} else {
+			return fmt.Errorf("tddriver: invalid number %s", v)
+		}
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		*dest = string(data)
+	}
+	return nil
+}
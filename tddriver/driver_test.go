@@ -0,0 +1,256 @@
+package tddriver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// testServer serves job 1 in state status, with two result rows
+func testServer(t *testing.T, status string, issued func(query string)) (*httptest.Server, *http.ServeMux) {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/job/issue/trino/sales", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if issued != nil {
+			query, _ := body["query"].(string)
+			issued(query)
+		}
+		fmt.Fprint(w, `{"job_id": "1", "database": "sales"}`)
+	})
+	mux.HandleFunc("/v3/job/status/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"job_id": "1", "status": %q}`, status)
+	})
+	mux.HandleFunc("/v3/job/show/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"job_id": "1", "status": %q, "hive_result_schema": "[[\"method\", \"varchar\"], [\"hits\", \"bigint\"], [\"rate\", \"double\"], [\"tags\", \"array(varchar)\"]]"}`, status)
+	})
+	mux.HandleFunc("/v3/job/result/1", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("format"); got != "jsonl" {
+			t.Errorf("format = %q, want jsonl", got)
+		}
+		fmt.Fprint(w, `["GET", 120, 0.5, ["a", "b"]]`+"\n"+`["POST", 3, null, []]`+"\n")
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, mux
+}
+
+func openTestDB(t *testing.T, server *httptest.Server) *sql.DB {
+	t.Helper()
+	client, err := td.NewClient("1/test", td.WithEndpoint(server.URL+"/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := sql.OpenDB(NewConnector(client, Config{Database: "sales", PollInterval: time.Millisecond}))
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestQuery(t *testing.T) {
+	var query string
+	server, _ := testServer(t, "success", func(q string) { query = q })
+	db := openTestDB(t, server)
+
+	rows, err := db.QueryContext(context.Background(),
+		"SELECT method, COUNT(1) AS hits FROM www_access WHERE method IN (:methods) AND path = :path GROUP BY 1",
+		sql.Named("methods", []string{"GET", "POST"}), sql.Named("path", "it's"))
+	if err != nil {
+		t.Fatalf("QueryContext returned error: %v", err)
+	}
+	defer rows.Close()
+	if want := "SELECT method, COUNT(1) AS hits FROM www_access WHERE method IN ('GET', 'POST') AND path = 'it''s' GROUP BY 1"; query != want {
+		t.Errorf("query = %q, want %q", query, want)
+	}
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if types[0].Name() != "method" || types[1].DatabaseTypeName() != "BIGINT" {
+		t.Errorf("column types = %s %s", types[0].Name(), types[1].DatabaseTypeName())
+	}
+
+	type row struct {
+		method string
+		hits   int64
+		rate   sql.NullFloat64
+		tags   string
+	}
+	var got []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.method, &r.hits, &r.rate, &r.tags); err != nil {
+			t.Fatalf("Scan returned error: %v", err)
+		}
+		got = append(got, r)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := []row{
+		{"GET", 120, sql.NullFloat64{Float64: 0.5, Valid: true}, `["a","b"]`},
+		{"POST", 3, sql.NullFloat64{}, `[]`},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("rows = %v, want %v", got, want)
+	}
+}
+
+func TestQuery_PositionalArgs(t *testing.T) {
+	server, _ := testServer(t, "success", nil)
+	db := openTestDB(t, server)
+
+	_, err := db.Query("SELECT * FROM www_access WHERE method = ?", "GET")
+	if err == nil || !strings.Contains(err.Error(), "sql.Named") {
+		t.Errorf("err = %v, want a hint to use sql.Named", err)
+	}
+}
+
+func TestQuery_HiveEngine(t *testing.T) {
+	var query string
+	server, mux := testServer(t, "success", nil)
+	mux.HandleFunc("/v3/job/issue/hive/sales", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		query, _ = body["query"].(string)
+		fmt.Fprint(w, `{"job_id": "1", "database": "sales"}`)
+	})
+	client, err := td.NewClient("1/test", td.WithEndpoint(server.URL+"/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := sql.OpenDB(NewConnector(client, Config{Database: "sales", Engine: td.QueryTypeHive, PollInterval: time.Millisecond}))
+	defer db.Close()
+
+	rows, err := db.Query("SELECT * FROM www_access WHERE path = :path", sql.Named("path", `it's\`))
+	if err != nil {
+		t.Fatalf("Query returned error: %v", err)
+	}
+	rows.Close()
+	if want := `SELECT * FROM www_access WHERE path = 'it\'s\\'`; query != want {
+		t.Errorf("query = %q, want %q", query, want)
+	}
+}
+
+func TestQuery_JobFailed(t *testing.T) {
+	server, _ := testServer(t, "error", nil)
+	db := openTestDB(t, server)
+
+	_, err := db.Query("SELECT 1")
+	var failed *td.JobFailedError
+	if !errors.As(err, &failed) || failed.JobID != "1" {
+		t.Errorf("err = %v, want a *td.JobFailedError", err)
+	}
+}
+
+func TestQuery_CancelKillsJob(t *testing.T) {
+	server, mux := testServer(t, "running", nil)
+	var killed atomic.Bool
+	mux.HandleFunc("/v3/job/kill/1", func(w http.ResponseWriter, r *http.Request) {
+		killed.Store(true)
+		fmt.Fprint(w, `{"job_id": "1", "former_status": "running"}`)
+	})
+	db := openTestDB(t, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := db.QueryContext(ctx, "SELECT 1"); err == nil {
+		t.Fatal("expected an error once the context ends")
+	}
+	if !killed.Load() {
+		t.Error("the running job was not killed")
+	}
+}
+
+func TestExec(t *testing.T) {
+	var query string
+	server, _ := testServer(t, "success", func(q string) { query = q })
+	db := openTestDB(t, server)
+
+	result, err := db.Exec("INSERT INTO archive SELECT * FROM www_access WHERE method = :method", sql.Named("method", "GET"))
+	if err != nil {
+		t.Fatalf("Exec returned error: %v", err)
+	}
+	if query != "INSERT INTO archive SELECT * FROM www_access WHERE method = 'GET'" {
+		t.Errorf("query = %q", query)
+	}
+	if _, err := result.RowsAffected(); err == nil {
+		t.Error("RowsAffected should report that TD has no row count")
+	}
+}
+
+func TestOpen_DSN(t *testing.T) {
+	server, mux := testServer(t, "success", nil)
+	mux.HandleFunc("/v3/database/show/sales", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "TD1 1/test" {
+			t.Errorf("Authorization = %q", got)
+		}
+		fmt.Fprint(w, `{"name": "sales"}`)
+	})
+
+	db, err := sql.Open("td", "td://1%2Ftest@/sales?poll_interval=1ms&endpoint="+url.QueryEscape(server.URL+"/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		t.Fatalf("Ping returned error: %v", err)
+	}
+	var hits int64
+	var method string
+	var rate sql.NullFloat64
+	var tags string
+	if err := db.QueryRow("SELECT 1").Scan(&method, &hits, &rate, &tags); err != nil || method != "GET" || hits != 120 {
+		t.Errorf("QueryRow = %q %d, %v", method, hits, err)
+	}
+}
+
+func TestParseDSN(t *testing.T) {
+	t.Setenv("TD_API_KEY", "1/from-env")
+
+	cfg, err := ParseDSN("td://1%2Fabc@tokyo/sales?engine=hive&priority=-1&pool_name=batch&engine_version=experimental&poll_interval=500ms")
+	if err != nil {
+		t.Fatalf("ParseDSN returned error: %v", err)
+	}
+	want := Config{
+		APIKey: "1/abc", Region: "tokyo", Database: "sales", Engine: td.QueryTypeHive,
+		Priority: -1, PoolName: "batch", EngineVersion: "experimental", PollInterval: 500 * time.Millisecond,
+	}
+	if *cfg != want {
+		t.Errorf("ParseDSN = %+v, want %+v", *cfg, want)
+	}
+
+	cfg, err = ParseDSN("td:///sales")
+	if err != nil {
+		t.Fatalf("ParseDSN returned error: %v", err)
+	}
+	if cfg.APIKey != "1/from-env" || cfg.Region != "" || cfg.Engine != td.QueryTypeTrino {
+		t.Errorf("ParseDSN defaults = %+v", *cfg)
+	}
+
+	for _, dsn := range []string{
+		"postgres://us/sales",
+		"td://us/",
+		"td://us/sales/events",
+		"td://us/sales?engine=presto",
+		"td://us/sales?priority=5",
+		"td://us/sales?poll_interval=soon",
+		"td://us/sales?unknown=1",
+	} {
+		if _, err := ParseDSN(dsn); err == nil {
+			t.Errorf("ParseDSN(%q) should fail", dsn)
+		}
+	}
+}
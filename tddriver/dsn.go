@@ -0,0 +1,106 @@
+package tddriver
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+// Config describes the connection a DSN opens
+type Config struct {
+	// APIKey authenticates the client a DSN creates. Defaults to the
+	// TD_API_KEY environment variable.
+	APIKey string
+	// Region picks the regional endpoint (us, eu, tokyo, ap02); Endpoint
+	// overrides it with an API URL
+	Region   string
+	Endpoint string
+
+	// Database is the database queries run against
+	Database string
+	// Engine defaults to td.QueryTypeTrino
+	Engine        td.QueryType
+	Priority      int
+	PoolName      string
+	EngineVersion string
+	// PollInterval is the first delay between job status checks. Defaults to
+	// that of td.WaitOptions.
+	PollInterval time.Duration
+}
+
+// ParseDSN reads a DSN of the form
+//
+//	td://[API_KEY@][REGION]/DATABASE[?engine=hive&priority=1&pool_name=NAME&engine_version=VERSION&poll_interval=1s&endpoint=URL]
+//
+// The slash of the API key must be escaped as %2F.
+func ParseDSN(dsn string) (*Config, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("tddriver: invalid DSN: %w", err)
+	}
+	if u.Scheme != "td" {
+		return nil, fmt.Errorf("tddriver: DSN scheme must be td, not %q", u.Scheme)
+	}
+
+	cfg := &Config{
+		Region:   u.Host,
+		Database: strings.TrimPrefix(u.Path, "/"),
+		Engine:   td.QueryTypeTrino,
+	}
+	if u.User != nil {
+		cfg.APIKey = u.User.Username()
+	}
+	if cfg.APIKey == "" {
+		cfg.APIKey = os.Getenv("TD_API_KEY")
+	}
+	if cfg.Database == "" || strings.Contains(cfg.Database, "/") {
+		return nil, fmt.Errorf("tddriver: DSN must name one database, as in td://us/sample_datasets")
+	}
+
+	for key, values := range u.Query() {
+		value := values[len(values)-1]
+		switch key {
+		case "engine":
+			switch td.QueryType(value) {
+			case td.QueryTypeTrino, td.QueryTypeHive:
+				cfg.Engine = td.QueryType(value)
+			default:
+				return nil, fmt.Errorf("tddriver: engine must be trino or hive, not %q", value)
+			}
+		case "priority":
+			if cfg.Priority, err = strconv.Atoi(value); err != nil || cfg.Priority < -2 || cfg.Priority > 2 {
+				return nil, fmt.Errorf("tddriver: priority must be between -2 and 2, not %q", value)
+			}
+		case "pool_name":
+			cfg.PoolName = value
+		case "engine_version":
+			cfg.EngineVersion = value
+		case "poll_interval":
+			if cfg.PollInterval, err = time.ParseDuration(value); err != nil {
+				return nil, fmt.Errorf("tddriver: invalid poll_interval %q", value)
+			}
+		case "endpoint":
+			cfg.Endpoint = value
+		default:
+			return nil, fmt.Errorf("tddriver: unknown DSN parameter %q", key)
+		}
+	}
+	return cfg, nil
+}
+
+// clientOptions returns the options that point a client at the configured
+// region or endpoint
+func (c *Config) clientOptions() []td.ClientOption {
+	switch {
+	case c.Endpoint != "":
+		return []td.ClientOption{td.WithEndpoint(c.Endpoint)}
+	case c.Region != "":
+		return []td.ClientOption{td.WithRegion(c.Region)}
+	}
+	return nil
+}
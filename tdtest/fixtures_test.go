@@ -0,0 +1,36 @@
+package tdtest
+
+import (
+	"context"
+	"testing"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+func TestFixtures(t *testing.T) {
+	fixtures := NewFixtures().
+		Handle("GET", "/v3/job/status/1", `{"job_id": "1", "status": "success"}`).
+		HandleStatus("POST", "/v3/database/create/sales", 409, `{"error": "database already exists"}`)
+	client, err := td.NewClient("1/secret-key", fixtures.ClientOption())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		status, err := client.Jobs.Status(ctx, "1")
+		if err != nil {
+			t.Fatalf("Jobs.Status returned error: %v", err)
+		}
+		if status.Status != "success" {
+			t.Errorf("status = %q, want success", status.Status)
+		}
+	}
+
+	if _, err := client.Databases.Create(ctx, "sales"); !td.IsConflict(err) {
+		t.Errorf("Databases.Create error = %v, want conflict", err)
+	}
+	if _, err := client.Databases.Get(ctx, "other"); !td.IsNotFound(err) {
+		t.Errorf("Databases.Get error = %v, want not found", err)
+	}
+}
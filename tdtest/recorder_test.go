@@ -0,0 +1,184 @@
+package tdtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	td "github.com/mickeey2525/treasuredata-go-sdk"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	statusCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/database/list":
+			w.Header().Set("Set-Cookie", "session=secret")
+			fmt.Fprint(w, `{"databases": [{"name": "sales"}]}`)
+		case "/v3/job/status/1":
+			statusCalls++
+			status := "running"
+			if statusCalls > 1 {
+				status = "success"
+			}
+			fmt.Fprintf(w, `{"job_id": "1", "status": %q}`, status)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "databases.json")
+	ctx := context.Background()
+
+	// Record against the live server
+	rec, err := NewWithOptions(path, Options{Mode: ModeRecord, RedactHeaders: []string{"User-Agent"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := td.NewClient("1/secret-key", td.WithEndpoint(server.URL), rec.ClientOption())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Databases.List(ctx); err != nil {
+		t.Fatalf("Databases.List returned error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := client.Jobs.Status(ctx, "1"); err != nil {
+			t.Fatalf("Jobs.Status returned error: %v", err)
+		}
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("Stop returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"secret-key", "session=secret", "treasuredata-go-sdk/"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, data)
+		}
+	}
+
+	// Replay without the server, against another endpoint
+	server.Close()
+	rec, err = New(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err = td.NewClient("1/other", td.WithEndpoint("https://api.invalid"), rec.ClientOption())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dbs, err := client.Databases.List(ctx)
+	if err != nil || len(dbs) != 1 || dbs[0].Name != "sales" {
+		t.Fatalf("Databases.List = %+v, %v", dbs, err)
+	}
+	for _, want := range []string{"running", "success"} {
+		status, err := client.Jobs.Status(ctx, "1")
+		if err != nil || status.Status != want {
+			t.Fatalf("Jobs.Status = %+v, %v; want %s", status, err, want)
+		}
+	}
+	if unused := rec.Unused(); len(unused) != 0 {
+		t.Errorf("Unused = %+v", unused)
+	}
+
+	// Every interaction is consumed once
+	if _, err := client.Jobs.Status(ctx, "1"); err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Errorf("Expected a missing interaction error, got %v", err)
+	}
+}
+
+func TestRecord_RedactsBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"user": {"name": "a"}, "apikey": "1/user-key"}`)
+	}))
+	defer server.Close()
+
+	for _, keep := range []bool{false, true} {
+		path := filepath.Join(t.TempDir(), "user.json")
+		rec, err := NewWithOptions(path, Options{Mode: ModeRecord, KeepBodySecrets: keep})
+		if err != nil {
+			t.Fatal(err)
+		}
+		client, err := td.NewClient("1/test", td.WithEndpoint(server.URL), rec.ClientOption())
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, _ := client.NewRequest("POST", "v3/user/add/a", map[string]string{
+			"password": "hunter2",
+			"result":   "s3://AKIA:s3cret@/bucket/path",
+		})
+		if _, err := client.Do(context.Background(), req, nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := rec.Stop(); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, secret := range []string{"hunter2", "s3cret", "1/user-key"} {
+			if got := strings.Contains(string(data), secret); got != keep {
+				t.Errorf("KeepBodySecrets=%v: cassette contains %q = %v:\n%s", keep, secret, got, data)
+			}
+		}
+	}
+}
+
+func TestNew_MissingCassette(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); err == nil || !strings.Contains(err.Error(), RecordEnv) {
+		t.Errorf("Expected error mentioning %s, got %v", RecordEnv, err)
+	}
+}
+
+func TestBody_JSON(t *testing.T) {
+	for _, body := range []Body{Body(`{"a":1}`), {0x1f, 0x8b, 0xff, 0x00}} {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded Body
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if string(decoded) != string(body) {
+			t.Errorf("round trip of %q via %s = %q", body, data, decoded)
+		}
+	}
+}
+
+func TestDefaultMatch(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://api.treasuredata.com/v3/job/list?from=0&to=9", nil)
+	tests := []struct {
+		url   string
+		match bool
+	}{
+		{"https://api.treasuredata.co.jp/v3/job/list?to=9&from=0", true},
+		{"https://api.treasuredata.com/v3/job/list?from=0", false},
+		{"https://api.treasuredata.com/v3/job/list?from=0&to=9&status=success", false},
+		{"https://api.treasuredata.com/v3/job/show?from=0&to=9", false},
+	}
+	for _, tt := range tests {
+		if got := DefaultMatch(req, RecordedRequest{Method: "GET", URL: tt.url}); got != tt.match {
+			t.Errorf("DefaultMatch(%s) = %v, want %v", tt.url, got, tt.match)
+		}
+	}
+
+	req, _ = http.NewRequest("GET", "https://example.com/export?apikey=real", nil)
+	if !DefaultMatch(req, RecordedRequest{Method: "GET", URL: redactURL(req.URL.String())}) {
+		t.Error("Redacted query parameters should match any value")
+	}
+}
@@ -0,0 +1 @@
+SELECT * FROM raw_orders WHERE TD_INTERVAL(time, '-1d', 'UTC')
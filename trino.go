@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -46,6 +47,11 @@ type TDTrinoClientConfig struct {
 	Database   string
 	Source     string
 	HTTPClient *http.Client
+	// AppName and AppVersion identify the application, as WithAppName does
+	// for the REST client. They set Source when it is empty and are added
+	// to the Trino client info.
+	AppName    string
+	AppVersion string
 }
 
 // TDTrinoError wraps errors to remove sensitive information
@@ -62,10 +68,12 @@ func (e *TDTrinoError) Unwrap() error {
 	return e.Original
 }
 
-// trinoTransport wraps an http.RoundTripper to add the X-Trino-User header
+// trinoTransport wraps an http.RoundTripper to add the X-Trino-User header,
+// and X-Trino-Client-Info when an application identity is configured
 type trinoTransport struct {
-	base   http.RoundTripper
-	apiKey string
+	base       http.RoundTripper
+	apiKey     string
+	clientInfo string
 }
 
 // RoundTrip implements http.RoundTripper
@@ -73,6 +81,9 @@ func (t *trinoTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	// Clone the request to avoid modifying the original
 	reqCopy := req.Clone(req.Context())
 	reqCopy.Header.Set("X-Trino-User", t.apiKey)
+	if t.clientInfo != "" {
+		reqCopy.Header.Set("X-Trino-Client-Info", t.clientInfo)
+	}
 
 	// Use the base transport or default
 	transport := t.base
@@ -108,16 +119,6 @@ func wrapError(err error) error {
 	}
 }
 
-// EscapeIdentifier escapes a SQL identifier
-func EscapeIdentifier(identifier string) string {
-	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
-}
-
-// EscapeStringLiteral escapes a SQL string literal
-func EscapeStringLiteral(literal string) string {
-	return `'` + strings.ReplaceAll(literal, `'`, `''`) + `'`
-}
-
 // NewTDTrinoClient creates a new Treasure Data Trino client
 func NewTDTrinoClient(config TDTrinoClientConfig) (*TDTrinoClient, error) {
 	if config.APIKey == "" {
@@ -138,6 +139,9 @@ func NewTDTrinoClient(config TDTrinoClientConfig) (*TDTrinoClient, error) {
 
 	if config.Source == "" {
 		config.Source = "treasuredata-go-sdk"
+		if config.AppName != "" {
+			config.Source = appIdentity(config.AppName, config.AppVersion)
+		}
 	}
 
 	// Determine endpoint
@@ -160,12 +164,16 @@ func NewTDTrinoClient(config TDTrinoClientConfig) (*TDTrinoClient, error) {
 	}
 
 	// Wrap the HTTP client to add the X-Trino-User header
+	transport := &trinoTransport{
+		base:   httpClient.Transport,
+		apiKey: config.APIKey,
+	}
+	if config.AppName != "" {
+		transport.clientInfo = appIdentity(config.AppName, config.AppVersion)
+	}
 	wrappedClient := &http.Client{
-		Timeout: httpClient.Timeout,
-		Transport: &trinoTransport{
-			base:   httpClient.Transport,
-			apiKey: config.APIKey,
-		},
+		Timeout:   httpClient.Timeout,
+		Transport: transport,
 	}
 
 	// Register custom client
@@ -203,10 +211,15 @@ func NewTDTrinoClientWithHTTPClient(httpClient *http.Client) (*TDTrinoClient, er
 
 // buildDSN constructs the Trino DSN
 func buildDSN(endpoint, database, source string) string {
+	host := endpoint
+	if _, _, err := net.SplitHostPort(endpoint); err != nil {
+		host = fmt.Sprintf("%s:%d", endpoint, defaultTrinoPort)
+	}
+
 	u := &url.URL{
 		Scheme: "https",
 		User:   url.User("td"), // Dummy user required by Trino protocol
-		Host:   fmt.Sprintf("%s:%d", endpoint, defaultTrinoPort),
+		Host:   host,
 		Path:   "/",
 	}
 
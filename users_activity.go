@@ -0,0 +1,128 @@
+package treasuredata
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultActivityWindow is the job history scanned when no Since is given
+const defaultActivityWindow = 30 * 24 * time.Hour
+
+// UserActivityOptions controls the activity report
+type UserActivityOptions struct {
+	// Since bounds the job history scanned; it defaults to 30 days ago
+	Since time.Time
+}
+
+// UserActivity summarizes a user's jobs within the report window. Users that
+// ran jobs but are no longer in the user list are reported with only
+// User.Name set.
+type UserActivity struct {
+	User User `json:"user"`
+	// JobCount and FailedJobCount cover jobs created since the window start
+	JobCount       int `json:"job_count"`
+	FailedJobCount int `json:"failed_job_count"`
+	// LastJobAt is zero when the user ran no job in the window
+	LastJobAt TDTime `json:"last_job_at"`
+	LastJobID string `json:"last_job_id,omitempty"`
+	// Databases lists the distinct databases the user queried
+	Databases []string `json:"databases,omitempty"`
+}
+
+// Activity reports per-user job counts and most recent job for the users of
+// the account. The user list and the job history are fetched concurrently;
+// jobs are matched to users by name. Login times are not available through
+// the REST API.
+func (s *UsersService) Activity(ctx context.Context, opts *UserActivityOptions) ([]UserActivity, error) {
+	since := time.Now().Add(-defaultActivityWindow)
+	if opts != nil && !opts.Since.IsZero() {
+		since = opts.Since
+	}
+
+	var (
+		wg               sync.WaitGroup
+		users            []User
+		jobs             []Job
+		usersErr, jobErr error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		users, usersErr = s.List(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		jobs, jobErr = s.client.recentJobs(ctx, since)
+	}()
+	wg.Wait()
+	if usersErr != nil {
+		return nil, usersErr
+	}
+	if jobErr != nil {
+		return nil, jobErr
+	}
+
+	activity := make([]UserActivity, len(users))
+	byName := make(map[string]*UserActivity, len(users))
+	for i, u := range users {
+		activity[i].User = u
+		byName[u.Name] = &activity[i]
+	}
+
+	databases := make(map[*UserActivity]map[string]bool)
+	var unknown []*UserActivity
+	for _, job := range jobs {
+		a, ok := byName[job.UserName]
+		if !ok {
+			a = &UserActivity{User: User{Name: job.UserName}}
+			byName[job.UserName] = a
+			unknown = append(unknown, a)
+		}
+		a.JobCount++
+		if job.Status == "error" {
+			a.FailedJobCount++
+		}
+		if job.CreatedAt.After(a.LastJobAt.Time) {
+			a.LastJobAt = job.CreatedAt
+			a.LastJobID = job.JobID
+		}
+		if job.Database != "" {
+			if databases[a] == nil {
+				databases[a] = make(map[string]bool)
+			}
+			if !databases[a][job.Database] {
+				databases[a][job.Database] = true
+				a.Databases = append(a.Databases, job.Database)
+			}
+		}
+	}
+	for _, a := range unknown {
+		activity = append(activity, *a)
+	}
+
+	// Least recently active first, which puts cleanup candidates on top
+	sort.SliceStable(activity, func(i, j int) bool {
+		return activity[i].LastJobAt.Before(activity[j].LastJobAt.Time)
+	})
+	for i := range activity {
+		sort.Strings(activity[i].Databases)
+	}
+	return activity, nil
+}
+
+// recentJobs returns the jobs created at or after since. The job list is
+// ordered newest first, so paging stops at the first older job.
+func (c *Client) recentJobs(ctx context.Context, since time.Time) ([]Job, error) {
+	var jobs []Job
+	it := c.Jobs.ListAll(ctx, nil)
+	for it.Next(ctx) {
+		job := it.Value()
+		if job.CreatedAt.Before(since) {
+			break
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, it.Err()
+}
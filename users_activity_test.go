@@ -0,0 +1,56 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestUsersService_Activity(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	now := time.Now().Unix()
+	day := int64(24 * 60 * 60)
+
+	mux.HandleFunc("/v3/user/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"users": [
+			{"id": 1, "name": "Alice", "email": "alice@example.com"},
+			{"id": 2, "name": "Bob", "email": "bob@example.com"}
+		]}`)
+	})
+	mux.HandleFunc("/v3/job/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"jobs": [
+			{"job_id": "3", "user_name": "Alice", "database": "web", "status": "success", "created_at": %d},
+			{"job_id": "2", "user_name": "Carol", "database": "web", "status": "error", "created_at": %d},
+			{"job_id": "1", "user_name": "Alice", "database": "app", "status": "error", "created_at": %d},
+			{"job_id": "0", "user_name": "Bob", "database": "app", "status": "success", "created_at": %d}
+		]}`, now-day, now-2*day, now-3*day, now-40*day)
+	})
+
+	activity, err := client.Users.Activity(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Users.Activity returned error: %v", err)
+	}
+	if len(activity) != 3 {
+		t.Fatalf("got %d entries, want 3: %+v", len(activity), activity)
+	}
+
+	// Bob has no job in the last 30 days, so he sorts first
+	bob, carol, alice := activity[0], activity[1], activity[2]
+	if bob.User.Email != "bob@example.com" || bob.JobCount != 0 || !bob.LastJobAt.IsZero() {
+		t.Errorf("bob = %+v", bob)
+	}
+	if carol.User.Name != "Carol" || carol.User.ID != 0 || carol.JobCount != 1 || carol.FailedJobCount != 1 {
+		t.Errorf("carol = %+v", carol)
+	}
+	if alice.JobCount != 2 || alice.FailedJobCount != 1 || alice.LastJobID != "3" {
+		t.Errorf("alice = %+v", alice)
+	}
+	if !reflect.DeepEqual(alice.Databases, []string{"app", "web"}) {
+		t.Errorf("alice databases = %v", alice.Databases)
+	}
+}
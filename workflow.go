@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 // WorkflowService handles communication with the Workflow related methods of the Treasure Data API.
@@ -30,6 +31,35 @@ type Workflow struct {
 type WorkflowListOptions struct {
 	Limit  int `url:"limit,omitempty"`
 	Offset int `url:"offset,omitempty"`
+	// Search matches workflow and project names on the server
+	Search string `url:"search,omitempty"`
+
+	// The filters below are applied by the client to each page, so a
+	// filtered page may hold fewer than Limit workflows
+
+	// Project keeps workflows of the project with this name
+	Project string `url:"-"`
+	// Status keeps workflows with this status, ignoring case
+	Status string `url:"-"`
+	// ScheduledOnly keeps workflows that have a next scheduled run
+	ScheduledOnly bool `url:"-"`
+}
+
+// matches reports whether w passes the client-side filters of opts
+func (opts *WorkflowListOptions) matches(w Workflow) bool {
+	if opts == nil {
+		return true
+	}
+	if opts.Project != "" && w.Project.Name != opts.Project {
+		return false
+	}
+	if opts.Status != "" && !strings.EqualFold(w.Status, opts.Status) {
+		return false
+	}
+	if opts.ScheduledOnly && (w.NextSchedule == nil || w.NextSchedule.IsZero()) {
+		return false
+	}
+	return true
 }
 
 // WorkflowListResponse represents the response from the workflow list API
@@ -39,6 +69,53 @@ type WorkflowListResponse struct {
 
 // ListWorkflows returns a list of workflows
 func (s *WorkflowService) ListWorkflows(ctx context.Context, opts *WorkflowListOptions) (*WorkflowListResponse, error) {
+	resp, err := s.listWorkflowsPage(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]Workflow, 0, len(resp.Workflows))
+	for _, w := range resp.Workflows {
+		if opts.matches(w) {
+			filtered = append(filtered, w)
+		}
+	}
+	resp.Workflows = filtered
+	return resp, nil
+}
+
+// ListAllWorkflows returns an iterator over all workflows matching opts,
+// following limit/offset pagination past the server's page size. opts.Limit
+// sets the page size.
+func (s *WorkflowService) ListAllWorkflows(ctx context.Context, opts *WorkflowListOptions) *Iterator[Workflow] {
+	page := WorkflowListOptions{}
+	if opts != nil {
+		page = *opts
+	}
+	if page.Limit <= 0 {
+		page.Limit = listAllPageSize
+	}
+
+	return newIterator(ctx, func(ctx context.Context) ([]Workflow, bool, error) {
+		resp, err := s.listWorkflowsPage(ctx, &page)
+		if err != nil {
+			return nil, false, err
+		}
+		// Paginate on what the server returned, not on what passed the filters
+		more := len(resp.Workflows) >= page.Limit
+		page.Offset += len(resp.Workflows)
+
+		var workflows []Workflow
+		for _, w := range resp.Workflows {
+			if page.matches(w) {
+				workflows = append(workflows, w)
+			}
+		}
+		return workflows, more, nil
+	})
+}
+
+func (s *WorkflowService) listWorkflowsPage(ctx context.Context, opts *WorkflowListOptions) (*WorkflowListResponse, error) {
 	u := "api/workflows"
 	u, err := addOptions(u, opts)
 	if err != nil {
@@ -85,8 +162,8 @@ func (s *WorkflowService) GetWorkflow(ctx context.Context, workflowID string) (*
 // CreateWorkflow creates a new workflow
 func (s *WorkflowService) CreateWorkflow(ctx context.Context, name, project, config string) (*Workflow, error) {
 	// Validate input
-	if name == "" {
-		return nil, NewValidationError("name", name, "cannot be empty")
+	if err := ValidateWorkflowName(name); err != nil {
+		return nil, err
 	}
 	if project == "" {
 		return nil, NewValidationError("project", project, "cannot be empty")
@@ -0,0 +1,187 @@
+package treasuredata
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WorkflowProjectMetadataFile is the file at the root of a workflow project
+// that describes who owns it. It is uploaded with the project archive.
+const WorkflowProjectMetadataFile = "td-project.yml"
+
+// workflowProjectReadme is used for the description when the metadata file
+// does not give one
+const workflowProjectReadme = "README.md"
+
+// maxWorkflowProjectMetadataSize bounds the metadata and README read from an
+// archive
+const maxWorkflowProjectMetadataSize = 1 << 20
+
+// WorkflowProjectMetadata describes the ownership of a workflow project
+type WorkflowProjectMetadata struct {
+	Owner       string `json:"owner,omitempty" yaml:"owner"`
+	Description string `json:"description,omitempty" yaml:"description"`
+	RunbookURL  string `json:"runbook_url,omitempty" yaml:"runbook_url"`
+}
+
+// Validate checks that the runbook URL, if any, is an http(s) URL
+func (m *WorkflowProjectMetadata) Validate() error {
+	if m.RunbookURL == "" {
+		return nil
+	}
+	u, err := url.Parse(m.RunbookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return NewValidationError("runbook_url", m.RunbookURL, "must be an http or https URL")
+	}
+	return nil
+}
+
+// ParseWorkflowProjectMetadata parses the contents of a metadata file.
+// Unknown keys are rejected so that typos do not go unnoticed.
+func ParseWorkflowProjectMetadata(data []byte) (*WorkflowProjectMetadata, error) {
+	var metadata WorkflowProjectMetadata
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&metadata); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("%s: %w", WorkflowProjectMetadataFile, err)
+	}
+	if err := metadata.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", WorkflowProjectMetadataFile, err)
+	}
+	return &metadata, nil
+}
+
+// ReadWorkflowProjectMetadata reads the metadata of the project in dir. If
+// the metadata has no description, the first paragraph of README.md is used.
+// It returns an error wrapping ErrNotFound if dir has neither file.
+func ReadWorkflowProjectMetadata(dir string) (*WorkflowProjectMetadata, error) {
+	data, err := os.ReadFile(filepath.Join(dir, WorkflowProjectMetadataFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	readme, readmeErr := os.ReadFile(filepath.Join(dir, workflowProjectReadme))
+	if readmeErr != nil && !errors.Is(readmeErr, os.ErrNotExist) {
+		return nil, readmeErr
+	}
+	return workflowProjectMetadata(data, readme)
+}
+
+// GetProjectMetadata returns the metadata published in a project's archive,
+// read as ReadWorkflowProjectMetadata does. An empty revision means the
+// latest one.
+func (s *WorkflowService) GetProjectMetadata(ctx context.Context, projectID, revision string) (*WorkflowProjectMetadata, error) {
+	archive, err := s.DownloadProjectWithRevision(ctx, projectID, revision)
+	if err != nil {
+		return nil, err
+	}
+	files, err := readTarGzFiles(archive, WorkflowProjectMetadataFile, workflowProjectReadme)
+	if err != nil {
+		return nil, fmt.Errorf("project %s: %w", projectID, err)
+	}
+	metadata, err := workflowProjectMetadata(files[WorkflowProjectMetadataFile], files[workflowProjectReadme])
+	if err != nil {
+		return nil, fmt.Errorf("project %s: %w", projectID, err)
+	}
+	return metadata, nil
+}
+
+func workflowProjectMetadata(data, readme []byte) (*WorkflowProjectMetadata, error) {
+	if data == nil && readme == nil {
+		return nil, fmt.Errorf("%s or %s: %w", WorkflowProjectMetadataFile, workflowProjectReadme, ErrNotFound)
+	}
+	metadata := &WorkflowProjectMetadata{}
+	if data != nil {
+		var err error
+		if metadata, err = ParseWorkflowProjectMetadata(data); err != nil {
+			return nil, err
+		}
+	}
+	if metadata.Description == "" {
+		metadata.Description = readmeSummary(readme)
+	}
+	return metadata, nil
+}
+
+// readmeSummary returns the first paragraph of a Markdown README that is not
+// a heading, badge or code block, joined into one line
+func readmeSummary(readme []byte) string {
+	var paragraph []string
+	inCode := false
+	for _, line := range strings.Split(string(readme), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+			continue
+		}
+		switch {
+		case inCode:
+		case line == "":
+			if len(paragraph) > 0 {
+				return strings.Join(paragraph, " ")
+			}
+		case strings.HasPrefix(line, "#"), strings.HasPrefix(line, "!["), strings.HasPrefix(line, "[!["):
+			if len(paragraph) > 0 {
+				return strings.Join(paragraph, " ")
+			}
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	return strings.Join(paragraph, " ")
+}
+
+// readTarGzFiles returns the contents of the named files at the root of a
+// tar.gz archive; files that are missing have no entry
+func readTarGzFiles(archiveData []byte, names ...string) (map[string][]byte, error) {
+	wanted := map[string]bool{}
+	for _, name := range names {
+		wanted[name] = true
+	}
+	return readTarGzMatching(archiveData, func(name string) bool { return wanted[name] })
+}
+
+// readTarGzMatching returns the contents of the regular files of a tar.gz
+// archive whose cleaned paths match
+func readTarGzMatching(archiveData []byte, match func(name string) bool) (map[string][]byte, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(archiveData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+	defer gzipReader.Close()
+
+	files := map[string][]byte{}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar header: %w", err)
+		}
+		name := path.Clean(header.Name)
+		if header.Typeflag != tar.TypeReg || !match(name) {
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(tarReader, maxWorkflowProjectMetadataSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if len(data) > maxWorkflowProjectMetadataSize {
+			return nil, fmt.Errorf("%s is larger than %d bytes", name, maxWorkflowProjectMetadataSize)
+		}
+		files[name] = data
+	}
+}
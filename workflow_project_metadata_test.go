@@ -0,0 +1,75 @@
+package treasuredata
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorkflowService_GetProjectMetadata(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "workflow.dig"), []byte("+task:\n  echo>: hi\n"), 0644)
+	os.WriteFile(filepath.Join(dir, WorkflowProjectMetadataFile), []byte("owner: data-eng@example.com\nrunbook_url: https://wiki.example.com/etl\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Daily ETL\n\n![build](badge.svg)\n\nLoads orders\ninto the warehouse.\n\nMore details.\n"), 0644)
+	archive, err := createTarGz(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux.HandleFunc("/api/projects/1/archive", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		w.Write(archive)
+	})
+	empty, err := createTarGz(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.HandleFunc("/api/projects/2/archive", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(empty)
+	})
+
+	metadata, err := client.Workflow.GetProjectMetadata(context.Background(), "1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := WorkflowProjectMetadata{
+		Owner:       "data-eng@example.com",
+		Description: "Loads orders into the warehouse.",
+		RunbookURL:  "https://wiki.example.com/etl",
+	}
+	if *metadata != want {
+		t.Errorf("metadata = %+v, want %+v", *metadata, want)
+	}
+
+	if _, err := client.Workflow.GetProjectMetadata(context.Background(), "2", ""); !IsNotFound(err) {
+		t.Errorf("project without metadata: err = %v, want not found", err)
+	}
+}
+
+func TestParseWorkflowProjectMetadata(t *testing.T) {
+	for _, data := range []string{"ownr: me\n", "runbook_url: wiki/etl\n", "owner: [a\n"} {
+		if _, err := ParseWorkflowProjectMetadata([]byte(data)); err == nil {
+			t.Errorf("ParseWorkflowProjectMetadata(%q) should fail", data)
+		}
+	}
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, WorkflowProjectMetadataFile), []byte("owner: me\nrunbok_url: x\n"), 0644)
+	_, err := ReadWorkflowProjectMetadata(dir)
+	if err == nil || !strings.Contains(err.Error(), "runbok_url") {
+		t.Errorf("ReadWorkflowProjectMetadata err = %v, want unknown field", err)
+	}
+
+	// A bad metadata file stops the upload
+	client, _, teardown := setup()
+	defer teardown()
+	if _, err := client.Workflow.CreateProjectFromDirectory(context.Background(), "etl", dir); err == nil {
+		t.Error("CreateProjectFromDirectory accepted a malformed metadata file")
+	}
+}
@@ -0,0 +1,49 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// RestoreProject brings back a deleted workflow project by pushing its last
+// revision again under its old name. Digdag frees the name of a deleted
+// project, so the restored project gets a new ID. Workflows and their
+// schedules come back with the archive; secrets and attempt history stay with
+// the deleted project. It fails if the project is not deleted or if another
+// project has taken its name since.
+func (s *WorkflowService) RestoreProject(ctx context.Context, projectID string) (*WorkflowProject, error) {
+	if projectID == "" {
+		return nil, NewValidationError("projectID", projectID, "cannot be empty")
+	}
+
+	project, err := s.GetProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project.DeletedAt == nil || project.DeletedAt.IsZero() {
+		return nil, fmt.Errorf("project %s (%s) is not deleted", project.ID, project.Name)
+	}
+
+	u := fmt.Sprintf("api/projects?name=%s", url.QueryEscape(project.Name))
+	req, err := s.client.NewWorkflowRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	var existing WorkflowProjectListResponse
+	if _, err := s.client.Do(ctx, req, &existing); err != nil {
+		return nil, err
+	}
+	for _, p := range existing.Projects {
+		if p.Name == project.Name && p.DeletedAt == nil {
+			// Pushing would add a revision to the other project instead
+			return nil, fmt.Errorf("cannot restore project %s: its name %q is used by project %s", project.ID, project.Name, p.ID)
+		}
+	}
+
+	archive, err := s.DownloadProjectWithRevision(ctx, project.ID, project.Revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download revision %s of deleted project %s: %w", project.Revision, project.ID, err)
+	}
+	return s.CreateProjectWithRevision(ctx, project.Name, project.Revision, archive)
+}
@@ -0,0 +1,81 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWorkflowService_RestoreProject(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/projects/7", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id": "7", "name": "daily_etl", "revision": "r3", "deletedAt": "2025-01-02T03:04:05Z"}`)
+	})
+	mux.HandleFunc("/api/projects/7/archive", func(w http.ResponseWriter, r *http.Request) {
+		testURL(t, r, "/api/projects/7/archive?revision=r3")
+		fmt.Fprint(w, "archive")
+	})
+	mux.HandleFunc("/api/projects", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			testURL(t, r, "/api/projects?name=daily_etl")
+			fmt.Fprint(w, `{"projects": []}`)
+		case "PUT":
+			testURL(t, r, "/api/projects?project=daily_etl&revision=r3")
+			if body, _ := io.ReadAll(r.Body); string(body) != "archive" {
+				t.Errorf("pushed archive = %q", body)
+			}
+			fmt.Fprint(w, `{"id": "12", "name": "daily_etl", "revision": "r3"}`)
+		default:
+			t.Errorf("unexpected %s", r.Method)
+		}
+	})
+
+	project, err := client.Workflow.RestoreProject(context.Background(), "7")
+	if err != nil {
+		t.Fatalf("RestoreProject returned error: %v", err)
+	}
+	if project.ID != "12" || project.Name != "daily_etl" || project.DeletedAt != nil {
+		t.Errorf("project = %+v", project)
+	}
+}
+
+func TestWorkflowService_RestoreProject_NotDeleted(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/projects/7", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "7", "name": "daily_etl", "revision": "r3", "deletedAt": null}`)
+	})
+
+	_, err := client.Workflow.RestoreProject(context.Background(), "7")
+	if err == nil || !strings.Contains(err.Error(), "is not deleted") {
+		t.Errorf("err = %v, want a not deleted error", err)
+	}
+}
+
+func TestWorkflowService_RestoreProject_NameTaken(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/projects/7", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "7", "name": "daily_etl", "revision": "r3", "deletedAt": "2025-01-02T03:04:05Z"}`)
+	})
+	mux.HandleFunc("/api/projects", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("unexpected %s: the live project must not be overwritten", r.Method)
+		}
+		fmt.Fprint(w, `{"projects": [{"id": "9", "name": "daily_etl", "revision": "r5"}]}`)
+	})
+
+	_, err := client.Workflow.RestoreProject(context.Background(), "7")
+	if err == nil || !strings.Contains(err.Error(), "used by project 9") {
+		t.Errorf("err = %v, want a name conflict", err)
+	}
+}
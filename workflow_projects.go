@@ -91,6 +91,10 @@ func (s *WorkflowService) CreateProject(ctx context.Context, name string, archiv
 
 // CreateProjectWithRevision creates a new workflow project with a specific revision
 func (s *WorkflowService) CreateProjectWithRevision(ctx context.Context, name, revision string, archive []byte) (*WorkflowProject, error) {
+	if err := ValidateWorkflowProjectName(name); err != nil {
+		return nil, err
+	}
+
 	// If revision is empty, generate it from content hash
 	if revision == "" {
 		hash := md5.Sum(archive)
@@ -121,6 +125,11 @@ func (s *WorkflowService) CreateProjectFromDirectory(ctx context.Context, name s
 		return nil, fmt.Errorf("pre-upload hooks failed: %w", err)
 	}
 
+	// Reject a malformed metadata file before uploading it
+	if _, err := ReadWorkflowProjectMetadata(dirPath); err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+
 	// Create tar.gz archive from directory
 	archive, err := createTarGz(dirPath)
 	if err != nil {
@@ -141,6 +150,11 @@ func (s *WorkflowService) CreateProjectFromDirectoryWithRevision(ctx context.Con
 		return nil, fmt.Errorf("pre-upload hooks failed: %w", err)
 	}
 
+	// Reject a malformed metadata file before uploading it
+	if _, err := ReadWorkflowProjectMetadata(dirPath); err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+
 	// Create tar.gz archive from directory
 	archive, err := createTarGz(dirPath)
 	if err != nil {
@@ -248,12 +262,12 @@ func (s *WorkflowService) DeleteProjectSecret(ctx context.Context, projectID str
 }
 
 // DownloadProject downloads a project archive as raw bytes
-func (s *WorkflowService) DownloadProject(ctx context.Context, projectID string) ([]byte, error) {
-	return s.DownloadProjectWithRevision(ctx, projectID, "")
+func (s *WorkflowService) DownloadProject(ctx context.Context, projectID string, reqOpts ...RequestOption) ([]byte, error) {
+	return s.DownloadProjectWithRevision(ctx, projectID, "", reqOpts...)
 }
 
 // DownloadProjectWithRevision downloads a specific revision of a project archive as raw bytes
-func (s *WorkflowService) DownloadProjectWithRevision(ctx context.Context, projectID, revision string) ([]byte, error) {
+func (s *WorkflowService) DownloadProjectWithRevision(ctx context.Context, projectID, revision string, reqOpts ...RequestOption) ([]byte, error) {
 	// Validate input
 	if projectID == "" {
 		return nil, NewValidationError("projectID", projectID, "cannot be empty")
@@ -273,7 +287,7 @@ func (s *WorkflowService) DownloadProjectWithRevision(ctx context.Context, proje
 	req.Header.Set("Accept", "application/gzip, application/x-gzip, application/octet-stream, */*")
 
 	var buf bytes.Buffer
-	resp, err := s.client.Do(ctx, req, &buf)
+	resp, err := s.client.Do(ctx, req, &buf, reqOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -292,14 +306,14 @@ func (s *WorkflowService) DownloadProjectWithRevision(ctx context.Context, proje
 }
 
 // DownloadProjectToDirectory downloads and extracts a project to a directory
-func (s *WorkflowService) DownloadProjectToDirectory(ctx context.Context, projectID, outputDir string) error {
-	return s.DownloadProjectToDirectoryWithRevision(ctx, projectID, "", outputDir)
+func (s *WorkflowService) DownloadProjectToDirectory(ctx context.Context, projectID, outputDir string, reqOpts ...RequestOption) error {
+	return s.DownloadProjectToDirectoryWithRevision(ctx, projectID, "", outputDir, reqOpts...)
 }
 
 // DownloadProjectToDirectoryWithRevision downloads and extracts a specific revision of a project to a directory
-func (s *WorkflowService) DownloadProjectToDirectoryWithRevision(ctx context.Context, projectID, revision, outputDir string) error {
+func (s *WorkflowService) DownloadProjectToDirectoryWithRevision(ctx context.Context, projectID, revision, outputDir string, reqOpts ...RequestOption) error {
 	// Download the project archive
-	archiveData, err := s.DownloadProjectWithRevision(ctx, projectID, revision)
+	archiveData, err := s.DownloadProjectWithRevision(ctx, projectID, revision, reqOpts...)
 	if err != nil {
 		return err
 	}
@@ -377,12 +391,12 @@ func (s *WorkflowService) FindProjectByName(ctx context.Context, projectName str
 }
 
 // DownloadProjectByNameToDirectory downloads and extracts a project by name to a directory
-func (s *WorkflowService) DownloadProjectByNameToDirectory(ctx context.Context, projectName, outputDir string) error {
-	return s.DownloadProjectByNameToDirectoryWithRevision(ctx, projectName, "", outputDir)
+func (s *WorkflowService) DownloadProjectByNameToDirectory(ctx context.Context, projectName, outputDir string, reqOpts ...RequestOption) error {
+	return s.DownloadProjectByNameToDirectoryWithRevision(ctx, projectName, "", outputDir, reqOpts...)
 }
 
 // DownloadProjectByNameToDirectoryWithRevision downloads and extracts a specific revision of a project by name to a directory
-func (s *WorkflowService) DownloadProjectByNameToDirectoryWithRevision(ctx context.Context, projectName, revision, outputDir string) error {
+func (s *WorkflowService) DownloadProjectByNameToDirectoryWithRevision(ctx context.Context, projectName, revision, outputDir string, reqOpts ...RequestOption) error {
 	// Get project by name using direct API call
 	project, err := s.GetProjectByName(ctx, projectName)
 	if err != nil {
@@ -390,5 +404,5 @@ func (s *WorkflowService) DownloadProjectByNameToDirectoryWithRevision(ctx conte
 	}
 
 	// Download using the found project ID
-	return s.DownloadProjectToDirectoryWithRevision(ctx, project.ID, revision, outputDir)
+	return s.DownloadProjectToDirectoryWithRevision(ctx, project.ID, revision, outputDir, reqOpts...)
 }
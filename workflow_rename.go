@@ -0,0 +1,87 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// workflowCallPattern matches call> and require> operators in a .dig file
+var workflowCallPattern = regexp.MustCompile(`^\s*(call|require)>:\s*["']?([^"'\s#]+)`)
+
+// WorkflowReferences returns the workflows of the same project that run a
+// workflow through call> or require>. They refer to it by name, so they break
+// when it is renamed until the project is edited and pushed again.
+func (s *WorkflowService) WorkflowReferences(ctx context.Context, workflowID string) ([]RenameReference, error) {
+	workflow, err := s.GetWorkflow(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	return s.workflowReferences(ctx, workflow)
+}
+
+// RenameWorkflow renames a workflow and reports the workflows that call it
+// by its old name. They cannot be cascaded: the project has to be edited and
+// pushed again, so opts.Cascade has no effect.
+func (s *WorkflowService) RenameWorkflow(ctx context.Context, workflowID, newName string, opts *RenameOptions) (*RenameReport, error) {
+	if err := ValidateName(NameKindWorkflow, newName); err != nil {
+		return nil, err
+	}
+	workflow, err := s.GetWorkflow(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := s.workflowReferences(ctx, workflow)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RenameReport{OldName: workflow.Name, NewName: newName, References: refs}
+	return applyRename(report, opts, func() error {
+		_, err := s.UpdateWorkflow(ctx, workflowID, map[string]string{"name": newName})
+		return err
+	})
+}
+
+func (s *WorkflowService) workflowReferences(ctx context.Context, workflow *Workflow) ([]RenameReference, error) {
+	archive, err := s.DownloadProjectWithRevision(ctx, workflow.Project.ID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to download project %s: %w", workflow.Project.ID, err)
+	}
+	files, err := readTarGzMatching(archive, func(name string) bool {
+		return strings.HasSuffix(name, ".dig")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	refs := []RenameReference{}
+	for _, name := range names {
+		caller := strings.TrimSuffix(path.Base(name), ".dig")
+		if caller == workflow.Name {
+			continue
+		}
+		for i, line := range strings.Split(string(files[name]), "\n") {
+			m := workflowCallPattern.FindStringSubmatch(line)
+			if m == nil || strings.TrimSuffix(m[2], ".dig") != workflow.Name {
+				continue
+			}
+			refs = append(refs, RenameReference{
+				Kind:   "workflow",
+				Name:   caller,
+				Breaks: true,
+				Detail: fmt.Sprintf("%s line %d: %s>", name, i+1, m[1]),
+			})
+		}
+	}
+	return refs, nil
+}
@@ -0,0 +1,49 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWorkflowService_RenameWorkflow(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "load.dig"), []byte("+extract:\n  echo>: hi\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "daily.dig"), []byte("+load:\n  call>: load.dig\n+again:\n  require>: \"load\"\n+other:\n  call>: loader\n"), 0644)
+	archive, err := createTarGz(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var renamed bool
+	mux.HandleFunc("/api/workflows/1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			renamed = true
+		}
+		fmt.Fprint(w, `{"id": "1", "name": "load", "project": {"id": "7", "name": "etl"}}`)
+	})
+	mux.HandleFunc("/api/projects/7/archive", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	})
+
+	report, err := client.Workflow.RenameWorkflow(context.Background(), "1", "load_orders", &RenameOptions{Cascade: true})
+	if err != nil {
+		t.Fatalf("Workflow.RenameWorkflow returned error: %v", err)
+	}
+	if !renamed || !report.Renamed {
+		t.Error("workflow was not renamed")
+	}
+	breaking := report.Breaking()
+	if len(breaking) != 2 {
+		t.Fatalf("Breaking() = %+v, want the call> and require> in daily.dig", breaking)
+	}
+	if breaking[0].Name != "daily" || breaking[0].Detail != "daily.dig line 2: call>" || breaking[1].Detail != "daily.dig line 4: require>" {
+		t.Errorf("Breaking() = %+v", breaking)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 )
 
 // validateCronExpression validates a cron expression
@@ -150,3 +151,62 @@ func (s *WorkflowService) UpdateWorkflowSchedule(ctx context.Context, workflowID
 
 	return &schedule, nil
 }
+
+// DeleteWorkflowSchedule removes the schedule of a workflow. The workflow
+// itself is kept and can still be started by hand.
+func (s *WorkflowService) DeleteWorkflowSchedule(ctx context.Context, workflowID string) error {
+	u := fmt.Sprintf("api/workflows/%s/schedule", workflowID)
+
+	req, err := s.client.NewWorkflowRequest("DELETE", u, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// PauseWorkflowSchedule disables the schedule of a workflow for a
+// maintenance window and enables it again at until, blocking until then.
+// Runs due during the window are skipped. The workflow server cannot resume
+// a schedule by itself, so if ctx is done first (or the process exits) the
+// schedule stays disabled; ctx's error is returned and EnableWorkflowSchedule
+// resumes it.
+func (s *WorkflowService) PauseWorkflowSchedule(ctx context.Context, workflowID string, until time.Time) (*WorkflowSchedule, error) {
+	if err := s.disableForPause(ctx, workflowID, until); err != nil {
+		return nil, err
+	}
+	return s.ResumeWorkflowScheduleAt(ctx, workflowID, until)
+}
+
+// disableForPause disables a schedule that is enabled now, refusing to pause
+// one that is already disabled, which resuming would wrongly enable
+func (s *WorkflowService) disableForPause(ctx context.Context, workflowID string, until time.Time) error {
+	if !until.After(time.Now()) {
+		return NewValidationError("until", until, "must be in the future")
+	}
+	schedule, err := s.GetWorkflowSchedule(WithoutCache(ctx), workflowID)
+	if err != nil {
+		return err
+	}
+	if schedule.DisabledAt != nil && !schedule.DisabledAt.IsZero() {
+		return fmt.Errorf("schedule of workflow %s is already disabled", workflowID)
+	}
+	_, err = s.DisableWorkflowSchedule(ctx, workflowID)
+	return err
+}
+
+// ResumeWorkflowScheduleAt waits until at and enables the schedule of a
+// workflow. If ctx is done first, the schedule is left as it is and ctx's
+// error is returned.
+func (s *WorkflowService) ResumeWorkflowScheduleAt(ctx context.Context, workflowID string, at time.Time) (*WorkflowSchedule, error) {
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+	}
+	return s.EnableWorkflowSchedule(ctx, workflowID)
+}
@@ -235,3 +235,98 @@ func TestValidateCronExpression(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkflowService_DeleteWorkflowSchedule(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/workflows/1/schedule", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if err := client.Workflow.DeleteWorkflowSchedule(context.Background(), "1"); err != nil {
+		t.Errorf("Workflows.DeleteWorkflowSchedule returned error: %v", err)
+	}
+}
+
+func TestWorkflowService_PauseWorkflowSchedule(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var calls []string
+	mux.HandleFunc("/api/workflows/1/schedule", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		calls = append(calls, "get")
+		fmt.Fprint(w, `{"id": "10", "workflow_id": "1", "cron": "0 * * * *"}`)
+	})
+	mux.HandleFunc("/api/workflows/1/schedule/disable", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		calls = append(calls, "disable")
+		fmt.Fprint(w, `{"id": "10", "workflow_id": "1", "disabled_at": 1609545600}`)
+	})
+	mux.HandleFunc("/api/workflows/1/schedule/enable", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		calls = append(calls, "enable")
+		fmt.Fprint(w, `{"id": "10", "workflow_id": "1"}`)
+	})
+
+	until := time.Now().Add(50 * time.Millisecond)
+	schedule, err := client.Workflow.PauseWorkflowSchedule(context.Background(), "1", until)
+	if err != nil {
+		t.Fatalf("Workflows.PauseWorkflowSchedule returned error: %v", err)
+	}
+	if time.Now().Before(until) {
+		t.Errorf("Workflows.PauseWorkflowSchedule returned before %v", until)
+	}
+	if schedule.DisabledAt != nil {
+		t.Errorf("Workflows.PauseWorkflowSchedule returned a disabled schedule: %+v", schedule)
+	}
+	if want := []string{"get", "disable", "enable"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("Workflows.PauseWorkflowSchedule made calls %v, want %v", calls, want)
+	}
+}
+
+func TestWorkflowService_PauseWorkflowSchedule_canceled(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	enabled := false
+	mux.HandleFunc("/api/workflows/1/schedule", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "10", "workflow_id": "1"}`)
+	})
+	mux.HandleFunc("/api/workflows/1/schedule/disable", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "10", "workflow_id": "1", "disabled_at": 1609545600}`)
+	})
+	mux.HandleFunc("/api/workflows/1/schedule/enable", func(w http.ResponseWriter, r *http.Request) {
+		enabled = true
+		fmt.Fprint(w, `{"id": "10", "workflow_id": "1"}`)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := client.Workflow.PauseWorkflowSchedule(ctx, "1", time.Now().Add(time.Hour))
+	if err != context.DeadlineExceeded {
+		t.Errorf("Workflows.PauseWorkflowSchedule returned error %v, want %v", err, context.DeadlineExceeded)
+	}
+	if enabled {
+		t.Error("Workflows.PauseWorkflowSchedule enabled the schedule after ctx was done")
+	}
+}
+
+func TestWorkflowService_PauseWorkflowSchedule_alreadyDisabled(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/workflows/1/schedule", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "10", "workflow_id": "1", "disabled_at": 1609545600}`)
+	})
+	mux.HandleFunc("/api/workflows/1/schedule/disable", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Workflows.PauseWorkflowSchedule disabled an already disabled schedule")
+	})
+
+	_, err := client.Workflow.PauseWorkflowSchedule(context.Background(), "1", time.Now().Add(time.Hour))
+	if err == nil {
+		t.Error("Workflows.PauseWorkflowSchedule returned no error for a disabled schedule")
+	}
+}
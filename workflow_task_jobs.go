@@ -0,0 +1,141 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// taskJobLogPattern matches the job IDs the td> family of operators logs when
+// it starts a job, e.g. "Started presto job id=12345:"
+var taskJobLogPattern = regexp.MustCompile(`\bjob id=(\d+)`)
+
+// taskJobsConcurrency bounds the task log and job lookups of TaskJobs
+const taskJobsConcurrency = 8
+
+// WorkflowTaskJobs lists the TD jobs launched by one workflow task
+type WorkflowTaskJobs struct {
+	Task WorkflowTask `json:"task"`
+	// Operator is the task's td operator, e.g. "td" or "td_load"
+	Operator string `json:"operator"`
+	// Jobs are in launch order. A job that no longer exists has only its
+	// JobID set.
+	Jobs []Job `json:"jobs"`
+}
+
+// TaskJobs returns the TD jobs launched by the td> operator tasks (td, td_run,
+// td_load, td_for_each, ...) of a workflow attempt, with their current status.
+// Job IDs are read from the td.last_job_id store parameter and from the
+// "job id=" lines of the task logs, so tasks that ran several jobs list all
+// of them. Tasks without jobs are omitted.
+func (s *WorkflowService) TaskJobs(ctx context.Context, workflowID string, attemptID string) ([]WorkflowTaskJobs, error) {
+	resp, err := s.ListWorkflowTasks(ctx, workflowID, attemptID)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []WorkflowTask
+	for _, task := range resp.Tasks {
+		if !task.IsGroup && tdOperator(task.Config) != "" {
+			tasks = append(tasks, task)
+		}
+	}
+
+	jobIDs, err := BatchMap(ctx, taskJobsConcurrency, tasks, func(ctx context.Context, task WorkflowTask) ([]string, error) {
+		ids := storedJobIDs(task.StoreParams)
+		if task.StartedAt == nil {
+			return ids, nil
+		}
+		log, err := s.GetWorkflowTaskLog(ctx, workflowID, attemptID, task.ID)
+		if err != nil {
+			// Logs expire before task state does; fall back to the stored ID
+			if IsNotFound(err) {
+				return ids, nil
+			}
+			return nil, fmt.Errorf("task %s log: %w", task.FullName, err)
+		}
+		logged := logJobIDs(log)
+		for _, id := range ids {
+			logged = appendUnique(logged, id)
+		}
+		return logged, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var unique []string
+	for _, ids := range jobIDs {
+		for _, id := range ids {
+			unique = appendUnique(unique, id)
+		}
+	}
+	jobs, err := BatchMap(ctx, taskJobsConcurrency, unique, func(ctx context.Context, id string) (Job, error) {
+		job, err := s.client.Jobs.Get(ctx, id)
+		if err != nil {
+			if IsNotFound(err) {
+				return Job{JobID: id}, nil
+			}
+			return Job{}, fmt.Errorf("job %s: %w", id, err)
+		}
+		return *job, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]Job, len(jobs))
+	for _, job := range jobs {
+		byID[job.JobID] = job
+	}
+
+	var result []WorkflowTaskJobs
+	for i, task := range tasks {
+		if len(jobIDs[i]) == 0 {
+			continue
+		}
+		taskJobs := WorkflowTaskJobs{Task: task, Operator: tdOperator(task.Config)}
+		for _, id := range jobIDs[i] {
+			taskJobs.Jobs = append(taskJobs.Jobs, byID[id])
+		}
+		result = append(result, taskJobs)
+	}
+	return result, nil
+}
+
+// tdOperator returns the name of the td operator in a task config, or ""
+func tdOperator(config map[string]interface{}) string {
+	for key := range config {
+		name, ok := strings.CutSuffix(key, ">")
+		if ok && (name == "td" || strings.HasPrefix(name, "td_")) {
+			return name
+		}
+	}
+	return ""
+}
+
+// storedJobIDs returns the job ID td operators store as td.last_job_id
+func storedJobIDs(params map[string]interface{}) []string {
+	td, _ := params["td"].(map[string]interface{})
+	if td == nil {
+		return nil
+	}
+	switch id := td["last_job_id"].(type) {
+	case string:
+		if id != "" {
+			return []string{id}
+		}
+	case float64:
+		return []string{fmt.Sprintf("%.0f", id)}
+	}
+	return nil
+}
+
+// logJobIDs returns the job IDs started in a task log, in order
+func logJobIDs(log string) []string {
+	var ids []string
+	for _, m := range taskJobLogPattern.FindAllStringSubmatch(log, -1) {
+		ids = appendUnique(ids, m[1])
+	}
+	return ids
+}
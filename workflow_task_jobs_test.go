@@ -0,0 +1,69 @@
+package treasuredata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestWorkflowService_TaskJobs(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/workflows/1/attempts/100/tasks", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"tasks": [
+			{"id": "1", "full_name": "+wf", "is_group": true, "config": {}},
+			{"id": "2", "full_name": "+wf+echo", "config": {"echo>": "hi"}, "started_at": "2024-01-01T09:00:00Z"},
+			{"id": "3", "full_name": "+wf+query", "config": {"td>": "q.sql"}, "started_at": "2024-01-01T09:00:00Z",
+			 "store_params": {"td": {"last_job_id": "502"}}},
+			{"id": "4", "full_name": "+wf+load", "config": {"td_load>": "l.yml"}, "started_at": "2024-01-01T09:01:00Z",
+			 "store_params": {"td": {"last_job_id": 600}}},
+			{"id": "5", "full_name": "+wf+later", "config": {"td>": "later.sql"}}
+		]}`)
+	})
+	mux.HandleFunc("/api/workflows/1/attempts/100/tasks/3/log", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "Started presto job id=501:\nselect 1\nStarted presto job id=502:\nselect 2\n")
+	})
+	mux.HandleFunc("/api/workflows/1/attempts/100/tasks/4/log", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "log not found"}`, http.StatusNotFound)
+	})
+	mux.HandleFunc("/v3/job/show/", func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Path[len("/v3/job/show/"):]
+		if id == "501" {
+			http.Error(w, `{"error": "job not found"}`, http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"job_id": "%s", "status": "success", "duration": 12}`, id)
+	})
+
+	tasks, err := client.Workflow.TaskJobs(context.Background(), "1", "100")
+	if err != nil {
+		t.Fatalf("TaskJobs returned error: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("got %d tasks, want 2: %+v", len(tasks), tasks)
+	}
+
+	query := tasks[0]
+	if query.Task.FullName != "+wf+query" || query.Operator != "td" {
+		t.Errorf("first task = %s (%s)", query.Task.FullName, query.Operator)
+	}
+	var ids []string
+	for _, job := range query.Jobs {
+		ids = append(ids, job.JobID)
+	}
+	if !reflect.DeepEqual(ids, []string{"501", "502"}) {
+		t.Errorf("job IDs = %v, want [501 502]", ids)
+	}
+	if query.Jobs[0].Status != "" || query.Jobs[1].Status != "success" || query.Jobs[1].Duration != 12 {
+		t.Errorf("jobs = %+v", query.Jobs)
+	}
+
+	load := tasks[1]
+	if load.Operator != "td_load" || len(load.Jobs) != 1 || load.Jobs[0].JobID != "600" {
+		t.Errorf("load task = %s %+v", load.Operator, load.Jobs)
+	}
+}
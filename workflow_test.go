@@ -84,6 +84,47 @@ func TestWorkflowService_ListWorkflows_withOptions(t *testing.T) {
 	}
 }
 
+func TestWorkflowService_ListAllWorkflows_filters(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	pages := map[string]string{
+		"0": `{"workflows": [
+			{"id": "1", "name": "a", "project": {"name": "etl"}, "status": "active", "next_schedule": 1609545600},
+			{"id": "2", "name": "b", "project": {"name": "etl"}, "status": "error"}
+		]}`,
+		"2": `{"workflows": [
+			{"id": "3", "name": "c", "project": {"name": "other"}, "status": "error", "next_schedule": 1609545600},
+			{"id": "4", "name": "d", "project": {"name": "etl"}, "status": "Error", "next_schedule": 1609545600}
+		]}`,
+		"4": `{"workflows": [{"id": "5", "name": "e", "project": {"name": "etl"}, "status": "error", "next_schedule": 1609545600}]}`,
+	}
+	mux.HandleFunc("/api/workflows", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("project"); got != "" {
+			t.Errorf("client-side filter sent to the server: project=%s", got)
+		}
+		offset := r.URL.Query().Get("offset")
+		if offset == "" {
+			offset = "0"
+		}
+		fmt.Fprint(w, pages[offset])
+	})
+
+	opts := &WorkflowListOptions{Limit: 2, Project: "etl", Status: "error", ScheduledOnly: true}
+	workflows, err := client.Workflow.ListAllWorkflows(context.Background(), opts).All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workflows) != 2 || workflows[0].ID != "4" || workflows[1].ID != "5" {
+		t.Errorf("ListAllWorkflows = %+v", workflows)
+	}
+
+	resp, err := client.Workflow.ListWorkflows(context.Background(), &WorkflowListOptions{Limit: 2, Status: "ERROR"})
+	if err != nil || len(resp.Workflows) != 1 || resp.Workflows[0].ID != "2" {
+		t.Errorf("ListWorkflows = %+v, %v", resp, err)
+	}
+}
+
 func TestWorkflowService_GetWorkflow(t *testing.T) {
 	client, mux, teardown := setup()
 	defer teardown()
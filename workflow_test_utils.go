@@ -58,6 +58,7 @@ func setupCDP() (client *Client, mux *http.ServeMux, teardown func()) {
 	client, _ = NewClient("test-api-key")
 	url, _ := url.Parse(server.URL + "/")
 	client.CDPURL = url
+	client.ProfilesURL = url
 
 	return client, mux, func() {
 		server.Close()